@@ -1,11 +1,16 @@
 package account
 
 import (
+	"bytes"
+
 	addr "github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
 	"github.com/filecoin-project/go-state-types/cbor"
+	"github.com/filecoin-project/go-state-types/crypto"
 	"github.com/filecoin-project/go-state-types/exitcode"
 	"github.com/ipfs/go-cid"
+	"golang.org/x/xerrors"
 
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
 	"github.com/filecoin-project/specs-actors/v2/actors/runtime"
@@ -17,6 +22,10 @@ func (a Actor) Exports() []interface{} {
 	return []interface{}{
 		1: a.Constructor,
 		2: a.PubkeyAddress,
+		3: a.AuthenticateMessage,
+		4: a.AuthorizeSessionKey,
+		5: a.RevokeSessionKey,
+		6: a.AuthenticateSessionMessage,
 	}
 }
 
@@ -32,18 +41,67 @@ var _ runtime.VMActor = Actor{}
 
 type State struct {
 	Address addr.Address
+	// Secondary keys authorized by the account's key to act on its behalf within restrictions.
+	SessionKeys []SessionKey
+	// The nonce from which a newly authorized session key starts, above every nonce any key has used, so that
+	// messages signed under an earlier authorization of a key cannot be replayed after it is authorized again.
+	SessionNonceFloor uint64
+}
+
+// A secondary key authorized to act for an account until an expiration epoch,
+// optionally restricted to particular methods and a maximum value per message.
+type SessionKey struct {
+	Key        addr.Address    // A signature-protocol address
+	Expiration abi.ChainEpoch  // The last epoch at which the key is valid
+	Methods    []abi.MethodNum // Methods the key may invoke, or empty if unrestricted
+	MaxValue   abi.TokenAmount // Maximum value the key may transfer in a single message
+	Nonce      uint64          // The nonce of the next message the key may sign
+}
+
+// Maximum number of session keys an account may authorize at once.
+const MaxSessionKeys = 16
+
+// Finds the index of the session key for an address, or -1 if there is none.
+func (st *State) FindSessionKey(key addr.Address) int {
+	for i, sk := range st.SessionKeys {
+		if sk.Key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// Checks that a session key may invoke a method with a value at an epoch.
+// Returns an error describing the violated restriction if not.
+func (st *State) SessionKeyPermits(key addr.Address, method abi.MethodNum, value abi.TokenAmount, epoch abi.ChainEpoch) error {
+	i := st.FindSessionKey(key)
+	if i < 0 {
+		return xerrors.Errorf("%v is not a session key", key)
+	}
+	sk := st.SessionKeys[i]
+	if epoch > sk.Expiration {
+		return xerrors.Errorf("session key %v expired at %d", key, sk.Expiration)
+	}
+	if value.GreaterThan(sk.MaxValue) {
+		return xerrors.Errorf("value %v exceeds session key %v maximum %v", value, key, sk.MaxValue)
+	}
+	if len(sk.Methods) == 0 {
+		return nil
+	}
+	for _, m := range sk.Methods {
+		if m == method {
+			return nil
+		}
+	}
+	return xerrors.Errorf("session key %v may not invoke method %d", key, method)
 }
 
 func (a Actor) Constructor(rt runtime.Runtime, address *addr.Address) *abi.EmptyValue {
 	// Account actors are created implicitly by sending a message to a pubkey-style address.
 	// This constructor is not invoked by the InitActor, but by the system.
 	rt.ValidateImmediateCallerIs(builtin.SystemActorAddr)
-	switch address.Protocol() {
-	case addr.SECP256K1:
-	case addr.BLS:
-		break // ok
-	default:
-		rt.Abortf(exitcode.ErrIllegalArgument, "address must use BLS or SECP protocol, got %v", address.Protocol())
+	if !builtin.IsSignableAddress(*address) {
+		rt.Abortf(exitcode.ErrIllegalArgument, "address must use a signature protocol, got %v", address.Protocol())
 	}
 	st := State{Address: *address}
 	rt.StateCreate(&st)
@@ -57,3 +115,162 @@ func (a Actor) PubkeyAddress(rt runtime.Runtime, _ *abi.EmptyValue) *addr.Addres
 	rt.StateReadonly(&st)
 	return &st.Address
 }
+
+type AuthenticateMessageParams struct {
+	Signature crypto.Signature
+	Message   []byte
+}
+
+// Verifies that a signature over a message was made by this account's key.
+// Aborts with ErrIllegalArgument if the signature is invalid, so that callers may check it with a send.
+func (a Actor) AuthenticateMessage(rt runtime.Runtime, params *AuthenticateMessageParams) *abi.EmptyValue {
+	rt.ValidateImmediateCallerAcceptAny()
+	var st State
+	rt.StateReadonly(&st)
+
+	sigType, err := builtin.AddressSignatureType(st.Address)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "no signature type for account address %v", st.Address)
+	if params.Signature.Type != sigType {
+		rt.Abortf(exitcode.ErrIllegalArgument, "signature type %d does not match account address %v", params.Signature.Type, st.Address)
+	}
+
+	err = rt.VerifySignature(params.Signature, st.Address, params.Message)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "invalid signature by %v", st.Address)
+	return nil
+}
+
+type AuthorizeSessionKeyParams struct {
+	Key        addr.Address
+	Expiration abi.ChainEpoch
+	Methods    []abi.MethodNum
+	MaxValue   abi.TokenAmount
+}
+
+// Authorizes a session key to act for this account within the given restrictions,
+// replacing any existing authorization for the same key but retaining its nonce.
+// May only be invoked by the account itself, i.e. by a message signed with the account's key.
+func (a Actor) AuthorizeSessionKey(rt runtime.Runtime, params *AuthorizeSessionKeyParams) *abi.EmptyValue {
+	rt.ValidateImmediateCallerIs(rt.Receiver())
+	if !builtin.IsSignableAddress(params.Key) {
+		rt.Abortf(exitcode.ErrIllegalArgument, "session key must use a signature protocol, got %v", params.Key.Protocol())
+	}
+	if params.Expiration < rt.CurrEpoch() {
+		rt.Abortf(exitcode.ErrIllegalArgument, "session key expiration %d is in the past", params.Expiration)
+	}
+	if params.MaxValue.LessThan(big.Zero()) {
+		rt.Abortf(exitcode.ErrIllegalArgument, "negative session key max value %v", params.MaxValue)
+	}
+
+	var st State
+	rt.StateTransaction(&st, func() {
+		if params.Key == st.Address {
+			rt.Abortf(exitcode.ErrIllegalArgument, "session key %v is the account key", params.Key)
+		}
+		sk := SessionKey{
+			Key:        params.Key,
+			Expiration: params.Expiration,
+			Methods:    params.Methods,
+			MaxValue:   params.MaxValue,
+			Nonce:      st.SessionNonceFloor,
+		}
+		if i := st.FindSessionKey(params.Key); i >= 0 {
+			sk.Nonce = st.SessionKeys[i].Nonce
+			st.SessionKeys[i] = sk
+			return
+		}
+		if len(st.SessionKeys) >= MaxSessionKeys {
+			rt.Abortf(exitcode.ErrForbidden, "account already has %d session keys", MaxSessionKeys)
+		}
+		st.SessionKeys = append(st.SessionKeys, sk)
+	})
+	return nil
+}
+
+// Revokes a session key, along with any other session keys that have expired.
+// May only be invoked by the account itself.
+func (a Actor) RevokeSessionKey(rt runtime.Runtime, key *addr.Address) *abi.EmptyValue {
+	rt.ValidateImmediateCallerIs(rt.Receiver())
+
+	var st State
+	rt.StateTransaction(&st, func() {
+		i := st.FindSessionKey(*key)
+		if i < 0 {
+			rt.Abortf(exitcode.ErrNotFound, "%v is not a session key", key)
+		}
+		var remaining []SessionKey
+		for j, sk := range st.SessionKeys {
+			if j != i && sk.Expiration >= rt.CurrEpoch() {
+				remaining = append(remaining, sk)
+			}
+		}
+		st.SessionKeys = remaining
+	})
+	return nil
+}
+
+// The message a session key signs to act for an account. Its method and value are those checked against the
+// key's restrictions, so the signature covers what the key is authorized to do. The account and nonce bind the
+// signature to one use with one account, and the recipient to the actor that may present it.
+type SessionMessage struct {
+	Account addr.Address // The ID address of the account
+	Nonce   uint64       // The session key's nonce, see SessionKey
+	To      addr.Address // The ID address of the actor that acts on the message
+	Method  abi.MethodNum
+	Value   abi.TokenAmount
+	Params  []byte
+}
+
+type AuthenticateSessionMessageParams struct {
+	Key       addr.Address
+	Signature crypto.Signature
+	Message   []byte // A CBOR-encoded SessionMessage
+}
+
+// Verifies that a signature over a session message was made by one of this account's session keys, and that the
+// key may currently invoke the message's method with its value, then advances the key's nonce so that the message
+// is accepted only once.
+// Only the message's recipient may present it, so that no other party can consume its nonce.
+// Aborts with ErrForbidden if the caller is not the recipient or the key is not authorized, or ErrIllegalArgument
+// if the message cannot be decoded, is for another account or nonce, or the signature is invalid.
+// Session keys do not sign chain messages: a message's sender is established by the node from its signature, so
+// the VM's caller validation and AuthenticateMessage accept only the account's own key. A session key's message is
+// instead presented to this method by the actor acting on it, e.g. a multisig approving a transaction on behalf of
+// a signer (see multisig.Actor.ApproveBySessionKey).
+func (a Actor) AuthenticateSessionMessage(rt runtime.Runtime, params *AuthenticateSessionMessageParams) *abi.EmptyValue {
+	rt.ValidateImmediateCallerAcceptAny()
+	var msg SessionMessage
+	err := msg.UnmarshalCBOR(bytes.NewReader(params.Message))
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed to decode session message")
+	if msg.Account != rt.Receiver() {
+		rt.Abortf(exitcode.ErrIllegalArgument, "session message for account %v, not %v", msg.Account, rt.Receiver())
+	}
+	if msg.To != rt.Caller() {
+		rt.Abortf(exitcode.ErrForbidden, "session message to %v may not be presented by %v", msg.To, rt.Caller())
+	}
+
+	var st State
+	rt.StateReadonly(&st)
+	err = st.SessionKeyPermits(params.Key, msg.Method, msg.Value, rt.CurrEpoch())
+	builtin.RequireNoErr(rt, err, exitcode.ErrForbidden, "session key not authorized")
+	if nonce := st.SessionKeys[st.FindSessionKey(params.Key)].Nonce; msg.Nonce != nonce {
+		rt.Abortf(exitcode.ErrIllegalArgument, "session message nonce %d, expected %d", msg.Nonce, nonce)
+	}
+
+	sigType, err := builtin.AddressSignatureType(params.Key)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "no signature type for session key %v", params.Key)
+	if params.Signature.Type != sigType {
+		rt.Abortf(exitcode.ErrIllegalArgument, "signature type %d does not match session key %v", params.Signature.Type, params.Key)
+	}
+
+	err = rt.VerifySignature(params.Signature, params.Key, params.Message)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "invalid signature by session key %v", params.Key)
+
+	rt.StateTransaction(&st, func() {
+		sk := &st.SessionKeys[st.FindSessionKey(params.Key)]
+		sk.Nonce++
+		if sk.Nonce > st.SessionNonceFloor {
+			st.SessionNonceFloor = sk.Nonce
+		}
+	})
+	return nil
+}
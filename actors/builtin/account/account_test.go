@@ -1,13 +1,18 @@
 package account_test
 
 import (
+	"bytes"
 	"context"
 	"testing"
 
 	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/crypto"
 	"github.com/filecoin-project/go-state-types/exitcode"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
 
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/account"
@@ -66,7 +71,7 @@ func TestAccountactor(t *testing.T) {
 				assert.Equal(t, tc.addr, st.Address)
 
 				rt.ExpectValidateCallerAny()
-				pubkeyAddress := rt.Call(actor.PubkeyAddress, nil).(*address.Address)
+				pubkeyAddress := rt.CallReadOnly(actor.PubkeyAddress, nil).(*address.Address)
 				assert.Equal(t, &tc.addr, pubkeyAddress)
 
 				checkState(t, rt)
@@ -80,6 +85,292 @@ func TestAccountactor(t *testing.T) {
 	}
 }
 
+func TestAuthenticateMessage(t *testing.T) {
+	actor := account.Actor{}
+
+	receiver := tutil.NewIDAddr(t, 100)
+	pubkey := tutil.NewSECP256K1Addr(t, "secpaddress")
+	builder := mock.NewBuilder(context.Background(), receiver).WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
+
+	sig := crypto.Signature{Type: crypto.SigTypeSecp256k1, Data: []byte("signature")}
+	message := []byte("message")
+
+	setup := func(t *testing.T) *mock.Runtime {
+		rt := builder.Build(t)
+		rt.ExpectValidateCallerAddr(builtin.SystemActorAddr)
+		rt.Call(actor.Constructor, &pubkey)
+		rt.Verify()
+		rt.SetCaller(tutil.NewIDAddr(t, 101), builtin.StorageMarketActorCodeID)
+		return rt
+	}
+
+	t.Run("valid signature", func(t *testing.T) {
+		rt := setup(t)
+		rt.ExpectValidateCallerAny()
+		rt.ExpectVerifySignature(sig, pubkey, message, nil)
+		ret := rt.Call(actor.AuthenticateMessage, &account.AuthenticateMessageParams{Signature: sig, Message: message})
+		assert.Nil(t, ret)
+		rt.Verify()
+		checkState(t, rt)
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		rt := setup(t)
+		rt.ExpectValidateCallerAny()
+		rt.ExpectVerifySignature(sig, pubkey, message, xerrors.New("bad signature"))
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(actor.AuthenticateMessage, &account.AuthenticateMessageParams{Signature: sig, Message: message})
+		})
+		rt.Verify()
+		checkState(t, rt)
+	})
+
+	t.Run("signature type does not match address", func(t *testing.T) {
+		rt := setup(t)
+		blsSig := crypto.Signature{Type: crypto.SigTypeBLS, Data: []byte("signature")}
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(actor.AuthenticateMessage, &account.AuthenticateMessageParams{Signature: blsSig, Message: message})
+		})
+		rt.Verify()
+		checkState(t, rt)
+	})
+}
+
+func TestSessionKeys(t *testing.T) {
+	actor := account.Actor{}
+
+	receiver := tutil.NewIDAddr(t, 100)
+	pubkey := tutil.NewSECP256K1Addr(t, "secpaddress")
+	sessionKey := tutil.NewBLSAddr(t, 1)
+	otherKey := tutil.NewSECP256K1Addr(t, "otheraddress")
+	builder := mock.NewBuilder(context.Background(), receiver).WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
+
+	sig := crypto.Signature{Type: crypto.SigTypeBLS, Data: []byte("signature")}
+	// The signed message for an account to the multisig at 102, which the session key's restrictions are checked
+	// against.
+	sessionMessageFor := func(to address.Address, nonce uint64, method abi.MethodNum, value abi.TokenAmount) []byte {
+		var buf bytes.Buffer
+		msg := account.SessionMessage{Account: to, Nonce: nonce, To: tutil.NewIDAddr(t, 102), Method: method, Value: value, Params: []byte{}}
+		require.NoError(t, msg.MarshalCBOR(&buf))
+		return buf.Bytes()
+	}
+	sessionMessage := func(nonce uint64, method abi.MethodNum, value abi.TokenAmount) []byte {
+		return sessionMessageFor(receiver, nonce, method, value)
+	}
+
+	setup := func(t *testing.T) *mock.Runtime {
+		rt := builder.Build(t)
+		rt.ExpectValidateCallerAddr(builtin.SystemActorAddr)
+		rt.Call(actor.Constructor, &pubkey)
+		rt.Verify()
+		rt.SetEpoch(100)
+		return rt
+	}
+
+	authorize := func(rt *mock.Runtime, params *account.AuthorizeSessionKeyParams) {
+		rt.SetCaller(receiver, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(receiver)
+		ret := rt.Call(actor.AuthorizeSessionKey, params)
+		assert.Nil(t, ret)
+		rt.Verify()
+	}
+
+	authenticateMessage := func(rt *mock.Runtime, message []byte) {
+		rt.SetCaller(tutil.NewIDAddr(t, 102), builtin.MultisigActorCodeID)
+		rt.ExpectValidateCallerAny()
+		rt.Call(actor.AuthenticateSessionMessage, &account.AuthenticateSessionMessageParams{
+			Key:       sessionKey,
+			Signature: sig,
+			Message:   message,
+		})
+		rt.Verify()
+	}
+	// Authenticates a message with a nonce, method and value, expecting its signature to be verified if verify is set.
+	authenticate := func(rt *mock.Runtime, nonce uint64, method abi.MethodNum, value abi.TokenAmount, verify bool, sigErr error) {
+		message := sessionMessage(nonce, method, value)
+		if verify {
+			rt.ExpectVerifySignature(sig, sessionKey, message, sigErr)
+		}
+		authenticateMessage(rt, message)
+	}
+
+	restricted := &account.AuthorizeSessionKeyParams{
+		Key:        sessionKey,
+		Expiration: 200,
+		Methods:    []abi.MethodNum{builtin.MethodsMultisig.Propose},
+		MaxValue:   abi.NewTokenAmount(1000),
+	}
+
+	t.Run("authorize and authenticate within bounds", func(t *testing.T) {
+		rt := setup(t)
+		authorize(rt, restricted)
+
+		var st account.State
+		rt.GetState(&st)
+		assert.Equal(t, []account.SessionKey{{
+			Key:        sessionKey,
+			Expiration: 200,
+			Methods:    []abi.MethodNum{builtin.MethodsMultisig.Propose},
+			MaxValue:   abi.NewTokenAmount(1000),
+		}}, st.SessionKeys)
+
+		authenticate(rt, 0, builtin.MethodsMultisig.Propose, abi.NewTokenAmount(1000), true, nil)
+
+		// Still valid at the expiration epoch.
+		rt.SetEpoch(200)
+		authenticate(rt, 1, builtin.MethodsMultisig.Propose, abi.NewTokenAmount(0), true, nil)
+		rt.GetState(&st)
+		assert.Equal(t, uint64(2), st.SessionKeys[0].Nonce)
+		assert.Equal(t, uint64(2), st.SessionNonceFloor)
+		checkState(t, rt)
+	})
+
+	t.Run("rejects out of bounds", func(t *testing.T) {
+		rt := setup(t)
+		authorize(rt, restricted)
+
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			authenticate(rt, 0, builtin.MethodsMultisig.Approve, abi.NewTokenAmount(0), false, nil)
+		})
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			authenticate(rt, 0, builtin.MethodsMultisig.Propose, abi.NewTokenAmount(1001), false, nil)
+		})
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			authenticateMessage(rt, []byte("not a session message"))
+		})
+		rt.SetEpoch(201)
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			authenticate(rt, 0, builtin.MethodsMultisig.Propose, abi.NewTokenAmount(0), false, nil)
+		})
+		checkState(t, rt)
+	})
+
+	t.Run("rejects replayed messages and messages for other accounts", func(t *testing.T) {
+		rt := setup(t)
+		authorize(rt, restricted)
+		authenticate(rt, 0, builtin.MethodsMultisig.Propose, abi.NewTokenAmount(0), true, nil)
+
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "nonce 0, expected 1", func() {
+			authenticate(rt, 0, builtin.MethodsMultisig.Propose, abi.NewTokenAmount(0), false, nil)
+		})
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "nonce 2, expected 1", func() {
+			authenticate(rt, 2, builtin.MethodsMultisig.Propose, abi.NewTokenAmount(0), false, nil)
+		})
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "session message for account", func() {
+			authenticateMessage(rt, sessionMessageFor(tutil.NewIDAddr(t, 103), 1, builtin.MethodsMultisig.Propose, abi.NewTokenAmount(0)))
+		})
+		checkState(t, rt)
+	})
+
+	t.Run("only the recipient may present a message", func(t *testing.T) {
+		rt := setup(t)
+		authorize(rt, restricted)
+
+		rt.SetCaller(tutil.NewIDAddr(t, 101), builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbortContainsMessage(exitcode.ErrForbidden, "may not be presented by", func() {
+			rt.Call(actor.AuthenticateSessionMessage, &account.AuthenticateSessionMessageParams{
+				Key:       sessionKey,
+				Signature: sig,
+				Message:   sessionMessage(0, builtin.MethodsMultisig.Propose, abi.NewTokenAmount(0)),
+			})
+		})
+		rt.Verify()
+
+		// The nonce was not consumed.
+		authenticate(rt, 0, builtin.MethodsMultisig.Propose, abi.NewTokenAmount(0), true, nil)
+		checkState(t, rt)
+	})
+
+	t.Run("re-authorized key does not accept messages signed under an earlier authorization", func(t *testing.T) {
+		rt := setup(t)
+		authorize(rt, restricted)
+		authenticate(rt, 0, builtin.MethodsMultisig.Propose, abi.NewTokenAmount(0), true, nil)
+
+		// Replacing an authorization retains the nonce.
+		authorize(rt, &account.AuthorizeSessionKeyParams{Key: sessionKey, Expiration: 300, MaxValue: big.Zero()})
+		authenticate(rt, 1, builtin.MethodsMultisig.Propose, abi.NewTokenAmount(0), true, nil)
+
+		// A key revoked and authorized again starts above every nonce used.
+		rt.SetCaller(receiver, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(receiver)
+		rt.Call(actor.RevokeSessionKey, &sessionKey)
+		rt.Verify()
+		authorize(rt, restricted)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "nonce 0, expected 2", func() {
+			authenticate(rt, 0, builtin.MethodsMultisig.Propose, abi.NewTokenAmount(0), false, nil)
+		})
+		authenticate(rt, 2, builtin.MethodsMultisig.Propose, abi.NewTokenAmount(0), true, nil)
+		checkState(t, rt)
+	})
+
+	t.Run("rejects invalid signature", func(t *testing.T) {
+		rt := setup(t)
+		authorize(rt, restricted)
+
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			authenticate(rt, 0, builtin.MethodsMultisig.Propose, abi.NewTokenAmount(0), true, xerrors.New("bad signature"))
+		})
+	})
+
+	t.Run("unrestricted methods", func(t *testing.T) {
+		rt := setup(t)
+		authorize(rt, &account.AuthorizeSessionKeyParams{Key: sessionKey, Expiration: 200, MaxValue: big.Zero()})
+
+		authenticate(rt, 0, builtin.MethodsMultisig.Approve, abi.NewTokenAmount(0), true, nil)
+	})
+
+	t.Run("only account may authorize", func(t *testing.T) {
+		rt := setup(t)
+		rt.SetCaller(tutil.NewIDAddr(t, 101), builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(receiver)
+		rt.ExpectAbort(exitcode.SysErrForbidden, func() {
+			rt.Call(actor.AuthorizeSessionKey, restricted)
+		})
+	})
+
+	t.Run("invalid authorizations", func(t *testing.T) {
+		rt := setup(t)
+		rt.SetCaller(receiver, builtin.AccountActorCodeID)
+		for _, params := range []*account.AuthorizeSessionKeyParams{
+			{Key: tutil.NewIDAddr(t, 102), Expiration: 200, MaxValue: big.Zero()},
+			{Key: pubkey, Expiration: 200, MaxValue: big.Zero()},
+			{Key: sessionKey, Expiration: 99, MaxValue: big.Zero()},
+			{Key: sessionKey, Expiration: 200, MaxValue: abi.NewTokenAmount(-1)},
+		} {
+			rt.ExpectValidateCallerAddr(receiver)
+			rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+				rt.Call(actor.AuthorizeSessionKey, params)
+			})
+		}
+	})
+
+	t.Run("revoke removes key and expired keys", func(t *testing.T) {
+		rt := setup(t)
+		authorize(rt, restricted)
+		authorize(rt, &account.AuthorizeSessionKeyParams{Key: otherKey, Expiration: 150, MaxValue: big.Zero()})
+
+		rt.SetEpoch(151)
+		rt.ExpectValidateCallerAddr(receiver)
+		rt.Call(actor.RevokeSessionKey, &sessionKey)
+		rt.Verify()
+
+		var st account.State
+		rt.GetState(&st)
+		assert.Empty(t, st.SessionKeys)
+
+		rt.ExpectValidateCallerAddr(receiver)
+		rt.ExpectAbort(exitcode.ErrNotFound, func() {
+			rt.Call(actor.RevokeSessionKey, &sessionKey)
+		})
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			authenticate(rt, 0, builtin.MethodsMultisig.Propose, abi.NewTokenAmount(0), false, nil)
+		})
+		checkState(t, rt)
+	})
+}
+
 func checkState(t *testing.T, rt *mock.Runtime) {
 	testAddress, err := address.NewIDAddress(1000)
 	require.NoError(t, err)
@@ -6,13 +6,15 @@ import (
 	"fmt"
 	"io"
 
+	abi "github.com/filecoin-project/go-state-types/abi"
+	acbor "github.com/filecoin-project/specs-actors/v2/actors/util/cbor"
 	cbg "github.com/whyrusleeping/cbor-gen"
 	xerrors "golang.org/x/xerrors"
 )
 
 var _ = xerrors.Errorf
 
-var lengthBufState = []byte{129}
+var lengthBufState = []byte{131}
 
 func (t *State) MarshalCBOR(w io.Writer) error {
 	if t == nil {
@@ -23,10 +25,33 @@ func (t *State) MarshalCBOR(w io.Writer) error {
 		return err
 	}
 
+	scratch := make([]byte, 9)
+
 	// t.Address (address.Address) (struct)
 	if err := t.Address.MarshalCBOR(w); err != nil {
 		return err
 	}
+
+	// t.SessionKeys ([]account.SessionKey) (slice)
+	if len(t.SessionKeys) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.SessionKeys was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.SessionKeys))); err != nil {
+		return err
+	}
+	for _, v := range t.SessionKeys {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+
+	// t.SessionNonceFloor (uint64) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.SessionNonceFloor)); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -38,14 +63,14 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "account.State", "")
 	}
 	if maj != cbg.MajArray {
-		return fmt.Errorf("cbor input should be of type array")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "account.State", "")
 	}
 
-	if extra != 1 {
-		return fmt.Errorf("cbor input had wrong number of fields")
+	if extra != 3 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "account.State", "")
 	}
 
 	// t.Address (address.Address) (struct)
@@ -53,9 +78,725 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.Address.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.Address: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Address: %w", err), "account.State", "Address")
+		}
+
+	}
+	// t.SessionKeys ([]account.SessionKey) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "account.State", "SessionKeys")
+	}
+
+	if extra > cbg.MaxLength {
+		return acbor.WrapDecodeError(fmt.Errorf("t.SessionKeys: array too large (%d)", extra), "account.State", "SessionKeys")
+	}
+
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("expected cbor array"), "account.State", "SessionKeys")
+	}
+
+	if extra > 0 {
+		t.SessionKeys = make([]SessionKey, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v SessionKey
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(err, "account.State", fmt.Sprintf("SessionKeys[%d]", i))
+		}
+
+		t.SessionKeys[i] = v
+	}
+
+	// t.SessionNonceFloor (uint64) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return acbor.WrapDecodeError(err, "account.State", "SessionNonceFloor")
 		}
+		if maj != cbg.MajUnsignedInt {
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "account.State", "SessionNonceFloor")
+		}
+		t.SessionNonceFloor = uint64(extra)
 
 	}
 	return nil
 }
+
+var lengthBufSessionKey = []byte{133}
+
+func (t *SessionKey) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufSessionKey); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Key (address.Address) (struct)
+	if err := t.Key.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Expiration (abi.ChainEpoch) (int64)
+	if t.Expiration >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Expiration)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.Expiration-1)); err != nil {
+			return err
+		}
+	}
+
+	// t.Methods ([]abi.MethodNum) (slice)
+	if len(t.Methods) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Methods was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Methods))); err != nil {
+		return err
+	}
+	for _, v := range t.Methods {
+		if err := cbg.CborWriteHeader(w, cbg.MajUnsignedInt, uint64(v)); err != nil {
+			return err
+		}
+	}
+
+	// t.MaxValue (big.Int) (struct)
+	if err := t.MaxValue.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Nonce (uint64) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Nonce)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (t *SessionKey) UnmarshalCBOR(r io.Reader) error {
+	*t = SessionKey{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "account.SessionKey", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "account.SessionKey", "")
+	}
+
+	if extra != 5 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "account.SessionKey", "")
+	}
+
+	// t.Key (address.Address) (struct)
+
+	{
+
+		if err := t.Key.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Key: %w", err), "account.SessionKey", "Key")
+		}
+
+	}
+	// t.Expiration (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return acbor.WrapDecodeError(err, "account.SessionKey", "Expiration")
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "account.SessionKey", "Expiration")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "account.SessionKey", "Expiration")
+			}
+			extraI = -1 - extraI
+		default:
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "account.SessionKey", "Expiration")
+		}
+
+		t.Expiration = abi.ChainEpoch(extraI)
+	}
+	// t.Methods ([]abi.MethodNum) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "account.SessionKey", "Methods")
+	}
+
+	if extra > cbg.MaxLength {
+		return acbor.WrapDecodeError(fmt.Errorf("t.Methods: array too large (%d)", extra), "account.SessionKey", "Methods")
+	}
+
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("expected cbor array"), "account.SessionKey", "Methods")
+	}
+
+	if extra > 0 {
+		t.Methods = make([]abi.MethodNum, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		maj, val, err := cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read uint64 for t.Methods slice: %w", err), "account.SessionKey", fmt.Sprintf("Methods[%d]", i))
+		}
+
+		if maj != cbg.MajUnsignedInt {
+			return acbor.WrapDecodeError(xerrors.Errorf("value read for array t.Methods was not a uint, instead got %d", maj), "account.SessionKey", fmt.Sprintf("Methods[%d]", i))
+		}
+
+		t.Methods[i] = abi.MethodNum(val)
+	}
+
+	// t.MaxValue (big.Int) (struct)
+
+	{
+
+		if err := t.MaxValue.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.MaxValue: %w", err), "account.SessionKey", "MaxValue")
+		}
+
+	}
+	// t.Nonce (uint64) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return acbor.WrapDecodeError(err, "account.SessionKey", "Nonce")
+		}
+		if maj != cbg.MajUnsignedInt {
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "account.SessionKey", "Nonce")
+		}
+		t.Nonce = uint64(extra)
+
+	}
+	return nil
+}
+
+var lengthBufAuthenticateMessageParams = []byte{130}
+
+func (t *AuthenticateMessageParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufAuthenticateMessageParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Signature (crypto.Signature) (struct)
+	if err := t.Signature.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Message ([]uint8) (slice)
+	if len(t.Message) > cbg.ByteArrayMaxLen {
+		return xerrors.Errorf("Byte array in field t.Message was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajByteString, uint64(len(t.Message))); err != nil {
+		return err
+	}
+
+	if _, err := w.Write(t.Message[:]); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *AuthenticateMessageParams) UnmarshalCBOR(r io.Reader) error {
+	*t = AuthenticateMessageParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "account.AuthenticateMessageParams", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "account.AuthenticateMessageParams", "")
+	}
+
+	if extra != 2 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "account.AuthenticateMessageParams", "")
+	}
+
+	// t.Signature (crypto.Signature) (struct)
+
+	{
+
+		if err := t.Signature.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Signature: %w", err), "account.AuthenticateMessageParams", "Signature")
+		}
+
+	}
+	// t.Message ([]uint8) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "account.AuthenticateMessageParams", "Message")
+	}
+
+	if extra > cbg.ByteArrayMaxLen {
+		return acbor.WrapDecodeError(fmt.Errorf("t.Message: byte array too large (%d)", extra), "account.AuthenticateMessageParams", "Message")
+	}
+	if maj != cbg.MajByteString {
+		return acbor.WrapDecodeError(fmt.Errorf("expected byte array"), "account.AuthenticateMessageParams", "Message")
+	}
+
+	if extra > 0 {
+		t.Message = make([]uint8, extra)
+	}
+
+	if _, err := io.ReadFull(br, t.Message[:]); err != nil {
+		return acbor.WrapDecodeError(err, "account.AuthenticateMessageParams", "Message")
+	}
+	return nil
+}
+
+var lengthBufAuthorizeSessionKeyParams = []byte{132}
+
+func (t *AuthorizeSessionKeyParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufAuthorizeSessionKeyParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Key (address.Address) (struct)
+	if err := t.Key.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Expiration (abi.ChainEpoch) (int64)
+	if t.Expiration >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Expiration)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.Expiration-1)); err != nil {
+			return err
+		}
+	}
+
+	// t.Methods ([]abi.MethodNum) (slice)
+	if len(t.Methods) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Methods was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Methods))); err != nil {
+		return err
+	}
+	for _, v := range t.Methods {
+		if err := cbg.CborWriteHeader(w, cbg.MajUnsignedInt, uint64(v)); err != nil {
+			return err
+		}
+	}
+
+	// t.MaxValue (big.Int) (struct)
+	if err := t.MaxValue.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *AuthorizeSessionKeyParams) UnmarshalCBOR(r io.Reader) error {
+	*t = AuthorizeSessionKeyParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "account.AuthorizeSessionKeyParams", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "account.AuthorizeSessionKeyParams", "")
+	}
+
+	if extra != 4 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "account.AuthorizeSessionKeyParams", "")
+	}
+
+	// t.Key (address.Address) (struct)
+
+	{
+
+		if err := t.Key.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Key: %w", err), "account.AuthorizeSessionKeyParams", "Key")
+		}
+
+	}
+	// t.Expiration (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return acbor.WrapDecodeError(err, "account.AuthorizeSessionKeyParams", "Expiration")
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "account.AuthorizeSessionKeyParams", "Expiration")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "account.AuthorizeSessionKeyParams", "Expiration")
+			}
+			extraI = -1 - extraI
+		default:
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "account.AuthorizeSessionKeyParams", "Expiration")
+		}
+
+		t.Expiration = abi.ChainEpoch(extraI)
+	}
+	// t.Methods ([]abi.MethodNum) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "account.AuthorizeSessionKeyParams", "Methods")
+	}
+
+	if extra > cbg.MaxLength {
+		return acbor.WrapDecodeError(fmt.Errorf("t.Methods: array too large (%d)", extra), "account.AuthorizeSessionKeyParams", "Methods")
+	}
+
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("expected cbor array"), "account.AuthorizeSessionKeyParams", "Methods")
+	}
+
+	if extra > 0 {
+		t.Methods = make([]abi.MethodNum, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		maj, val, err := cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read uint64 for t.Methods slice: %w", err), "account.AuthorizeSessionKeyParams", fmt.Sprintf("Methods[%d]", i))
+		}
+
+		if maj != cbg.MajUnsignedInt {
+			return acbor.WrapDecodeError(xerrors.Errorf("value read for array t.Methods was not a uint, instead got %d", maj), "account.AuthorizeSessionKeyParams", fmt.Sprintf("Methods[%d]", i))
+		}
+
+		t.Methods[i] = abi.MethodNum(val)
+	}
+
+	// t.MaxValue (big.Int) (struct)
+
+	{
+
+		if err := t.MaxValue.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.MaxValue: %w", err), "account.AuthorizeSessionKeyParams", "MaxValue")
+		}
+
+	}
+	return nil
+}
+
+var lengthBufSessionMessage = []byte{134}
+
+func (t *SessionMessage) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufSessionMessage); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Account (address.Address) (struct)
+	if err := t.Account.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Nonce (uint64) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Nonce)); err != nil {
+		return err
+	}
+
+	// t.To (address.Address) (struct)
+	if err := t.To.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Method (abi.MethodNum) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Method)); err != nil {
+		return err
+	}
+
+	// t.Value (big.Int) (struct)
+	if err := t.Value.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Params ([]uint8) (slice)
+	if len(t.Params) > cbg.ByteArrayMaxLen {
+		return xerrors.Errorf("Byte array in field t.Params was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajByteString, uint64(len(t.Params))); err != nil {
+		return err
+	}
+
+	if _, err := w.Write(t.Params[:]); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *SessionMessage) UnmarshalCBOR(r io.Reader) error {
+	*t = SessionMessage{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "account.SessionMessage", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "account.SessionMessage", "")
+	}
+
+	if extra != 6 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "account.SessionMessage", "")
+	}
+
+	// t.Account (address.Address) (struct)
+
+	{
+
+		if err := t.Account.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Account: %w", err), "account.SessionMessage", "Account")
+		}
+
+	}
+	// t.Nonce (uint64) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return acbor.WrapDecodeError(err, "account.SessionMessage", "Nonce")
+		}
+		if maj != cbg.MajUnsignedInt {
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "account.SessionMessage", "Nonce")
+		}
+		t.Nonce = uint64(extra)
+
+	}
+	// t.To (address.Address) (struct)
+
+	{
+
+		if err := t.To.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.To: %w", err), "account.SessionMessage", "To")
+		}
+
+	}
+	// t.Method (abi.MethodNum) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return acbor.WrapDecodeError(err, "account.SessionMessage", "Method")
+		}
+		if maj != cbg.MajUnsignedInt {
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "account.SessionMessage", "Method")
+		}
+		t.Method = abi.MethodNum(extra)
+
+	}
+	// t.Value (big.Int) (struct)
+
+	{
+
+		if err := t.Value.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Value: %w", err), "account.SessionMessage", "Value")
+		}
+
+	}
+	// t.Params ([]uint8) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "account.SessionMessage", "Params")
+	}
+
+	if extra > cbg.ByteArrayMaxLen {
+		return acbor.WrapDecodeError(fmt.Errorf("t.Params: byte array too large (%d)", extra), "account.SessionMessage", "Params")
+	}
+	if maj != cbg.MajByteString {
+		return acbor.WrapDecodeError(fmt.Errorf("expected byte array"), "account.SessionMessage", "Params")
+	}
+
+	if extra > 0 {
+		t.Params = make([]uint8, extra)
+	}
+
+	if _, err := io.ReadFull(br, t.Params[:]); err != nil {
+		return acbor.WrapDecodeError(err, "account.SessionMessage", "Params")
+	}
+	return nil
+}
+
+var lengthBufAuthenticateSessionMessageParams = []byte{131}
+
+func (t *AuthenticateSessionMessageParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufAuthenticateSessionMessageParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Key (address.Address) (struct)
+	if err := t.Key.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Signature (crypto.Signature) (struct)
+	if err := t.Signature.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Message ([]uint8) (slice)
+	if len(t.Message) > cbg.ByteArrayMaxLen {
+		return xerrors.Errorf("Byte array in field t.Message was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajByteString, uint64(len(t.Message))); err != nil {
+		return err
+	}
+
+	if _, err := w.Write(t.Message[:]); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *AuthenticateSessionMessageParams) UnmarshalCBOR(r io.Reader) error {
+	*t = AuthenticateSessionMessageParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "account.AuthenticateSessionMessageParams", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "account.AuthenticateSessionMessageParams", "")
+	}
+
+	if extra != 3 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "account.AuthenticateSessionMessageParams", "")
+	}
+
+	// t.Key (address.Address) (struct)
+
+	{
+
+		if err := t.Key.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Key: %w", err), "account.AuthenticateSessionMessageParams", "Key")
+		}
+
+	}
+	// t.Signature (crypto.Signature) (struct)
+
+	{
+
+		if err := t.Signature.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Signature: %w", err), "account.AuthenticateSessionMessageParams", "Signature")
+		}
+
+	}
+	// t.Message ([]uint8) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "account.AuthenticateSessionMessageParams", "Message")
+	}
+
+	if extra > cbg.ByteArrayMaxLen {
+		return acbor.WrapDecodeError(fmt.Errorf("t.Message: byte array too large (%d)", extra), "account.AuthenticateSessionMessageParams", "Message")
+	}
+	if maj != cbg.MajByteString {
+		return acbor.WrapDecodeError(fmt.Errorf("expected byte array"), "account.AuthenticateSessionMessageParams", "Message")
+	}
+
+	if extra > 0 {
+		t.Message = make([]uint8, extra)
+	}
+
+	if _, err := io.ReadFull(br, t.Message[:]); err != nil {
+		return acbor.WrapDecodeError(err, "account.AuthenticateSessionMessageParams", "Message")
+	}
+	return nil
+}
+
+func (t *State) MaxEncodedLength() int64 {
+	return 605765711
+}
+
+func (t *SessionKey) MaxEncodedLength() int64 {
+	return 73946
+}
+
+func (t *AuthenticateMessageParams) MaxEncodedLength() int64 {
+	return 2097361
+}
+
+func (t *AuthorizeSessionKeyParams) MaxEncodedLength() int64 {
+	return 73937
+}
+
+func (t *SessionMessage) MaxEncodedLength() int64 {
+	return 2097438
+}
+
+func (t *AuthenticateSessionMessageParams) MaxEncodedLength() int64 {
+	return 2097427
+}
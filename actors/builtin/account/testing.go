@@ -2,6 +2,8 @@ package account
 
 import (
 	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/big"
+
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
 )
 
@@ -18,9 +20,18 @@ func CheckStateInvariants(st *State, idAddr address.Address) (*StateSummary, *bu
 		return nil, acc, err
 	}
 	if id >= builtin.FirstNonSingletonActorId {
-		acc.Require(
-			st.Address.Protocol() == address.BLS || st.Address.Protocol() == address.SECP256K1,
-			"actor address %v must be BLS or SECP256K1 protocol", st.Address)
+		acc.Require(builtin.IsSignableAddress(st.Address), "actor address %v must use a signature protocol", st.Address)
+	}
+
+	acc.Require(len(st.SessionKeys) <= MaxSessionKeys, "%d session keys exceeds maximum %d", len(st.SessionKeys), MaxSessionKeys)
+	keys := map[address.Address]bool{}
+	for _, sk := range st.SessionKeys {
+		acc.Require(builtin.IsSignableAddress(sk.Key), "session key %v must use a signature protocol", sk.Key)
+		acc.Require(sk.Key != st.Address, "session key %v is the account key", sk.Key)
+		acc.Require(!keys[sk.Key], "duplicate session key %v", sk.Key)
+		acc.Require(sk.MaxValue.GreaterThanEqual(big.Zero()), "session key %v has negative max value %v", sk.Key, sk.MaxValue)
+		acc.Require(sk.Nonce <= st.SessionNonceFloor, "session key %v nonce %d exceeds floor %d", sk.Key, sk.Nonce, st.SessionNonceFloor)
+		keys[sk.Key] = true
 	}
 
 	return &StateSummary{
@@ -7,6 +7,8 @@ import (
 	"io"
 
 	address "github.com/filecoin-project/go-address"
+	abi "github.com/filecoin-project/go-state-types/abi"
+	acbor "github.com/filecoin-project/specs-actors/v2/actors/util/cbor"
 	cbg "github.com/whyrusleeping/cbor-gen"
 	xerrors "golang.org/x/xerrors"
 )
@@ -60,14 +62,14 @@ func (t *MinerAddrs) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "builtin.MinerAddrs", "")
 	}
 	if maj != cbg.MajArray {
-		return fmt.Errorf("cbor input should be of type array")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "builtin.MinerAddrs", "")
 	}
 
 	if extra != 3 {
-		return fmt.Errorf("cbor input had wrong number of fields")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "builtin.MinerAddrs", "")
 	}
 
 	// t.Owner (address.Address) (struct)
@@ -75,7 +77,7 @@ func (t *MinerAddrs) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.Owner.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.Owner: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Owner: %w", err), "builtin.MinerAddrs", "Owner")
 		}
 
 	}
@@ -84,7 +86,7 @@ func (t *MinerAddrs) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.Worker.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.Worker: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Worker: %w", err), "builtin.MinerAddrs", "Worker")
 		}
 
 	}
@@ -92,15 +94,15 @@ func (t *MinerAddrs) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "builtin.MinerAddrs", "ControlAddrs")
 	}
 
 	if extra > cbg.MaxLength {
-		return fmt.Errorf("t.ControlAddrs: array too large (%d)", extra)
+		return acbor.WrapDecodeError(fmt.Errorf("t.ControlAddrs: array too large (%d)", extra), "builtin.MinerAddrs", "ControlAddrs")
 	}
 
 	if maj != cbg.MajArray {
-		return fmt.Errorf("expected cbor array")
+		return acbor.WrapDecodeError(fmt.Errorf("expected cbor array"), "builtin.MinerAddrs", "ControlAddrs")
 	}
 
 	if extra > 0 {
@@ -111,7 +113,7 @@ func (t *MinerAddrs) UnmarshalCBOR(r io.Reader) error {
 
 		var v address.Address
 		if err := v.UnmarshalCBOR(br); err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "builtin.MinerAddrs", fmt.Sprintf("ControlAddrs[%d]", i))
 		}
 
 		t.ControlAddrs[i] = v
@@ -151,14 +153,14 @@ func (t *ApplyRewardParams) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "builtin.ApplyRewardParams", "")
 	}
 	if maj != cbg.MajArray {
-		return fmt.Errorf("cbor input should be of type array")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "builtin.ApplyRewardParams", "")
 	}
 
 	if extra != 2 {
-		return fmt.Errorf("cbor input had wrong number of fields")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "builtin.ApplyRewardParams", "")
 	}
 
 	// t.Reward (big.Int) (struct)
@@ -166,7 +168,7 @@ func (t *ApplyRewardParams) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.Reward.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.Reward: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Reward: %w", err), "builtin.ApplyRewardParams", "Reward")
 		}
 
 	}
@@ -175,9 +177,629 @@ func (t *ApplyRewardParams) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.Penalty.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.Penalty: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Penalty: %w", err), "builtin.ApplyRewardParams", "Penalty")
+		}
+
+	}
+	return nil
+}
+
+var lengthBufTransferEvent = []byte{130}
+
+func (t *TransferEvent) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufTransferEvent); err != nil {
+		return err
+	}
+
+	// t.To (address.Address) (struct)
+	if err := t.To.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Amount (big.Int) (struct)
+	if err := t.Amount.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *TransferEvent) UnmarshalCBOR(r io.Reader) error {
+	*t = TransferEvent{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "builtin.TransferEvent", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "builtin.TransferEvent", "")
+	}
+
+	if extra != 2 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "builtin.TransferEvent", "")
+	}
+
+	// t.To (address.Address) (struct)
+
+	{
+
+		if err := t.To.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.To: %w", err), "builtin.TransferEvent", "To")
+		}
+
+	}
+	// t.Amount (big.Int) (struct)
+
+	{
+
+		if err := t.Amount.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Amount: %w", err), "builtin.TransferEvent", "Amount")
 		}
 
 	}
 	return nil
 }
+
+var lengthBufDealPublishedEvent = []byte{131}
+
+func (t *DealPublishedEvent) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufDealPublishedEvent); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.DealID (abi.DealID) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.DealID)); err != nil {
+		return err
+	}
+
+	// t.Client (address.Address) (struct)
+	if err := t.Client.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Provider (address.Address) (struct)
+	if err := t.Provider.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *DealPublishedEvent) UnmarshalCBOR(r io.Reader) error {
+	*t = DealPublishedEvent{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "builtin.DealPublishedEvent", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "builtin.DealPublishedEvent", "")
+	}
+
+	if extra != 3 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "builtin.DealPublishedEvent", "")
+	}
+
+	// t.DealID (abi.DealID) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return acbor.WrapDecodeError(err, "builtin.DealPublishedEvent", "DealID")
+		}
+		if maj != cbg.MajUnsignedInt {
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "builtin.DealPublishedEvent", "DealID")
+		}
+		t.DealID = abi.DealID(extra)
+
+	}
+	// t.Client (address.Address) (struct)
+
+	{
+
+		if err := t.Client.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Client: %w", err), "builtin.DealPublishedEvent", "Client")
+		}
+
+	}
+	// t.Provider (address.Address) (struct)
+
+	{
+
+		if err := t.Provider.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Provider: %w", err), "builtin.DealPublishedEvent", "Provider")
+		}
+
+	}
+	return nil
+}
+
+var lengthBufSectorActivatedEvent = []byte{130}
+
+func (t *SectorActivatedEvent) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufSectorActivatedEvent); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.SectorNumber (abi.SectorNumber) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.SectorNumber)); err != nil {
+		return err
+	}
+
+	// t.DealIDs ([]abi.DealID) (slice)
+	if len(t.DealIDs) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.DealIDs was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.DealIDs))); err != nil {
+		return err
+	}
+	for _, v := range t.DealIDs {
+		if err := cbg.CborWriteHeader(w, cbg.MajUnsignedInt, uint64(v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *SectorActivatedEvent) UnmarshalCBOR(r io.Reader) error {
+	*t = SectorActivatedEvent{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "builtin.SectorActivatedEvent", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "builtin.SectorActivatedEvent", "")
+	}
+
+	if extra != 2 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "builtin.SectorActivatedEvent", "")
+	}
+
+	// t.SectorNumber (abi.SectorNumber) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return acbor.WrapDecodeError(err, "builtin.SectorActivatedEvent", "SectorNumber")
+		}
+		if maj != cbg.MajUnsignedInt {
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "builtin.SectorActivatedEvent", "SectorNumber")
+		}
+		t.SectorNumber = abi.SectorNumber(extra)
+
+	}
+	// t.DealIDs ([]abi.DealID) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "builtin.SectorActivatedEvent", "DealIDs")
+	}
+
+	if extra > cbg.MaxLength {
+		return acbor.WrapDecodeError(fmt.Errorf("t.DealIDs: array too large (%d)", extra), "builtin.SectorActivatedEvent", "DealIDs")
+	}
+
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("expected cbor array"), "builtin.SectorActivatedEvent", "DealIDs")
+	}
+
+	if extra > 0 {
+		t.DealIDs = make([]abi.DealID, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		maj, val, err := cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read uint64 for t.DealIDs slice: %w", err), "builtin.SectorActivatedEvent", fmt.Sprintf("DealIDs[%d]", i))
+		}
+
+		if maj != cbg.MajUnsignedInt {
+			return acbor.WrapDecodeError(xerrors.Errorf("value read for array t.DealIDs was not a uint, instead got %d", maj), "builtin.SectorActivatedEvent", fmt.Sprintf("DealIDs[%d]", i))
+		}
+
+		t.DealIDs[i] = abi.DealID(val)
+	}
+
+	return nil
+}
+
+var lengthBufPowerUpdatedEvent = []byte{131}
+
+func (t *PowerUpdatedEvent) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufPowerUpdatedEvent); err != nil {
+		return err
+	}
+
+	// t.Miner (address.Address) (struct)
+	if err := t.Miner.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.RawByteDelta (big.Int) (struct)
+	if err := t.RawByteDelta.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.QualityAdjustedDelta (big.Int) (struct)
+	if err := t.QualityAdjustedDelta.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *PowerUpdatedEvent) UnmarshalCBOR(r io.Reader) error {
+	*t = PowerUpdatedEvent{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "builtin.PowerUpdatedEvent", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "builtin.PowerUpdatedEvent", "")
+	}
+
+	if extra != 3 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "builtin.PowerUpdatedEvent", "")
+	}
+
+	// t.Miner (address.Address) (struct)
+
+	{
+
+		if err := t.Miner.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Miner: %w", err), "builtin.PowerUpdatedEvent", "Miner")
+		}
+
+	}
+	// t.RawByteDelta (big.Int) (struct)
+
+	{
+
+		if err := t.RawByteDelta.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.RawByteDelta: %w", err), "builtin.PowerUpdatedEvent", "RawByteDelta")
+		}
+
+	}
+	// t.QualityAdjustedDelta (big.Int) (struct)
+
+	{
+
+		if err := t.QualityAdjustedDelta.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.QualityAdjustedDelta: %w", err), "builtin.PowerUpdatedEvent", "QualityAdjustedDelta")
+		}
+
+	}
+	return nil
+}
+
+var lengthBufSectorsFaultedEvent = []byte{129}
+
+func (t *SectorsFaultedEvent) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufSectorsFaultedEvent); err != nil {
+		return err
+	}
+
+	// t.Sectors (bitfield.BitField) (struct)
+	if err := t.Sectors.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *SectorsFaultedEvent) UnmarshalCBOR(r io.Reader) error {
+	*t = SectorsFaultedEvent{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "builtin.SectorsFaultedEvent", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "builtin.SectorsFaultedEvent", "")
+	}
+
+	if extra != 1 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "builtin.SectorsFaultedEvent", "")
+	}
+
+	// t.Sectors (bitfield.BitField) (struct)
+
+	{
+
+		if err := t.Sectors.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Sectors: %w", err), "builtin.SectorsFaultedEvent", "Sectors")
+		}
+
+	}
+	return nil
+}
+
+var lengthBufDataCapGrantedEvent = []byte{131}
+
+func (t *DataCapGrantedEvent) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufDataCapGrantedEvent); err != nil {
+		return err
+	}
+
+	// t.Verifier (address.Address) (struct)
+	if err := t.Verifier.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Client (address.Address) (struct)
+	if err := t.Client.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Allowance (big.Int) (struct)
+	if err := t.Allowance.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *DataCapGrantedEvent) UnmarshalCBOR(r io.Reader) error {
+	*t = DataCapGrantedEvent{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "builtin.DataCapGrantedEvent", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "builtin.DataCapGrantedEvent", "")
+	}
+
+	if extra != 3 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "builtin.DataCapGrantedEvent", "")
+	}
+
+	// t.Verifier (address.Address) (struct)
+
+	{
+
+		if err := t.Verifier.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Verifier: %w", err), "builtin.DataCapGrantedEvent", "Verifier")
+		}
+
+	}
+	// t.Client (address.Address) (struct)
+
+	{
+
+		if err := t.Client.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Client: %w", err), "builtin.DataCapGrantedEvent", "Client")
+		}
+
+	}
+	// t.Allowance (big.Int) (struct)
+
+	{
+
+		if err := t.Allowance.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Allowance: %w", err), "builtin.DataCapGrantedEvent", "Allowance")
+		}
+
+	}
+	return nil
+}
+
+var lengthBufSectorsFailure = []byte{129}
+
+func (t *SectorsFailure) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufSectorsFailure); err != nil {
+		return err
+	}
+
+	// t.Sectors (bitfield.BitField) (struct)
+	if err := t.Sectors.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *SectorsFailure) UnmarshalCBOR(r io.Reader) error {
+	*t = SectorsFailure{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "builtin.SectorsFailure", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "builtin.SectorsFailure", "")
+	}
+
+	if extra != 1 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "builtin.SectorsFailure", "")
+	}
+
+	// t.Sectors (bitfield.BitField) (struct)
+
+	{
+
+		if err := t.Sectors.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Sectors: %w", err), "builtin.SectorsFailure", "Sectors")
+		}
+
+	}
+	return nil
+}
+
+var lengthBufDealsFailure = []byte{129}
+
+func (t *DealsFailure) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufDealsFailure); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.DealIDs ([]abi.DealID) (slice)
+	if len(t.DealIDs) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.DealIDs was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.DealIDs))); err != nil {
+		return err
+	}
+	for _, v := range t.DealIDs {
+		if err := cbg.CborWriteHeader(w, cbg.MajUnsignedInt, uint64(v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *DealsFailure) UnmarshalCBOR(r io.Reader) error {
+	*t = DealsFailure{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "builtin.DealsFailure", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "builtin.DealsFailure", "")
+	}
+
+	if extra != 1 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "builtin.DealsFailure", "")
+	}
+
+	// t.DealIDs ([]abi.DealID) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "builtin.DealsFailure", "DealIDs")
+	}
+
+	if extra > cbg.MaxLength {
+		return acbor.WrapDecodeError(fmt.Errorf("t.DealIDs: array too large (%d)", extra), "builtin.DealsFailure", "DealIDs")
+	}
+
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("expected cbor array"), "builtin.DealsFailure", "DealIDs")
+	}
+
+	if extra > 0 {
+		t.DealIDs = make([]abi.DealID, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		maj, val, err := cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read uint64 for t.DealIDs slice: %w", err), "builtin.DealsFailure", fmt.Sprintf("DealIDs[%d]", i))
+		}
+
+		if maj != cbg.MajUnsignedInt {
+			return acbor.WrapDecodeError(xerrors.Errorf("value read for array t.DealIDs was not a uint, instead got %d", maj), "builtin.DealsFailure", fmt.Sprintf("DealIDs[%d]", i))
+		}
+
+		t.DealIDs[i] = abi.DealID(val)
+	}
+
+	return nil
+}
+
+func (t *MinerAddrs) MaxEncodedLength() int64 {
+	return 540808
+}
+
+func (t *ApplyRewardParams) MaxEncodedLength() int64 {
+	return 261
+}
+
+func (t *TransferEvent) MaxEncodedLength() int64 {
+	return 197
+}
+
+func (t *DealPublishedEvent) MaxEncodedLength() int64 {
+	return 142
+}
+
+func (t *SectorActivatedEvent) MaxEncodedLength() int64 {
+	return 73741
+}
+
+func (t *PowerUpdatedEvent) MaxEncodedLength() int64 {
+	return 327
+}
+
+func (t *SectorsFaultedEvent) MaxEncodedLength() int64 {
+	return 32772
+}
+
+func (t *DataCapGrantedEvent) MaxEncodedLength() int64 {
+	return 263
+}
+
+func (t *SectorsFailure) MaxEncodedLength() int64 {
+	return 32772
+}
+
+func (t *DealsFailure) MaxEncodedLength() int64 {
+	return 73732
+}
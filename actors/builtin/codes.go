@@ -9,18 +9,19 @@ import (
 
 // The built-in actor code IDs
 var (
-	SystemActorCodeID           cid.Cid
-	InitActorCodeID             cid.Cid
-	CronActorCodeID             cid.Cid
-	AccountActorCodeID          cid.Cid
-	StoragePowerActorCodeID     cid.Cid
-	StorageMinerActorCodeID     cid.Cid
-	StorageMarketActorCodeID    cid.Cid
-	PaymentChannelActorCodeID   cid.Cid
-	MultisigActorCodeID         cid.Cid
-	RewardActorCodeID           cid.Cid
-	VerifiedRegistryActorCodeID cid.Cid
-	CallerTypesSignable         []cid.Cid
+	SystemActorCodeID            cid.Cid
+	InitActorCodeID              cid.Cid
+	CronActorCodeID              cid.Cid
+	AccountActorCodeID           cid.Cid
+	StoragePowerActorCodeID      cid.Cid
+	StorageMinerActorCodeID      cid.Cid
+	StorageMarketActorCodeID     cid.Cid
+	PaymentChannelActorCodeID    cid.Cid
+	MultisigActorCodeID          cid.Cid
+	RewardActorCodeID            cid.Cid
+	VerifiedRegistryActorCodeID  cid.Cid
+	ParameterRegistryActorCodeID cid.Cid
+	CallerTypesSignable          []cid.Cid
 )
 
 var builtinActors map[cid.Cid]*actorInfo
@@ -35,17 +36,18 @@ func init() {
 	builtinActors = make(map[cid.Cid]*actorInfo)
 
 	for id, info := range map[*cid.Cid]*actorInfo{ //nolint:nomaprange
-		&SystemActorCodeID:           {name: "fil/2/system"},
-		&InitActorCodeID:             {name: "fil/2/init"},
-		&CronActorCodeID:             {name: "fil/2/cron"},
-		&StoragePowerActorCodeID:     {name: "fil/2/storagepower"},
-		&StorageMinerActorCodeID:     {name: "fil/2/storageminer"},
-		&StorageMarketActorCodeID:    {name: "fil/2/storagemarket"},
-		&PaymentChannelActorCodeID:   {name: "fil/2/paymentchannel"},
-		&RewardActorCodeID:           {name: "fil/2/reward"},
-		&VerifiedRegistryActorCodeID: {name: "fil/2/verifiedregistry"},
-		&AccountActorCodeID:          {name: "fil/2/account", signer: true},
-		&MultisigActorCodeID:         {name: "fil/2/multisig", signer: true},
+		&SystemActorCodeID:            {name: "fil/2/system"},
+		&InitActorCodeID:              {name: "fil/2/init"},
+		&CronActorCodeID:              {name: "fil/2/cron"},
+		&StoragePowerActorCodeID:      {name: "fil/2/storagepower"},
+		&StorageMinerActorCodeID:      {name: "fil/2/storageminer"},
+		&StorageMarketActorCodeID:     {name: "fil/2/storagemarket"},
+		&PaymentChannelActorCodeID:    {name: "fil/2/paymentchannel"},
+		&RewardActorCodeID:            {name: "fil/2/reward"},
+		&VerifiedRegistryActorCodeID:  {name: "fil/2/verifiedregistry"},
+		&ParameterRegistryActorCodeID: {name: "fil/2/parameterregistry"},
+		&AccountActorCodeID:           {name: "fil/2/account", signer: true},
+		&MultisigActorCodeID:          {name: "fil/2/multisig", signer: true},
 	} {
 		c, err := builder.Sum([]byte(info.name))
 		if err != nil {
@@ -7,13 +7,14 @@ import (
 	"io"
 
 	abi "github.com/filecoin-project/go-state-types/abi"
+	acbor "github.com/filecoin-project/specs-actors/v2/actors/util/cbor"
 	cbg "github.com/whyrusleeping/cbor-gen"
 	xerrors "golang.org/x/xerrors"
 )
 
 var _ = xerrors.Errorf
 
-var lengthBufState = []byte{129}
+var lengthBufState = []byte{130}
 
 func (t *State) MarshalCBOR(w io.Writer) error {
 	if t == nil {
@@ -39,6 +40,20 @@ func (t *State) MarshalCBOR(w io.Writer) error {
 			return err
 		}
 	}
+
+	// t.UserEntries ([]cron.UserEntry) (slice)
+	if len(t.UserEntries) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.UserEntries was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.UserEntries))); err != nil {
+		return err
+	}
+	for _, v := range t.UserEntries {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -50,29 +65,29 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "cron.State", "")
 	}
 	if maj != cbg.MajArray {
-		return fmt.Errorf("cbor input should be of type array")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "cron.State", "")
 	}
 
-	if extra != 1 {
-		return fmt.Errorf("cbor input had wrong number of fields")
+	if extra != 2 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "cron.State", "")
 	}
 
 	// t.Entries ([]cron.Entry) (slice)
 
 	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "cron.State", "Entries")
 	}
 
 	if extra > cbg.MaxLength {
-		return fmt.Errorf("t.Entries: array too large (%d)", extra)
+		return acbor.WrapDecodeError(fmt.Errorf("t.Entries: array too large (%d)", extra), "cron.State", "Entries")
 	}
 
 	if maj != cbg.MajArray {
-		return fmt.Errorf("expected cbor array")
+		return acbor.WrapDecodeError(fmt.Errorf("expected cbor array"), "cron.State", "Entries")
 	}
 
 	if extra > 0 {
@@ -83,16 +98,45 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 		var v Entry
 		if err := v.UnmarshalCBOR(br); err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "cron.State", fmt.Sprintf("Entries[%d]", i))
 		}
 
 		t.Entries[i] = v
 	}
 
+	// t.UserEntries ([]cron.UserEntry) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "cron.State", "UserEntries")
+	}
+
+	if extra > cbg.MaxLength {
+		return acbor.WrapDecodeError(fmt.Errorf("t.UserEntries: array too large (%d)", extra), "cron.State", "UserEntries")
+	}
+
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("expected cbor array"), "cron.State", "UserEntries")
+	}
+
+	if extra > 0 {
+		t.UserEntries = make([]UserEntry, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v UserEntry
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(err, "cron.State", fmt.Sprintf("UserEntries[%d]", i))
+		}
+
+		t.UserEntries[i] = v
+	}
+
 	return nil
 }
 
-var lengthBufEntry = []byte{130}
+var lengthBufEntry = []byte{131}
 
 func (t *Entry) MarshalCBOR(w io.Writer) error {
 	if t == nil {
@@ -116,6 +160,12 @@ func (t *Entry) MarshalCBOR(w io.Writer) error {
 		return err
 	}
 
+	// t.Priority (uint64) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Priority)); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -127,14 +177,137 @@ func (t *Entry) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
+		return acbor.WrapDecodeError(err, "cron.Entry", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "cron.Entry", "")
+	}
+
+	if extra != 3 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "cron.Entry", "")
+	}
+
+	// t.Receiver (address.Address) (struct)
+
+	{
+
+		if err := t.Receiver.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Receiver: %w", err), "cron.Entry", "Receiver")
+		}
+
+	}
+	// t.MethodNum (abi.MethodNum) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return acbor.WrapDecodeError(err, "cron.Entry", "MethodNum")
+		}
+		if maj != cbg.MajUnsignedInt {
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "cron.Entry", "MethodNum")
+		}
+		t.MethodNum = abi.MethodNum(extra)
+
+	}
+	// t.Priority (uint64) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return acbor.WrapDecodeError(err, "cron.Entry", "Priority")
+		}
+		if maj != cbg.MajUnsignedInt {
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "cron.Entry", "Priority")
+		}
+		t.Priority = uint64(extra)
+
+	}
+	return nil
+}
+
+var lengthBufUserEntry = []byte{135}
+
+func (t *UserEntry) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
 		return err
 	}
+	if _, err := w.Write(lengthBufUserEntry); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Receiver (address.Address) (struct)
+	if err := t.Receiver.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.MethodNum (abi.MethodNum) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.MethodNum)); err != nil {
+		return err
+	}
+
+	// t.Priority (uint64) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Priority)); err != nil {
+		return err
+	}
+
+	// t.Bond (big.Int) (struct)
+	if err := t.Bond.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.ConsecutiveOutOfGas (uint64) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.ConsecutiveOutOfGas)); err != nil {
+		return err
+	}
+
+	// t.NextEpoch (abi.ChainEpoch) (int64)
+	if t.NextEpoch >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.NextEpoch)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.NextEpoch-1)); err != nil {
+			return err
+		}
+	}
+
+	// t.LastEpoch (abi.ChainEpoch) (int64)
+	if t.LastEpoch >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.LastEpoch)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.LastEpoch-1)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *UserEntry) UnmarshalCBOR(r io.Reader) error {
+	*t = UserEntry{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "cron.UserEntry", "")
+	}
 	if maj != cbg.MajArray {
-		return fmt.Errorf("cbor input should be of type array")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "cron.UserEntry", "")
 	}
 
-	if extra != 2 {
-		return fmt.Errorf("cbor input had wrong number of fields")
+	if extra != 7 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "cron.UserEntry", "")
 	}
 
 	// t.Receiver (address.Address) (struct)
@@ -142,7 +315,7 @@ func (t *Entry) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.Receiver.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.Receiver: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Receiver: %w", err), "cron.UserEntry", "Receiver")
 		}
 
 	}
@@ -152,13 +325,337 @@ func (t *Entry) UnmarshalCBOR(r io.Reader) error {
 
 		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
 		if err != nil {
+			return acbor.WrapDecodeError(err, "cron.UserEntry", "MethodNum")
+		}
+		if maj != cbg.MajUnsignedInt {
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "cron.UserEntry", "MethodNum")
+		}
+		t.MethodNum = abi.MethodNum(extra)
+
+	}
+	// t.Priority (uint64) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return acbor.WrapDecodeError(err, "cron.UserEntry", "Priority")
+		}
+		if maj != cbg.MajUnsignedInt {
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "cron.UserEntry", "Priority")
+		}
+		t.Priority = uint64(extra)
+
+	}
+	// t.Bond (big.Int) (struct)
+
+	{
+
+		if err := t.Bond.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Bond: %w", err), "cron.UserEntry", "Bond")
+		}
+
+	}
+	// t.ConsecutiveOutOfGas (uint64) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return acbor.WrapDecodeError(err, "cron.UserEntry", "ConsecutiveOutOfGas")
+		}
+		if maj != cbg.MajUnsignedInt {
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "cron.UserEntry", "ConsecutiveOutOfGas")
+		}
+		t.ConsecutiveOutOfGas = uint64(extra)
+
+	}
+	// t.NextEpoch (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return acbor.WrapDecodeError(err, "cron.UserEntry", "NextEpoch")
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "cron.UserEntry", "NextEpoch")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "cron.UserEntry", "NextEpoch")
+			}
+			extraI = -1 - extraI
+		default:
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "cron.UserEntry", "NextEpoch")
+		}
+
+		t.NextEpoch = abi.ChainEpoch(extraI)
+	}
+	// t.LastEpoch (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return acbor.WrapDecodeError(err, "cron.UserEntry", "LastEpoch")
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "cron.UserEntry", "LastEpoch")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "cron.UserEntry", "LastEpoch")
+			}
+			extraI = -1 - extraI
+		default:
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "cron.UserEntry", "LastEpoch")
+		}
+
+		t.LastEpoch = abi.ChainEpoch(extraI)
+	}
+	return nil
+}
+
+var lengthBufConstructorParams = []byte{129}
+
+func (t *ConstructorParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufConstructorParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Entries ([]cron.Entry) (slice)
+	if len(t.Entries) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Entries was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Entries))); err != nil {
+		return err
+	}
+	for _, v := range t.Entries {
+		if err := v.MarshalCBOR(w); err != nil {
 			return err
 		}
+	}
+	return nil
+}
+
+func (t *ConstructorParams) UnmarshalCBOR(r io.Reader) error {
+	*t = ConstructorParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "cron.ConstructorParams", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "cron.ConstructorParams", "")
+	}
+
+	if extra != 1 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "cron.ConstructorParams", "")
+	}
+
+	// t.Entries ([]cron.Entry) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "cron.ConstructorParams", "Entries")
+	}
+
+	if extra > cbg.MaxLength {
+		return acbor.WrapDecodeError(fmt.Errorf("t.Entries: array too large (%d)", extra), "cron.ConstructorParams", "Entries")
+	}
+
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("expected cbor array"), "cron.ConstructorParams", "Entries")
+	}
+
+	if extra > 0 {
+		t.Entries = make([]Entry, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v Entry
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(err, "cron.ConstructorParams", fmt.Sprintf("Entries[%d]", i))
+		}
+
+		t.Entries[i] = v
+	}
+
+	return nil
+}
+
+var lengthBufRegisterUserEntryParams = []byte{130}
+
+func (t *RegisterUserEntryParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufRegisterUserEntryParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.MethodNum (abi.MethodNum) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.MethodNum)); err != nil {
+		return err
+	}
+
+	// t.Priority (uint64) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Priority)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (t *RegisterUserEntryParams) UnmarshalCBOR(r io.Reader) error {
+	*t = RegisterUserEntryParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "cron.RegisterUserEntryParams", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "cron.RegisterUserEntryParams", "")
+	}
+
+	if extra != 2 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "cron.RegisterUserEntryParams", "")
+	}
+
+	// t.MethodNum (abi.MethodNum) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return acbor.WrapDecodeError(err, "cron.RegisterUserEntryParams", "MethodNum")
+		}
+		if maj != cbg.MajUnsignedInt {
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "cron.RegisterUserEntryParams", "MethodNum")
+		}
+		t.MethodNum = abi.MethodNum(extra)
+
+	}
+	// t.Priority (uint64) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return acbor.WrapDecodeError(err, "cron.RegisterUserEntryParams", "Priority")
+		}
+		if maj != cbg.MajUnsignedInt {
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "cron.RegisterUserEntryParams", "Priority")
+		}
+		t.Priority = uint64(extra)
+
+	}
+	return nil
+}
+
+var lengthBufDeregisterUserEntryParams = []byte{129}
+
+func (t *DeregisterUserEntryParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufDeregisterUserEntryParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.MethodNum (abi.MethodNum) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.MethodNum)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (t *DeregisterUserEntryParams) UnmarshalCBOR(r io.Reader) error {
+	*t = DeregisterUserEntryParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "cron.DeregisterUserEntryParams", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "cron.DeregisterUserEntryParams", "")
+	}
+
+	if extra != 1 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "cron.DeregisterUserEntryParams", "")
+	}
+
+	// t.MethodNum (abi.MethodNum) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return acbor.WrapDecodeError(err, "cron.DeregisterUserEntryParams", "MethodNum")
+		}
 		if maj != cbg.MajUnsignedInt {
-			return fmt.Errorf("wrong type for uint64 field")
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "cron.DeregisterUserEntryParams", "MethodNum")
 		}
 		t.MethodNum = abi.MethodNum(extra)
 
 	}
 	return nil
 }
+
+func (t *State) MaxEncodedLength() int64 {
+	return 2678791
+}
+
+func (t *Entry) MaxEncodedLength() int64 {
+	return 85
+}
+
+func (t *UserEntry) MaxEncodedLength() int64 {
+	return 242
+}
+
+func (t *ConstructorParams) MaxEncodedLength() int64 {
+	return 696324
+}
+
+func (t *RegisterUserEntryParams) MaxEncodedLength() int64 {
+	return 19
+}
+
+func (t *DeregisterUserEntryParams) MaxEncodedLength() int64 {
+	return 10
+}
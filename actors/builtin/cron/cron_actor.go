@@ -2,8 +2,9 @@ package cron
 
 import (
 	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
 	"github.com/filecoin-project/go-state-types/cbor"
-	cron0 "github.com/filecoin-project/specs-actors/actors/builtin/cron"
+	"github.com/filecoin-project/go-state-types/exitcode"
 	"github.com/ipfs/go-cid"
 
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
@@ -17,6 +18,8 @@ func (a Actor) Exports() []interface{} {
 	return []interface{}{
 		builtin.MethodConstructor: a.Constructor,
 		2:                         a.EpochTick,
+		3:                         a.RegisterUserEntry,
+		4:                         a.DeregisterUserEntry,
 	}
 }
 
@@ -34,20 +37,13 @@ func (a Actor) State() cbor.Er {
 
 var _ runtime.VMActor = Actor{}
 
-//type ConstructorParams struct {
-//	Entries []Entry
-//}
-type ConstructorParams = cron0.ConstructorParams
-
-type EntryParam = cron0.Entry
+type ConstructorParams struct {
+	Entries []Entry
+}
 
 func (a Actor) Constructor(rt runtime.Runtime, params *ConstructorParams) *abi.EmptyValue {
 	rt.ValidateImmediateCallerIs(builtin.SystemActorAddr)
-	entries := make([]Entry, len(params.Entries))
-	for i, e := range params.Entries {
-		entries[i] = Entry(e) // Identical
-	}
-	rt.StateCreate(ConstructState(entries))
+	rt.StateCreate(ConstructState(params.Entries))
 	return nil
 }
 
@@ -58,9 +54,149 @@ func (a Actor) EpochTick(rt runtime.Runtime, _ *abi.EmptyValue) *abi.EmptyValue
 	var st State
 	rt.StateReadonly(&st)
 	for _, entry := range st.Entries {
+		// Builtin entries are invoked with all the gas remaining, since the network's deadline processing and deal
+		// settlement depend on them completing.
 		_ = rt.Send(entry.Receiver, entry.MethodNum, nil, abi.NewTokenAmount(0), &builtin.Discard{})
 		// Any error and return value are ignored.
 	}
 
+	// Charge the user entries selected for this epoch for their invocation before making it,
+	// dropping those whose bond cannot pay for the next. Due entries not selected are carried over uncharged.
+	epoch := rt.CurrEpoch()
+	var due []UserEntry
+	toBurn := big.Zero()
+	rt.StateTransaction(&st, func() {
+		selected := make([]bool, len(st.UserEntries))
+		for _, i := range st.SelectUserEntries(epoch) {
+			selected[i] = true
+			due = append(due, st.UserEntries[i])
+		}
+
+		var remaining []UserEntry
+		for i, entry := range st.UserEntries {
+			if !selected[i] {
+				remaining = append(remaining, entry)
+				continue
+			}
+			entry.Bond = big.Sub(entry.Bond, UserEntryInvocationFee)
+			if entry.Bond.GreaterThanEqual(UserEntryInvocationFee) {
+				remaining = append(remaining, entry)
+				toBurn = big.Add(toBurn, UserEntryInvocationFee)
+			} else {
+				// The remainder of an exhausted bond is burnt with the final fee.
+				toBurn = big.Add(toBurn, big.Add(entry.Bond, UserEntryInvocationFee))
+			}
+		}
+		st.UserEntries = remaining
+	})
+
+	if toBurn.GreaterThan(big.Zero()) {
+		code := rt.Send(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, toBurn, &builtin.Discard{})
+		builtin.RequireSuccess(rt, code, "failed to burn user entry fees")
+	}
+
+	results := make([]exitcode.ExitCode, len(due))
+	for i, entry := range due {
+		results[i] = rt.SendWithGasLimit(entry.Receiver, entry.MethodNum, nil, abi.NewTokenAmount(0), &builtin.Discard{}, UserEntryGasLimit)
+		// Return values are ignored, and errors other than running out of gas have no consequence.
+	}
+
+	rt.StateTransaction(&st, func() {
+		for i, entry := range due {
+			// The entry may have been dropped for an exhausted bond, or deregistered during its invocation.
+			if j := st.FindUserEntry(entry.Receiver, entry.MethodNum); j >= 0 {
+				st.UserEntries[j].RecordInvocation(results[i], epoch)
+			}
+		}
+	})
+
+	return nil
+}
+
+type RegisterUserEntryParams struct {
+	MethodNum abi.MethodNum // The method of the calling actor to invoke each epoch
+	Priority  uint64        // Order of invocation relative to other user entries, lower first, at most MaxUserEntryPriority
+}
+
+// Registers a callback to the calling actor at the end of every epoch, funded by the value sent.
+// A new entry's bond must be at least MinUserEntryBond for the number of entries registered, and an actor may
+// register at most MaxUserEntriesPerRegistrant entries.
+// Each invocation burns UserEntryInvocationFee from the bond, and the entry is removed when the bond can no longer
+// pay for an invocation. Registering an existing entry again adds the value sent to its bond, and if the priority
+// changes, moves the entry after others with the new priority.
+// Only non-builtin actors may register entries; builtin actors are scheduled by the entries installed at genesis.
+func (a Actor) RegisterUserEntry(rt runtime.Runtime, params *RegisterUserEntryParams) *abi.EmptyValue {
+	rt.ValidateImmediateCallerAcceptAny()
+	callerCode, ok := rt.GetActorCodeCID(rt.Caller())
+	if !ok {
+		rt.Abortf(exitcode.ErrNotFound, "no code for caller %v", rt.Caller())
+	}
+	if builtin.IsBuiltinActor(callerCode) {
+		rt.Abortf(exitcode.ErrForbidden, "builtin actor %v cannot register a user entry", rt.Caller())
+	}
+	if params.MethodNum <= builtin.MethodSend {
+		rt.Abortf(exitcode.ErrIllegalArgument, "invalid method number %d", params.MethodNum)
+	}
+	if params.Priority > MaxUserEntryPriority {
+		rt.Abortf(exitcode.ErrIllegalArgument, "priority %d exceeds maximum %d", params.Priority, MaxUserEntryPriority)
+	}
+
+	var st State
+	rt.StateTransaction(&st, func() {
+		if i := st.FindUserEntry(rt.Caller(), params.MethodNum); i >= 0 {
+			entry := st.UserEntries[i]
+			entry.Bond = big.Add(entry.Bond, rt.ValueReceived())
+			if entry.Priority == params.Priority {
+				st.UserEntries[i] = entry
+				return
+			}
+			entry.Priority = params.Priority
+			st.RemoveUserEntry(i)
+			st.InsertUserEntry(entry)
+			return
+		}
+
+		if len(st.UserEntries) >= MaxUserEntries {
+			rt.Abortf(exitcode.ErrForbidden, "too many user entries")
+		}
+		if st.CountUserEntries(rt.Caller()) >= MaxUserEntriesPerRegistrant {
+			rt.Abortf(exitcode.ErrForbidden, "%v already has %d user entries", rt.Caller(), MaxUserEntriesPerRegistrant)
+		}
+		if minBond := MinUserEntryBond(len(st.UserEntries)); rt.ValueReceived().LessThan(minBond) {
+			rt.Abortf(exitcode.ErrInsufficientFunds, "bond %v less than minimum %v for %d registered entries",
+				rt.ValueReceived(), minBond, len(st.UserEntries))
+		}
+		st.InsertUserEntry(UserEntry{
+			Receiver:  rt.Caller(),
+			MethodNum: params.MethodNum,
+			Priority:  params.Priority,
+			Bond:      rt.ValueReceived(),
+			LastEpoch: rt.CurrEpoch(),
+		})
+	})
+	return nil
+}
+
+type DeregisterUserEntryParams struct {
+	MethodNum abi.MethodNum
+}
+
+// Removes the calling actor's entry for a method, returning the remaining bond to the caller.
+func (a Actor) DeregisterUserEntry(rt runtime.Runtime, params *DeregisterUserEntryParams) *abi.EmptyValue {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	var refund abi.TokenAmount
+	var st State
+	rt.StateTransaction(&st, func() {
+		i := st.FindUserEntry(rt.Caller(), params.MethodNum)
+		if i < 0 {
+			rt.Abortf(exitcode.ErrNotFound, "no user entry for %v method %d", rt.Caller(), params.MethodNum)
+		}
+		refund = st.UserEntries[i].Bond
+		st.RemoveUserEntry(i)
+	})
+
+	code := rt.Send(rt.Caller(), builtin.MethodSend, nil, refund, &builtin.Discard{})
+	builtin.RequireSuccess(rt, code, "failed to refund user entry bond")
 	return nil
 }
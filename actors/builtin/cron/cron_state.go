@@ -1,35 +1,229 @@
 package cron
 
 import (
+	"sort"
+
 	addr "github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/exitcode"
 
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
 )
 
+// Entries are invoked in a total order: all builtin entries before all user entries, and within each list
+// in order of priority, then of registration. Each list is kept sorted in this order.
+// User entries due at an epoch are instead ordered by their priority aged by the epochs since they were last
+// invoked, then by that epoch, so that entries carried over from an earlier epoch by the gas budgets
+// (see SelectUserEntries) are invoked first, and no entry is carried over indefinitely.
 type State struct {
 	Entries []Entry
+	// Entries registered by non-builtin actors.
+	UserEntries []UserEntry
 }
 
 type Entry struct {
 	Receiver  addr.Address  // The actor to call (must be an ID-address)
 	MethodNum abi.MethodNum // The method number to call (must accept empty parameters)
+	Priority  uint64        // Entries with lower values are invoked first
+}
+
+// An entry registered by an actor for a callback to itself, paid for from a bond posted at registration.
+type UserEntry struct {
+	Receiver  addr.Address    // The registering actor (an ID-address)
+	MethodNum abi.MethodNum   // The method number to call (must accept empty parameters)
+	Priority  uint64          // Entries with lower values are invoked first
+	Bond      abi.TokenAmount // Remaining bond, from which UserEntryInvocationFee is burnt for each invocation
+	// Number of consecutive invocations that exhausted UserEntryGasLimit.
+	ConsecutiveOutOfGas uint64
+	// Earliest epoch at which the entry will next be invoked, set when backing off after repeated out-of-gas failures.
+	NextEpoch abi.ChainEpoch
+	// Epoch at which the entry was last invoked, or registered if it has not yet been invoked.
+	LastEpoch abi.ChainEpoch
+}
+
+// Amount burnt from a user entry's bond for each invocation, bounding the cost to the system of executing it.
+var UserEntryInvocationFee = big.Div(builtin.TokenPrecision, big.NewInt(10_000)) // 0.0001 FIL
+
+// Maximum number of user entries, bounding the work done in each epoch tick.
+const MaxUserEntries = 1000
+
+// Maximum number of user entries registered by a single actor, so that no one actor can exhaust MaxUserEntries.
+const MaxUserEntriesPerRegistrant = 10
+
+// Returns the minimum bond for a new user entry when a number of entries are already registered: one
+// UserEntryInvocationFee for each registered entry and one for the new entry. Registering the last of
+// MaxUserEntries thus requires a bond for MaxUserEntries invocations, so that locking others out by filling the
+// table requires locking up bonds quadratic in its size.
+func MinUserEntryBond(registered int) abi.TokenAmount {
+	return big.Mul(UserEntryInvocationFee, big.NewInt(int64(registered)+1))
 }
 
+// Gas allowance for the invocation of each user entry.
+const UserEntryGasLimit int64 = 100_000_000
+
+// Gas allowance for the invocations of all user entries registered by a single actor in one epoch.
+// An actor's entries beyond this allowance are carried over to the next epoch.
+const UserRegistrantGasBudget = 3 * UserEntryGasLimit
+
+// Gas allowance for the invocations of all user entries in one epoch, bounding the time taken to validate the
+// epoch tick. Entries beyond this allowance are carried over to the next epoch.
+const UserEntriesGasBudget = 100 * UserEntryGasLimit
+
+// Number of consecutive out-of-gas invocations after which a user entry is skipped for a backoff period.
+const OutOfGasBackoffThreshold = 2
+
+// Maximum number of epochs for which a user entry is skipped after repeated out-of-gas invocations.
+var MaxOutOfGasBackoff = builtin.EpochsInDay
+
+// Priorities of the builtin entries.
+// The power actor's tick is invoked before the market actor's.
+const (
+	PowerEntryPriority  = builtin.PowerCronEntryPriority
+	MarketEntryPriority = builtin.MarketCronEntryPriority
+)
+
+// Priority of entries that don't need to be ordered with respect to others.
+const DefaultEntryPriority uint64 = 1000
+
+// Maximum priority of a user entry. A due entry's priority improves by one for each epoch since it was last
+// invoked, so an entry is carried over in favour of entries of higher priority for at most this many epochs.
+const MaxUserEntryPriority = DefaultEntryPriority
+
+// Constructs state with entries sorted by priority, retaining the given order of entries with equal priority.
 func ConstructState(entries []Entry) *State {
-	return &State{Entries: entries}
+	sorted := append([]Entry(nil), entries...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Priority < sorted[j].Priority
+	})
+	return &State{Entries: sorted}
 }
 
-// The default entries to install in the cron actor's state at genesis.
+// Inserts a user entry after all entries of the same or lower priority.
+func (st *State) InsertUserEntry(entry UserEntry) {
+	i := sort.Search(len(st.UserEntries), func(i int) bool {
+		return st.UserEntries[i].Priority > entry.Priority
+	})
+	st.UserEntries = append(st.UserEntries, UserEntry{})
+	copy(st.UserEntries[i+1:], st.UserEntries[i:])
+	st.UserEntries[i] = entry
+}
+
+// Removes the user entry at an index.
+func (st *State) RemoveUserEntry(i int) {
+	st.UserEntries = append(st.UserEntries[:i], st.UserEntries[i+1:]...)
+}
+
+// Finds the index of the user entry for a receiver and method, or -1 if there is none.
+func (st *State) FindUserEntry(receiver addr.Address, method abi.MethodNum) int {
+	for i, e := range st.UserEntries {
+		if e.Receiver == receiver && e.MethodNum == method {
+			return i
+		}
+	}
+	return -1
+}
+
+// Returns the number of user entries registered by a receiver.
+func (st *State) CountUserEntries(receiver addr.Address) int {
+	count := 0
+	for _, e := range st.UserEntries {
+		if e.Receiver == receiver {
+			count++
+		}
+	}
+	return count
+}
+
+// Selects the user entries to invoke at an epoch, returning their indices in order of invocation.
+// Entries due at the epoch are taken in order of aged priority (see AgedPriority), then of the epoch at which they
+// were last invoked, then of registration, each allotted UserEntryGasLimit while that fits within both its registrant's remaining
+// UserRegistrantGasBudget and the remaining UserEntriesGasBudget. Due entries not selected are carried over.
+func (st *State) SelectUserEntries(epoch abi.ChainEpoch) []int {
+	var due []int
+	for i, e := range st.UserEntries {
+		if e.NextEpoch <= epoch {
+			due = append(due, i)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool {
+		a, b := st.UserEntries[due[i]], st.UserEntries[due[j]]
+		if pa, pb := a.AgedPriority(epoch), b.AgedPriority(epoch); pa != pb {
+			return pa < pb
+		}
+		return a.LastEpoch < b.LastEpoch
+	})
+
+	var selected []int
+	remaining := UserEntriesGasBudget
+	registrantUsed := map[addr.Address]int64{}
+	for _, i := range due {
+		if remaining < UserEntryGasLimit {
+			break
+		}
+		receiver := st.UserEntries[i].Receiver
+		if registrantUsed[receiver]+UserEntryGasLimit > UserRegistrantGasBudget {
+			continue
+		}
+		registrantUsed[receiver] += UserEntryGasLimit
+		remaining -= UserEntryGasLimit
+		selected = append(selected, i)
+	}
+	return selected
+}
+
+// Returns the total bond held for all user entries.
+func (st *State) TotalUserBond() abi.TokenAmount {
+	total := big.Zero()
+	for _, e := range st.UserEntries {
+		total = big.Add(total, e.Bond)
+	}
+	return total
+}
+
+// The default entries to install in the cron actor's state at genesis: one for each singleton actor with a cron
+// method, in order of priority.
 func BuiltInEntries() []Entry {
-	return []Entry{
-		{
-			Receiver:  builtin.StoragePowerActorAddr,
-			MethodNum: builtin.MethodsPower.OnEpochTickEnd,
-		},
-		{
-			Receiver:  builtin.StorageMarketActorAddr,
-			MethodNum: builtin.MethodsMarket.CronTick,
-		},
+	var entries []Entry
+	for _, s := range builtin.Singletons() {
+		if s.CronMethod != 0 {
+			entries = append(entries, Entry{Receiver: s.Address, MethodNum: s.CronMethod, Priority: s.CronPriority})
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Priority < entries[j].Priority })
+	return entries
+}
+
+// Returns an entry's priority at an epoch: its registered priority less the number of epochs since it was last
+// invoked, to a minimum of zero.
+func (e *UserEntry) AgedPriority(epoch abi.ChainEpoch) uint64 {
+	waited := epoch - e.LastEpoch
+	if waited <= 0 {
+		return e.Priority
+	}
+	if uint64(waited) >= e.Priority {
+		return 0
+	}
+	return e.Priority - uint64(waited)
+}
+
+// Records the result of invoking a user entry at an epoch.
+// An entry that runs out of gas OutOfGasBackoffThreshold or more times in a row is not invoked again for a period
+// doubling with each further failure, up to MaxOutOfGasBackoff. Any other result resets the count.
+func (e *UserEntry) RecordInvocation(code exitcode.ExitCode, epoch abi.ChainEpoch) {
+	e.LastEpoch = epoch
+	if code != exitcode.SysErrOutOfGas {
+		e.ConsecutiveOutOfGas = 0
+		return
+	}
+
+	e.ConsecutiveOutOfGas++
+	if e.ConsecutiveOutOfGas < OutOfGasBackoffThreshold {
+		return
+	}
+	backoff := MaxOutOfGasBackoff
+	if shift := e.ConsecutiveOutOfGas - OutOfGasBackoffThreshold + 1; shift < 32 && abi.ChainEpoch(1)<<shift < backoff {
+		backoff = abi.ChainEpoch(1) << shift
 	}
+	e.NextEpoch = epoch + backoff
 }
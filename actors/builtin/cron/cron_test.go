@@ -4,10 +4,13 @@ import (
 	"context"
 	"testing"
 
+	addr "github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
 	"github.com/filecoin-project/go-state-types/exitcode"
+	cid "github.com/ipfs/go-cid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/cron"
@@ -23,7 +26,7 @@ func TestConstructor(t *testing.T) {
 	actor := cronHarness{cron.Actor{}, t}
 
 	receiver := tutil.NewIDAddr(t, 100)
-	builder := mock.NewBuilder(context.Background(), receiver).WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
+	builder := mock.NewBuilder(context.Background(), receiver).WithStateInvariants(cron.CheckStateInvariants).WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
 
 	t.Run("construct with empty entries", func(t *testing.T) {
 		rt := builder.Build(t)
@@ -34,6 +37,7 @@ func TestConstructor(t *testing.T) {
 		rt.GetState(&st)
 		var nilCronEntries = []cron.Entry(nil)
 		assert.Equal(t, nilCronEntries, st.Entries)
+		assert.Equal(t, []cron.UserEntry(nil), st.UserEntries)
 
 		actor.checkState(rt)
 	})
@@ -41,7 +45,7 @@ func TestConstructor(t *testing.T) {
 	t.Run("construct with non-empty entries", func(t *testing.T) {
 		rt := builder.Build(t)
 
-		var entryParams = []cron.EntryParam{
+		var entryParams = []cron.Entry{
 			{Receiver: tutil.NewIDAddr(t, 1001), MethodNum: abi.MethodNum(1001)},
 			{Receiver: tutil.NewIDAddr(t, 1002), MethodNum: abi.MethodNum(1002)},
 			{Receiver: tutil.NewIDAddr(t, 1003), MethodNum: abi.MethodNum(1003)},
@@ -51,11 +55,7 @@ func TestConstructor(t *testing.T) {
 
 		var st cron.State
 		rt.GetState(&st)
-		expectedEntries := make([]cron.Entry, len(entryParams))
-		for i, e := range entryParams {
-			expectedEntries[i] = cron.Entry(e)
-		}
-		assert.Equal(t, expectedEntries, st.Entries)
+		assert.Equal(t, entryParams, st.Entries)
 
 		actor.checkState(rt)
 	})
@@ -65,12 +65,12 @@ func TestEpochTick(t *testing.T) {
 	actor := cronHarness{cron.Actor{}, t}
 
 	receiver := tutil.NewIDAddr(t, 100)
-	builder := mock.NewBuilder(context.Background(), receiver).WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
+	builder := mock.NewBuilder(context.Background(), receiver).WithStateInvariants(cron.CheckStateInvariants).WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
 
 	t.Run("epoch tick with empty entries", func(t *testing.T) {
 		rt := builder.Build(t)
 
-		var nilCronEntries = []cron.EntryParam(nil)
+		var nilCronEntries = []cron.Entry(nil)
 		actor.constructAndVerify(rt, nilCronEntries...)
 		actor.epochTickAndVerify(rt)
 		actor.checkState(rt)
@@ -79,10 +79,10 @@ func TestEpochTick(t *testing.T) {
 	t.Run("epoch tick with non-empty entries", func(t *testing.T) {
 		rt := builder.Build(t)
 
-		entry1 := cron.EntryParam{Receiver: tutil.NewIDAddr(t, 1001), MethodNum: abi.MethodNum(1001)}
-		entry2 := cron.EntryParam{Receiver: tutil.NewIDAddr(t, 1002), MethodNum: abi.MethodNum(1002)}
-		entry3 := cron.EntryParam{Receiver: tutil.NewIDAddr(t, 1003), MethodNum: abi.MethodNum(1003)}
-		entry4 := cron.EntryParam{Receiver: tutil.NewIDAddr(t, 1004), MethodNum: abi.MethodNum(1004)}
+		entry1 := cron.Entry{Receiver: tutil.NewIDAddr(t, 1001), MethodNum: abi.MethodNum(1001)}
+		entry2 := cron.Entry{Receiver: tutil.NewIDAddr(t, 1002), MethodNum: abi.MethodNum(1002)}
+		entry3 := cron.Entry{Receiver: tutil.NewIDAddr(t, 1003), MethodNum: abi.MethodNum(1003)}
+		entry4 := cron.Entry{Receiver: tutil.NewIDAddr(t, 1004), MethodNum: abi.MethodNum(1004)}
 
 		actor.constructAndVerify(rt, entry1, entry2, entry3, entry4)
 		// exit code should not matter
@@ -101,12 +101,391 @@ func TestEpochTick(t *testing.T) {
 	})
 }
 
+func TestEntryOrdering(t *testing.T) {
+	actor := cronHarness{cron.Actor{}, t}
+
+	receiver := tutil.NewIDAddr(t, 100)
+	builder := mock.NewBuilder(context.Background(), receiver).WithStateInvariants(cron.CheckStateInvariants).WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
+
+	t.Run("built-in entries invoke power before market", func(t *testing.T) {
+		st := cron.ConstructState(cron.BuiltInEntries())
+		assert.Equal(t, builtin.StoragePowerActorAddr, st.Entries[0].Receiver)
+		assert.Equal(t, builtin.StorageMarketActorAddr, st.Entries[1].Receiver)
+
+		reversed := cron.BuiltInEntries()
+		reversed[0], reversed[1] = reversed[1], reversed[0]
+		assert.Equal(t, st, cron.ConstructState(reversed))
+	})
+
+	t.Run("entries invoked by priority then registration order", func(t *testing.T) {
+		rt := builder.Build(t)
+
+		entry1 := cron.Entry{Receiver: tutil.NewIDAddr(t, 1001), MethodNum: abi.MethodNum(1001), Priority: 20}
+		entry2 := cron.Entry{Receiver: tutil.NewIDAddr(t, 1002), MethodNum: abi.MethodNum(1002), Priority: 10}
+		entry3 := cron.Entry{Receiver: tutil.NewIDAddr(t, 1003), MethodNum: abi.MethodNum(1003), Priority: 20}
+		entry4 := cron.Entry{Receiver: tutil.NewIDAddr(t, 1004), MethodNum: abi.MethodNum(1004), Priority: 10}
+		actor.constructAndVerify(rt, entry1, entry2, entry3, entry4)
+
+		var st cron.State
+		rt.GetState(&st)
+		assert.Equal(t, []cron.Entry{entry2, entry4, entry1, entry3}, st.Entries)
+
+		// User entries with equal priority are invoked in registration order, and always after builtin entries.
+		fee := cron.UserEntryInvocationFee
+		userCode := tutil.MakeCID("user-actor", nil)
+		user1 := tutil.NewIDAddr(t, 2001)
+		user2 := tutil.NewIDAddr(t, 2002)
+		user3 := tutil.NewIDAddr(t, 2003)
+		bond := big.Mul(fee, big.NewInt(10))
+		actor.registerUserEntryWithPriority(rt, user1, userCode, 1, 5, bond)
+		actor.registerUserEntryWithPriority(rt, user2, userCode, 1, 0, bond)
+		actor.registerUserEntryWithPriority(rt, user3, userCode, 1, 5, bond)
+		actor.checkState(rt)
+
+		for _, e := range []cron.Entry{entry2, entry4, entry1, entry3} {
+			rt.ExpectSend(e.Receiver, e.MethodNum, nil, big.Zero(), nil, exitcode.Ok)
+		}
+		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, big.Mul(fee, big.NewInt(3)), nil, exitcode.Ok)
+		for _, a := range []addr.Address{user2, user1, user3} {
+			rt.ExpectSendWithGasLimit(a, 1, nil, big.Zero(), nil, exitcode.Ok, cron.UserEntryGasLimit)
+		}
+		actor.epochTickAndVerify(rt)
+
+		// Changing priority moves an entry after others of its new priority.
+		actor.registerUserEntryWithPriority(rt, user2, userCode, 1, 5, big.Zero())
+		rt.GetState(&st)
+		var order []addr.Address
+		for _, e := range st.UserEntries {
+			order = append(order, e.Receiver)
+		}
+		assert.Equal(t, []addr.Address{user1, user3, user2}, order)
+		actor.checkState(rt)
+	})
+}
+
+func TestUserEntries(t *testing.T) {
+	actor := cronHarness{cron.Actor{}, t}
+
+	receiver := tutil.NewIDAddr(t, 100)
+	userActor := tutil.NewIDAddr(t, 1001)
+	userCode := tutil.MakeCID("user-actor", nil)
+	method := abi.MethodNum(1001)
+	fee := cron.UserEntryInvocationFee
+	builder := mock.NewBuilder(context.Background(), receiver).WithStateInvariants(cron.CheckStateInvariants).WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
+
+	t.Run("register and invoke until bond is exhausted", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		// Bond covers two invocations and change.
+		bond := big.Add(big.Mul(fee, big.NewInt(2)), big.NewInt(1))
+		actor.registerUserEntry(rt, userActor, userCode, method, bond)
+
+		var st cron.State
+		rt.GetState(&st)
+		assert.Equal(t, []cron.UserEntry{{Receiver: userActor, MethodNum: method, Bond: bond}}, st.UserEntries)
+		actor.checkState(rt)
+
+		// First tick charges the fee and invokes the entry, which remains.
+		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, fee, nil, exitcode.Ok)
+		rt.ExpectSendWithGasLimit(userActor, method, nil, big.Zero(), nil, exitcode.ErrIllegalState, cron.UserEntryGasLimit)
+		actor.epochTickAndVerify(rt)
+		rt.GetState(&st)
+		assert.Equal(t, 1, len(st.UserEntries))
+		assert.Equal(t, big.Sub(bond, fee), st.UserEntries[0].Bond)
+		actor.checkState(rt)
+
+		// Second tick leaves too little for a third invocation, so the entry is dropped and the remainder burnt.
+		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, big.Sub(bond, fee), nil, exitcode.Ok)
+		rt.ExpectSendWithGasLimit(userActor, method, nil, big.Zero(), nil, exitcode.Ok, cron.UserEntryGasLimit)
+		actor.epochTickAndVerify(rt)
+		rt.GetState(&st)
+		assert.Equal(t, 0, len(st.UserEntries))
+		assert.Equal(t, big.Zero(), rt.Balance())
+		actor.checkState(rt)
+
+		// Nothing further is invoked.
+		actor.epochTickAndVerify(rt)
+	})
+
+	t.Run("user entries invoked after builtin entries", func(t *testing.T) {
+		rt := builder.Build(t)
+		entry := cron.Entry{Receiver: builtin.StoragePowerActorAddr, MethodNum: builtin.MethodsPower.OnEpochTickEnd}
+		actor.constructAndVerify(rt, entry)
+		actor.registerUserEntry(rt, userActor, userCode, method, big.Mul(fee, big.NewInt(10)))
+
+		rt.ExpectSend(entry.Receiver, entry.MethodNum, nil, big.Zero(), nil, exitcode.Ok)
+		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, fee, nil, exitcode.Ok)
+		rt.ExpectSendWithGasLimit(userActor, method, nil, big.Zero(), nil, exitcode.Ok, cron.UserEntryGasLimit)
+		actor.epochTickAndVerify(rt)
+		actor.checkState(rt)
+	})
+
+	t.Run("entries repeatedly out of gas are backed off", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		actor.registerUserEntry(rt, userActor, userCode, method, big.Mul(fee, big.NewInt(100)))
+
+		tick := func(epoch abi.ChainEpoch, invoked bool, code exitcode.ExitCode) {
+			rt.SetEpoch(epoch)
+			if invoked {
+				rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, fee, nil, exitcode.Ok)
+				rt.ExpectSendWithGasLimit(userActor, method, nil, big.Zero(), nil, code, cron.UserEntryGasLimit)
+			}
+			actor.epochTickAndVerify(rt)
+			actor.checkState(rt)
+		}
+
+		// A single out-of-gas failure does not back off.
+		tick(1, true, exitcode.SysErrOutOfGas)
+		tick(2, true, exitcode.SysErrOutOfGas)
+
+		// The second consecutive failure skips the entry for two epochs.
+		var st cron.State
+		rt.GetState(&st)
+		assert.Equal(t, uint64(2), st.UserEntries[0].ConsecutiveOutOfGas)
+		assert.Equal(t, abi.ChainEpoch(4), st.UserEntries[0].NextEpoch)
+		tick(3, false, exitcode.Ok)
+
+		// A further failure doubles the backoff.
+		tick(4, true, exitcode.SysErrOutOfGas)
+		rt.GetState(&st)
+		assert.Equal(t, abi.ChainEpoch(8), st.UserEntries[0].NextEpoch)
+		tick(5, false, exitcode.Ok)
+		tick(7, false, exitcode.Ok)
+
+		// Any other result resets the count.
+		tick(8, true, exitcode.ErrIllegalState)
+		rt.GetState(&st)
+		assert.Equal(t, uint64(0), st.UserEntries[0].ConsecutiveOutOfGas)
+		tick(9, true, exitcode.SysErrOutOfGas)
+		tick(10, true, exitcode.Ok)
+	})
+
+	t.Run("backoff is bounded", func(t *testing.T) {
+		entry := cron.UserEntry{ConsecutiveOutOfGas: 100}
+		entry.RecordInvocation(exitcode.SysErrOutOfGas, 10)
+		assert.Equal(t, 10+cron.MaxOutOfGasBackoff, entry.NextEpoch)
+	})
+
+	t.Run("entries beyond registrant budget are carried over", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		perEpoch := int(cron.UserRegistrantGasBudget / cron.UserEntryGasLimit)
+		var methods []abi.MethodNum
+		for i := 0; i <= perEpoch; i++ {
+			methods = append(methods, abi.MethodNum(2+i))
+			actor.registerUserEntry(rt, userActor, userCode, methods[i], big.Mul(fee, big.NewInt(10)))
+		}
+
+		// The last entry exceeds the registrant's budget and is not charged.
+		rt.SetEpoch(1)
+		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, big.Mul(fee, big.NewInt(int64(perEpoch))), nil, exitcode.Ok)
+		for _, m := range methods[:perEpoch] {
+			rt.ExpectSendWithGasLimit(userActor, m, nil, big.Zero(), nil, exitcode.Ok, cron.UserEntryGasLimit)
+		}
+		actor.epochTickAndVerify(rt)
+		var st cron.State
+		rt.GetState(&st)
+		assert.Equal(t, big.Mul(fee, big.NewInt(10)), st.UserEntries[perEpoch].Bond)
+		actor.checkState(rt)
+
+		// The carried over entry is invoked first in the next epoch, and the last of the others is carried over.
+		rt.SetEpoch(2)
+		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, big.Mul(fee, big.NewInt(int64(perEpoch))), nil, exitcode.Ok)
+		rt.ExpectSendWithGasLimit(userActor, methods[perEpoch], nil, big.Zero(), nil, exitcode.Ok, cron.UserEntryGasLimit)
+		for _, m := range methods[:perEpoch-1] {
+			rt.ExpectSendWithGasLimit(userActor, m, nil, big.Zero(), nil, exitcode.Ok, cron.UserEntryGasLimit)
+		}
+		actor.epochTickAndVerify(rt)
+		actor.checkState(rt)
+	})
+
+	t.Run("entries beyond epoch budget are carried over", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		perEpoch := int(cron.UserEntriesGasBudget / cron.UserEntryGasLimit)
+		// Each bond is that required of the last entry registered.
+		bond := cron.MinUserEntryBond(perEpoch)
+		var users []addr.Address
+		for i := 0; i <= perEpoch; i++ {
+			users = append(users, tutil.NewIDAddr(t, uint64(2000+i)))
+			actor.registerUserEntry(rt, users[i], userCode, method, bond)
+		}
+
+		rt.SetEpoch(1)
+		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, big.Mul(fee, big.NewInt(int64(perEpoch))), nil, exitcode.Ok)
+		for _, u := range users[:perEpoch] {
+			rt.ExpectSendWithGasLimit(u, method, nil, big.Zero(), nil, exitcode.Ok, cron.UserEntryGasLimit)
+		}
+		actor.epochTickAndVerify(rt)
+		actor.checkState(rt)
+
+		rt.SetEpoch(2)
+		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, big.Mul(fee, big.NewInt(int64(perEpoch))), nil, exitcode.Ok)
+		rt.ExpectSendWithGasLimit(users[perEpoch], method, nil, big.Zero(), nil, exitcode.Ok, cron.UserEntryGasLimit)
+		for _, u := range users[:perEpoch-1] {
+			rt.ExpectSendWithGasLimit(u, method, nil, big.Zero(), nil, exitcode.Ok, cron.UserEntryGasLimit)
+		}
+		actor.epochTickAndVerify(rt)
+		actor.checkState(rt)
+	})
+
+	t.Run("low priority entry is invoked under saturation", func(t *testing.T) {
+		// Enough registrants, each with entries at the highest priority, to fill every epoch's budget.
+		var st cron.State
+		perRegistrant := int(cron.UserRegistrantGasBudget / cron.UserEntryGasLimit)
+		perEpoch := int(cron.UserEntriesGasBudget / cron.UserEntryGasLimit)
+		for i := 0; i < perEpoch/perRegistrant+1; i++ {
+			for j := 0; j < perRegistrant; j++ {
+				st.InsertUserEntry(cron.UserEntry{Receiver: tutil.NewIDAddr(t, uint64(2000+i)), MethodNum: abi.MethodNum(2 + j)})
+			}
+		}
+		st.InsertUserEntry(cron.UserEntry{Receiver: userActor, MethodNum: method, Priority: cron.MaxUserEntryPriority})
+
+		invokedAt := abi.ChainEpoch(-1)
+		for epoch := abi.ChainEpoch(1); epoch <= abi.ChainEpoch(cron.MaxUserEntryPriority)+1 && invokedAt < 0; epoch++ {
+			selected := st.SelectUserEntries(epoch)
+			require.Len(t, selected, perEpoch)
+			for _, i := range selected {
+				if st.UserEntries[i].Receiver == userActor {
+					invokedAt = epoch
+				}
+				st.UserEntries[i].RecordInvocation(exitcode.Ok, epoch)
+			}
+		}
+		assert.Equal(t, abi.ChainEpoch(cron.MaxUserEntryPriority), invokedAt)
+	})
+
+	t.Run("priority is bounded", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		rt.SetCaller(userActor, userCode)
+		rt.SetReceived(fee)
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(actor.RegisterUserEntry, &cron.RegisterUserEntryParams{MethodNum: method, Priority: cron.MaxUserEntryPriority + 1})
+		})
+	})
+
+	t.Run("registering again tops up bond", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		actor.registerUserEntry(rt, userActor, userCode, method, fee)
+		actor.registerUserEntry(rt, userActor, userCode, method, fee)
+
+		var st cron.State
+		rt.GetState(&st)
+		assert.Equal(t, []cron.UserEntry{{Receiver: userActor, MethodNum: method, Bond: big.Mul(fee, big.NewInt(2))}}, st.UserEntries)
+		actor.checkState(rt)
+	})
+
+	t.Run("deregister refunds bond", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		bond := big.Mul(fee, big.NewInt(5))
+		actor.registerUserEntry(rt, userActor, userCode, method, bond)
+
+		rt.SetCaller(userActor, userCode)
+		rt.ExpectValidateCallerAny()
+		rt.ExpectSend(userActor, builtin.MethodSend, nil, bond, nil, exitcode.Ok)
+		ret := rt.Call(actor.DeregisterUserEntry, &cron.DeregisterUserEntryParams{MethodNum: method})
+		assert.Nil(t, ret)
+		rt.Verify()
+
+		var st cron.State
+		rt.GetState(&st)
+		assert.Equal(t, 0, len(st.UserEntries))
+		actor.checkState(rt)
+
+		// A second deregistration finds nothing.
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbort(exitcode.ErrNotFound, func() {
+			rt.Call(actor.DeregisterUserEntry, &cron.DeregisterUserEntryParams{MethodNum: method})
+		})
+	})
+
+	t.Run("builtin actor cannot register", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		rt.SetCaller(tutil.NewIDAddr(t, 1002), builtin.MultisigActorCodeID)
+		rt.SetReceived(fee)
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			rt.Call(actor.RegisterUserEntry, &cron.RegisterUserEntryParams{MethodNum: method})
+		})
+	})
+
+	t.Run("insufficient bond", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		rt.SetCaller(userActor, userCode)
+		rt.SetReceived(big.Sub(fee, big.NewInt(1)))
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbort(exitcode.ErrInsufficientFunds, func() {
+			rt.Call(actor.RegisterUserEntry, &cron.RegisterUserEntryParams{MethodNum: method})
+		})
+	})
+
+	t.Run("bond scales with registered entries", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		actor.registerUserEntry(rt, userActor, userCode, method, fee)
+		actor.registerUserEntry(rt, userActor, userCode, method+1, big.Mul(fee, big.NewInt(2)))
+
+		rt.SetCaller(userActor, userCode)
+		rt.SetReceived(big.Sub(cron.MinUserEntryBond(2), big.NewInt(1)))
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbortContainsMessage(exitcode.ErrInsufficientFunds, "for 2 registered entries", func() {
+			rt.Call(actor.RegisterUserEntry, &cron.RegisterUserEntryParams{MethodNum: method + 2})
+		})
+		rt.Reset()
+		actor.registerUserEntry(rt, userActor, userCode, method+2, cron.MinUserEntryBond(2))
+		actor.checkState(rt)
+	})
+
+	t.Run("registrant cannot take more than its share of entries", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		for i := 0; i < cron.MaxUserEntriesPerRegistrant; i++ {
+			actor.registerUserEntry(rt, userActor, userCode, method+abi.MethodNum(i), cron.MinUserEntryBond(i))
+		}
+
+		rt.SetCaller(userActor, userCode)
+		rt.SetReceived(cron.MinUserEntryBond(cron.MaxUserEntriesPerRegistrant))
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbortContainsMessage(exitcode.ErrForbidden, "already has", func() {
+			rt.Call(actor.RegisterUserEntry, &cron.RegisterUserEntryParams{MethodNum: method + cron.MaxUserEntriesPerRegistrant})
+		})
+		rt.Reset()
+
+		// Another actor may still register.
+		actor.registerUserEntry(rt, tutil.NewIDAddr(t, 1002), userCode, method, cron.MinUserEntryBond(cron.MaxUserEntriesPerRegistrant))
+		actor.checkState(rt)
+	})
+
+	t.Run("invalid method number", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		rt.SetCaller(userActor, userCode)
+		rt.SetReceived(fee)
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(actor.RegisterUserEntry, &cron.RegisterUserEntryParams{MethodNum: builtin.MethodSend})
+		})
+	})
+}
+
 type cronHarness struct {
 	cron.Actor
 	t testing.TB
 }
 
-func (h *cronHarness) constructAndVerify(rt *mock.Runtime, entries ...cron.EntryParam) {
+func (h *cronHarness) constructAndVerify(rt *mock.Runtime, entries ...cron.Entry) {
 	params := cron.ConstructorParams{Entries: entries}
 	rt.ExpectValidateCallerAddr(builtin.SystemActorAddr)
 	ret := rt.Call(h.Constructor, &params)
@@ -121,10 +500,26 @@ func (h *cronHarness) epochTickAndVerify(rt *mock.Runtime) {
 	rt.Verify()
 }
 
+func (h *cronHarness) registerUserEntry(rt *mock.Runtime, caller addr.Address, code cid.Cid, method abi.MethodNum, bond abi.TokenAmount) {
+	h.registerUserEntryWithPriority(rt, caller, code, method, 0, bond)
+}
+
+func (h *cronHarness) registerUserEntryWithPriority(rt *mock.Runtime, caller addr.Address, code cid.Cid, method abi.MethodNum, priority uint64, bond abi.TokenAmount) {
+	rt.SetCaller(caller, code)
+	rt.SetReceived(bond)
+	rt.SetBalance(big.Add(rt.Balance(), bond))
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.RegisterUserEntry, &cron.RegisterUserEntryParams{MethodNum: method, Priority: priority})
+	assert.Nil(h.t, ret)
+	rt.Verify()
+	rt.SetCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
+	rt.SetReceived(big.Zero())
+}
+
 func (h *cronHarness) checkState(rt *mock.Runtime) {
 	var st cron.State
 	rt.GetState(&st)
-	_, msgs, err := cron.CheckStateInvariants(&st, rt.AdtStore())
+	_, msgs, err := cron.CheckStateInvariants(&st, rt.AdtStore(), rt.Balance())
 	assert.NoError(h.t, err)
 	assert.True(h.t, msgs.IsEmpty())
 }
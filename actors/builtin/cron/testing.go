@@ -2,23 +2,58 @@ package cron
 
 import (
 	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
 	"github.com/filecoin-project/specs-actors/v2/actors/util/adt"
 )
 
 type StateSummary struct {
-	EntryCount int
+	EntryCount     int
+	UserEntryCount int
 }
 
 // Checks internal invariants of cron state.
-func CheckStateInvariants(st *State, store adt.Store) (*StateSummary, *builtin.MessageAccumulator, error) {
+func CheckStateInvariants(st *State, store adt.Store, balance abi.TokenAmount) (*StateSummary, *builtin.MessageAccumulator, error) {
 	acc := &builtin.MessageAccumulator{}
 	for i, e := range st.Entries {
 		acc.Require(e.Receiver.Protocol() == address.ID, "entry %d receiver address %v must be ID protocol", i, e.Receiver)
 		acc.Require(e.MethodNum > 0, "entry %d has invalid method number %d", i, e.MethodNum)
+		if i > 0 {
+			acc.Require(st.Entries[i-1].Priority <= e.Priority, "entry %d priority %d out of order", i, e.Priority)
+		}
+	}
+
+	type userKey struct {
+		receiver address.Address
+		method   abi.MethodNum
 	}
+	seen := map[userKey]bool{}
+	perRegistrant := map[address.Address]int{}
+	for i, e := range st.UserEntries {
+		acc.Require(e.Receiver.Protocol() == address.ID, "user entry %d receiver address %v must be ID protocol", i, e.Receiver)
+		acc.Require(e.MethodNum > builtin.MethodSend, "user entry %d has invalid method number %d", i, e.MethodNum)
+		acc.Require(e.Bond.GreaterThanEqual(UserEntryInvocationFee), "user entry %d bond %v less than invocation fee", i, e.Bond)
+		acc.Require(e.NextEpoch >= 0, "user entry %d next epoch %d is negative", i, e.NextEpoch)
+		acc.Require(e.LastEpoch >= 0, "user entry %d last epoch %d is negative", i, e.LastEpoch)
+		if i > 0 {
+			acc.Require(st.UserEntries[i-1].Priority <= e.Priority, "user entry %d priority %d out of order", i, e.Priority)
+		}
+		key := userKey{e.Receiver, e.MethodNum}
+		acc.Require(!seen[key], "user entry %d duplicates receiver %v method %d", i, e.Receiver, e.MethodNum)
+		seen[key] = true
+		perRegistrant[e.Receiver]++
+	}
+	for receiver, count := range perRegistrant {
+		acc.Require(count <= MaxUserEntriesPerRegistrant, "%v has %d user entries, exceeding maximum %d", receiver, count, MaxUserEntriesPerRegistrant)
+	}
+	acc.Require(len(st.UserEntries) <= MaxUserEntries, "%d user entries exceeds maximum %d", len(st.UserEntries), MaxUserEntries)
+
+	totalBond := st.TotalUserBond()
+	acc.Require(totalBond.LessThanEqual(balance), "total user entry bond %v exceeds balance %v", totalBond, balance)
 
 	return &StateSummary{
-		EntryCount: len(st.Entries),
+		EntryCount:     len(st.Entries),
+		UserEntryCount: len(st.UserEntries),
 	}, acc, nil
 }
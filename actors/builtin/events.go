@@ -0,0 +1,99 @@
+package builtin
+
+import (
+	addr "github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-bitfield"
+	"github.com/filecoin-project/go-state-types/abi"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/runtime"
+)
+
+// Keys of the events emitted by builtin actors.
+// Each event has a single entry, keyed by one of these, whose value is of the corresponding event type.
+const (
+	EventKeyTransfer        = "transfer"
+	EventKeyDealPublished   = "deal-published"
+	EventKeySectorActivated = "sector-activated"
+	EventKeyPowerUpdated    = "power-updated"
+	EventKeySectorsFaulted  = "sectors-faulted"
+	EventKeyDataCapGranted  = "datacap-granted"
+)
+
+// Emitted when an actor transfers funds on behalf of its owners or participants.
+type TransferEvent struct {
+	To     addr.Address
+	Amount abi.TokenAmount
+}
+
+// Emitted by the storage market for each deal published.
+type DealPublishedEvent struct {
+	DealID   abi.DealID
+	Client   addr.Address
+	Provider addr.Address
+}
+
+// Emitted by a storage miner for each sector activated when its proof is confirmed.
+type SectorActivatedEvent struct {
+	SectorNumber abi.SectorNumber
+	DealIDs      []abi.DealID
+}
+
+// Emitted by the power actor when a miner's claimed power changes.
+type PowerUpdatedEvent struct {
+	Miner                addr.Address
+	RawByteDelta         abi.StoragePower
+	QualityAdjustedDelta abi.StoragePower
+}
+
+// Emitted by a storage miner when sectors are declared faulty.
+type SectorsFaultedEvent struct {
+	Sectors bitfield.BitField
+}
+
+// Emitted by the verified registry when a verifier grants data cap to a client.
+type DataCapGrantedEvent struct {
+	Verifier  addr.Address
+	Client    addr.Address
+	Allowance abi.StoragePower
+}
+
+// Helpers for emitting builtin events.
+
+func EmitTransfer(rt runtime.Runtime, to addr.Address, amount abi.TokenAmount) {
+	rt.EmitEvent(runtime.EventEntry{Key: EventKeyTransfer, Value: &TransferEvent{To: to, Amount: amount}})
+}
+
+func EmitDealPublished(rt runtime.Runtime, dealID abi.DealID, client, provider addr.Address) {
+	rt.EmitEvent(runtime.EventEntry{Key: EventKeyDealPublished, Value: &DealPublishedEvent{
+		DealID:   dealID,
+		Client:   client,
+		Provider: provider,
+	}})
+}
+
+func EmitSectorActivated(rt runtime.Runtime, sectorNumber abi.SectorNumber, dealIDs []abi.DealID) {
+	rt.EmitEvent(runtime.EventEntry{Key: EventKeySectorActivated, Value: &SectorActivatedEvent{
+		SectorNumber: sectorNumber,
+		DealIDs:      dealIDs,
+	}})
+}
+
+func EmitPowerUpdated(rt runtime.Runtime, miner addr.Address, rawDelta, qaDelta abi.StoragePower) {
+	rt.EmitEvent(runtime.EventEntry{Key: EventKeyPowerUpdated, Value: &PowerUpdatedEvent{
+		Miner:                miner,
+		RawByteDelta:         rawDelta,
+		QualityAdjustedDelta: qaDelta,
+	}})
+}
+
+func EmitSectorsFaulted(rt runtime.Runtime, sectors bitfield.BitField) {
+	rt.EmitEvent(runtime.EventEntry{Key: EventKeySectorsFaulted, Value: &SectorsFaultedEvent{Sectors: sectors}})
+}
+
+func EmitDataCapGranted(rt runtime.Runtime, verifier, client addr.Address, allowance abi.StoragePower) {
+	rt.EmitEvent(runtime.EventEntry{Key: EventKeyDataCapGranted, Value: &DataCapGrantedEvent{
+		Verifier:  verifier,
+		Client:    client,
+		Allowance: allowance,
+	}})
+}
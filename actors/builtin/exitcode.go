@@ -0,0 +1,151 @@
+package builtin
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/ipfs/go-cid"
+)
+
+// Exit codes are partitioned into ranges:
+// - [Ok, FirstActorErrorCode) are reserved for the runtime and may not be used by actors;
+// - [FirstActorErrorCode, FirstActorSpecificExitCode) are common codes with the same meaning for every actor;
+// - [FirstActorSpecificExitCode, LastActorSpecificExitCode] are defined by each actor, and so are interpreted
+//   in the context of the actor that returned them.
+const LastActorSpecificExitCode = exitcode.ExitCode(1<<16 - 1)
+
+func IsSystemExitCode(code exitcode.ExitCode) bool {
+	return code >= exitcode.Ok && code < exitcode.FirstActorErrorCode
+}
+
+func IsCommonExitCode(code exitcode.ExitCode) bool {
+	return code >= exitcode.FirstActorErrorCode && code < exitcode.FirstActorSpecificExitCode
+}
+
+func IsActorSpecificExitCode(code exitcode.ExitCode) bool {
+	return code >= exitcode.FirstActorSpecificExitCode && code <= LastActorSpecificExitCode
+}
+
+var commonExitCodeNames = map[exitcode.ExitCode]string{
+	exitcode.ErrIllegalArgument:   "ErrIllegalArgument",
+	exitcode.ErrNotFound:          "ErrNotFound",
+	exitcode.ErrForbidden:         "ErrForbidden",
+	exitcode.ErrInsufficientFunds: "ErrInsufficientFunds",
+	exitcode.ErrIllegalState:      "ErrIllegalState",
+	exitcode.ErrSerialization:     "ErrSerialization",
+}
+
+// Names of actor-specific exit codes, keyed by actor code CID.
+var actorExitCodeNames = make(map[cid.Cid]map[exitcode.ExitCode]string)
+
+// Registers names for an actor's specific exit codes.
+// This is intended to be called from the actor package's initialization, and panics on a code outside
+// the actor-specific range or a code registered twice.
+func RegisterExitCodeNames(actorCode cid.Cid, names map[exitcode.ExitCode]string) {
+	registered, ok := actorExitCodeNames[actorCode]
+	if !ok {
+		registered = make(map[exitcode.ExitCode]string)
+		actorExitCodeNames[actorCode] = registered
+	}
+	for code, name := range names { //nolint:nomaprange
+		if !IsActorSpecificExitCode(code) {
+			panic(fmt.Sprintf("exit code %d for %s is not in the actor-specific range", code, name))
+		}
+		if prev, ok := registered[code]; ok {
+			panic(fmt.Sprintf("exit code %d for %s already registered as %s", code, name, prev))
+		}
+		registered[code] = name
+	}
+}
+
+// Returns a name for an exit code returned by an actor with the given code CID.
+// Codes that are not registered are named by their numeric value.
+func ExitCodeName(actorCode cid.Cid, code exitcode.ExitCode) string {
+	if IsSystemExitCode(code) {
+		return code.String()
+	}
+	if name, ok := commonExitCodeNames[code]; ok {
+		return name
+	}
+	if name, ok := actorExitCodeNames[actorCode][code]; ok {
+		return name
+	}
+	return code.String()
+}
+
+// Whether a failure with an exit code might succeed if attempted again later against a different state,
+// e.g. after the caller has acquired more funds or gas.
+func IsRetryableExitCode(code exitcode.ExitCode) bool {
+	switch code {
+	case exitcode.SysErrSenderStateInvalid, exitcode.SysErrInsufficientFunds, exitcode.SysErrOutOfGas,
+		exitcode.ErrInsufficientFunds:
+		return true
+	}
+	return false
+}
+
+// An error carrying an exit code and a hint as to whether the failing operation might be retried.
+// The exit code is recoverable with exitcode.Unwrap.
+type ExitError struct {
+	Retryable bool
+	cause     error
+}
+
+// Constructs an error with an exit code, and a retryability hint defaulting to that of the code.
+func NewExitError(code exitcode.ExitCode, msg string, args ...interface{}) *ExitError {
+	return &ExitError{
+		Retryable: IsRetryableExitCode(code),
+		cause:     code.Wrapf(msg, args...),
+	}
+}
+
+func (e *ExitError) Error() string {
+	return e.cause.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.cause
+}
+
+func (e *ExitError) Code() exitcode.ExitCode {
+	return exitcode.Unwrap(e.cause, exitcode.ErrIllegalState)
+}
+
+func IllegalArgumentf(msg string, args ...interface{}) error {
+	return NewExitError(exitcode.ErrIllegalArgument, msg, args...)
+}
+
+func NotFoundf(msg string, args ...interface{}) error {
+	return NewExitError(exitcode.ErrNotFound, msg, args...)
+}
+
+func Forbiddenf(msg string, args ...interface{}) error {
+	return NewExitError(exitcode.ErrForbidden, msg, args...)
+}
+
+func InsufficientFundsf(msg string, args ...interface{}) error {
+	return NewExitError(exitcode.ErrInsufficientFunds, msg, args...)
+}
+
+func IllegalStatef(msg string, args ...interface{}) error {
+	return NewExitError(exitcode.ErrIllegalState, msg, args...)
+}
+
+func Serializationf(msg string, args ...interface{}) error {
+	return NewExitError(exitcode.ErrSerialization, msg, args...)
+}
+
+// Whether an error indicates a failure that might succeed if retried.
+// An error without an explicit hint is retryable if its exit code is.
+func IsRetryable(err error) bool {
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Retryable
+	}
+	var code exitcode.ExitCode
+	if errors.As(err, &code) {
+		return IsRetryableExitCode(code)
+	}
+	return false
+}
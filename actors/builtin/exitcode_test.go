@@ -0,0 +1,94 @@
+package builtin_test
+
+import (
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/paych"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/power"
+)
+
+func TestExitCodeRanges(t *testing.T) {
+	assert.True(t, builtin.IsSystemExitCode(exitcode.Ok))
+	assert.True(t, builtin.IsSystemExitCode(exitcode.SysErrReserved6))
+	assert.False(t, builtin.IsSystemExitCode(exitcode.ErrIllegalArgument))
+
+	assert.True(t, builtin.IsCommonExitCode(exitcode.ErrIllegalArgument))
+	assert.True(t, builtin.IsCommonExitCode(exitcode.ErrSerialization))
+	assert.False(t, builtin.IsCommonExitCode(exitcode.FirstActorSpecificExitCode))
+
+	assert.True(t, builtin.IsActorSpecificExitCode(exitcode.FirstActorSpecificExitCode))
+	assert.True(t, builtin.IsActorSpecificExitCode(builtin.LastActorSpecificExitCode))
+	assert.False(t, builtin.IsActorSpecificExitCode(builtin.LastActorSpecificExitCode+1))
+	assert.False(t, builtin.IsActorSpecificExitCode(exitcode.ErrIllegalState))
+}
+
+func TestExitCodeName(t *testing.T) {
+	assert.Equal(t, "SysErrOutOfGas(7)", builtin.ExitCodeName(builtin.StoragePowerActorCodeID, exitcode.SysErrOutOfGas))
+	assert.Equal(t, "ErrInsufficientFunds", builtin.ExitCodeName(builtin.StoragePowerActorCodeID, exitcode.ErrInsufficientFunds))
+
+	// Actor-specific codes are named in the context of the actor.
+	assert.Equal(t, power.ErrTooManyProveCommits, paych.ErrChannelStateUpdateAfterSettled)
+	assert.Equal(t, "ErrTooManyProveCommits", builtin.ExitCodeName(builtin.StoragePowerActorCodeID, power.ErrTooManyProveCommits))
+	assert.Equal(t, "ErrChannelStateUpdateAfterSettled", builtin.ExitCodeName(builtin.PaymentChannelActorCodeID, paych.ErrChannelStateUpdateAfterSettled))
+	assert.Equal(t, "32", builtin.ExitCodeName(builtin.StorageMinerActorCodeID, exitcode.FirstActorSpecificExitCode))
+
+	t.Run("registration outside actor-specific range panics", func(t *testing.T) {
+		assert.Panics(t, func() {
+			builtin.RegisterExitCodeNames(builtin.StorageMinerActorCodeID, map[exitcode.ExitCode]string{
+				exitcode.ErrIllegalState: "ErrBad",
+			})
+		})
+	})
+
+	t.Run("duplicate registration panics", func(t *testing.T) {
+		assert.Panics(t, func() {
+			builtin.RegisterExitCodeNames(builtin.StoragePowerActorCodeID, map[exitcode.ExitCode]string{
+				power.ErrTooManyProveCommits: "ErrDuplicate",
+			})
+		})
+	})
+}
+
+func TestExitError(t *testing.T) {
+	t.Run("constructors carry code and retryability", func(t *testing.T) {
+		for _, tc := range []struct {
+			err       error
+			code      exitcode.ExitCode
+			retryable bool
+		}{
+			{builtin.IllegalArgumentf("bad %d", 1), exitcode.ErrIllegalArgument, false},
+			{builtin.NotFoundf("missing"), exitcode.ErrNotFound, false},
+			{builtin.Forbiddenf("nope"), exitcode.ErrForbidden, false},
+			{builtin.InsufficientFundsf("poor"), exitcode.ErrInsufficientFunds, true},
+			{builtin.IllegalStatef("broken"), exitcode.ErrIllegalState, false},
+			{builtin.Serializationf("garbled"), exitcode.ErrSerialization, false},
+		} {
+			assert.Equal(t, tc.code, exitcode.Unwrap(tc.err, exitcode.Ok))
+			assert.Equal(t, tc.retryable, builtin.IsRetryable(tc.err))
+		}
+		assert.Equal(t, "bad 1", builtin.IllegalArgumentf("bad %d", 1).Error())
+	})
+
+	t.Run("code and hint survive wrapping", func(t *testing.T) {
+		err := xerrors.Errorf("context: %w", builtin.InsufficientFundsf("poor"))
+		assert.Equal(t, exitcode.ErrInsufficientFunds, exitcode.Unwrap(err, exitcode.ErrIllegalState))
+		assert.True(t, builtin.IsRetryable(err))
+
+		exitErr := builtin.NewExitError(exitcode.ErrNotFound, "not yet")
+		exitErr.Retryable = true
+		assert.Equal(t, exitcode.ErrNotFound, exitErr.Code())
+		assert.True(t, builtin.IsRetryable(xerrors.Errorf("context: %w", exitErr)))
+	})
+
+	t.Run("plain exit codes fall back to code retryability", func(t *testing.T) {
+		assert.True(t, builtin.IsRetryable(exitcode.SysErrOutOfGas))
+		assert.True(t, builtin.IsRetryable(exitcode.ErrInsufficientFunds.Wrapf("poor")))
+		assert.False(t, builtin.IsRetryable(exitcode.ErrIllegalArgument.Wrapf("bad")))
+		assert.False(t, builtin.IsRetryable(xerrors.New("no code")))
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/market"
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/miner"
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/multisig"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/paramreg"
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/paych"
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/power"
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/reward"
@@ -23,6 +24,7 @@ func BuiltinActors() []runtime.VMActor {
 		market.Actor{},
 		miner.Actor{},
 		multisig.Actor{},
+		paramreg.Actor{},
 		paych.Actor{},
 		power.Actor{},
 		reward.Actor{},
@@ -14,6 +14,7 @@ import (
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/market"
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/miner"
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/multisig"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/paramreg"
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/paych"
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/power"
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/reward"
@@ -44,6 +45,7 @@ func TestKnownActors(t *testing.T) {
 		{market.Actor{}, builtin.StorageMarketActorCodeID, builtin.MethodsMarket},
 		{miner.Actor{}, builtin.StorageMinerActorCodeID, builtin.MethodsMiner},
 		{multisig.Actor{}, builtin.MultisigActorCodeID, builtin.MethodsMultisig},
+		{paramreg.Actor{}, builtin.ParameterRegistryActorCodeID, builtin.MethodsParameterRegistry},
 		{paych.Actor{}, builtin.PaymentChannelActorCodeID, builtin.MethodsPaych},
 		{power.Actor{}, builtin.StoragePowerActorCodeID, builtin.MethodsPower},
 		{reward.Actor{}, builtin.RewardActorCodeID, builtin.MethodsReward},
@@ -0,0 +1,235 @@
+package exported
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+
+	"github.com/filecoin-project/go-bitfield"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/cbor"
+	"github.com/ipfs/go-cid"
+	"golang.org/x/xerrors"
+)
+
+// JSON forms of builtin actor method params and returns, so that tools can construct and display messages without
+// encoding CBOR by hand.
+// The JSON form of a value is mostly its type's standard JSON encoding: addresses are strings such as "f01234",
+// CIDs are objects such as {"/": "bafy..."}, token amounts and other big integers are decimal strings, and bytes
+// are base64 strings. Bitfields are lists of the indices of their set bits, e.g. sector numbers, and a type wrapping
+// a CID, such as typegen.CborCid, has the CID's form. Empty params or returns are null.
+// The conversion works from the method's types by reflection, rather than by methods generated on those types,
+// since many params are aliases of types from earlier actors versions, to which methods cannot be added.
+
+// Converts a method's CBOR-encoded params to JSON.
+func MethodParamsToJSON(code cid.Cid, method abi.MethodNum, params []byte) ([]byte, error) {
+	typ, err := methodType(code, method, true)
+	if err != nil {
+		return nil, err
+	}
+	return cborToJSON(typ, params)
+}
+
+// Converts a method's params from JSON to their CBOR encoding, as sent in a message.
+func MethodParamsFromJSON(code cid.Cid, method abi.MethodNum, params []byte) ([]byte, error) {
+	typ, err := methodType(code, method, true)
+	if err != nil {
+		return nil, err
+	}
+	return jsonToCBOR(typ, params)
+}
+
+// Converts a method's CBOR-encoded return value to JSON.
+func MethodReturnToJSON(code cid.Cid, method abi.MethodNum, ret []byte) ([]byte, error) {
+	typ, err := methodType(code, method, false)
+	if err != nil {
+		return nil, err
+	}
+	return cborToJSON(typ, ret)
+}
+
+// Converts a method's return value from JSON to its CBOR encoding.
+func MethodReturnFromJSON(code cid.Cid, method abi.MethodNum, ret []byte) ([]byte, error) {
+	typ, err := methodType(code, method, false)
+	if err != nil {
+		return nil, err
+	}
+	return jsonToCBOR(typ, ret)
+}
+
+// Maximum number of set bits in a bitfield converted to JSON, bounding the size of the list of their indices.
+const maxJSONBitFieldCount = 1 << 20
+
+var (
+	emptyValueType = reflect.TypeOf(abi.EmptyValue{})
+	cidType        = reflect.TypeOf(cid.Cid{})
+	bitfieldType   = reflect.TypeOf(bitfield.BitField{})
+	bitIndicesType = reflect.TypeOf([]uint64{})
+	nullJSON       = []byte("null")
+)
+
+// Returns the type of a builtin actor method's params or return, dereferenced.
+func methodType(code cid.Cid, method abi.MethodNum, params bool) (reflect.Type, error) {
+	if MethodsByCode(code) == nil {
+		return nil, xerrors.Errorf("no builtin actor with code %v", code)
+	}
+	m, ok := MethodByCode(code, method)
+	if !ok {
+		return nil, xerrors.Errorf("no method %d for actor code %v", method, code)
+	}
+	if params {
+		return m.Params, nil
+	}
+	return m.Return, nil
+}
+
+func cborToJSON(typ reflect.Type, b []byte) ([]byte, error) {
+	if typ == emptyValueType {
+		if len(b) != 0 {
+			return nil, xerrors.Errorf("expected empty value, got %d bytes", len(b))
+		}
+		return nullJSON, nil
+	}
+	val := reflect.New(typ)
+	if err := val.Interface().(cbor.Unmarshaler).UnmarshalCBOR(bytes.NewReader(b)); err != nil {
+		return nil, xerrors.Errorf("failed to decode %v: %w", typ, err)
+	}
+	out := reflect.New(jsonType(typ))
+	if err := convertJSON(out.Elem(), val.Elem()); err != nil {
+		return nil, xerrors.Errorf("failed to convert %v to JSON: %w", typ, err)
+	}
+	return json.Marshal(out.Interface())
+}
+
+func jsonToCBOR(typ reflect.Type, b []byte) ([]byte, error) {
+	if typ == emptyValueType {
+		if !bytes.Equal(bytes.TrimSpace(b), nullJSON) {
+			return nil, xerrors.Errorf("expected null for empty value, got %s", b)
+		}
+		return nil, nil
+	}
+	in := reflect.New(jsonType(typ))
+	if err := json.Unmarshal(b, in.Interface()); err != nil {
+		return nil, xerrors.Errorf("failed to decode %v from JSON: %w", typ, err)
+	}
+	val := reflect.New(typ)
+	if err := convertJSON(val.Elem(), in.Elem()); err != nil {
+		return nil, xerrors.Errorf("failed to convert %v from JSON: %w", typ, err)
+	}
+	var buf bytes.Buffer
+	if err := val.Interface().(cbor.Marshaler).MarshalCBOR(&buf); err != nil {
+		return nil, xerrors.Errorf("failed to encode %v: %w", typ, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Returns the type whose standard JSON encoding is the JSON form of a type. This is the type itself, unless it
+// contains bitfields or CID wrappers, in which case it is a mirror of the type with those replaced by lists of
+// bit indices and CIDs.
+// Structs with unexported fields cannot be mirrored, so are left as they are.
+func jsonType(typ reflect.Type) reflect.Type {
+	if typ == bitfieldType {
+		return bitIndicesType
+	}
+	if typ != cidType && typ.ConvertibleTo(cidType) {
+		return cidType
+	}
+	switch typ.Kind() {
+	case reflect.Ptr:
+		if elem := jsonType(typ.Elem()); elem != typ.Elem() {
+			return reflect.PtrTo(elem)
+		}
+	case reflect.Slice:
+		if elem := jsonType(typ.Elem()); elem != typ.Elem() {
+			return reflect.SliceOf(elem)
+		}
+	case reflect.Array:
+		if elem := jsonType(typ.Elem()); elem != typ.Elem() {
+			return reflect.ArrayOf(typ.Len(), elem)
+		}
+	case reflect.Map:
+		if elem := jsonType(typ.Elem()); elem != typ.Elem() {
+			return reflect.MapOf(typ.Key(), elem)
+		}
+	case reflect.Struct:
+		fields := make([]reflect.StructField, typ.NumField())
+		mirrored := false
+		for i := range fields {
+			f := typ.Field(i)
+			if f.PkgPath != "" {
+				return typ
+			}
+			fields[i] = reflect.StructField{Name: f.Name, Type: jsonType(f.Type), Tag: f.Tag}
+			mirrored = mirrored || fields[i].Type != f.Type
+		}
+		if mirrored {
+			return reflect.StructOf(fields)
+		}
+	}
+	return typ
+}
+
+// Sets a value from another of the same shape, where one's type is the JSON type of the other's.
+func convertJSON(dst, src reflect.Value) error {
+	switch {
+	case src.Type() == dst.Type():
+		dst.Set(src)
+		return nil
+	case src.Type() == bitfieldType:
+		indices, err := src.Interface().(bitfield.BitField).All(maxJSONBitFieldCount)
+		if err != nil {
+			return err
+		}
+		dst.Set(reflect.ValueOf(append([]uint64{}, indices...)))
+		return nil
+	case dst.Type() == bitfieldType:
+		dst.Set(reflect.ValueOf(bitfield.NewFromSet(src.Interface().([]uint64))))
+		return nil
+	case src.Type() == cidType || dst.Type() == cidType:
+		dst.Set(src.Convert(dst.Type()))
+		return nil
+	}
+
+	switch src.Kind() {
+	case reflect.Ptr:
+		if src.IsNil() {
+			return nil
+		}
+		dst.Set(reflect.New(dst.Type().Elem()))
+		return convertJSON(dst.Elem(), src.Elem())
+	case reflect.Slice:
+		if src.IsNil() {
+			return nil
+		}
+		dst.Set(reflect.MakeSlice(dst.Type(), src.Len(), src.Len()))
+		fallthrough
+	case reflect.Array:
+		for i := 0; i < src.Len(); i++ {
+			if err := convertJSON(dst.Index(i), src.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if src.IsNil() {
+			return nil
+		}
+		dst.Set(reflect.MakeMapWithSize(dst.Type(), src.Len()))
+		iter := src.MapRange()
+		for iter.Next() {
+			elem := reflect.New(dst.Type().Elem()).Elem()
+			if err := convertJSON(elem, iter.Value()); err != nil {
+				return err
+			}
+			dst.SetMapIndex(iter.Key(), elem)
+		}
+	case reflect.Struct:
+		for i := 0; i < src.NumField(); i++ {
+			if err := convertJSON(dst.Field(i), src.Field(i)); err != nil {
+				return err
+			}
+		}
+	default:
+		return xerrors.Errorf("cannot convert %v to %v", src.Type(), dst.Type())
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package exported
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/filecoin-project/go-bitfield"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/cbor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/miner"
+	tutil "github.com/filecoin-project/specs-actors/v2/support/testing"
+)
+
+func TestMethodJSON(t *testing.T) {
+	t.Run("every method's params and return round trip", func(t *testing.T) {
+		for _, actor := range BuiltinActors() {
+			for i, method := range actor.Exports() {
+				if method == nil {
+					continue
+				}
+				methodNum := abi.MethodNum(i)
+				methodType := reflect.TypeOf(method)
+				encoded := encodeDeterministic(t, methodType.In(1).Elem())
+				asJSON, err := MethodParamsToJSON(actor.Code(), methodNum, encoded)
+				require.NoError(t, err, "%v method %d params", builtin.ActorNameByCode(actor.Code()), i)
+				decoded, err := MethodParamsFromJSON(actor.Code(), methodNum, asJSON)
+				require.NoError(t, err, "%v method %d params: %s", builtin.ActorNameByCode(actor.Code()), i, asJSON)
+				assert.Equal(t, encoded, decoded, "%v method %d params: %s", builtin.ActorNameByCode(actor.Code()), i, asJSON)
+
+				encoded = encodeDeterministic(t, methodType.Out(0).Elem())
+				asJSON, err = MethodReturnToJSON(actor.Code(), methodNum, encoded)
+				require.NoError(t, err, "%v method %d return", builtin.ActorNameByCode(actor.Code()), i)
+				decoded, err = MethodReturnFromJSON(actor.Code(), methodNum, asJSON)
+				require.NoError(t, err, "%v method %d return: %s", builtin.ActorNameByCode(actor.Code()), i, asJSON)
+				assert.Equal(t, encoded, decoded, "%v method %d return: %s", builtin.ActorNameByCode(actor.Code()), i, asJSON)
+			}
+		}
+	})
+
+	t.Run("readable forms", func(t *testing.T) {
+		recipient := tutil.NewIDAddr(t, 1234)
+		params := miner.WithdrawBalanceParams{AmountRequested: big.NewInt(5e18), Recipient: &recipient}
+		var buf bytes.Buffer
+		require.NoError(t, params.MarshalCBOR(&buf))
+		asJSON, err := MethodParamsToJSON(builtin.StorageMinerActorCodeID, builtin.MethodsMiner.WithdrawBalance, buf.Bytes())
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"AmountRequested": "5000000000000000000", "Recipient": "`+recipient.String()+`"}`, string(asJSON))
+
+		sectors := miner.CompactSectorNumbersParams{MaskSectorNumbers: bitfield.NewFromSet([]uint64{3, 4, 10})}
+		buf.Reset()
+		require.NoError(t, sectors.MarshalCBOR(&buf))
+		asJSON, err = MethodParamsToJSON(builtin.StorageMinerActorCodeID, builtin.MethodsMiner.CompactSectorNumbers, buf.Bytes())
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"MaskSectorNumbers": [3, 4, 10]}`, string(asJSON))
+
+		commD := tutil.MakeCID("commd", nil)
+		asJSON, err = json.Marshal(map[string]string{"/": commD.String()})
+		require.NoError(t, err)
+		encoded, err := MethodReturnFromJSON(builtin.StorageMarketActorCodeID, builtin.MethodsMarket.ComputeDataCommitment, asJSON)
+		require.NoError(t, err)
+		roundTrip, err := MethodReturnToJSON(builtin.StorageMarketActorCodeID, builtin.MethodsMarket.ComputeDataCommitment, encoded)
+		require.NoError(t, err)
+		assert.JSONEq(t, string(asJSON), string(roundTrip))
+
+		asJSON, err = MethodParamsToJSON(builtin.AccountActorCodeID, builtin.MethodsAccount.PubkeyAddress, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "null", string(asJSON))
+	})
+
+	t.Run("rejects unknown methods", func(t *testing.T) {
+		_, err := MethodParamsToJSON(builtin.AccountActorCodeID, 100, nil)
+		assert.Error(t, err)
+		_, err = MethodParamsToJSON(tutil.MakeCID("unknown", nil), builtin.MethodSend, nil)
+		assert.Error(t, err)
+	})
+}
+
+// Returns the CBOR encoding of a deterministically filled value of a type.
+func encodeDeterministic(t *testing.T, typ reflect.Type) []byte {
+	if typ == emptyValueType {
+		return nil
+	}
+	val := reflect.New(typ)
+	fillDeterministic(val.Elem(), new(uint64), 0)
+	var buf bytes.Buffer
+	require.NoError(t, val.Interface().(cbor.Marshaler).MarshalCBOR(&buf))
+	return buf.Bytes()
+}
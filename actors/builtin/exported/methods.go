@@ -0,0 +1,58 @@
+package exported
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/ipfs/go-cid"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+)
+
+// Metadata about a method exported by a builtin actor.
+// The table of methods is generated from the actors' exports by ./gen.
+type MethodMeta struct {
+	Num    abi.MethodNum
+	Name   string
+	Params reflect.Type // The type to which the method's params decode, dereferenced.
+	Return reflect.Type // The type of the method's return value, dereferenced.
+}
+
+// Returns the methods exported by the builtin actor with a code, ordered by number,
+// or nil if the code is not that of a builtin actor.
+func MethodsByCode(code cid.Cid) []MethodMeta {
+	return builtinMethods[builtin.ActorNameByCode(code)]
+}
+
+// Returns the metadata of a builtin actor's method, and whether the actor exports it.
+func MethodByCode(code cid.Cid, method abi.MethodNum) (MethodMeta, bool) {
+	for _, m := range MethodsByCode(code) {
+		if m.Num == method {
+			return m, true
+		}
+	}
+	return MethodMeta{}, false
+}
+
+// Returns the metadata of a builtin actor's method by name, and whether the actor exports it.
+func MethodByName(code cid.Cid, name string) (MethodMeta, bool) {
+	for _, m := range MethodsByCode(code) {
+		if m.Name == name {
+			return m, true
+		}
+	}
+	return MethodMeta{}, false
+}
+
+// Returns a human-readable name for a method, such as "PreCommitSector".
+// The name of a method not exported by a builtin actor is its number.
+func MethodName(code cid.Cid, method abi.MethodNum) string {
+	if method == builtin.MethodSend {
+		return "Send"
+	}
+	if m, ok := MethodByCode(code, method); ok {
+		return m.Name
+	}
+	return fmt.Sprintf("%d", method)
+}
@@ -0,0 +1,61 @@
+package exported
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/miner"
+)
+
+func TestMethodTableMatchesExports(t *testing.T) {
+	// A mismatch means the table is stale: run `make gen`.
+	for _, actor := range BuiltinActors() {
+		methods := MethodsByCode(actor.Code())
+		require.NotNil(t, methods, builtin.ActorNameByCode(actor.Code()))
+		var expected []abi.MethodNum
+		for num, m := range actor.Exports() {
+			if m == nil {
+				continue
+			}
+			expected = append(expected, abi.MethodNum(num))
+			meta, ok := MethodByCode(actor.Code(), abi.MethodNum(num))
+			require.True(t, ok)
+			typ := reflect.TypeOf(m)
+			assert.Equal(t, typ.In(1).Elem(), meta.Params, meta.Name)
+			assert.Equal(t, typ.Out(0).Elem(), meta.Return, meta.Name)
+		}
+		var actual []abi.MethodNum
+		for _, m := range methods {
+			actual = append(actual, m.Num)
+		}
+		assert.Equal(t, expected, actual, builtin.ActorNameByCode(actor.Code()))
+	}
+}
+
+func TestMethodLookup(t *testing.T) {
+	code := builtin.StorageMinerActorCodeID
+	m, ok := MethodByCode(code, builtin.MethodsMiner.PreCommitSector)
+	require.True(t, ok)
+	assert.Equal(t, "PreCommitSector", m.Name)
+	assert.Equal(t, reflect.TypeOf(miner.PreCommitSectorParams{}), m.Params)
+
+	m, ok = MethodByName(code, "ProveCommitSector")
+	require.True(t, ok)
+	assert.Equal(t, builtin.MethodsMiner.ProveCommitSector, m.Num)
+	_, ok = MethodByName(code, "NoSuchMethod")
+	assert.False(t, ok)
+
+	assert.Equal(t, "Send", MethodName(code, builtin.MethodSend))
+	assert.Equal(t, "SubmitWindowedPoSt", MethodName(code, builtin.MethodsMiner.SubmitWindowedPoSt))
+	assert.Equal(t, "1000", MethodName(code, 1000))
+	// The deprecated power method is not exported.
+	assert.Equal(t, "7", MethodName(builtin.StoragePowerActorCodeID, builtin.MethodsPower.Deprecated1))
+	assert.Equal(t, "2", MethodName(cid.Undef, 2))
+	assert.Nil(t, MethodsByCode(cid.Undef))
+}
@@ -0,0 +1,166 @@
+package exported
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-bitfield"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/cbor"
+	"github.com/filecoin-project/go-state-types/crypto"
+	"github.com/stretchr/testify/require"
+	"github.com/xorcare/golden"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	tutil "github.com/filecoin-project/specs-actors/v2/support/testing"
+)
+
+// Checks that the serialization of every builtin actor's method params and returns, and state, is unchanged.
+// Each type is populated with deterministic content, serialized, and compared byte-for-byte with the golden file,
+// which other implementations may also use as test vectors. The golden bytes must also decode and re-encode
+// to themselves.
+// After an intentional change to serialization, regenerate the golden file with -update.
+func TestSerializationGolden(t *testing.T) {
+	types := map[string]reflect.Type{}
+	add := func(typ reflect.Type) {
+		if typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		if typ == emptyValueType {
+			return
+		}
+		types[typ.String()] = typ
+	}
+	for _, actor := range BuiltinActors() {
+		add(reflect.TypeOf(actor.State()))
+		for _, method := range actor.Exports() {
+			if method == nil {
+				continue
+			}
+			methodType := reflect.TypeOf(method)
+			add(methodType.In(1))
+			add(methodType.Out(0))
+		}
+	}
+
+	names := make([]string, 0, len(types))
+	for name := range types { //nolint:nomaprange
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var out strings.Builder
+	for _, name := range names {
+		val := reflect.New(types[name])
+		fillDeterministic(val.Elem(), new(uint64), 0)
+		marshaler, ok := val.Interface().(cbor.Marshaler)
+		require.True(t, ok, "%s is not a CBOR marshaler", name)
+		buf := new(bytes.Buffer)
+		require.NoError(t, marshaler.MarshalCBOR(buf), "failed to marshal %s", name)
+
+		// The encoding must be canonical: decoding and re-encoding reproduces it.
+		decoded := reflect.New(types[name]).Interface().(cbor.Er)
+		require.NoError(t, decoded.UnmarshalCBOR(bytes.NewReader(buf.Bytes())), "failed to unmarshal %s", name)
+		reencoded := new(bytes.Buffer)
+		require.NoError(t, decoded.MarshalCBOR(reencoded), "failed to re-marshal %s", name)
+		require.Equal(t, buf.Bytes(), reencoded.Bytes(), "%s does not round trip", name)
+
+		if bounded, ok := val.Interface().(builtin.BoundedEncoding); ok {
+			require.LessOrEqual(t, int64(buf.Len()), bounded.MaxEncodedLength(), "%s exceeds its maximum length", name)
+		}
+
+		_, _ = fmt.Fprintf(&out, "%s %s\n", name, hex.EncodeToString(buf.Bytes()))
+	}
+	golden.Assert(t, []byte(out.String()))
+}
+
+var (
+	addressType = reflect.TypeOf(address.Address{})
+	bigIntType  = reflect.TypeOf(big.Int{})
+	sigTypeType = reflect.TypeOf(crypto.SigType(0))
+)
+
+// Fills a value with content derived only from its shape and a counter incremented for each leaf value, so that
+// every field of a type holds a distinct value, and the same type is always filled the same way.
+// Collections have two elements, and nesting is limited to keep recursive types finite.
+func fillDeterministic(v reflect.Value, seq *uint64, depth int) {
+	next := func() uint64 {
+		*seq++
+		return *seq
+	}
+	if v.Type() != cidType && v.Type().ConvertibleTo(cidType) {
+		// A CID wrapper such as typegen.CborCid.
+		v.Set(reflect.ValueOf(tutil.MakeCID(fmt.Sprint(next()), nil)).Convert(v.Type()))
+		return
+	}
+	switch v.Type() {
+	case addressType:
+		addr, _ := address.NewIDAddress(100 + next())
+		v.Set(reflect.ValueOf(addr))
+		return
+	case cidType:
+		v.Set(reflect.ValueOf(tutil.MakeCID(fmt.Sprint(next()), nil)))
+		return
+	case bigIntType:
+		v.Set(reflect.ValueOf(big.Mul(big.NewIntUnsigned(next()), big.NewInt(1e18))))
+		return
+	case bitfieldType:
+		n := next()
+		v.Set(reflect.ValueOf(bitfield.NewFromSet([]uint64{n, n + 2})))
+		return
+	case sigTypeType:
+		// Decoding rejects unknown signature types.
+		v.Set(reflect.ValueOf(crypto.SigTypeBLS))
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(next()%2 == 1)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(int64(next()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(next())
+	case reflect.String:
+		v.SetString(fmt.Sprint("s", next()))
+	case reflect.Ptr:
+		if depth < 4 {
+			v.Set(reflect.New(v.Type().Elem()))
+			fillDeterministic(v.Elem(), seq, depth+1)
+		}
+	case reflect.Slice:
+		if depth < 4 {
+			v.Set(reflect.MakeSlice(v.Type(), 2, 2))
+			for i := 0; i < v.Len(); i++ {
+				fillDeterministic(v.Index(i), seq, depth+1)
+			}
+		}
+	case reflect.Map:
+		if depth < 4 {
+			v.Set(reflect.MakeMap(v.Type()))
+			for i := 0; i < 2; i++ {
+				key := reflect.New(v.Type().Key()).Elem()
+				fillDeterministic(key, seq, depth+1)
+				elem := reflect.New(v.Type().Elem()).Elem()
+				fillDeterministic(elem, seq, depth+1)
+				v.SetMapIndex(key, elem)
+			}
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			fillDeterministic(v.Index(i), seq, depth+1)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Field(i).CanSet() {
+				fillDeterministic(v.Field(i), seq, depth+1)
+			}
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package builtin
+
+import (
+	"bytes"
+
+	addr "github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-bitfield"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/cbor"
+	"github.com/filecoin-project/go-state-types/exitcode"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/runtime"
+)
+
+// Data describing a failure attributable to some of the sectors addressed by a method, with which an actor aborts
+// (see runtime.Runtime.AbortWithData) so that callers can tell which sectors to drop or fix before retrying.
+type SectorsFailure struct {
+	Sectors bitfield.BitField
+}
+
+func NewSectorsFailure(sectors ...abi.SectorNumber) *SectorsFailure {
+	nums := make([]uint64, len(sectors))
+	for i, s := range sectors {
+		nums[i] = uint64(s)
+	}
+	return &SectorsFailure{Sectors: bitfield.NewFromSet(nums)}
+}
+
+// Data describing a failure attributable to some of the deals addressed by a method, as for SectorsFailure.
+type DealsFailure struct {
+	DealIDs []abi.DealID
+}
+
+func NewDealsFailure(dealIDs ...abi.DealID) *DealsFailure {
+	return &DealsFailure{DealIDs: dealIDs}
+}
+
+// Sends a message, unmarshalling its return value into out if it succeeds, or the data describing its failure
+// into failure if the callee aborted with data. A nil out discards the return value.
+// Returns the exit code, and whether failure data was received. Failure data which does not decode into
+// failure is ignored, as it is for diagnosis and the exit code alone determines the outcome.
+func SendWithFailureData(rt runtime.Runtime, to addr.Address, method abi.MethodNum, params cbor.Marshaler, value abi.TokenAmount, out cbor.Unmarshaler, failure cbor.Unmarshaler) (exitcode.ExitCode, bool) {
+	var ret CBORBytes
+	code := rt.Send(to, method, params, value, &ret)
+	if code.IsSuccess() {
+		if out != nil {
+			err := out.UnmarshalCBOR(bytes.NewReader(ret))
+			RequireNoErr(rt, err, exitcode.ErrSerialization, "failed to unmarshal return value of method %d to %v", method, to)
+		}
+		return code, false
+	}
+	if len(ret) == 0 {
+		return code, false
+	}
+	return code, failure.UnmarshalCBOR(bytes.NewReader(ret)) == nil
+}
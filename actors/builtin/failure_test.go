@@ -0,0 +1,72 @@
+package builtin_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	cbg "github.com/whyrusleeping/cbor-gen"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v2/actors/runtime"
+	"github.com/filecoin-project/specs-actors/v2/support/mock"
+	tutil "github.com/filecoin-project/specs-actors/v2/support/testing"
+)
+
+func TestSendWithFailureData(t *testing.T) {
+	receiver := tutil.NewIDAddr(t, 100)
+	target := tutil.NewIDAddr(t, 101)
+	builder := mock.NewBuilder(context.Background(), receiver)
+	method := abi.MethodNum(7)
+
+	var got cbg.CborInt
+	var failed builtin.DealsFailure
+	var code exitcode.ExitCode
+	var hasFailed bool
+	send := func(rt runtime.Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
+		got, failed = 0, builtin.DealsFailure{}
+		code, hasFailed = builtin.SendWithFailureData(rt, target, method, nil, big.Zero(), &got, &failed)
+		return nil
+	}
+
+	t.Run("unmarshals return value on success", func(t *testing.T) {
+		rt := builder.Build(t)
+		ret := cbg.CborInt(42)
+		rt.ExpectSend(target, method, nil, big.Zero(), &ret, exitcode.Ok)
+		rt.Call(send, nil)
+		rt.Verify()
+		assert.Equal(t, exitcode.Ok, code)
+		assert.False(t, hasFailed)
+		assert.Equal(t, ret, got)
+	})
+
+	t.Run("unmarshals failure data", func(t *testing.T) {
+		rt := builder.Build(t)
+		rt.ExpectSend(target, method, nil, big.Zero(), builtin.NewDealsFailure(3, 5), exitcode.ErrIllegalArgument)
+		rt.Call(send, nil)
+		rt.Verify()
+		assert.Equal(t, exitcode.ErrIllegalArgument, code)
+		assert.True(t, hasFailed)
+		assert.Equal(t, []abi.DealID{3, 5}, failed.DealIDs)
+	})
+
+	t.Run("reports failure without data", func(t *testing.T) {
+		rt := builder.Build(t)
+		rt.ExpectSend(target, method, nil, big.Zero(), nil, exitcode.ErrForbidden)
+		rt.Call(send, nil)
+		rt.Verify()
+		assert.Equal(t, exitcode.ErrForbidden, code)
+		assert.False(t, hasFailed)
+	})
+}
+
+func TestSectorsFailure(t *testing.T) {
+	failure := builtin.NewSectorsFailure(9, 2, 5)
+	sectors, err := failure.Sectors.All(10)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{2, 5, 9}, sectors)
+}
@@ -0,0 +1,78 @@
+package builtin
+
+import (
+	"fmt"
+	stdbig "math/big"
+	"strings"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+)
+
+// Human-readable forms of token amounts and byte quantities, for state dumps, test failure messages and tools.
+// Token amounts and other big integers marshal to and from JSON as decimal strings of their integer value.
+
+// Formats a token amount in FIL, with as many fractional digits as it has, e.g. "12.5 FIL".
+func FormatFIL(amount abi.TokenAmount) string {
+	if amount.Int == nil {
+		return "<nil>"
+	}
+	sign := ""
+	if amount.Sign() < 0 {
+		sign = "-"
+	}
+	whole, frac := big.Div(amount.Abs(), TokenPrecision), big.Mod(amount.Abs(), TokenPrecision)
+	if frac.IsZero() {
+		return fmt.Sprintf("%s%s FIL", sign, whole)
+	}
+	digits := frac.String()
+	digits = strings.Repeat("0", 18-len(digits)) + digits
+	fracDigits := strings.TrimRight(digits, "0")
+	return fmt.Sprintf("%s%s.%s FIL", sign, whole, fracDigits)
+}
+
+// Parses an amount of FIL in decimal, with or without a trailing " FIL", e.g. "12.5 FIL" or "0.001".
+// The amount must be a whole number of token units.
+func ParseFIL(s string) (abi.TokenAmount, error) {
+	str := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), "FIL"))
+	digits := strings.TrimPrefix(str, "-")
+	if digits == "" || strings.Trim(digits, "0123456789.") != "" || strings.Count(digits, ".") > 1 {
+		return big.Int{}, fmt.Errorf("invalid FIL amount %q", s)
+	}
+	r, ok := new(stdbig.Rat).SetString(str)
+	if !ok {
+		return big.Int{}, fmt.Errorf("invalid FIL amount %q", s)
+	}
+	r.Mul(r, new(stdbig.Rat).SetInt(TokenPrecision.Int))
+	if !r.IsInt() {
+		return big.Int{}, fmt.Errorf("FIL amount %q is not a whole number of token units", s)
+	}
+	return big.NewFromGo(r.Num()), nil
+}
+
+var byteUnits = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB", "ZiB", "YiB"}
+
+// Formats a number of bytes, such as storage power, in the largest binary unit in which it is at least one,
+// truncated to one fractional digit, e.g. "3.2 EiB". A whole number of units has no fractional digit.
+func FormatBytes(n big.Int) string {
+	if n.Int == nil {
+		return "<nil>"
+	}
+	sign := ""
+	if n.Sign() < 0 {
+		sign = "-"
+	}
+	abs := n.Abs()
+	unit := 0
+	scale := big.NewInt(1)
+	for unit < len(byteUnits)-1 && big.Mul(scale, big.NewInt(1024)).LessThanEqual(abs) {
+		scale = big.Mul(scale, big.NewInt(1024))
+		unit++
+	}
+	tenths := big.Div(big.Mul(abs, big.NewInt(10)), scale)
+	whole, frac := big.Div(tenths, big.NewInt(10)), big.Mod(tenths, big.NewInt(10))
+	if frac.IsZero() {
+		return fmt.Sprintf("%s%s %s", sign, whole, byteUnits[unit])
+	}
+	return fmt.Sprintf("%s%s.%s %s", sign, whole, frac, byteUnits[unit])
+}
@@ -0,0 +1,44 @@
+package builtin_test
+
+import (
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+)
+
+func TestFormatFIL(t *testing.T) {
+	fil := func(s string) abi.TokenAmount {
+		amount, err := builtin.ParseFIL(s)
+		require.NoError(t, err)
+		return amount
+	}
+	assert.Equal(t, "0 FIL", builtin.FormatFIL(big.Zero()))
+	assert.Equal(t, "12 FIL", builtin.FormatFIL(big.Mul(big.NewInt(12), builtin.TokenPrecision)))
+	assert.Equal(t, "12.5 FIL", builtin.FormatFIL(fil("12.5")))
+	assert.Equal(t, "0.000000000000000001 FIL", builtin.FormatFIL(big.NewInt(1)))
+	assert.Equal(t, "-0.25 FIL", builtin.FormatFIL(fil("-0.25 FIL")))
+	assert.Equal(t, "2000000000 FIL", builtin.FormatFIL(builtin.TotalFilecoin))
+
+	assert.Equal(t, big.NewInt(1), fil("0.000000000000000001"))
+	assert.Equal(t, big.Div(builtin.TokenPrecision, big.NewInt(10_000)), fil(" .0001 FIL"))
+	for _, invalid := range []string{"", "FIL", "1/2", "1e3", "0x10", "1.2.3", "0.0000000000000000001"} {
+		_, err := builtin.ParseFIL(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	assert.Equal(t, "0 B", builtin.FormatBytes(big.Zero()))
+	assert.Equal(t, "1023 B", builtin.FormatBytes(big.NewInt(1023)))
+	assert.Equal(t, "1 KiB", builtin.FormatBytes(big.NewInt(1024)))
+	assert.Equal(t, "1.4 KiB", builtin.FormatBytes(big.NewInt(1500)))
+	assert.Equal(t, "32 GiB", builtin.FormatBytes(big.NewInt(int64(abi.SectorSize(32<<30)))))
+	assert.Equal(t, "3.2 EiB", builtin.FormatBytes(big.NewInt(13<<58)))
+	assert.Equal(t, "-2.5 TiB", builtin.FormatBytes(big.NewInt(-5<<39)))
+	assert.Equal(t, "2048 YiB", builtin.FormatBytes(big.Lsh(big.NewInt(1), 91)))
+}
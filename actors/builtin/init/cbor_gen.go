@@ -7,13 +7,15 @@ import (
 	"io"
 
 	abi "github.com/filecoin-project/go-state-types/abi"
+	acbor "github.com/filecoin-project/specs-actors/v2/actors/util/cbor"
+	cid "github.com/ipfs/go-cid"
 	cbg "github.com/whyrusleeping/cbor-gen"
 	xerrors "golang.org/x/xerrors"
 )
 
 var _ = xerrors.Errorf
 
-var lengthBufState = []byte{131}
+var lengthBufState = []byte{135}
 
 func (t *State) MarshalCBOR(w io.Writer) error {
 	if t == nil {
@@ -49,6 +51,46 @@ func (t *State) MarshalCBOR(w io.Writer) error {
 	if _, err := io.WriteString(w, string(t.NetworkName)); err != nil {
 		return err
 	}
+
+	// t.RobustAddressMap (cid.Cid) (struct)
+
+	if err := cbg.WriteCidBuf(scratch, w, t.RobustAddressMap); err != nil {
+		return xerrors.Errorf("failed to write cid field t.RobustAddressMap: %w", err)
+	}
+
+	// t.Tombstones (cid.Cid) (struct)
+
+	if err := cbg.WriteCidBuf(scratch, w, t.Tombstones); err != nil {
+		return xerrors.Errorf("failed to write cid field t.Tombstones: %w", err)
+	}
+
+	// t.ExecAllowlist ([]init.ExecPermission) (slice)
+	if len(t.ExecAllowlist) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.ExecAllowlist was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.ExecAllowlist))); err != nil {
+		return err
+	}
+	for _, v := range t.ExecAllowlist {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+
+	// t.UpgradeApprovals ([]init.UpgradeApproval) (slice)
+	if len(t.UpgradeApprovals) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.UpgradeApprovals was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.UpgradeApprovals))); err != nil {
+		return err
+	}
+	for _, v := range t.UpgradeApprovals {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -60,14 +102,14 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "init.State", "")
 	}
 	if maj != cbg.MajArray {
-		return fmt.Errorf("cbor input should be of type array")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "init.State", "")
 	}
 
-	if extra != 3 {
-		return fmt.Errorf("cbor input had wrong number of fields")
+	if extra != 7 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "init.State", "")
 	}
 
 	// t.AddressMap (cid.Cid) (struct)
@@ -76,7 +118,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 		c, err := cbg.ReadCid(br)
 		if err != nil {
-			return xerrors.Errorf("failed to read cid field t.AddressMap: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read cid field t.AddressMap: %w", err), "init.State", "AddressMap")
 		}
 
 		t.AddressMap = c
@@ -88,10 +130,10 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "init.State", "NextID")
 		}
 		if maj != cbg.MajUnsignedInt {
-			return fmt.Errorf("wrong type for uint64 field")
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "init.State", "NextID")
 		}
 		t.NextID = abi.ActorID(extra)
 
@@ -101,10 +143,811 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 	{
 		sval, err := cbg.ReadStringBuf(br, scratch)
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "init.State", "NetworkName")
 		}
 
 		t.NetworkName = string(sval)
 	}
+	// t.RobustAddressMap (cid.Cid) (struct)
+
+	{
+
+		c, err := cbg.ReadCid(br)
+		if err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read cid field t.RobustAddressMap: %w", err), "init.State", "RobustAddressMap")
+		}
+
+		t.RobustAddressMap = c
+
+	}
+	// t.Tombstones (cid.Cid) (struct)
+
+	{
+
+		c, err := cbg.ReadCid(br)
+		if err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read cid field t.Tombstones: %w", err), "init.State", "Tombstones")
+		}
+
+		t.Tombstones = c
+
+	}
+	// t.ExecAllowlist ([]init.ExecPermission) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "init.State", "ExecAllowlist")
+	}
+
+	if extra > cbg.MaxLength {
+		return acbor.WrapDecodeError(fmt.Errorf("t.ExecAllowlist: array too large (%d)", extra), "init.State", "ExecAllowlist")
+	}
+
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("expected cbor array"), "init.State", "ExecAllowlist")
+	}
+
+	if extra > 0 {
+		t.ExecAllowlist = make([]ExecPermission, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v ExecPermission
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(err, "init.State", fmt.Sprintf("ExecAllowlist[%d]", i))
+		}
+
+		t.ExecAllowlist[i] = v
+	}
+
+	// t.UpgradeApprovals ([]init.UpgradeApproval) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "init.State", "UpgradeApprovals")
+	}
+
+	if extra > cbg.MaxLength {
+		return acbor.WrapDecodeError(fmt.Errorf("t.UpgradeApprovals: array too large (%d)", extra), "init.State", "UpgradeApprovals")
+	}
+
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("expected cbor array"), "init.State", "UpgradeApprovals")
+	}
+
+	if extra > 0 {
+		t.UpgradeApprovals = make([]UpgradeApproval, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v UpgradeApproval
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(err, "init.State", fmt.Sprintf("UpgradeApprovals[%d]", i))
+		}
+
+		t.UpgradeApprovals[i] = v
+	}
+
+	return nil
+}
+
+var lengthBufExec4Params = []byte{131}
+
+func (t *Exec4Params) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufExec4Params); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.CodeCID (cid.Cid) (struct)
+
+	if err := cbg.WriteCidBuf(scratch, w, t.CodeCID); err != nil {
+		return xerrors.Errorf("failed to write cid field t.CodeCID: %w", err)
+	}
+
+	// t.ConstructorParams ([]uint8) (slice)
+	if len(t.ConstructorParams) > cbg.ByteArrayMaxLen {
+		return xerrors.Errorf("Byte array in field t.ConstructorParams was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajByteString, uint64(len(t.ConstructorParams))); err != nil {
+		return err
+	}
+
+	if _, err := w.Write(t.ConstructorParams[:]); err != nil {
+		return err
+	}
+
+	// t.Salt ([]uint8) (slice)
+	if len(t.Salt) > cbg.ByteArrayMaxLen {
+		return xerrors.Errorf("Byte array in field t.Salt was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajByteString, uint64(len(t.Salt))); err != nil {
+		return err
+	}
+
+	if _, err := w.Write(t.Salt[:]); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *Exec4Params) UnmarshalCBOR(r io.Reader) error {
+	*t = Exec4Params{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "init.Exec4Params", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "init.Exec4Params", "")
+	}
+
+	if extra != 3 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "init.Exec4Params", "")
+	}
+
+	// t.CodeCID (cid.Cid) (struct)
+
+	{
+
+		c, err := cbg.ReadCid(br)
+		if err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read cid field t.CodeCID: %w", err), "init.Exec4Params", "CodeCID")
+		}
+
+		t.CodeCID = c
+
+	}
+	// t.ConstructorParams ([]uint8) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "init.Exec4Params", "ConstructorParams")
+	}
+
+	if extra > cbg.ByteArrayMaxLen {
+		return acbor.WrapDecodeError(fmt.Errorf("t.ConstructorParams: byte array too large (%d)", extra), "init.Exec4Params", "ConstructorParams")
+	}
+	if maj != cbg.MajByteString {
+		return acbor.WrapDecodeError(fmt.Errorf("expected byte array"), "init.Exec4Params", "ConstructorParams")
+	}
+
+	if extra > 0 {
+		t.ConstructorParams = make([]uint8, extra)
+	}
+
+	if _, err := io.ReadFull(br, t.ConstructorParams[:]); err != nil {
+		return acbor.WrapDecodeError(err, "init.Exec4Params", "ConstructorParams")
+	}
+	// t.Salt ([]uint8) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "init.Exec4Params", "Salt")
+	}
+
+	if extra > cbg.ByteArrayMaxLen {
+		return acbor.WrapDecodeError(fmt.Errorf("t.Salt: byte array too large (%d)", extra), "init.Exec4Params", "Salt")
+	}
+	if maj != cbg.MajByteString {
+		return acbor.WrapDecodeError(fmt.Errorf("expected byte array"), "init.Exec4Params", "Salt")
+	}
+
+	if extra > 0 {
+		t.Salt = make([]uint8, extra)
+	}
+
+	if _, err := io.ReadFull(br, t.Salt[:]); err != nil {
+		return acbor.WrapDecodeError(err, "init.Exec4Params", "Salt")
+	}
+	return nil
+}
+
+var lengthBufAddressStatusReturn = []byte{131}
+
+func (t *AddressStatusReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufAddressStatusReturn); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Status (init.AddressStatus) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Status)); err != nil {
+		return err
+	}
+
+	// t.IDAddress (address.Address) (struct)
+	if err := t.IDAddress.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.DeletionEpoch (abi.ChainEpoch) (int64)
+	if t.DeletionEpoch >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.DeletionEpoch)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.DeletionEpoch-1)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *AddressStatusReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = AddressStatusReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "init.AddressStatusReturn", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "init.AddressStatusReturn", "")
+	}
+
+	if extra != 3 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "init.AddressStatusReturn", "")
+	}
+
+	// t.Status (init.AddressStatus) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return acbor.WrapDecodeError(err, "init.AddressStatusReturn", "Status")
+		}
+		if maj != cbg.MajUnsignedInt {
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "init.AddressStatusReturn", "Status")
+		}
+		t.Status = AddressStatus(extra)
+
+	}
+	// t.IDAddress (address.Address) (struct)
+
+	{
+
+		if err := t.IDAddress.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.IDAddress: %w", err), "init.AddressStatusReturn", "IDAddress")
+		}
+
+	}
+	// t.DeletionEpoch (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return acbor.WrapDecodeError(err, "init.AddressStatusReturn", "DeletionEpoch")
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "init.AddressStatusReturn", "DeletionEpoch")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "init.AddressStatusReturn", "DeletionEpoch")
+			}
+			extraI = -1 - extraI
+		default:
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "init.AddressStatusReturn", "DeletionEpoch")
+		}
+
+		t.DeletionEpoch = abi.ChainEpoch(extraI)
+	}
+	return nil
+}
+
+var lengthBufTombstone = []byte{130}
+
+func (t *Tombstone) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufTombstone); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.ID (abi.ActorID) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.ID)); err != nil {
+		return err
+	}
+
+	// t.DeletionEpoch (abi.ChainEpoch) (int64)
+	if t.DeletionEpoch >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.DeletionEpoch)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.DeletionEpoch-1)); err != nil {
+			return err
+		}
+	}
 	return nil
 }
+
+func (t *Tombstone) UnmarshalCBOR(r io.Reader) error {
+	*t = Tombstone{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "init.Tombstone", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "init.Tombstone", "")
+	}
+
+	if extra != 2 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "init.Tombstone", "")
+	}
+
+	// t.ID (abi.ActorID) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return acbor.WrapDecodeError(err, "init.Tombstone", "ID")
+		}
+		if maj != cbg.MajUnsignedInt {
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "init.Tombstone", "ID")
+		}
+		t.ID = abi.ActorID(extra)
+
+	}
+	// t.DeletionEpoch (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return acbor.WrapDecodeError(err, "init.Tombstone", "DeletionEpoch")
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "init.Tombstone", "DeletionEpoch")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "init.Tombstone", "DeletionEpoch")
+			}
+			extraI = -1 - extraI
+		default:
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "init.Tombstone", "DeletionEpoch")
+		}
+
+		t.DeletionEpoch = abi.ChainEpoch(extraI)
+	}
+	return nil
+}
+
+var lengthBufExecPermission = []byte{130}
+
+func (t *ExecPermission) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufExecPermission); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.CodeCID (cid.Cid) (struct)
+
+	if err := cbg.WriteCidBuf(scratch, w, t.CodeCID); err != nil {
+		return xerrors.Errorf("failed to write cid field t.CodeCID: %w", err)
+	}
+
+	// t.Callers ([]cid.Cid) (slice)
+	if len(t.Callers) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Callers was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Callers))); err != nil {
+		return err
+	}
+	for _, v := range t.Callers {
+		if err := cbg.WriteCidBuf(scratch, w, v); err != nil {
+			return xerrors.Errorf("failed writing cid field t.Callers: %w", err)
+		}
+	}
+	return nil
+}
+
+func (t *ExecPermission) UnmarshalCBOR(r io.Reader) error {
+	*t = ExecPermission{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "init.ExecPermission", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "init.ExecPermission", "")
+	}
+
+	if extra != 2 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "init.ExecPermission", "")
+	}
+
+	// t.CodeCID (cid.Cid) (struct)
+
+	{
+
+		c, err := cbg.ReadCid(br)
+		if err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read cid field t.CodeCID: %w", err), "init.ExecPermission", "CodeCID")
+		}
+
+		t.CodeCID = c
+
+	}
+	// t.Callers ([]cid.Cid) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "init.ExecPermission", "Callers")
+	}
+
+	if extra > cbg.MaxLength {
+		return acbor.WrapDecodeError(fmt.Errorf("t.Callers: array too large (%d)", extra), "init.ExecPermission", "Callers")
+	}
+
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("expected cbor array"), "init.ExecPermission", "Callers")
+	}
+
+	if extra > 0 {
+		t.Callers = make([]cid.Cid, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		c, err := cbg.ReadCid(br)
+		if err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("reading cid field t.Callers failed: %w", err), "init.ExecPermission", fmt.Sprintf("Callers[%d]", i))
+		}
+		t.Callers[i] = c
+	}
+
+	return nil
+}
+
+var lengthBufUpgradeApproval = []byte{131}
+
+func (t *UpgradeApproval) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufUpgradeApproval); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.OldCodeCID (cid.Cid) (struct)
+
+	if err := cbg.WriteCidBuf(scratch, w, t.OldCodeCID); err != nil {
+		return xerrors.Errorf("failed to write cid field t.OldCodeCID: %w", err)
+	}
+
+	// t.NewCodeCID (cid.Cid) (struct)
+
+	if err := cbg.WriteCidBuf(scratch, w, t.NewCodeCID); err != nil {
+		return xerrors.Errorf("failed to write cid field t.NewCodeCID: %w", err)
+	}
+
+	// t.Expiration (abi.ChainEpoch) (int64)
+	if t.Expiration >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Expiration)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.Expiration-1)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *UpgradeApproval) UnmarshalCBOR(r io.Reader) error {
+	*t = UpgradeApproval{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "init.UpgradeApproval", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "init.UpgradeApproval", "")
+	}
+
+	if extra != 3 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "init.UpgradeApproval", "")
+	}
+
+	// t.OldCodeCID (cid.Cid) (struct)
+
+	{
+
+		c, err := cbg.ReadCid(br)
+		if err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read cid field t.OldCodeCID: %w", err), "init.UpgradeApproval", "OldCodeCID")
+		}
+
+		t.OldCodeCID = c
+
+	}
+	// t.NewCodeCID (cid.Cid) (struct)
+
+	{
+
+		c, err := cbg.ReadCid(br)
+		if err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read cid field t.NewCodeCID: %w", err), "init.UpgradeApproval", "NewCodeCID")
+		}
+
+		t.NewCodeCID = c
+
+	}
+	// t.Expiration (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return acbor.WrapDecodeError(err, "init.UpgradeApproval", "Expiration")
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "init.UpgradeApproval", "Expiration")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "init.UpgradeApproval", "Expiration")
+			}
+			extraI = -1 - extraI
+		default:
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "init.UpgradeApproval", "Expiration")
+		}
+
+		t.Expiration = abi.ChainEpoch(extraI)
+	}
+	return nil
+}
+
+var lengthBufApproveUpgradeParams = []byte{131}
+
+func (t *ApproveUpgradeParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufApproveUpgradeParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.OldCodeCID (cid.Cid) (struct)
+
+	if err := cbg.WriteCidBuf(scratch, w, t.OldCodeCID); err != nil {
+		return xerrors.Errorf("failed to write cid field t.OldCodeCID: %w", err)
+	}
+
+	// t.NewCodeCID (cid.Cid) (struct)
+
+	if err := cbg.WriteCidBuf(scratch, w, t.NewCodeCID); err != nil {
+		return xerrors.Errorf("failed to write cid field t.NewCodeCID: %w", err)
+	}
+
+	// t.Expiration (abi.ChainEpoch) (int64)
+	if t.Expiration >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Expiration)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.Expiration-1)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *ApproveUpgradeParams) UnmarshalCBOR(r io.Reader) error {
+	*t = ApproveUpgradeParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "init.ApproveUpgradeParams", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "init.ApproveUpgradeParams", "")
+	}
+
+	if extra != 3 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "init.ApproveUpgradeParams", "")
+	}
+
+	// t.OldCodeCID (cid.Cid) (struct)
+
+	{
+
+		c, err := cbg.ReadCid(br)
+		if err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read cid field t.OldCodeCID: %w", err), "init.ApproveUpgradeParams", "OldCodeCID")
+		}
+
+		t.OldCodeCID = c
+
+	}
+	// t.NewCodeCID (cid.Cid) (struct)
+
+	{
+
+		c, err := cbg.ReadCid(br)
+		if err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read cid field t.NewCodeCID: %w", err), "init.ApproveUpgradeParams", "NewCodeCID")
+		}
+
+		t.NewCodeCID = c
+
+	}
+	// t.Expiration (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return acbor.WrapDecodeError(err, "init.ApproveUpgradeParams", "Expiration")
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "init.ApproveUpgradeParams", "Expiration")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "init.ApproveUpgradeParams", "Expiration")
+			}
+			extraI = -1 - extraI
+		default:
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "init.ApproveUpgradeParams", "Expiration")
+		}
+
+		t.Expiration = abi.ChainEpoch(extraI)
+	}
+	return nil
+}
+
+var lengthBufAuthorizeUpgradeParams = []byte{129}
+
+func (t *AuthorizeUpgradeParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufAuthorizeUpgradeParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.NewCodeCID (cid.Cid) (struct)
+
+	if err := cbg.WriteCidBuf(scratch, w, t.NewCodeCID); err != nil {
+		return xerrors.Errorf("failed to write cid field t.NewCodeCID: %w", err)
+	}
+
+	return nil
+}
+
+func (t *AuthorizeUpgradeParams) UnmarshalCBOR(r io.Reader) error {
+	*t = AuthorizeUpgradeParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "init.AuthorizeUpgradeParams", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "init.AuthorizeUpgradeParams", "")
+	}
+
+	if extra != 1 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "init.AuthorizeUpgradeParams", "")
+	}
+
+	// t.NewCodeCID (cid.Cid) (struct)
+
+	{
+
+		c, err := cbg.ReadCid(br)
+		if err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read cid field t.NewCodeCID: %w", err), "init.AuthorizeUpgradeParams", "NewCodeCID")
+		}
+
+		t.NewCodeCID = c
+
+	}
+	return nil
+}
+
+func (t *State) MaxEncodedLength() int64 {
+	return 34708112930
+}
+
+func (t *Exec4Params) MaxEncodedLength() int64 {
+	return 4194832
+}
+
+func (t *AddressStatusReturn) MaxEncodedLength() int64 {
+	return 85
+}
+
+func (t *Tombstone) MaxEncodedLength() int64 {
+	return 19
+}
+
+func (t *ExecPermission) MaxEncodedLength() int64 {
+	return 4235785
+}
+
+func (t *UpgradeApproval) MaxEncodedLength() int64 {
+	return 1044
+}
+
+func (t *ApproveUpgradeParams) MaxEncodedLength() int64 {
+	return 1044
+}
+
+func (t *AuthorizeUpgradeParams) MaxEncodedLength() int64 {
+	return 518
+}
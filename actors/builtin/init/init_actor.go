@@ -7,6 +7,7 @@ import (
 	"github.com/filecoin-project/go-state-types/exitcode"
 	init0 "github.com/filecoin-project/specs-actors/actors/builtin/init"
 	cid "github.com/ipfs/go-cid"
+	"golang.org/x/xerrors"
 
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
 	"github.com/filecoin-project/specs-actors/v2/actors/runtime"
@@ -22,6 +23,12 @@ func (a Actor) Exports() []interface{} {
 	return []interface{}{
 		builtin.MethodConstructor: a.Constructor,
 		2:                         a.Exec,
+		3:                         a.Exec4,
+		4:                         a.LookupRobustAddress,
+		5:                         a.TombstoneActor,
+		6:                         a.AddressStatus,
+		7:                         a.ApproveUpgrade,
+		8:                         a.AuthorizeUpgrade,
 	}
 }
 
@@ -47,7 +54,7 @@ func (a Actor) Constructor(rt runtime.Runtime, params *ConstructorParams) *abi.E
 	emptyMap, err := adt.MakeEmptyMap(adt.AsStore(rt)).Root()
 	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to construct state")
 
-	st := ConstructState(emptyMap, params.NetworkName)
+	st := ConstructState(emptyMap, emptyMap, emptyMap, params.NetworkName)
 	rt.StateCreate(st)
 	return nil
 }
@@ -68,7 +75,9 @@ func (a Actor) Exec(rt runtime.Runtime, params *ExecParams) *ExecReturn {
 	rt.ValidateImmediateCallerAcceptAny()
 	callerCodeCID, ok := rt.GetActorCodeCID(rt.Caller())
 	autil.AssertMsg(ok, "no code for actor at %s", rt.Caller())
-	if !canExec(callerCodeCID, params.CodeCID) {
+	var st State
+	rt.StateReadonly(&st)
+	if !st.CanExec(callerCodeCID, params.CodeCID) {
 		rt.Abortf(exitcode.ErrForbidden, "caller type %v cannot exec actor type %v", callerCodeCID, params.CodeCID)
 	}
 
@@ -80,7 +89,6 @@ func (a Actor) Exec(rt runtime.Runtime, params *ExecParams) *ExecReturn {
 
 	// Allocate an ID for this actor.
 	// Store mapping of pubkey or actor address to actor ID
-	var st State
 	var idAddr addr.Address
 	rt.StateTransaction(&st, func() {
 		var err error
@@ -98,16 +106,206 @@ func (a Actor) Exec(rt runtime.Runtime, params *ExecParams) *ExecReturn {
 	return &ExecReturn{IDAddress: idAddr, RobustAddress: uniqueAddress}
 }
 
-func canExec(callerCodeID cid.Cid, execCodeID cid.Cid) bool {
-	switch execCodeID {
-	case builtin.StorageMinerActorCodeID:
-		if callerCodeID == builtin.StoragePowerActorCodeID {
-			return true
+// Maximum length of the salt used to derive an Exec4 address.
+const MaxExec4SaltLength = 32
+
+type Exec4Params struct {
+	CodeCID           cid.Cid `checked:"true"` // invalid CIDs won't get committed to the state tree
+	ConstructorParams []byte
+	Salt              []byte
+}
+
+// Creates a new actor, as for Exec, but with a robust address derived deterministically from the creator's ID address,
+// the salt and the code CID, rather than from the creating message.
+// This allows the address of the new actor to be computed in advance with ComputeExec4Address.
+// Each (creator, salt, code) combination may be used to create only one actor.
+func (a Actor) Exec4(rt runtime.Runtime, params *Exec4Params) *ExecReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+	callerCodeCID, ok := rt.GetActorCodeCID(rt.Caller())
+	autil.AssertMsg(ok, "no code for actor at %s", rt.Caller())
+	var st State
+	rt.StateReadonly(&st)
+	if !st.CanExec(callerCodeCID, params.CodeCID) {
+		rt.Abortf(exitcode.ErrForbidden, "caller type %v cannot exec actor type %v", callerCodeCID, params.CodeCID)
+	}
+	if len(params.Salt) > MaxExec4SaltLength {
+		rt.Abortf(exitcode.ErrIllegalArgument, "salt length %d exceeds maximum %d", len(params.Salt), MaxExec4SaltLength)
+	}
+
+	uniqueAddress, err := ComputeExec4Address(rt.Caller(), params.Salt, params.CodeCID)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed to compute actor address")
+
+	// Allocate an ID for this actor.
+	// Store mapping of the derived actor address to actor ID
+	var idAddr addr.Address
+	rt.StateTransaction(&st, func() {
+		_, found, err := st.ResolveAddress(adt.AsStore(rt), uniqueAddress)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to resolve address %v", uniqueAddress)
+		if found {
+			rt.Abortf(exitcode.ErrForbidden, "actor address %v already exists", uniqueAddress)
+		}
+		// An address may not be re-used after its actor has been deleted.
+		_, found, err = st.LookupTombstone(adt.AsStore(rt), uniqueAddress)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to look up tombstone for %v", uniqueAddress)
+		if found {
+			rt.Abortf(exitcode.ErrForbidden, "actor address %v belonged to a deleted actor", uniqueAddress)
+		}
+
+		idAddr, err = st.MapAddressToNewID(adt.AsStore(rt), uniqueAddress)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to allocate ID address")
+	})
+
+	// Create an empty actor.
+	rt.CreateActor(params.CodeCID, idAddr)
+
+	// Invoke constructor.
+	code := rt.Send(idAddr, builtin.MethodConstructor, builtin.CBORBytes(params.ConstructorParams), rt.ValueReceived(), &builtin.Discard{})
+	builtin.RequireSuccess(rt, code, "constructor failed")
+
+	return &ExecReturn{IDAddress: idAddr, RobustAddress: uniqueAddress}
+}
+
+// Computes the robust address of an actor created by Exec4.
+// The address is an actor-protocol address over the concatenation of the creator's ID address bytes,
+// the code CID bytes and the salt. The creator must be given as an ID address.
+func ComputeExec4Address(creator addr.Address, salt []byte, code cid.Cid) (addr.Address, error) {
+	if creator.Protocol() != addr.ID {
+		return addr.Undef, xerrors.Errorf("creator %v must be an ID address", creator)
+	}
+	if !code.Defined() {
+		return addr.Undef, xerrors.Errorf("undefined code CID")
+	}
+	var preimage []byte
+	preimage = append(preimage, creator.Bytes()...)
+	preimage = append(preimage, code.Bytes()...)
+	preimage = append(preimage, salt...)
+	return addr.NewActorAddress(preimage)
+}
+
+// Returns the robust address from which an ID-address was mapped when its actor was created.
+// Aborts with ErrNotFound if there is no such address, e.g. for singleton actors.
+func (a Actor) LookupRobustAddress(rt runtime.Runtime, idAddr *addr.Address) *addr.Address {
+	rt.ValidateImmediateCallerAcceptAny()
+	if idAddr.Protocol() != addr.ID {
+		rt.Abortf(exitcode.ErrIllegalArgument, "address %v is not an ID address", idAddr)
+	}
+
+	var st State
+	rt.StateReadonly(&st)
+	robust, found, err := st.LookupRobustAddress(adt.AsStore(rt), *idAddr)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to look up robust address for %v", idAddr)
+	if !found {
+		if s, ok := builtin.SingletonByAddress(*idAddr); ok {
+			rt.Abortf(exitcode.ErrNotFound, "no robust address for %v, the %s singleton actor", idAddr, s.Name)
 		}
-		return false
-	case builtin.PaymentChannelActorCodeID, builtin.MultisigActorCodeID:
-		return true
-	default:
-		return false
+		rt.Abortf(exitcode.ErrNotFound, "no robust address for %v", idAddr)
 	}
+	return &robust
+}
+
+// Removes the address mapping for the calling actor, which is about to delete itself, and records a tombstone
+// for its robust address. Subsequent attempts to resolve the robust address will fail, rather than resolving
+// to the ID of a deleted actor.
+// Only actor types which may delete themselves may call this method.
+func (a Actor) TombstoneActor(rt runtime.Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
+	rt.ValidateImmediateCallerType(builtin.PaymentChannelActorCodeID)
+
+	var st State
+	rt.StateTransaction(&st, func() {
+		_, err := st.TombstoneAddress(adt.AsStore(rt), rt.Caller(), rt.CurrEpoch())
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to tombstone %v", rt.Caller())
+	})
+	return nil
+}
+
+type AddressStatus uint64
+
+const (
+	AddressUnknown AddressStatus = iota // The address has never been mapped to an ID.
+	AddressActive                       // The address is mapped to the ID of a live actor.
+	AddressDeleted                      // The address was mapped to the ID of an actor which has since been deleted.
+)
+
+type AddressStatusReturn struct {
+	Status        AddressStatus
+	IDAddress     addr.Address   // The ID-address to which the address is or was mapped, undefined if unknown.
+	DeletionEpoch abi.ChainEpoch // The epoch at which the actor was deleted, if deleted.
+}
+
+// Reports whether an address resolves to a live actor, belonged to a deleted actor, or was never mapped.
+func (a Actor) AddressStatus(rt runtime.Runtime, address *addr.Address) *AddressStatusReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+	if address.Protocol() == addr.ID {
+		rt.Abortf(exitcode.ErrIllegalArgument, "address %v is an ID address", address)
+	}
+
+	var st State
+	rt.StateReadonly(&st)
+	store := adt.AsStore(rt)
+
+	idAddr, found, err := st.ResolveAddress(store, *address)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to resolve address %v", address)
+	if found {
+		return &AddressStatusReturn{Status: AddressActive, IDAddress: idAddr, DeletionEpoch: -1}
+	}
+
+	tombstone, found, err := st.LookupTombstone(store, *address)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to look up tombstone for %v", address)
+	if found {
+		idAddr, err = addr.NewIDAddress(uint64(tombstone.ID))
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to construct ID address for %d", tombstone.ID)
+		return &AddressStatusReturn{Status: AddressDeleted, IDAddress: idAddr, DeletionEpoch: tombstone.DeletionEpoch}
+	}
+	return &AddressStatusReturn{Status: AddressUnknown, IDAddress: addr.Undef, DeletionEpoch: -1}
+}
+
+type ApproveUpgradeParams struct {
+	OldCodeCID cid.Cid        `checked:"true"`
+	NewCodeCID cid.Cid        `checked:"true"`
+	Expiration abi.ChainEpoch // The last epoch at which the upgrade may be performed.
+}
+
+// Approves in-place upgrade of actors with some code to new code, up to an expiration epoch.
+// This opens a migration window during which actors may replace their own code via the runtime.
+// Only governance, exercised by the system actor, may approve upgrades.
+func (a Actor) ApproveUpgrade(rt runtime.Runtime, params *ApproveUpgradeParams) *abi.EmptyValue {
+	rt.ValidateImmediateCallerIs(builtin.SystemActorAddr)
+	if !builtin.IsBuiltinActor(params.OldCodeCID) || !builtin.IsBuiltinActor(params.NewCodeCID) {
+		rt.Abortf(exitcode.ErrIllegalArgument, "cannot approve upgrade from %v to %v, not builtin actor code", params.OldCodeCID, params.NewCodeCID)
+	}
+	if params.OldCodeCID.Equals(params.NewCodeCID) {
+		rt.Abortf(exitcode.ErrIllegalArgument, "cannot approve upgrade of %v to itself", params.OldCodeCID)
+	}
+	if params.Expiration < rt.CurrEpoch() {
+		rt.Abortf(exitcode.ErrIllegalArgument, "upgrade expiration %d is before current epoch %d", params.Expiration, rt.CurrEpoch())
+	}
+
+	var st State
+	rt.StateTransaction(&st, func() {
+		st.SetUpgradeApproval(params.OldCodeCID, params.NewCodeCID, params.Expiration, rt.CurrEpoch())
+	})
+	return nil
+}
+
+type AuthorizeUpgradeParams struct {
+	NewCodeCID cid.Cid `checked:"true"`
+}
+
+// Checks that the calling actor may replace its code with new code at the current epoch.
+// This is invoked by the runtime on behalf of an actor upgrading itself.
+// Aborts with ErrForbidden if the upgrade has not been approved, or the approval has expired.
+func (a Actor) AuthorizeUpgrade(rt runtime.Runtime, params *AuthorizeUpgradeParams) *abi.EmptyValue {
+	rt.ValidateImmediateCallerAcceptAny()
+	callerCode, ok := rt.GetActorCodeCID(rt.Caller())
+	if !ok {
+		rt.Abortf(exitcode.ErrIllegalState, "no code for caller %v", rt.Caller())
+	}
+
+	var st State
+	rt.StateReadonly(&st)
+	if !st.CanUpgrade(callerCode, params.NewCodeCID, rt.CurrEpoch()) {
+		rt.Abortf(exitcode.ErrForbidden, "upgrade of %v from %v to %v is not approved at epoch %d",
+			rt.Caller(), callerCode, params.NewCodeCID, rt.CurrEpoch())
+	}
+	return nil
 }
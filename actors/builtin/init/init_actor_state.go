@@ -16,16 +16,124 @@ type State struct {
 	AddressMap  cid.Cid // HAMT[addr.Address]abi.ActorID
 	NextID      abi.ActorID
 	NetworkName string
+	// Reverse of the address map, resolving the ID of each mapped actor to the address from which it was mapped.
+	RobustAddressMap cid.Cid // HAMT[abi.ActorID]addr.Address
+	// Addresses which were removed from the address map when their actor was deleted.
+	Tombstones cid.Cid // HAMT[addr.Address]Tombstone
+	// Actor code which may be instantiated by Exec and Exec4, and by which callers.
+	ExecAllowlist []ExecPermission
+	// Code upgrades approved by governance, which actors may perform in place until expiration.
+	UpgradeApprovals []UpgradeApproval
 }
 
-func ConstructState(addressMapRoot cid.Cid, networkName string) *State {
+// Permits instantiation of actors with some code.
+type ExecPermission struct {
+	CodeCID cid.Cid
+	// Code of actors which may instantiate the code, or empty if any actor may.
+	Callers []cid.Cid
+}
+
+// Permits actors with some code to replace it with new code, up to and including an expiration epoch.
+type UpgradeApproval struct {
+	OldCodeCID cid.Cid
+	NewCodeCID cid.Cid
+	Expiration abi.ChainEpoch
+}
+
+// Records the deletion of an actor whose address was mapped to an ID.
+type Tombstone struct {
+	ID            abi.ActorID
+	DeletionEpoch abi.ChainEpoch
+}
+
+func ConstructState(addressMapRoot, robustAddressMapRoot, tombstonesRoot cid.Cid, networkName string) *State {
 	return &State{
-		AddressMap:  addressMapRoot,
-		NextID:      abi.ActorID(builtin.FirstNonSingletonActorId),
-		NetworkName: networkName,
+		AddressMap:       addressMapRoot,
+		NextID:           abi.ActorID(builtin.FirstNonSingletonActorId),
+		NetworkName:      networkName,
+		RobustAddressMap: robustAddressMapRoot,
+		Tombstones:       tombstonesRoot,
+		ExecAllowlist:    DefaultExecAllowlist(),
 	}
 }
 
+// Returns the actor code which may be instantiated by Exec in a new network.
+// Storage miners may only be created by the power actor, which tracks them. Payment channels and multisigs
+// may be created by anyone.
+func DefaultExecAllowlist() []ExecPermission {
+	return []ExecPermission{
+		{CodeCID: builtin.StorageMinerActorCodeID, Callers: []cid.Cid{builtin.StoragePowerActorCodeID}},
+		{CodeCID: builtin.PaymentChannelActorCodeID},
+		{CodeCID: builtin.MultisigActorCodeID},
+	}
+}
+
+// Returns whether an actor with code callerCodeID may instantiate an actor with code execCodeID.
+func (s *State) CanExec(callerCodeID, execCodeID cid.Cid) bool {
+	for _, perm := range s.ExecAllowlist {
+		if !perm.CodeCID.Equals(execCodeID) {
+			continue
+		}
+		if len(perm.Callers) == 0 {
+			return true
+		}
+		for _, caller := range perm.Callers {
+			if caller.Equals(callerCodeID) {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+// Permits instantiation of actors with code by the given callers (or any caller, if empty),
+// replacing any existing permission for the code.
+// This is intended for use by state migrations that deploy new actor code.
+func (s *State) SetExecPermission(code cid.Cid, callers []cid.Cid) {
+	perm := ExecPermission{CodeCID: code, Callers: callers}
+	for i := range s.ExecAllowlist {
+		if s.ExecAllowlist[i].CodeCID.Equals(code) {
+			s.ExecAllowlist[i] = perm
+			return
+		}
+	}
+	s.ExecAllowlist = append(s.ExecAllowlist, perm)
+}
+
+// Removes any permission to instantiate actors with code.
+func (s *State) RemoveExecPermission(code cid.Cid) {
+	for i := range s.ExecAllowlist {
+		if s.ExecAllowlist[i].CodeCID.Equals(code) {
+			s.ExecAllowlist = append(s.ExecAllowlist[:i], s.ExecAllowlist[i+1:]...)
+			return
+		}
+	}
+}
+
+// Checks whether an actor with code oldCode may replace it with newCode at an epoch.
+func (s *State) CanUpgrade(oldCode, newCode cid.Cid, epoch abi.ChainEpoch) bool {
+	for _, approval := range s.UpgradeApprovals {
+		if approval.OldCodeCID.Equals(oldCode) {
+			return approval.NewCodeCID.Equals(newCode) && epoch <= approval.Expiration
+		}
+	}
+	return false
+}
+
+// Approves upgrade of actors with code oldCode to newCode until an expiration epoch,
+// replacing any existing approval for the old code. Expired approvals are pruned.
+func (s *State) SetUpgradeApproval(oldCode, newCode cid.Cid, expiration, currEpoch abi.ChainEpoch) {
+	approvals := make([]UpgradeApproval, 0, len(s.UpgradeApprovals)+1)
+	for _, approval := range s.UpgradeApprovals {
+		if approval.OldCodeCID.Equals(oldCode) || approval.Expiration < currEpoch {
+			continue
+		}
+		approvals = append(approvals, approval)
+	}
+	s.UpgradeApprovals = append(approvals, UpgradeApproval{OldCodeCID: oldCode, NewCodeCID: newCode, Expiration: expiration})
+}
+
 // ResolveAddress resolves an address to an ID-address, if possible.
 // If the provided address is an ID address, it is returned as-is.
 // This means that mapped ID-addresses (which should only appear as values, not keys) and
@@ -81,7 +189,118 @@ func (s *State) MapAddressToNewID(store adt.Store, address addr.Address) (addr.A
 	}
 	s.AddressMap = amr
 
+	rm, err := adt.AsMap(store, s.RobustAddressMap)
+	if err != nil {
+		return addr.Undef, xerrors.Errorf("failed to load robust address map: %w", err)
+	}
+	err = rm.Put(abi.UIntKey(uint64(actorID)), &address)
+	if err != nil {
+		return addr.Undef, xerrors.Errorf("robust address map failed to store entry: %w", err)
+	}
+	rmr, err := rm.Root()
+	if err != nil {
+		return addr.Undef, xerrors.Errorf("failed to get robust address map root: %w", err)
+	}
+	s.RobustAddressMap = rmr
+
 	idAddr, err := addr.NewIDAddress(uint64(actorID))
 	autil.Assert(err == nil)
 	return idAddr, nil
 }
+
+// LookupRobustAddress finds the address from which an ID-address was mapped, if any.
+//
+// Returns the robust address and `true` if the ID-address was allocated by the mapping.
+// Returns an undefined address and `false` if the ID-address was not found, which is the case for singleton actors,
+// for actors created by ID directly, and for unallocated IDs.
+// Returns an error if the address is not an ID-address or if state was inconsistent.
+func (s *State) LookupRobustAddress(store adt.Store, idAddr addr.Address) (addr.Address, bool, error) {
+	actorID, err := addr.IDFromAddress(idAddr)
+	if err != nil {
+		return addr.Undef, false, xerrors.Errorf("failed to get actor ID from %v: %w", idAddr, err)
+	}
+
+	m, err := adt.AsMap(store, s.RobustAddressMap)
+	if err != nil {
+		return addr.Undef, false, xerrors.Errorf("failed to load robust address map: %w", err)
+	}
+
+	var robust addr.Address
+	found, err := m.Get(abi.UIntKey(actorID), &robust)
+	if err != nil {
+		return addr.Undef, false, xerrors.Errorf("failed to get from robust address map: %w", err)
+	}
+	if !found {
+		return addr.Undef, false, nil
+	}
+	return robust, true, nil
+}
+
+// Removes the address mapping to an ID-address, recording a tombstone for the robust address at the deletion epoch.
+// Returns false if the ID-address was not allocated by the mapping, in which case no tombstone is recorded.
+func (s *State) TombstoneAddress(store adt.Store, idAddr addr.Address, epoch abi.ChainEpoch) (bool, error) {
+	actorID, err := addr.IDFromAddress(idAddr)
+	if err != nil {
+		return false, xerrors.Errorf("failed to get actor ID from %v: %w", idAddr, err)
+	}
+
+	rm, err := adt.AsMap(store, s.RobustAddressMap)
+	if err != nil {
+		return false, xerrors.Errorf("failed to load robust address map: %w", err)
+	}
+	var robust addr.Address
+	found, err := rm.Get(abi.UIntKey(actorID), &robust)
+	if err != nil {
+		return false, xerrors.Errorf("failed to get from robust address map: %w", err)
+	}
+	if !found {
+		return false, nil
+	}
+	if err = rm.Delete(abi.UIntKey(actorID)); err != nil {
+		return false, xerrors.Errorf("failed to remove from robust address map: %w", err)
+	}
+	if s.RobustAddressMap, err = rm.Root(); err != nil {
+		return false, xerrors.Errorf("failed to get robust address map root: %w", err)
+	}
+
+	m, err := adt.AsMap(store, s.AddressMap)
+	if err != nil {
+		return false, xerrors.Errorf("failed to load address map: %w", err)
+	}
+	if err = m.Delete(abi.AddrKey(robust)); err != nil {
+		return false, xerrors.Errorf("failed to remove from address map: %w", err)
+	}
+	if s.AddressMap, err = m.Root(); err != nil {
+		return false, xerrors.Errorf("failed to get address map root: %w", err)
+	}
+
+	tombstones, err := adt.AsMap(store, s.Tombstones)
+	if err != nil {
+		return false, xerrors.Errorf("failed to load tombstones: %w", err)
+	}
+	if err = tombstones.Put(abi.AddrKey(robust), &Tombstone{ID: abi.ActorID(actorID), DeletionEpoch: epoch}); err != nil {
+		return false, xerrors.Errorf("failed to store tombstone: %w", err)
+	}
+	if s.Tombstones, err = tombstones.Root(); err != nil {
+		return false, xerrors.Errorf("failed to get tombstones root: %w", err)
+	}
+	return true, nil
+}
+
+// LookupTombstone finds the tombstone for an address whose actor has been deleted, if any.
+// Returns the tombstone and `true` if found, or `false` if the address was never removed from the mapping.
+func (s *State) LookupTombstone(store adt.Store, address addr.Address) (*Tombstone, bool, error) {
+	tombstones, err := adt.AsMap(store, s.Tombstones)
+	if err != nil {
+		return nil, false, xerrors.Errorf("failed to load tombstones: %w", err)
+	}
+	var tombstone Tombstone
+	found, err := tombstones.Get(abi.AddrKey(address), &tombstone)
+	if err != nil {
+		return nil, false, xerrors.Errorf("failed to get tombstone for %v: %w", address, err)
+	}
+	if !found {
+		return nil, false, nil
+	}
+	return &tombstone, true, nil
+}
@@ -27,7 +27,7 @@ func TestConstructor(t *testing.T) {
 	actor := initHarness{init_.Actor{}, t}
 
 	receiver := tutil.NewIDAddr(t, 1000)
-	builder := mock.NewBuilder(context.Background(), receiver).WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
+	builder := mock.NewBuilder(context.Background(), receiver).WithStateInvariants(init_.CheckStateInvariants).WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
 	rt := builder.Build(t)
 	actor.constructAndVerify(rt)
 	actor.checkState(rt)
@@ -38,7 +38,7 @@ func TestExec(t *testing.T) {
 
 	receiver := tutil.NewIDAddr(t, 1000)
 	anne := tutil.NewIDAddr(t, 1001)
-	builder := mock.NewBuilder(context.Background(), receiver).WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
+	builder := mock.NewBuilder(context.Background(), receiver).WithStateInvariants(init_.CheckStateInvariants).WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
 
 	t.Run("abort actors that cannot call exec", func(t *testing.T) {
 		rt := builder.Build(t)
@@ -213,6 +213,423 @@ func TestExec(t *testing.T) {
 	})
 }
 
+func TestExecAllowlist(t *testing.T) {
+	actor := initHarness{init_.Actor{}, t}
+
+	receiver := tutil.NewIDAddr(t, 1000)
+	anne := tutil.NewIDAddr(t, 1001)
+	newCode := tutil.MakeCID("new-actor", nil)
+	builder := mock.NewBuilder(context.Background(), receiver).WithStateInvariants(init_.CheckStateInvariants).WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
+
+	setPermission := func(rt *mock.Runtime, code cid.Cid, callers ...cid.Cid) {
+		st := actor.state(rt)
+		st.SetExecPermission(code, callers)
+		rt.ReplaceState(st)
+	}
+
+	t.Run("default allowlist", func(t *testing.T) {
+		st := init_.State{ExecAllowlist: init_.DefaultExecAllowlist()}
+		assert.True(t, st.CanExec(builtin.AccountActorCodeID, builtin.PaymentChannelActorCodeID))
+		assert.True(t, st.CanExec(builtin.AccountActorCodeID, builtin.MultisigActorCodeID))
+		assert.True(t, st.CanExec(builtin.StoragePowerActorCodeID, builtin.StorageMinerActorCodeID))
+		assert.False(t, st.CanExec(builtin.AccountActorCodeID, builtin.StorageMinerActorCodeID))
+		assert.False(t, st.CanExec(builtin.AccountActorCodeID, builtin.AccountActorCodeID))
+		assert.False(t, st.CanExec(builtin.AccountActorCodeID, newCode))
+	})
+
+	t.Run("exec code added to allowlist", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			actor.execAndVerify(rt, newCode, []byte{})
+		})
+
+		setPermission(rt, newCode)
+		actor.checkState(rt)
+
+		uniqueAddr := tutil.NewActorAddr(t, "new")
+		rt.SetNewActorAddress(uniqueAddr)
+		expectedIdAddr := tutil.NewIDAddr(t, 100)
+		rt.ExpectCreateActor(newCode, expectedIdAddr)
+		rt.ExpectSend(expectedIdAddr, builtin.MethodConstructor, builtin.CBORBytes(nil), big.Zero(), nil, exitcode.Ok)
+		execRet := actor.execAndVerify(rt, newCode, nil)
+		assert.Equal(t, expectedIdAddr, execRet.IDAddress)
+		actor.checkState(rt)
+	})
+
+	t.Run("permission restricted to callers", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		setPermission(rt, newCode, builtin.MultisigActorCodeID)
+
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			actor.exec4AndVerify(rt, newCode, []byte{}, []byte("salt"))
+		})
+
+		msig := tutil.NewIDAddr(t, 1002)
+		rt.SetCaller(msig, builtin.MultisigActorCodeID)
+		expectedIdAddr := tutil.NewIDAddr(t, 100)
+		rt.ExpectCreateActor(newCode, expectedIdAddr)
+		rt.ExpectSend(expectedIdAddr, builtin.MethodConstructor, builtin.CBORBytes(nil), big.Zero(), nil, exitcode.Ok)
+		actor.exec4AndVerify(rt, newCode, nil, []byte("salt"))
+		actor.checkState(rt)
+	})
+
+	t.Run("exec code removed from allowlist", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		st := actor.state(rt)
+		st.RemoveExecPermission(builtin.MultisigActorCodeID)
+		rt.ReplaceState(st)
+
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			actor.execAndVerify(rt, builtin.MultisigActorCodeID, []byte{})
+		})
+		actor.checkState(rt)
+	})
+}
+
+func TestExec4(t *testing.T) {
+	actor := initHarness{init_.Actor{}, t}
+
+	receiver := tutil.NewIDAddr(t, 1000)
+	anne := tutil.NewIDAddr(t, 1001)
+	bob := tutil.NewIDAddr(t, 1002)
+	builder := mock.NewBuilder(context.Background(), receiver).WithStateInvariants(init_.CheckStateInvariants).WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
+
+	var fakeParams = builtin.CBORBytes([]byte{'D', 'E', 'A', 'D', 'B', 'E', 'E', 'F'})
+	salt := []byte("salt")
+
+	t.Run("creates actor at predictable address", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+
+		expectedRobust, err := init_.ComputeExec4Address(anne, salt, builtin.MultisigActorCodeID)
+		assert.NoError(t, err)
+		assert.Equal(t, addr.Actor, expectedRobust.Protocol())
+
+		expectedIdAddr := tutil.NewIDAddr(t, 100)
+		rt.ExpectCreateActor(builtin.MultisigActorCodeID, expectedIdAddr)
+		rt.ExpectSend(expectedIdAddr, builtin.MethodConstructor, fakeParams, big.Zero(), nil, exitcode.Ok)
+		ret := actor.exec4AndVerify(rt, builtin.MultisigActorCodeID, fakeParams, salt)
+		assert.Equal(t, expectedRobust, ret.RobustAddress)
+		assert.Equal(t, expectedIdAddr, ret.IDAddress)
+
+		resolved, found, err := actor.state(rt).ResolveAddress(adt.AsStore(rt), expectedRobust)
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, expectedIdAddr, resolved)
+
+		// The same salt from a different creator yields a different address.
+		rt.SetCaller(bob, builtin.AccountActorCodeID)
+		expectedIdAddr2 := tutil.NewIDAddr(t, 101)
+		rt.ExpectCreateActor(builtin.MultisigActorCodeID, expectedIdAddr2)
+		rt.ExpectSend(expectedIdAddr2, builtin.MethodConstructor, fakeParams, big.Zero(), nil, exitcode.Ok)
+		ret2 := actor.exec4AndVerify(rt, builtin.MultisigActorCodeID, fakeParams, salt)
+		assert.NotEqual(t, ret.RobustAddress, ret2.RobustAddress)
+		assert.Equal(t, expectedIdAddr2, ret2.IDAddress)
+		actor.checkState(rt)
+	})
+
+	t.Run("fails to reuse a salt", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+
+		expectedIdAddr := tutil.NewIDAddr(t, 100)
+		rt.ExpectCreateActor(builtin.PaymentChannelActorCodeID, expectedIdAddr)
+		rt.ExpectSend(expectedIdAddr, builtin.MethodConstructor, fakeParams, big.Zero(), nil, exitcode.Ok)
+		actor.exec4AndVerify(rt, builtin.PaymentChannelActorCodeID, fakeParams, salt)
+
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			actor.exec4AndVerify(rt, builtin.PaymentChannelActorCodeID, fakeParams, salt)
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("fails with oversized salt", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			actor.exec4AndVerify(rt, builtin.PaymentChannelActorCodeID, fakeParams, make([]byte, init_.MaxExec4SaltLength+1))
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("abort actors that cannot call exec", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			actor.exec4AndVerify(rt, builtin.StorageMinerActorCodeID, fakeParams, salt)
+		})
+		actor.checkState(rt)
+	})
+}
+
+func TestLookupRobustAddress(t *testing.T) {
+	actor := initHarness{init_.Actor{}, t}
+
+	receiver := tutil.NewIDAddr(t, 1000)
+	anne := tutil.NewIDAddr(t, 1001)
+	builder := mock.NewBuilder(context.Background(), receiver).WithStateInvariants(init_.CheckStateInvariants).WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
+
+	t.Run("resolves created actor to its robust address", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+
+		uniqueAddr := tutil.NewActorAddr(t, "paych")
+		rt.SetNewActorAddress(uniqueAddr)
+		expectedIdAddr := tutil.NewIDAddr(t, 100)
+		rt.ExpectCreateActor(builtin.PaymentChannelActorCodeID, expectedIdAddr)
+		rt.ExpectSend(expectedIdAddr, builtin.MethodConstructor, nil, big.Zero(), nil, exitcode.Ok)
+		actor.execAndVerify(rt, builtin.PaymentChannelActorCodeID, nil)
+
+		assert.Equal(t, uniqueAddr, actor.lookupRobustAddress(rt, expectedIdAddr))
+
+		// State mapping of a pubkey address is also reversed.
+		st := actor.state(rt)
+		pubkey := tutil.NewBLSAddr(t, 1)
+		pubkeyIdAddr, err := st.MapAddressToNewID(adt.AsStore(rt), pubkey)
+		assert.NoError(t, err)
+		robust, found, err := st.LookupRobustAddress(adt.AsStore(rt), pubkeyIdAddr)
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, pubkey, robust)
+		actor.checkState(rt)
+	})
+
+	t.Run("fails for unmapped ID", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+
+		rt.ExpectAbort(exitcode.ErrNotFound, func() {
+			actor.lookupRobustAddress(rt, builtin.StoragePowerActorAddr)
+		})
+		rt.ExpectAbort(exitcode.ErrNotFound, func() {
+			actor.lookupRobustAddress(rt, tutil.NewIDAddr(t, 100))
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("fails for non-ID address", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			actor.lookupRobustAddress(rt, tutil.NewBLSAddr(t, 1))
+		})
+		actor.checkState(rt)
+	})
+}
+
+func TestTombstones(t *testing.T) {
+	actor := initHarness{init_.Actor{}, t}
+
+	receiver := tutil.NewIDAddr(t, 1000)
+	anne := tutil.NewIDAddr(t, 1001)
+	builder := mock.NewBuilder(context.Background(), receiver).WithStateInvariants(init_.CheckStateInvariants).WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
+
+	createPaych := func(rt *mock.Runtime, robust addr.Address) addr.Address {
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		rt.SetNewActorAddress(robust)
+		idAddr := tutil.NewIDAddr(t, uint64(actor.state(rt).NextID))
+		rt.ExpectCreateActor(builtin.PaymentChannelActorCodeID, idAddr)
+		rt.ExpectSend(idAddr, builtin.MethodConstructor, nil, big.Zero(), nil, exitcode.Ok)
+		actor.execAndVerify(rt, builtin.PaymentChannelActorCodeID, nil)
+		return idAddr
+	}
+
+	t.Run("deleted actor address is tombstoned", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		robust := tutil.NewActorAddr(t, "paych")
+		idAddr := createPaych(rt, robust)
+
+		status := actor.addressStatus(rt, robust)
+		assert.Equal(t, init_.AddressActive, status.Status)
+		assert.Equal(t, idAddr, status.IDAddress)
+
+		rt.SetEpoch(42)
+		actor.tombstoneActor(rt, idAddr)
+
+		status = actor.addressStatus(rt, robust)
+		assert.Equal(t, init_.AddressDeleted, status.Status)
+		assert.Equal(t, idAddr, status.IDAddress)
+		assert.Equal(t, abi.ChainEpoch(42), status.DeletionEpoch)
+
+		_, found, err := actor.state(rt).ResolveAddress(adt.AsStore(rt), robust)
+		assert.NoError(t, err)
+		assert.False(t, found)
+		_, found, err = actor.state(rt).LookupRobustAddress(adt.AsStore(rt), idAddr)
+		assert.NoError(t, err)
+		assert.False(t, found)
+		actor.checkState(rt)
+	})
+
+	t.Run("unknown address", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		status := actor.addressStatus(rt, tutil.NewActorAddr(t, "nothing"))
+		assert.Equal(t, init_.AddressUnknown, status.Status)
+		assert.Equal(t, addr.Undef, status.IDAddress)
+		actor.checkState(rt)
+	})
+
+	t.Run("tombstoning an unmapped actor has no effect", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		before := actor.state(rt)
+
+		actor.tombstoneActor(rt, tutil.NewIDAddr(t, 100))
+		assert.Equal(t, before, actor.state(rt))
+		actor.checkState(rt)
+	})
+
+	t.Run("Exec4 address cannot be reused after deletion", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+
+		idAddr := tutil.NewIDAddr(t, 100)
+		rt.ExpectCreateActor(builtin.PaymentChannelActorCodeID, idAddr)
+		rt.ExpectSend(idAddr, builtin.MethodConstructor, nil, big.Zero(), nil, exitcode.Ok)
+		actor.exec4AndVerify(rt, builtin.PaymentChannelActorCodeID, nil, []byte("salt"))
+		actor.tombstoneActor(rt, idAddr)
+
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			actor.exec4AndVerify(rt, builtin.PaymentChannelActorCodeID, nil, []byte("salt"))
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("fails when caller cannot delete itself", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.PaymentChannelActorCodeID)
+		rt.ExpectAbort(exitcode.SysErrForbidden, func() {
+			rt.Call(actor.TombstoneActor, nil)
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("fails to query status of an ID address", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			actor.addressStatus(rt, tutil.NewIDAddr(t, 100))
+		})
+		actor.checkState(rt)
+	})
+}
+
+func TestUpgradeApproval(t *testing.T) {
+	actor := initHarness{init_.Actor{}, t}
+
+	receiver := tutil.NewIDAddr(t, 1000)
+	miner := tutil.NewIDAddr(t, 1001)
+	builder := mock.NewBuilder(context.Background(), receiver).WithStateInvariants(init_.CheckStateInvariants).WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
+
+	oldCode := builtin.StorageMinerActorCodeID
+	newCode := builtin.MultisigActorCodeID
+
+	t.Run("approved upgrade is authorized until expiration", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		rt.SetEpoch(10)
+		actor.approveUpgrade(rt, oldCode, newCode, 20)
+		assert.Equal(t, []init_.UpgradeApproval{{OldCodeCID: oldCode, NewCodeCID: newCode, Expiration: 20}}, actor.state(rt).UpgradeApprovals)
+
+		rt.SetCaller(miner, oldCode)
+		actor.authorizeUpgrade(rt, newCode)
+		rt.SetEpoch(20)
+		actor.authorizeUpgrade(rt, newCode)
+
+		rt.SetEpoch(21)
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			actor.authorizeUpgrade(rt, newCode)
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("upgrade must match approval", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		actor.approveUpgrade(rt, oldCode, newCode, 20)
+
+		rt.SetCaller(miner, oldCode)
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			actor.authorizeUpgrade(rt, builtin.PaymentChannelActorCodeID)
+		})
+		rt.SetCaller(miner, builtin.PaymentChannelActorCodeID)
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			actor.authorizeUpgrade(rt, newCode)
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("approval replaces previous approval and prunes expired ones", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		actor.approveUpgrade(rt, builtin.PaymentChannelActorCodeID, newCode, 5)
+		actor.approveUpgrade(rt, oldCode, newCode, 20)
+
+		rt.SetEpoch(10)
+		actor.approveUpgrade(rt, oldCode, builtin.PaymentChannelActorCodeID, 30)
+		assert.Equal(t, []init_.UpgradeApproval{
+			{OldCodeCID: oldCode, NewCodeCID: builtin.PaymentChannelActorCodeID, Expiration: 30},
+		}, actor.state(rt).UpgradeApprovals)
+		actor.checkState(rt)
+	})
+
+	t.Run("only governance may approve", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		rt.SetCaller(miner, oldCode)
+		rt.ExpectValidateCallerAddr(builtin.SystemActorAddr)
+		rt.ExpectAbort(exitcode.SysErrForbidden, func() {
+			rt.Call(actor.ApproveUpgrade, &init_.ApproveUpgradeParams{OldCodeCID: oldCode, NewCodeCID: newCode, Expiration: 20})
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("invalid approvals rejected", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		rt.SetEpoch(10)
+
+		for _, params := range []init_.ApproveUpgradeParams{
+			{OldCodeCID: oldCode, NewCodeCID: oldCode, Expiration: 20},
+			{OldCodeCID: oldCode, NewCodeCID: tutil.MakeCID("unknown", nil), Expiration: 20},
+			{OldCodeCID: oldCode, NewCodeCID: newCode, Expiration: 9},
+		} {
+			params := params
+			rt.ExpectValidateCallerAddr(builtin.SystemActorAddr)
+			rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+				rt.Call(actor.ApproveUpgrade, &params)
+			})
+		}
+		assert.Empty(t, actor.state(rt).UpgradeApprovals)
+		actor.checkState(rt)
+	})
+}
+
 type initHarness struct {
 	init_.Actor
 	t testing.TB
@@ -242,8 +659,11 @@ func (h *initHarness) constructAndVerify(rt *mock.Runtime) {
 	emptyMap, err := adt.AsMap(adt.AsStore(rt), st.AddressMap)
 	assert.NoError(h.t, err)
 	assert.Equal(h.t, tutil.MustRoot(h.t, emptyMap), st.AddressMap)
+	assert.Equal(h.t, tutil.MustRoot(h.t, emptyMap), st.RobustAddressMap)
+	assert.Equal(h.t, tutil.MustRoot(h.t, emptyMap), st.Tombstones)
 	assert.Equal(h.t, abi.ActorID(builtin.FirstNonSingletonActorId), st.NextID)
 	assert.Equal(h.t, "mock", st.NetworkName)
+	assert.Equal(h.t, init_.DefaultExecAllowlist(), st.ExecAllowlist)
 }
 
 func (h *initHarness) execAndVerify(rt *mock.Runtime, codeID cid.Cid, constructorParams []byte) *init_.ExecReturn {
@@ -255,3 +675,51 @@ func (h *initHarness) execAndVerify(rt *mock.Runtime, codeID cid.Cid, constructo
 	rt.Verify()
 	return ret
 }
+
+func (h *initHarness) exec4AndVerify(rt *mock.Runtime, codeID cid.Cid, constructorParams []byte, salt []byte) *init_.ExecReturn {
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.Exec4, &init_.Exec4Params{
+		CodeCID:           codeID,
+		ConstructorParams: constructorParams,
+		Salt:              salt,
+	}).(*init_.ExecReturn)
+	rt.Verify()
+	return ret
+}
+
+func (h *initHarness) lookupRobustAddress(rt *mock.Runtime, idAddr addr.Address) addr.Address {
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.LookupRobustAddress, &idAddr).(*addr.Address)
+	rt.Verify()
+	return *ret
+}
+
+func (h *initHarness) tombstoneActor(rt *mock.Runtime, idAddr addr.Address) {
+	rt.SetCaller(idAddr, builtin.PaymentChannelActorCodeID)
+	rt.ExpectValidateCallerType(builtin.PaymentChannelActorCodeID)
+	ret := rt.Call(h.TombstoneActor, nil)
+	assert.Nil(h.t, ret)
+	rt.Verify()
+}
+
+func (h *initHarness) addressStatus(rt *mock.Runtime, a addr.Address) *init_.AddressStatusReturn {
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.AddressStatus, &a).(*init_.AddressStatusReturn)
+	rt.Verify()
+	return ret
+}
+
+func (h *initHarness) approveUpgrade(rt *mock.Runtime, oldCode, newCode cid.Cid, expiration abi.ChainEpoch) {
+	rt.SetCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
+	rt.ExpectValidateCallerAddr(builtin.SystemActorAddr)
+	ret := rt.Call(h.ApproveUpgrade, &init_.ApproveUpgradeParams{OldCodeCID: oldCode, NewCodeCID: newCode, Expiration: expiration})
+	assert.Nil(h.t, ret)
+	rt.Verify()
+}
+
+func (h *initHarness) authorizeUpgrade(rt *mock.Runtime, newCode cid.Cid) {
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.AuthorizeUpgrade, &init_.AuthorizeUpgradeParams{NewCodeCID: newCode})
+	assert.Nil(h.t, ret)
+	rt.Verify()
+}
@@ -3,6 +3,7 @@ package init
 import (
 	addr "github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/abi"
+	cid "github.com/ipfs/go-cid"
 	cbg "github.com/whyrusleeping/cbor-gen"
 
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
@@ -10,8 +11,9 @@ import (
 )
 
 type StateSummary struct {
-	AddrIDs map[addr.Address]abi.ActorID
-	NextID  abi.ActorID
+	AddrIDs     map[addr.Address]abi.ActorID
+	NextID      abi.ActorID
+	NetworkName string
 }
 
 // Checks internal invariants of init state.
@@ -50,8 +52,75 @@ func CheckStateInvariants(st *State, store adt.Store) (*StateSummary, *builtin.M
 		return nil, nil, err
 	}
 
+	robustAddrs, err := adt.AsMap(store, st.RobustAddressMap)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	robustCount := 0
+	var robust addr.Address
+	if err = robustAddrs.ForEach(&robust, func(key string) error {
+		actorId, err := abi.ParseUIntKey(key)
+		if err != nil {
+			return err
+		}
+		robustCount++
+
+		keyAddr, found := reverse[abi.ActorID(actorId)]
+		acc.Require(found, "robust address %v for ID %d has no forward mapping", robust, actorId)
+		acc.Require(keyAddr == robust, "robust address %v for ID %d does not match forward mapping from %v", robust, actorId, keyAddr)
+		return nil
+	}); err != nil {
+		return nil, nil, err
+	}
+	acc.Require(robustCount == len(reverse), "robust address map has %d entries, expected %d", robustCount, len(reverse))
+
+	tombstones, err := adt.AsMap(store, st.Tombstones)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var tombstone Tombstone
+	if err = tombstones.ForEach(&tombstone, func(key string) error {
+		keyAddr, err := addr.NewFromBytes([]byte(key))
+		if err != nil {
+			return err
+		}
+
+		acc.Require(keyAddr.Protocol() != addr.ID, "tombstone key %v is an ID address", keyAddr)
+		_, found := addrs[keyAddr]
+		acc.Require(!found, "tombstoned address %v is still mapped", keyAddr)
+		_, found = reverse[tombstone.ID]
+		acc.Require(!found, "tombstoned ID %d for %v is still mapped", tombstone.ID, keyAddr)
+		acc.Require(tombstone.ID >= builtin.FirstNonSingletonActorId, "unexpected singleton ID %d in tombstone for %v", tombstone.ID, keyAddr)
+		acc.Require(tombstone.ID < st.NextID, "tombstone ID %d for %v not yet allocated", tombstone.ID, keyAddr)
+		acc.Require(tombstone.DeletionEpoch >= 0, "tombstone for %v has negative deletion epoch %d", keyAddr, tombstone.DeletionEpoch)
+		return nil
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	execCodes := map[cid.Cid]bool{}
+	for _, perm := range st.ExecAllowlist {
+		acc.Require(perm.CodeCID.Defined(), "exec allowlist has undefined code")
+		acc.Require(!execCodes[perm.CodeCID], "exec allowlist has duplicate code %v", perm.CodeCID)
+		execCodes[perm.CodeCID] = true
+		for _, caller := range perm.Callers {
+			acc.Require(caller.Defined(), "exec allowlist for code %v has undefined caller code", perm.CodeCID)
+		}
+	}
+
+	upgradeCodes := map[cid.Cid]bool{}
+	for _, approval := range st.UpgradeApprovals {
+		acc.Require(approval.OldCodeCID.Defined() && approval.NewCodeCID.Defined(), "upgrade approval has undefined code")
+		acc.Require(!approval.OldCodeCID.Equals(approval.NewCodeCID), "upgrade approval for code %v to itself", approval.OldCodeCID)
+		acc.Require(!upgradeCodes[approval.OldCodeCID], "upgrade approvals have duplicate code %v", approval.OldCodeCID)
+		upgradeCodes[approval.OldCodeCID] = true
+	}
+
 	return &StateSummary{
-		AddrIDs: addrs,
-		NextID:  st.NextID,
+		AddrIDs:     addrs,
+		NextID:      st.NextID,
+		NetworkName: st.NetworkName,
 	}, acc, nil
 }
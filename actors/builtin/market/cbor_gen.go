@@ -7,13 +7,15 @@ import (
 	"io"
 
 	abi "github.com/filecoin-project/go-state-types/abi"
+	market "github.com/filecoin-project/specs-actors/actors/builtin/market"
+	acbor "github.com/filecoin-project/specs-actors/v2/actors/util/cbor"
 	cbg "github.com/whyrusleeping/cbor-gen"
 	xerrors "golang.org/x/xerrors"
 )
 
 var _ = xerrors.Errorf
 
-var lengthBufState = []byte{139}
+var lengthBufState = []byte{140}
 
 func (t *State) MarshalCBOR(w io.Writer) error {
 	if t == nil {
@@ -93,6 +95,13 @@ func (t *State) MarshalCBOR(w io.Writer) error {
 	if err := t.TotalClientStorageFee.MarshalCBOR(w); err != nil {
 		return err
 	}
+
+	// t.DealsByProvider (cid.Cid) (struct)
+
+	if err := cbg.WriteCidBuf(scratch, w, t.DealsByProvider); err != nil {
+		return xerrors.Errorf("failed to write cid field t.DealsByProvider: %w", err)
+	}
+
 	return nil
 }
 
@@ -104,14 +113,14 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "market.State", "")
 	}
 	if maj != cbg.MajArray {
-		return fmt.Errorf("cbor input should be of type array")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "market.State", "")
 	}
 
-	if extra != 11 {
-		return fmt.Errorf("cbor input had wrong number of fields")
+	if extra != 12 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "market.State", "")
 	}
 
 	// t.Proposals (cid.Cid) (struct)
@@ -120,7 +129,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 		c, err := cbg.ReadCid(br)
 		if err != nil {
-			return xerrors.Errorf("failed to read cid field t.Proposals: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read cid field t.Proposals: %w", err), "market.State", "Proposals")
 		}
 
 		t.Proposals = c
@@ -132,7 +141,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 		c, err := cbg.ReadCid(br)
 		if err != nil {
-			return xerrors.Errorf("failed to read cid field t.States: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read cid field t.States: %w", err), "market.State", "States")
 		}
 
 		t.States = c
@@ -144,7 +153,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 		c, err := cbg.ReadCid(br)
 		if err != nil {
-			return xerrors.Errorf("failed to read cid field t.PendingProposals: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read cid field t.PendingProposals: %w", err), "market.State", "PendingProposals")
 		}
 
 		t.PendingProposals = c
@@ -156,7 +165,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 		c, err := cbg.ReadCid(br)
 		if err != nil {
-			return xerrors.Errorf("failed to read cid field t.EscrowTable: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read cid field t.EscrowTable: %w", err), "market.State", "EscrowTable")
 		}
 
 		t.EscrowTable = c
@@ -168,7 +177,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 		c, err := cbg.ReadCid(br)
 		if err != nil {
-			return xerrors.Errorf("failed to read cid field t.LockedTable: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read cid field t.LockedTable: %w", err), "market.State", "LockedTable")
 		}
 
 		t.LockedTable = c
@@ -180,10 +189,10 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "market.State", "NextID")
 		}
 		if maj != cbg.MajUnsignedInt {
-			return fmt.Errorf("wrong type for uint64 field")
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "market.State", "NextID")
 		}
 		t.NextID = abi.DealID(extra)
 
@@ -194,7 +203,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 		c, err := cbg.ReadCid(br)
 		if err != nil {
-			return xerrors.Errorf("failed to read cid field t.DealOpsByEpoch: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read cid field t.DealOpsByEpoch: %w", err), "market.State", "DealOpsByEpoch")
 		}
 
 		t.DealOpsByEpoch = c
@@ -205,22 +214,22 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 		var extraI int64
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "market.State", "LastCron")
 		}
 		switch maj {
 		case cbg.MajUnsignedInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 positive overflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "market.State", "LastCron")
 			}
 		case cbg.MajNegativeInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 negative oveflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "market.State", "LastCron")
 			}
 			extraI = -1 - extraI
 		default:
-			return fmt.Errorf("wrong type for int64 field: %d", maj)
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "market.State", "LastCron")
 		}
 
 		t.LastCron = abi.ChainEpoch(extraI)
@@ -230,7 +239,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.TotalClientLockedCollateral.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.TotalClientLockedCollateral: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.TotalClientLockedCollateral: %w", err), "market.State", "TotalClientLockedCollateral")
 		}
 
 	}
@@ -239,7 +248,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.TotalProviderLockedCollateral.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.TotalProviderLockedCollateral: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.TotalProviderLockedCollateral: %w", err), "market.State", "TotalProviderLockedCollateral")
 		}
 
 	}
@@ -248,7 +257,112 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.TotalClientStorageFee.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.TotalClientStorageFee: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.TotalClientStorageFee: %w", err), "market.State", "TotalClientStorageFee")
+		}
+
+	}
+	// t.DealsByProvider (cid.Cid) (struct)
+
+	{
+
+		c, err := cbg.ReadCid(br)
+		if err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read cid field t.DealsByProvider: %w", err), "market.State", "DealsByProvider")
+		}
+
+		t.DealsByProvider = c
+
+	}
+	return nil
+}
+
+var lengthBufPublishStorageDealsAggregateParams = []byte{130}
+
+func (t *PublishStorageDealsAggregateParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufPublishStorageDealsAggregateParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Deals ([]market.DealProposal) (slice)
+	if len(t.Deals) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Deals was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Deals))); err != nil {
+		return err
+	}
+	for _, v := range t.Deals {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+
+	// t.ClientSignature (crypto.Signature) (struct)
+	if err := t.ClientSignature.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *PublishStorageDealsAggregateParams) UnmarshalCBOR(r io.Reader) error {
+	*t = PublishStorageDealsAggregateParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "market.PublishStorageDealsAggregateParams", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "market.PublishStorageDealsAggregateParams", "")
+	}
+
+	if extra != 2 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "market.PublishStorageDealsAggregateParams", "")
+	}
+
+	// t.Deals ([]market.DealProposal) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "market.PublishStorageDealsAggregateParams", "Deals")
+	}
+
+	if extra > cbg.MaxLength {
+		return acbor.WrapDecodeError(fmt.Errorf("t.Deals: array too large (%d)", extra), "market.PublishStorageDealsAggregateParams", "Deals")
+	}
+
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("expected cbor array"), "market.PublishStorageDealsAggregateParams", "Deals")
+	}
+
+	if extra > 0 {
+		t.Deals = make([]market.DealProposal, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v market.DealProposal
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(err, "market.PublishStorageDealsAggregateParams", fmt.Sprintf("Deals[%d]", i))
+		}
+
+		t.Deals[i] = v
+	}
+
+	// t.ClientSignature (crypto.Signature) (struct)
+
+	{
+
+		if err := t.ClientSignature.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.ClientSignature: %w", err), "market.PublishStorageDealsAggregateParams", "ClientSignature")
 		}
 
 	}
@@ -295,14 +409,14 @@ func (t *VerifyDealsForActivationReturn) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "market.VerifyDealsForActivationReturn", "")
 	}
 	if maj != cbg.MajArray {
-		return fmt.Errorf("cbor input should be of type array")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "market.VerifyDealsForActivationReturn", "")
 	}
 
 	if extra != 3 {
-		return fmt.Errorf("cbor input had wrong number of fields")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "market.VerifyDealsForActivationReturn", "")
 	}
 
 	// t.DealWeight (big.Int) (struct)
@@ -310,7 +424,7 @@ func (t *VerifyDealsForActivationReturn) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.DealWeight.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.DealWeight: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.DealWeight: %w", err), "market.VerifyDealsForActivationReturn", "DealWeight")
 		}
 
 	}
@@ -319,7 +433,7 @@ func (t *VerifyDealsForActivationReturn) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.VerifiedDealWeight.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.VerifiedDealWeight: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.VerifiedDealWeight: %w", err), "market.VerifyDealsForActivationReturn", "VerifiedDealWeight")
 		}
 
 	}
@@ -329,10 +443,10 @@ func (t *VerifyDealsForActivationReturn) UnmarshalCBOR(r io.Reader) error {
 
 		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "market.VerifyDealsForActivationReturn", "DealSpace")
 		}
 		if maj != cbg.MajUnsignedInt {
-			return fmt.Errorf("wrong type for uint64 field")
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "market.VerifyDealsForActivationReturn", "DealSpace")
 		}
 		t.DealSpace = uint64(extra)
 
@@ -340,6 +454,360 @@ func (t *VerifyDealsForActivationReturn) UnmarshalCBOR(r io.Reader) error {
 	return nil
 }
 
+var lengthBufGetDealsParams = []byte{129}
+
+func (t *GetDealsParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufGetDealsParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.DealIDs ([]abi.DealID) (slice)
+	if len(t.DealIDs) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.DealIDs was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.DealIDs))); err != nil {
+		return err
+	}
+	for _, v := range t.DealIDs {
+		if err := cbg.CborWriteHeader(w, cbg.MajUnsignedInt, uint64(v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *GetDealsParams) UnmarshalCBOR(r io.Reader) error {
+	*t = GetDealsParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "market.GetDealsParams", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "market.GetDealsParams", "")
+	}
+
+	if extra != 1 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "market.GetDealsParams", "")
+	}
+
+	// t.DealIDs ([]abi.DealID) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "market.GetDealsParams", "DealIDs")
+	}
+
+	if extra > cbg.MaxLength {
+		return acbor.WrapDecodeError(fmt.Errorf("t.DealIDs: array too large (%d)", extra), "market.GetDealsParams", "DealIDs")
+	}
+
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("expected cbor array"), "market.GetDealsParams", "DealIDs")
+	}
+
+	if extra > 0 {
+		t.DealIDs = make([]abi.DealID, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		maj, val, err := cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read uint64 for t.DealIDs slice: %w", err), "market.GetDealsParams", fmt.Sprintf("DealIDs[%d]", i))
+		}
+
+		if maj != cbg.MajUnsignedInt {
+			return acbor.WrapDecodeError(xerrors.Errorf("value read for array t.DealIDs was not a uint, instead got %d", maj), "market.GetDealsParams", fmt.Sprintf("DealIDs[%d]", i))
+		}
+
+		t.DealIDs[i] = abi.DealID(val)
+	}
+
+	return nil
+}
+
+var lengthBufGetDealsReturn = []byte{129}
+
+func (t *GetDealsReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufGetDealsReturn); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Deals ([]market.DealInfo) (slice)
+	if len(t.Deals) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Deals was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Deals))); err != nil {
+		return err
+	}
+	for _, v := range t.Deals {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *GetDealsReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = GetDealsReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "market.GetDealsReturn", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "market.GetDealsReturn", "")
+	}
+
+	if extra != 1 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "market.GetDealsReturn", "")
+	}
+
+	// t.Deals ([]market.DealInfo) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "market.GetDealsReturn", "Deals")
+	}
+
+	if extra > cbg.MaxLength {
+		return acbor.WrapDecodeError(fmt.Errorf("t.Deals: array too large (%d)", extra), "market.GetDealsReturn", "Deals")
+	}
+
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("expected cbor array"), "market.GetDealsReturn", "Deals")
+	}
+
+	if extra > 0 {
+		t.Deals = make([]DealInfo, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v DealInfo
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(err, "market.GetDealsReturn", fmt.Sprintf("Deals[%d]", i))
+		}
+
+		t.Deals[i] = v
+	}
+
+	return nil
+}
+
+var lengthBufGetDealsByProviderParams = []byte{131}
+
+func (t *GetDealsByProviderParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufGetDealsByProviderParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Provider (address.Address) (struct)
+	if err := t.Provider.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Start (abi.DealID) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Start)); err != nil {
+		return err
+	}
+
+	// t.Limit (uint64) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Limit)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (t *GetDealsByProviderParams) UnmarshalCBOR(r io.Reader) error {
+	*t = GetDealsByProviderParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "market.GetDealsByProviderParams", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "market.GetDealsByProviderParams", "")
+	}
+
+	if extra != 3 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "market.GetDealsByProviderParams", "")
+	}
+
+	// t.Provider (address.Address) (struct)
+
+	{
+
+		if err := t.Provider.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Provider: %w", err), "market.GetDealsByProviderParams", "Provider")
+		}
+
+	}
+	// t.Start (abi.DealID) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return acbor.WrapDecodeError(err, "market.GetDealsByProviderParams", "Start")
+		}
+		if maj != cbg.MajUnsignedInt {
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "market.GetDealsByProviderParams", "Start")
+		}
+		t.Start = abi.DealID(extra)
+
+	}
+	// t.Limit (uint64) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return acbor.WrapDecodeError(err, "market.GetDealsByProviderParams", "Limit")
+		}
+		if maj != cbg.MajUnsignedInt {
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "market.GetDealsByProviderParams", "Limit")
+		}
+		t.Limit = uint64(extra)
+
+	}
+	return nil
+}
+
+var lengthBufGetDealsByProviderReturn = []byte{130}
+
+func (t *GetDealsByProviderReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufGetDealsByProviderReturn); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Deals ([]market.DealInfo) (slice)
+	if len(t.Deals) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Deals was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Deals))); err != nil {
+		return err
+	}
+	for _, v := range t.Deals {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+
+	// t.More (bool) (bool)
+	if err := cbg.WriteBool(w, t.More); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *GetDealsByProviderReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = GetDealsByProviderReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "market.GetDealsByProviderReturn", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "market.GetDealsByProviderReturn", "")
+	}
+
+	if extra != 2 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "market.GetDealsByProviderReturn", "")
+	}
+
+	// t.Deals ([]market.DealInfo) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "market.GetDealsByProviderReturn", "Deals")
+	}
+
+	if extra > cbg.MaxLength {
+		return acbor.WrapDecodeError(fmt.Errorf("t.Deals: array too large (%d)", extra), "market.GetDealsByProviderReturn", "Deals")
+	}
+
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("expected cbor array"), "market.GetDealsByProviderReturn", "Deals")
+	}
+
+	if extra > 0 {
+		t.Deals = make([]DealInfo, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v DealInfo
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(err, "market.GetDealsByProviderReturn", fmt.Sprintf("Deals[%d]", i))
+		}
+
+		t.Deals[i] = v
+	}
+
+	// t.More (bool) (bool)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "market.GetDealsByProviderReturn", "More")
+	}
+	if maj != cbg.MajOther {
+		return acbor.WrapDecodeError(fmt.Errorf("booleans must be major type 7"), "market.GetDealsByProviderReturn", "More")
+	}
+	switch extra {
+	case 20:
+		t.More = false
+	case 21:
+		t.More = true
+	default:
+		return acbor.WrapDecodeError(fmt.Errorf("booleans are either major type 7, value 20 or 21 (got %d)", extra), "market.GetDealsByProviderReturn", "More")
+	}
+	return nil
+}
+
 var lengthBufDealState = []byte{131}
 
 func (t *DealState) MarshalCBOR(w io.Writer) error {
@@ -396,14 +864,14 @@ func (t *DealState) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "market.DealState", "")
 	}
 	if maj != cbg.MajArray {
-		return fmt.Errorf("cbor input should be of type array")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "market.DealState", "")
 	}
 
 	if extra != 3 {
-		return fmt.Errorf("cbor input had wrong number of fields")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "market.DealState", "")
 	}
 
 	// t.SectorStartEpoch (abi.ChainEpoch) (int64)
@@ -411,22 +879,22 @@ func (t *DealState) UnmarshalCBOR(r io.Reader) error {
 		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 		var extraI int64
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "market.DealState", "SectorStartEpoch")
 		}
 		switch maj {
 		case cbg.MajUnsignedInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 positive overflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "market.DealState", "SectorStartEpoch")
 			}
 		case cbg.MajNegativeInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 negative oveflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "market.DealState", "SectorStartEpoch")
 			}
 			extraI = -1 - extraI
 		default:
-			return fmt.Errorf("wrong type for int64 field: %d", maj)
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "market.DealState", "SectorStartEpoch")
 		}
 
 		t.SectorStartEpoch = abi.ChainEpoch(extraI)
@@ -436,22 +904,22 @@ func (t *DealState) UnmarshalCBOR(r io.Reader) error {
 		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 		var extraI int64
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "market.DealState", "LastUpdatedEpoch")
 		}
 		switch maj {
 		case cbg.MajUnsignedInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 positive overflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "market.DealState", "LastUpdatedEpoch")
 			}
 		case cbg.MajNegativeInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 negative oveflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "market.DealState", "LastUpdatedEpoch")
 			}
 			extraI = -1 - extraI
 		default:
-			return fmt.Errorf("wrong type for int64 field: %d", maj)
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "market.DealState", "LastUpdatedEpoch")
 		}
 
 		t.LastUpdatedEpoch = abi.ChainEpoch(extraI)
@@ -461,25 +929,145 @@ func (t *DealState) UnmarshalCBOR(r io.Reader) error {
 		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 		var extraI int64
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "market.DealState", "SlashEpoch")
 		}
 		switch maj {
 		case cbg.MajUnsignedInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 positive overflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "market.DealState", "SlashEpoch")
 			}
 		case cbg.MajNegativeInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 negative oveflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "market.DealState", "SlashEpoch")
 			}
 			extraI = -1 - extraI
 		default:
-			return fmt.Errorf("wrong type for int64 field: %d", maj)
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "market.DealState", "SlashEpoch")
 		}
 
 		t.SlashEpoch = abi.ChainEpoch(extraI)
 	}
 	return nil
 }
+
+var lengthBufDealInfo = []byte{131}
+
+func (t *DealInfo) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufDealInfo); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.DealID (abi.DealID) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.DealID)); err != nil {
+		return err
+	}
+
+	// t.Proposal (market.DealProposal) (struct)
+	if err := t.Proposal.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.State (market.DealState) (struct)
+	if err := t.State.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *DealInfo) UnmarshalCBOR(r io.Reader) error {
+	*t = DealInfo{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "market.DealInfo", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "market.DealInfo", "")
+	}
+
+	if extra != 3 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "market.DealInfo", "")
+	}
+
+	// t.DealID (abi.DealID) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return acbor.WrapDecodeError(err, "market.DealInfo", "DealID")
+		}
+		if maj != cbg.MajUnsignedInt {
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "market.DealInfo", "DealID")
+		}
+		t.DealID = abi.DealID(extra)
+
+	}
+	// t.Proposal (market.DealProposal) (struct)
+
+	{
+
+		if err := t.Proposal.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Proposal: %w", err), "market.DealInfo", "Proposal")
+		}
+
+	}
+	// t.State (market.DealState) (struct)
+
+	{
+
+		if err := t.State.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.State: %w", err), "market.DealInfo", "State")
+		}
+
+	}
+	return nil
+}
+
+func (t *State) MaxEncodedLength() int64 {
+	return 4028
+}
+
+func (t *PublishStorageDealsAggregateParams) MaxEncodedLength() int64 {
+	return 75882703
+}
+
+func (t *VerifyDealsForActivationReturn) MaxEncodedLength() int64 {
+	return 270
+}
+
+func (t *GetDealsParams) MaxEncodedLength() int64 {
+	return 73732
+}
+
+func (t *GetDealsReturn) MaxEncodedLength() int64 {
+	return 76193796
+}
+
+func (t *GetDealsByProviderParams) MaxEncodedLength() int64 {
+	return 85
+}
+
+func (t *GetDealsByProviderReturn) MaxEncodedLength() int64 {
+	return 76193797
+}
+
+func (t *DealState) MaxEncodedLength() int64 {
+	return 28
+}
+
+func (t *DealInfo) MaxEncodedLength() int64 {
+	return 9301
+}
@@ -41,6 +41,9 @@ func (a Actor) Exports() []interface{} {
 		7:                         a.OnMinerSectorsTerminate,
 		8:                         a.ComputeDataCommitment,
 		9:                         a.CronTick,
+		10:                        a.GetDeals,
+		11:                        a.GetDealsByProvider,
+		12:                        a.PublishStorageDealsAggregate,
 	}
 }
 
@@ -65,13 +68,14 @@ var _ runtime.VMActor = Actor{}
 func (a Actor) Constructor(rt Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
 	rt.ValidateImmediateCallerIs(builtin.SystemActorAddr)
 
+	// The root of an empty HAMT doesn't depend on its bitwidth, so one serves for all the state's maps.
 	emptyArray, err := adt.MakeEmptyArray(adt.AsStore(rt)).Root()
 	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to create state")
 
-	emptyMap, err := adt.MakeEmptyMap(adt.AsStore(rt)).Root()
+	emptyMap, err := adt.MakeEmptyMapWithBitwidth(adt.AsStore(rt), PendingProposalsHamtBitwidth).Root()
 	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to create state")
 
-	emptyMSet, err := MakeEmptySetMultimap(adt.AsStore(rt)).Root()
+	emptyMSet, err := MakeEmptySetMultimap(adt.AsStore(rt), DealOpsByEpochHamtBitwidth, DealOpsHamtBitwidth).Root()
 	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to create state")
 
 	st := ConstructState(emptyArray, emptyMap, emptyMSet)
@@ -121,6 +125,7 @@ func (a Actor) WithdrawBalance(rt Runtime, params *WithdrawBalanceParams) *abi.E
 
 	code := rt.Send(recipient, builtin.MethodSend, nil, amountExtracted, &builtin.Discard{})
 	builtin.RequireSuccess(rt, code, "failed to send funds")
+	builtin.EmitTransfer(rt, recipient, amountExtracted)
 	return nil
 }
 
@@ -161,16 +166,58 @@ type PublishStorageDealsReturn = market0.PublishStorageDealsReturn
 
 // Publish a new set of storage deals (not yet included in a sector).
 func (a Actor) PublishStorageDeals(rt Runtime, params *PublishStorageDealsParams) *PublishStorageDealsReturn {
-
 	// Deal message must have a From field identical to the provider of all the deals.
 	// This allows us to retain and verify only the client's signature in each deal proposal itself.
+	rt.ValidateImmediateCallerType(builtin.CallerTypesSignable...)
+	return publishStorageDeals(rt, params.Deals, true)
+}
+
+type PublishStorageDealsAggregateParams struct {
+	Deals []DealProposal
+	// Aggregate of the client's BLS signatures of each proposal, in place of a signature in each.
+	ClientSignature crypto.Signature
+}
+
+// Publish a new set of storage deals from a single client, signed by the client with a single aggregate BLS
+// signature, which is verified at less cost than a signature for each deal.
+func (a Actor) PublishStorageDealsAggregate(rt Runtime, params *PublishStorageDealsAggregateParams) *PublishStorageDealsReturn {
 	rt.ValidateImmediateCallerType(builtin.CallerTypesSignable...)
 	if len(params.Deals) == 0 {
 		rt.Abortf(exitcode.ErrIllegalArgument, "empty deals parameter")
 	}
+	if params.ClientSignature.Type != crypto.SigTypeBLS {
+		rt.Abortf(exitcode.ErrIllegalArgument, "aggregate signature must be BLS, was type %d", params.ClientSignature.Type)
+	}
+
+	client := params.Deals[0].Client
+	signers := make([]addr.Address, len(params.Deals))
+	plaintexts := make([][]byte, len(params.Deals))
+	deals := make([]ClientDealProposal, len(params.Deals))
+	for i, proposal := range params.Deals {
+		if proposal.Client != client {
+			rt.Abortf(exitcode.ErrIllegalArgument, "cannot publish deals from different clients with an aggregate signature")
+		}
+		buf := bytes.Buffer{}
+		err := proposal.MarshalCBOR(&buf)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed to marshal proposal %d", i)
+		signers[i] = client
+		plaintexts[i] = buf.Bytes()
+		deals[i] = ClientDealProposal{Proposal: proposal}
+	}
+	err := rt.VerifyAggregateSignature(params.ClientSignature, signers, plaintexts)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "invalid aggregate signature of client %v", client)
+
+	return publishStorageDeals(rt, deals, false)
+}
+
+// Publishes deals, verifying each proposal's client signature unless they have been verified in aggregate.
+func publishStorageDeals(rt Runtime, deals []ClientDealProposal, verifySignatures bool) *PublishStorageDealsReturn {
+	if len(deals) == 0 {
+		rt.Abortf(exitcode.ErrIllegalArgument, "empty deals parameter")
+	}
 
 	// All deals should have the same provider so get worker once
-	providerRaw := params.Deals[0].Proposal.Provider
+	providerRaw := deals[0].Proposal.Provider
 	provider, ok := rt.ResolveAddress(providerRaw)
 	if !ok {
 		rt.Abortf(exitcode.ErrNotFound, "failed to resolve provider address %v", providerRaw)
@@ -182,12 +229,12 @@ func (a Actor) PublishStorageDeals(rt Runtime, params *PublishStorageDealsParams
 		rt.Abortf(exitcode.ErrIllegalArgument, "deal provider is not a StorageMinerActor")
 	}
 
-	_, worker, _ := builtin.RequestMinerControlAddrs(rt, provider)
+	owner, worker, controlAddrs := builtin.RequestMinerControlAddrs(rt, provider)
 	if worker != rt.Caller() {
 		rt.Abortf(exitcode.ErrForbidden, "caller is not provider %v", provider)
 	}
 
-	resolvedAddrs := make(map[addr.Address]addr.Address, len(params.Deals))
+	resolvedAddrs := make(map[addr.Address]addr.Address, len(deals))
 	baselinePower := requestCurrentBaselinePower(rt)
 	networkRawPower, networkQAPower := requestCurrentNetworkPower(rt)
 
@@ -195,13 +242,18 @@ func (a Actor) PublishStorageDeals(rt Runtime, params *PublishStorageDealsParams
 	var st State
 	rt.StateTransaction(&st, func() {
 		msm, err := st.mutator(adt.AsStore(rt)).withPendingProposals(WritePermission).
-			withDealProposals(WritePermission).withDealsByEpoch(WritePermission).withEscrowTable(WritePermission).
-			withLockedTable(WritePermission).build()
+			withDealProposals(WritePermission).withDealsByEpoch(WritePermission).withDealsByProvider(WritePermission).
+			withEscrowTable(WritePermission).withLockedTable(WritePermission).build()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load state")
 
 		// All storage dealProposals will be added in an atomic transaction; this operation will be unrolled if any of them fails.
-		for di, deal := range params.Deals {
-			validateDeal(rt, deal, networkRawPower, networkQAPower, baselinePower)
+		for di, deal := range deals {
+			if verifySignatures {
+				if err := dealProposalIsInternallyValid(rt, deal); err != nil {
+					rt.Abortf(exitcode.ErrIllegalArgument, "Invalid deal proposal: %s", err)
+				}
+			}
+			validateDeal(rt, deal.Proposal, networkRawPower, networkQAPower, baselinePower)
 
 			if deal.Proposal.Provider != provider && deal.Proposal.Provider != providerRaw {
 				rt.Abortf(exitcode.ErrIllegalArgument, "cannot publish deals from different providers at the same time")
@@ -216,6 +268,12 @@ func (a Actor) PublishStorageDeals(rt Runtime, params *PublishStorageDealsParams
 			resolvedAddrs[deal.Proposal.Client] = client
 			deal.Proposal.Client = client
 
+			// A provider may not spend DataCap it controls on deals with itself.
+			if deal.Proposal.VerifiedDeal {
+				err := verifreg.CheckProviderClientSeparation(client, owner, worker, controlAddrs)
+				builtin.RequireNoErr(rt, err, exitcode.ErrForbidden, "invalid verified deal %d", di)
+			}
+
 			err, code := msm.lockClientAndProviderBalances(&deal.Proposal)
 			builtin.RequireNoErr(rt, err, code, "failed to lock balance")
 
@@ -238,12 +296,15 @@ func (a Actor) PublishStorageDeals(rt Runtime, params *PublishStorageDealsParams
 
 			// We should randomize the first epoch for when the deal will be processed so an attacker isn't able to
 			// schedule too many deals for the same tick.
-			processEpoch, err := genRandNextEpoch(rt.CurrEpoch(), &deal.Proposal, rt.GetRandomnessFromBeacon)
+			processEpoch, err := genRandNextEpoch(rt.CurrEpoch(), &deal.Proposal, rt.GetRandomness)
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to generate random process epoch")
 
 			err = msm.dealsByEpoch.Put(processEpoch, id)
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to set deal ops by epoch")
 
+			err = msm.dealsByProvider.Put(provider, id)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to index deal by provider")
+
 			newDealIds = append(newDealIds, id)
 		}
 
@@ -251,7 +312,7 @@ func (a Actor) PublishStorageDeals(rt Runtime, params *PublishStorageDealsParams
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush state")
 	})
 
-	for _, deal := range params.Deals {
+	for _, deal := range deals {
 		// Check VerifiedClient allowed cap and deduct PieceSize from cap.
 		// Either the DealSize is within the available DataCap of the VerifiedClient
 		// or this message will fail. We do not allow a deal that is partially verified.
@@ -273,6 +334,11 @@ func (a Actor) PublishStorageDeals(rt Runtime, params *PublishStorageDealsParams
 		}
 	}
 
+	for i, id := range newDealIds {
+		deal := deals[i].Proposal
+		builtin.EmitDealPublished(rt, id, resolvedAddrs[deal.Client], provider)
+	}
+
 	return &PublishStorageDealsReturn{IDs: newDealIds}
 }
 
@@ -343,7 +409,7 @@ func (a Actor) ActivateDeals(rt Runtime, params *ActivateDealsParams) *abi.Empty
 			_, found, err := msm.dealStates.Get(dealID)
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get state for dealId %d", dealID)
 			if found {
-				rt.Abortf(exitcode.ErrIllegalArgument, "deal %d already included in another sector", dealID)
+				rt.AbortWithData(exitcode.ErrIllegalArgument, builtin.NewDealsFailure(dealID), "deal %d already included in another sector", dealID)
 			}
 
 			proposal, err := getDealProposal(msm.dealProposals, dealID)
@@ -479,10 +545,16 @@ func (a Actor) CronTick(rt Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
 
 		msm, err := st.mutator(adt.AsStore(rt)).withDealStates(WritePermission).
 			withLockedTable(WritePermission).withEscrowTable(WritePermission).withDealsByEpoch(WritePermission).
-			withDealProposals(WritePermission).withPendingProposals(WritePermission).build()
+			withDealProposals(WritePermission).withPendingProposals(WritePermission).
+			withDealsByProvider(WritePermission).build()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load state")
 
+		// Always process at least one epoch, so that the tick makes progress.
+		lastProcessed := st.LastCron
 		for i := st.LastCron + 1; i <= rt.CurrEpoch(); i++ {
+			if i > st.LastCron+1 && rt.GasAvailable() < CronTickGasReserve {
+				break
+			}
 			err = msm.dealsByEpoch.ForEach(i, func(dealID abi.DealID) error {
 				deal, err := getDealProposal(msm.dealProposals, dealID)
 				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get dealId %d", dealID)
@@ -510,6 +582,8 @@ func (a Actor) CronTick(rt Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
 					if err := deleteDealProposalAndState(dealID, msm.dealStates, msm.dealProposals, true, false); err != nil {
 						builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete deal")
 					}
+					err = msm.dealsByProvider.Remove(deal.Provider, dealID)
+					builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to remove deal from provider index")
 
 					pdErr := msm.pendingDeals.Delete(abi.CidKey(dcid))
 					builtin.RequireNoErr(rt, pdErr, exitcode.ErrIllegalState, "failed to delete pending proposal")
@@ -532,6 +606,8 @@ func (a Actor) CronTick(rt Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
 					amountSlashed = big.Add(amountSlashed, slashAmount)
 					err := deleteDealProposalAndState(dealID, msm.dealStates, msm.dealProposals, true, true)
 					builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete deal proposal and states")
+					err = msm.dealsByProvider.Remove(deal.Provider, dealID)
+					builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to remove deal from provider index")
 				} else {
 					AssertMsg(nextEpoch > rt.CurrEpoch() && slashAmount.IsZero(), "deal should not be slashed and should have a schedule for next cron tick"+
 						" as it has not been removed")
@@ -550,6 +626,7 @@ func (a Actor) CronTick(rt Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
 
 			err = msm.dealsByEpoch.RemoveAll(i)
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete deal ops for epoch %v", i)
+			lastProcessed = i
 		}
 
 		// Iterate changes in sorted order to ensure that loads/stores
@@ -567,7 +644,7 @@ func (a Actor) CronTick(rt Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to reinsert deal IDs for epoch %v", epoch)
 		}
 
-		st.LastCron = rt.CurrEpoch()
+		st.LastCron = lastProcessed
 
 		err = msm.commitState()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush state")
@@ -599,13 +676,67 @@ func (a Actor) CronTick(rt Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
 	return nil
 }
 
-func genRandNextEpoch(currEpoch abi.ChainEpoch, deal *DealProposal, rbF func(crypto.DomainSeparationTag, abi.ChainEpoch, []byte) abi.Randomness) (abi.ChainEpoch, error) {
+type GetDealsParams struct {
+	DealIDs []abi.DealID
+}
+
+type GetDealsReturn struct {
+	Deals []DealInfo
+}
+
+// Returns the proposals and states of some deals, omitting those not found (see State.GetDeals).
+func (a Actor) GetDeals(rt Runtime, params *GetDealsParams) *GetDealsReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+	if uint64(len(params.DealIDs)) > DealQueryMax {
+		rt.Abortf(exitcode.ErrIllegalArgument, "too many deals %d, max %d", len(params.DealIDs), DealQueryMax)
+	}
+
+	var st State
+	rt.StateReadonly(&st)
+	deals, err := st.GetDeals(adt.AsStore(rt), params.DealIDs)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get deals")
+	return &GetDealsReturn{Deals: deals}
+}
+
+type GetDealsByProviderParams struct {
+	Provider addr.Address
+	Start    abi.DealID // The lowest deal ID to return.
+	Limit    uint64     // The maximum number of deals to return.
+}
+
+type GetDealsByProviderReturn struct {
+	Deals []DealInfo
+	More  bool // Whether deals follow those returned, from the ID after the last.
+}
+
+// Returns a page of the proposals and states of a provider's deals, in order of deal ID
+// (see State.GetDealsByProvider).
+func (a Actor) GetDealsByProvider(rt Runtime, params *GetDealsByProviderParams) *GetDealsByProviderReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+	if params.Limit == 0 || params.Limit > DealQueryMax {
+		rt.Abortf(exitcode.ErrIllegalArgument, "limit %d must be positive and at most %d", params.Limit, DealQueryMax)
+	}
+
+	// Deals are indexed by the provider's ID address, so a provider which cannot be resolved has none.
+	provider, ok := rt.ResolveAddress(params.Provider)
+	if !ok {
+		return &GetDealsByProviderReturn{Deals: []DealInfo{}}
+	}
+
+	var st State
+	rt.StateReadonly(&st)
+	deals, more, err := st.GetDealsByProvider(adt.AsStore(rt), provider, params.Start, params.Limit)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get deals of provider %v", provider)
+	return &GetDealsByProviderReturn{Deals: deals, More: more}
+}
+
+func genRandNextEpoch(currEpoch abi.ChainEpoch, deal *DealProposal, rbF func(runtime.RandomnessSource, crypto.DomainSeparationTag, abi.ChainEpoch, []byte) abi.Randomness) (abi.ChainEpoch, error) {
 	buf := bytes.Buffer{}
 	if err := deal.MarshalCBOR(&buf); err != nil {
 		return epochUndefined, xerrors.Errorf("failed to marshal proposal: %w", err)
 	}
 
-	rb := rbF(crypto.DomainSeparationTag_MarketDealCronSeed, currEpoch-1, buf.Bytes())
+	rb := rbF(runtime.RandomnessBeacon, crypto.DomainSeparationTag_MarketDealCronSeed, currEpoch-1, buf.Bytes())
 
 	// generate a random epoch in [baseEpoch, baseEpoch + DealUpdatesInterval)
 	offset := binary.BigEndian.Uint64(rb)
@@ -697,13 +828,7 @@ func validateDealCanActivate(proposal *DealProposal, minerAddr addr.Address, sec
 	return nil
 }
 
-func validateDeal(rt Runtime, deal ClientDealProposal, networkRawPower, networkQAPower, baselinePower abi.StoragePower) {
-	if err := dealProposalIsInternallyValid(rt, deal); err != nil {
-		rt.Abortf(exitcode.ErrIllegalArgument, "Invalid deal proposal: %s", err)
-	}
-
-	proposal := deal.Proposal
-
+func validateDeal(rt Runtime, proposal DealProposal, networkRawPower, networkQAPower, baselinePower abi.StoragePower) {
 	if len(proposal.Label) > DealMaxLabelSize {
 		rt.Abortf(exitcode.ErrIllegalArgument, "deal label can be at most %d bytes, is %d", DealMaxLabelSize, len(proposal.Label))
 	}
@@ -730,7 +855,9 @@ func validateDeal(rt Runtime, deal ClientDealProposal, networkRawPower, networkQ
 
 	minDuration, maxDuration := DealDurationBounds(proposal.PieceSize)
 	if proposal.Duration() < minDuration || proposal.Duration() > maxDuration {
-		rt.Abortf(exitcode.ErrIllegalArgument, "Deal duration out of bounds.")
+		rt.Abortf(exitcode.ErrIllegalArgument, "Deal duration %d (%v) out of bounds [%d, %d] (%v to %v).",
+			proposal.Duration(), builtin.EpochsDuration(proposal.Duration()), minDuration, maxDuration,
+			builtin.EpochsDuration(minDuration), builtin.EpochsDuration(maxDuration))
 	}
 
 	minPrice, maxPrice := DealPricePerEpochBounds(proposal.PieceSize, proposal.Duration())
@@ -791,16 +918,11 @@ func getDealProposal(proposals *DealArray, dealID abi.DealID) (*DealProposal, er
 
 // Requests the current epoch target block reward from the reward actor.
 func requestCurrentBaselinePower(rt Runtime) abi.StoragePower {
-	var ret reward.ThisEpochRewardReturn
-	code := rt.Send(builtin.RewardActorAddr, builtin.MethodsReward.ThisEpochReward, nil, big.Zero(), &ret)
-	builtin.RequireSuccess(rt, code, "failed to check epoch baseline power")
-	return ret.ThisEpochBaselinePower
+	return reward.NewClient(rt).ThisEpochReward().ThisEpochBaselinePower
 }
 
 // Requests the current network total power and pledge from the power actor.
 func requestCurrentNetworkPower(rt Runtime) (rawPower, qaPower abi.StoragePower) {
-	var pwr power.CurrentTotalPowerReturn
-	code := rt.Send(builtin.StoragePowerActorAddr, builtin.MethodsPower.CurrentTotalPower, nil, big.Zero(), &pwr)
-	builtin.RequireSuccess(rt, code, "failed to check current power")
+	pwr := power.NewClient(rt).CurrentTotalPower()
 	return pwr.RawBytePower, pwr.QualityAdjPower
 }
@@ -8,6 +8,7 @@ import (
 	"golang.org/x/xerrors"
 
 	. "github.com/filecoin-project/specs-actors/v2/actors/util"
+	"github.com/filecoin-project/specs-actors/v2/actors/util/math"
 )
 
 // if the returned error is not nil, the Runtime will exit with the returned exit code.
@@ -38,15 +39,22 @@ func (m *marketStateMutation) unlockBalance(addr addr.Address, amount abi.TokenA
 		return xerrors.Errorf("subtracting from locked balance: %v", err)
 	}
 
+	var total *abi.TokenAmount
 	switch lockReason {
 	case ClientCollateral:
-		m.totalClientLockedCollateral = big.Sub(m.totalClientLockedCollateral, amount)
+		total = &m.totalClientLockedCollateral
 	case ClientStorageFee:
-		m.totalClientStorageFee = big.Sub(m.totalClientStorageFee, amount)
+		total = &m.totalClientStorageFee
 	case ProviderCollateral:
-		m.totalProviderLockedCollateral = big.Sub(m.totalProviderLockedCollateral, amount)
+		total = &m.totalProviderLockedCollateral
+	default:
+		return nil
 	}
-
+	remaining, ok := math.CheckedSub(*total, amount)
+	if !ok {
+		return xerrors.Errorf("unlocking %v exceeds total %v locked for reason %d", amount, *total, lockReason)
+	}
+	*total = remaining
 	return nil
 }
 
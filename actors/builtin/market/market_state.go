@@ -3,6 +3,7 @@ package market
 import (
 	"bytes"
 
+	addr "github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
 	"github.com/filecoin-project/go-state-types/exitcode"
@@ -11,6 +12,7 @@ import (
 
 	. "github.com/filecoin-project/specs-actors/v2/actors/util"
 	"github.com/filecoin-project/specs-actors/v2/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/v2/actors/util/math"
 )
 
 const epochUndefined = abi.ChainEpoch(-1)
@@ -30,6 +32,16 @@ const (
 	ProviderCollateral
 )
 
+// Bitwidths of the state's HAMTs, which trade off the depth of each map against the size of its nodes.
+// Changing a bitwidth requires the existing state root of the map to be migrated (see adt.MigrateMapBitwidth).
+// The AMTs of proposals and deal states have no such parameter: the AMT implementation supports a single width.
+const (
+	PendingProposalsHamtBitwidth = adt.DefaultHamtBitwidth
+	DealOpsByEpochHamtBitwidth   = adt.DefaultHamtBitwidth
+	DealOpsHamtBitwidth          = adt.DefaultHamtBitwidth // Bitwidth of each epoch's set of deals
+	DealsByProviderHamtBitwidth  = adt.DefaultHamtBitwidth
+)
+
 type State struct {
 	Proposals cid.Cid // AMT[DealID]DealProposal
 	States    cid.Cid // AMT[DealID]DealState
@@ -58,6 +70,9 @@ type State struct {
 	TotalProviderLockedCollateral abi.TokenAmount
 	// Total storage fee that is locked in escrow -> unlocked when payments are made
 	TotalClientStorageFee abi.TokenAmount
+
+	// Index of the deals in Proposals by provider, maintained as deals are published and removed.
+	DealsByProvider cid.Cid // ProviderDealIndex, HAMT[Address]AMT[DealID]
 }
 
 func ConstructState(emptyArrayCid, emptyMapCid, emptyMSetCid cid.Cid) *State {
@@ -74,9 +89,85 @@ func ConstructState(emptyArrayCid, emptyMapCid, emptyMSetCid cid.Cid) *State {
 		TotalClientLockedCollateral:   abi.NewTokenAmount(0),
 		TotalProviderLockedCollateral: abi.NewTokenAmount(0),
 		TotalClientStorageFee:         abi.NewTokenAmount(0),
+
+		DealsByProvider: emptyMapCid,
 	}
 }
 
+////////////////////////////////////////////////////////////////////////////////
+// Deal queries
+////////////////////////////////////////////////////////////////////////////////
+
+// A deal's proposal with its state.
+// The state of a deal which has not yet been activated has undefined (-1) epochs.
+type DealInfo struct {
+	DealID   abi.DealID
+	Proposal DealProposal
+	State    DealState
+}
+
+// Returns the proposals and states of some deals, in the order given.
+// Deals which are not found are omitted: a deal is removed from state once it has expired, been slashed, or timed
+// out before activation.
+func (s *State) GetDeals(store adt.Store, dealIDs []abi.DealID) ([]DealInfo, error) {
+	proposals, err := AsDealProposalArray(store, s.Proposals)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to load deal proposals: %w", err)
+	}
+	states, err := AsDealStateArray(store, s.States)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to load deal states: %w", err)
+	}
+
+	deals := make([]DealInfo, 0, len(dealIDs))
+	for _, dealID := range dealIDs {
+		proposal, found, err := proposals.Get(dealID)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to load deal proposal %d: %w", dealID, err)
+		}
+		if !found {
+			continue
+		}
+		state, _, err := states.Get(dealID)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to load deal state %d: %w", dealID, err)
+		}
+		deals = append(deals, DealInfo{DealID: dealID, Proposal: *proposal, State: *state})
+	}
+	return deals, nil
+}
+
+// Returns the proposals and states of a provider's deals in order of deal ID, from a starting deal ID and up to a
+// limited number, along with whether more deals follow those returned. A subsequent page may be fetched by
+// starting from the deal ID following the last one returned.
+// The provider's deals are read in order from the starting ID, and only as many as the limit and one more.
+func (s *State) GetDealsByProvider(store adt.Store, provider addr.Address, start abi.DealID, limit uint64) ([]DealInfo, bool, error) {
+	index, err := AsProviderDealIndex(store, s.DealsByProvider)
+	if err != nil {
+		return nil, false, xerrors.Errorf("failed to load deals by provider: %w", err)
+	}
+
+	var dealIDs []abi.DealID
+	more := false
+	stopErr := xerrors.New("stop")
+	if err = index.ForEachFrom(provider, start, func(dealID abi.DealID) error {
+		if uint64(len(dealIDs)) == limit {
+			more = true
+			return stopErr
+		}
+		dealIDs = append(dealIDs, dealID)
+		return nil
+	}); err != nil && err != stopErr {
+		return nil, false, xerrors.Errorf("failed to iterate deals of provider %v: %w", provider, err)
+	}
+
+	deals, err := s.GetDeals(store, dealIDs)
+	if err != nil {
+		return nil, false, err
+	}
+	return deals, more, nil
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // Deal state operations
 ////////////////////////////////////////////////////////////////////////////////
@@ -167,7 +258,10 @@ func (m *marketStateMutation) processDealInitTimedOut(rt Runtime, deal *DealProp
 	}
 
 	amountSlashed := CollateralPenaltyForDealActivationMissed(deal.ProviderCollateral)
-	amountRemaining := big.Sub(deal.ProviderBalanceRequirement(), amountSlashed)
+	amountRemaining, ok := math.CheckedSub(deal.ProviderBalanceRequirement(), amountSlashed)
+	if !ok {
+		rt.Abortf(exitcode.ErrIllegalState, "slashed %v exceeds provider balance requirement %v", amountSlashed, deal.ProviderBalanceRequirement())
+	}
 
 	if err := m.slashBalance(deal.Provider, amountSlashed, ProviderCollateral); err != nil {
 		rt.Abortf(exitcode.ErrIllegalState, "failed to slash balance: %s", err)
@@ -264,6 +358,9 @@ type marketStateMutation struct {
 	dpePermit    MarketStateMutationPermission
 	dealsByEpoch *SetMultimap
 
+	dbpPermit       MarketStateMutationPermission
+	dealsByProvider *ProviderDealIndex
+
 	lockedPermit                  MarketStateMutationPermission
 	lockedTable                   *adt.BalanceTable
 	totalClientLockedCollateral   abi.TokenAmount
@@ -314,7 +411,7 @@ func (m *marketStateMutation) build() (*marketStateMutation, error) {
 	}
 
 	if m.pendingPermit != Invalid {
-		pending, err := adt.AsMap(m.store, m.st.PendingProposals)
+		pending, err := adt.AsMapWithBitwidth(m.store, m.st.PendingProposals, PendingProposalsHamtBitwidth)
 		if err != nil {
 			return nil, xerrors.Errorf("failed to load pending proposals: %w", err)
 		}
@@ -322,13 +419,21 @@ func (m *marketStateMutation) build() (*marketStateMutation, error) {
 	}
 
 	if m.dpePermit != Invalid {
-		dbe, err := AsSetMultimap(m.store, m.st.DealOpsByEpoch)
+		dbe, err := AsSetMultimap(m.store, m.st.DealOpsByEpoch, DealOpsByEpochHamtBitwidth, DealOpsHamtBitwidth)
 		if err != nil {
 			return nil, xerrors.Errorf("failed to load deals by epoch: %w", err)
 		}
 		m.dealsByEpoch = dbe
 	}
 
+	if m.dbpPermit != Invalid {
+		dbp, err := AsProviderDealIndex(m.store, m.st.DealsByProvider)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to load deals by provider: %w", err)
+		}
+		m.dealsByProvider = dbp
+	}
+
 	m.nextDealId = m.st.NextID
 
 	return m, nil
@@ -364,6 +469,11 @@ func (m *marketStateMutation) withDealsByEpoch(permit MarketStateMutationPermiss
 	return m
 }
 
+func (m *marketStateMutation) withDealsByProvider(permit MarketStateMutationPermission) *marketStateMutation {
+	m.dbpPermit = permit
+	return m
+}
+
 func (m *marketStateMutation) commitState() error {
 	var err error
 	if m.proposalPermit == WritePermission {
@@ -405,6 +515,12 @@ func (m *marketStateMutation) commitState() error {
 		}
 	}
 
+	if m.dbpPermit == WritePermission {
+		if m.st.DealsByProvider, err = m.dealsByProvider.Root(); err != nil {
+			return xerrors.Errorf("failed to flush deals by provider: %w", err)
+		}
+	}
+
 	m.st.NextID = m.nextDealId
 	return nil
 }
@@ -23,7 +23,9 @@ import (
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/power"
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/reward"
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/verifreg"
+	"github.com/filecoin-project/specs-actors/v2/actors/runtime"
 	"github.com/filecoin-project/specs-actors/v2/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/v2/support/fixtures"
 	"github.com/filecoin-project/specs-actors/v2/support/mock"
 	tutil "github.com/filecoin-project/specs-actors/v2/support/testing"
 
@@ -41,16 +43,18 @@ func mustCbor(o cbor.Marshaler) []byte {
 }
 
 func TestExports(t *testing.T) {
+	t.Parallel()
 	mock.CheckActorExports(t, market.Actor{})
 }
 
 func TestRemoveAllError(t *testing.T) {
+	t.Parallel()
 	marketActor := tutil.NewIDAddr(t, 100)
-	builder := mock.NewBuilder(context.Background(), marketActor)
+	builder := mock.NewBuilder(context.Background(), marketActor).WithStateInvariants(market.CheckStateInvariants)
 	rt := builder.Build(t)
 	store := adt.AsStore(rt)
 
-	smm := market.MakeEmptySetMultimap(store)
+	smm := market.MakeEmptySetMultimap(store, market.DealOpsByEpochHamtBitwidth, market.DealOpsHamtBitwidth)
 
 	if err := smm.RemoveAll(42); err != nil {
 		t.Fatalf("expected no error, got: %s", err)
@@ -58,6 +62,7 @@ func TestRemoveAllError(t *testing.T) {
 }
 
 func TestMarketActor(t *testing.T) {
+	t.Parallel()
 	owner := tutil.NewIDAddr(t, 101)
 	provider := tutil.NewIDAddr(t, 102)
 	worker := tutil.NewIDAddr(t, 103)
@@ -69,7 +74,7 @@ func TestMarketActor(t *testing.T) {
 	t.Run("simple construction", func(t *testing.T) {
 		actor := market.Actor{}
 		receiver := tutil.NewIDAddr(t, 100)
-		builder := mock.NewBuilder(context.Background(), receiver).
+		builder := mock.NewBuilder(context.Background(), receiver).WithStateInvariants(market.CheckStateInvariants).
 			WithCaller(builtin.SystemActorAddr, builtin.InitActorCodeID)
 
 		rt := builder.Build(t)
@@ -88,7 +93,7 @@ func TestMarketActor(t *testing.T) {
 		emptyArray, err := adt.MakeEmptyArray(store).Root()
 		assert.NoError(t, err)
 
-		emptyMultiMap, err := market.MakeEmptySetMultimap(store).Root()
+		emptyMultiMap, err := market.MakeEmptySetMultimap(store, market.DealOpsByEpochHamtBitwidth, market.DealOpsHamtBitwidth).Root()
 		assert.NoError(t, err)
 
 		var state market.State
@@ -409,6 +414,7 @@ func TestMarketActor(t *testing.T) {
 }
 
 func TestPublishStorageDeals(t *testing.T) {
+	t.Parallel()
 	owner := tutil.NewIDAddr(t, 101)
 	provider := tutil.NewIDAddr(t, 102)
 	worker := tutil.NewIDAddr(t, 103)
@@ -435,7 +441,7 @@ func TestPublishStorageDeals(t *testing.T) {
 		// generate deal and add required funds for deal
 		startEpoch := abi.ChainEpoch(42)
 		endEpoch := startEpoch + 200*builtin.EpochsInDay
-		deal := generateDealProposal(clientBls, mAddr.provider, startEpoch, endEpoch)
+		deal := fixtures.NewDealBuilder(clientBls, mAddr.provider, startEpoch, endEpoch).Build()
 		deal.VerifiedDeal = true
 
 		// add funds for cient using it's BLS address -> will be resolved and persisted
@@ -456,11 +462,10 @@ func TestPublishStorageDeals(t *testing.T) {
 		// publish deal using the BLS addresses
 		rt.SetCaller(mAddr.worker, builtin.AccountActorCodeID)
 		rt.ExpectValidateCallerType(builtin.CallerTypesSignable...)
-		rt.ExpectSend(
+		rt.ExpectSendReadOnly(
 			providerResolved,
 			builtin.MethodsMiner.ControlAddresses,
 			nil,
-			big.Zero(),
 			&miner.GetControlAddressesReturn{Owner: mAddr.owner, Worker: mAddr.worker},
 			exitcode.Ok,
 		)
@@ -534,7 +539,7 @@ func TestPublishStorageDeals(t *testing.T) {
 		clientCollateral := abi.NewTokenAmount(10) // min is zero so this is placeholder
 
 		// given power and circ supply cancel this should be 1*dealqapower / 100
-		dealSize := abi.PaddedPieceSize(2048) // generateDealProposal's deal size
+		dealSize := abi.PaddedPieceSize(2048) // the default deal size
 		providerCollateral := big.Div(
 			big.Mul(big.NewInt(int64(dealSize)), market.ProviderCollateralSupplyTarget.Numerator),
 			market.ProviderCollateralSupplyTarget.Denominator,
@@ -644,7 +649,87 @@ func TestPublishStorageDeals(t *testing.T) {
 	})
 }
 
+func TestPublishStorageDealsAggregate(t *testing.T) {
+	t.Parallel()
+	owner := tutil.NewIDAddr(t, 101)
+	provider := tutil.NewIDAddr(t, 102)
+	worker := tutil.NewIDAddr(t, 103)
+	client := tutil.NewIDAddr(t, 104)
+	startEpoch := abi.ChainEpoch(42)
+	endEpoch := startEpoch + 200*builtin.EpochsInDay
+	mAddr := &minerAddrs{owner, worker, provider}
+	aggregate := crypto.Signature{Type: crypto.SigTypeBLS, Data: []byte("aggregate")}
+
+	t.Run("publishes deals from a client with an aggregate signature", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		deal1 := actor.generateDealAndAddFunds(rt, client, mAddr, startEpoch, endEpoch)
+		deal2 := actor.generateDealAndAddFunds(rt, client, mAddr, startEpoch, endEpoch+1)
+
+		dealIDs := actor.publishDealsAggregate(rt, mAddr, aggregate, deal1, deal2)
+		require.Len(t, dealIDs, 2)
+		assert.Equal(t, deal1, *actor.getDealProposal(rt, dealIDs[0]))
+		assert.Equal(t, deal2, *actor.getDealProposal(rt, dealIDs[1]))
+		assert.Equal(t, big.Add(deal1.ClientBalanceRequirement(), deal2.ClientBalanceRequirement()),
+			actor.getLockedBalance(rt, client))
+
+		actor.checkState(rt)
+	})
+
+	t.Run("fails when the signature is not BLS", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		deal := actor.generateDealAndAddFunds(rt, client, mAddr, startEpoch, endEpoch)
+		params := &market.PublishStorageDealsAggregateParams{
+			Deals:           []market.DealProposal{deal},
+			ClientSignature: crypto.Signature{Type: crypto.SigTypeSecp256k1, Data: []byte("sig")},
+		}
+
+		rt.SetCaller(worker, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.CallerTypesSignable...)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "must be BLS", func() {
+			rt.Call(actor.PublishStorageDealsAggregate, params)
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("fails when deals have different clients", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		client2 := tutil.NewIDAddr(t, 900)
+		deal1 := actor.generateDealAndAddFunds(rt, client, mAddr, startEpoch, endEpoch)
+		deal2 := actor.generateDealAndAddFunds(rt, client2, mAddr, startEpoch, endEpoch)
+		params := &market.PublishStorageDealsAggregateParams{
+			Deals:           []market.DealProposal{deal1, deal2},
+			ClientSignature: aggregate,
+		}
+
+		rt.SetCaller(worker, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.CallerTypesSignable...)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "different clients", func() {
+			rt.Call(actor.PublishStorageDealsAggregate, params)
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("fails when the aggregate signature is invalid", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		deal := actor.generateDealAndAddFunds(rt, client, mAddr, startEpoch, endEpoch)
+		params := &market.PublishStorageDealsAggregateParams{
+			Deals:           []market.DealProposal{deal},
+			ClientSignature: aggregate,
+		}
+
+		rt.SetCaller(worker, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.CallerTypesSignable...)
+		rt.ExpectVerifyAggregateSignature(aggregate, []address.Address{client}, [][]byte{mustCbor(&deal)},
+			errors.New("invalid signature"))
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "invalid aggregate signature", func() {
+			rt.Call(actor.PublishStorageDealsAggregate, params)
+		})
+		actor.checkState(rt)
+	})
+}
+
 func TestPublishStorageDealsFailures(t *testing.T) {
+	t.Parallel()
 	owner := tutil.NewIDAddr(t, 101)
 	provider := tutil.NewIDAddr(t, 102)
 	worker := tutil.NewIDAddr(t, 103)
@@ -800,13 +885,13 @@ func TestPublishStorageDealsFailures(t *testing.T) {
 			t.Run(name, func(t *testing.T) {
 				_ = name
 				rt, actor := basicMarketSetup(t, owner, provider, worker, client)
-				dealProposal := generateDealProposal(client, provider, startEpoch, endEpoch)
+				dealProposal := fixtures.NewDealBuilder(client, provider, startEpoch, endEpoch).Build()
 				rt.SetEpoch(currentEpoch)
 				tc.setup(rt, actor, &dealProposal)
 				params := mkPublishStorageParams(dealProposal)
 
 				rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
-				rt.ExpectSend(provider, builtin.MethodsMiner.ControlAddresses, nil, abi.NewTokenAmount(0), &miner.GetControlAddressesReturn{Worker: worker, Owner: owner}, 0)
+				rt.ExpectSendReadOnly(provider, builtin.MethodsMiner.ControlAddresses, nil, &miner.GetControlAddressesReturn{Worker: worker, Owner: owner}, 0)
 				expectQueryNetworkInfo(rt, actor)
 				rt.SetCaller(worker, builtin.AccountActorCodeID)
 				rt.ExpectVerifySignature(crypto.Signature{}, dealProposal.Client, mustCbor(&dealProposal), tc.signatureVerificationError)
@@ -828,12 +913,12 @@ func TestPublishStorageDealsFailures(t *testing.T) {
 			//
 			actor.addParticipantFunds(rt, client, abi.NewTokenAmount(100))
 			startEpoch := abi.ChainEpoch(42)
-			deal1 := generateDealProposal(client, provider, startEpoch, startEpoch+200*builtin.EpochsInDay)
+			deal1 := fixtures.NewDealBuilder(client, provider, startEpoch, startEpoch+200*builtin.EpochsInDay).Build()
 			actor.addProviderFunds(rt, deal1.ProviderCollateral, mAddrs)
 			params := mkPublishStorageParams(deal1)
 
 			rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
-			rt.ExpectSend(provider, builtin.MethodsMiner.ControlAddresses, nil, abi.NewTokenAmount(0), &miner.GetControlAddressesReturn{Worker: worker, Owner: owner}, 0)
+			rt.ExpectSendReadOnly(provider, builtin.MethodsMiner.ControlAddresses, nil, &miner.GetControlAddressesReturn{Worker: worker, Owner: owner}, 0)
 			expectQueryNetworkInfo(rt, actor)
 			rt.SetCaller(worker, builtin.AccountActorCodeID)
 			rt.ExpectVerifySignature(crypto.Signature{}, deal1.Client, mustCbor(&deal1), nil)
@@ -849,13 +934,13 @@ func TestPublishStorageDealsFailures(t *testing.T) {
 			rt, actor := basicMarketSetup(t, owner, provider, worker, client)
 
 			actor.addProviderFunds(rt, abi.NewTokenAmount(1), mAddrs)
-			deal1 := generateDealProposal(client, provider, startEpoch, endEpoch)
+			deal1 := fixtures.NewDealBuilder(client, provider, startEpoch, endEpoch).Build()
 			actor.addParticipantFunds(rt, client, deal1.ClientBalanceRequirement())
 
 			params := mkPublishStorageParams(deal1)
 
 			rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
-			rt.ExpectSend(provider, builtin.MethodsMiner.ControlAddresses, nil, abi.NewTokenAmount(0), &miner.GetControlAddressesReturn{Worker: worker, Owner: owner}, 0)
+			rt.ExpectSendReadOnly(provider, builtin.MethodsMiner.ControlAddresses, nil, &miner.GetControlAddressesReturn{Worker: worker, Owner: owner}, 0)
 			expectQueryNetworkInfo(rt, actor)
 			rt.SetCaller(worker, builtin.AccountActorCodeID)
 			rt.ExpectVerifySignature(crypto.Signature{}, deal1.Client, mustCbor(&deal1), nil)
@@ -868,6 +953,48 @@ func TestPublishStorageDealsFailures(t *testing.T) {
 		})
 	}
 
+	// fail when a verified deal client controls the provider
+	{
+		t.Run("fail when verified deal client is the provider owner", func(t *testing.T) {
+			rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+			deal := fixtures.NewDealBuilder(owner, provider, startEpoch, endEpoch).Build()
+			deal.VerifiedDeal = true
+			params := mkPublishStorageParams(deal)
+
+			rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+			rt.ExpectSendReadOnly(provider, builtin.MethodsMiner.ControlAddresses, nil, &miner.GetControlAddressesReturn{Worker: worker, Owner: owner}, 0)
+			expectQueryNetworkInfo(rt, actor)
+			rt.SetCaller(worker, builtin.AccountActorCodeID)
+			rt.ExpectVerifySignature(crypto.Signature{}, deal.Client, mustCbor(&deal), nil)
+			rt.ExpectAbort(exitcode.ErrForbidden, func() {
+				rt.Call(actor.PublishStorageDeals, params)
+			})
+
+			rt.Verify()
+			actor.checkState(rt)
+		})
+
+		t.Run("fail when verified deal client is a provider control address", func(t *testing.T) {
+			rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+			deal := fixtures.NewDealBuilder(client, provider, startEpoch, endEpoch).Build()
+			deal.VerifiedDeal = true
+			params := mkPublishStorageParams(deal)
+
+			rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+			rt.ExpectSendReadOnly(provider, builtin.MethodsMiner.ControlAddresses, nil,
+				&miner.GetControlAddressesReturn{Worker: worker, Owner: owner, ControlAddrs: []address.Address{client}}, 0)
+			expectQueryNetworkInfo(rt, actor)
+			rt.SetCaller(worker, builtin.AccountActorCodeID)
+			rt.ExpectVerifySignature(crypto.Signature{}, deal.Client, mustCbor(&deal), nil)
+			rt.ExpectAbort(exitcode.ErrForbidden, func() {
+				rt.Call(actor.PublishStorageDeals, params)
+			})
+
+			rt.Verify()
+			actor.checkState(rt)
+		})
+	}
+
 	// fail when deals have different providers
 	{
 		t.Run("fail when deals have different providers", func(t *testing.T) {
@@ -880,7 +1007,7 @@ func TestPublishStorageDealsFailures(t *testing.T) {
 			params := mkPublishStorageParams(deal1, deal2)
 
 			rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
-			rt.ExpectSend(provider, builtin.MethodsMiner.ControlAddresses, nil, abi.NewTokenAmount(0), &miner.GetControlAddressesReturn{Worker: worker, Owner: owner}, 0)
+			rt.ExpectSendReadOnly(provider, builtin.MethodsMiner.ControlAddresses, nil, &miner.GetControlAddressesReturn{Worker: worker, Owner: owner}, 0)
 			expectQueryNetworkInfo(rt, actor)
 			rt.SetCaller(worker, builtin.AccountActorCodeID)
 			rt.ExpectVerifySignature(crypto.Signature{}, deal1.Client, mustCbor(&deal1), nil)
@@ -899,7 +1026,7 @@ func TestPublishStorageDealsFailures(t *testing.T) {
 		//  failures because of incorrect call params
 		t.Run("fail when caller is not of signable type", func(t *testing.T) {
 			rt, actor := basicMarketSetup(t, owner, provider, worker, client)
-			params := mkPublishStorageParams(generateDealProposal(client, provider, startEpoch, endEpoch))
+			params := mkPublishStorageParams(fixtures.NewDealBuilder(client, provider, startEpoch, endEpoch).Build())
 			w := tutil.NewIDAddr(t, 1000)
 			rt.SetCaller(w, builtin.StorageMinerActorCodeID)
 			rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
@@ -922,7 +1049,7 @@ func TestPublishStorageDealsFailures(t *testing.T) {
 
 		t.Run("fail to resolve provider address", func(t *testing.T) {
 			rt, actor := basicMarketSetup(t, owner, provider, worker, client)
-			deal := generateDealProposal(client, provider, startEpoch, endEpoch)
+			deal := fixtures.NewDealBuilder(client, provider, startEpoch, endEpoch).Build()
 			deal.Provider = tutil.NewBLSAddr(t, 100)
 
 			params := mkPublishStorageParams(deal)
@@ -936,10 +1063,10 @@ func TestPublishStorageDealsFailures(t *testing.T) {
 
 		t.Run("caller is not the same as the worker address for miner", func(t *testing.T) {
 			rt, actor := basicMarketSetup(t, owner, provider, worker, client)
-			deal := generateDealProposal(client, provider, startEpoch, endEpoch)
+			deal := fixtures.NewDealBuilder(client, provider, startEpoch, endEpoch).Build()
 			params := mkPublishStorageParams(deal)
 			rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
-			rt.ExpectSend(provider, builtin.MethodsMiner.ControlAddresses, nil, abi.NewTokenAmount(0), &miner.GetControlAddressesReturn{Worker: tutil.NewIDAddr(t, 999), Owner: owner}, 0)
+			rt.ExpectSendReadOnly(provider, builtin.MethodsMiner.ControlAddresses, nil, &miner.GetControlAddressesReturn{Worker: tutil.NewIDAddr(t, 999), Owner: owner}, 0)
 			rt.SetCaller(worker, builtin.AccountActorCodeID)
 			rt.ExpectAbort(exitcode.ErrForbidden, func() {
 				rt.Call(actor.PublishStorageDeals, params)
@@ -956,7 +1083,7 @@ func TestPublishStorageDealsFailures(t *testing.T) {
 		// deal provider will be a Storage Miner Actor.
 		p2 := tutil.NewIDAddr(t, 505)
 		rt.SetAddressActorType(p2, builtin.StoragePowerActorCodeID)
-		deal := generateDealProposal(client, p2, abi.ChainEpoch(1), abi.ChainEpoch(5))
+		deal := fixtures.NewDealBuilder(client, p2, abi.ChainEpoch(1), abi.ChainEpoch(5)).Build()
 
 		params := mkPublishStorageParams(deal)
 		rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
@@ -971,6 +1098,7 @@ func TestPublishStorageDealsFailures(t *testing.T) {
 }
 
 func TestActivateDeals(t *testing.T) {
+	t.Parallel()
 
 	owner := tutil.NewIDAddr(t, 101)
 	provider := tutil.NewIDAddr(t, 102)
@@ -1015,6 +1143,7 @@ func TestActivateDeals(t *testing.T) {
 }
 
 func TestActivateDealFailures(t *testing.T) {
+	t.Parallel()
 	owner := tutil.NewIDAddr(t, 101)
 	provider := tutil.NewIDAddr(t, 102)
 	worker := tutil.NewIDAddr(t, 103)
@@ -1087,7 +1216,7 @@ func TestActivateDealFailures(t *testing.T) {
 
 			rt.ExpectValidateCallerType(builtin.StorageMinerActorCodeID)
 			rt.SetCaller(provider, builtin.StorageMinerActorCodeID)
-			rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.ExpectAbortWithData(exitcode.ErrIllegalArgument, builtin.NewDealsFailure(dealId), func() {
 				rt.Call(actor.ActivateDeals, mkActivateDealParams(sectorExpiry, dealId))
 			})
 
@@ -1141,7 +1270,7 @@ func TestActivateDealFailures(t *testing.T) {
 
 			rt.ExpectValidateCallerType(builtin.StorageMinerActorCodeID)
 			rt.SetCaller(provider, builtin.StorageMinerActorCodeID)
-			rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.ExpectAbortWithData(exitcode.ErrIllegalArgument, builtin.NewDealsFailure(dealId1), func() {
 				rt.Call(actor.ActivateDeals, mkActivateDealParams(sectorExpiry, dealId1, dealId2))
 			})
 			rt.Verify()
@@ -1165,6 +1294,7 @@ func TestActivateDealFailures(t *testing.T) {
 }
 
 func TestOnMinerSectorsTerminate(t *testing.T) {
+	t.Parallel()
 	owner := tutil.NewIDAddr(t, 101)
 	provider := tutil.NewIDAddr(t, 102)
 	worker := tutil.NewIDAddr(t, 103)
@@ -1402,6 +1532,7 @@ func TestOnMinerSectorsTerminate(t *testing.T) {
 }
 
 func TestCronTick(t *testing.T) {
+	t.Parallel()
 	owner := tutil.NewIDAddr(t, 101)
 	provider := tutil.NewIDAddr(t, 102)
 	worker := tutil.NewIDAddr(t, 103)
@@ -1464,6 +1595,30 @@ func TestCronTick(t *testing.T) {
 		actor.checkState(rt)
 	})
 
+	t.Run("crontick with insufficient gas processes a single epoch", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		lastCron := func() abi.ChainEpoch {
+			var st market.State
+			rt.GetState(&st)
+			return st.LastCron
+		}
+		require.Equal(t, abi.ChainEpoch(-1), lastCron())
+
+		rt.SetEpoch(10)
+		rt.SetGasAvailable(market.CronTickGasReserve - 1)
+		actor.cronTick(rt)
+		assert.Equal(t, abi.ChainEpoch(0), lastCron())
+		actor.cronTick(rt)
+		assert.Equal(t, abi.ChainEpoch(1), lastCron())
+
+		// with sufficient gas the tick catches up with the current epoch
+		rt.SetGasAvailable(market.CronTickGasReserve)
+		actor.cronTick(rt)
+		assert.Equal(t, abi.ChainEpoch(10), lastCron())
+
+		actor.checkState(rt)
+	})
+
 	t.Run("slash a deal and make payment for another deal in the same epoch", func(t *testing.T) {
 		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
 
@@ -1500,7 +1655,7 @@ func TestCronTick(t *testing.T) {
 		d2 := actor.generateDealAndAddFunds(rt, client, mAddrs, startEpoch, endEpoch)
 		params := mkPublishStorageParams(d2)
 		rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
-		rt.ExpectSend(provider, builtin.MethodsMiner.ControlAddresses, nil, abi.NewTokenAmount(0), &miner.GetControlAddressesReturn{Worker: worker, Owner: owner}, 0)
+		rt.ExpectSendReadOnly(provider, builtin.MethodsMiner.ControlAddresses, nil, &miner.GetControlAddressesReturn{Worker: worker, Owner: owner}, 0)
 		expectQueryNetworkInfo(rt, actor)
 		rt.SetCaller(worker, builtin.AccountActorCodeID)
 		rt.ExpectVerifySignature(crypto.Signature{}, d2.Client, mustCbor(&d2), nil)
@@ -1521,6 +1676,7 @@ func TestCronTick(t *testing.T) {
 }
 
 func TestRandomCronEpochDuringPublish(t *testing.T) {
+	t.Parallel()
 	owner := tutil.NewIDAddr(t, 101)
 	provider := tutil.NewIDAddr(t, 102)
 	worker := tutil.NewIDAddr(t, 103)
@@ -1741,6 +1897,7 @@ func TestLockedFundTrackingStates(t *testing.T) {
 }
 
 func TestCronTickTimedoutDeals(t *testing.T) {
+	t.Parallel()
 	owner := tutil.NewIDAddr(t, 101)
 	provider := tutil.NewIDAddr(t, 102)
 	worker := tutil.NewIDAddr(t, 103)
@@ -1782,7 +1939,7 @@ func TestCronTickTimedoutDeals(t *testing.T) {
 		d2 := actor.generateDealAndAddFunds(rt, client, mAddrs, startEpoch, endEpoch)
 		params := mkPublishStorageParams(d2)
 		rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
-		rt.ExpectSend(provider, builtin.MethodsMiner.ControlAddresses, nil, abi.NewTokenAmount(0), &miner.GetControlAddressesReturn{Worker: worker, Owner: owner}, 0)
+		rt.ExpectSendReadOnly(provider, builtin.MethodsMiner.ControlAddresses, nil, &miner.GetControlAddressesReturn{Worker: worker, Owner: owner}, 0)
 		expectQueryNetworkInfo(rt, actor)
 		rt.SetCaller(worker, builtin.AccountActorCodeID)
 		rt.ExpectVerifySignature(crypto.Signature{}, d2.Client, mustCbor(&d2), nil)
@@ -1854,6 +2011,7 @@ func TestCronTickTimedoutDeals(t *testing.T) {
 }
 
 func TestCronTickDealExpiry(t *testing.T) {
+	t.Parallel()
 	owner := tutil.NewIDAddr(t, 101)
 	provider := tutil.NewIDAddr(t, 102)
 	worker := tutil.NewIDAddr(t, 103)
@@ -2017,6 +2175,7 @@ func TestCronTickDealExpiry(t *testing.T) {
 }
 
 func TestCronTickDealSlashing(t *testing.T) {
+	t.Parallel()
 	owner := tutil.NewIDAddr(t, 101)
 	provider := tutil.NewIDAddr(t, 102)
 	worker := tutil.NewIDAddr(t, 103)
@@ -2322,6 +2481,7 @@ func TestCronTickDealSlashing(t *testing.T) {
 }
 
 func TestMarketActorDeals(t *testing.T) {
+	t.Parallel()
 	owner := tutil.NewIDAddr(t, 101)
 	provider := tutil.NewIDAddr(t, 102)
 	worker := tutil.NewIDAddr(t, 103)
@@ -2338,7 +2498,7 @@ func TestMarketActorDeals(t *testing.T) {
 
 	actor.addParticipantFunds(rt, client, abi.NewTokenAmount(20000000))
 
-	dealProposal := generateDealProposal(client, provider, abi.ChainEpoch(1), abi.ChainEpoch(200*builtin.EpochsInDay))
+	dealProposal := fixtures.NewDealBuilder(client, provider, abi.ChainEpoch(1), abi.ChainEpoch(200*builtin.EpochsInDay)).Build()
 	params := &market.PublishStorageDealsParams{Deals: []market.ClientDealProposal{{Proposal: dealProposal}}}
 
 	// First attempt at publishing the deal should work
@@ -2349,7 +2509,7 @@ func TestMarketActorDeals(t *testing.T) {
 	// Second attempt at publishing the same deal should fail
 	{
 		rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
-		rt.ExpectSend(provider, builtin.MethodsMiner.ControlAddresses, nil, abi.NewTokenAmount(0), &miner.GetControlAddressesReturn{Worker: worker, Owner: owner}, 0)
+		rt.ExpectSendReadOnly(provider, builtin.MethodsMiner.ControlAddresses, nil, &miner.GetControlAddressesReturn{Worker: worker, Owner: owner}, 0)
 		expectQueryNetworkInfo(rt, actor)
 		rt.ExpectVerifySignature(crypto.Signature{}, client, mustCbor(&params.Deals[0].Proposal), nil)
 		rt.SetCaller(worker, builtin.AccountActorCodeID)
@@ -2370,6 +2530,7 @@ func TestMarketActorDeals(t *testing.T) {
 }
 
 func TestMaxDealLabelSize(t *testing.T) {
+	t.Parallel()
 	owner := tutil.NewIDAddr(t, 101)
 	provider := tutil.NewIDAddr(t, 102)
 	worker := tutil.NewIDAddr(t, 103)
@@ -2386,7 +2547,7 @@ func TestMaxDealLabelSize(t *testing.T) {
 
 	actor.addParticipantFunds(rt, client, abi.NewTokenAmount(20000000))
 
-	dealProposal := generateDealProposal(client, provider, abi.ChainEpoch(1), abi.ChainEpoch(200*builtin.EpochsInDay))
+	dealProposal := fixtures.NewDealBuilder(client, provider, abi.ChainEpoch(1), abi.ChainEpoch(200*builtin.EpochsInDay)).Build()
 	dealProposal.Label = string(make([]byte, market.DealMaxLabelSize))
 	params := &market.PublishStorageDealsParams{Deals: []market.ClientDealProposal{{Proposal: dealProposal}}}
 
@@ -2400,7 +2561,7 @@ func TestMaxDealLabelSize(t *testing.T) {
 	// Label greater than max size should fail.
 	{
 		rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
-		rt.ExpectSend(provider, builtin.MethodsMiner.ControlAddresses, nil, abi.NewTokenAmount(0), &miner.GetControlAddressesReturn{Worker: worker, Owner: owner}, 0)
+		rt.ExpectSendReadOnly(provider, builtin.MethodsMiner.ControlAddresses, nil, &miner.GetControlAddressesReturn{Worker: worker, Owner: owner}, 0)
 		expectQueryNetworkInfo(rt, actor)
 		rt.ExpectVerifySignature(crypto.Signature{}, client, mustCbor(&params.Deals[0].Proposal), nil)
 		rt.SetCaller(worker, builtin.AccountActorCodeID)
@@ -2414,6 +2575,7 @@ func TestMaxDealLabelSize(t *testing.T) {
 }
 
 func TestComputeDataCommitment(t *testing.T) {
+	t.Parallel()
 	owner := tutil.NewIDAddr(t, 101)
 	provider := tutil.NewIDAddr(t, 102)
 	worker := tutil.NewIDAddr(t, 103)
@@ -2480,6 +2642,7 @@ func TestComputeDataCommitment(t *testing.T) {
 }
 
 func TestVerifyDealsForActivation(t *testing.T) {
+	t.Parallel()
 	owner := tutil.NewIDAddr(t, 101)
 	provider := tutil.NewIDAddr(t, 102)
 	worker := tutil.NewIDAddr(t, 103)
@@ -2619,6 +2782,121 @@ func TestVerifyDealsForActivation(t *testing.T) {
 	})
 }
 
+func TestGetDeals(t *testing.T) {
+	t.Parallel()
+	owner := tutil.NewIDAddr(t, 101)
+	provider := tutil.NewIDAddr(t, 102)
+	worker := tutil.NewIDAddr(t, 103)
+	client := tutil.NewIDAddr(t, 104)
+	mAddrs := &minerAddrs{owner, worker, provider}
+	provider2 := tutil.NewIDAddr(t, 105)
+	mAddrs2 := &minerAddrs{owner, worker, provider2}
+
+	startEpoch := abi.ChainEpoch(50)
+	endEpoch := startEpoch + 200*builtin.EpochsInDay
+	currentEpoch := abi.ChainEpoch(5)
+	sectorExpiry := endEpoch + 100
+
+	t.Run("pages through a provider's deals in order of ID", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetEpoch(currentEpoch)
+		dealID1 := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch, startEpoch)
+		dealID2 := actor.generateAndPublishDeal(rt, client, mAddrs2, startEpoch, endEpoch, startEpoch)
+		dealID3 := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch+1, startEpoch)
+		dealID4 := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch+2, startEpoch)
+		actor.activateDeals(rt, sectorExpiry, provider, currentEpoch, dealID3)
+
+		deals, more := actor.getDealsByProvider(rt, provider, 0, 2)
+		assert.True(t, more)
+		require.Len(t, deals, 2)
+		assert.Equal(t, dealID1, deals[0].DealID)
+		assert.Equal(t, *actor.getDealProposal(rt, dealID1), deals[0].Proposal)
+		assert.Equal(t, abi.ChainEpoch(-1), deals[0].State.SectorStartEpoch)
+		assert.Equal(t, dealID3, deals[1].DealID)
+		assert.Equal(t, *actor.getDealState(rt, dealID3), deals[1].State)
+		assert.Equal(t, currentEpoch, deals[1].State.SectorStartEpoch)
+
+		deals, more = actor.getDealsByProvider(rt, provider, deals[1].DealID+1, 2)
+		assert.False(t, more)
+		require.Len(t, deals, 1)
+		assert.Equal(t, dealID4, deals[0].DealID)
+
+		// A page may start from a deal ID of another provider, and end exactly at the provider's last deal.
+		deals, more = actor.getDealsByProvider(rt, provider, dealID2, 2)
+		assert.False(t, more)
+		require.Len(t, deals, 2)
+		assert.Equal(t, dealID3, deals[0].DealID)
+		assert.Equal(t, dealID4, deals[1].DealID)
+
+		deals, more = actor.getDealsByProvider(rt, provider2, 0, 2)
+		assert.False(t, more)
+		require.Len(t, deals, 1)
+		assert.Equal(t, dealID2, deals[0].DealID)
+
+		deals, more = actor.getDealsByProvider(rt, tutil.NewIDAddr(t, 999), 0, 2)
+		assert.False(t, more)
+		assert.Empty(t, deals)
+
+		actor.checkState(rt)
+	})
+
+	t.Run("gets deals in the order requested omitting those not found", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetEpoch(currentEpoch)
+		dealID1 := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch, startEpoch)
+		dealID2 := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch+1, startEpoch)
+
+		deals := actor.getDeals(rt, dealID2, 99, dealID1)
+		require.Len(t, deals, 2)
+		assert.Equal(t, dealID2, deals[0].DealID)
+		assert.Equal(t, *actor.getDealProposal(rt, dealID2), deals[0].Proposal)
+		assert.Equal(t, dealID1, deals[1].DealID)
+
+		actor.checkState(rt)
+	})
+
+	t.Run("removed deals are removed from their provider's deals", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealID := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch, startEpoch)
+		d := actor.getDealProposal(rt, dealID)
+
+		// The deal times out without being activated, and is deleted.
+		rt.SetEpoch(startEpoch)
+		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, d.ProviderCollateral, nil, exitcode.Ok)
+		actor.cronTick(rt)
+		actor.assertDealDeleted(rt, dealID, d)
+
+		deals, more := actor.getDealsByProvider(rt, provider, 0, 10)
+		assert.False(t, more)
+		assert.Empty(t, deals)
+		assert.Empty(t, actor.getDeals(rt, dealID))
+
+		actor.checkState(rt)
+	})
+
+	t.Run("fails when too many or no deals are requested", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetCaller(client, builtin.AccountActorCodeID)
+
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(actor.GetDealsByProvider, &market.GetDealsByProviderParams{Provider: provider, Limit: market.DealQueryMax + 1})
+		})
+
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(actor.GetDealsByProvider, &market.GetDealsByProviderParams{Provider: provider, Limit: 0})
+		})
+
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(actor.GetDeals, &market.GetDealsParams{DealIDs: make([]abi.DealID, market.DealQueryMax+1)})
+		})
+
+		actor.checkState(rt)
+	})
+}
+
 type marketActorTestHarness struct {
 	market.Actor
 	t testing.TB
@@ -2649,6 +2927,24 @@ func (h *marketActorTestHarness) verifyDealsForActivation(rt *mock.Runtime, prov
 	return val
 }
 
+func (h *marketActorTestHarness) getDeals(rt *mock.Runtime, dealIDs ...abi.DealID) []market.DealInfo {
+	rt.SetCaller(tutil.NewIDAddr(h.t, 1000), builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.GetDeals, &market.GetDealsParams{DealIDs: dealIDs}).(*market.GetDealsReturn)
+	rt.Verify()
+	return ret.Deals
+}
+
+func (h *marketActorTestHarness) getDealsByProvider(rt *mock.Runtime, provider address.Address, start abi.DealID,
+	limit uint64) ([]market.DealInfo, bool) {
+	rt.SetCaller(tutil.NewIDAddr(h.t, 1000), builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAny()
+	params := &market.GetDealsByProviderParams{Provider: provider, Start: start, Limit: limit}
+	ret := rt.Call(h.GetDealsByProvider, params).(*market.GetDealsByProviderReturn)
+	rt.Verify()
+	return ret.Deals, ret.More
+}
+
 type minerAddrs struct {
 	owner    address.Address
 	worker   address.Address
@@ -2687,11 +2983,10 @@ func (h *marketActorTestHarness) addParticipantFunds(rt *mock.Runtime, addr addr
 func (h *marketActorTestHarness) expectProviderControlAddresses(rt *mock.Runtime, provider address.Address, owner address.Address, worker address.Address) {
 	expectRet := &miner.GetControlAddressesReturn{Owner: owner, Worker: worker}
 
-	rt.ExpectSend(
+	rt.ExpectSendReadOnly(
 		provider,
 		builtin.MethodsMiner.ControlAddresses,
 		nil,
-		big.Zero(),
 		expectRet,
 		exitcode.Ok,
 	)
@@ -2710,6 +3005,7 @@ func (h *marketActorTestHarness) withdrawProviderBalance(rt *mock.Runtime, withD
 	rt.ExpectSend(miner.owner, builtin.MethodSend, nil, expectedSend, nil, exitcode.Ok)
 	rt.Call(h.WithdrawBalance, &params)
 	rt.Verify()
+	rt.RequireEmittedEvent(builtin.EventKeyTransfer, &builtin.TransferEvent{To: miner.owner, Amount: expectedSend})
 }
 
 func (h *marketActorTestHarness) withdrawClientBalance(rt *mock.Runtime, client address.Address, withDrawAmt, expectedSend abi.TokenAmount) {
@@ -2829,7 +3125,7 @@ func (h *marketActorTestHarness) expectGetRandom(rt *mock.Runtime, deal *market.
 	diff := uint64(requiredProcessEpoch - deal.StartEpoch)
 	require.NoError(h.t, deal.MarshalCBOR(&dealBuf))
 	require.NoError(h.t, binary.Write(&epochBuf, binary.BigEndian, diff))
-	rt.ExpectGetRandomnessBeacon(crypto.DomainSeparationTag_MarketDealCronSeed, rt.Epoch()-1, dealBuf.Bytes(), epochBuf.Bytes())
+	rt.ExpectGetRandomness(runtime.RandomnessBeacon, crypto.DomainSeparationTag_MarketDealCronSeed, rt.Epoch()-1, dealBuf.Bytes(), epochBuf.Bytes())
 }
 
 func (h *marketActorTestHarness) publishDeals(rt *mock.Runtime, minerAddrs *minerAddrs, publishDealReqs ...publishDealReq) []abi.DealID {
@@ -2839,11 +3135,10 @@ func (h *marketActorTestHarness) publishDeals(rt *mock.Runtime, minerAddrs *mine
 
 	rt.SetCaller(minerAddrs.worker, builtin.AccountActorCodeID)
 	rt.ExpectValidateCallerType(builtin.CallerTypesSignable...)
-	rt.ExpectSend(
+	rt.ExpectSendReadOnly(
 		minerAddrs.provider,
 		builtin.MethodsMiner.ControlAddresses,
 		nil,
-		big.Zero(),
 		&miner.GetControlAddressesReturn{Owner: minerAddrs.owner, Worker: minerAddrs.worker},
 		exitcode.Ok,
 	)
@@ -2883,6 +3178,11 @@ func (h *marketActorTestHarness) publishDeals(rt *mock.Runtime, minerAddrs *mine
 	for i, deaId := range dealIds {
 		expected := publishDealReqs[i].deal
 		p := h.getDealProposal(rt, deaId)
+		rt.RequireEmittedEvent(builtin.EventKeyDealPublished, &builtin.DealPublishedEvent{
+			DealID:   deaId,
+			Client:   p.Client,
+			Provider: p.Provider,
+		})
 
 		require.Equal(h.t, expected.StartEpoch, p.StartEpoch)
 		require.Equal(h.t, expected.EndEpoch, p.EndEpoch)
@@ -2900,6 +3200,39 @@ func (h *marketActorTestHarness) publishDeals(rt *mock.Runtime, minerAddrs *mine
 	return resp.IDs
 }
 
+// Publishes deals from a single client with an aggregate signature.
+func (h *marketActorTestHarness) publishDealsAggregate(rt *mock.Runtime, minerAddrs *minerAddrs, sig crypto.Signature,
+	deals ...market.DealProposal) []abi.DealID {
+	for i := range deals {
+		h.expectGetRandom(rt, &deals[i], 0)
+	}
+
+	rt.SetCaller(minerAddrs.worker, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerType(builtin.CallerTypesSignable...)
+	rt.ExpectSendReadOnly(
+		minerAddrs.provider,
+		builtin.MethodsMiner.ControlAddresses,
+		nil,
+		&miner.GetControlAddressesReturn{Owner: minerAddrs.owner, Worker: minerAddrs.worker},
+		exitcode.Ok,
+	)
+	expectQueryNetworkInfo(rt, h)
+
+	signers := make([]address.Address, len(deals))
+	plaintexts := make([][]byte, len(deals))
+	for i := range deals {
+		signers[i] = deals[i].Client
+		plaintexts[i] = mustCbor(&deals[i])
+	}
+	rt.ExpectVerifyAggregateSignature(sig, signers, plaintexts, nil)
+
+	params := &market.PublishStorageDealsAggregateParams{Deals: deals, ClientSignature: sig}
+	ret := rt.Call(h.PublishStorageDealsAggregate, params).(*market.PublishStorageDealsReturn)
+	rt.Verify()
+	require.Len(h.t, ret.IDs, len(deals))
+	return ret.IDs
+}
+
 func (h *marketActorTestHarness) assertDealsNotActivated(rt *mock.Runtime, epoch abi.ChainEpoch, dealIDs ...abi.DealID) {
 	var st market.State
 	rt.GetState(&st)
@@ -3110,7 +3443,7 @@ func (h *marketActorTestHarness) generateAndPublishDeal(rt *mock.Runtime, client
 
 func (h *marketActorTestHarness) generateDealAndAddFunds(rt *mock.Runtime, client address.Address, minerAddrs *minerAddrs,
 	startEpoch, endEpoch abi.ChainEpoch) market.DealProposal {
-	deal4 := generateDealProposal(client, minerAddrs.provider, startEpoch, endEpoch)
+	deal4 := fixtures.NewDealBuilder(client, minerAddrs.provider, startEpoch, endEpoch).Build()
 	h.addProviderFunds(rt, deal4.ProviderCollateral, minerAddrs)
 	h.addParticipantFunds(rt, client, deal4.ClientBalanceRequirement())
 
@@ -3119,8 +3452,9 @@ func (h *marketActorTestHarness) generateDealAndAddFunds(rt *mock.Runtime, clien
 
 func (h *marketActorTestHarness) generateDealWithCollateralAndAddFunds(rt *mock.Runtime, client address.Address,
 	minerAddrs *minerAddrs, providerCollateral, clientCollateral abi.TokenAmount, startEpoch, endEpoch abi.ChainEpoch) market.DealProposal {
-	deal := generateDealProposalWithCollateral(client, minerAddrs.provider, providerCollateral, clientCollateral,
-		startEpoch, endEpoch)
+	deal := fixtures.NewDealBuilder(client, minerAddrs.provider, startEpoch, endEpoch).
+		WithCollateral(providerCollateral, clientCollateral).
+		Build()
 	h.addProviderFunds(rt, deal.ProviderCollateral, minerAddrs)
 	h.addParticipantFunds(rt, client, deal.ClientBalanceRequirement())
 
@@ -3135,24 +3469,8 @@ func (h *marketActorTestHarness) checkState(rt *mock.Runtime) {
 	assert.True(h.t, msgs.IsEmpty(), strings.Join(msgs.Messages(), "\n"))
 }
 
-func generateDealProposalWithCollateral(client, provider address.Address, providerCollateral, clientCollateral abi.TokenAmount, startEpoch, endEpoch abi.ChainEpoch) market.DealProposal {
-	pieceCid := tutil.MakeCID("1", &market.PieceCIDPrefix)
-	pieceSize := abi.PaddedPieceSize(2048)
-	storagePerEpoch := big.NewInt(10)
-
-	return market.DealProposal{PieceCID: pieceCid, PieceSize: pieceSize, Client: client, Provider: provider, Label: "label", StartEpoch: startEpoch,
-		EndEpoch: endEpoch, StoragePricePerEpoch: storagePerEpoch, ProviderCollateral: providerCollateral, ClientCollateral: clientCollateral}
-}
-
-func generateDealProposal(client, provider address.Address, startEpoch, endEpoch abi.ChainEpoch) market.DealProposal {
-	clientCollateral := big.NewInt(10)
-	providerCollateral := big.NewInt(10)
-
-	return generateDealProposalWithCollateral(client, provider, clientCollateral, providerCollateral, startEpoch, endEpoch)
-}
-
 func basicMarketSetup(t *testing.T, owner, provider, worker, client address.Address) (*mock.Runtime, *marketActorTestHarness) {
-	builder := mock.NewBuilder(context.Background(), builtin.StorageMarketActorAddr).
+	builder := mock.NewBuilder(context.Background(), builtin.StorageMarketActorAddr).WithStateInvariants(market.CheckStateInvariants).
 		WithCaller(builtin.SystemActorAddr, builtin.InitActorCodeID).
 		WithBalance(big.Mul(big.NewInt(10), big.NewInt(1e18)), big.Zero()).
 		WithActorType(owner, builtin.AccountActorCodeID).
@@ -3195,20 +3513,18 @@ func expectQueryNetworkInfo(rt *mock.Runtime, h *marketActorTestHarness) {
 	currentReward := reward.ThisEpochRewardReturn{
 		ThisEpochBaselinePower: h.networkBaselinePower,
 	}
-	rt.ExpectSend(
+	rt.ExpectSendReadOnly(
 		builtin.RewardActorAddr,
 		builtin.MethodsReward.ThisEpochReward,
 		nil,
-		big.Zero(),
 		&currentReward,
 		exitcode.Ok,
 	)
 
-	rt.ExpectSend(
+	rt.ExpectSendReadOnly(
 		builtin.StoragePowerActorAddr,
 		builtin.MethodsPower.CurrentTotalPower,
 		nil,
-		big.Zero(),
 		&currentPower,
 		exitcode.Ok,
 	)
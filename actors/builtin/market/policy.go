@@ -5,10 +5,11 @@ import (
 	"github.com/filecoin-project/go-state-types/big"
 
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v2/actors/util/math"
 )
 
 // The number of epochs between payment and other state processing for deals.
-const DealUpdatesInterval = builtin.EpochsInDay // PARAM_SPEC
+var DealUpdatesInterval = builtin.EpochsInDay // PARAM_SPEC
 
 // The percentage of normalized cirulating
 // supply that must be covered by provider collateral in a deal
@@ -18,14 +19,21 @@ var ProviderCollateralSupplyTarget = builtin.BigFrac{
 }
 
 // Minimum deal duration.
-var DealMinDuration = abi.ChainEpoch(180 * builtin.EpochsInDay) // PARAM_SPEC
+var DealMinDuration = 180 * builtin.EpochsInDay // PARAM_SPEC
 
 // Maximum deal duration
-var DealMaxDuration = abi.ChainEpoch(540 * builtin.EpochsInDay) // PARAM_SPEC
+var DealMaxDuration = 540 * builtin.EpochsInDay // PARAM_SPEC
 
 // DealMaxLabelSize is the maximum size of a deal label.
 const DealMaxLabelSize = 256
 
+// Gas that must remain available before the cron tick processes the deal ops of another epoch, sufficient to
+// flush state and send restored data cap and slashed funds. Epochs not reached are processed at a later tick.
+const CronTickGasReserve = int64(2e9)
+
+// The maximum number of deals which may be requested by a single call to GetDeals or GetDealsByProvider.
+const DealQueryMax = 1000
+
 // Bounds (inclusive) on deal duration
 func DealDurationBounds(_ abi.PaddedPieceSize) (min abi.ChainEpoch, max abi.ChainEpoch) {
 	return DealMinDuration, DealMaxDuration
@@ -44,7 +52,7 @@ func DealProviderCollateralBounds(pieceSize abi.PaddedPieceSize, verified bool,
 	lockTargetNum := big.Mul(ProviderCollateralSupplyTarget.Numerator, networkCirculatingSupply)
 	lockTargetDenom := ProviderCollateralSupplyTarget.Denominator
 	powerShareNum := big.NewIntUnsigned(uint64(pieceSize))
-	powerShareDenom := big.Max(big.Max(networkRawPower, baselinePower), powerShareNum)
+	powerShareDenom := math.Max(networkRawPower, baselinePower, powerShareNum)
 
 	num := big.Mul(lockTargetNum, powerShareNum)
 	denom := big.Mul(lockTargetDenom, powerShareDenom)
@@ -0,0 +1,139 @@
+package market
+
+import (
+	addr "github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	cid "github.com/ipfs/go-cid"
+	cbg "github.com/whyrusleeping/cbor-gen"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/util/adt"
+)
+
+// An index of deal IDs by provider, as a HAMT of AMTs keyed by deal ID.
+// Each provider's deals are held in order of ID, so that they may be iterated from any deal without reading those
+// before it.
+type ProviderDealIndex struct {
+	mp    *adt.Map
+	store adt.Store
+}
+
+// The value of each entry in a provider's AMT, which holds deal IDs only as keys.
+var providerDealMarker = cbg.CborBool(true)
+
+// Interprets a store as an index of deals by provider with root `r`.
+func AsProviderDealIndex(s adt.Store, r cid.Cid) (*ProviderDealIndex, error) {
+	m, err := adt.AsMapWithBitwidth(s, r, DealsByProviderHamtBitwidth)
+	if err != nil {
+		return nil, err
+	}
+	return &ProviderDealIndex{mp: m, store: s}, nil
+}
+
+// Creates a new index backed by an empty HAMT.
+func MakeEmptyProviderDealIndex(s adt.Store) *ProviderDealIndex {
+	return &ProviderDealIndex{mp: adt.MakeEmptyMapWithBitwidth(s, DealsByProviderHamtBitwidth), store: s}
+}
+
+// Returns the root cid of the underlying HAMT.
+func (x *ProviderDealIndex) Root() (cid.Cid, error) {
+	return x.mp.Root()
+}
+
+// Adds a deal to a provider's deals.
+func (x *ProviderDealIndex) Put(provider addr.Address, dealID abi.DealID) error {
+	deals, found, err := x.get(provider)
+	if err != nil {
+		return err
+	}
+	if !found {
+		deals = adt.MakeEmptyArray(x.store)
+	}
+	marker := providerDealMarker
+	if err = deals.Set(uint64(dealID), &marker); err != nil {
+		return xerrors.Errorf("failed to add deal %d of provider %v: %w", dealID, provider, err)
+	}
+	return x.put(provider, deals)
+}
+
+// Removes a deal from a provider's deals, removing the provider if it has no more.
+// Removing a deal which is not present is not an error.
+func (x *ProviderDealIndex) Remove(provider addr.Address, dealID abi.DealID) error {
+	deals, found, err := x.get(provider)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return nil
+	}
+	var marker cbg.CborBool
+	if present, err := deals.Get(uint64(dealID), &marker); err != nil {
+		return xerrors.Errorf("failed to load deal %d of provider %v: %w", dealID, provider, err)
+	} else if !present {
+		return nil
+	}
+	if err = deals.Delete(uint64(dealID)); err != nil {
+		return xerrors.Errorf("failed to remove deal %d of provider %v: %w", dealID, provider, err)
+	}
+	if deals.Length() == 0 {
+		if err = x.mp.Delete(abi.AddrKey(provider)); err != nil {
+			return xerrors.Errorf("failed to delete deals of provider %v: %w", provider, err)
+		}
+		return nil
+	}
+	return x.put(provider, deals)
+}
+
+// Iterates a provider's deals in order of ID, from a deal ID on. Iteration halts if the function returns an error.
+func (x *ProviderDealIndex) ForEachFrom(provider addr.Address, start abi.DealID, fn func(id abi.DealID) error) error {
+	deals, found, err := x.get(provider)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return nil
+	}
+	return deals.ForEachFrom(uint64(start), nil, func(i int64) error {
+		return fn(abi.DealID(i))
+	})
+}
+
+// Iterates the providers with deals, in the order of the HAMT. Iteration halts if the function returns an error.
+func (x *ProviderDealIndex) ForEachProvider(fn func(provider addr.Address) error) error {
+	var root cbg.CborCid
+	return x.mp.ForEach(&root, func(k string) error {
+		provider, err := addr.NewFromBytes([]byte(k))
+		if err != nil {
+			return xerrors.Errorf("deals by provider has key that is not an address %x: %w", k, err)
+		}
+		return fn(provider)
+	})
+}
+
+func (x *ProviderDealIndex) get(provider addr.Address) (*adt.Array, bool, error) {
+	var root cbg.CborCid
+	found, err := x.mp.Get(abi.AddrKey(provider), &root)
+	if err != nil {
+		return nil, false, xerrors.Errorf("failed to load deals of provider %v: %w", provider, err)
+	}
+	if !found {
+		return nil, false, nil
+	}
+	deals, err := adt.AsArray(x.store, cid.Cid(root))
+	if err != nil {
+		return nil, false, xerrors.Errorf("failed to load deals of provider %v: %w", provider, err)
+	}
+	return deals, true, nil
+}
+
+func (x *ProviderDealIndex) put(provider addr.Address, deals *adt.Array) error {
+	r, err := deals.Root()
+	if err != nil {
+		return xerrors.Errorf("failed to flush deals of provider %v: %w", provider, err)
+	}
+	root := cbg.CborCid(r)
+	if err = x.mp.Put(abi.AddrKey(provider), &root); err != nil {
+		return xerrors.Errorf("failed to store deals of provider %v: %w", provider, err)
+	}
+	return nil
+}
@@ -14,23 +14,25 @@ import (
 )
 
 type SetMultimap struct {
-	mp    *adt.Map
-	store adt.Store
+	mp          *adt.Map
+	store       adt.Store
+	setBitwidth int
 }
 
-// Interprets a store as a HAMT-based map of HAMT-based sets with root `r`.
-func AsSetMultimap(s adt.Store, r cid.Cid) (*SetMultimap, error) {
-	m, err := adt.AsMap(s, r)
+// Interprets a store as a HAMT-based map of HAMT-based sets with root `r`, and the bitwidths with which the map
+// and the sets were built.
+func AsSetMultimap(s adt.Store, r cid.Cid, mapBitwidth, setBitwidth int) (*SetMultimap, error) {
+	m, err := adt.AsMapWithBitwidth(s, r, mapBitwidth)
 	if err != nil {
 		return nil, err
 	}
-	return &SetMultimap{mp: m, store: s}, nil
+	return &SetMultimap{mp: m, store: s, setBitwidth: setBitwidth}, nil
 }
 
 // Creates a new map backed by an empty HAMT and flushes it to the store.
-func MakeEmptySetMultimap(s adt.Store) *SetMultimap {
-	m := adt.MakeEmptyMap(s)
-	return &SetMultimap{m, s}
+func MakeEmptySetMultimap(s adt.Store, mapBitwidth, setBitwidth int) *SetMultimap {
+	m := adt.MakeEmptyMapWithBitwidth(s, mapBitwidth)
+	return &SetMultimap{m, s, setBitwidth}
 }
 
 // Returns the root cid of the underlying HAMT.
@@ -39,52 +41,47 @@ func (mm *SetMultimap) Root() (cid.Cid, error) {
 }
 
 func (mm *SetMultimap) Put(epoch abi.ChainEpoch, v abi.DealID) error {
-	// Load the hamt under key, or initialize a new empty one if not found.
-	k := abi.UIntKey(uint64(epoch))
-	set, found, err := mm.get(k)
-	if err != nil {
-		return err
-	}
-	if !found {
-		set = adt.MakeEmptySet(mm.store)
-	}
+	return mm.putMany(abi.UIntKey(uint64(epoch)), []abi.DealID{v})
+}
 
-	// Add to the set.
-	if err = set.Put(dealKey(v)); err != nil {
-		return errors.Wrapf(err, "failed to add key to set %v", epoch)
-	}
+func (mm *SetMultimap) PutMany(epoch abi.ChainEpoch, vs []abi.DealID) error {
+	return mm.putMany(abi.UIntKey(uint64(epoch)), vs)
+}
 
-	src, err := set.Root()
-	if err != nil {
-		return xerrors.Errorf("failed to flush set root: %w", err)
-	}
-	// Store the new set root under key.
-	newSetRoot := cbg.CborCid(src)
-	err = mm.mp.Put(k, &newSetRoot)
-	if err != nil {
-		return errors.Wrapf(err, "failed to store set")
+// Removes all values for a key.
+func (mm *SetMultimap) RemoveAll(key abi.ChainEpoch) error {
+	err := mm.mp.Delete(abi.UIntKey(uint64(key)))
+	if err != nil && !xerrors.Is(err, hamt.ErrNotFound) {
+		return xerrors.Errorf("failed to delete set key %v: %w", key, err)
 	}
 	return nil
 }
 
-func (mm *SetMultimap) PutMany(epoch abi.ChainEpoch, vs []abi.DealID) error {
+// Iterates all entries for a key, iteration halts if the function returns an error.
+func (mm *SetMultimap) ForEach(epoch abi.ChainEpoch, fn func(id abi.DealID) error) error {
+	return mm.forEach(abi.UIntKey(uint64(epoch)), fn)
+}
+
+func (mm *SetMultimap) putMany(k abi.Keyer, vs []abi.DealID) error {
 	// Load the hamt under key, or initialize a new empty one if not found.
-	k := abi.UIntKey(uint64(epoch))
 	set, found, err := mm.get(k)
 	if err != nil {
 		return err
 	}
 	if !found {
-		set = adt.MakeEmptySet(mm.store)
+		set = adt.MakeEmptySetWithBitwidth(mm.store, mm.setBitwidth)
 	}
 
 	// Add to the set.
 	for _, v := range vs {
 		if err = set.Put(dealKey(v)); err != nil {
-			return errors.Wrapf(err, "failed to add key to set %v", epoch)
+			return errors.Wrapf(err, "failed to add key to set %v", k)
 		}
 	}
+	return mm.putSet(k, set)
+}
 
+func (mm *SetMultimap) putSet(k abi.Keyer, set *adt.Set) error {
 	src, err := set.Root()
 	if err != nil {
 		return xerrors.Errorf("failed to flush set root: %w", err)
@@ -98,18 +95,8 @@ func (mm *SetMultimap) PutMany(epoch abi.ChainEpoch, vs []abi.DealID) error {
 	return nil
 }
 
-// Removes all values for a key.
-func (mm *SetMultimap) RemoveAll(key abi.ChainEpoch) error {
-	err := mm.mp.Delete(abi.UIntKey(uint64(key)))
-	if err != nil && !xerrors.Is(err, hamt.ErrNotFound) {
-		return xerrors.Errorf("failed to delete set key %v: %w", key, err)
-	}
-	return nil
-}
-
-// Iterates all entries for a key, iteration halts if the function returns an error.
-func (mm *SetMultimap) ForEach(epoch abi.ChainEpoch, fn func(id abi.DealID) error) error {
-	set, found, err := mm.get(abi.UIntKey(uint64(epoch)))
+func (mm *SetMultimap) forEach(k abi.Keyer, fn func(id abi.DealID) error) error {
+	set, found, err := mm.get(k)
 	if err != nil {
 		return err
 	}
@@ -133,7 +120,7 @@ func (mm *SetMultimap) get(key abi.Keyer) (*adt.Set, bool, error) {
 	}
 	var set *adt.Set
 	if found {
-		set, err = adt.AsSet(mm.store, cid.Cid(setRoot))
+		set, err = adt.AsSetWithBitwidth(mm.store, cid.Cid(setRoot), mm.setBitwidth)
 		if err != nil {
 			return nil, false, err
 		}
@@ -64,6 +64,13 @@ func CheckStateInvariants(st *State, store adt.Store, balance abi.TokenAmount, c
 	}
 	var proposal DealProposal
 	totalProposalCollateral := abi.NewTokenAmount(0)
+	partyCollateral := map[address.Address]abi.TokenAmount{}
+	addCollateral := func(party address.Address, amount abi.TokenAmount) {
+		if prev, ok := partyCollateral[party]; ok {
+			amount = big.Add(prev, amount)
+		}
+		partyCollateral[party] = amount
+	}
 	err = proposals.ForEach(&proposal, func(dealID int64) error {
 		pcid, err := proposal.Cid()
 		if err != nil {
@@ -89,6 +96,8 @@ func CheckStateInvariants(st *State, store adt.Store, balance abi.TokenAmount, c
 		}
 
 		totalProposalCollateral = big.Sum(totalProposalCollateral, proposal.ClientCollateral, proposal.ProviderCollateral)
+		addCollateral(proposal.Client, proposal.ClientCollateral)
+		addCollateral(proposal.Provider, proposal.ProviderCollateral)
 
 		acc.Require(proposal.Client.Protocol() == address.ID, "client address for deal %d is not an ID address", dealID)
 		acc.Require(proposal.Provider.Protocol() == address.ID, "provider address for deal %d is not an ID address", dealID)
@@ -154,7 +163,7 @@ func CheckStateInvariants(st *State, store adt.Store, balance abi.TokenAmount, c
 	//
 
 	pendingProposalCount := uint64(0)
-	pendingProposals, err := adt.AsMap(store, st.PendingProposals)
+	pendingProposals, err := adt.AsMapWithBitwidth(store, st.PendingProposals, PendingProposalsHamtBitwidth)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -190,34 +199,33 @@ func CheckStateInvariants(st *State, store adt.Store, balance abi.TokenAmount, c
 		return nil, acc, err
 	}
 
-	lockTableCount := uint64(0)
 	lockTable, err := adt.AsBalanceTable(store, st.LockedTable)
 	if err != nil {
 		return nil, acc, err
 	}
-	var lockedAmount abi.TokenAmount
-	lockedTotal := abi.NewTokenAmount(0)
-	err = (*adt.Map)(lockTable).ForEach(&lockedAmount, func(key string) error {
-		addr, err := address.NewFromBytes([]byte(key))
-		if err != nil {
-			return err
-		}
-		lockedTotal = big.Add(lockedTotal, lockedAmount)
-
-		// every entry in locked table should have a corresponding entry in escrow table that is at least as high
-		escrowAmount, err := escrowTable.Get(addr)
-		if err != nil {
-			return err
-		}
-		acc.Require(escrowAmount.GreaterThanEqual(lockedAmount),
-			"locked funds for %s, %s, greater than escrow amount, %s", addr, lockedAmount, escrowAmount)
 
-		lockTableCount++
-		return nil
-	})
+	// every entry in locked table should have a corresponding entry in escrow table that is at least as high,
+	// and escrow should not exceed actor balance
+	// lockTable item <= escrow item and escrowTotal <= balance implies lockTable total <= balance
+	audit, err := adt.AuditBalances(escrowTable, lockTable, balance)
 	if err != nil {
 		return nil, acc, err
 	}
+	for _, problem := range audit.Problems {
+		acc.Add(problem)
+	}
+	lockedTotal := audit.LockedTotal
+	lockTableCount := audit.LockedCount
+
+	// each party's locked funds should cover the collateral of its deals, which is locked until the deal is removed
+	for party, collateral := range partyCollateral { // nolint:nomaprange
+		locked, err := lockTable.Get(party)
+		if err != nil {
+			return nil, acc, err
+		}
+		acc.Require(locked.GreaterThanEqual(collateral),
+			"locked funds for %s, %s, less than collateral of its deals, %s", party, locked, collateral)
+	}
 
 	// lockTable total should be sum of client and provider locked plus client storage fee
 	expectedLockTotal := big.Sum(st.TotalProviderLockedCollateral, st.TotalClientLockedCollateral, st.TotalClientStorageFee)
@@ -225,13 +233,7 @@ func CheckStateInvariants(st *State, store adt.Store, balance abi.TokenAmount, c
 		"locked total, %s, does not sum to provider locked, %s, client locked, %s, and client storage fee, %s",
 		lockedTotal, st.TotalProviderLockedCollateral, st.TotalClientLockedCollateral, st.TotalClientStorageFee)
 
-	// assert escrow <= actor balance
-	// lockTable item <= escrow item and escrowTotal <= balance implies lockTable total <= balance
-	escrowTotal, err := escrowTable.Total()
-	if err != nil {
-		return nil, acc, err
-	}
-	acc.Require(escrowTotal.LessThanEqual(balance), "escrow total, %v, greater than actor balance, %v", escrowTotal, balance)
+	escrowTotal := audit.Total
 	acc.Require(escrowTotal.GreaterThanEqual(totalProposalCollateral), "escrow total, %v, less than sum of proposal collateral, %v", escrowTotal, totalProposalCollateral)
 
 	//
@@ -240,7 +242,7 @@ func CheckStateInvariants(st *State, store adt.Store, balance abi.TokenAmount, c
 
 	dealOpEpochCount := uint64(0)
 	dealOpCount := uint64(0)
-	dealOps, err := AsSetMultimap(store, st.DealOpsByEpoch)
+	dealOps, err := AsSetMultimap(store, st.DealOpsByEpoch, DealOpsByEpochHamtBitwidth, DealOpsHamtBitwidth)
 	if err != nil {
 		return nil, acc, err
 	}
@@ -268,6 +270,29 @@ func CheckStateInvariants(st *State, store adt.Store, balance abi.TokenAmount, c
 
 	acc.Require(len(expectedDealOps) == 0, "missing deal ops for proposals: %v", expectedDealOps)
 
+	//
+	// Deals by Provider
+	//
+
+	dealsByProvider, err := AsProviderDealIndex(store, st.DealsByProvider)
+	if err != nil {
+		return nil, acc, err
+	}
+	indexedDeals := make(map[abi.DealID]struct{}, len(proposalStats))
+	err = dealsByProvider.ForEachProvider(func(provider address.Address) error {
+		return dealsByProvider.ForEachFrom(provider, 0, func(id abi.DealID) error {
+			stats, found := proposalStats[id]
+			acc.Require(found, "deal %d indexed for provider %v has no proposal", id, provider)
+			acc.Require(!found || stats.Provider == provider, "deal %d indexed for provider %v has provider %v", id, provider, stats.Provider)
+			indexedDeals[id] = struct{}{}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, acc, err
+	}
+	acc.Require(len(indexedDeals) == len(proposalStats), "%d deals indexed by provider, expected %d", len(indexedDeals), len(proposalStats))
+
 	return &StateSummary{
 		Deals:                proposalStats,
 		PendingProposalCount: pendingProposalCount,
@@ -10,20 +10,37 @@ const (
 	MethodConstructor = builtin0.MethodConstructor
 )
 
-var MethodsAccount = struct {
-	Constructor   abi.MethodNum
-	PubkeyAddress abi.MethodNum
+var MethodsSystem = struct {
+	Constructor     abi.MethodNum
+	NetworkIdentity abi.MethodNum
 }{MethodConstructor, 2}
 
+var MethodsAccount = struct {
+	Constructor                abi.MethodNum
+	PubkeyAddress              abi.MethodNum
+	AuthenticateMessage        abi.MethodNum
+	AuthorizeSessionKey        abi.MethodNum
+	RevokeSessionKey           abi.MethodNum
+	AuthenticateSessionMessage abi.MethodNum
+}{MethodConstructor, 2, 3, 4, 5, 6}
+
 var MethodsInit = struct {
-	Constructor abi.MethodNum
-	Exec        abi.MethodNum
-}{MethodConstructor, 2}
+	Constructor         abi.MethodNum
+	Exec                abi.MethodNum
+	Exec4               abi.MethodNum
+	LookupRobustAddress abi.MethodNum
+	TombstoneActor      abi.MethodNum
+	AddressStatus       abi.MethodNum
+	ApproveUpgrade      abi.MethodNum
+	AuthorizeUpgrade    abi.MethodNum
+}{MethodConstructor, 2, 3, 4, 5, 6, 7, 8}
 
 var MethodsCron = struct {
-	Constructor abi.MethodNum
-	EpochTick   abi.MethodNum
-}{MethodConstructor, 2}
+	Constructor         abi.MethodNum
+	EpochTick           abi.MethodNum
+	RegisterUserEntry   abi.MethodNum
+	DeregisterUserEntry abi.MethodNum
+}{MethodConstructor, 2, 3, 4}
 
 var MethodsReward = struct {
 	Constructor      abi.MethodNum
@@ -42,26 +59,32 @@ var MethodsMultisig = struct {
 	SwapSigner                  abi.MethodNum
 	ChangeNumApprovalsThreshold abi.MethodNum
 	LockBalance                 abi.MethodNum
-}{MethodConstructor, 2, 3, 4, 5, 6, 7, 8, 9}
+	PruneExpired                abi.MethodNum
+	ApproveBySessionKey         abi.MethodNum
+}{MethodConstructor, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}
 
 var MethodsPaych = struct {
 	Constructor        abi.MethodNum
 	UpdateChannelState abi.MethodNum
 	Settle             abi.MethodNum
 	Collect            abi.MethodNum
-}{MethodConstructor, 2, 3, 4}
+	PartialCollect     abi.MethodNum
+}{MethodConstructor, 2, 3, 4, 5}
 
 var MethodsMarket = struct {
-	Constructor              abi.MethodNum
-	AddBalance               abi.MethodNum
-	WithdrawBalance          abi.MethodNum
-	PublishStorageDeals      abi.MethodNum
-	VerifyDealsForActivation abi.MethodNum
-	ActivateDeals            abi.MethodNum
-	OnMinerSectorsTerminate  abi.MethodNum
-	ComputeDataCommitment    abi.MethodNum
-	CronTick                 abi.MethodNum
-}{MethodConstructor, 2, 3, 4, 5, 6, 7, 8, 9}
+	Constructor                  abi.MethodNum
+	AddBalance                   abi.MethodNum
+	WithdrawBalance              abi.MethodNum
+	PublishStorageDeals          abi.MethodNum
+	VerifyDealsForActivation     abi.MethodNum
+	ActivateDeals                abi.MethodNum
+	OnMinerSectorsTerminate      abi.MethodNum
+	ComputeDataCommitment        abi.MethodNum
+	CronTick                     abi.MethodNum
+	GetDeals                     abi.MethodNum
+	GetDealsByProvider           abi.MethodNum
+	PublishStorageDealsAggregate abi.MethodNum
+}{MethodConstructor, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
 
 var MethodsPower = struct {
 	Constructor              abi.MethodNum
@@ -73,7 +96,8 @@ var MethodsPower = struct {
 	Deprecated1              abi.MethodNum
 	SubmitPoRepForBulkVerify abi.MethodNum
 	CurrentTotalPower        abi.MethodNum
-}{MethodConstructor, 2, 3, 4, 5, 6, 7, 8, 9}
+	CurrentSmoothedEstimates abi.MethodNum
+}{MethodConstructor, 2, 3, 4, 5, 6, 7, 8, 9, 10}
 
 var MethodsMiner = struct {
 	Constructor              abi.MethodNum
@@ -99,13 +123,25 @@ var MethodsMiner = struct {
 	ConfirmUpdateWorkerKey   abi.MethodNum
 	RepayDebt                abi.MethodNum
 	ChangeOwnerAddress       abi.MethodNum
-}{MethodConstructor, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23}
+	ProveCommitSectors       abi.MethodNum
+	PreCommitSectorBatch     abi.MethodNum
+	SubmitWindowedPoStBatch  abi.MethodNum
+}{MethodConstructor, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26}
 
 var MethodsVerifiedRegistry = struct {
-	Constructor       abi.MethodNum
-	AddVerifier       abi.MethodNum
-	RemoveVerifier    abi.MethodNum
-	AddVerifiedClient abi.MethodNum
-	UseBytes          abi.MethodNum
-	RestoreBytes      abi.MethodNum
-}{MethodConstructor, 2, 3, 4, 5, 6}
+	Constructor                 abi.MethodNum
+	AddVerifier                 abi.MethodNum
+	RemoveVerifier              abi.MethodNum
+	AddVerifiedClient           abi.MethodNum
+	UseBytes                    abi.MethodNum
+	RestoreBytes                abi.MethodNum
+	GetDataCapEvents            abi.MethodNum
+	AddVerifiedClients          abi.MethodNum
+	CheckClientSeparation       abi.MethodNum
+	RemoveVerifiedClientDataCap abi.MethodNum
+}{MethodConstructor, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+var MethodsParameterRegistry = struct {
+	Constructor abi.MethodNum
+	Get         abi.MethodNum
+}{MethodConstructor, 2}
@@ -8,6 +8,9 @@ import (
 
 	address "github.com/filecoin-project/go-address"
 	abi "github.com/filecoin-project/go-state-types/abi"
+	network "github.com/filecoin-project/go-state-types/network"
+	miner "github.com/filecoin-project/specs-actors/actors/builtin/miner"
+	acbor "github.com/filecoin-project/specs-actors/v2/actors/util/cbor"
 	cid "github.com/ipfs/go-cid"
 	cbg "github.com/whyrusleeping/cbor-gen"
 	xerrors "golang.org/x/xerrors"
@@ -122,14 +125,14 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "miner.State", "")
 	}
 	if maj != cbg.MajArray {
-		return fmt.Errorf("cbor input should be of type array")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "miner.State", "")
 	}
 
 	if extra != 14 {
-		return fmt.Errorf("cbor input had wrong number of fields")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "miner.State", "")
 	}
 
 	// t.Info (cid.Cid) (struct)
@@ -138,7 +141,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 		c, err := cbg.ReadCid(br)
 		if err != nil {
-			return xerrors.Errorf("failed to read cid field t.Info: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read cid field t.Info: %w", err), "miner.State", "Info")
 		}
 
 		t.Info = c
@@ -149,7 +152,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.PreCommitDeposits.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.PreCommitDeposits: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.PreCommitDeposits: %w", err), "miner.State", "PreCommitDeposits")
 		}
 
 	}
@@ -158,7 +161,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.LockedFunds.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.LockedFunds: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.LockedFunds: %w", err), "miner.State", "LockedFunds")
 		}
 
 	}
@@ -168,7 +171,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 		c, err := cbg.ReadCid(br)
 		if err != nil {
-			return xerrors.Errorf("failed to read cid field t.VestingFunds: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read cid field t.VestingFunds: %w", err), "miner.State", "VestingFunds")
 		}
 
 		t.VestingFunds = c
@@ -179,7 +182,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.FeeDebt.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.FeeDebt: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.FeeDebt: %w", err), "miner.State", "FeeDebt")
 		}
 
 	}
@@ -188,7 +191,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.InitialPledge.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.InitialPledge: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.InitialPledge: %w", err), "miner.State", "InitialPledge")
 		}
 
 	}
@@ -198,7 +201,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 		c, err := cbg.ReadCid(br)
 		if err != nil {
-			return xerrors.Errorf("failed to read cid field t.PreCommittedSectors: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read cid field t.PreCommittedSectors: %w", err), "miner.State", "PreCommittedSectors")
 		}
 
 		t.PreCommittedSectors = c
@@ -210,7 +213,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 		c, err := cbg.ReadCid(br)
 		if err != nil {
-			return xerrors.Errorf("failed to read cid field t.PreCommittedSectorsExpiry: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read cid field t.PreCommittedSectorsExpiry: %w", err), "miner.State", "PreCommittedSectorsExpiry")
 		}
 
 		t.PreCommittedSectorsExpiry = c
@@ -222,7 +225,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 		c, err := cbg.ReadCid(br)
 		if err != nil {
-			return xerrors.Errorf("failed to read cid field t.AllocatedSectors: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read cid field t.AllocatedSectors: %w", err), "miner.State", "AllocatedSectors")
 		}
 
 		t.AllocatedSectors = c
@@ -234,7 +237,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 		c, err := cbg.ReadCid(br)
 		if err != nil {
-			return xerrors.Errorf("failed to read cid field t.Sectors: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read cid field t.Sectors: %w", err), "miner.State", "Sectors")
 		}
 
 		t.Sectors = c
@@ -245,22 +248,22 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 		var extraI int64
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "miner.State", "ProvingPeriodStart")
 		}
 		switch maj {
 		case cbg.MajUnsignedInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 positive overflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "miner.State", "ProvingPeriodStart")
 			}
 		case cbg.MajNegativeInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 negative oveflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "miner.State", "ProvingPeriodStart")
 			}
 			extraI = -1 - extraI
 		default:
-			return fmt.Errorf("wrong type for int64 field: %d", maj)
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "miner.State", "ProvingPeriodStart")
 		}
 
 		t.ProvingPeriodStart = abi.ChainEpoch(extraI)
@@ -271,10 +274,10 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "miner.State", "CurrentDeadline")
 		}
 		if maj != cbg.MajUnsignedInt {
-			return fmt.Errorf("wrong type for uint64 field")
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "miner.State", "CurrentDeadline")
 		}
 		t.CurrentDeadline = uint64(extra)
 
@@ -285,7 +288,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 		c, err := cbg.ReadCid(br)
 		if err != nil {
-			return xerrors.Errorf("failed to read cid field t.Deadlines: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read cid field t.Deadlines: %w", err), "miner.State", "Deadlines")
 		}
 
 		t.Deadlines = c
@@ -296,7 +299,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.EarlyTerminations.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.EarlyTerminations: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.EarlyTerminations: %w", err), "miner.State", "EarlyTerminations")
 		}
 
 	}
@@ -429,14 +432,14 @@ func (t *MinerInfo) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "miner.MinerInfo", "")
 	}
 	if maj != cbg.MajArray {
-		return fmt.Errorf("cbor input should be of type array")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "miner.MinerInfo", "")
 	}
 
 	if extra != 11 {
-		return fmt.Errorf("cbor input had wrong number of fields")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "miner.MinerInfo", "")
 	}
 
 	// t.Owner (address.Address) (struct)
@@ -444,7 +447,7 @@ func (t *MinerInfo) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.Owner.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.Owner: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Owner: %w", err), "miner.MinerInfo", "Owner")
 		}
 
 	}
@@ -453,7 +456,7 @@ func (t *MinerInfo) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.Worker.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.Worker: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Worker: %w", err), "miner.MinerInfo", "Worker")
 		}
 
 	}
@@ -461,15 +464,15 @@ func (t *MinerInfo) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "miner.MinerInfo", "ControlAddresses")
 	}
 
 	if extra > cbg.MaxLength {
-		return fmt.Errorf("t.ControlAddresses: array too large (%d)", extra)
+		return acbor.WrapDecodeError(fmt.Errorf("t.ControlAddresses: array too large (%d)", extra), "miner.MinerInfo", "ControlAddresses")
 	}
 
 	if maj != cbg.MajArray {
-		return fmt.Errorf("expected cbor array")
+		return acbor.WrapDecodeError(fmt.Errorf("expected cbor array"), "miner.MinerInfo", "ControlAddresses")
 	}
 
 	if extra > 0 {
@@ -480,7 +483,7 @@ func (t *MinerInfo) UnmarshalCBOR(r io.Reader) error {
 
 		var v address.Address
 		if err := v.UnmarshalCBOR(br); err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "miner.MinerInfo", fmt.Sprintf("ControlAddresses[%d]", i))
 		}
 
 		t.ControlAddresses[i] = v
@@ -492,15 +495,15 @@ func (t *MinerInfo) UnmarshalCBOR(r io.Reader) error {
 
 		b, err := br.ReadByte()
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "miner.MinerInfo", "PendingWorkerKey")
 		}
 		if b != cbg.CborNull[0] {
 			if err := br.UnreadByte(); err != nil {
-				return err
+				return acbor.WrapDecodeError(err, "miner.MinerInfo", "PendingWorkerKey")
 			}
 			t.PendingWorkerKey = new(WorkerKeyChange)
 			if err := t.PendingWorkerKey.UnmarshalCBOR(br); err != nil {
-				return xerrors.Errorf("unmarshaling t.PendingWorkerKey pointer: %w", err)
+				return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.PendingWorkerKey pointer: %w", err), "miner.MinerInfo", "PendingWorkerKey")
 			}
 		}
 
@@ -509,14 +512,14 @@ func (t *MinerInfo) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "miner.MinerInfo", "PeerId")
 	}
 
 	if extra > cbg.ByteArrayMaxLen {
-		return fmt.Errorf("t.PeerId: byte array too large (%d)", extra)
+		return acbor.WrapDecodeError(fmt.Errorf("t.PeerId: byte array too large (%d)", extra), "miner.MinerInfo", "PeerId")
 	}
 	if maj != cbg.MajByteString {
-		return fmt.Errorf("expected byte array")
+		return acbor.WrapDecodeError(fmt.Errorf("expected byte array"), "miner.MinerInfo", "PeerId")
 	}
 
 	if extra > 0 {
@@ -524,21 +527,21 @@ func (t *MinerInfo) UnmarshalCBOR(r io.Reader) error {
 	}
 
 	if _, err := io.ReadFull(br, t.PeerId[:]); err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "miner.MinerInfo", "PeerId")
 	}
 	// t.Multiaddrs ([][]uint8) (slice)
 
 	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "miner.MinerInfo", "Multiaddrs")
 	}
 
 	if extra > cbg.MaxLength {
-		return fmt.Errorf("t.Multiaddrs: array too large (%d)", extra)
+		return acbor.WrapDecodeError(fmt.Errorf("t.Multiaddrs: array too large (%d)", extra), "miner.MinerInfo", "Multiaddrs")
 	}
 
 	if maj != cbg.MajArray {
-		return fmt.Errorf("expected cbor array")
+		return acbor.WrapDecodeError(fmt.Errorf("expected cbor array"), "miner.MinerInfo", "Multiaddrs")
 	}
 
 	if extra > 0 {
@@ -553,14 +556,14 @@ func (t *MinerInfo) UnmarshalCBOR(r io.Reader) error {
 
 			maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
 			if err != nil {
-				return err
+				return acbor.WrapDecodeError(err, "miner.MinerInfo", fmt.Sprintf("Multiaddrs[%d]", i))
 			}
 
 			if extra > cbg.ByteArrayMaxLen {
-				return fmt.Errorf("t.Multiaddrs[i]: byte array too large (%d)", extra)
+				return acbor.WrapDecodeError(fmt.Errorf("t.Multiaddrs[i]: byte array too large (%d)", extra), "miner.MinerInfo", fmt.Sprintf("Multiaddrs[%d]", i))
 			}
 			if maj != cbg.MajByteString {
-				return fmt.Errorf("expected byte array")
+				return acbor.WrapDecodeError(fmt.Errorf("expected byte array"), "miner.MinerInfo", fmt.Sprintf("Multiaddrs[%d]", i))
 			}
 
 			if extra > 0 {
@@ -568,7 +571,7 @@ func (t *MinerInfo) UnmarshalCBOR(r io.Reader) error {
 			}
 
 			if _, err := io.ReadFull(br, t.Multiaddrs[i][:]); err != nil {
-				return err
+				return acbor.WrapDecodeError(err, "miner.MinerInfo", fmt.Sprintf("Multiaddrs[%d]", i))
 			}
 		}
 	}
@@ -578,22 +581,22 @@ func (t *MinerInfo) UnmarshalCBOR(r io.Reader) error {
 		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 		var extraI int64
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "miner.MinerInfo", "SealProofType")
 		}
 		switch maj {
 		case cbg.MajUnsignedInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 positive overflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "miner.MinerInfo", "SealProofType")
 			}
 		case cbg.MajNegativeInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 negative oveflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "miner.MinerInfo", "SealProofType")
 			}
 			extraI = -1 - extraI
 		default:
-			return fmt.Errorf("wrong type for int64 field: %d", maj)
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "miner.MinerInfo", "SealProofType")
 		}
 
 		t.SealProofType = abi.RegisteredSealProof(extraI)
@@ -604,10 +607,10 @@ func (t *MinerInfo) UnmarshalCBOR(r io.Reader) error {
 
 		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "miner.MinerInfo", "SectorSize")
 		}
 		if maj != cbg.MajUnsignedInt {
-			return fmt.Errorf("wrong type for uint64 field")
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "miner.MinerInfo", "SectorSize")
 		}
 		t.SectorSize = abi.SectorSize(extra)
 
@@ -618,10 +621,10 @@ func (t *MinerInfo) UnmarshalCBOR(r io.Reader) error {
 
 		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "miner.MinerInfo", "WindowPoStPartitionSectors")
 		}
 		if maj != cbg.MajUnsignedInt {
-			return fmt.Errorf("wrong type for uint64 field")
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "miner.MinerInfo", "WindowPoStPartitionSectors")
 		}
 		t.WindowPoStPartitionSectors = uint64(extra)
 
@@ -631,22 +634,22 @@ func (t *MinerInfo) UnmarshalCBOR(r io.Reader) error {
 		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 		var extraI int64
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "miner.MinerInfo", "ConsensusFaultElapsed")
 		}
 		switch maj {
 		case cbg.MajUnsignedInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 positive overflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "miner.MinerInfo", "ConsensusFaultElapsed")
 			}
 		case cbg.MajNegativeInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 negative oveflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "miner.MinerInfo", "ConsensusFaultElapsed")
 			}
 			extraI = -1 - extraI
 		default:
-			return fmt.Errorf("wrong type for int64 field: %d", maj)
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "miner.MinerInfo", "ConsensusFaultElapsed")
 		}
 
 		t.ConsensusFaultElapsed = abi.ChainEpoch(extraI)
@@ -657,15 +660,15 @@ func (t *MinerInfo) UnmarshalCBOR(r io.Reader) error {
 
 		b, err := br.ReadByte()
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "miner.MinerInfo", "PendingOwnerAddress")
 		}
 		if b != cbg.CborNull[0] {
 			if err := br.UnreadByte(); err != nil {
-				return err
+				return acbor.WrapDecodeError(err, "miner.MinerInfo", "PendingOwnerAddress")
 			}
 			t.PendingOwnerAddress = new(address.Address)
 			if err := t.PendingOwnerAddress.UnmarshalCBOR(br); err != nil {
-				return xerrors.Errorf("unmarshaling t.PendingOwnerAddress pointer: %w", err)
+				return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.PendingOwnerAddress pointer: %w", err), "miner.MinerInfo", "PendingOwnerAddress")
 			}
 		}
 
@@ -710,33 +713,33 @@ func (t *Deadlines) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "miner.Deadlines", "")
 	}
 	if maj != cbg.MajArray {
-		return fmt.Errorf("cbor input should be of type array")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "miner.Deadlines", "")
 	}
 
 	if extra != 1 {
-		return fmt.Errorf("cbor input had wrong number of fields")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "miner.Deadlines", "")
 	}
 
 	// t.Due ([48]cid.Cid) (array)
 
 	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "miner.Deadlines", "Due")
 	}
 
 	if extra > cbg.MaxLength {
-		return fmt.Errorf("t.Due: array too large (%d)", extra)
+		return acbor.WrapDecodeError(fmt.Errorf("t.Due: array too large (%d)", extra), "miner.Deadlines", "Due")
 	}
 
 	if maj != cbg.MajArray {
-		return fmt.Errorf("expected cbor array")
+		return acbor.WrapDecodeError(fmt.Errorf("expected cbor array"), "miner.Deadlines", "Due")
 	}
 
 	if extra != 48 {
-		return fmt.Errorf("expected array to have 48 elements")
+		return acbor.WrapDecodeError(fmt.Errorf("expected array to have 48 elements"), "miner.Deadlines", "Due")
 	}
 
 	t.Due = [48]cid.Cid{}
@@ -745,7 +748,7 @@ func (t *Deadlines) UnmarshalCBOR(r io.Reader) error {
 
 		c, err := cbg.ReadCid(br)
 		if err != nil {
-			return xerrors.Errorf("reading cid field t.Due failed: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("reading cid field t.Due failed: %w", err), "miner.Deadlines", fmt.Sprintf("Due[%d]", i))
 		}
 		t.Due[i] = c
 	}
@@ -815,14 +818,14 @@ func (t *Deadline) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "miner.Deadline", "")
 	}
 	if maj != cbg.MajArray {
-		return fmt.Errorf("cbor input should be of type array")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "miner.Deadline", "")
 	}
 
 	if extra != 7 {
-		return fmt.Errorf("cbor input had wrong number of fields")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "miner.Deadline", "")
 	}
 
 	// t.Partitions (cid.Cid) (struct)
@@ -831,7 +834,7 @@ func (t *Deadline) UnmarshalCBOR(r io.Reader) error {
 
 		c, err := cbg.ReadCid(br)
 		if err != nil {
-			return xerrors.Errorf("failed to read cid field t.Partitions: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read cid field t.Partitions: %w", err), "miner.Deadline", "Partitions")
 		}
 
 		t.Partitions = c
@@ -843,7 +846,7 @@ func (t *Deadline) UnmarshalCBOR(r io.Reader) error {
 
 		c, err := cbg.ReadCid(br)
 		if err != nil {
-			return xerrors.Errorf("failed to read cid field t.ExpirationsEpochs: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read cid field t.ExpirationsEpochs: %w", err), "miner.Deadline", "ExpirationsEpochs")
 		}
 
 		t.ExpirationsEpochs = c
@@ -854,7 +857,7 @@ func (t *Deadline) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.PostSubmissions.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.PostSubmissions: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.PostSubmissions: %w", err), "miner.Deadline", "PostSubmissions")
 		}
 
 	}
@@ -863,7 +866,7 @@ func (t *Deadline) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.EarlyTerminations.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.EarlyTerminations: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.EarlyTerminations: %w", err), "miner.Deadline", "EarlyTerminations")
 		}
 
 	}
@@ -873,10 +876,10 @@ func (t *Deadline) UnmarshalCBOR(r io.Reader) error {
 
 		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "miner.Deadline", "LiveSectors")
 		}
 		if maj != cbg.MajUnsignedInt {
-			return fmt.Errorf("wrong type for uint64 field")
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "miner.Deadline", "LiveSectors")
 		}
 		t.LiveSectors = uint64(extra)
 
@@ -887,10 +890,10 @@ func (t *Deadline) UnmarshalCBOR(r io.Reader) error {
 
 		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "miner.Deadline", "TotalSectors")
 		}
 		if maj != cbg.MajUnsignedInt {
-			return fmt.Errorf("wrong type for uint64 field")
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "miner.Deadline", "TotalSectors")
 		}
 		t.TotalSectors = uint64(extra)
 
@@ -900,7 +903,7 @@ func (t *Deadline) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.FaultyPower.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.FaultyPower: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.FaultyPower: %w", err), "miner.Deadline", "FaultyPower")
 		}
 
 	}
@@ -987,14 +990,14 @@ func (t *Partition) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "miner.Partition", "")
 	}
 	if maj != cbg.MajArray {
-		return fmt.Errorf("cbor input should be of type array")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "miner.Partition", "")
 	}
 
 	if extra != 11 {
-		return fmt.Errorf("cbor input had wrong number of fields")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "miner.Partition", "")
 	}
 
 	// t.Sectors (bitfield.BitField) (struct)
@@ -1002,7 +1005,7 @@ func (t *Partition) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.Sectors.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.Sectors: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Sectors: %w", err), "miner.Partition", "Sectors")
 		}
 
 	}
@@ -1011,7 +1014,7 @@ func (t *Partition) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.Unproven.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.Unproven: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Unproven: %w", err), "miner.Partition", "Unproven")
 		}
 
 	}
@@ -1020,7 +1023,7 @@ func (t *Partition) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.Faults.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.Faults: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Faults: %w", err), "miner.Partition", "Faults")
 		}
 
 	}
@@ -1029,7 +1032,7 @@ func (t *Partition) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.Recoveries.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.Recoveries: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Recoveries: %w", err), "miner.Partition", "Recoveries")
 		}
 
 	}
@@ -1038,7 +1041,7 @@ func (t *Partition) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.Terminated.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.Terminated: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Terminated: %w", err), "miner.Partition", "Terminated")
 		}
 
 	}
@@ -1048,7 +1051,7 @@ func (t *Partition) UnmarshalCBOR(r io.Reader) error {
 
 		c, err := cbg.ReadCid(br)
 		if err != nil {
-			return xerrors.Errorf("failed to read cid field t.ExpirationsEpochs: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read cid field t.ExpirationsEpochs: %w", err), "miner.Partition", "ExpirationsEpochs")
 		}
 
 		t.ExpirationsEpochs = c
@@ -1060,7 +1063,7 @@ func (t *Partition) UnmarshalCBOR(r io.Reader) error {
 
 		c, err := cbg.ReadCid(br)
 		if err != nil {
-			return xerrors.Errorf("failed to read cid field t.EarlyTerminated: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read cid field t.EarlyTerminated: %w", err), "miner.Partition", "EarlyTerminated")
 		}
 
 		t.EarlyTerminated = c
@@ -1071,7 +1074,7 @@ func (t *Partition) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.LivePower.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.LivePower: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.LivePower: %w", err), "miner.Partition", "LivePower")
 		}
 
 	}
@@ -1080,7 +1083,7 @@ func (t *Partition) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.UnprovenPower.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.UnprovenPower: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.UnprovenPower: %w", err), "miner.Partition", "UnprovenPower")
 		}
 
 	}
@@ -1089,7 +1092,7 @@ func (t *Partition) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.FaultyPower.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.FaultyPower: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.FaultyPower: %w", err), "miner.Partition", "FaultyPower")
 		}
 
 	}
@@ -1098,7 +1101,7 @@ func (t *Partition) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.RecoveringPower.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.RecoveringPower: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.RecoveringPower: %w", err), "miner.Partition", "RecoveringPower")
 		}
 
 	}
@@ -1151,14 +1154,14 @@ func (t *ExpirationSet) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "miner.ExpirationSet", "")
 	}
 	if maj != cbg.MajArray {
-		return fmt.Errorf("cbor input should be of type array")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "miner.ExpirationSet", "")
 	}
 
 	if extra != 5 {
-		return fmt.Errorf("cbor input had wrong number of fields")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "miner.ExpirationSet", "")
 	}
 
 	// t.OnTimeSectors (bitfield.BitField) (struct)
@@ -1166,7 +1169,7 @@ func (t *ExpirationSet) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.OnTimeSectors.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.OnTimeSectors: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.OnTimeSectors: %w", err), "miner.ExpirationSet", "OnTimeSectors")
 		}
 
 	}
@@ -1175,7 +1178,7 @@ func (t *ExpirationSet) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.EarlySectors.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.EarlySectors: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.EarlySectors: %w", err), "miner.ExpirationSet", "EarlySectors")
 		}
 
 	}
@@ -1184,7 +1187,7 @@ func (t *ExpirationSet) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.OnTimePledge.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.OnTimePledge: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.OnTimePledge: %w", err), "miner.ExpirationSet", "OnTimePledge")
 		}
 
 	}
@@ -1193,7 +1196,7 @@ func (t *ExpirationSet) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.ActivePower.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.ActivePower: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.ActivePower: %w", err), "miner.ExpirationSet", "ActivePower")
 		}
 
 	}
@@ -1202,7 +1205,7 @@ func (t *ExpirationSet) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.FaultyPower.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.FaultyPower: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.FaultyPower: %w", err), "miner.ExpirationSet", "FaultyPower")
 		}
 
 	}
@@ -1240,14 +1243,14 @@ func (t *PowerPair) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "miner.PowerPair", "")
 	}
 	if maj != cbg.MajArray {
-		return fmt.Errorf("cbor input should be of type array")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "miner.PowerPair", "")
 	}
 
 	if extra != 2 {
-		return fmt.Errorf("cbor input had wrong number of fields")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "miner.PowerPair", "")
 	}
 
 	// t.Raw (big.Int) (struct)
@@ -1255,7 +1258,7 @@ func (t *PowerPair) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.Raw.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.Raw: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Raw: %w", err), "miner.PowerPair", "Raw")
 		}
 
 	}
@@ -1264,7 +1267,7 @@ func (t *PowerPair) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.QA.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.QA: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.QA: %w", err), "miner.PowerPair", "QA")
 		}
 
 	}
@@ -1325,14 +1328,14 @@ func (t *SectorPreCommitOnChainInfo) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "miner.SectorPreCommitOnChainInfo", "")
 	}
 	if maj != cbg.MajArray {
-		return fmt.Errorf("cbor input should be of type array")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "miner.SectorPreCommitOnChainInfo", "")
 	}
 
 	if extra != 5 {
-		return fmt.Errorf("cbor input had wrong number of fields")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "miner.SectorPreCommitOnChainInfo", "")
 	}
 
 	// t.Info (miner.SectorPreCommitInfo) (struct)
@@ -1340,7 +1343,7 @@ func (t *SectorPreCommitOnChainInfo) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.Info.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.Info: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Info: %w", err), "miner.SectorPreCommitOnChainInfo", "Info")
 		}
 
 	}
@@ -1349,7 +1352,7 @@ func (t *SectorPreCommitOnChainInfo) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.PreCommitDeposit.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.PreCommitDeposit: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.PreCommitDeposit: %w", err), "miner.SectorPreCommitOnChainInfo", "PreCommitDeposit")
 		}
 
 	}
@@ -1358,22 +1361,22 @@ func (t *SectorPreCommitOnChainInfo) UnmarshalCBOR(r io.Reader) error {
 		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 		var extraI int64
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "miner.SectorPreCommitOnChainInfo", "PreCommitEpoch")
 		}
 		switch maj {
 		case cbg.MajUnsignedInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 positive overflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "miner.SectorPreCommitOnChainInfo", "PreCommitEpoch")
 			}
 		case cbg.MajNegativeInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 negative oveflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "miner.SectorPreCommitOnChainInfo", "PreCommitEpoch")
 			}
 			extraI = -1 - extraI
 		default:
-			return fmt.Errorf("wrong type for int64 field: %d", maj)
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "miner.SectorPreCommitOnChainInfo", "PreCommitEpoch")
 		}
 
 		t.PreCommitEpoch = abi.ChainEpoch(extraI)
@@ -1383,7 +1386,7 @@ func (t *SectorPreCommitOnChainInfo) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.DealWeight.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.DealWeight: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.DealWeight: %w", err), "miner.SectorPreCommitOnChainInfo", "DealWeight")
 		}
 
 	}
@@ -1392,7 +1395,7 @@ func (t *SectorPreCommitOnChainInfo) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.VerifiedDealWeight.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.VerifiedDealWeight: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.VerifiedDealWeight: %w", err), "miner.SectorPreCommitOnChainInfo", "VerifiedDealWeight")
 		}
 
 	}
@@ -1505,14 +1508,14 @@ func (t *SectorPreCommitInfo) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "miner.SectorPreCommitInfo", "")
 	}
 	if maj != cbg.MajArray {
-		return fmt.Errorf("cbor input should be of type array")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "miner.SectorPreCommitInfo", "")
 	}
 
 	if extra != 10 {
-		return fmt.Errorf("cbor input had wrong number of fields")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "miner.SectorPreCommitInfo", "")
 	}
 
 	// t.SealProof (abi.RegisteredSealProof) (int64)
@@ -1520,22 +1523,22 @@ func (t *SectorPreCommitInfo) UnmarshalCBOR(r io.Reader) error {
 		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 		var extraI int64
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "miner.SectorPreCommitInfo", "SealProof")
 		}
 		switch maj {
 		case cbg.MajUnsignedInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 positive overflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "miner.SectorPreCommitInfo", "SealProof")
 			}
 		case cbg.MajNegativeInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 negative oveflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "miner.SectorPreCommitInfo", "SealProof")
 			}
 			extraI = -1 - extraI
 		default:
-			return fmt.Errorf("wrong type for int64 field: %d", maj)
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "miner.SectorPreCommitInfo", "SealProof")
 		}
 
 		t.SealProof = abi.RegisteredSealProof(extraI)
@@ -1546,10 +1549,10 @@ func (t *SectorPreCommitInfo) UnmarshalCBOR(r io.Reader) error {
 
 		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "miner.SectorPreCommitInfo", "SectorNumber")
 		}
 		if maj != cbg.MajUnsignedInt {
-			return fmt.Errorf("wrong type for uint64 field")
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "miner.SectorPreCommitInfo", "SectorNumber")
 		}
 		t.SectorNumber = abi.SectorNumber(extra)
 
@@ -1560,7 +1563,7 @@ func (t *SectorPreCommitInfo) UnmarshalCBOR(r io.Reader) error {
 
 		c, err := cbg.ReadCid(br)
 		if err != nil {
-			return xerrors.Errorf("failed to read cid field t.SealedCID: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read cid field t.SealedCID: %w", err), "miner.SectorPreCommitInfo", "SealedCID")
 		}
 
 		t.SealedCID = c
@@ -1571,22 +1574,22 @@ func (t *SectorPreCommitInfo) UnmarshalCBOR(r io.Reader) error {
 		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 		var extraI int64
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "miner.SectorPreCommitInfo", "SealRandEpoch")
 		}
 		switch maj {
 		case cbg.MajUnsignedInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 positive overflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "miner.SectorPreCommitInfo", "SealRandEpoch")
 			}
 		case cbg.MajNegativeInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 negative oveflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "miner.SectorPreCommitInfo", "SealRandEpoch")
 			}
 			extraI = -1 - extraI
 		default:
-			return fmt.Errorf("wrong type for int64 field: %d", maj)
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "miner.SectorPreCommitInfo", "SealRandEpoch")
 		}
 
 		t.SealRandEpoch = abi.ChainEpoch(extraI)
@@ -1595,15 +1598,15 @@ func (t *SectorPreCommitInfo) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "miner.SectorPreCommitInfo", "DealIDs")
 	}
 
 	if extra > cbg.MaxLength {
-		return fmt.Errorf("t.DealIDs: array too large (%d)", extra)
+		return acbor.WrapDecodeError(fmt.Errorf("t.DealIDs: array too large (%d)", extra), "miner.SectorPreCommitInfo", "DealIDs")
 	}
 
 	if maj != cbg.MajArray {
-		return fmt.Errorf("expected cbor array")
+		return acbor.WrapDecodeError(fmt.Errorf("expected cbor array"), "miner.SectorPreCommitInfo", "DealIDs")
 	}
 
 	if extra > 0 {
@@ -1614,11 +1617,11 @@ func (t *SectorPreCommitInfo) UnmarshalCBOR(r io.Reader) error {
 
 		maj, val, err := cbg.CborReadHeaderBuf(br, scratch)
 		if err != nil {
-			return xerrors.Errorf("failed to read uint64 for t.DealIDs slice: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read uint64 for t.DealIDs slice: %w", err), "miner.SectorPreCommitInfo", fmt.Sprintf("DealIDs[%d]", i))
 		}
 
 		if maj != cbg.MajUnsignedInt {
-			return xerrors.Errorf("value read for array t.DealIDs was not a uint, instead got %d", maj)
+			return acbor.WrapDecodeError(xerrors.Errorf("value read for array t.DealIDs was not a uint, instead got %d", maj), "miner.SectorPreCommitInfo", fmt.Sprintf("DealIDs[%d]", i))
 		}
 
 		t.DealIDs[i] = abi.DealID(val)
@@ -1629,22 +1632,22 @@ func (t *SectorPreCommitInfo) UnmarshalCBOR(r io.Reader) error {
 		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 		var extraI int64
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "miner.SectorPreCommitInfo", "Expiration")
 		}
 		switch maj {
 		case cbg.MajUnsignedInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 positive overflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "miner.SectorPreCommitInfo", "Expiration")
 			}
 		case cbg.MajNegativeInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 negative oveflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "miner.SectorPreCommitInfo", "Expiration")
 			}
 			extraI = -1 - extraI
 		default:
-			return fmt.Errorf("wrong type for int64 field: %d", maj)
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "miner.SectorPreCommitInfo", "Expiration")
 		}
 
 		t.Expiration = abi.ChainEpoch(extraI)
@@ -1653,10 +1656,10 @@ func (t *SectorPreCommitInfo) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "miner.SectorPreCommitInfo", "ReplaceCapacity")
 	}
 	if maj != cbg.MajOther {
-		return fmt.Errorf("booleans must be major type 7")
+		return acbor.WrapDecodeError(fmt.Errorf("booleans must be major type 7"), "miner.SectorPreCommitInfo", "ReplaceCapacity")
 	}
 	switch extra {
 	case 20:
@@ -1664,7 +1667,7 @@ func (t *SectorPreCommitInfo) UnmarshalCBOR(r io.Reader) error {
 	case 21:
 		t.ReplaceCapacity = true
 	default:
-		return fmt.Errorf("booleans are either major type 7, value 20 or 21 (got %d)", extra)
+		return acbor.WrapDecodeError(fmt.Errorf("booleans are either major type 7, value 20 or 21 (got %d)", extra), "miner.SectorPreCommitInfo", "ReplaceCapacity")
 	}
 	// t.ReplaceSectorDeadline (uint64) (uint64)
 
@@ -1672,10 +1675,10 @@ func (t *SectorPreCommitInfo) UnmarshalCBOR(r io.Reader) error {
 
 		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "miner.SectorPreCommitInfo", "ReplaceSectorDeadline")
 		}
 		if maj != cbg.MajUnsignedInt {
-			return fmt.Errorf("wrong type for uint64 field")
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "miner.SectorPreCommitInfo", "ReplaceSectorDeadline")
 		}
 		t.ReplaceSectorDeadline = uint64(extra)
 
@@ -1686,10 +1689,10 @@ func (t *SectorPreCommitInfo) UnmarshalCBOR(r io.Reader) error {
 
 		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "miner.SectorPreCommitInfo", "ReplaceSectorPartition")
 		}
 		if maj != cbg.MajUnsignedInt {
-			return fmt.Errorf("wrong type for uint64 field")
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "miner.SectorPreCommitInfo", "ReplaceSectorPartition")
 		}
 		t.ReplaceSectorPartition = uint64(extra)
 
@@ -1700,10 +1703,10 @@ func (t *SectorPreCommitInfo) UnmarshalCBOR(r io.Reader) error {
 
 		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "miner.SectorPreCommitInfo", "ReplaceSectorNumber")
 		}
 		if maj != cbg.MajUnsignedInt {
-			return fmt.Errorf("wrong type for uint64 field")
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "miner.SectorPreCommitInfo", "ReplaceSectorNumber")
 		}
 		t.ReplaceSectorNumber = abi.SectorNumber(extra)
 
@@ -1834,14 +1837,14 @@ func (t *SectorOnChainInfo) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "miner.SectorOnChainInfo", "")
 	}
 	if maj != cbg.MajArray {
-		return fmt.Errorf("cbor input should be of type array")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "miner.SectorOnChainInfo", "")
 	}
 
 	if extra != 13 {
-		return fmt.Errorf("cbor input had wrong number of fields")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "miner.SectorOnChainInfo", "")
 	}
 
 	// t.SectorNumber (abi.SectorNumber) (uint64)
@@ -1850,10 +1853,10 @@ func (t *SectorOnChainInfo) UnmarshalCBOR(r io.Reader) error {
 
 		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "miner.SectorOnChainInfo", "SectorNumber")
 		}
 		if maj != cbg.MajUnsignedInt {
-			return fmt.Errorf("wrong type for uint64 field")
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "miner.SectorOnChainInfo", "SectorNumber")
 		}
 		t.SectorNumber = abi.SectorNumber(extra)
 
@@ -1863,22 +1866,22 @@ func (t *SectorOnChainInfo) UnmarshalCBOR(r io.Reader) error {
 		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 		var extraI int64
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "miner.SectorOnChainInfo", "SealProof")
 		}
 		switch maj {
 		case cbg.MajUnsignedInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 positive overflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "miner.SectorOnChainInfo", "SealProof")
 			}
 		case cbg.MajNegativeInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 negative oveflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "miner.SectorOnChainInfo", "SealProof")
 			}
 			extraI = -1 - extraI
 		default:
-			return fmt.Errorf("wrong type for int64 field: %d", maj)
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "miner.SectorOnChainInfo", "SealProof")
 		}
 
 		t.SealProof = abi.RegisteredSealProof(extraI)
@@ -1889,7 +1892,7 @@ func (t *SectorOnChainInfo) UnmarshalCBOR(r io.Reader) error {
 
 		c, err := cbg.ReadCid(br)
 		if err != nil {
-			return xerrors.Errorf("failed to read cid field t.SealedCID: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read cid field t.SealedCID: %w", err), "miner.SectorOnChainInfo", "SealedCID")
 		}
 
 		t.SealedCID = c
@@ -1899,15 +1902,15 @@ func (t *SectorOnChainInfo) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "miner.SectorOnChainInfo", "DealIDs")
 	}
 
 	if extra > cbg.MaxLength {
-		return fmt.Errorf("t.DealIDs: array too large (%d)", extra)
+		return acbor.WrapDecodeError(fmt.Errorf("t.DealIDs: array too large (%d)", extra), "miner.SectorOnChainInfo", "DealIDs")
 	}
 
 	if maj != cbg.MajArray {
-		return fmt.Errorf("expected cbor array")
+		return acbor.WrapDecodeError(fmt.Errorf("expected cbor array"), "miner.SectorOnChainInfo", "DealIDs")
 	}
 
 	if extra > 0 {
@@ -1918,11 +1921,11 @@ func (t *SectorOnChainInfo) UnmarshalCBOR(r io.Reader) error {
 
 		maj, val, err := cbg.CborReadHeaderBuf(br, scratch)
 		if err != nil {
-			return xerrors.Errorf("failed to read uint64 for t.DealIDs slice: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read uint64 for t.DealIDs slice: %w", err), "miner.SectorOnChainInfo", fmt.Sprintf("DealIDs[%d]", i))
 		}
 
 		if maj != cbg.MajUnsignedInt {
-			return xerrors.Errorf("value read for array t.DealIDs was not a uint, instead got %d", maj)
+			return acbor.WrapDecodeError(xerrors.Errorf("value read for array t.DealIDs was not a uint, instead got %d", maj), "miner.SectorOnChainInfo", fmt.Sprintf("DealIDs[%d]", i))
 		}
 
 		t.DealIDs[i] = abi.DealID(val)
@@ -1933,22 +1936,22 @@ func (t *SectorOnChainInfo) UnmarshalCBOR(r io.Reader) error {
 		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 		var extraI int64
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "miner.SectorOnChainInfo", "Activation")
 		}
 		switch maj {
 		case cbg.MajUnsignedInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 positive overflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "miner.SectorOnChainInfo", "Activation")
 			}
 		case cbg.MajNegativeInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 negative oveflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "miner.SectorOnChainInfo", "Activation")
 			}
 			extraI = -1 - extraI
 		default:
-			return fmt.Errorf("wrong type for int64 field: %d", maj)
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "miner.SectorOnChainInfo", "Activation")
 		}
 
 		t.Activation = abi.ChainEpoch(extraI)
@@ -1958,22 +1961,22 @@ func (t *SectorOnChainInfo) UnmarshalCBOR(r io.Reader) error {
 		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 		var extraI int64
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "miner.SectorOnChainInfo", "Expiration")
 		}
 		switch maj {
 		case cbg.MajUnsignedInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 positive overflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "miner.SectorOnChainInfo", "Expiration")
 			}
 		case cbg.MajNegativeInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 negative oveflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "miner.SectorOnChainInfo", "Expiration")
 			}
 			extraI = -1 - extraI
 		default:
-			return fmt.Errorf("wrong type for int64 field: %d", maj)
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "miner.SectorOnChainInfo", "Expiration")
 		}
 
 		t.Expiration = abi.ChainEpoch(extraI)
@@ -1983,7 +1986,7 @@ func (t *SectorOnChainInfo) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.DealWeight.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.DealWeight: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.DealWeight: %w", err), "miner.SectorOnChainInfo", "DealWeight")
 		}
 
 	}
@@ -1992,7 +1995,7 @@ func (t *SectorOnChainInfo) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.VerifiedDealWeight.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.VerifiedDealWeight: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.VerifiedDealWeight: %w", err), "miner.SectorOnChainInfo", "VerifiedDealWeight")
 		}
 
 	}
@@ -2001,7 +2004,7 @@ func (t *SectorOnChainInfo) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.InitialPledge.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.InitialPledge: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.InitialPledge: %w", err), "miner.SectorOnChainInfo", "InitialPledge")
 		}
 
 	}
@@ -2010,7 +2013,7 @@ func (t *SectorOnChainInfo) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.ExpectedDayReward.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.ExpectedDayReward: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.ExpectedDayReward: %w", err), "miner.SectorOnChainInfo", "ExpectedDayReward")
 		}
 
 	}
@@ -2019,7 +2022,7 @@ func (t *SectorOnChainInfo) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.ExpectedStoragePledge.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.ExpectedStoragePledge: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.ExpectedStoragePledge: %w", err), "miner.SectorOnChainInfo", "ExpectedStoragePledge")
 		}
 
 	}
@@ -2028,22 +2031,22 @@ func (t *SectorOnChainInfo) UnmarshalCBOR(r io.Reader) error {
 		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 		var extraI int64
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "miner.SectorOnChainInfo", "ReplacedSectorAge")
 		}
 		switch maj {
 		case cbg.MajUnsignedInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 positive overflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "miner.SectorOnChainInfo", "ReplacedSectorAge")
 			}
 		case cbg.MajNegativeInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 negative oveflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "miner.SectorOnChainInfo", "ReplacedSectorAge")
 			}
 			extraI = -1 - extraI
 		default:
-			return fmt.Errorf("wrong type for int64 field: %d", maj)
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "miner.SectorOnChainInfo", "ReplacedSectorAge")
 		}
 
 		t.ReplacedSectorAge = abi.ChainEpoch(extraI)
@@ -2053,7 +2056,7 @@ func (t *SectorOnChainInfo) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.ReplacedDayReward.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.ReplacedDayReward: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.ReplacedDayReward: %w", err), "miner.SectorOnChainInfo", "ReplacedDayReward")
 		}
 
 	}
@@ -2099,14 +2102,14 @@ func (t *WorkerKeyChange) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "miner.WorkerKeyChange", "")
 	}
 	if maj != cbg.MajArray {
-		return fmt.Errorf("cbor input should be of type array")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "miner.WorkerKeyChange", "")
 	}
 
 	if extra != 2 {
-		return fmt.Errorf("cbor input had wrong number of fields")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "miner.WorkerKeyChange", "")
 	}
 
 	// t.NewWorker (address.Address) (struct)
@@ -2114,7 +2117,7 @@ func (t *WorkerKeyChange) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.NewWorker.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.NewWorker: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.NewWorker: %w", err), "miner.WorkerKeyChange", "NewWorker")
 		}
 
 	}
@@ -2123,22 +2126,22 @@ func (t *WorkerKeyChange) UnmarshalCBOR(r io.Reader) error {
 		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 		var extraI int64
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "miner.WorkerKeyChange", "EffectiveAt")
 		}
 		switch maj {
 		case cbg.MajUnsignedInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 positive overflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "miner.WorkerKeyChange", "EffectiveAt")
 			}
 		case cbg.MajNegativeInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 negative oveflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "miner.WorkerKeyChange", "EffectiveAt")
 			}
 			extraI = -1 - extraI
 		default:
-			return fmt.Errorf("wrong type for int64 field: %d", maj)
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "miner.WorkerKeyChange", "EffectiveAt")
 		}
 
 		t.EffectiveAt = abi.ChainEpoch(extraI)
@@ -2183,29 +2186,29 @@ func (t *VestingFunds) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "miner.VestingFunds", "")
 	}
 	if maj != cbg.MajArray {
-		return fmt.Errorf("cbor input should be of type array")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "miner.VestingFunds", "")
 	}
 
 	if extra != 1 {
-		return fmt.Errorf("cbor input had wrong number of fields")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "miner.VestingFunds", "")
 	}
 
 	// t.Funds ([]miner.VestingFund) (slice)
 
 	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "miner.VestingFunds", "Funds")
 	}
 
 	if extra > cbg.MaxLength {
-		return fmt.Errorf("t.Funds: array too large (%d)", extra)
+		return acbor.WrapDecodeError(fmt.Errorf("t.Funds: array too large (%d)", extra), "miner.VestingFunds", "Funds")
 	}
 
 	if maj != cbg.MajArray {
-		return fmt.Errorf("expected cbor array")
+		return acbor.WrapDecodeError(fmt.Errorf("expected cbor array"), "miner.VestingFunds", "Funds")
 	}
 
 	if extra > 0 {
@@ -2216,7 +2219,7 @@ func (t *VestingFunds) UnmarshalCBOR(r io.Reader) error {
 
 		var v VestingFund
 		if err := v.UnmarshalCBOR(br); err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "miner.VestingFunds", fmt.Sprintf("Funds[%d]", i))
 		}
 
 		t.Funds[i] = v
@@ -2264,14 +2267,14 @@ func (t *VestingFund) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "miner.VestingFund", "")
 	}
 	if maj != cbg.MajArray {
-		return fmt.Errorf("cbor input should be of type array")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "miner.VestingFund", "")
 	}
 
 	if extra != 2 {
-		return fmt.Errorf("cbor input had wrong number of fields")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "miner.VestingFund", "")
 	}
 
 	// t.Epoch (abi.ChainEpoch) (int64)
@@ -2279,22 +2282,22 @@ func (t *VestingFund) UnmarshalCBOR(r io.Reader) error {
 		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 		var extraI int64
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "miner.VestingFund", "Epoch")
 		}
 		switch maj {
 		case cbg.MajUnsignedInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 positive overflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "miner.VestingFund", "Epoch")
 			}
 		case cbg.MajNegativeInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 negative oveflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "miner.VestingFund", "Epoch")
 			}
 			extraI = -1 - extraI
 		default:
-			return fmt.Errorf("wrong type for int64 field: %d", maj)
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "miner.VestingFund", "Epoch")
 		}
 
 		t.Epoch = abi.ChainEpoch(extraI)
@@ -2304,13 +2307,83 @@ func (t *VestingFund) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.Amount.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.Amount: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Amount: %w", err), "miner.VestingFund", "Amount")
 		}
 
 	}
 	return nil
 }
 
+var lengthBufChangePeerIDParams = []byte{129}
+
+func (t *ChangePeerIDParams) marshalCBORTuple(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufChangePeerIDParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.NewID ([]uint8) (slice)
+	if len(t.NewID) > cbg.ByteArrayMaxLen {
+		return xerrors.Errorf("Byte array in field t.NewID was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajByteString, uint64(len(t.NewID))); err != nil {
+		return err
+	}
+
+	if _, err := w.Write(t.NewID[:]); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *ChangePeerIDParams) unmarshalCBORTuple(r io.Reader) error {
+	*t = ChangePeerIDParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "miner.ChangePeerIDParams", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "miner.ChangePeerIDParams", "")
+	}
+
+	if extra != 1 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "miner.ChangePeerIDParams", "")
+	}
+
+	// t.NewID ([]uint8) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "miner.ChangePeerIDParams", "NewID")
+	}
+
+	if extra > cbg.ByteArrayMaxLen {
+		return acbor.WrapDecodeError(fmt.Errorf("t.NewID: byte array too large (%d)", extra), "miner.ChangePeerIDParams", "NewID")
+	}
+	if maj != cbg.MajByteString {
+		return acbor.WrapDecodeError(fmt.Errorf("expected byte array"), "miner.ChangePeerIDParams", "NewID")
+	}
+
+	if extra > 0 {
+		t.NewID = make([]uint8, extra)
+	}
+
+	if _, err := io.ReadFull(br, t.NewID[:]); err != nil {
+		return acbor.WrapDecodeError(err, "miner.ChangePeerIDParams", "NewID")
+	}
+	return nil
+}
+
 var lengthBufGetControlAddressesReturn = []byte{131}
 
 func (t *GetControlAddressesReturn) MarshalCBOR(w io.Writer) error {
@@ -2358,14 +2431,14 @@ func (t *GetControlAddressesReturn) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "miner.GetControlAddressesReturn", "")
 	}
 	if maj != cbg.MajArray {
-		return fmt.Errorf("cbor input should be of type array")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "miner.GetControlAddressesReturn", "")
 	}
 
 	if extra != 3 {
-		return fmt.Errorf("cbor input had wrong number of fields")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "miner.GetControlAddressesReturn", "")
 	}
 
 	// t.Owner (address.Address) (struct)
@@ -2373,7 +2446,7 @@ func (t *GetControlAddressesReturn) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.Owner.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.Owner: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Owner: %w", err), "miner.GetControlAddressesReturn", "Owner")
 		}
 
 	}
@@ -2382,7 +2455,7 @@ func (t *GetControlAddressesReturn) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.Worker.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.Worker: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Worker: %w", err), "miner.GetControlAddressesReturn", "Worker")
 		}
 
 	}
@@ -2390,15 +2463,15 @@ func (t *GetControlAddressesReturn) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "miner.GetControlAddressesReturn", "ControlAddrs")
 	}
 
 	if extra > cbg.MaxLength {
-		return fmt.Errorf("t.ControlAddrs: array too large (%d)", extra)
+		return acbor.WrapDecodeError(fmt.Errorf("t.ControlAddrs: array too large (%d)", extra), "miner.GetControlAddressesReturn", "ControlAddrs")
 	}
 
 	if maj != cbg.MajArray {
-		return fmt.Errorf("expected cbor array")
+		return acbor.WrapDecodeError(fmt.Errorf("expected cbor array"), "miner.GetControlAddressesReturn", "ControlAddrs")
 	}
 
 	if extra > 0 {
@@ -2409,7 +2482,7 @@ func (t *GetControlAddressesReturn) UnmarshalCBOR(r io.Reader) error {
 
 		var v address.Address
 		if err := v.UnmarshalCBOR(br); err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "miner.GetControlAddressesReturn", fmt.Sprintf("ControlAddrs[%d]", i))
 		}
 
 		t.ControlAddrs[i] = v
@@ -2417,3 +2490,579 @@ func (t *GetControlAddressesReturn) UnmarshalCBOR(r io.Reader) error {
 
 	return nil
 }
+
+var lengthBufProveCommitSectorsParams = []byte{129}
+
+func (t *ProveCommitSectorsParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufProveCommitSectorsParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Sectors ([]miner.ProveCommitSectorParams) (slice)
+	if len(t.Sectors) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Sectors was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Sectors))); err != nil {
+		return err
+	}
+	for _, v := range t.Sectors {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *ProveCommitSectorsParams) UnmarshalCBOR(r io.Reader) error {
+	*t = ProveCommitSectorsParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "miner.ProveCommitSectorsParams", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "miner.ProveCommitSectorsParams", "")
+	}
+
+	if extra != 1 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "miner.ProveCommitSectorsParams", "")
+	}
+
+	// t.Sectors ([]miner.ProveCommitSectorParams) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "miner.ProveCommitSectorsParams", "Sectors")
+	}
+
+	if extra > cbg.MaxLength {
+		return acbor.WrapDecodeError(fmt.Errorf("t.Sectors: array too large (%d)", extra), "miner.ProveCommitSectorsParams", "Sectors")
+	}
+
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("expected cbor array"), "miner.ProveCommitSectorsParams", "Sectors")
+	}
+
+	if extra > 0 {
+		t.Sectors = make([]miner.ProveCommitSectorParams, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v miner.ProveCommitSectorParams
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(err, "miner.ProveCommitSectorsParams", fmt.Sprintf("Sectors[%d]", i))
+		}
+
+		t.Sectors[i] = v
+	}
+
+	return nil
+}
+
+var lengthBufProveCommitSectorsReturn = []byte{129}
+
+func (t *ProveCommitSectorsReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufProveCommitSectorsReturn); err != nil {
+		return err
+	}
+
+	// t.Activated (bitfield.BitField) (struct)
+	if err := t.Activated.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *ProveCommitSectorsReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = ProveCommitSectorsReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "miner.ProveCommitSectorsReturn", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "miner.ProveCommitSectorsReturn", "")
+	}
+
+	if extra != 1 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "miner.ProveCommitSectorsReturn", "")
+	}
+
+	// t.Activated (bitfield.BitField) (struct)
+
+	{
+
+		if err := t.Activated.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Activated: %w", err), "miner.ProveCommitSectorsReturn", "Activated")
+		}
+
+	}
+	return nil
+}
+
+var lengthBufPreCommitSectorBatchParams = []byte{129}
+
+func (t *PreCommitSectorBatchParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufPreCommitSectorBatchParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Sectors ([]miner.SectorPreCommitInfo) (slice)
+	if len(t.Sectors) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Sectors was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Sectors))); err != nil {
+		return err
+	}
+	for _, v := range t.Sectors {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *PreCommitSectorBatchParams) UnmarshalCBOR(r io.Reader) error {
+	*t = PreCommitSectorBatchParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "miner.PreCommitSectorBatchParams", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "miner.PreCommitSectorBatchParams", "")
+	}
+
+	if extra != 1 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "miner.PreCommitSectorBatchParams", "")
+	}
+
+	// t.Sectors ([]miner.SectorPreCommitInfo) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "miner.PreCommitSectorBatchParams", "Sectors")
+	}
+
+	if extra > cbg.MaxLength {
+		return acbor.WrapDecodeError(fmt.Errorf("t.Sectors: array too large (%d)", extra), "miner.PreCommitSectorBatchParams", "Sectors")
+	}
+
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("expected cbor array"), "miner.PreCommitSectorBatchParams", "Sectors")
+	}
+
+	if extra > 0 {
+		t.Sectors = make([]miner.SectorPreCommitInfo, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v miner.SectorPreCommitInfo
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(err, "miner.PreCommitSectorBatchParams", fmt.Sprintf("Sectors[%d]", i))
+		}
+
+		t.Sectors[i] = v
+	}
+
+	return nil
+}
+
+var lengthBufSubmitWindowedPoStBatchParams = []byte{129}
+
+func (t *SubmitWindowedPoStBatchParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufSubmitWindowedPoStBatchParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Posts ([]miner.SubmitWindowedPoStParams) (slice)
+	if len(t.Posts) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Posts was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Posts))); err != nil {
+		return err
+	}
+	for _, v := range t.Posts {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *SubmitWindowedPoStBatchParams) UnmarshalCBOR(r io.Reader) error {
+	*t = SubmitWindowedPoStBatchParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "miner.SubmitWindowedPoStBatchParams", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "miner.SubmitWindowedPoStBatchParams", "")
+	}
+
+	if extra != 1 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "miner.SubmitWindowedPoStBatchParams", "")
+	}
+
+	// t.Posts ([]miner.SubmitWindowedPoStParams) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "miner.SubmitWindowedPoStBatchParams", "Posts")
+	}
+
+	if extra > cbg.MaxLength {
+		return acbor.WrapDecodeError(fmt.Errorf("t.Posts: array too large (%d)", extra), "miner.SubmitWindowedPoStBatchParams", "Posts")
+	}
+
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("expected cbor array"), "miner.SubmitWindowedPoStBatchParams", "Posts")
+	}
+
+	if extra > 0 {
+		t.Posts = make([]miner.SubmitWindowedPoStParams, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v miner.SubmitWindowedPoStParams
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(err, "miner.SubmitWindowedPoStBatchParams", fmt.Sprintf("Posts[%d]", i))
+		}
+
+		t.Posts[i] = v
+	}
+
+	return nil
+}
+
+var lengthBufWithdrawBalanceParams = []byte{130}
+
+func (t *WithdrawBalanceParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufWithdrawBalanceParams); err != nil {
+		return err
+	}
+
+	// t.AmountRequested (big.Int) (struct)
+	if err := t.AmountRequested.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Recipient (address.Address) (struct)
+	if t.Recipient == nil {
+		if _, err := w.Write(cbg.CborNull); err != nil {
+			return err
+		}
+	} else if err := t.Recipient.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *WithdrawBalanceParams) UnmarshalCBOR(r io.Reader) error {
+	*t = WithdrawBalanceParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "miner.WithdrawBalanceParams", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "miner.WithdrawBalanceParams", "")
+	}
+
+	fieldCount := extra
+	if fieldCount < 1 || fieldCount > 2 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "miner.WithdrawBalanceParams", "")
+	}
+
+	// t.AmountRequested (big.Int) (struct)
+
+	{
+
+		if err := t.AmountRequested.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.AmountRequested: %w", err), "miner.WithdrawBalanceParams", "AmountRequested")
+		}
+
+	}
+	if fieldCount <= 1 {
+		return nil
+	}
+	// t.Recipient (address.Address) (struct)
+
+	{
+
+		b, err := br.ReadByte()
+		if err != nil {
+			return acbor.WrapDecodeError(err, "miner.WithdrawBalanceParams", "Recipient")
+		}
+		if b != cbg.CborNull[0] {
+			if err := br.UnreadByte(); err != nil {
+				return acbor.WrapDecodeError(err, "miner.WithdrawBalanceParams", "Recipient")
+			}
+			t.Recipient = new(address.Address)
+			if err := t.Recipient.UnmarshalCBOR(br); err != nil {
+				return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Recipient pointer: %w", err), "miner.WithdrawBalanceParams", "Recipient")
+			}
+		}
+
+	}
+	return nil
+}
+
+func (t *ChangePeerIDParams) marshalCBORMap(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write([]byte{161}); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.NewID ([]uint8) (slice)
+	if len("NewID") > cbg.MaxLength {
+		return xerrors.Errorf("Value in field \"NewID\" was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajTextString, uint64(len("NewID"))); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, string("NewID")); err != nil {
+		return err
+	}
+
+	if len(t.NewID) > cbg.ByteArrayMaxLen {
+		return xerrors.Errorf("Byte array in field t.NewID was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajByteString, uint64(len(t.NewID))); err != nil {
+		return err
+	}
+
+	if _, err := w.Write(t.NewID[:]); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *ChangePeerIDParams) unmarshalCBORMap(r io.Reader) error {
+	*t = ChangePeerIDParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "miner.ChangePeerIDParams", "")
+	}
+	if maj != cbg.MajMap {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type map"), "miner.ChangePeerIDParams", "")
+	}
+
+	if extra > 1 {
+		return acbor.WrapDecodeError(fmt.Errorf("ChangePeerIDParams: map struct too large (%d)", extra), "miner.ChangePeerIDParams", "")
+	}
+
+	var name string
+	n := extra
+
+	for i := uint64(0); i < n; i++ {
+
+		{
+			sval, err := cbg.ReadStringBuf(br, scratch)
+			if err != nil {
+				return acbor.WrapDecodeError(err, "miner.ChangePeerIDParams", "")
+			}
+
+			name = string(sval)
+		}
+
+		switch name {
+		// t.NewID ([]uint8) (slice)
+		case "NewID":
+
+			maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+			if err != nil {
+				return acbor.WrapDecodeError(err, "miner.ChangePeerIDParams", "NewID")
+			}
+
+			if extra > cbg.ByteArrayMaxLen {
+				return acbor.WrapDecodeError(fmt.Errorf("t.NewID: byte array too large (%d)", extra), "miner.ChangePeerIDParams", "NewID")
+			}
+			if maj != cbg.MajByteString {
+				return acbor.WrapDecodeError(fmt.Errorf("expected byte array"), "miner.ChangePeerIDParams", "NewID")
+			}
+
+			if extra > 0 {
+				t.NewID = make([]uint8, extra)
+			}
+
+			if _, err := io.ReadFull(br, t.NewID[:]); err != nil {
+				return acbor.WrapDecodeError(err, "miner.ChangePeerIDParams", "NewID")
+			}
+
+		default:
+			return acbor.WrapDecodeError(fmt.Errorf("unknown struct field %d: '%s'", i, name), "miner.ChangePeerIDParams", "")
+		}
+	}
+
+	return nil
+}
+
+func (t *ChangePeerIDParams) MarshalCBOR(w io.Writer) error {
+	return t.marshalCBORTuple(w)
+}
+
+func (t *ChangePeerIDParams) MarshalCBORForVersion(w io.Writer, nv network.Version) error {
+	if nv >= t.MapEncodingVersion() {
+		return t.marshalCBORMap(w)
+	}
+	return t.marshalCBORTuple(w)
+}
+
+func (t *ChangePeerIDParams) MapEncodingVersion() network.Version {
+	return 6
+}
+
+func (t *ChangePeerIDParams) UnmarshalCBOR(r io.Reader) error {
+	br := cbg.GetPeeker(r)
+	first, err := br.ReadByte()
+	if err != nil {
+		return err
+	}
+	if err := br.UnreadByte(); err != nil {
+		return err
+	}
+	if first>>5 == cbg.MajMap {
+		return t.unmarshalCBORMap(br)
+	}
+	return t.unmarshalCBORTuple(br)
+}
+
+func (t *State) MaxEncodedLength() int64 {
+	return 36929
+}
+
+func (t *MinerInfo) MaxEncodedLength() int64 {
+	return 17182548290
+}
+
+func (t *Deadlines) MaxEncodedLength() int64 {
+	return 24819
+}
+
+func (t *Deadline) MaxEncodedLength() int64 {
+	return 66856
+}
+
+func (t *Partition) MaxEncodedLength() int64 {
+	return 165934
+}
+
+func (t *ExpirationSet) MaxEncodedLength() int64 {
+	return 66195
+}
+
+func (t *PowerPair) MaxEncodedLength() int64 {
+	return 261
+}
+
+func (t *SectorPreCommitOnChainInfo) MaxEncodedLength() int64 {
+	return 74713
+}
+
+func (t *SectorPreCommitInfo) MaxEncodedLength() int64 {
+	return 74313
+}
+
+func (t *SectorOnChainInfo) MaxEncodedLength() int64 {
+	return 75074
+}
+
+func (t *WorkerKeyChange) MaxEncodedLength() int64 {
+	return 76
+}
+
+func (t *VestingFunds) MaxEncodedLength() int64 {
+	return 1146884
+}
+
+func (t *VestingFund) MaxEncodedLength() int64 {
+	return 140
+}
+
+func (t *ChangePeerIDParams) MaxEncodedLength() int64 {
+	return 2097164
+}
+
+func (t *GetControlAddressesReturn) MaxEncodedLength() int64 {
+	return 540808
+}
+
+func (t *ProveCommitSectorsParams) MaxEncodedLength() int64 {
+	return 17179992068
+}
+
+func (t *ProveCommitSectorsReturn) MaxEncodedLength() int64 {
+	return 32772
+}
+
+func (t *PreCommitSectorBatchParams) MaxEncodedLength() int64 {
+	return 608772100
+}
+
+func (t *SubmitWindowedPoStBatchParams) MaxEncodedLength() int64 {
+	return 142955570774020
+}
+
+func (t *WithdrawBalanceParams) MaxEncodedLength() int64 {
+	return 197
+}
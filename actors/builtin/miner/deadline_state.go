@@ -565,7 +565,7 @@ func (dl *Deadline) RemovePartitions(store adt.Store, toRemove bitfield.BitField
 		return bitfield.BitField{}, bitfield.BitField{}, NewPowerPairZero(), xerrors.Errorf("failed to check for early terminations: %w", err)
 	}
 	if !noEarlyTerminations {
-		return bitfield.BitField{}, bitfield.BitField{}, NewPowerPairZero(), xerrors.Errorf("cannot remove partitions from deadline with early terminations: %w", err)
+		return bitfield.BitField{}, bitfield.BitField{}, NewPowerPairZero(), xc.ErrForbidden.Wrapf("cannot remove partitions from deadline with unprocessed early terminations")
 	}
 
 	newPartitions := adt.MakeEmptyArray(store)
@@ -674,18 +674,21 @@ func (dl *Deadline) RemovePartitions(store adt.Store, toRemove bitfield.BitField
 	return live, dead, removedPower, nil
 }
 
+// Marks sectors in the deadline's partitions faulty, expiring at faultExpirationEpoch.
+// Returns the sectors newly faulty, and the power delta.
 func (dl *Deadline) DeclareFaults(
 	store adt.Store, sectors Sectors, ssize abi.SectorSize, quant QuantSpec,
 	faultExpirationEpoch abi.ChainEpoch, partitionSectors PartitionSectorMap,
-) (powerDelta PowerPair, err error) {
+) (newFaults bitfield.BitField, powerDelta PowerPair, err error) {
 	partitions, err := dl.PartitionsArray(store)
 	if err != nil {
-		return NewPowerPairZero(), err
+		return bitfield.BitField{}, NewPowerPairZero(), err
 	}
 
 	// Record partitions with some fault, for subsequently indexing in the deadline.
 	// Duplicate entries don't matter, they'll be stored in a bitfield (a set).
 	partitionsWithFault := make([]uint64, 0, len(partitionSectors))
+	var newFaultsByPartition []bitfield.BitField
 	powerDelta = NewPowerPairZero()
 	if err := partitionSectors.ForEach(func(partIdx uint64, sectorNos bitfield.BitField) error {
 		var partition Partition
@@ -695,7 +698,7 @@ func (dl *Deadline) DeclareFaults(
 			return xc.ErrNotFound.Wrapf("no such partition %d", partIdx)
 		}
 
-		newFaults, partitionPowerDelta, partitionNewFaultyPower, err := partition.DeclareFaults(
+		partitionNewFaults, partitionPowerDelta, partitionNewFaultyPower, err := partition.DeclareFaults(
 			store, sectors, sectorNos, faultExpirationEpoch, ssize, quant,
 		)
 		if err != nil {
@@ -703,10 +706,11 @@ func (dl *Deadline) DeclareFaults(
 		}
 		dl.FaultyPower = dl.FaultyPower.Add(partitionNewFaultyPower)
 		powerDelta = powerDelta.Add(partitionPowerDelta)
-		if empty, err := newFaults.IsEmpty(); err != nil {
+		if empty, err := partitionNewFaults.IsEmpty(); err != nil {
 			return xerrors.Errorf("failed to count new faults: %w", err)
 		} else if !empty {
 			partitionsWithFault = append(partitionsWithFault, partIdx)
+			newFaultsByPartition = append(newFaultsByPartition, partitionNewFaults)
 		}
 
 		err = partitions.Set(partIdx, &partition)
@@ -716,20 +720,24 @@ func (dl *Deadline) DeclareFaults(
 
 		return nil
 	}); err != nil {
-		return NewPowerPairZero(), err
+		return bitfield.BitField{}, NewPowerPairZero(), err
 	}
 
 	dl.Partitions, err = partitions.Root()
 	if err != nil {
-		return NewPowerPairZero(), xc.ErrIllegalState.Wrapf("failed to store partitions root: %w", err)
+		return bitfield.BitField{}, NewPowerPairZero(), xc.ErrIllegalState.Wrapf("failed to store partitions root: %w", err)
 	}
 
 	err = dl.AddExpirationPartitions(store, faultExpirationEpoch, partitionsWithFault, quant)
 	if err != nil {
-		return NewPowerPairZero(), xc.ErrIllegalState.Wrapf("failed to update expirations for partitions with faults: %w", err)
+		return bitfield.BitField{}, NewPowerPairZero(), xc.ErrIllegalState.Wrapf("failed to update expirations for partitions with faults: %w", err)
 	}
 
-	return powerDelta, nil
+	newFaults, err = bitfield.MultiMerge(newFaultsByPartition...)
+	if err != nil {
+		return bitfield.BitField{}, NewPowerPairZero(), xerrors.Errorf("failed to merge new faults: %w", err)
+	}
+	return newFaults, powerDelta, nil
 }
 
 func (dl *Deadline) DeclareFaultsRecovered(
@@ -7,6 +7,7 @@ import (
 
 	"github.com/filecoin-project/go-bitfield"
 	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/exitcode"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -174,7 +175,7 @@ func TestDeadlines(t *testing.T) {
 		addSectors(t, store, dl, proveFirst)
 
 		// Mark faulty.
-		powerDelta, err := dl.DeclareFaults(
+		newFaults, powerDelta, err := dl.DeclareFaults(
 			store, sectorsArr(t, store, sectors), sectorSize, quantSpec, 9,
 			map[uint64]bitfield.BitField{
 				0: bf(1),
@@ -182,6 +183,7 @@ func TestDeadlines(t *testing.T) {
 			},
 		)
 		require.NoError(t, err)
+		assertBitfieldEquals(t, newFaults, 1, 5, 6)
 
 		expectedPower := miner.NewPowerPairZero()
 		unproven := []uint64{2, 3, 4, 7, 8, 9} // not 1, 5, 6
@@ -265,6 +267,7 @@ func TestDeadlines(t *testing.T) {
 
 		_, _, _, err := dl.RemovePartitions(store, bf(0), quantSpec)
 		require.Error(t, err, "should have failed to remove a partition with early terminations")
+		assert.Equal(t, exitcode.ErrForbidden, exitcode.Unwrap(err, exitcode.Ok))
 	})
 
 	t.Run("can pop early terminations in multiple steps", func(t *testing.T) {
@@ -740,12 +743,13 @@ func TestDeadlines(t *testing.T) {
 		}))
 
 		// Retract recovery for sector 1.
-		powerDelta, err := dl.DeclareFaults(store, sectorArr, sectorSize, quantSpec, 13, map[uint64]bitfield.BitField{
+		newFaults, powerDelta, err := dl.DeclareFaults(store, sectorArr, sectorSize, quantSpec, 13, map[uint64]bitfield.BitField{
 			0: bf(1),
 		})
 
 		// We're just retracting a recovery, this doesn't count as a new fault.
 		require.NoError(t, err)
+		assertBitfieldEmpty(t, newFaults)
 		require.True(t, powerDelta.Equals(miner.NewPowerPairZero()))
 
 		// We're now recovering 6.
@@ -846,7 +850,7 @@ func TestDeadlines(t *testing.T) {
 		sectorArr := sectorsArr(t, store, allSectors)
 
 		// Declare sectors 1 & 6 faulty.
-		_, err := dl.DeclareFaults(store, sectorArr, sectorSize, quantSpec, 17, map[uint64]bitfield.BitField{
+		_, _, err := dl.DeclareFaults(store, sectorArr, sectorSize, quantSpec, 17, map[uint64]bitfield.BitField{
 			0: bf(1),
 			4: bf(6),
 		})
@@ -7,6 +7,7 @@ import (
 	"github.com/filecoin-project/go-bitfield"
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/cbor"
 	"github.com/ipfs/go-cid"
 	"golang.org/x/xerrors"
 
@@ -179,15 +180,29 @@ func (q ExpirationQueue) AddActiveSectors(sectors []*SectorOnChainInfo, ssize ab
 	var totalSectors []bitfield.BitField
 	noEarlySectors := bitfield.New()
 	noFaultyPower := NewPowerPairZero()
-	for _, group := range groupNewSectorsByDeclaredExpiration(ssize, sectors, q.quant) {
+
+	// Load the entries for all groups at once, add to them, then write them back at once.
+	groups := groupNewSectorsByDeclaredExpiration(ssize, sectors, q.quant)
+	epochs := make([]abi.ChainEpoch, len(groups))
+	for i, group := range groups {
+		epochs[i] = group.epoch
+	}
+	sets, err := q.mayBatchGet(epochs)
+	if err != nil {
+		return bitfield.BitField{}, NewPowerPairZero(), big.Zero(), xerrors.Errorf("failed to record new sector expirations: %w", err)
+	}
+	for i, group := range groups {
 		snos := bitfield.NewFromSet(group.sectors)
-		if err := q.add(group.epoch, snos, noEarlySectors, group.power, noFaultyPower, group.pledge); err != nil {
-			return bitfield.BitField{}, NewPowerPairZero(), big.Zero(), xerrors.Errorf("failed to record new sector expirations: %w", err)
+		if err := sets[i].Add(snos, noEarlySectors, group.pledge, group.power, noFaultyPower); err != nil {
+			return bitfield.BitField{}, NewPowerPairZero(), big.Zero(), xerrors.Errorf("failed to add expiration values for epoch %v: %w", group.epoch, err)
 		}
 		totalSectors = append(totalSectors, snos)
 		totalPower = totalPower.Add(group.power)
 		totalPledge = big.Add(totalPledge, group.pledge)
 	}
+	if err := q.mustBatchUpdate(epochs, sets); err != nil {
+		return bitfield.BitField{}, NewPowerPairZero(), big.Zero(), xerrors.Errorf("failed to record new sector expirations: %w", err)
+	}
 	snos, err := bitfield.MultiMerge(totalSectors...)
 	if err != nil {
 		return bitfield.BitField{}, NewPowerPairZero(), big.Zero(), err
@@ -618,10 +633,19 @@ func (q ExpirationQueue) removeActiveSectors(sectors []*SectorOnChainInfo, ssize
 		return bitfield.BitField{}, NewPowerPairZero(), big.Zero(), err
 	}
 
-	for _, group := range groups {
+	// Load the entries for all groups at once, remove from them, then write them back (or delete them) at once.
+	epochs := make([]abi.ChainEpoch, len(groups))
+	for i, group := range groups {
+		epochs[i] = group.epoch
+	}
+	sets, err := q.mustBatchGet(epochs)
+	if err != nil {
+		return bitfield.BitField{}, NewPowerPairZero(), big.Zero(), err
+	}
+	for i, group := range groups {
 		sectorsBf := bitfield.NewFromSet(group.sectors)
-		if err := q.remove(group.epoch, sectorsBf, noEarlySectors, group.power, noFaultyPower, group.pledge); err != nil {
-			return bitfield.BitField{}, NewPowerPairZero(), big.Zero(), err
+		if err := sets[i].Remove(sectorsBf, noEarlySectors, group.pledge, group.power, noFaultyPower); err != nil {
+			return bitfield.BitField{}, NewPowerPairZero(), big.Zero(), xerrors.Errorf("failed to remove expiration values for queue epoch %v: %w", group.epoch, err)
 		}
 		for _, n := range group.sectors {
 			removedSnos.Set(n)
@@ -629,6 +653,9 @@ func (q ExpirationQueue) removeActiveSectors(sectors []*SectorOnChainInfo, ssize
 		removedPower = removedPower.Add(group.power)
 		removedPledge = big.Add(removedPledge, group.pledge)
 	}
+	if err := q.mustBatchUpdateOrDelete(epochs, sets); err != nil {
+		return bitfield.BitField{}, NewPowerPairZero(), big.Zero(), err
+	}
 	return removedSnos, removedPower, removedPledge, nil
 }
 
@@ -702,6 +729,82 @@ func (q ExpirationQueue) mustUpdateOrDelete(epoch abi.ChainEpoch, es *Expiration
 	return nil
 }
 
+// Loads the entries at a set of epochs, with an empty set for each epoch without an entry.
+func (q ExpirationQueue) mayBatchGet(epochs []abi.ChainEpoch) ([]*ExpirationSet, error) {
+	keys := make([]uint64, len(epochs))
+	sets := make([]*ExpirationSet, len(epochs))
+	outs := make([]cbor.Unmarshaler, len(epochs))
+	for i, epoch := range epochs {
+		keys[i] = uint64(epoch)
+		sets[i] = NewExpirationSetEmpty()
+		outs[i] = sets[i]
+	}
+	if _, err := q.Array.BatchGet(keys, outs); err != nil {
+		return nil, xerrors.Errorf("failed to lookup queue epochs: %w", err)
+	}
+	return sets, nil
+}
+
+// Loads the entries at a set of epochs, all of which must be present.
+func (q ExpirationQueue) mustBatchGet(epochs []abi.ChainEpoch) ([]*ExpirationSet, error) {
+	keys := make([]uint64, len(epochs))
+	sets := make([]*ExpirationSet, len(epochs))
+	outs := make([]cbor.Unmarshaler, len(epochs))
+	for i, epoch := range epochs {
+		keys[i] = uint64(epoch)
+		sets[i] = new(ExpirationSet)
+		outs[i] = sets[i]
+	}
+	found, err := q.Array.BatchGet(keys, outs)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to lookup queue epochs: %w", err)
+	}
+	for i, epoch := range epochs {
+		if !found[i] {
+			return nil, xerrors.Errorf("missing expected expiration set at epoch %v", epoch)
+		}
+	}
+	return sets, nil
+}
+
+func (q ExpirationQueue) mustBatchUpdate(epochs []abi.ChainEpoch, sets []*ExpirationSet) error {
+	keys := make([]uint64, len(epochs))
+	values := make([]cbor.Marshaler, len(epochs))
+	for i, epoch := range epochs {
+		keys[i] = uint64(epoch)
+		values[i] = sets[i]
+	}
+	if err := q.Array.BatchSet(keys, values); err != nil {
+		return xerrors.Errorf("failed to set queue epochs: %w", err)
+	}
+	return nil
+}
+
+// Writes the entries at a set of epochs, deleting those that are empty.
+// Since this might delete nodes, it's not safe for use inside an iteration.
+func (q ExpirationQueue) mustBatchUpdateOrDelete(epochs []abi.ChainEpoch, sets []*ExpirationSet) error {
+	var updated []abi.ChainEpoch
+	var updatedSets []*ExpirationSet
+	var emptied []uint64
+	for i, epoch := range epochs {
+		if empty, err := sets[i].IsEmpty(); err != nil {
+			return err
+		} else if empty {
+			emptied = append(emptied, uint64(epoch))
+		} else {
+			updated = append(updated, epoch)
+			updatedSets = append(updatedSets, sets[i])
+		}
+	}
+	if err := q.mustBatchUpdate(updated, updatedSets); err != nil {
+		return err
+	}
+	if err := q.Array.BatchDelete(emptied); err != nil {
+		return xerrors.Errorf("failed to delete queue epochs: %w", err)
+	}
+	return nil
+}
+
 type sectorEpochSet struct {
 	epoch   abi.ChainEpoch
 	sectors []uint64
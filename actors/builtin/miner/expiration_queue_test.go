@@ -2,7 +2,6 @@ package miner_test
 
 import (
 	"context"
-	"fmt"
 	"testing"
 
 	"github.com/filecoin-project/go-address"
@@ -14,8 +13,8 @@ import (
 
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/miner"
 	"github.com/filecoin-project/specs-actors/v2/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/v2/support/fixtures"
 	"github.com/filecoin-project/specs-actors/v2/support/mock"
-	tutil "github.com/filecoin-project/specs-actors/v2/support/testing"
 )
 
 func TestExpirationSet(t *testing.T) {
@@ -730,14 +729,11 @@ func TestExpirationQueue(t *testing.T) {
 }
 
 func testSector(expiration, number, weight, vweight, pledge int64) *miner.SectorOnChainInfo {
-	return &miner.SectorOnChainInfo{
-		Expiration:         abi.ChainEpoch(expiration),
-		SectorNumber:       abi.SectorNumber(number),
-		DealWeight:         big.NewInt(weight),
-		VerifiedDealWeight: big.NewInt(vweight),
-		InitialPledge:      abi.NewTokenAmount(pledge),
-		SealedCID:          tutil.MakeCID(fmt.Sprintf("commR-%d", number), &miner.SealedCIDPrefix),
-	}
+	return fixtures.NewSectorBuilder(abi.SectorNumber(number)).
+		WithExpiration(abi.ChainEpoch(expiration)).
+		WithDealWeights(big.NewInt(weight), big.NewInt(vweight)).
+		WithPledge(abi.NewTokenAmount(pledge)).
+		Build()
 }
 
 func requireNoExpirationGroupsBefore(t *testing.T, epoch abi.ChainEpoch, queue miner.ExpirationQueue) {
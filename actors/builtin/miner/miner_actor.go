@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
-	"math"
 
 	addr "github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-bitfield"
@@ -72,6 +71,9 @@ func (a Actor) Exports() []interface{} {
 		21:                        a.ConfirmUpdateWorkerKey,
 		22:                        a.RepayDebt,
 		23:                        a.ChangeOwnerAddress,
+		24:                        a.ProveCommitSectors,
+		25:                        a.PreCommitSectorBatch,
+		26:                        a.SubmitWindowedPoStBatch,
 	}
 }
 
@@ -281,10 +283,11 @@ func (a Actor) ChangeOwnerAddress(rt Runtime, newAddress *addr.Address) *abi.Emp
 	return nil
 }
 
-//type ChangePeerIDParams struct {
-//	NewID abi.PeerID
-//}
-type ChangePeerIDParams = miner0.ChangePeerIDParams
+// Encoded as a map keyed by field name from network version 6, as a prototype of the map encoding.
+// Either encoding is accepted from then on.
+type ChangePeerIDParams struct {
+	NewID abi.PeerID
+}
 
 func (a Actor) ChangePeerID(rt Runtime, params *ChangePeerIDParams) *abi.EmptyValue {
 	checkPeerInfo(rt, params.NewID, nil)
@@ -355,21 +358,52 @@ type SubmitWindowedPoStParams = miner0.SubmitWindowedPoStParams
 
 // Invoked by miner's worker address to submit their fallback post
 func (a Actor) SubmitWindowedPoSt(rt Runtime, params *SubmitWindowedPoStParams) *abi.EmptyValue {
+	submitWindowedPoSts(rt, []*SubmitWindowedPoStParams{params})
+	return nil
+}
+
+type SubmitWindowedPoStBatchParams struct {
+	Posts []SubmitWindowedPoStParams
+}
+
+// Submits several Window PoSts for the current deadline in one message, as for SubmitWindowedPoSt, e.g. to prove
+// more partitions than fit in a single submission.
+// Each submission is validated and recorded in turn, and their proofs are then verified together as a batch.
+// The batch succeeds or fails as a whole.
+func (a Actor) SubmitWindowedPoStBatch(rt Runtime, params *SubmitWindowedPoStBatchParams) *abi.EmptyValue {
+	if len(params.Posts) == 0 {
+		rt.Abortf(exitcode.ErrIllegalArgument, "no posts to submit")
+	}
+	if len(params.Posts) > MaxWindowPoStBatchSize {
+		rt.Abortf(exitcode.ErrIllegalArgument, "too many posts to submit %d, max %d", len(params.Posts), MaxWindowPoStBatchSize)
+	}
+	posts := make([]*SubmitWindowedPoStParams, len(params.Posts))
+	for i := range params.Posts {
+		posts[i] = &params.Posts[i]
+	}
+	submitWindowedPoSts(rt, posts)
+	return nil
+}
+
+// Validates and records Window PoSt submissions for the current deadline, verifying their proofs.
+func submitWindowedPoSts(rt Runtime, posts []*SubmitWindowedPoStParams) {
 	currEpoch := rt.CurrEpoch()
 	store := adt.AsStore(rt)
 	var st State
 
-	if params.Deadline >= WPoStPeriodDeadlines {
-		rt.Abortf(exitcode.ErrIllegalArgument, "invalid deadline %d of %d", params.Deadline, WPoStPeriodDeadlines)
-	}
-	// Technically, ChainCommitRand should be _exactly_ 32 bytes. However:
-	// 1. It's convenient to allow smaller slices when testing.
-	// 2. Nothing bad will happen if the caller provides too little randomness.
-	if len(params.ChainCommitRand) > abi.RandomnessLength {
-		rt.Abortf(exitcode.ErrIllegalArgument, "expected at most %d bytes of randomness, got %d", abi.RandomnessLength, len(params.ChainCommitRand))
+	for _, params := range posts {
+		if params.Deadline >= WPoStPeriodDeadlines {
+			rt.Abortf(exitcode.ErrIllegalArgument, "invalid deadline %d of %d", params.Deadline, WPoStPeriodDeadlines)
+		}
+		// Technically, ChainCommitRand should be _exactly_ 32 bytes. However:
+		// 1. It's convenient to allow smaller slices when testing.
+		// 2. Nothing bad will happen if the caller provides too little randomness.
+		if len(params.ChainCommitRand) > abi.RandomnessLength {
+			rt.Abortf(exitcode.ErrIllegalArgument, "expected at most %d bytes of randomness, got %d", abi.RandomnessLength, len(params.ChainCommitRand))
+		}
 	}
 
-	var postResult *PoStResult
+	powerDelta := NewPowerPairZero()
 
 	var info *MinerInfo
 	rt.StateTransaction(&st, func() {
@@ -377,25 +411,6 @@ func (a Actor) SubmitWindowedPoSt(rt Runtime, params *SubmitWindowedPoStParams)
 
 		rt.ValidateImmediateCallerIs(append(info.ControlAddresses, info.Owner, info.Worker)...)
 
-		// Verify that the miner has passed 0 or 1 proofs. If they've
-		// passed 1, verify that it's a good proof.
-		//
-		// This can be 0 if the miner isn't actually proving anything,
-		// just skipping all sectors.
-		windowPoStProofType, err := info.SealProofType.RegisteredWindowPoStProof()
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to determine window PoSt type")
-		if len(params.Proofs) != 1 {
-			rt.Abortf(exitcode.ErrIllegalArgument, "expected exactly one proof, got %d", len(params.Proofs))
-		} else if params.Proofs[0].PoStProof != windowPoStProofType {
-			rt.Abortf(exitcode.ErrIllegalArgument, "expected proof of type %s, got proof of type %s", params.Proofs[0], windowPoStProofType)
-		}
-
-		// Validate that the miner didn't try to prove too many partitions at once.
-		submissionPartitionLimit := loadPartitionsSectorsMax(info.WindowPoStPartitionSectors)
-		if uint64(len(params.Partitions)) > submissionPartitionLimit {
-			rt.Abortf(exitcode.ErrIllegalArgument, "too many partitions %d, limit %d", len(params.Partitions), submissionPartitionLimit)
-		}
-
 		currDeadline := st.DeadlineInfo(currEpoch)
 		// Check that the miner state indicates that the current proving deadline has started.
 		// This should only fail if the cron actor wasn't invoked, and matters only in case that it hasn't been
@@ -405,73 +420,30 @@ func (a Actor) SubmitWindowedPoSt(rt Runtime, params *SubmitWindowedPoStParams)
 			rt.Abortf(exitcode.ErrIllegalState, "proving period %d not yet open at %d", currDeadline.PeriodStart, currEpoch)
 		}
 
-		// The miner may only submit a proof for the current deadline.
-		if params.Deadline != currDeadline.Index {
-			rt.Abortf(exitcode.ErrIllegalArgument, "invalid deadline %d at epoch %d, expected %d",
-				params.Deadline, currEpoch, currDeadline.Index)
-		}
-
-		// Verify that the PoSt was committed to the chain at most WPoStChallengeLookback+WPoStChallengeWindow in the past.
-		if params.ChainCommitEpoch < currDeadline.Challenge {
-			rt.Abortf(exitcode.ErrIllegalArgument, "expected chain commit epoch %d to be after %d", params.ChainCommitEpoch, currDeadline.Challenge)
-		}
-		if params.ChainCommitEpoch >= currEpoch {
-			rt.Abortf(exitcode.ErrIllegalArgument, "chain commit epoch %d must be less than the current epoch %d", params.ChainCommitEpoch, currEpoch)
-		}
-		// Verify the chain commit randomness.
-		commRand := rt.GetRandomnessFromTickets(crypto.DomainSeparationTag_PoStChainCommit, params.ChainCommitEpoch, nil)
-		if !bytes.Equal(commRand, params.ChainCommitRand) {
-			rt.Abortf(exitcode.ErrIllegalArgument, "post commit randomness mismatched")
-		}
-
 		sectors, err := LoadSectors(store, st.Sectors)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load sectors")
 
 		deadlines, err := st.LoadDeadlines(adt.AsStore(rt))
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadlines")
 
-		deadline, err := deadlines.LoadDeadline(store, params.Deadline)
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadline %d", params.Deadline)
+		deadline, err := deadlines.LoadDeadline(store, currDeadline.Index)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadline %d", currDeadline.Index)
 
-		// Record proven sectors/partitions, returning updates to power and the final set of sectors
-		// proven/skipped.
-		//
-		// NOTE: This function does not actually check the proofs but does assume that they'll be
-		// successfully validated. The actual proof verification is done below in verifyWindowedPost.
-		//
-		// If proof verification fails, the this deadline MUST NOT be saved and this function should
-		// be aborted.
-		faultExpiration := currDeadline.Last() + FaultMaxAge
-		postResult, err = deadline.RecordProvenSectors(store, sectors, info.SectorSize, QuantSpecForDeadline(currDeadline), faultExpiration, params.Partitions)
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to process post submission for deadline %d", params.Deadline)
-
-		// Skipped sectors (including retracted recoveries) pay nothing at Window PoSt,
-		// but will incur the "ongoing" fault fee at deadline end.
-
-		// Validate proofs
-
-		// Load sector infos for proof, substituting a known-good sector for known-faulty sectors.
-		// Note: this is slightly sub-optimal, loading info for the recovering sectors again after they were already
-		// loaded above.
-		sectorInfos, err := sectors.LoadForProof(postResult.Sectors, postResult.IgnoredSectors)
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load proven sector info")
-
-		if len(sectorInfos) == 0 {
-			// Abort verification if all sectors are (now) faults. There's nothing to prove.
-			// It's not rational for a miner to submit a Window PoSt marking *all* non-faulty sectors as skipped,
-			// since that will just cause them to pay a penalty at deadline end that would otherwise be zero
-			// if they had *not* declared them.
-			rt.Abortf(exitcode.ErrIllegalArgument, "cannot prove partitions with no active sectors")
+		vis := make([]proof.WindowPoStVerifyInfo, len(posts))
+		for i, params := range posts {
+			var postResult *PoStResult
+			postResult, vis[i] = recordWindowedPoSt(rt, info, sectors, deadline, currDeadline, params)
+			powerDelta = powerDelta.Add(postResult.PowerDelta)
 		}
 
-		// Verify the proof.
+		// Verify the proofs.
 		// A failed verification doesn't immediately cause a penalty; the miner can try again.
 		//
 		// This function aborts on failure.
-		verifyWindowedPost(rt, currDeadline.Challenge, sectorInfos, params.Proofs)
+		verifyWindowedPoSts(rt, vis)
 
-		err = deadlines.UpdateDeadline(store, params.Deadline, deadline)
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to update deadline %d", params.Deadline)
+		err = deadlines.UpdateDeadline(store, currDeadline.Index, deadline)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to update deadline %d", currDeadline.Index)
 
 		err = st.SaveDeadlines(store, deadlines)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save deadlines")
@@ -481,13 +453,85 @@ func (a Actor) SubmitWindowedPoSt(rt Runtime, params *SubmitWindowedPoStParams)
 	// NOTE: It would be permissible to delay the power loss until the deadline closes, but that would require
 	// additional accounting state.
 	// https://github.com/filecoin-project/specs-actors/issues/414
-	requestUpdatePower(rt, postResult.PowerDelta)
+	requestUpdatePower(rt, powerDelta)
 
 	rt.StateReadonly(&st)
 	err := st.CheckBalanceInvariants(rt.CurrentBalance())
 	builtin.RequireNoErr(rt, err, ErrBalanceInvariantBroken, "balance invariants broken")
+}
 
-	return nil
+// Validates a Window PoSt submission for the current deadline and records the sectors it proves in the deadline,
+// returning the result and the information with which to verify its proof.
+//
+// NOTE: This function does not actually check the proof but does assume that it'll be successfully validated.
+// If proof verification fails, the deadline MUST NOT be saved and the method should be aborted.
+func recordWindowedPoSt(rt Runtime, info *MinerInfo, sectors Sectors, deadline *Deadline, currDeadline *dline.Info,
+	params *SubmitWindowedPoStParams) (*PoStResult, proof.WindowPoStVerifyInfo) {
+	currEpoch := rt.CurrEpoch()
+	store := adt.AsStore(rt)
+
+	// Verify that the miner has passed exactly one proof, of the window PoSt type for its sectors.
+	windowPoStProofType, err := info.SealProofType.RegisteredWindowPoStProof()
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to determine window PoSt type")
+	if len(params.Proofs) != 1 {
+		rt.Abortf(exitcode.ErrIllegalArgument, "expected exactly one proof, got %d", len(params.Proofs))
+	} else if params.Proofs[0].PoStProof != windowPoStProofType {
+		rt.Abortf(exitcode.ErrIllegalArgument, "expected proof of type %s, got proof of type %s", params.Proofs[0], windowPoStProofType)
+	}
+
+	// Validate that the miner didn't try to prove too many partitions at once.
+	submissionPartitionLimit := loadPartitionsSectorsMax(info.WindowPoStPartitionSectors)
+	if uint64(len(params.Partitions)) > submissionPartitionLimit {
+		rt.Abortf(exitcode.ErrIllegalArgument, "too many partitions %d, limit %d", len(params.Partitions), submissionPartitionLimit)
+	}
+	for _, partition := range params.Partitions {
+		err := ValidateBitField(partition.Skipped, addressedBitFieldLimits(info.WindowPoStPartitionSectors))
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "invalid skipped sectors for partition %d", partition.Index)
+	}
+
+	// The miner may only submit a proof for the current deadline.
+	if params.Deadline != currDeadline.Index {
+		rt.Abortf(exitcode.ErrIllegalArgument, "invalid deadline %d at epoch %d, expected %d",
+			params.Deadline, currEpoch, currDeadline.Index)
+	}
+
+	// Verify that the PoSt was committed to the chain at most WPoStChallengeLookback+WPoStChallengeWindow in the past.
+	if params.ChainCommitEpoch < currDeadline.Challenge {
+		rt.Abortf(exitcode.ErrIllegalArgument, "expected chain commit epoch %d to be after %d", params.ChainCommitEpoch, currDeadline.Challenge)
+	}
+	if params.ChainCommitEpoch >= currEpoch {
+		rt.Abortf(exitcode.ErrIllegalArgument, "chain commit epoch %d must be less than the current epoch %d", params.ChainCommitEpoch, currEpoch)
+	}
+	// Verify the chain commit randomness.
+	commRand := rt.GetRandomness(runtime.RandomnessTickets, crypto.DomainSeparationTag_PoStChainCommit, params.ChainCommitEpoch, nil)
+	if !bytes.Equal(commRand, params.ChainCommitRand) {
+		rt.Abortf(exitcode.ErrIllegalArgument, "post commit randomness mismatched")
+	}
+
+	// Record proven sectors/partitions, returning updates to power and the final set of sectors
+	// proven/skipped.
+	faultExpiration := currDeadline.Last() + FaultMaxAge
+	postResult, err := deadline.RecordProvenSectors(store, sectors, info.SectorSize, QuantSpecForDeadline(currDeadline), faultExpiration, params.Partitions)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to process post submission for deadline %d", params.Deadline)
+
+	// Skipped sectors (including retracted recoveries) pay nothing at Window PoSt,
+	// but will incur the "ongoing" fault fee at deadline end.
+
+	// Load sector infos for proof, substituting a known-good sector for known-faulty sectors.
+	// Note: this is slightly sub-optimal, loading info for the recovering sectors again after they were already
+	// loaded above.
+	sectorInfos, err := sectors.LoadForProof(postResult.Sectors, postResult.IgnoredSectors)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load proven sector info")
+
+	if len(sectorInfos) == 0 {
+		// Abort verification if all sectors are (now) faults. There's nothing to prove.
+		// It's not rational for a miner to submit a Window PoSt marking *all* non-faulty sectors as skipped,
+		// since that will just cause them to pay a penalty at deadline end that would otherwise be zero
+		// if they had *not* declared them.
+		rt.Abortf(exitcode.ErrIllegalArgument, "cannot prove partitions with no active sectors")
+	}
+
+	return postResult, windowedPoStVerifyInfo(rt, currDeadline.Challenge, sectorInfos, params.Proofs)
 }
 
 ///////////////////////
@@ -499,47 +543,67 @@ type PreCommitSectorParams = miner0.SectorPreCommitInfo
 // Proposals must be posted on chain via sma.PublishStorageDeals before PreCommitSector.
 // Optimization: PreCommitSector could contain a list of deals that are not published yet.
 func (a Actor) PreCommitSector(rt Runtime, params *PreCommitSectorParams) *abi.EmptyValue {
-	if _, ok := SupportedProofTypes[params.SealProof]; !ok {
-		rt.Abortf(exitcode.ErrIllegalArgument, "unsupported seal proof type: %s", params.SealProof)
-	}
-	if params.SectorNumber > abi.MaxSectorNumber {
-		rt.Abortf(exitcode.ErrIllegalArgument, "sector number %d out of range 0..(2^63-1)", params.SectorNumber)
-	}
-	if !params.SealedCID.Defined() {
-		rt.Abortf(exitcode.ErrIllegalArgument, "sealed CID undefined")
-	}
-	if params.SealedCID.Prefix() != SealedCIDPrefix {
-		rt.Abortf(exitcode.ErrIllegalArgument, "sealed CID had wrong prefix")
+	preCommitSectors(rt, []*PreCommitSectorParams{params})
+	return nil
+}
+
+type PreCommitSectorBatchParams struct {
+	Sectors []PreCommitSectorParams
+}
+
+// Pre-commits a batch of sectors in one message, as for PreCommitSector.
+// The reward and power actors are consulted once for the whole batch, and the batch's deposits are checked
+// against the available balance together. The batch succeeds or fails as a whole: a failure attributable to
+// particular sectors aborts with a builtin.SectorsFailure identifying them.
+func (a Actor) PreCommitSectorBatch(rt Runtime, params *PreCommitSectorBatchParams) *abi.EmptyValue {
+	if len(params.Sectors) == 0 {
+		rt.Abortf(exitcode.ErrIllegalArgument, "no sectors to pre-commit")
 	}
-	if params.SealRandEpoch >= rt.CurrEpoch() {
-		rt.Abortf(exitcode.ErrIllegalArgument, "seal challenge epoch %v must be before now %v", params.SealRandEpoch, rt.CurrEpoch())
+	if len(params.Sectors) > MaxPreCommitSectorBatchSize {
+		rt.Abortf(exitcode.ErrIllegalArgument, "too many sectors to pre-commit %d, max %d", len(params.Sectors), MaxPreCommitSectorBatchSize)
 	}
-
-	challengeEarliest := rt.CurrEpoch() - MaxPreCommitRandomnessLookback
-	if params.SealRandEpoch < challengeEarliest {
-		rt.Abortf(exitcode.ErrIllegalArgument, "seal challenge epoch %v too old, must be after %v", params.SealRandEpoch, challengeEarliest)
+	sectors := make([]*PreCommitSectorParams, len(params.Sectors))
+	for i := range params.Sectors {
+		sectors[i] = &params.Sectors[i]
 	}
+	preCommitSectors(rt, sectors)
+	return nil
+}
 
-	// Require sector lifetime meets minimum by assuming activation happens at last epoch permitted for seal proof.
-	// This could make sector maximum lifetime validation more lenient if the maximum sector limit isn't hit first.
-	maxActivation := rt.CurrEpoch() + MaxProveCommitDuration[params.SealProof]
-	validateExpiration(rt, maxActivation, params.Expiration, params.SealProof)
+// Validates and records pre-commitments for sectors, locking their deposits.
+func preCommitSectors(rt Runtime, sectors []*PreCommitSectorParams) {
+	currEpoch := rt.CurrEpoch()
 
-	if params.ReplaceCapacity && len(params.DealIDs) == 0 {
-		rt.Abortf(exitcode.ErrIllegalArgument, "cannot replace sector without committing deals")
-	}
-	if params.ReplaceSectorDeadline >= WPoStPeriodDeadlines {
-		rt.Abortf(exitcode.ErrIllegalArgument, "invalid deadline %d", params.ReplaceSectorDeadline)
+	// Check the parameters of every sector before any state is loaded, failing with all the invalid sectors.
+	var invalid []abi.SectorNumber
+	var invalidErr error
+	seen := make(map[abi.SectorNumber]bool, len(sectors))
+	for _, params := range sectors {
+		err := validatePreCommitParams(currEpoch, params)
+		if err == nil && seen[params.SectorNumber] {
+			err = exitcode.ErrIllegalArgument.Wrapf("duplicate sector %d in pre-commit batch", params.SectorNumber)
+		}
+		seen[params.SectorNumber] = true
+		if err != nil {
+			if invalidErr == nil {
+				invalidErr = err
+			}
+			invalid = append(invalid, params.SectorNumber)
+		}
 	}
-	if params.ReplaceSectorNumber > abi.MaxSectorNumber {
-		rt.Abortf(exitcode.ErrIllegalArgument, "invalid sector number %d", params.ReplaceSectorNumber)
+	if len(invalid) > 0 {
+		rt.AbortWithData(exitcode.Unwrap(invalidErr, exitcode.ErrIllegalArgument), builtin.NewSectorsFailure(invalid...),
+			"invalid pre-commit for sector %d: %s", invalid[0], invalidErr)
 	}
 
 	// gather information from other actors
 
 	rewardStats := requestCurrentEpochBlockReward(rt)
 	pwrTotal := requestCurrentTotalPower(rt)
-	dealWeight := requestDealWeight(rt, params.DealIDs, rt.CurrEpoch(), params.Expiration)
+	dealWeights := make([]market.VerifyDealsForActivationReturn, len(sectors))
+	for i, params := range sectors {
+		dealWeights[i] = requestDealWeight(rt, params.DealIDs, currEpoch, params.Expiration)
+	}
 
 	store := adt.AsStore(rt)
 	var st State
@@ -547,7 +611,7 @@ func (a Actor) PreCommitSector(rt Runtime, params *PreCommitSectorParams) *abi.E
 	newlyVested := big.Zero()
 	feeToBurn := abi.NewTokenAmount(0)
 	rt.StateTransaction(&st, func() {
-		newlyVested, err = st.UnlockVestedFunds(store, rt.CurrEpoch())
+		newlyVested, err = st.UnlockVestedFunds(store, currEpoch)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to vest funds")
 		// available balance already accounts for fee debt so it is correct to call
 		// this before RepayDebts. We would have to
@@ -559,76 +623,70 @@ func (a Actor) PreCommitSector(rt Runtime, params *PreCommitSectorParams) *abi.E
 		info := getMinerInfo(rt, &st)
 		rt.ValidateImmediateCallerIs(append(info.ControlAddresses, info.Owner, info.Worker)...)
 
-		if ConsensusFaultActive(info, rt.CurrEpoch()) {
+		if ConsensusFaultActive(info, currEpoch) {
 			rt.Abortf(exitcode.ErrForbidden, "precommit not allowed during active consensus fault")
 		}
 
-		if params.SealProof != info.SealProofType {
-			rt.Abortf(exitcode.ErrIllegalArgument, "sector seal proof %v must match miner seal proof type %d", params.SealProof, info.SealProofType)
-		}
-
-		dealCountMax := SectorDealsMax(info.SectorSize)
-		if uint64(len(params.DealIDs)) > dealCountMax {
-			rt.Abortf(exitcode.ErrIllegalArgument, "too many deals for sector %d > %d", len(params.DealIDs), dealCountMax)
-		}
-
-		// Ensure total deal space does not exceed sector size.
-		if dealWeight.DealSpace > uint64(info.SectorSize) {
-			rt.Abortf(exitcode.ErrIllegalArgument, "deals too large to fit in sector %d > %d", dealWeight.DealSpace, info.SectorSize)
-		}
-
-		err = st.AllocateSectorNumber(store, params.SectorNumber)
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to allocate sector id %d", params.SectorNumber)
+		totalDepositReq := big.Zero()
+		for i, params := range sectors {
+			err := checkPreCommitAgainstInfo(info, params, dealWeights[i])
+			requireSectorNoErr(rt, params.SectorNumber, err, exitcode.ErrIllegalArgument, "invalid pre-commit for sector %d", params.SectorNumber)
+
+			err = st.AllocateSectorNumber(store, params.SectorNumber)
+			requireSectorNoErr(rt, params.SectorNumber, err, exitcode.ErrIllegalState, "failed to allocate sector id %d", params.SectorNumber)
+
+			// The following two checks shouldn't be necessary, but it can't
+			// hurt to double-check (unless it's really just too
+			// expensive?).
+			_, preCommitFound, err := st.GetPrecommittedSector(store, params.SectorNumber)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to check pre-commit %v", params.SectorNumber)
+			if preCommitFound {
+				rt.AbortWithData(exitcode.ErrIllegalState, builtin.NewSectorsFailure(params.SectorNumber), "sector %v already pre-committed", params.SectorNumber)
+			}
 
-		// The following two checks shouldn't be necessary, but it can't
-		// hurt to double-check (unless it's really just too
-		// expensive?).
-		_, preCommitFound, err := st.GetPrecommittedSector(store, params.SectorNumber)
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to check pre-commit %v", params.SectorNumber)
-		if preCommitFound {
-			rt.Abortf(exitcode.ErrIllegalState, "sector %v already pre-committed", params.SectorNumber)
-		}
+			sectorFound, err := st.HasSectorNo(store, params.SectorNumber)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to check sector %v", params.SectorNumber)
+			if sectorFound {
+				rt.AbortWithData(exitcode.ErrIllegalState, builtin.NewSectorsFailure(params.SectorNumber), "sector %v already committed", params.SectorNumber)
+			}
 
-		sectorFound, err := st.HasSectorNo(store, params.SectorNumber)
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to check sector %v", params.SectorNumber)
-		if sectorFound {
-			rt.Abortf(exitcode.ErrIllegalState, "sector %v already committed", params.SectorNumber)
-		}
+			if params.ReplaceCapacity {
+				err := checkReplaceSector(&st, store, params)
+				requireSectorNoErr(rt, params.SectorNumber, err, exitcode.ErrIllegalState, "invalid replacement by sector %d", params.SectorNumber)
+			}
 
-		if params.ReplaceCapacity {
-			validateReplaceSector(rt, &st, store, params)
-		}
+			duration := params.Expiration - currEpoch
+			sectorWeight := QAPowerForWeight(info.SectorSize, duration, dealWeights[i].DealWeight, dealWeights[i].VerifiedDealWeight)
+			depositReq := PreCommitDepositForPower(rewardStats.ThisEpochRewardSmoothed, pwrTotal.QualityAdjPowerSmoothed, sectorWeight)
+			totalDepositReq = big.Add(totalDepositReq, depositReq)
+
+			if err := st.PutPrecommittedSector(store, &SectorPreCommitOnChainInfo{
+				Info:               SectorPreCommitInfo(*params),
+				PreCommitDeposit:   depositReq,
+				PreCommitEpoch:     currEpoch,
+				DealWeight:         dealWeights[i].DealWeight,
+				VerifiedDealWeight: dealWeights[i].VerifiedDealWeight,
+			}); err != nil {
+				rt.Abortf(exitcode.ErrIllegalState, "failed to write pre-committed sector %v: %v", params.SectorNumber, err)
+			}
+			// add precommit expiry to the queue
+			msd, ok := MaxProveCommitDuration[params.SealProof]
+			if !ok {
+				rt.Abortf(exitcode.ErrIllegalArgument, "no max seal duration set for proof type: %d", params.SealProof)
+			}
+			// The +1 here is critical for the batch verification of proofs. Without it, if a proof arrived exactly on the
+			// due epoch, ProveCommitSector would accept it, then the expiry event would remove it, and then
+			// ConfirmSectorProofsValid would fail to find it.
+			expiryBound := currEpoch + msd + 1
 
-		duration := params.Expiration - rt.CurrEpoch()
-		sectorWeight := QAPowerForWeight(info.SectorSize, duration, dealWeight.DealWeight, dealWeight.VerifiedDealWeight)
-		depositReq := PreCommitDepositForPower(rewardStats.ThisEpochRewardSmoothed, pwrTotal.QualityAdjPowerSmoothed, sectorWeight)
-		if availableBalance.LessThan(depositReq) {
-			rt.Abortf(exitcode.ErrInsufficientFunds, "insufficient funds for pre-commit deposit: %v", depositReq)
+			err = st.AddPreCommitExpiry(store, expiryBound, params.SectorNumber)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to add pre-commit expiry to queue")
 		}
 
-		st.AddPreCommitDeposit(depositReq)
-
-		if err := st.PutPrecommittedSector(store, &SectorPreCommitOnChainInfo{
-			Info:               SectorPreCommitInfo(*params),
-			PreCommitDeposit:   depositReq,
-			PreCommitEpoch:     rt.CurrEpoch(),
-			DealWeight:         dealWeight.DealWeight,
-			VerifiedDealWeight: dealWeight.VerifiedDealWeight,
-		}); err != nil {
-			rt.Abortf(exitcode.ErrIllegalState, "failed to write pre-committed sector %v: %v", params.SectorNumber, err)
-		}
-		// add precommit expiry to the queue
-		msd, ok := MaxProveCommitDuration[params.SealProof]
-		if !ok {
-			rt.Abortf(exitcode.ErrIllegalArgument, "no max seal duration set for proof type: %d", params.SealProof)
+		if availableBalance.LessThan(totalDepositReq) {
+			rt.Abortf(exitcode.ErrInsufficientFunds, "insufficient funds for pre-commit deposit: %v", totalDepositReq)
 		}
-		// The +1 here is critical for the batch verification of proofs. Without it, if a proof arrived exactly on the
-		// due epoch, ProveCommitSector would accept it, then the expiry event would remove it, and then
-		// ConfirmSectorProofsValid would fail to find it.
-		expiryBound := rt.CurrEpoch() + msd + 1
-
-		err = st.AddPreCommitExpiry(store, expiryBound, params.SectorNumber)
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to add pre-commit expiry to queue")
+		st.AddPreCommitDeposit(totalDepositReq)
 	})
 
 	burnFunds(rt, feeToBurn)
@@ -637,10 +695,76 @@ func (a Actor) PreCommitSector(rt Runtime, params *PreCommitSectorParams) *abi.E
 	builtin.RequireNoErr(rt, err, ErrBalanceInvariantBroken, "balance invariants broken")
 
 	notifyPledgeChanged(rt, newlyVested.Neg())
+}
 
+// Checks the parameters of a pre-commitment which are independent of the miner's state.
+func validatePreCommitParams(currEpoch abi.ChainEpoch, params *PreCommitSectorParams) error {
+	if _, ok := SupportedProofTypes[params.SealProof]; !ok {
+		return exitcode.ErrIllegalArgument.Wrapf("unsupported seal proof type: %s", params.SealProof)
+	}
+	if params.SectorNumber > abi.MaxSectorNumber {
+		return exitcode.ErrIllegalArgument.Wrapf("sector number %d out of range 0..(2^63-1)", params.SectorNumber)
+	}
+	if !params.SealedCID.Defined() {
+		return exitcode.ErrIllegalArgument.Wrapf("sealed CID undefined")
+	}
+	if params.SealedCID.Prefix() != SealedCIDPrefix {
+		return exitcode.ErrIllegalArgument.Wrapf("sealed CID had wrong prefix")
+	}
+	if params.SealRandEpoch >= currEpoch {
+		return exitcode.ErrIllegalArgument.Wrapf("seal challenge epoch %v must be before now %v", params.SealRandEpoch, currEpoch)
+	}
+
+	challengeEarliest := currEpoch - MaxPreCommitRandomnessLookback
+	if params.SealRandEpoch < challengeEarliest {
+		return exitcode.ErrIllegalArgument.Wrapf("seal challenge epoch %v too old, must be after %v", params.SealRandEpoch, challengeEarliest)
+	}
+
+	// Require sector lifetime meets minimum by assuming activation happens at last epoch permitted for seal proof.
+	// This could make sector maximum lifetime validation more lenient if the maximum sector limit isn't hit first.
+	maxActivation := currEpoch + MaxProveCommitDuration[params.SealProof]
+	if err := checkExpiration(currEpoch, maxActivation, params.Expiration, params.SealProof); err != nil {
+		return err
+	}
+
+	if params.ReplaceCapacity && len(params.DealIDs) == 0 {
+		return exitcode.ErrIllegalArgument.Wrapf("cannot replace sector without committing deals")
+	}
+	if params.ReplaceSectorDeadline >= WPoStPeriodDeadlines {
+		return exitcode.ErrIllegalArgument.Wrapf("invalid deadline %d", params.ReplaceSectorDeadline)
+	}
+	if params.ReplaceSectorNumber > abi.MaxSectorNumber {
+		return exitcode.ErrIllegalArgument.Wrapf("invalid sector number %d", params.ReplaceSectorNumber)
+	}
+	return nil
+}
+
+// Checks a pre-commitment against the miner's info and the weight of its deals.
+func checkPreCommitAgainstInfo(info *MinerInfo, params *PreCommitSectorParams, dealWeight market.VerifyDealsForActivationReturn) error {
+	if params.SealProof != info.SealProofType {
+		return exitcode.ErrIllegalArgument.Wrapf("sector seal proof %v must match miner seal proof type %d", params.SealProof, info.SealProofType)
+	}
+
+	dealCountMax := SectorDealsMax(info.SectorSize)
+	if uint64(len(params.DealIDs)) > dealCountMax {
+		return exitcode.ErrIllegalArgument.Wrapf("too many deals for sector %d > %d", len(params.DealIDs), dealCountMax)
+	}
+
+	// Ensure total deal space does not exceed sector size.
+	if dealWeight.DealSpace > uint64(info.SectorSize) {
+		return exitcode.ErrIllegalArgument.Wrapf("deals too large to fit in sector %d > %d", dealWeight.DealSpace, info.SectorSize)
+	}
 	return nil
 }
 
+// Aborts as for builtin.RequireNoErr if err is not nil, with data identifying the sector at fault.
+func requireSectorNoErr(rt Runtime, sectorNo abi.SectorNumber, err error, defaultExitCode exitcode.ExitCode, msg string, args ...interface{}) {
+	if err != nil {
+		code := exitcode.Unwrap(err, defaultExitCode)
+		rt.AbortWithData(code, builtin.NewSectorsFailure(sectorNo), msg+": %s", append(args, err)...)
+	}
+}
+
 //type ProveCommitSectorParams struct {
 //	SectorNumber abi.SectorNumber
 //	Proof        []byte
@@ -652,6 +776,76 @@ type ProveCommitSectorParams = miner0.ProveCommitSectorParams
 // If valid, the power actor will call ConfirmSectorProofsValid at the end of the same epoch as this message.
 func (a Actor) ProveCommitSector(rt Runtime, params *ProveCommitSectorParams) *abi.EmptyValue {
 	rt.ValidateImmediateCallerAcceptAny()
+	svi := getProveCommitVerifyInfo(rt, params)
+	power.NewClient(rt).SubmitPoRepForBulkVerify(svi)
+	return nil
+}
+
+type ProveCommitSectorsParams struct {
+	Sectors []ProveCommitSectorParams
+}
+
+type ProveCommitSectorsReturn struct {
+	// The sectors activated. The others in the batch had invalid proofs or deals that failed to activate.
+	Activated bitfield.BitField
+}
+
+// Checks state of the corresponding sector pre-commitments, then verifies the proofs immediately as a single batch,
+// rather than deferring verification to the power actor, and activates the sectors with valid proofs.
+// A batch with some invalid proofs or deals activates the remaining sectors, returning which were activated, but
+// fails if none can be.
+func (a Actor) ProveCommitSectors(rt Runtime, params *ProveCommitSectorsParams) *ProveCommitSectorsReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	if len(params.Sectors) == 0 {
+		rt.Abortf(exitcode.ErrIllegalArgument, "no sectors to prove-commit")
+	}
+	if len(params.Sectors) > MaxProveCommitSectorsBatchSize {
+		rt.Abortf(exitcode.ErrIllegalArgument, "too many sectors to prove-commit %d, max %d", len(params.Sectors), MaxProveCommitSectorsBatchSize)
+	}
+
+	seen := make(map[abi.SectorNumber]bool, len(params.Sectors))
+	svis := make([]proof.SealVerifyInfo, 0, len(params.Sectors))
+	for i := range params.Sectors {
+		sectorNo := params.Sectors[i].SectorNumber
+		if seen[sectorNo] {
+			rt.AbortWithData(exitcode.ErrIllegalArgument, builtin.NewSectorsFailure(sectorNo), "duplicate sector %d in prove-commit batch", sectorNo)
+		}
+		seen[sectorNo] = true
+		svis = append(svis, *getProveCommitVerifyInfo(rt, &params.Sectors[i]))
+	}
+
+	res, err := rt.BatchVerifySeals(map[addr.Address][]proof.SealVerifyInfo{rt.Receiver(): svis})
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to batch verify seals")
+	verified := res[rt.Receiver()]
+	if len(verified) != len(svis) {
+		rt.Abortf(exitcode.ErrIllegalState, "batch verify seals returned %d results for %d proofs", len(verified), len(svis))
+	}
+
+	var validSectors, invalidSectors []abi.SectorNumber
+	for i, ok := range verified {
+		if ok {
+			validSectors = append(validSectors, svis[i].SectorID.Number)
+		} else {
+			invalidSectors = append(invalidSectors, svis[i].SectorID.Number)
+			rt.Log(rtt.INFO, "invalid seal proof for sector %d, dropping from prove commit set", svis[i].SectorID.Number)
+		}
+	}
+	if len(validSectors) == 0 {
+		rt.AbortWithData(exitcode.ErrIllegalArgument, builtin.NewSectorsFailure(invalidSectors...), "all prove commits failed to verify")
+	}
+
+	activated := activateProvenSectors(rt, validSectors)
+	activatedNos := make([]uint64, len(activated))
+	for i, sectorNo := range activated {
+		activatedNos[i] = uint64(sectorNo)
+	}
+	return &ProveCommitSectorsReturn{Activated: bitfield.NewFromSet(activatedNos)}
+}
+
+// Checks state of the pre-commitment corresponding to a prove-commit, and gathers the information with which
+// to verify its proof.
+func getProveCommitVerifyInfo(rt Runtime, params *ProveCommitSectorParams) *proof.SealVerifyInfo {
 	nv := rt.NetworkVersion()
 
 	if params.SectorNumber > abi.MaxSectorNumber {
@@ -690,7 +884,7 @@ func (a Actor) ProveCommitSector(rt Runtime, params *ProveCommitSectorParams) *a
 		rt.Abortf(exitcode.ErrIllegalArgument, "commitment proof for %d too late at %d, due %d", sectorNo, rt.CurrEpoch(), proveCommitDue)
 	}
 
-	svi := getVerifyInfo(rt, &SealVerifyStuff{
+	return getVerifyInfo(rt, &SealVerifyStuff{
 		SealedCID:           precommit.Info.SealedCID,
 		InteractiveEpoch:    precommit.PreCommitEpoch + PreCommitChallengeDelay,
 		SealRandEpoch:       precommit.Info.SealRandEpoch,
@@ -699,16 +893,6 @@ func (a Actor) ProveCommitSector(rt Runtime, params *ProveCommitSectorParams) *a
 		SectorNumber:        precommit.Info.SectorNumber,
 		RegisteredSealProof: precommit.Info.SealProof,
 	})
-
-	code := rt.Send(
-		builtin.StoragePowerActorAddr,
-		builtin.MethodsPower.SubmitPoRepForBulkVerify,
-		svi,
-		abi.NewTokenAmount(0),
-		&builtin.Discard{},
-	)
-	builtin.RequireSuccess(rt, code, "failed to submit proof for bulk verification")
-	return nil
 }
 
 func (a Actor) ConfirmSectorProofsValid(rt Runtime, params *builtin.ConfirmSectorProofsParams) *abi.EmptyValue {
@@ -722,6 +906,13 @@ func (a Actor) ConfirmSectorProofsValid(rt Runtime, params *builtin.ConfirmSecto
 		)
 	}
 
+	activateProvenSectors(rt, params.Sectors)
+	return nil
+}
+
+// Activates the pre-committed sectors with proven seals, returning the numbers of those activated.
+// Sectors whose deals fail to activate are skipped, but aborts if none can be activated.
+func activateProvenSectors(rt Runtime, sectors []abi.SectorNumber) []abi.SectorNumber {
 	// get network stats from other actors
 	rewardStats := requestCurrentEpochBlockReward(rt)
 	pwrTotal := requestCurrentTotalPower(rt)
@@ -749,7 +940,7 @@ func (a Actor) ConfirmSectorProofsValid(rt Runtime, params *builtin.ConfirmSecto
 	//
 
 	// This skips missing pre-commits.
-	precommittedSectors, err := st.FindPrecommittedSectors(store, params.Sectors...)
+	precommittedSectors, err := st.FindPrecommittedSectors(store, sectors...)
 	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load pre-committed sectors")
 
 	// Committed-capacity sectors licensed for early removal by new sectors being proven.
@@ -761,7 +952,8 @@ func (a Actor) ConfirmSectorProofsValid(rt Runtime, params *builtin.ConfirmSecto
 			// Check (and activate) storage deals associated to sector. Abort if checks failed.
 			// TODO: we should batch these calls...
 			// https://github.com/filecoin-project/specs-actors/issues/474
-			code := rt.Send(
+			var failed builtin.DealsFailure
+			code, hasFailed := builtin.SendWithFailureData(rt,
 				builtin.StorageMarketActorAddr,
 				builtin.MethodsMarket.ActivateDeals,
 				&market.ActivateDealsParams{
@@ -769,11 +961,16 @@ func (a Actor) ConfirmSectorProofsValid(rt Runtime, params *builtin.ConfirmSecto
 					SectorExpiry: precommit.Info.Expiration,
 				},
 				abi.NewTokenAmount(0),
-				&builtin.Discard{},
+				nil,
+				&failed,
 			)
 
 			if code != exitcode.Ok {
-				rt.Log(rtt.INFO, "failed to activate deals on sector %d, dropping from prove commit set", precommit.Info.SectorNumber)
+				if hasFailed {
+					rt.Log(rtt.INFO, "failed to activate deals %v on sector %d, dropping from prove commit set", failed.DealIDs, precommit.Info.SectorNumber)
+				} else {
+					rt.Log(rtt.INFO, "failed to activate deals on sector %d, dropping from prove commit set", precommit.Info.SectorNumber)
+				}
 				continue
 			}
 		}
@@ -799,6 +996,7 @@ func (a Actor) ConfirmSectorProofsValid(rt Runtime, params *builtin.ConfirmSecto
 	totalPledge := big.Zero()
 	depositToUnlock := big.Zero()
 	newSectors := make([]*SectorOnChainInfo, 0)
+	newSectorNos := make([]abi.SectorNumber, 0, len(preCommits))
 	newlyVested := big.Zero()
 	rt.StateTransaction(&st, func() {
 		// Schedule expiration for replaced sectors to the end of their next deadline window.
@@ -807,7 +1005,6 @@ func (a Actor) ConfirmSectorProofsValid(rt Runtime, params *builtin.ConfirmSecto
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to replace sector expirations")
 		replacedBySectorNumber := asMapBySectorNumber(replaced)
 
-		newSectorNos := make([]abi.SectorNumber, 0, len(preCommits))
 		for _, precommit := range preCommits {
 			// compute initial pledge
 			activation := rt.CurrEpoch()
@@ -884,11 +1081,14 @@ func (a Actor) ConfirmSectorProofsValid(rt Runtime, params *builtin.ConfirmSecto
 		builtin.RequireNoErr(rt, err, ErrBalanceInvariantBroken, "balance invariants broken")
 	})
 
+	for _, sector := range newSectors {
+		builtin.EmitSectorActivated(rt, sector.SectorNumber, sector.DealIDs)
+	}
+
 	// Request power and pledge update for activated sector.
 	requestUpdatePower(rt, newPower)
 	notifyPledgeChanged(rt, big.Sub(totalPledge, newlyVested))
-
-	return nil
+	return newSectorNos
 }
 
 //type CheckSectorProvenParams struct {
@@ -933,9 +1133,11 @@ type ExtendSectorExpirationParams = miner0.ExtendSectorExpirationParams
 //}
 type ExpirationExtension = miner0.ExpirationExtension
 
-// Changes the expiration epoch for a sector to a new, later one.
-// The sector must not be terminated or faulty.
-// The sector's power is recomputed for the new expiration.
+// Changes the expiration epoch for sectors to new, later ones.
+// Sectors are declared in groups by deadline and partition, each with a new expiration. The sectors must not be
+// terminated or faulty.
+// Each partition's expiration queue is updated in bulk, its power recomputed once for the new expirations, and the
+// partition rescheduled in its deadline's expiration queue at the new epochs.
 func (a Actor) ExtendSectorExpiration(rt Runtime, params *ExtendSectorExpirationParams) *abi.EmptyValue {
 	if uint64(len(params.Extensions)) > DeclarationsMax {
 		rt.Abortf(exitcode.ErrIllegalArgument, "too many declarations %d, max %d", len(params.Extensions), DeclarationsMax)
@@ -948,21 +1150,20 @@ func (a Actor) ExtendSectorExpiration(rt Runtime, params *ExtendSectorExpiration
 		if decl.Deadline >= WPoStPeriodDeadlines {
 			rt.Abortf(exitcode.ErrIllegalArgument, "deadline %d not in range 0..%d", decl.Deadline, WPoStPeriodDeadlines)
 		}
-		count, err := decl.Sectors.Count()
+		err := ValidateBitField(decl.Sectors, addressedBitFieldLimits(AddressedSectorsMax))
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument,
+			"invalid sectors for deadline %d, partition %d", decl.Deadline, decl.Partition,
+		)
+		// Count only as far as exceeding the limit, rather than expanding an arbitrarily large bitfield.
+		count, err := BitFieldCountUpTo(decl.Sectors, AddressedSectorsMax-sectorCount+1)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument,
 			"failed to count sectors for deadline %d, partition %d",
 			decl.Deadline, decl.Partition,
 		)
-		if sectorCount > math.MaxUint64-count {
-			rt.Abortf(exitcode.ErrIllegalArgument, "sector bitfield integer overflow")
-		}
 		sectorCount += count
-	}
-	if sectorCount > AddressedSectorsMax {
-		rt.Abortf(exitcode.ErrIllegalArgument,
-			"too many sectors for declaration %d, max %d",
-			sectorCount, AddressedSectorsMax,
-		)
+		if sectorCount > AddressedSectorsMax {
+			rt.Abortf(exitcode.ErrIllegalArgument, "too many sectors for declaration, max %d", AddressedSectorsMax)
+		}
 	}
 
 	currEpoch := rt.CurrEpoch()
@@ -1005,6 +1206,10 @@ func (a Actor) ExtendSectorExpiration(rt Runtime, params *ExtendSectorExpiration
 
 			quant := st.QuantSpecForDeadline(dlIdx)
 
+			// Group partitions by their new expiration, and remember iteration order.
+			partitionsByNewEpoch := map[abi.ChainEpoch][]uint64{}
+			var epochsToReschedule []abi.ChainEpoch
+
 			for _, decl := range declsByDeadline[dlIdx] {
 				var partition Partition
 				found, err := partitions.Get(decl.Partition, &partition)
@@ -1051,11 +1256,23 @@ func (a Actor) ExtendSectorExpiration(rt Runtime, params *ExtendSectorExpiration
 
 				err = partitions.Set(decl.Partition, &partition)
 				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save deadline %v partition %v", dlIdx, decl.Partition)
+
+				if _, ok := partitionsByNewEpoch[decl.NewExpiration]; !ok {
+					epochsToReschedule = append(epochsToReschedule, decl.NewExpiration)
+				}
+				partitionsByNewEpoch[decl.NewExpiration] = append(partitionsByNewEpoch[decl.NewExpiration], decl.Partition)
 			}
 
 			deadline.Partitions, err = partitions.Root()
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save partitions for deadline %d", dlIdx)
 
+			// Record the partitions at their new expirations in the deadline's expiration queue. Entries at their
+			// previous expirations are left in place, and are ignored when popped if no sectors are found there.
+			for _, epoch := range epochsToReschedule {
+				err = deadline.AddExpirationPartitions(store, epoch, partitionsByNewEpoch[epoch], quant)
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to reschedule partition expirations at deadline %d", dlIdx)
+			}
+
 			err = deadlines.UpdateDeadline(store, dlIdx, deadline)
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save deadline %d", dlIdx)
 		}
@@ -1098,14 +1315,13 @@ type TerminateSectorsReturn = miner0.TerminateSectorsReturn
 
 // Marks some sectors as terminated at the present epoch, earlier than their
 // scheduled termination, and adds these sectors to the early termination queue.
-// This method then processes up to AddressedSectorsMax sectors and
-// AddressedPartitionsMax partitions from the early termination queue,
-// terminating deals, paying fines, and returning pledge collateral. While
-// sectors remain in this queue:
+// This method then processes a batch of sectors from the early termination
+// queue, of the size suggested by the runtime, terminating deals, paying
+// fines, and returning pledge collateral. While sectors remain in this queue:
 //
 //  1. The miner will be unable to withdraw funds.
-//  2. The chain will process up to AddressedSectorsMax sectors and
-//     AddressedPartitionsMax per epoch until the queue is empty.
+//  2. The chain will process a batch of sectors per epoch until the queue
+//     is empty.
 //
 // The sectors are immediately ignored for Window PoSt proofs, and should be
 // masked in the same way as faulty sectors. A miner terminating sectors in the
@@ -1127,7 +1343,11 @@ func (a Actor) TerminateSectors(rt Runtime, params *TerminateSectorsParams) *Ter
 
 	toProcess := make(DeadlineSectorMap)
 	for _, term := range params.Terminations {
-		err := toProcess.Add(term.Deadline, term.Partition, term.Sectors)
+		err := ValidateBitField(term.Sectors, addressedBitFieldLimits(AddressedSectorsMax))
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument,
+			"invalid sectors for deadline %d, partition %d", term.Deadline, term.Partition,
+		)
+		err = toProcess.Add(term.Deadline, term.Partition, term.Sectors)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument,
 			"failed to process deadline %d, partition %d", term.Deadline, term.Partition,
 		)
@@ -1223,7 +1443,11 @@ func (a Actor) DeclareFaults(rt Runtime, params *DeclareFaultsParams) *abi.Empty
 
 	toProcess := make(DeadlineSectorMap)
 	for _, term := range params.Faults {
-		err := toProcess.Add(term.Deadline, term.Partition, term.Sectors)
+		err := ValidateBitField(term.Sectors, addressedBitFieldLimits(AddressedSectorsMax))
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument,
+			"invalid sectors for deadline %d, partition %d", term.Deadline, term.Partition,
+		)
+		err = toProcess.Add(term.Deadline, term.Partition, term.Sectors)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument,
 			"failed to process deadline %d, partition %d", term.Deadline, term.Partition,
 		)
@@ -1234,6 +1458,7 @@ func (a Actor) DeclareFaults(rt Runtime, params *DeclareFaultsParams) *abi.Empty
 	store := adt.AsStore(rt)
 	var st State
 	powerDelta := NewPowerPairZero()
+	var newFaults []bitfield.BitField
 	rt.StateTransaction(&st, func() {
 		info := getMinerInfo(rt, &st)
 		rt.ValidateImmediateCallerIs(append(info.ControlAddresses, info.Owner, info.Worker)...)
@@ -1255,8 +1480,9 @@ func (a Actor) DeclareFaults(rt Runtime, params *DeclareFaultsParams) *abi.Empty
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadline %d", dlIdx)
 
 			faultExpirationEpoch := targetDeadline.Last() + FaultMaxAge
-			deadlinePowerDelta, err := deadline.DeclareFaults(store, sectors, info.SectorSize, QuantSpecForDeadline(targetDeadline), faultExpirationEpoch, pm)
+			deadlineNewFaults, deadlinePowerDelta, err := deadline.DeclareFaults(store, sectors, info.SectorSize, QuantSpecForDeadline(targetDeadline), faultExpirationEpoch, pm)
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to declare faults for deadline %d", dlIdx)
+			newFaults = append(newFaults, deadlineNewFaults)
 
 			err = deadlines.UpdateDeadline(store, dlIdx, deadline)
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to store deadline %d partitions", dlIdx)
@@ -1276,6 +1502,14 @@ func (a Actor) DeclareFaults(rt Runtime, params *DeclareFaultsParams) *abi.Empty
 	// https://github.com/filecoin-project/specs-actors/issues/414
 	requestUpdatePower(rt, powerDelta)
 
+	allNewFaults, err := bitfield.MultiMerge(newFaults...)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to merge new faults")
+	empty, err := allNewFaults.IsEmpty()
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to count new faults")
+	if !empty {
+		builtin.EmitSectorsFaulted(rt, allNewFaults)
+	}
+
 	// Payment of penalty for declared faults is deferred to the deadline cron.
 	return nil
 }
@@ -1305,7 +1539,11 @@ func (a Actor) DeclareFaultsRecovered(rt Runtime, params *DeclareFaultsRecovered
 
 	toProcess := make(DeadlineSectorMap)
 	for _, term := range params.Recoveries {
-		err := toProcess.Add(term.Deadline, term.Partition, term.Sectors)
+		err := ValidateBitField(term.Sectors, addressedBitFieldLimits(AddressedSectorsMax))
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument,
+			"invalid sectors for deadline %d, partition %d", term.Deadline, term.Partition,
+		)
+		err = toProcess.Add(term.Deadline, term.Partition, term.Sectors)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument,
 			"failed to process deadline %d, partition %d", term.Deadline, term.Partition,
 		)
@@ -1385,6 +1623,8 @@ func (a Actor) CompactPartitions(rt Runtime, params *CompactPartitionsParams) *a
 		rt.Abortf(exitcode.ErrIllegalArgument, "invalid deadline %v", params.Deadline)
 	}
 
+	err := ValidateBitField(params.Partitions, addressedBitFieldLimits(AddressedPartitionsMax))
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "invalid partitions bitfield")
 	partitionCount, err := params.Partitions.Count()
 	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed to parse partitions bitfield")
 
@@ -1451,6 +1691,12 @@ type CompactSectorNumbersParams = miner0.CompactSectorNumbersParams
 // For example, if sectors 1-99 and 101-200 have been allocated, sector number
 // 99 can be masked out to collapse these two ranges into one.
 func (a Actor) CompactSectorNumbers(rt Runtime, params *CompactSectorNumbersParams) *abi.EmptyValue {
+	// The mask may cover large ranges of sector numbers, so its runs rather than the bits it sets are limited.
+	err := ValidateBitField(params.MaskSectorNumbers, BitFieldLimits{
+		MaxEncodedSize: bitfield.MaxEncodedSize,
+		MaxRuns:        2*AddressedSectorsMax + 1,
+	})
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "invalid mask bitfield")
 	lastSectorNo, err := params.MaskSectorNumbers.Last()
 	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "invalid mask bitfield")
 	if lastSectorNo > abi.MaxSectorNumber {
@@ -1603,10 +1849,12 @@ func (a Actor) ReportConsensusFault(rt Runtime, params *ReportConsensusFaultPara
 	return nil
 }
 
-//type WithdrawBalanceParams struct {
-//	AmountRequested abi.TokenAmount
-//}
-type WithdrawBalanceParams = miner0.WithdrawBalanceParams
+type WithdrawBalanceParams struct {
+	AmountRequested abi.TokenAmount
+	// Address to which the funds are sent, or nil to send them to the owner.
+	// Optional, so that parameters encoded without it remain valid.
+	Recipient *addr.Address `cborgen:"optional"`
+}
 
 func (a Actor) WithdrawBalance(rt Runtime, params *WithdrawBalanceParams) *abi.EmptyValue {
 	var st State
@@ -1654,8 +1902,12 @@ func (a Actor) WithdrawBalance(rt Runtime, params *WithdrawBalanceParams) *abi.E
 	Assert(amountWithdrawn.GreaterThanEqual(big.Zero()))
 	Assert(amountWithdrawn.LessThanEqual(availableBalance))
 
+	recipient := info.Owner
+	if params.Recipient != nil {
+		recipient = *params.Recipient
+	}
 	if amountWithdrawn.GreaterThan(abi.NewTokenAmount(0)) {
-		code := rt.Send(info.Owner, builtin.MethodSend, nil, amountWithdrawn, &builtin.Discard{})
+		code := rt.Send(recipient, builtin.MethodSend, nil, amountWithdrawn, &builtin.Discard{})
 		builtin.RequireSuccess(rt, code, "failed to withdraw balance")
 	}
 
@@ -1729,6 +1981,14 @@ func (a Actor) OnDeferredCronEvent(rt Runtime, payload *CronEventPayload) *abi.E
 func processEarlyTerminations(rt Runtime) (more bool) {
 	store := adt.AsStore(rt)
 
+	// Defer the batch rather than risk running out of gas part way through it.
+	limit := rt.SuggestedBatchLimit(runtime.BatchEarlyTerminations)
+	if limit.IsZero() {
+		var st State
+		rt.StateReadonly(&st)
+		return havePendingEarlyTerminations(rt, &st)
+	}
+
 	// TODO: We're using the current power+epoch reward. Technically, we
 	// should use the power/reward at the time of termination.
 	// https://github.com/filecoin-project/specs-actors/v2/pull/648
@@ -1745,7 +2005,7 @@ func processEarlyTerminations(rt Runtime) (more bool) {
 	var st State
 	rt.StateTransaction(&st, func() {
 		var err error
-		result, more, err = st.PopEarlyTerminations(store, AddressedPartitionsMax, AddressedSectorsMax)
+		result, more, err = st.PopEarlyTerminations(store, limit.Groups, limit.Items)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to pop early terminations")
 
 		// Nothing to do, don't waste any time.
@@ -1908,58 +2168,73 @@ func handleProvingDeadline(rt Runtime) {
 		}
 		// Note: _don't_ process early terminations if we had a cron
 		// callback already scheduled. In that case, we'll already have
-		// processed AddressedSectorsMax terminations this epoch.
+		// processed a batch of terminations this epoch.
 	}
 }
 
 // Check expiry is exactly *the epoch before* the start of a proving period.
 func validateExpiration(rt Runtime, activation, expiration abi.ChainEpoch, sealProof abi.RegisteredSealProof) {
+	err := checkExpiration(rt.CurrEpoch(), activation, expiration, sealProof)
+	if err != nil {
+		rt.Abortf(exitcode.Unwrap(err, exitcode.ErrIllegalArgument), "%s", err)
+	}
+}
+
+func checkExpiration(currEpoch, activation, expiration abi.ChainEpoch, sealProof abi.RegisteredSealProof) error {
 	// Expiration must be after activation. Check this explicitly to avoid an underflow below.
 	if expiration <= activation {
-		rt.Abortf(exitcode.ErrIllegalArgument, "sector expiration %v must be after activation (%v)", expiration, activation)
+		return exitcode.ErrIllegalArgument.Wrapf("sector expiration %v must be after activation (%v)", expiration, activation)
 	}
 	// expiration cannot be less than minimum after activation
 	if expiration-activation < MinSectorExpiration {
-		rt.Abortf(exitcode.ErrIllegalArgument, "invalid expiration %d, total sector lifetime (%d) must exceed %d after activation %d",
+		return exitcode.ErrIllegalArgument.Wrapf("invalid expiration %d, total sector lifetime (%d) must exceed %d after activation %d",
 			expiration, expiration-activation, MinSectorExpiration, activation)
 	}
 
 	// expiration cannot exceed MaxSectorExpirationExtension from now
-	if expiration > rt.CurrEpoch()+MaxSectorExpirationExtension {
-		rt.Abortf(exitcode.ErrIllegalArgument, "invalid expiration %d, cannot be more than %d past current epoch %d",
-			expiration, MaxSectorExpirationExtension, rt.CurrEpoch())
+	if expiration > currEpoch+MaxSectorExpirationExtension {
+		return exitcode.ErrIllegalArgument.Wrapf("invalid expiration %d, cannot be more than %d past current epoch %d",
+			expiration, MaxSectorExpirationExtension, currEpoch)
 	}
 
 	// total sector lifetime cannot exceed SectorMaximumLifetime for the sector's seal proof
 	maxLifetime, err := builtin.SealProofSectorMaximumLifetime(sealProof)
-	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "unrecognized seal proof type %d", sealProof)
+	if err != nil {
+		return exitcode.ErrIllegalArgument.Wrapf("unrecognized seal proof type %d: %w", sealProof, err)
+	}
 	if expiration-activation > maxLifetime {
-		rt.Abortf(exitcode.ErrIllegalArgument, "invalid expiration %d, total sector lifetime (%d) cannot exceed %d after activation %d",
+		return exitcode.ErrIllegalArgument.Wrapf("invalid expiration %d, total sector lifetime (%d) cannot exceed %d after activation %d",
 			expiration, expiration-activation, maxLifetime, activation)
 	}
+	return nil
 }
 
-func validateReplaceSector(rt Runtime, st *State, store adt.Store, params *PreCommitSectorParams) {
+func checkReplaceSector(st *State, store adt.Store, params *PreCommitSectorParams) error {
 	replaceSector, found, err := st.GetSector(store, params.ReplaceSectorNumber)
-	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load sector %v", params.SectorNumber)
+	if err != nil {
+		return xerrors.Errorf("failed to load sector %v: %w", params.SectorNumber, err)
+	}
 	if !found {
-		rt.Abortf(exitcode.ErrNotFound, "no such sector %v to replace", params.ReplaceSectorNumber)
+		return exitcode.ErrNotFound.Wrapf("no such sector %v to replace", params.ReplaceSectorNumber)
 	}
 
 	if len(replaceSector.DealIDs) > 0 {
-		rt.Abortf(exitcode.ErrIllegalArgument, "cannot replace sector %v which has deals", params.ReplaceSectorNumber)
+		return exitcode.ErrIllegalArgument.Wrapf("cannot replace sector %v which has deals", params.ReplaceSectorNumber)
 	}
 	if params.SealProof != replaceSector.SealProof {
-		rt.Abortf(exitcode.ErrIllegalArgument, "cannot replace sector %v seal proof %v with seal proof %v",
+		return exitcode.ErrIllegalArgument.Wrapf("cannot replace sector %v seal proof %v with seal proof %v",
 			params.ReplaceSectorNumber, replaceSector.SealProof, params.SealProof)
 	}
 	if params.Expiration < replaceSector.Expiration {
-		rt.Abortf(exitcode.ErrIllegalArgument, "cannot replace sector %v expiration %v with sooner expiration %v",
+		return exitcode.ErrIllegalArgument.Wrapf("cannot replace sector %v expiration %v with sooner expiration %v",
 			params.ReplaceSectorNumber, replaceSector.Expiration, params.Expiration)
 	}
 
 	err = st.CheckSectorHealth(store, params.ReplaceSectorDeadline, params.ReplaceSectorPartition, params.ReplaceSectorNumber)
-	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to replace sector %v", params.ReplaceSectorNumber)
+	if err != nil {
+		return xerrors.Errorf("failed to replace sector %v: %w", params.ReplaceSectorNumber, err)
+	}
+	return nil
 }
 
 func enrollCronEvent(rt Runtime, eventEpoch abi.ChainEpoch, callbackPayload *CronEventPayload) {
@@ -1968,34 +2243,20 @@ func enrollCronEvent(rt Runtime, eventEpoch abi.ChainEpoch, callbackPayload *Cro
 	if err != nil {
 		rt.Abortf(exitcode.ErrIllegalArgument, "failed to serialize payload: %v", err)
 	}
-	code := rt.Send(
-		builtin.StoragePowerActorAddr,
-		builtin.MethodsPower.EnrollCronEvent,
-		&power.EnrollCronEventParams{
-			EventEpoch: eventEpoch,
-			Payload:    payload.Bytes(),
-		},
-		abi.NewTokenAmount(0),
-		&builtin.Discard{},
-	)
-	builtin.RequireSuccess(rt, code, "failed to enroll cron event")
+	power.NewClient(rt).EnrollCronEvent(&power.EnrollCronEventParams{
+		EventEpoch: eventEpoch,
+		Payload:    payload.Bytes(),
+	})
 }
 
 func requestUpdatePower(rt Runtime, delta PowerPair) {
 	if delta.IsZero() {
 		return
 	}
-	code := rt.Send(
-		builtin.StoragePowerActorAddr,
-		builtin.MethodsPower.UpdateClaimedPower,
-		&power.UpdateClaimedPowerParams{
-			RawByteDelta:         delta.Raw,
-			QualityAdjustedDelta: delta.QA,
-		},
-		abi.NewTokenAmount(0),
-		&builtin.Discard{},
-	)
-	builtin.RequireSuccess(rt, code, "failed to update power with %v", delta)
+	power.NewClient(rt).UpdateClaimedPower(&power.UpdateClaimedPowerParams{
+		RawByteDelta:         delta.Raw,
+		QualityAdjustedDelta: delta.QA,
+	})
 }
 
 func requestTerminateDeals(rt Runtime, epoch abi.ChainEpoch, dealIDs []abi.DealID) {
@@ -2029,7 +2290,8 @@ func havePendingEarlyTerminations(rt Runtime, st *State) bool {
 	return !noEarlyTerminations
 }
 
-func verifyWindowedPost(rt Runtime, challengeEpoch abi.ChainEpoch, sectors []*SectorOnChainInfo, proofs []proof.PoStProof) {
+// Builds the information with which to verify a Window PoSt over sectors, regenerating its challenge randomness.
+func windowedPoStVerifyInfo(rt Runtime, challengeEpoch abi.ChainEpoch, sectors []*SectorOnChainInfo, proofs []proof.PoStProof) proof.WindowPoStVerifyInfo {
 	minerActorID, err := addr.IDFromAddress(rt.Receiver())
 	AssertNoError(err) // Runtime always provides ID-addresses
 
@@ -2038,7 +2300,7 @@ func verifyWindowedPost(rt Runtime, challengeEpoch abi.ChainEpoch, sectors []*Se
 	receiver := rt.Receiver()
 	err = receiver.MarshalCBOR(&addrBuf)
 	builtin.RequireNoErr(rt, err, exitcode.ErrSerialization, "failed to marshal address for window post challenge")
-	postRandomness := rt.GetRandomnessFromBeacon(crypto.DomainSeparationTag_WindowedPoStChallengeSeed, challengeEpoch, addrBuf.Bytes())
+	postRandomness := rt.GetRandomness(runtime.RandomnessBeacon, crypto.DomainSeparationTag_WindowedPoStChallengeSeed, challengeEpoch, addrBuf.Bytes())
 
 	sectorProofInfo := make([]proof.SectorInfo, len(sectors))
 	for i, s := range sectors {
@@ -2050,16 +2312,32 @@ func verifyWindowedPost(rt Runtime, challengeEpoch abi.ChainEpoch, sectors []*Se
 	}
 
 	// Get public inputs
-	pvInfo := proof.WindowPoStVerifyInfo{
+	return proof.WindowPoStVerifyInfo{
 		Randomness:        abi.PoStRandomness(postRandomness),
 		Proofs:            proofs,
 		ChallengedSectors: sectorProofInfo,
 		Prover:            abi.ActorID(minerActorID),
 	}
+}
 
-	// Verify the PoSt Proof
-	if err = rt.VerifyPoSt(pvInfo); err != nil {
-		rt.Abortf(exitcode.ErrIllegalArgument, "invalid PoSt %+v: %s", pvInfo, err)
+// Verifies Window PoSts, aborting if any is invalid.
+// A single PoSt is verified alone, and several together as a batch.
+func verifyWindowedPoSts(rt Runtime, vis []proof.WindowPoStVerifyInfo) {
+	if len(vis) == 1 {
+		if err := rt.VerifyPoSt(vis[0]); err != nil {
+			rt.Abortf(exitcode.ErrIllegalArgument, "invalid PoSt %+v: %s", vis[0], err)
+		}
+		return
+	}
+	res, err := rt.BatchVerifyPoSts(vis)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to batch verify PoSts")
+	if len(res) != len(vis) {
+		rt.Abortf(exitcode.ErrIllegalState, "batch verification returned %d results for %d PoSts", len(res), len(vis))
+	}
+	for i, ok := range res {
+		if !ok {
+			rt.Abortf(exitcode.ErrIllegalArgument, "invalid PoSt %d of %d in batch %+v", i, len(vis), vis[i])
+		}
 	}
 }
 
@@ -2092,8 +2370,8 @@ func getVerifyInfo(rt Runtime, params *SealVerifyStuff) *proof.SealVerifyInfo {
 	err = receiver.MarshalCBOR(buf)
 	builtin.RequireNoErr(rt, err, exitcode.ErrSerialization, "failed to marshal address for seal verification challenge")
 
-	svInfoRandomness := rt.GetRandomnessFromTickets(crypto.DomainSeparationTag_SealRandomness, params.SealRandEpoch, buf.Bytes())
-	svInfoInteractiveRandomness := rt.GetRandomnessFromBeacon(crypto.DomainSeparationTag_InteractiveSealChallengeSeed, params.InteractiveEpoch, buf.Bytes())
+	svInfoRandomness := rt.GetRandomness(runtime.RandomnessTickets, crypto.DomainSeparationTag_SealRandomness, params.SealRandEpoch, buf.Bytes())
+	svInfoInteractiveRandomness := rt.GetRandomness(runtime.RandomnessBeacon, crypto.DomainSeparationTag_InteractiveSealChallengeSeed, params.InteractiveEpoch, buf.Bytes())
 
 	return &proof.SealVerifyInfo{
 		SealProof: params.RegisteredSealProof,
@@ -2155,18 +2433,12 @@ func requestDealWeight(rt Runtime, dealIDs []abi.DealID, sectorStart, sectorExpi
 // Requests the current epoch target block reward from the reward actor.
 // return value includes reward, smoothed estimate of reward, and baseline power
 func requestCurrentEpochBlockReward(rt Runtime) reward.ThisEpochRewardReturn {
-	var ret reward.ThisEpochRewardReturn
-	code := rt.Send(builtin.RewardActorAddr, builtin.MethodsReward.ThisEpochReward, nil, big.Zero(), &ret)
-	builtin.RequireSuccess(rt, code, "failed to check epoch baseline power")
-	return ret
+	return *reward.NewClient(rt).ThisEpochReward()
 }
 
 // Requests the current network total power and pledge from the power actor.
 func requestCurrentTotalPower(rt Runtime) *power.CurrentTotalPowerReturn {
-	var pwr power.CurrentTotalPowerReturn
-	code := rt.Send(builtin.StoragePowerActorAddr, builtin.MethodsPower.CurrentTotalPower, nil, big.Zero(), &pwr)
-	builtin.RequireSuccess(rt, code, "failed to check current power")
-	return &pwr
+	return power.NewClient(rt).CurrentTotalPower()
 }
 
 // Resolves an address to an ID address and verifies that it is address of an account or multisig actor.
@@ -2206,7 +2478,7 @@ func resolveWorkerAddress(rt Runtime, raw addr.Address) addr.Address {
 
 	if raw.Protocol() != addr.BLS {
 		var pubkey addr.Address
-		code := rt.Send(resolved, builtin.MethodsAccount.PubkeyAddress, nil, big.Zero(), &pubkey)
+		code := rt.SendReadOnly(resolved, builtin.MethodsAccount.PubkeyAddress, nil, &pubkey)
 		builtin.RequireSuccess(rt, code, "failed to fetch account pubkey from %v", resolved)
 		if pubkey.Protocol() != addr.BLS {
 			rt.Abortf(exitcode.ErrIllegalArgument, "worker account %v must have BLS pubkey, was %v", resolved, pubkey.Protocol())
@@ -2224,8 +2496,7 @@ func burnFunds(rt Runtime, amt abi.TokenAmount) {
 
 func notifyPledgeChanged(rt Runtime, pledgeDelta abi.TokenAmount) {
 	if !pledgeDelta.IsZero() {
-		code := rt.Send(builtin.StoragePowerActorAddr, builtin.MethodsPower.UpdatePledgeTotal, &pledgeDelta, big.Zero(), &builtin.Discard{})
-		builtin.RequireSuccess(rt, code, "failed to update total pledge")
+		power.NewClient(rt).UpdatePledgeTotal(pledgeDelta)
 	}
 }
 
@@ -2335,6 +2606,17 @@ func validateFRDeclarationDeadline(deadline *dline.Info) error {
 	return nil
 }
 
+// Limits on a bitfield of sector numbers or partition indices in method parameters with at most maxSetBits set,
+// checked before the bitfield is expanded or merged with others.
+// A bitfield with that many bits set has at most 2*maxSetBits+1 runs.
+func addressedBitFieldLimits(maxSetBits uint64) BitFieldLimits {
+	return BitFieldLimits{
+		MaxEncodedSize: bitfield.MaxEncodedSize,
+		MaxRuns:        2*maxSetBits + 1,
+		MaxSetBits:     maxSetBits,
+	}
+}
+
 // Validates that a partition contains the given sectors.
 func validatePartitionContainsSectors(partition *Partition, sectors bitfield.BitField) error {
 	// Check that the declared sectors are actually assigned to the partition.
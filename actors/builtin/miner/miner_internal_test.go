@@ -113,7 +113,7 @@ func TestFaultFeeInvariants(t *testing.T) {
 		rewardEstimate := smoothing.TestingConstantEstimate(tensOfFIL)
 		smallPower := big.NewInt(32 << 30) // 32 GiB
 		hugePower := big.NewInt(1 << 60)   // 1 EiB
-		epochsPerDay := big.NewInt(builtin.EpochsInDay)
+		epochsPerDay := big.NewInt(int64(builtin.EpochsInDay))
 		smallPowerBRNum := big.Mul(big.Mul(smallPower, epochsPerDay), tensOfFIL)
 		hugePowerBRNum := big.Mul(big.Mul(hugePower, epochsPerDay), tensOfFIL)
 
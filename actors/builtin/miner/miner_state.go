@@ -18,6 +18,7 @@ import (
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
 	. "github.com/filecoin-project/specs-actors/v2/actors/util"
 	"github.com/filecoin-project/specs-actors/v2/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/v2/actors/util/math"
 )
 
 // Balance of Miner Actor should be greater than or equal to
@@ -749,8 +750,9 @@ func (st *State) AddLockedFunds(store adt.Store, currEpoch abi.ChainEpoch, vesti
 
 	// unlock vested funds first
 	amountUnlocked := vestingFunds.unlockVestedFunds(currEpoch)
-	st.LockedFunds = big.Sub(st.LockedFunds, amountUnlocked)
-	Assert(st.LockedFunds.GreaterThanEqual(big.Zero()))
+	var ok bool
+	st.LockedFunds, ok = math.CheckedSub(st.LockedFunds, amountUnlocked)
+	AssertMsg(ok, "unlocked %v exceeds locked funds", amountUnlocked)
 
 	// add locked funds now
 	vestingFunds.addLockedFunds(currEpoch, vestingSum, st.ProvingPeriodStart, spec)
@@ -790,11 +792,11 @@ func (st *State) RepayPartialDebtInPriorityOrder(store adt.Store, currEpoch abi.
 	}
 
 	// We should never unlock more than the debt we need to repay
-	Assert(fromVesting.LessThanEqual(st.FeeDebt))
-	st.FeeDebt = big.Sub(st.FeeDebt, fromVesting)
+	feeDebt, ok := math.CheckedSub(st.FeeDebt, fromVesting)
+	AssertMsg(ok, "unlocked %v exceeds fee debt %v", fromVesting, st.FeeDebt)
 
-	fromBalance = big.Min(unlockedBalance, st.FeeDebt)
-	st.FeeDebt = big.Sub(st.FeeDebt, fromBalance)
+	fromBalance = big.Min(unlockedBalance, feeDebt)
+	st.FeeDebt = big.Sub(feeDebt, fromBalance)
 
 	return fromVesting, fromBalance, nil
 
@@ -810,7 +812,7 @@ func (st *State) repayDebts(currBalance abi.TokenAmount) (abi.TokenAmount, error
 		return big.Zero(), err
 	}
 	if unlockedBalance.LessThan(st.FeeDebt) {
-		return big.Zero(), xc.ErrInsufficientFunds.Wrapf("unlocked balance can not repay fee debt (%v < %v)", unlockedBalance, st.FeeDebt)
+		return big.Zero(), builtin.InsufficientFundsf("unlocked balance can not repay fee debt (%v < %v)", unlockedBalance, st.FeeDebt)
 	}
 	debtToRepay := st.FeeDebt
 	st.FeeDebt = big.Zero()
@@ -833,8 +835,9 @@ func (st *State) UnlockUnvestedFunds(store adt.Store, currEpoch abi.ChainEpoch,
 
 	amountUnlocked := vestingFunds.unlockUnvestedFunds(currEpoch, target)
 
-	st.LockedFunds = big.Sub(st.LockedFunds, amountUnlocked)
-	Assert(st.LockedFunds.GreaterThanEqual(big.Zero()))
+	lockedFunds, ok := math.CheckedSub(st.LockedFunds, amountUnlocked)
+	AssertMsg(ok, "unlocked %v exceeds locked funds %v", amountUnlocked, st.LockedFunds)
+	st.LockedFunds = lockedFunds
 
 	if err := st.SaveVestingFunds(store, vestingFunds); err != nil {
 		return big.Zero(), xerrors.Errorf("failed to save vesting funds: %w", err)
@@ -857,10 +860,11 @@ func (st *State) UnlockVestedFunds(store adt.Store, currEpoch abi.ChainEpoch) (a
 	}
 
 	amountUnlocked := vestingFunds.unlockVestedFunds(currEpoch)
-	st.LockedFunds = big.Sub(st.LockedFunds, amountUnlocked)
-	if st.LockedFunds.LessThan(big.Zero()) {
-		return big.Zero(), xerrors.Errorf("vesting cause locked funds negative %v", st.LockedFunds)
+	lockedFunds, ok := math.CheckedSub(st.LockedFunds, amountUnlocked)
+	if !ok {
+		return big.Zero(), xerrors.Errorf("vesting %v exceeds locked funds %v", amountUnlocked, st.LockedFunds)
 	}
+	st.LockedFunds = lockedFunds
 
 	err = st.SaveVestingFunds(store, vestingFunds)
 	if err != nil {
@@ -897,9 +901,10 @@ func (st *State) CheckVestedFunds(store adt.Store, currEpoch abi.ChainEpoch) (ab
 // Unclaimed funds that are not locked -- includes free funds and does not
 // account for fee debt.  Always greater than or equal to zero
 func (st *State) GetUnlockedBalance(actorBalance abi.TokenAmount) (abi.TokenAmount, error) {
-	unlockedBalance := big.Subtract(actorBalance, st.LockedFunds, st.PreCommitDeposits, st.InitialPledge)
-	if unlockedBalance.LessThan(big.Zero()) {
-		return big.Zero(), xerrors.Errorf("negative unlocked balance %v", unlockedBalance)
+	locked := big.Sum(st.LockedFunds, st.PreCommitDeposits, st.InitialPledge)
+	unlockedBalance, ok := math.CheckedSub(actorBalance, locked)
+	if !ok {
+		return big.Zero(), xerrors.Errorf("negative unlocked balance %v", big.Sub(actorBalance, locked))
 	}
 	return unlockedBalance, nil
 }
@@ -1014,10 +1019,11 @@ func (st *State) ExpirePreCommits(store adt.Store, currEpoch abi.ChainEpoch) (de
 		}
 	}
 
-	st.PreCommitDeposits = big.Sub(st.PreCommitDeposits, depositToBurn)
-	if st.PreCommitDeposits.LessThan(big.Zero()) {
-		return big.Zero(), xerrors.Errorf("pre-commit expiry caused negative deposits: %v", st.PreCommitDeposits)
+	deposits, ok := math.CheckedSub(st.PreCommitDeposits, depositToBurn)
+	if !ok {
+		return big.Zero(), xerrors.Errorf("pre-commit expiry caused negative deposits: %v", big.Sub(st.PreCommitDeposits, depositToBurn))
 	}
+	st.PreCommitDeposits = deposits
 
 	// This deposit was locked separately to pledge collateral so there's no pledge change here.
 	return depositToBurn, nil
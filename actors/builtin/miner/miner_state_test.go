@@ -17,6 +17,7 @@ import (
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/miner"
 	"github.com/filecoin-project/specs-actors/v2/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/v2/support/fixtures"
 	"github.com/filecoin-project/specs-actors/v2/support/ipld"
 	tutils "github.com/filecoin-project/specs-actors/v2/support/testing"
 )
@@ -887,57 +888,13 @@ func (h *stateHarness) deletePreCommit(sectorNo abi.SectorNumber) {
 }
 
 func constructStateHarness(t *testing.T, periodBoundary abi.ChainEpoch) *stateHarness {
-	// store init
 	store := ipld.NewADTStore(context.Background())
-	emptyMap, err := adt.MakeEmptyMap(store).Root()
-	require.NoError(t, err)
-
-	emptyBitfield := bitfield.NewFromSet(nil)
-	emptyBitfieldCid, err := store.Put(store.Context(), emptyBitfield)
-	require.NoError(t, err)
-
-	emptyArray, err := adt.MakeEmptyArray(store).Root()
-	require.NoError(t, err)
-	emptyDeadline := miner.ConstructDeadline(emptyArray)
-	emptyDeadlineCid, err := store.Put(store.Context(), emptyDeadline)
-	require.NoError(t, err)
-
-	emptyDeadlines := miner.ConstructDeadlines(emptyDeadlineCid)
-	emptyDeadlinesCid, err := store.Put(context.Background(), emptyDeadlines)
-	require.NoError(t, err)
-
-	// state field init
-	owner := tutils.NewBLSAddr(t, 1)
-	worker := tutils.NewBLSAddr(t, 2)
-
-	testSealProofType := abi.RegisteredSealProof_StackedDrg2KiBV1
-
-	sectorSize, err := testSealProofType.SectorSize()
-	require.NoError(t, err)
-
-	partitionSectors, err := builtin.SealProofWindowPoStPartitionSectors(testSealProofType)
-	require.NoError(t, err)
-
-	info := miner.MinerInfo{
-		Owner:                      owner,
-		Worker:                     worker,
-		PendingWorkerKey:           nil,
-		PeerId:                     abi.PeerID("peer"),
-		Multiaddrs:                 testMultiaddrs,
-		SealProofType:              testSealProofType,
-		SectorSize:                 sectorSize,
-		WindowPoStPartitionSectors: partitionSectors,
-	}
-	infoCid, err := store.Put(context.Background(), &info)
-	require.NoError(t, err)
-
-	emptyVestingFunds := miner.ConstructVestingFunds()
-	emptyVestingFundsCid, err := store.Put(context.Background(), emptyVestingFunds)
-	require.NoError(t, err)
-
-	state, err := miner.ConstructState(infoCid, periodBoundary, 0, emptyBitfieldCid, emptyArray, emptyMap, emptyDeadlinesCid,
-		emptyVestingFundsCid)
-	require.NoError(t, err)
+	state := fixtures.NewMinerBuilder(t, store).
+		WithOwner(tutils.NewBLSAddr(t, 1), tutils.NewBLSAddr(t, 2)).
+		WithSealProof(abi.RegisteredSealProof_StackedDrg2KiBV1).
+		WithPeer(abi.PeerID("peer"), testMultiaddrs...).
+		WithProvingPeriodStart(periodBoundary).
+		Build()
 
 	return &stateHarness{
 		t: t,
@@ -970,21 +927,12 @@ const (
 
 // returns a unique SectorOnChainInfo with each invocation with SectorNumber set to `sectorNo`.
 func newSectorOnChainInfo(sectorNo abi.SectorNumber, sealed cid.Cid, weight big.Int, activation abi.ChainEpoch) *miner.SectorOnChainInfo {
-	return &miner.SectorOnChainInfo{
-		SectorNumber:          sectorNo,
-		SealProof:             abi.RegisteredSealProof_StackedDrg32GiBV1,
-		SealedCID:             sealed,
-		DealIDs:               nil,
-		Activation:            activation,
-		Expiration:            sectorExpiration,
-		DealWeight:            weight,
-		VerifiedDealWeight:    weight,
-		InitialPledge:         abi.NewTokenAmount(0),
-		ExpectedDayReward:     abi.NewTokenAmount(0),
-		ExpectedStoragePledge: abi.NewTokenAmount(0),
-		ReplacedSectorAge:     abi.ChainEpoch(0),
-		ReplacedDayReward:     big.Zero(),
-	}
+	return fixtures.NewSectorBuilder(sectorNo).
+		WithSealedCID(sealed).
+		WithActivation(activation).
+		WithExpiration(sectorExpiration).
+		WithDealWeights(weight, weight).
+		Build()
 }
 
 // returns a unique SectorPreCommitInfo with each invocation with SectorNumber set to `sectorNo`.
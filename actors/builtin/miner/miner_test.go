@@ -11,12 +11,14 @@ import (
 
 	addr "github.com/filecoin-project/go-address"
 	bitfield "github.com/filecoin-project/go-bitfield"
+	rlepluslazy "github.com/filecoin-project/go-bitfield/rle"
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
 	"github.com/filecoin-project/go-state-types/crypto"
 	"github.com/filecoin-project/go-state-types/dline"
 	"github.com/filecoin-project/go-state-types/exitcode"
 	"github.com/filecoin-project/go-state-types/network"
+	miner0 "github.com/filecoin-project/specs-actors/actors/builtin/miner"
 	cid "github.com/ipfs/go-cid"
 	"github.com/minio/blake2b-simd"
 	"github.com/stretchr/testify/assert"
@@ -60,10 +62,12 @@ func init() {
 }
 
 func TestExports(t *testing.T) {
+	t.Parallel()
 	mock.CheckActorExports(t, miner.Actor{})
 }
 
 func TestConstruction(t *testing.T) {
+	t.Parallel()
 	actor := miner.Actor{}
 	owner := tutil.NewIDAddr(t, 100)
 	worker := tutil.NewIDAddr(t, 101)
@@ -72,7 +76,7 @@ func TestConstruction(t *testing.T) {
 	controlAddrs := []addr.Address{tutil.NewIDAddr(t, 999), tutil.NewIDAddr(t, 998)}
 
 	receiver := tutil.NewIDAddr(t, 1000)
-	builder := mock.NewBuilder(context.Background(), receiver).
+	builder := mock.NewBuilder(context.Background(), receiver).WithStateInvariants(miner.CheckStateInvariants).
 		WithActorType(owner, builtin.AccountActorCodeID).
 		WithActorType(worker, builtin.AccountActorCodeID).
 		WithActorType(controlAddrs[0], builtin.AccountActorCodeID).
@@ -94,7 +98,7 @@ func TestConstruction(t *testing.T) {
 		provingPeriodStart := abi.ChainEpoch(-2222) // This is just set from running the code.
 		rt.ExpectValidateCallerAddr(builtin.InitActorAddr)
 		// Fetch worker pubkey.
-		rt.ExpectSend(worker, builtin.MethodsAccount.PubkeyAddress, nil, big.Zero(), &workerKey, exitcode.Ok)
+		rt.ExpectSendReadOnly(worker, builtin.MethodsAccount.PubkeyAddress, nil, &workerKey, exitcode.Ok)
 		// Register proving period cron.
 		dlIdx := (rt.Epoch() - provingPeriodStart) / miner.WPoStChallengeWindow
 		firstDeadlineClose := provingPeriodStart + (1+dlIdx)*miner.WPoStChallengeWindow
@@ -164,7 +168,7 @@ func TestConstruction(t *testing.T) {
 
 		provingPeriodStart := abi.ChainEpoch(-2222) // This is just set from running the code.
 		rt.ExpectValidateCallerAddr(builtin.InitActorAddr)
-		rt.ExpectSend(worker, builtin.MethodsAccount.PubkeyAddress, nil, big.Zero(), &workerKey, exitcode.Ok)
+		rt.ExpectSendReadOnly(worker, builtin.MethodsAccount.PubkeyAddress, nil, &workerKey, exitcode.Ok)
 		dlIdx := (rt.Epoch() - provingPeriodStart) / miner.WPoStChallengeWindow
 		firstDeadlineClose := provingPeriodStart + (1+dlIdx)*miner.WPoStChallengeWindow
 		rt.ExpectSend(builtin.StoragePowerActorAddr, builtin.MethodsPower.EnrollCronEvent,
@@ -194,7 +198,7 @@ func TestConstruction(t *testing.T) {
 		}
 
 		rt.ExpectValidateCallerAddr(builtin.InitActorAddr)
-		rt.ExpectSend(worker, builtin.MethodsAccount.PubkeyAddress, nil, big.Zero(), &workerKey, exitcode.Ok)
+		rt.ExpectSendReadOnly(worker, builtin.MethodsAccount.PubkeyAddress, nil, &workerKey, exitcode.Ok)
 
 		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
 			rt.Call(actor.Constructor, &params)
@@ -288,6 +292,7 @@ func TestConstruction(t *testing.T) {
 
 // Test operations related to peer info (peer ID/multiaddrs)
 func TestPeerInfo(t *testing.T) {
+	t.Parallel()
 	h := newHarness(t, 0)
 	builder := builderForHarness(h)
 
@@ -370,6 +375,7 @@ func TestPeerInfo(t *testing.T) {
 
 // Tests for fetching and manipulating miner addresses.
 func TestControlAddresses(t *testing.T) {
+	t.Parallel()
 	actor := newHarness(t, 0)
 	builder := builderForHarness(actor)
 
@@ -389,6 +395,7 @@ func TestControlAddresses(t *testing.T) {
 
 // Test for sector precommitment and proving.
 func TestCommitments(t *testing.T) {
+	t.Parallel()
 	periodOffset := abi.ChainEpoch(100)
 	t.Run("valid precommit then provecommit", func(t *testing.T) {
 		actor := newHarness(t, periodOffset)
@@ -875,6 +882,7 @@ func TestCommitments(t *testing.T) {
 
 // Test sector lifecycle when a sector is upgraded
 func TestCCUpgrade(t *testing.T) {
+	t.Parallel()
 	periodOffset := abi.ChainEpoch(100)
 	t.Run("valid committed capacity upgrade", func(t *testing.T) {
 		actor := newHarness(t, periodOffset)
@@ -1501,14 +1509,7 @@ func TestCCUpgrade(t *testing.T) {
 			expectedEnrollment: rt.Epoch() + miner.WPoStChallengeWindow,
 		})
 
-		//actor.checkState(rt)
-		// ExtendSectorExpiration fails to update the deadline expiration queue.
-		st = getState(rt)
-		_, msgs := miner.CheckStateInvariants(st, rt.AdtStore(), rt.Balance())
-		assert.Equal(t, 2, len(msgs.Messages()), strings.Join(msgs.Messages(), "\n"))
-		for _, msg := range msgs.Messages() {
-			assert.True(t, strings.Contains(msg, "at epoch 725919"))
-		}
+		actor.checkState(rt)
 	})
 
 	t.Run("fault and recover a replaced sector", func(t *testing.T) {
@@ -1726,6 +1727,7 @@ func TestCCUpgrade(t *testing.T) {
 }
 
 func TestWindowPost(t *testing.T) {
+	t.Parallel()
 	periodOffset := abi.ChainEpoch(100)
 	actor := newHarness(t, periodOffset)
 	actor.setProofType(abi.RegisteredSealProof_StackedDrg2KiBV1)
@@ -1810,7 +1812,7 @@ func TestWindowPost(t *testing.T) {
 		expectQueryNetworkInfo(rt, actor)
 		rt.SetCaller(actor.worker, builtin.AccountActorCodeID)
 		rt.ExpectValidateCallerAddr(append(actor.controlAddrs, actor.owner, actor.worker)...)
-		rt.ExpectGetRandomnessTickets(crypto.DomainSeparationTag_PoStChainCommit, dlinfo.Challenge, nil, commitRand)
+		rt.ExpectGetRandomness(runtime.RandomnessTickets, crypto.DomainSeparationTag_PoStChainCommit, dlinfo.Challenge, nil, commitRand)
 		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "no active sectors", func() {
 			rt.Call(actor.a.SubmitWindowedPoSt, &params)
 		})
@@ -2069,9 +2071,87 @@ func TestWindowPost(t *testing.T) {
 		})
 		actor.checkState(rt)
 	})
+
+	t.Run("batch of posts proves partitions with one verification", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		// create enough sectors that one deadline has two partitions
+		n := 95
+		infos := actor.commitAndProveSectors(rt, n, defaultSectorExpiration, nil)
+		st := getState(rt)
+		dlIdx, pIdx0, err := st.FindSector(rt.AdtStore(), infos[0].SectorNumber)
+		require.NoError(t, err)
+		_, pIdx1, err := st.FindSector(rt.AdtStore(), infos[n-1].SectorNumber)
+		require.NoError(t, err)
+		require.NotEqual(t, pIdx0, pIdx1)
+
+		dlinfo := actor.deadline(rt)
+		for dlinfo.Index != dlIdx {
+			dlinfo = advanceDeadline(rt, actor, &cronConfig{})
+		}
+
+		posts := [][]miner.PoStPartition{
+			{{Index: pIdx0, Skipped: bitfield.New()}},
+			{{Index: pIdx1, Skipped: bitfield.New()}},
+		}
+		proven := actor.partitionSectors(rt, dlIdx, infos, pIdx0, pIdx1)
+		actor.submitWindowPoStBatch(rt, dlinfo, posts, infos, &poStConfig{
+			expectedPowerDelta: miner.PowerForSectors(actor.sectorSize, proven),
+		}, []bool{true, true})
+
+		deadline := actor.getDeadline(rt, dlIdx)
+		assertBitfieldEquals(t, deadline.PostSubmissions, pIdx0, pIdx1)
+		actor.checkState(rt)
+	})
+
+	t.Run("batch of posts fails with an invalid proof", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		n := 95
+		infos := actor.commitAndProveSectors(rt, n, defaultSectorExpiration, nil)
+		st := getState(rt)
+		dlIdx, pIdx0, err := st.FindSector(rt.AdtStore(), infos[0].SectorNumber)
+		require.NoError(t, err)
+		_, pIdx1, err := st.FindSector(rt.AdtStore(), infos[n-1].SectorNumber)
+		require.NoError(t, err)
+
+		dlinfo := actor.deadline(rt)
+		for dlinfo.Index != dlIdx {
+			dlinfo = advanceDeadline(rt, actor, &cronConfig{})
+		}
+
+		posts := [][]miner.PoStPartition{
+			{{Index: pIdx0, Skipped: bitfield.New()}},
+			{{Index: pIdx1, Skipped: bitfield.New()}},
+		}
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "invalid PoSt 1 of 2", func() {
+			actor.submitWindowPoStBatch(rt, dlinfo, posts, infos, nil, []bool{true, false})
+		})
+		rt.Reset()
+
+		// Nothing was recorded.
+		deadline := actor.getDeadline(rt, dlIdx)
+		assertBitfieldEquals(t, deadline.PostSubmissions)
+		actor.checkState(rt)
+	})
+
+	t.Run("rejects too many posts in a batch", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		params := miner.SubmitWindowedPoStBatchParams{
+			Posts: make([]miner.SubmitWindowedPoStParams, miner.MaxWindowPoStBatchSize+1),
+		}
+		rt.SetCaller(actor.worker, builtin.AccountActorCodeID)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "too many posts", func() {
+			rt.Call(actor.a.SubmitWindowedPoStBatch, &params)
+		})
+	})
 }
 
 func TestProveCommit(t *testing.T) {
+	t.Parallel()
 	periodOffset := abi.ChainEpoch(100)
 	actor := newHarness(t, periodOffset)
 	builder := builderForHarness(actor).
@@ -2149,7 +2229,237 @@ func TestProveCommit(t *testing.T) {
 	})
 }
 
+func TestPreCommitSectorBatch(t *testing.T) {
+	t.Parallel()
+	periodOffset := abi.ChainEpoch(100)
+	actor := newHarness(t, periodOffset)
+	builder := builderForHarness(actor).
+		WithBalance(bigBalance, big.Zero())
+	expiration := defaultSectorExpiration*miner.WPoStProvingPeriod + periodOffset - 1
+
+	makePreCommits := func(rt *mock.Runtime, n int) []*miner.PreCommitSectorParams {
+		var params []*miner.PreCommitSectorParams
+		for i := 0; i < n; i++ {
+			params = append(params, actor.makePreCommit(actor.nextSectorNo, rt.Epoch()-1, expiration, nil))
+			actor.nextSectorNo++
+		}
+		return params
+	}
+	// Calls the batch method, expecting it to abort before consulting other actors.
+	callInvalid := func(rt *mock.Runtime, params []*miner.PreCommitSectorParams) {
+		batch := &miner.PreCommitSectorBatchParams{}
+		for _, p := range params {
+			batch.Sectors = append(batch.Sectors, *p)
+		}
+		rt.SetCaller(actor.worker, builtin.AccountActorCodeID)
+		rt.Call(actor.a.PreCommitSectorBatch, batch)
+	}
+
+	t.Run("pre-commits all sectors with the deposit of each", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		rt.SetEpoch(periodOffset + 1)
+
+		// A sector pre-committed alone requires the same deposit as each in the batch.
+		single := actor.preCommitSector(rt, makePreCommits(rt, 1)[0], preCommitConf{})
+		precommits := actor.preCommitSectorBatch(rt, makePreCommits(rt, 3))
+		for _, precommit := range precommits {
+			assert.Equal(t, rt.Epoch(), precommit.PreCommitEpoch)
+			assert.Equal(t, single.PreCommitDeposit, precommit.PreCommitDeposit)
+		}
+		st := getState(rt)
+		assert.Equal(t, big.Mul(big.NewInt(4), single.PreCommitDeposit), st.PreCommitDeposits)
+		actor.checkState(rt)
+	})
+
+	t.Run("rejects empty and oversized batches", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		rt.SetEpoch(periodOffset + 1)
+
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "no sectors", func() {
+			callInvalid(rt, nil)
+		})
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "too many sectors", func() {
+			callInvalid(rt, makePreCommits(rt, miner.MaxPreCommitSectorBatchSize+1))
+		})
+		rt.Verify()
+	})
+
+	t.Run("fails with every invalid sector identified", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		rt.SetEpoch(periodOffset + 1)
+
+		params := makePreCommits(rt, 4)
+		params[1].Expiration = rt.Epoch()
+		params[3].SealRandEpoch = rt.Epoch()
+		var failed builtin.SectorsFailure
+		rt.ExpectAbortDecodingData(exitcode.ErrIllegalArgument, &failed, func() {
+			callInvalid(rt, params)
+		})
+		sectors, err := failed.Sectors.All(miner.AddressedSectorsMax)
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{uint64(params[1].SectorNumber), uint64(params[3].SectorNumber)}, sectors)
+
+		// A sector number repeated within the batch is invalid.
+		params = makePreCommits(rt, 2)
+		params = append(params, params[0])
+		rt.ExpectAbortWithData(exitcode.ErrIllegalArgument, builtin.NewSectorsFailure(params[0].SectorNumber), func() {
+			callInvalid(rt, params)
+		})
+		rt.Verify()
+	})
+
+	t.Run("fails if the balance does not cover every deposit", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		rt.SetEpoch(periodOffset + 1)
+		single := actor.preCommitSector(rt, makePreCommits(rt, 1)[0], preCommitConf{})
+
+		// Leave funds available for one more sector but not two.
+		st := getState(rt)
+		rt.SetBalance(big.Sum(st.PreCommitDeposits, single.PreCommitDeposit, big.Div(single.PreCommitDeposit, big.NewInt(2))))
+		rt.ExpectAbortContainsMessage(exitcode.ErrInsufficientFunds, "insufficient funds", func() {
+			actor.preCommitSectorBatch(rt, makePreCommits(rt, 2))
+		})
+		rt.Reset()
+		actor.preCommitSectorBatch(rt, makePreCommits(rt, 1))
+		actor.checkState(rt)
+	})
+}
+
+func TestProveCommitSectors(t *testing.T) {
+	t.Parallel()
+	periodOffset := abi.ChainEpoch(100)
+	actor := newHarness(t, periodOffset)
+	builder := builderForHarness(actor).
+		WithBalance(bigBalance, big.Zero())
+
+	// Pre-commits n sectors and advances to an epoch at which they may be proven.
+	precommitSectors := func(rt *mock.Runtime, n int) []*miner.SectorPreCommitOnChainInfo {
+		expiration := defaultSectorExpiration*miner.WPoStProvingPeriod + periodOffset - 1
+		precommitEpoch := rt.Epoch() + 1
+		rt.SetEpoch(precommitEpoch)
+		var precommits []*miner.SectorPreCommitOnChainInfo
+		for i := 0; i < n; i++ {
+			params := actor.makePreCommit(actor.nextSectorNo, rt.Epoch()-1, expiration, nil)
+			precommits = append(precommits, actor.preCommitSector(rt, params, preCommitConf{}))
+			actor.nextSectorNo++
+		}
+		rt.SetEpoch(precommitEpoch + miner.PreCommitChallengeDelay + 1)
+		return precommits
+	}
+
+	t.Run("activates all sectors with valid proofs", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		precommits := precommitSectors(rt, 3)
+
+		actor.proveCommitSectors(rt, proveCommitConf{}, precommits, []bool{true, true, true})
+		for _, precommit := range precommits {
+			sector := actor.getSector(rt, precommit.Info.SectorNumber)
+			assert.Equal(t, rt.Epoch(), sector.Activation)
+			st := getState(rt)
+			_, found, err := st.GetPrecommittedSector(rt.AdtStore(), precommit.Info.SectorNumber)
+			require.NoError(t, err)
+			assert.False(t, found)
+		}
+		actor.checkState(rt)
+	})
+
+	t.Run("invalid proof does not fail the batch", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		precommits := precommitSectors(rt, 3)
+
+		actor.proveCommitSectors(rt, proveCommitConf{}, precommits, []bool{true, false, true})
+		actor.getSector(rt, precommits[0].Info.SectorNumber)
+		actor.getSector(rt, precommits[2].Info.SectorNumber)
+
+		// The sector with the invalid proof remains pre-committed.
+		st := getState(rt)
+		_, found, err := st.GetSector(rt.AdtStore(), precommits[1].Info.SectorNumber)
+		require.NoError(t, err)
+		assert.False(t, found)
+		actor.getPreCommit(rt, precommits[1].Info.SectorNumber)
+		actor.checkState(rt)
+	})
+
+	t.Run("sector with failed deals is not activated", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		precommits := precommitSectors(rt, 1)
+		expiration := precommits[0].Info.Expiration
+		rt.SetEpoch(precommits[0].PreCommitEpoch)
+		params := actor.makePreCommit(actor.nextSectorNo, rt.Epoch()-1, expiration, []abi.DealID{1})
+		precommits = append(precommits, actor.preCommitSector(rt, params, preCommitConf{}))
+		actor.nextSectorNo++
+		rt.SetEpoch(precommits[0].PreCommitEpoch + miner.PreCommitChallengeDelay + 1)
+
+		// Only the first sector is returned as activated.
+		conf := proveCommitConf{verifyDealsExit: map[abi.SectorNumber]exitcode.ExitCode{
+			precommits[1].Info.SectorNumber: exitcode.ErrIllegalArgument,
+		}}
+		actor.proveCommitSectors(rt, conf, precommits, []bool{true, true})
+		actor.getSector(rt, precommits[0].Info.SectorNumber)
+		actor.getPreCommit(rt, precommits[1].Info.SectorNumber)
+		actor.checkState(rt)
+	})
+
+	t.Run("fails if all proofs are invalid", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		precommits := precommitSectors(rt, 2)
+
+		// The failure identifies the sectors whose proofs failed.
+		var failed builtin.SectorsFailure
+		rt.ExpectAbortDecodingData(exitcode.ErrIllegalArgument, &failed, func() {
+			actor.proveCommitSectors(rt, proveCommitConf{}, precommits, []bool{false, false})
+		})
+		rt.Reset()
+		sectors, err := failed.Sectors.All(miner.AddressedSectorsMax)
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{uint64(precommits[0].Info.SectorNumber), uint64(precommits[1].Info.SectorNumber)}, sectors)
+		actor.checkState(rt)
+	})
+
+	t.Run("rejects invalid batches", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		rt.SetCaller(actor.worker, builtin.AccountActorCodeID)
+
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(actor.a.ProveCommitSectors, &miner.ProveCommitSectorsParams{})
+		})
+
+		tooMany := &miner.ProveCommitSectorsParams{}
+		for i := 0; i <= miner.MaxProveCommitSectorsBatchSize; i++ {
+			tooMany.Sectors = append(tooMany.Sectors, *makeProveCommit(abi.SectorNumber(i)))
+		}
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(actor.a.ProveCommitSectors, tooMany)
+		})
+
+		precommits := precommitSectors(rt, 1)
+		sectorNo := precommits[0].Info.SectorNumber
+		actor.expectProveCommitVerifyInfo(rt, precommits[0], makeProveCommit(sectorNo))
+		rt.SetCaller(actor.worker, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbortWithData(exitcode.ErrIllegalArgument, builtin.NewSectorsFailure(sectorNo), func() {
+			rt.Call(actor.a.ProveCommitSectors, &miner.ProveCommitSectorsParams{
+				Sectors: []miner.ProveCommitSectorParams{*makeProveCommit(sectorNo), *makeProveCommit(sectorNo)},
+			})
+		})
+		rt.Reset()
+		actor.checkState(rt)
+	})
+}
+
 func TestDeadlineCron(t *testing.T) {
+	t.Parallel()
 	periodOffset := abi.ChainEpoch(100)
 	actor := newHarness(t, periodOffset)
 	builder := builderForHarness(actor).
@@ -2368,6 +2678,7 @@ func TestDeadlineCron(t *testing.T) {
 }
 
 func TestDeclareFaults(t *testing.T) {
+	t.Parallel()
 	periodOffset := abi.ChainEpoch(100)
 	actor := newHarness(t, periodOffset)
 	builder := builderForHarness(actor).
@@ -2416,6 +2727,7 @@ func TestDeclareFaults(t *testing.T) {
 }
 
 func TestDeclareRecoveries(t *testing.T) {
+	t.Parallel()
 	periodOffset := abi.ChainEpoch(100)
 	actor := newHarness(t, periodOffset)
 	builder := builderForHarness(actor).
@@ -2519,9 +2831,34 @@ func TestDeclareRecoveries(t *testing.T) {
 		})
 		actor.checkState(rt)
 	})
+
+	t.Run("rejects declarations of too many sectors before reading them", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		// A single run of more sectors than may be addressed, which is cheap to encode but not to expand.
+		tooMany, err := bitfield.NewFromIter(&rlepluslazy.RunSliceIterator{Runs: []rlepluslazy.Run{
+			{Val: true, Len: miner.AddressedSectorsMax + 1},
+		}})
+		require.NoError(t, err)
+
+		rt.SetCaller(actor.worker, builtin.AccountActorCodeID)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "invalid sectors for deadline 0, partition 0", func() {
+			rt.Call(actor.a.DeclareFaults, &miner.DeclareFaultsParams{
+				Faults: []miner.FaultDeclaration{{Deadline: 0, Partition: 0, Sectors: tooMany}},
+			})
+		})
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "invalid sectors for deadline 0, partition 0", func() {
+			rt.Call(actor.a.DeclareFaultsRecovered, &miner.DeclareFaultsRecoveredParams{
+				Recoveries: []miner.RecoveryDeclaration{{Deadline: 0, Partition: 0, Sectors: tooMany}},
+			})
+		})
+		actor.checkState(rt)
+	})
 }
 
 func TestExtendSectorExpiration(t *testing.T) {
+	t.Parallel()
 	periodOffset := abi.ChainEpoch(100)
 	actor := newHarness(t, periodOffset)
 	precommitEpoch := abi.ChainEpoch(1)
@@ -2638,14 +2975,7 @@ func TestExtendSectorExpiration(t *testing.T) {
 			actor.extendSectors(rt, params)
 		})
 
-		// actor.checkState(rt)
-		// ExtendSectorExpiration fails to update the deadline expiration queue.
-		st = getState(rt)
-		_, msgs := miner.CheckStateInvariants(st, rt.AdtStore(), rt.Balance())
-		assert.Equal(t, 2, len(msgs.Messages()), strings.Join(msgs.Messages(), "\n"))
-		for _, msg := range msgs.Messages() {
-			assert.True(t, strings.Contains(msg, "at epoch 5213079"))
-		}
+		actor.checkState(rt)
 	})
 
 	t.Run("updates expiration with valid params", func(t *testing.T) {
@@ -2690,14 +3020,7 @@ func TestExtendSectorExpiration(t *testing.T) {
 		require.NoError(t, err)
 		assert.False(t, empty)
 
-		//actor.checkState(rt)
-		// ExtendSectorExpiration fails to update the deadline expiration queue.
-		st = getState(rt)
-		_, msgs := miner.CheckStateInvariants(st, rt.AdtStore(), rt.Balance())
-		assert.Equal(t, 2, len(msgs.Messages()), strings.Join(msgs.Messages(), "\n"))
-		for _, msg := range msgs.Messages() {
-			assert.True(t, strings.Contains(msg, "at epoch 668439"))
-		}
+		actor.checkState(rt)
 	})
 
 	t.Run("updates many sectors", func(t *testing.T) {
@@ -2705,6 +3028,8 @@ func TestExtendSectorExpiration(t *testing.T) {
 		actor.constructAndVerify(rt)
 
 		const sectorCount = 4000
+		// Checking invariants after each of the thousands of calls committing sectors is quadratic in their number.
+		rt.SetInvariantCheck(nil)
 
 		// commit a bunch of sectors to ensure that we get multiple partitions.
 		sectorInfos := actor.commitAndProveSectors(rt, sectorCount, defaultSectorExpiration, nil)
@@ -2786,17 +3111,7 @@ func TestExtendSectorExpiration(t *testing.T) {
 			assert.EqualValues(t, sectorCount/2, extendedTotal)
 		}
 
-		//actor.checkState(rt)
-		// ExtendSectorExpiration fails to update the deadline expiration queue.
-		st := getState(rt)
-		_, msgs := miner.CheckStateInvariants(st, rt.AdtStore(), rt.Balance())
-		assert.Equal(t, 4, len(msgs.Messages()), strings.Join(msgs.Messages(), "\n"))
-		for _, msg := range msgs.Messages()[:2] {
-			assert.True(t, strings.Contains(msg, "at epoch 668439")) // deadline 2
-		}
-		for _, msg := range msgs.Messages()[2:] {
-			assert.True(t, strings.Contains(msg, "at epoch 668499")) // deadline 3
-		}
+		actor.checkState(rt)
 	})
 
 	t.Run("supports extensions off deadline boundary", func(t *testing.T) {
@@ -2855,6 +3170,7 @@ func TestExtendSectorExpiration(t *testing.T) {
 }
 
 func TestTerminateSectors(t *testing.T) {
+	t.Parallel()
 	periodOffset := abi.ChainEpoch(100)
 	actor := newHarness(t, periodOffset)
 	builder := builderForHarness(actor).
@@ -2906,6 +3222,79 @@ func TestTerminateSectors(t *testing.T) {
 		actor.checkState(rt)
 	})
 
+	t.Run("defers early termination processing when no batch is suggested", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		rt.SetEpoch(abi.ChainEpoch(1))
+		sectorInfo := actor.commitAndProveSectors(rt, 1, defaultSectorExpiration, nil)
+		sector := sectorInfo[0]
+		advanceAndSubmitPoSts(rt, actor, sector)
+
+		sectorSize, err := sector.SealProof.SectorSize()
+		require.NoError(t, err)
+		sectorPower := miner.PowerForSector(sectorSize, sector)
+		terminationEpoch := rt.Epoch()
+
+		// Terminate with too little gas to process any of the early termination queue.
+		// Power is removed immediately, while the fee and pledge are deferred to a cron callback.
+		rt.SetGasAvailable(0)
+		rt.SetCaller(actor.worker, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(append(actor.controlAddrs, actor.owner, actor.worker)...)
+		payload := miner.CronEventPayload{EventType: miner.CronEventProcessEarlyTerminations}
+		var payloadBuf bytes.Buffer
+		require.NoError(t, payload.MarshalCBOR(&payloadBuf))
+		rt.ExpectSend(builtin.StoragePowerActorAddr, builtin.MethodsPower.EnrollCronEvent, &power.EnrollCronEventParams{
+			EventEpoch: rt.Epoch() + 1,
+			Payload:    payloadBuf.Bytes(),
+		}, big.Zero(), nil, exitcode.Ok)
+		rt.ExpectSend(builtin.StoragePowerActorAddr, builtin.MethodsPower.UpdateClaimedPower, &power.UpdateClaimedPowerParams{
+			RawByteDelta:         sectorPower.Raw.Neg(),
+			QualityAdjustedDelta: sectorPower.QA.Neg(),
+		}, big.Zero(), nil, exitcode.Ok)
+		deadlines, err := getState(rt).LoadDeadlines(rt.AdtStore())
+		require.NoError(t, err)
+		dlIdx, pIdx, err := miner.FindSector(rt.AdtStore(), deadlines, sector.SectorNumber)
+		require.NoError(t, err)
+		ret := rt.Call(actor.a.TerminateSectors, &miner.TerminateSectorsParams{Terminations: []miner.TerminationDeclaration{{
+			Deadline:  dlIdx,
+			Partition: pIdx,
+			Sectors:   bf(uint64(sector.SectorNumber)),
+		}}}).(*miner.TerminateSectorsReturn)
+		rt.Verify()
+		assert.False(t, ret.Done)
+
+		st := getState(rt)
+		noEarlyTerminations, err := st.EarlyTerminations.IsEmpty()
+		require.NoError(t, err)
+		assert.False(t, noEarlyTerminations)
+		assert.Equal(t, sector.InitialPledge, st.InitialPledge)
+
+		// The cron callback processes the queue once a batch is suggested.
+		rt.SetEpoch(rt.Epoch() + 1)
+		rt.SetBatchLimit(runtime.BatchEarlyTerminations, runtime.BatchLimit{Items: 1, Groups: 1})
+		dayReward := miner.ExpectedRewardForPower(actor.epochRewardSmooth, actor.epochQAPowerSmooth, sectorPower.QA, builtin.EpochsInDay)
+		twentyDayReward := miner.ExpectedRewardForPower(actor.epochRewardSmooth, actor.epochQAPowerSmooth, sectorPower.QA, miner.InitialPledgeProjectionPeriod)
+		expectedFee := miner.PledgePenaltyForTermination(dayReward, terminationEpoch-sector.Activation, twentyDayReward,
+			actor.epochQAPowerSmooth, sectorPower.QA, actor.epochRewardSmooth, big.Zero(), 0)
+		// With no locked funds, the fee is paid from the available balance and only the initial pledge is released.
+		pledgeDelta := sector.InitialPledge.Neg()
+
+		rt.ExpectValidateCallerAddr(builtin.StoragePowerActorAddr)
+		expectQueryNetworkInfo(rt, actor)
+		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, expectedFee, nil, exitcode.Ok)
+		rt.ExpectSend(builtin.StoragePowerActorAddr, builtin.MethodsPower.UpdatePledgeTotal, &pledgeDelta, big.Zero(), nil, exitcode.Ok)
+		rt.SetCaller(builtin.StoragePowerActorAddr, builtin.StoragePowerActorCodeID)
+		rt.Call(actor.a.OnDeferredCronEvent, &payload)
+		rt.Verify()
+
+		st = getState(rt)
+		noEarlyTerminations, err = st.EarlyTerminations.IsEmpty()
+		require.NoError(t, err)
+		assert.True(t, noEarlyTerminations)
+		assert.Equal(t, big.Zero(), st.InitialPledge)
+		actor.checkState(rt)
+	})
+
 	t.Run("charges correct fee for young termination of committed capacity upgrade", func(t *testing.T) {
 		actor := newHarness(t, periodOffset)
 		rt := builderForHarness(actor).
@@ -2965,6 +3354,7 @@ func TestTerminateSectors(t *testing.T) {
 }
 
 func TestWithdrawBalance(t *testing.T) {
+	t.Parallel()
 	periodOffset := abi.ChainEpoch(100)
 	actor := newHarness(t, periodOffset)
 	builder := builderForHarness(actor).
@@ -2981,6 +3371,48 @@ func TestWithdrawBalance(t *testing.T) {
 		actor.checkState(rt)
 	})
 
+	t.Run("withdraws funds to recipient", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		recipient := tutil.NewIDAddr(t, 1000)
+		rt.SetCaller(actor.owner, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(actor.owner)
+		rt.ExpectSend(recipient, builtin.MethodSend, nil, onePercentBalance, nil, exitcode.Ok)
+		rt.Call(actor.a.WithdrawBalance, &miner.WithdrawBalanceParams{
+			AmountRequested: onePercentBalance,
+			Recipient:       &recipient,
+		})
+		rt.Verify()
+		actor.checkState(rt)
+	})
+
+	t.Run("params without recipient decode and round trip", func(t *testing.T) {
+		// Parameters encoded before the recipient was added have only the amount.
+		var legacy bytes.Buffer
+		require.NoError(t, (&miner0.WithdrawBalanceParams{AmountRequested: onePercentBalance}).MarshalCBOR(&legacy))
+		var params miner.WithdrawBalanceParams
+		require.NoError(t, params.UnmarshalCBOR(bytes.NewReader(legacy.Bytes())))
+		assert.Equal(t, onePercentBalance, params.AmountRequested)
+		assert.Nil(t, params.Recipient)
+
+		// An absent recipient is encoded as null, and a present one as the address, each decoding to itself.
+		recipient := tutil.NewIDAddr(t, 1000)
+		for _, p := range []miner.WithdrawBalanceParams{
+			{AmountRequested: onePercentBalance},
+			{AmountRequested: onePercentBalance, Recipient: &recipient},
+		} {
+			var buf bytes.Buffer
+			require.NoError(t, p.MarshalCBOR(&buf))
+			var decoded miner.WithdrawBalanceParams
+			require.NoError(t, decoded.UnmarshalCBOR(bytes.NewReader(buf.Bytes())))
+			assert.Equal(t, p, decoded)
+			var reencoded bytes.Buffer
+			require.NoError(t, decoded.MarshalCBOR(&reencoded))
+			assert.Equal(t, buf.Bytes(), reencoded.Bytes())
+		}
+	})
+
 	t.Run("fails if miner can't repay fee debt", func(t *testing.T) {
 		rt := builder.Build(t)
 		actor.constructAndVerify(rt)
@@ -3011,6 +3443,7 @@ func TestWithdrawBalance(t *testing.T) {
 }
 
 func TestRepayDebts(t *testing.T) {
+	t.Parallel()
 	actor := newHarness(t, abi.ChainEpoch(100))
 	builder := builderForHarness(actor).
 		WithBalance(big.Zero(), big.Zero())
@@ -3097,6 +3530,7 @@ func TestRepayDebts(t *testing.T) {
 }
 
 func TestChangePeerID(t *testing.T) {
+	t.Parallel()
 	periodOffset := abi.ChainEpoch(100)
 	actor := newHarness(t, periodOffset)
 	builder := builderForHarness(actor).
@@ -3110,9 +3544,40 @@ func TestChangePeerID(t *testing.T) {
 		actor.changePeerID(rt, newPID)
 		actor.checkState(rt)
 	})
+
+	t.Run("params encoded as a tuple or a map decode alike", func(t *testing.T) {
+		params := miner.ChangePeerIDParams{NewID: tutil.MakePID("test-change-peer-id")}
+
+		// Before the map encoding version, and by default, the params are a tuple, as they were in v0.
+		var legacy, tuple, asMap bytes.Buffer
+		require.NoError(t, (&miner0.ChangePeerIDParams{NewID: params.NewID}).MarshalCBOR(&legacy))
+		require.NoError(t, params.MarshalCBORForVersion(&tuple, params.MapEncodingVersion()-1))
+		assert.Equal(t, legacy.Bytes(), tuple.Bytes())
+		tuple.Reset()
+		require.NoError(t, params.MarshalCBOR(&tuple))
+		assert.Equal(t, legacy.Bytes(), tuple.Bytes())
+
+		require.NoError(t, params.MarshalCBORForVersion(&asMap, network.Version6))
+		assert.Equal(t, append([]byte{0xa1, 0x65}, "NewID"...), asMap.Bytes()[:7])
+		assert.LessOrEqual(t, int64(asMap.Len()), params.MaxEncodedLength())
+
+		for _, encoded := range [][]byte{tuple.Bytes(), asMap.Bytes()} {
+			var decoded miner.ChangePeerIDParams
+			require.NoError(t, decoded.UnmarshalCBOR(bytes.NewReader(encoded)))
+			assert.Equal(t, params, decoded)
+		}
+
+		// A map with an unknown or repeated field is rejected.
+		var decoded miner.ChangePeerIDParams
+		unknown := append([]byte{0xa1, 0x65}, "OldID"...)
+		assert.Error(t, decoded.UnmarshalCBOR(bytes.NewReader(append(unknown, 0x40))))
+		repeated := append(append([]byte{0xa2}, asMap.Bytes()[1:]...), asMap.Bytes()[1:]...)
+		assert.Error(t, decoded.UnmarshalCBOR(bytes.NewReader(repeated)))
+	})
 }
 
 func TestCompactPartitions(t *testing.T) {
+	t.Parallel()
 	periodOffset := abi.ChainEpoch(100)
 	actor := newHarness(t, periodOffset)
 	builder := builderForHarness(actor).
@@ -3260,6 +3725,7 @@ func TestCompactPartitions(t *testing.T) {
 }
 
 func TestCheckSectorProven(t *testing.T) {
+	t.Parallel()
 	periodOffset := abi.ChainEpoch(100)
 
 	t.Run("successfully check sector is proven", func(t *testing.T) {
@@ -3290,6 +3756,7 @@ func TestCheckSectorProven(t *testing.T) {
 }
 
 func TestChangeMultiAddrs(t *testing.T) {
+	t.Parallel()
 	periodOffset := abi.ChainEpoch(100)
 
 	t.Run("successfully change multiaddrs", func(t *testing.T) {
@@ -3319,6 +3786,7 @@ func TestChangeMultiAddrs(t *testing.T) {
 }
 
 func TestChangeWorkerAddress(t *testing.T) {
+	t.Parallel()
 	periodOffset := abi.ChainEpoch(100)
 
 	setupFunc := func() (*mock.Runtime, *actorHarness) {
@@ -3527,7 +3995,7 @@ func TestChangeWorkerAddress(t *testing.T) {
 		rt.SetAddressActorType(newWorker, builtin.AccountActorCodeID)
 		key := tutil.NewIDAddr(t, 505)
 
-		rt.ExpectSend(newWorker, builtin.MethodsAccount.PubkeyAddress, nil, big.Zero(), &key, exitcode.Ok)
+		rt.ExpectSendReadOnly(newWorker, builtin.MethodsAccount.PubkeyAddress, nil, &key, exitcode.Ok)
 
 		rt.SetCaller(actor.owner, builtin.AccountActorCodeID)
 		param := &miner.ChangeWorkerAddressParams{NewWorker: newWorker}
@@ -3574,7 +4042,7 @@ func TestChangeWorkerAddress(t *testing.T) {
 		rt.SetAddressActorType(newWorker, builtin.AccountActorCodeID)
 
 		rt.ExpectValidateCallerAddr(actor.owner)
-		rt.ExpectSend(newWorker, builtin.MethodsAccount.PubkeyAddress, nil, big.Zero(), &actor.key, exitcode.Ok)
+		rt.ExpectSendReadOnly(newWorker, builtin.MethodsAccount.PubkeyAddress, nil, &actor.key, exitcode.Ok)
 
 		rt.SetCaller(actor.worker, builtin.AccountActorCodeID)
 		param := &miner.ChangeWorkerAddressParams{NewWorker: newWorker}
@@ -3587,6 +4055,7 @@ func TestChangeWorkerAddress(t *testing.T) {
 }
 
 func TestConfirmUpdateWorkerKey(t *testing.T) {
+	t.Parallel()
 	periodOffset := abi.ChainEpoch(100)
 	newWorker := tutil.NewIDAddr(t, 999)
 	currentEpoch := abi.ChainEpoch(5)
@@ -3651,6 +4120,7 @@ func TestConfirmUpdateWorkerKey(t *testing.T) {
 }
 
 func TestChangeOwnerAddress(t *testing.T) {
+	t.Parallel()
 	actor := newHarness(t, 0)
 	builder := builderForHarness(actor).
 		WithBalance(bigBalance, big.Zero())
@@ -3805,6 +4275,7 @@ func TestChangeOwnerAddress(t *testing.T) {
 }
 
 func TestReportConsensusFault(t *testing.T) {
+	t.Parallel()
 	periodOffset := abi.ChainEpoch(100)
 	actor := newHarness(t, periodOffset)
 	builder := builderForHarness(actor).
@@ -3895,6 +4366,7 @@ func TestReportConsensusFault(t *testing.T) {
 }
 
 func TestApplyRewards(t *testing.T) {
+	t.Parallel()
 	periodOffset := abi.ChainEpoch(1808)
 	actor := newHarness(t, periodOffset)
 
@@ -4085,6 +4557,7 @@ func TestApplyRewards(t *testing.T) {
 }
 
 func TestCompactSectorNumbers(t *testing.T) {
+	t.Parallel()
 	periodOffset := abi.ChainEpoch(100)
 	actor := newHarness(t, periodOffset)
 	builder := builderForHarness(actor).
@@ -4183,6 +4656,20 @@ func TestCompactSectorNumbers(t *testing.T) {
 		})
 		actor.checkState(rt)
 	})
+
+	t.Run("compacting a mask of too many runs aborts", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		var alternate []uint64
+		for i := uint64(0); i <= miner.AddressedSectorsMax+1; i++ {
+			alternate = append(alternate, 2*i)
+		}
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "invalid mask bitfield", func() {
+			actor.compactSectorNumbers(rt, bf(alternate...))
+		})
+		actor.checkState(rt)
+	})
 }
 
 type actorHarness struct {
@@ -4271,7 +4758,7 @@ func (h *actorHarness) constructAndVerify(rt *mock.Runtime) {
 
 	rt.ExpectValidateCallerAddr(builtin.InitActorAddr)
 	// Fetch worker pubkey.
-	rt.ExpectSend(h.worker, builtin.MethodsAccount.PubkeyAddress, nil, big.Zero(), &h.key, exitcode.Ok)
+	rt.ExpectSendReadOnly(h.worker, builtin.MethodsAccount.PubkeyAddress, nil, &h.key, exitcode.Ok)
 	// Register proving period cron.
 	deadlineEnd := h.periodOffset + ((rt.Epoch()-h.periodOffset)/miner.WPoStChallengeWindow)*miner.WPoStChallengeWindow
 	if rt.Epoch() >= h.periodOffset {
@@ -4417,7 +4904,7 @@ func (h *actorHarness) changeWorkerAddress(rt *mock.Runtime, newWorker addr.Addr
 	param := &miner.ChangeWorkerAddressParams{}
 	param.NewControlAddrs = newControlAddrs
 	param.NewWorker = newWorker
-	rt.ExpectSend(newWorker, builtin.MethodsAccount.PubkeyAddress, nil, big.Zero(), &h.key, exitcode.Ok)
+	rt.ExpectSendReadOnly(newWorker, builtin.MethodsAccount.PubkeyAddress, nil, &h.key, exitcode.Ok)
 
 	rt.ExpectValidateCallerAddr(h.owner)
 	rt.SetCaller(h.owner, builtin.AccountActorCodeID)
@@ -4499,7 +4986,7 @@ func (h *actorHarness) changePeerID(rt *mock.Runtime, newPID abi.PeerID) {
 
 func (h *actorHarness) controlAddresses(rt *mock.Runtime) (owner, worker addr.Address, control []addr.Address) {
 	rt.ExpectValidateCallerAny()
-	ret := rt.Call(h.a.ControlAddresses, nil).(*miner.GetControlAddressesReturn)
+	ret := rt.CallReadOnly(h.a.ControlAddresses, nil).(*miner.GetControlAddressesReturn)
 	require.NotNil(h.t, ret)
 	rt.Verify()
 	return ret.Owner, ret.Worker, ret.ControlAddrs
@@ -4557,6 +5044,35 @@ func (h *actorHarness) preCommitSector(rt *mock.Runtime, params *miner.PreCommit
 	return h.getPreCommit(rt, params.SectorNumber)
 }
 
+// Pre-commits a batch of sectors without deals in one message.
+func (h *actorHarness) preCommitSectorBatch(rt *mock.Runtime, params []*miner.PreCommitSectorParams) []*miner.SectorPreCommitOnChainInfo {
+	rt.SetCaller(h.worker, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAddr(append(h.controlAddrs, h.owner, h.worker)...)
+	expectQueryNetworkInfo(rt, h)
+
+	st := getState(rt)
+	pledgeDelta := immediatelyVestingFunds(rt, st).Neg()
+	if !pledgeDelta.IsZero() {
+		rt.ExpectSend(builtin.StoragePowerActorAddr, builtin.MethodsPower.UpdatePledgeTotal, &pledgeDelta, big.Zero(), nil, exitcode.Ok)
+	}
+	if st.FeeDebt.GreaterThan(big.Zero()) {
+		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, st.FeeDebt, nil, exitcode.Ok)
+	}
+
+	batch := &miner.PreCommitSectorBatchParams{}
+	for _, p := range params {
+		batch.Sectors = append(batch.Sectors, *p)
+	}
+	rt.Call(h.a.PreCommitSectorBatch, batch)
+	rt.Verify()
+
+	var precommits []*miner.SectorPreCommitOnChainInfo
+	for _, p := range params {
+		precommits = append(precommits, h.getPreCommit(rt, p.SectorNumber))
+	}
+	return precommits
+}
+
 // Options for proveCommitSector behaviour.
 // Default zero values should let everything be ok.
 type proveCommitConf struct {
@@ -4564,6 +5080,49 @@ type proveCommitConf struct {
 }
 
 func (h *actorHarness) proveCommitSector(rt *mock.Runtime, precommit *miner.SectorPreCommitOnChainInfo, params *miner.ProveCommitSectorParams) {
+	seal := h.expectProveCommitVerifyInfo(rt, precommit, params)
+	rt.ExpectSend(builtin.StoragePowerActorAddr, builtin.MethodsPower.SubmitPoRepForBulkVerify, &seal, abi.NewTokenAmount(0), nil, exitcode.Ok)
+	rt.SetCaller(h.worker, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAny()
+	rt.Call(h.a.ProveCommitSector, params)
+	rt.Verify()
+}
+
+// Prove-commits a batch of sectors, with proofs verified immediately, expecting those with valid proofs and deals
+// to be activated, and returned as activated.
+func (h *actorHarness) proveCommitSectors(rt *mock.Runtime, conf proveCommitConf, precommits []*miner.SectorPreCommitOnChainInfo, valid []bool) {
+	params := &miner.ProveCommitSectorsParams{}
+	var seals []proof.SealVerifyInfo
+	var validPrecommits []*miner.SectorPreCommitOnChainInfo
+	var activated []uint64
+	for i, precommit := range precommits {
+		proveCommit := makeProveCommit(precommit.Info.SectorNumber)
+		params.Sectors = append(params.Sectors, *proveCommit)
+		seals = append(seals, h.expectProveCommitVerifyInfo(rt, precommit, proveCommit))
+		if valid[i] {
+			validPrecommits = append(validPrecommits, precommit)
+			if exit, found := conf.verifyDealsExit[precommit.Info.SectorNumber]; !found || exit == exitcode.Ok {
+				activated = append(activated, uint64(precommit.Info.SectorNumber))
+			}
+		}
+	}
+	rt.ExpectBatchVerifySeals(map[addr.Address][]proof.SealVerifyInfo{h.receiver: seals}, map[addr.Address][]bool{h.receiver: valid}, nil)
+	if len(validPrecommits) > 0 {
+		h.expectActivateSectors(rt, conf, validPrecommits...)
+	}
+
+	rt.SetCaller(h.worker, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.a.ProveCommitSectors, params).(*miner.ProveCommitSectorsReturn)
+	rt.Verify()
+
+	sectors, err := ret.Activated.All(miner.AddressedSectorsMax)
+	require.NoError(h.t, err)
+	assert.Equal(h.t, activated, sectors)
+}
+
+// Sets expectations for gathering the information to verify a prove-commit, and returns that information.
+func (h *actorHarness) expectProveCommitVerifyInfo(rt *mock.Runtime, precommit *miner.SectorPreCommitOnChainInfo, params *miner.ProveCommitSectorParams) proof.SealVerifyInfo {
 	commd := cbg.CborCid(tutil.MakeCID("commd", &market.PieceCIDPrefix))
 	sealRand := abi.SealRandomness([]byte{1, 2, 3, 4})
 	sealIntRand := abi.InteractiveSealRandomness([]byte{5, 6, 7, 8})
@@ -4582,8 +5141,8 @@ func (h *actorHarness) proveCommitSector(rt *mock.Runtime, precommit *miner.Sect
 		receiver := rt.Receiver()
 		err := receiver.MarshalCBOR(&buf)
 		require.NoError(h.t, err)
-		rt.ExpectGetRandomnessTickets(crypto.DomainSeparationTag_SealRandomness, precommit.Info.SealRandEpoch, buf.Bytes(), abi.Randomness(sealRand))
-		rt.ExpectGetRandomnessBeacon(crypto.DomainSeparationTag_InteractiveSealChallengeSeed, interactiveEpoch, buf.Bytes(), abi.Randomness(sealIntRand))
+		rt.ExpectGetRandomness(runtime.RandomnessTickets, crypto.DomainSeparationTag_SealRandomness, precommit.Info.SealRandEpoch, buf.Bytes(), abi.Randomness(sealRand))
+		rt.ExpectGetRandomness(runtime.RandomnessBeacon, crypto.DomainSeparationTag_InteractiveSealChallengeSeed, interactiveEpoch, buf.Bytes(), abi.Randomness(sealIntRand))
 	}
 	{
 		actorId, err := addr.IDFromAddress(h.receiver)
@@ -4601,15 +5160,21 @@ func (h *actorHarness) proveCommitSector(rt *mock.Runtime, precommit *miner.Sect
 			InteractiveRandomness: sealIntRand,
 			UnsealedCID:           cid.Cid(commd),
 		}
-		rt.ExpectSend(builtin.StoragePowerActorAddr, builtin.MethodsPower.SubmitPoRepForBulkVerify, &seal, abi.NewTokenAmount(0), nil, exitcode.Ok)
+		return seal
 	}
-	rt.SetCaller(h.worker, builtin.AccountActorCodeID)
-	rt.ExpectValidateCallerAny()
-	rt.Call(h.a.ProveCommitSector, params)
-	rt.Verify()
 }
 
 func (h *actorHarness) confirmSectorProofsValid(rt *mock.Runtime, conf proveCommitConf, precommits ...*miner.SectorPreCommitOnChainInfo) {
+	allSectorNumbers := h.expectActivateSectors(rt, conf, precommits...)
+
+	rt.SetCaller(builtin.StoragePowerActorAddr, builtin.StoragePowerActorCodeID)
+	rt.ExpectValidateCallerAddr(builtin.StoragePowerActorAddr)
+	rt.Call(h.a.ConfirmSectorProofsValid, &builtin.ConfirmSectorProofsParams{Sectors: allSectorNumbers})
+	rt.Verify()
+}
+
+// Sets expectations for activation of proven sectors, returning their sector numbers.
+func (h *actorHarness) expectActivateSectors(rt *mock.Runtime, conf proveCommitConf, precommits ...*miner.SectorPreCommitOnChainInfo) []abi.SectorNumber {
 	// expect calls to get network stats
 	expectQueryNetworkInfo(rt, h)
 
@@ -4665,11 +5230,7 @@ func (h *actorHarness) confirmSectorProofsValid(rt *mock.Runtime, conf proveComm
 			rt.ExpectSend(builtin.StoragePowerActorAddr, builtin.MethodsPower.UpdatePledgeTotal, &expectPledge, big.Zero(), nil, exitcode.Ok)
 		}
 	}
-
-	rt.SetCaller(builtin.StoragePowerActorAddr, builtin.StoragePowerActorCodeID)
-	rt.ExpectValidateCallerAddr(builtin.StoragePowerActorAddr)
-	rt.Call(h.a.ConfirmSectorProofsValid, &builtin.ConfirmSectorProofsParams{Sectors: allSectorNumbers})
-	rt.Verify()
+	return allSectorNumbers
 }
 
 func (h *actorHarness) proveCommitSectorAndConfirm(rt *mock.Runtime, precommit *miner.SectorPreCommitOnChainInfo,
@@ -4809,7 +5370,7 @@ type poStConfig struct {
 func (h *actorHarness) submitWindowPoSt(rt *mock.Runtime, deadline *dline.Info, partitions []miner.PoStPartition, infos []*miner.SectorOnChainInfo, poStCfg *poStConfig) {
 	rt.SetCaller(h.worker, builtin.AccountActorCodeID)
 	commitRand := abi.Randomness("chaincommitment")
-	rt.ExpectGetRandomnessTickets(crypto.DomainSeparationTag_PoStChainCommit, deadline.Challenge, nil, commitRand)
+	rt.ExpectGetRandomness(runtime.RandomnessTickets, crypto.DomainSeparationTag_PoStChainCommit, deadline.Challenge, nil, commitRand)
 
 	rt.ExpectValidateCallerAddr(append(h.controlAddrs, h.owner, h.worker)...)
 
@@ -4845,7 +5406,7 @@ func (h *actorHarness) submitWindowPoSt(rt *mock.Runtime, deadline *dline.Info,
 		err := receiver.MarshalCBOR(&buf)
 		require.NoError(h.t, err)
 
-		rt.ExpectGetRandomnessBeacon(crypto.DomainSeparationTag_WindowedPoStChallengeSeed, deadline.Challenge, buf.Bytes(), abi.Randomness(challengeRand))
+		rt.ExpectGetRandomness(runtime.RandomnessBeacon, crypto.DomainSeparationTag_WindowedPoStChallengeSeed, deadline.Challenge, buf.Bytes(), abi.Randomness(challengeRand))
 
 		actorId, err := addr.IDFromAddress(h.receiver)
 		require.NoError(h.t, err)
@@ -4898,6 +5459,89 @@ func (h *actorHarness) submitWindowPoSt(rt *mock.Runtime, deadline *dline.Info,
 	rt.Verify()
 }
 
+// Submits a batch of PoSts, one per set of partitions, for partitions without faults or skipped sectors.
+// The batch verification returns verified, and a power update is expected only if poStCfg is given.
+func (h *actorHarness) submitWindowPoStBatch(rt *mock.Runtime, deadline *dline.Info, posts [][]miner.PoStPartition,
+	infos []*miner.SectorOnChainInfo, poStCfg *poStConfig, verified []bool) {
+	rt.SetCaller(h.worker, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAddr(append(h.controlAddrs, h.owner, h.worker)...)
+
+	commitRand := abi.Randomness("chaincommitment")
+	challengeRand := abi.SealRandomness([]byte{10, 11, 12, 13})
+	proofs := makePoStProofs(h.postProofType)
+	var buf bytes.Buffer
+	receiver := rt.Receiver()
+	require.NoError(h.t, receiver.MarshalCBOR(&buf))
+	actorId, err := addr.IDFromAddress(h.receiver)
+	require.NoError(h.t, err)
+
+	params := miner.SubmitWindowedPoStBatchParams{}
+	var vis []proof.WindowPoStVerifyInfo
+	for _, partitions := range posts {
+		rt.ExpectGetRandomness(runtime.RandomnessTickets, crypto.DomainSeparationTag_PoStChainCommit, deadline.Challenge, nil, commitRand)
+		rt.ExpectGetRandomness(runtime.RandomnessBeacon, crypto.DomainSeparationTag_WindowedPoStChallengeSeed, deadline.Challenge, buf.Bytes(), abi.Randomness(challengeRand))
+
+		var indices []uint64
+		for _, p := range partitions {
+			indices = append(indices, p.Index)
+		}
+		var sectorInfos []proof.SectorInfo
+		for _, si := range h.partitionSectors(rt, deadline.Index, infos, indices...) {
+			sectorInfos = append(sectorInfos, proof.SectorInfo{
+				SealProof:    si.SealProof,
+				SectorNumber: si.SectorNumber,
+				SealedCID:    si.SealedCID,
+			})
+		}
+		vis = append(vis, proof.WindowPoStVerifyInfo{
+			Randomness:        abi.PoStRandomness(challengeRand),
+			Proofs:            proofs,
+			ChallengedSectors: sectorInfos,
+			Prover:            abi.ActorID(actorId),
+		})
+		params.Posts = append(params.Posts, miner.SubmitWindowedPoStParams{
+			Deadline:         deadline.Index,
+			Partitions:       partitions,
+			Proofs:           proofs,
+			ChainCommitEpoch: deadline.Challenge,
+			ChainCommitRand:  commitRand,
+		})
+	}
+	rt.ExpectBatchVerifyPoSts(vis, verified, nil)
+
+	if poStCfg != nil && !poStCfg.expectedPowerDelta.IsZero() {
+		claim := &power.UpdateClaimedPowerParams{
+			RawByteDelta:         poStCfg.expectedPowerDelta.Raw,
+			QualityAdjustedDelta: poStCfg.expectedPowerDelta.QA,
+		}
+		rt.ExpectSend(builtin.StoragePowerActorAddr, builtin.MethodsPower.UpdateClaimedPower, claim, abi.NewTokenAmount(0),
+			nil, exitcode.Ok)
+	}
+
+	rt.Call(h.a.SubmitWindowedPoStBatch, &params)
+	rt.Verify()
+}
+
+// Returns the infos of the sectors in partitions of a deadline, in order of sector number.
+func (h *actorHarness) partitionSectors(rt *mock.Runtime, dlIdx uint64, infos []*miner.SectorOnChainInfo, partitions ...uint64) []*miner.SectorOnChainInfo {
+	dl := h.getDeadline(rt, dlIdx)
+	var numbers []bitfield.BitField
+	for _, pIdx := range partitions {
+		numbers = append(numbers, h.getPartition(rt, dl, pIdx).Sectors)
+	}
+	all, err := bitfield.MultiMerge(numbers...)
+	require.NoError(h.t, err)
+	var selected []*miner.SectorOnChainInfo
+	for _, info := range infos {
+		set, err := all.IsSet(uint64(info.SectorNumber))
+		require.NoError(h.t, err)
+		if set {
+			selected = append(selected, info)
+		}
+	}
+	return selected
+}
+
 func (h *actorHarness) declareFaults(rt *mock.Runtime, faultSectorInfos ...*miner.SectorOnChainInfo) miner.PowerPair {
 	rt.SetCaller(h.worker, builtin.AccountActorCodeID)
 	rt.ExpectValidateCallerAddr(append(h.controlAddrs, h.owner, h.worker)...)
@@ -4922,6 +5566,12 @@ func (h *actorHarness) declareFaults(rt *mock.Runtime, faultSectorInfos ...*mine
 		exitcode.Ok,
 	)
 
+	var faultedSectors []uint64
+	for _, info := range faultSectorInfos {
+		faultedSectors = append(faultedSectors, uint64(info.SectorNumber))
+	}
+	rt.ExpectEvent(builtin.EventKeySectorsFaulted, &builtin.SectorsFaultedEvent{Sectors: bitfield.NewFromSet(faultedSectors)})
+
 	// Calculate params from faulted sector infos
 	st := getState(rt)
 	params := makeFaultParamsFromFaultingSectors(h.t, st, rt.AdtStore(), faultSectorInfos)
@@ -5080,7 +5730,7 @@ func (h *actorHarness) reportConsensusFault(rt *mock.Runtime, from addr.Address,
 		ThisEpochBaselinePower:  h.baselinePower,
 		ThisEpochRewardSmoothed: h.epochRewardSmooth,
 	}
-	rt.ExpectSend(builtin.RewardActorAddr, builtin.MethodsReward.ThisEpochReward, nil, big.Zero(), &currentReward, exitcode.Ok)
+	rt.ExpectSendReadOnly(builtin.RewardActorAddr, builtin.MethodsReward.ThisEpochReward, nil, &currentReward, exitcode.Ok)
 
 	penaltyTotal := miner.ConsensusFaultPenalty(h.epochRewardSmooth.Estimate())
 	// slash reward
@@ -5146,9 +5796,9 @@ func (h *actorHarness) onDeadlineCron(rt *mock.Runtime, config *cronConfig) {
 		ThisEpochBaselinePower:  h.baselinePower,
 		ThisEpochRewardSmoothed: h.epochRewardSmooth,
 	}
-	rt.ExpectSend(builtin.RewardActorAddr, builtin.MethodsReward.ThisEpochReward, nil, big.Zero(), &rwd, exitcode.Ok)
+	rt.ExpectSendReadOnly(builtin.RewardActorAddr, builtin.MethodsReward.ThisEpochReward, nil, &rwd, exitcode.Ok)
 	networkPower := big.NewIntUnsigned(1 << 50)
-	rt.ExpectSend(builtin.StoragePowerActorAddr, builtin.MethodsPower.CurrentTotalPower, nil, big.Zero(),
+	rt.ExpectSendReadOnly(builtin.StoragePowerActorAddr, builtin.MethodsPower.CurrentTotalPower, nil,
 		&power.CurrentTotalPowerReturn{
 			RawBytePower:            networkPower,
 			QualityAdjPower:         networkPower,
@@ -5448,7 +6098,7 @@ func immediatelyVestingFunds(rt *mock.Runtime, st *miner.State) big.Int {
 //
 
 func builderForHarness(actor *actorHarness) *mock.RuntimeBuilder {
-	rb := mock.NewBuilder(context.Background(), actor.receiver).
+	rb := mock.NewBuilder(context.Background(), actor.receiver).WithStateInvariants(miner.CheckStateInvariants).
 		WithActorType(actor.owner, builtin.AccountActorCodeID).
 		WithActorType(actor.worker, builtin.AccountActorCodeID).
 		WithHasher(fixedHasher(uint64(actor.periodOffset)))
@@ -5574,20 +6224,18 @@ func expectQueryNetworkInfo(rt *mock.Runtime, h *actorHarness) {
 		ThisEpochRewardSmoothed: h.epochRewardSmooth,
 	}
 
-	rt.ExpectSend(
+	rt.ExpectSendReadOnly(
 		builtin.RewardActorAddr,
 		builtin.MethodsReward.ThisEpochReward,
 		nil,
-		big.Zero(),
 		&currentReward,
 		exitcode.Ok,
 	)
 
-	rt.ExpectSend(
+	rt.ExpectSendReadOnly(
 		builtin.StoragePowerActorAddr,
 		builtin.MethodsPower.CurrentTotalPower,
 		nil,
-		big.Zero(),
 		&currentPower,
 		exitcode.Ok,
 	)
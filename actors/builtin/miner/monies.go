@@ -40,7 +40,7 @@ var InitialPledgeLockTarget = builtin.BigFrac{
 // FF = BR(t, ContinuedFaultProjectionPeriod)
 var ContinuedFaultFactorNum = 351 // PARAM_SPEC
 var ContinuedFaultFactorDenom = 100
-var ContinuedFaultProjectionPeriod = abi.ChainEpoch((builtin.EpochsInDay * ContinuedFaultFactorNum) / ContinuedFaultFactorDenom)
+var ContinuedFaultProjectionPeriod = (builtin.EpochsInDay * abi.ChainEpoch(ContinuedFaultFactorNum)) / abi.ChainEpoch(ContinuedFaultFactorDenom)
 
 var TerminationPenaltyLowerBoundProjectionPeriod = abi.ChainEpoch((builtin.EpochsInDay * 35) / 10) // PARAM_SPEC
 
@@ -70,10 +70,8 @@ func ExpectedRewardForPower(rewardEstimate, networkQAPowerEstimate smoothing.Fil
 	if networkQAPowerSmoothed.IsZero() {
 		return rewardEstimate.Estimate()
 	}
-	expectedRewardForProvingPeriod := smoothing.ExtrapolatedCumSumOfRatio(projectionDuration, 0, rewardEstimate, networkQAPowerEstimate)
-	br128 := big.Mul(qaSectorPower, expectedRewardForProvingPeriod) // Q.0 * Q.128 => Q.128
-	br := big.Rsh(br128, math.Precision128)
-	return big.Max(br, big.Zero()) // negative BR is clamped at 0
+	br := rewardEstimate.DivideBy(networkQAPowerEstimate).ExtrapolatedCumSumTimes(qaSectorPower, projectionDuration, 0)
+	return math.Max(br, big.Zero()) // negative BR is clamped at 0
 }
 
 // The penalty for a sector continuing faulty for another proving period.
@@ -116,7 +114,7 @@ func PledgePenaltyForTermination(dayReward abi.TokenAmount, sectorAge abi.ChainE
 			twentyDayRewardAtActivation,
 			big.Div(
 				penalizedReward,
-				big.Mul(big.NewInt(builtin.EpochsInDay), TerminationRewardFactor.Denominator)))) // (epochs*AttoFIL/day -> AttoFIL)
+				big.Mul(big.NewInt(int64(builtin.EpochsInDay)), TerminationRewardFactor.Denominator)))) // (epochs*AttoFIL/day -> AttoFIL)
 }
 
 // Computes the PreCommit deposit given sector qa weight and current network conditions.
@@ -143,7 +141,7 @@ func InitialPledgeForPower(qaPower, baselinePower abi.StoragePower, rewardEstima
 	lockTargetDenom := InitialPledgeLockTarget.Denominator
 	pledgeShareNum := qaPower
 	networkQAPower := networkQAPowerEstimate.Estimate()
-	pledgeShareDenom := big.Max(big.Max(networkQAPower, baselinePower), qaPower) // use qaPower in case others are 0
+	pledgeShareDenom := math.Max(networkQAPower, baselinePower, qaPower) // use qaPower in case others are 0
 	additionalIPNum := big.Mul(lockTargetNum, pledgeShareNum)
 	additionalIPDenom := big.Mul(lockTargetDenom, pledgeShareDenom)
 	additionalIP := big.Div(additionalIPNum, additionalIPDenom)
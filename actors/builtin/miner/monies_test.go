@@ -43,7 +43,7 @@ func TestPledgePenaltyForTermination(t *testing.T) {
 		dayReward := big.Div(initialPledge, bigInitialPledgeFactor)
 		twentyDayReward := big.Mul(dayReward, bigInitialPledgeFactor)
 		sectorAgeInDays := int64(20)
-		sectorAge := abi.ChainEpoch(sectorAgeInDays * builtin.EpochsInDay)
+		sectorAge := abi.ChainEpoch(sectorAgeInDays) * builtin.EpochsInDay
 
 		fee := miner.PledgePenaltyForTermination(dayReward, sectorAge, twentyDayReward, powerEstimate, qaSectorPower, rewardEstimate, big.Zero(), 0)
 
@@ -120,9 +120,9 @@ func TestPledgePenaltyForTermination(t *testing.T) {
 		oldDayReward := big.Mul(big.NewInt(2), dayReward)
 		twentyDayReward := big.Mul(dayReward, bigInitialPledgeFactor)
 		oldSectorAgeInDays := int64(20)
-		oldSectorAge := abi.ChainEpoch(oldSectorAgeInDays * builtin.EpochsInDay)
+		oldSectorAge := abi.ChainEpoch(oldSectorAgeInDays) * builtin.EpochsInDay
 		replacementAgeInDays := int64(15)
-		replacementAge := abi.ChainEpoch(replacementAgeInDays * builtin.EpochsInDay)
+		replacementAge := abi.ChainEpoch(replacementAgeInDays) * builtin.EpochsInDay
 
 		// use low power, so termination fee exceeds SP
 		power := big.NewInt(1)
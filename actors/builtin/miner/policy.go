@@ -2,6 +2,7 @@ package miner
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
@@ -14,12 +15,12 @@ import (
 // The period over which a miner's active sectors are expected to be proven via WindowPoSt.
 // This guarantees that (1) user data is proven daily, (2) user data is stored for 24h by a rational miner
 // (due to Window PoSt cost assumption).
-var WPoStProvingPeriod = abi.ChainEpoch(builtin.EpochsInDay) // 24 hours PARAM_SPEC
+var WPoStProvingPeriod = builtin.EpochsInDay // 24 hours PARAM_SPEC
 
 // The period between the opening and the closing of a WindowPoSt deadline in which the miner is expected to
 // provide a Window PoSt proof.
 // This provides a miner enough time to compute and propagate a Window PoSt proof.
-var WPoStChallengeWindow = abi.ChainEpoch(30 * 60 / builtin.EpochDurationSeconds) // 30 minutes (48 per day) PARAM_SPEC
+var WPoStChallengeWindow = builtin.EpochsIn(30 * time.Minute) // 30 minutes (48 per day) PARAM_SPEC
 
 // The number of non-overlapping PoSt deadlines in a proving period.
 // This spreads a miner's Window PoSt work across a proving period.
@@ -69,6 +70,16 @@ const (
 const MaxProveCommitSizeV4 = 1024
 const MaxProveCommitSizeV5 = 10240
 
+// Maximum number of sectors which may be prove-committed together in a single batch,
+// with their proofs verified immediately rather than deferred to the power actor.
+const MaxProveCommitSectorsBatchSize = 32 // PARAM_SPEC
+
+// Maximum number of sectors which may be pre-committed together in a single batch.
+const MaxPreCommitSectorBatchSize = 256 // PARAM_SPEC
+
+// Maximum number of Window PoSts which may be submitted together in a single batch.
+const MaxWindowPoStBatchSize = 8 // PARAM_SPEC
+
 // Maximum number of control addresses a miner may register.
 const MaxControlAddresses = 10
 
@@ -121,13 +132,13 @@ var PreCommitChallengeDelay = abi.ChainEpoch(150) // PARAM_SPEC
 // This means that deadline windows can be non-overlapping (which make the programming simpler) without requiring a
 // miner to wait for chain stability during the challenge window.
 // This value cannot be too large lest it compromise the rationality of honest storage (from Window PoSt cost assumptions).
-const WPoStChallengeLookback = abi.ChainEpoch(20) // PARAM_SPEC
+var WPoStChallengeLookback = abi.ChainEpoch(20) // PARAM_SPEC
 
 // Minimum period between fault declaration and the next deadline opening.
 // If the number of epochs between fault declaration and deadline's challenge window opening is lower than FaultDeclarationCutoff,
 // the fault declaration is considered invalid for that deadline.
 // This guarantees that a miner is not likely to successfully fork the chain and declare a fault after seeing the challenges.
-const FaultDeclarationCutoff = WPoStChallengeLookback + 50 // PARAM_SPEC
+var FaultDeclarationCutoff = WPoStChallengeLookback + 50 // PARAM_SPEC
 
 // The maximum age of a fault before the sector is terminated.
 // This bounds the time a miner can lose client's data before sacrificing pledge and deal collateral.
@@ -138,12 +149,12 @@ var FaultMaxAge = WPoStProvingPeriod * 14 // PARAM_SPEC
 const WorkerKeyChangeDelay = ChainFinality // PARAM_SPEC
 
 // Minimum number of epochs past the current epoch a sector may be set to expire.
-const MinSectorExpiration = 180 * builtin.EpochsInDay // PARAM_SPEC
+var MinSectorExpiration = abi.ChainEpoch(180 * builtin.EpochsInDay) // PARAM_SPEC
 
 // The maximum number of epochs past the current epoch that sector lifetime may be extended.
 // A sector may be extended multiple times, however, the total maximum lifetime is also bounded by
 // the associated seal proof's maximum lifetime.
-const MaxSectorExpirationExtension = 540 * builtin.EpochsInDay // PARAM_SPEC
+var MaxSectorExpirationExtension = abi.ChainEpoch(540 * builtin.EpochsInDay) // PARAM_SPEC
 
 // Ratio of sector size to maximum number of deals per sector.
 // The maximum number of deals is the sector size divided by this number (2^27)
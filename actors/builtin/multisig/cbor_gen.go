@@ -9,6 +9,7 @@ import (
 	address "github.com/filecoin-project/go-address"
 	abi "github.com/filecoin-project/go-state-types/abi"
 	multisig "github.com/filecoin-project/specs-actors/actors/builtin/multisig"
+	acbor "github.com/filecoin-project/specs-actors/v2/actors/util/cbor"
 	cbg "github.com/whyrusleeping/cbor-gen"
 	xerrors "golang.org/x/xerrors"
 )
@@ -103,29 +104,29 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "multisig.State", "")
 	}
 	if maj != cbg.MajArray {
-		return fmt.Errorf("cbor input should be of type array")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "multisig.State", "")
 	}
 
 	if extra != 7 {
-		return fmt.Errorf("cbor input had wrong number of fields")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "multisig.State", "")
 	}
 
 	// t.Signers ([]address.Address) (slice)
 
 	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "multisig.State", "Signers")
 	}
 
 	if extra > cbg.MaxLength {
-		return fmt.Errorf("t.Signers: array too large (%d)", extra)
+		return acbor.WrapDecodeError(fmt.Errorf("t.Signers: array too large (%d)", extra), "multisig.State", "Signers")
 	}
 
 	if maj != cbg.MajArray {
-		return fmt.Errorf("expected cbor array")
+		return acbor.WrapDecodeError(fmt.Errorf("expected cbor array"), "multisig.State", "Signers")
 	}
 
 	if extra > 0 {
@@ -136,7 +137,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 		var v address.Address
 		if err := v.UnmarshalCBOR(br); err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "multisig.State", fmt.Sprintf("Signers[%d]", i))
 		}
 
 		t.Signers[i] = v
@@ -148,10 +149,10 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "multisig.State", "NumApprovalsThreshold")
 		}
 		if maj != cbg.MajUnsignedInt {
-			return fmt.Errorf("wrong type for uint64 field")
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "multisig.State", "NumApprovalsThreshold")
 		}
 		t.NumApprovalsThreshold = uint64(extra)
 
@@ -161,22 +162,22 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 		var extraI int64
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "multisig.State", "NextTxnID")
 		}
 		switch maj {
 		case cbg.MajUnsignedInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 positive overflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "multisig.State", "NextTxnID")
 			}
 		case cbg.MajNegativeInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 negative oveflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "multisig.State", "NextTxnID")
 			}
 			extraI = -1 - extraI
 		default:
-			return fmt.Errorf("wrong type for int64 field: %d", maj)
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "multisig.State", "NextTxnID")
 		}
 
 		t.NextTxnID = multisig.TxnID(extraI)
@@ -186,7 +187,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.InitialBalance.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.InitialBalance: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.InitialBalance: %w", err), "multisig.State", "InitialBalance")
 		}
 
 	}
@@ -195,22 +196,22 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 		var extraI int64
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "multisig.State", "StartEpoch")
 		}
 		switch maj {
 		case cbg.MajUnsignedInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 positive overflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "multisig.State", "StartEpoch")
 			}
 		case cbg.MajNegativeInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 negative oveflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "multisig.State", "StartEpoch")
 			}
 			extraI = -1 - extraI
 		default:
-			return fmt.Errorf("wrong type for int64 field: %d", maj)
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "multisig.State", "StartEpoch")
 		}
 
 		t.StartEpoch = abi.ChainEpoch(extraI)
@@ -220,22 +221,22 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 		var extraI int64
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "multisig.State", "UnlockDuration")
 		}
 		switch maj {
 		case cbg.MajUnsignedInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 positive overflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "multisig.State", "UnlockDuration")
 			}
 		case cbg.MajNegativeInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 negative oveflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "multisig.State", "UnlockDuration")
 			}
 			extraI = -1 - extraI
 		default:
-			return fmt.Errorf("wrong type for int64 field: %d", maj)
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "multisig.State", "UnlockDuration")
 		}
 
 		t.UnlockDuration = abi.ChainEpoch(extraI)
@@ -246,7 +247,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 		c, err := cbg.ReadCid(br)
 		if err != nil {
-			return xerrors.Errorf("failed to read cid field t.PendingTxns: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read cid field t.PendingTxns: %w", err), "multisig.State", "PendingTxns")
 		}
 
 		t.PendingTxns = c
@@ -255,6 +256,207 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 	return nil
 }
 
+var lengthBufTransaction = []byte{134}
+
+func (t *Transaction) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufTransaction); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.To (address.Address) (struct)
+	if err := t.To.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Value (big.Int) (struct)
+	if err := t.Value.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Method (abi.MethodNum) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Method)); err != nil {
+		return err
+	}
+
+	// t.Params ([]uint8) (slice)
+	if len(t.Params) > cbg.ByteArrayMaxLen {
+		return xerrors.Errorf("Byte array in field t.Params was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajByteString, uint64(len(t.Params))); err != nil {
+		return err
+	}
+
+	if _, err := w.Write(t.Params[:]); err != nil {
+		return err
+	}
+
+	// t.Approved ([]address.Address) (slice)
+	if len(t.Approved) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Approved was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Approved))); err != nil {
+		return err
+	}
+	for _, v := range t.Approved {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+
+	// t.Expiration (abi.ChainEpoch) (int64)
+	if t.Expiration >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Expiration)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.Expiration-1)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *Transaction) UnmarshalCBOR(r io.Reader) error {
+	*t = Transaction{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "multisig.Transaction", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "multisig.Transaction", "")
+	}
+
+	fieldCount := extra
+	if fieldCount < 5 || fieldCount > 6 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "multisig.Transaction", "")
+	}
+
+	// t.To (address.Address) (struct)
+
+	{
+
+		if err := t.To.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.To: %w", err), "multisig.Transaction", "To")
+		}
+
+	}
+	// t.Value (big.Int) (struct)
+
+	{
+
+		if err := t.Value.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Value: %w", err), "multisig.Transaction", "Value")
+		}
+
+	}
+	// t.Method (abi.MethodNum) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return acbor.WrapDecodeError(err, "multisig.Transaction", "Method")
+		}
+		if maj != cbg.MajUnsignedInt {
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "multisig.Transaction", "Method")
+		}
+		t.Method = abi.MethodNum(extra)
+
+	}
+	// t.Params ([]uint8) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "multisig.Transaction", "Params")
+	}
+
+	if extra > cbg.ByteArrayMaxLen {
+		return acbor.WrapDecodeError(fmt.Errorf("t.Params: byte array too large (%d)", extra), "multisig.Transaction", "Params")
+	}
+	if maj != cbg.MajByteString {
+		return acbor.WrapDecodeError(fmt.Errorf("expected byte array"), "multisig.Transaction", "Params")
+	}
+
+	if extra > 0 {
+		t.Params = make([]uint8, extra)
+	}
+
+	if _, err := io.ReadFull(br, t.Params[:]); err != nil {
+		return acbor.WrapDecodeError(err, "multisig.Transaction", "Params")
+	}
+	// t.Approved ([]address.Address) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "multisig.Transaction", "Approved")
+	}
+
+	if extra > cbg.MaxLength {
+		return acbor.WrapDecodeError(fmt.Errorf("t.Approved: array too large (%d)", extra), "multisig.Transaction", "Approved")
+	}
+
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("expected cbor array"), "multisig.Transaction", "Approved")
+	}
+
+	if extra > 0 {
+		t.Approved = make([]address.Address, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v address.Address
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(err, "multisig.Transaction", fmt.Sprintf("Approved[%d]", i))
+		}
+
+		t.Approved[i] = v
+	}
+
+	if fieldCount <= 5 {
+		return nil
+	}
+	// t.Expiration (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return acbor.WrapDecodeError(err, "multisig.Transaction", "Expiration")
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "multisig.Transaction", "Expiration")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "multisig.Transaction", "Expiration")
+			}
+			extraI = -1 - extraI
+		default:
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "multisig.Transaction", "Expiration")
+		}
+
+		t.Expiration = abi.ChainEpoch(extraI)
+	}
+	return nil
+}
+
 var lengthBufConstructorParams = []byte{132}
 
 func (t *ConstructorParams) MarshalCBOR(w io.Writer) error {
@@ -320,29 +522,29 @@ func (t *ConstructorParams) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "multisig.ConstructorParams", "")
 	}
 	if maj != cbg.MajArray {
-		return fmt.Errorf("cbor input should be of type array")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "multisig.ConstructorParams", "")
 	}
 
 	if extra != 4 {
-		return fmt.Errorf("cbor input had wrong number of fields")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "multisig.ConstructorParams", "")
 	}
 
 	// t.Signers ([]address.Address) (slice)
 
 	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "multisig.ConstructorParams", "Signers")
 	}
 
 	if extra > cbg.MaxLength {
-		return fmt.Errorf("t.Signers: array too large (%d)", extra)
+		return acbor.WrapDecodeError(fmt.Errorf("t.Signers: array too large (%d)", extra), "multisig.ConstructorParams", "Signers")
 	}
 
 	if maj != cbg.MajArray {
-		return fmt.Errorf("expected cbor array")
+		return acbor.WrapDecodeError(fmt.Errorf("expected cbor array"), "multisig.ConstructorParams", "Signers")
 	}
 
 	if extra > 0 {
@@ -353,7 +555,7 @@ func (t *ConstructorParams) UnmarshalCBOR(r io.Reader) error {
 
 		var v address.Address
 		if err := v.UnmarshalCBOR(br); err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "multisig.ConstructorParams", fmt.Sprintf("Signers[%d]", i))
 		}
 
 		t.Signers[i] = v
@@ -365,10 +567,10 @@ func (t *ConstructorParams) UnmarshalCBOR(r io.Reader) error {
 
 		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "multisig.ConstructorParams", "NumApprovalsThreshold")
 		}
 		if maj != cbg.MajUnsignedInt {
-			return fmt.Errorf("wrong type for uint64 field")
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "multisig.ConstructorParams", "NumApprovalsThreshold")
 		}
 		t.NumApprovalsThreshold = uint64(extra)
 
@@ -378,22 +580,22 @@ func (t *ConstructorParams) UnmarshalCBOR(r io.Reader) error {
 		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 		var extraI int64
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "multisig.ConstructorParams", "UnlockDuration")
 		}
 		switch maj {
 		case cbg.MajUnsignedInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 positive overflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "multisig.ConstructorParams", "UnlockDuration")
 			}
 		case cbg.MajNegativeInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 negative oveflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "multisig.ConstructorParams", "UnlockDuration")
 			}
 			extraI = -1 - extraI
 		default:
-			return fmt.Errorf("wrong type for int64 field: %d", maj)
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "multisig.ConstructorParams", "UnlockDuration")
 		}
 
 		t.UnlockDuration = abi.ChainEpoch(extraI)
@@ -403,25 +605,376 @@ func (t *ConstructorParams) UnmarshalCBOR(r io.Reader) error {
 		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 		var extraI int64
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "multisig.ConstructorParams", "StartEpoch")
 		}
 		switch maj {
 		case cbg.MajUnsignedInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 positive overflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "multisig.ConstructorParams", "StartEpoch")
 			}
 		case cbg.MajNegativeInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 negative oveflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "multisig.ConstructorParams", "StartEpoch")
 			}
 			extraI = -1 - extraI
 		default:
-			return fmt.Errorf("wrong type for int64 field: %d", maj)
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "multisig.ConstructorParams", "StartEpoch")
 		}
 
 		t.StartEpoch = abi.ChainEpoch(extraI)
 	}
 	return nil
 }
+
+var lengthBufProposeParams = []byte{133}
+
+func (t *ProposeParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufProposeParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.To (address.Address) (struct)
+	if err := t.To.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Value (big.Int) (struct)
+	if err := t.Value.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Method (abi.MethodNum) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Method)); err != nil {
+		return err
+	}
+
+	// t.Params ([]uint8) (slice)
+	if len(t.Params) > cbg.ByteArrayMaxLen {
+		return xerrors.Errorf("Byte array in field t.Params was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajByteString, uint64(len(t.Params))); err != nil {
+		return err
+	}
+
+	if _, err := w.Write(t.Params[:]); err != nil {
+		return err
+	}
+
+	// t.Expiration (abi.ChainEpoch) (int64)
+	if t.Expiration >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Expiration)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.Expiration-1)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *ProposeParams) UnmarshalCBOR(r io.Reader) error {
+	*t = ProposeParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "multisig.ProposeParams", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "multisig.ProposeParams", "")
+	}
+
+	fieldCount := extra
+	if fieldCount < 4 || fieldCount > 5 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "multisig.ProposeParams", "")
+	}
+
+	// t.To (address.Address) (struct)
+
+	{
+
+		if err := t.To.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.To: %w", err), "multisig.ProposeParams", "To")
+		}
+
+	}
+	// t.Value (big.Int) (struct)
+
+	{
+
+		if err := t.Value.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Value: %w", err), "multisig.ProposeParams", "Value")
+		}
+
+	}
+	// t.Method (abi.MethodNum) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return acbor.WrapDecodeError(err, "multisig.ProposeParams", "Method")
+		}
+		if maj != cbg.MajUnsignedInt {
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "multisig.ProposeParams", "Method")
+		}
+		t.Method = abi.MethodNum(extra)
+
+	}
+	// t.Params ([]uint8) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "multisig.ProposeParams", "Params")
+	}
+
+	if extra > cbg.ByteArrayMaxLen {
+		return acbor.WrapDecodeError(fmt.Errorf("t.Params: byte array too large (%d)", extra), "multisig.ProposeParams", "Params")
+	}
+	if maj != cbg.MajByteString {
+		return acbor.WrapDecodeError(fmt.Errorf("expected byte array"), "multisig.ProposeParams", "Params")
+	}
+
+	if extra > 0 {
+		t.Params = make([]uint8, extra)
+	}
+
+	if _, err := io.ReadFull(br, t.Params[:]); err != nil {
+		return acbor.WrapDecodeError(err, "multisig.ProposeParams", "Params")
+	}
+	if fieldCount <= 4 {
+		return nil
+	}
+	// t.Expiration (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return acbor.WrapDecodeError(err, "multisig.ProposeParams", "Expiration")
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "multisig.ProposeParams", "Expiration")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "multisig.ProposeParams", "Expiration")
+			}
+			extraI = -1 - extraI
+		default:
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "multisig.ProposeParams", "Expiration")
+		}
+
+		t.Expiration = abi.ChainEpoch(extraI)
+	}
+	return nil
+}
+
+var lengthBufPruneExpiredReturn = []byte{129}
+
+func (t *PruneExpiredReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufPruneExpiredReturn); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Pruned (uint64) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Pruned)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (t *PruneExpiredReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = PruneExpiredReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "multisig.PruneExpiredReturn", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "multisig.PruneExpiredReturn", "")
+	}
+
+	if extra != 1 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "multisig.PruneExpiredReturn", "")
+	}
+
+	// t.Pruned (uint64) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return acbor.WrapDecodeError(err, "multisig.PruneExpiredReturn", "Pruned")
+		}
+		if maj != cbg.MajUnsignedInt {
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "multisig.PruneExpiredReturn", "Pruned")
+		}
+		t.Pruned = uint64(extra)
+
+	}
+	return nil
+}
+
+var lengthBufApproveBySessionKeyParams = []byte{132}
+
+func (t *ApproveBySessionKeyParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufApproveBySessionKeyParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Signer (address.Address) (struct)
+	if err := t.Signer.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Key (address.Address) (struct)
+	if err := t.Key.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Signature (crypto.Signature) (struct)
+	if err := t.Signature.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Message ([]uint8) (slice)
+	if len(t.Message) > cbg.ByteArrayMaxLen {
+		return xerrors.Errorf("Byte array in field t.Message was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajByteString, uint64(len(t.Message))); err != nil {
+		return err
+	}
+
+	if _, err := w.Write(t.Message[:]); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *ApproveBySessionKeyParams) UnmarshalCBOR(r io.Reader) error {
+	*t = ApproveBySessionKeyParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "multisig.ApproveBySessionKeyParams", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "multisig.ApproveBySessionKeyParams", "")
+	}
+
+	if extra != 4 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "multisig.ApproveBySessionKeyParams", "")
+	}
+
+	// t.Signer (address.Address) (struct)
+
+	{
+
+		if err := t.Signer.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Signer: %w", err), "multisig.ApproveBySessionKeyParams", "Signer")
+		}
+
+	}
+	// t.Key (address.Address) (struct)
+
+	{
+
+		if err := t.Key.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Key: %w", err), "multisig.ApproveBySessionKeyParams", "Key")
+		}
+
+	}
+	// t.Signature (crypto.Signature) (struct)
+
+	{
+
+		if err := t.Signature.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Signature: %w", err), "multisig.ApproveBySessionKeyParams", "Signature")
+		}
+
+	}
+	// t.Message ([]uint8) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "multisig.ApproveBySessionKeyParams", "Message")
+	}
+
+	if extra > cbg.ByteArrayMaxLen {
+		return acbor.WrapDecodeError(fmt.Errorf("t.Message: byte array too large (%d)", extra), "multisig.ApproveBySessionKeyParams", "Message")
+	}
+	if maj != cbg.MajByteString {
+		return acbor.WrapDecodeError(fmt.Errorf("expected byte array"), "multisig.ApproveBySessionKeyParams", "Message")
+	}
+
+	if extra > 0 {
+		t.Message = make([]uint8, extra)
+	}
+
+	if _, err := io.ReadFull(br, t.Message[:]); err != nil {
+		return acbor.WrapDecodeError(err, "multisig.ApproveBySessionKeyParams", "Message")
+	}
+	return nil
+}
+
+func (t *State) MaxEncodedLength() int64 {
+	return 541359
+}
+
+func (t *Transaction) MaxEncodedLength() int64 {
+	return 2638047
+}
+
+func (t *ConstructorParams) MaxEncodedLength() int64 {
+	return 540703
+}
+
+func (t *ProposeParams) MaxEncodedLength() int64 {
+	return 2097372
+}
+
+func (t *PruneExpiredReturn) MaxEncodedLength() int64 {
+	return 10
+}
+
+func (t *ApproveBySessionKeyParams) MaxEncodedLength() int64 {
+	return 2097493
+}
@@ -6,13 +6,16 @@ import (
 
 	addr "github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
 	"github.com/filecoin-project/go-state-types/cbor"
+	"github.com/filecoin-project/go-state-types/crypto"
 	"github.com/filecoin-project/go-state-types/exitcode"
 	"github.com/filecoin-project/go-state-types/network"
 	multisig0 "github.com/filecoin-project/specs-actors/actors/builtin/multisig"
 	"github.com/ipfs/go-cid"
 
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/account"
 	"github.com/filecoin-project/specs-actors/v2/actors/runtime"
 	. "github.com/filecoin-project/specs-actors/v2/actors/util"
 	"github.com/filecoin-project/specs-actors/v2/actors/util/adt"
@@ -20,16 +23,26 @@ import (
 
 type TxnID = multisig0.TxnID
 
-//type Transaction struct {
-//	To     addr.Address
-//	Value  abi.TokenAmount
-//	Method abi.MethodNum
-//	Params []byte
-//
-//	// This address at index 0 is the transaction proposer, order of this slice must be preserved.
-//	Approved []addr.Address
-//}
-type Transaction = multisig0.Transaction
+// Changed since v0:
+// - Added Expiration
+type Transaction struct {
+	To     addr.Address
+	Value  abi.TokenAmount
+	Method abi.MethodNum
+	Params []byte
+
+	// This address at index 0 is the transaction proposer, order of this slice must be preserved.
+	Approved []addr.Address
+
+	// The epoch from which the transaction can no longer be approved, or zero if it does not expire.
+	// Optional, so that transactions proposed before it existed remain valid.
+	Expiration abi.ChainEpoch `cborgen:"optional"`
+}
+
+// Whether a transaction has expired, and so can no longer be approved, at an epoch.
+func (t *Transaction) IsExpired(epoch abi.ChainEpoch) bool {
+	return t.Expiration != 0 && epoch >= t.Expiration
+}
 
 // Data for a BLAKE2B-256 to be attached to methods referencing proposals via TXIDs.
 // Ensures the existence of a cryptographic reference to the original proposal. Useful
@@ -59,6 +72,8 @@ func (a Actor) Exports() []interface{} {
 		7:                         a.SwapSigner,
 		8:                         a.ChangeNumApprovalsThreshold,
 		9:                         a.LockBalance,
+		10:                        a.PruneExpired,
+		11:                        a.ApproveBySessionKey,
 	}
 }
 
@@ -137,13 +152,18 @@ func (a Actor) Constructor(rt runtime.Runtime, params *ConstructorParams) *abi.E
 	return nil
 }
 
-//type ProposeParams struct {
-//	To     addr.Address
-//	Value  abi.TokenAmount
-//	Method abi.MethodNum
-//	Params []byte
-//}
-type ProposeParams = multisig0.ProposeParams
+// Changed since v0:
+// - Added Expiration
+type ProposeParams struct {
+	To     addr.Address
+	Value  abi.TokenAmount
+	Method abi.MethodNum
+	Params []byte
+
+	// The epoch from which the transaction can no longer be approved, or zero if it does not expire.
+	// Optional, so that parameters encoded without it remain valid.
+	Expiration abi.ChainEpoch `cborgen:"optional"`
+}
 
 //type ProposeReturn struct {
 //	// TxnID is the ID of the proposed transaction
@@ -164,6 +184,9 @@ func (a Actor) Propose(rt runtime.Runtime, params *ProposeParams) *ProposeReturn
 	if params.Value.Sign() < 0 {
 		rt.Abortf(exitcode.ErrIllegalArgument, "proposed value must be non-negative, was %v", params.Value)
 	}
+	if params.Expiration != 0 && params.Expiration <= rt.CurrEpoch() {
+		rt.Abortf(exitcode.ErrIllegalArgument, "expiration %d must be after the current epoch %d", params.Expiration, rt.CurrEpoch())
+	}
 
 	var txnID TxnID
 	var st State
@@ -179,11 +202,12 @@ func (a Actor) Propose(rt runtime.Runtime, params *ProposeParams) *ProposeReturn
 		txnID = st.NextTxnID
 		st.NextTxnID += 1
 		txn = &Transaction{
-			To:       params.To,
-			Value:    params.Value,
-			Method:   params.Method,
-			Params:   params.Params,
-			Approved: []addr.Address{},
+			To:         params.To,
+			Value:      params.Value,
+			Method:     params.Method,
+			Params:     params.Params,
+			Approved:   []addr.Address{},
+			Expiration: params.Expiration,
 		}
 
 		if err := ptx.Put(txnID, txn); err != nil {
@@ -194,7 +218,7 @@ func (a Actor) Propose(rt runtime.Runtime, params *ProposeParams) *ProposeReturn
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush pending transactions")
 	})
 
-	applied, ret, code := a.approveTransaction(rt, txnID, txn)
+	applied, ret, code := a.approveTransaction(rt, proposer, txnID, txn)
 
 	// Note: this transaction ID may not be stable across chain re-orgs.
 	// The proposal hash may be provided as a stability check when approving.
@@ -226,20 +250,62 @@ type ApproveReturn = multisig0.ApproveReturn
 
 func (a Actor) Approve(rt runtime.Runtime, params *TxnIDParams) *ApproveReturn {
 	rt.ValidateImmediateCallerType(builtin.CallerTypesSignable...)
-	callerAddr := rt.Caller()
+	return a.approve(rt, rt.Caller(), params)
+}
+
+type ApproveBySessionKeyParams struct {
+	Signer    addr.Address     // The signer on whose behalf the transaction is approved
+	Key       addr.Address     // One of the signer's session keys
+	Signature crypto.Signature // The session key's signature over the message
+	Message   []byte           // A CBOR-encoded account.SessionMessage invoking this actor's Approve method
+}
+
+// Approves a transaction on behalf of a signer, as authorized by a message signed with one of the signer's session
+// keys (see account.Actor.AuthorizeSessionKey). The signer's account authenticates the message, checking the key's
+// restrictions and consuming its nonce, so the message approves at most once.
+// May be invoked by anyone relaying the message.
+func (a Actor) ApproveBySessionKey(rt runtime.Runtime, params *ApproveBySessionKeyParams) *ApproveReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	var msg account.SessionMessage
+	err := msg.UnmarshalCBOR(bytes.NewReader(params.Message))
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed to decode session message")
+	if msg.To != rt.Receiver() || msg.Method != builtin.MethodsMultisig.Approve || !msg.Value.IsZero() {
+		rt.Abortf(exitcode.ErrIllegalArgument, "session message must invoke Approve on %v with no value", rt.Receiver())
+	}
+	var approveParams TxnIDParams
+	err = approveParams.UnmarshalCBOR(bytes.NewReader(msg.Params))
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed to decode approval params")
 
+	signer, ok := rt.ResolveAddress(params.Signer)
+	if !ok {
+		rt.Abortf(exitcode.ErrNotFound, "failed to resolve signer %v", params.Signer)
+	}
+	code := rt.Send(signer, builtin.MethodsAccount.AuthenticateSessionMessage, &account.AuthenticateSessionMessageParams{
+		Key:       params.Key,
+		Signature: params.Signature,
+		Message:   params.Message,
+	}, big.Zero(), &builtin.Discard{})
+	builtin.RequireSuccess(rt, code, "session message not authenticated by %v", signer)
+
+	return a.approve(rt, signer, &approveParams)
+}
+
+func (a Actor) approve(rt runtime.Runtime, approver addr.Address, params *TxnIDParams) *ApproveReturn {
 	var st State
 	var txn *Transaction
 	rt.StateTransaction(&st, func() {
-		callerIsSigner := isSigner(callerAddr, st.Signers)
-		if !callerIsSigner {
-			rt.Abortf(exitcode.ErrForbidden, "%s is not a signer", callerAddr)
+		if !isSigner(approver, st.Signers) {
+			rt.Abortf(exitcode.ErrForbidden, "%s is not a signer", approver)
 		}
 
 		ptx, err := adt.AsMap(adt.AsStore(rt), st.PendingTxns)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load pending transactions")
 
 		txn = getTransaction(rt, ptx, params.ID, params.ProposalHash, true)
+		if txn.IsExpired(rt.CurrEpoch()) {
+			rt.Abortf(exitcode.ErrForbidden, "transaction %d expired at epoch %d", params.ID, txn.Expiration)
+		}
 	})
 
 	// if the transaction already has enough approvers, execute it without "processing" this approval.
@@ -247,7 +313,7 @@ func (a Actor) Approve(rt runtime.Runtime, params *TxnIDParams) *ApproveReturn {
 	if !approved {
 		// if the transaction hasn't already been approved, let's "process" this approval
 		// and see if we can execute the transaction
-		approved, ret, code = a.approveTransaction(rt, params.ID, txn)
+		approved, ret, code = a.approveTransaction(rt, approver, params.ID, txn)
 	}
 
 	return &ApproveReturn{
@@ -473,24 +539,43 @@ func (a Actor) LockBalance(rt runtime.Runtime, params *LockBalanceParams) *abi.E
 	return nil
 }
 
-func (a Actor) approveTransaction(rt runtime.Runtime, txnID TxnID, txn *Transaction) (bool, []byte, exitcode.ExitCode) {
-	caller := rt.Caller()
+type PruneExpiredReturn struct {
+	// The number of expired transactions deleted.
+	Pruned uint64
+}
+
+// Deletes pending transactions which have expired, and so can no longer be approved.
+// Expired transactions can otherwise only be deleted by their proposers cancelling them, so anyone may call this
+// to clean up after a proposer who has left.
+func (a Actor) PruneExpired(rt runtime.Runtime, _ *abi.EmptyValue) *PruneExpiredReturn {
+	rt.ValidateImmediateCallerAcceptAny()
 
+	var st State
+	var pruned uint64
+	rt.StateTransaction(&st, func() {
+		var err error
+		pruned, err = st.PruneExpired(adt.AsStore(rt), rt.CurrEpoch())
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to prune expired transactions")
+	})
+	return &PruneExpiredReturn{Pruned: pruned}
+}
+
+func (a Actor) approveTransaction(rt runtime.Runtime, approver addr.Address, txnID TxnID, txn *Transaction) (bool, []byte, exitcode.ExitCode) {
 	var st State
 	// abort duplicate approval
 	for _, previousApprover := range txn.Approved {
-		if previousApprover == caller {
+		if previousApprover == approver {
 			rt.Abortf(exitcode.ErrForbidden, "%s already approved this message", previousApprover)
 		}
 	}
 
-	// add the caller to the list of approvers
+	// add the approver to the list of approvers
 	rt.StateTransaction(&st, func() {
 		ptx, err := adt.AsMap(adt.AsStore(rt), st.PendingTxns)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load pending transactions")
 
 		// update approved on the transaction
-		txn.Approved = append(txn.Approved, caller)
+		txn.Approved = append(txn.Approved, approver)
 		err = ptx.Put(txnID, txn)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to put transaction %v for approval", txnID)
 
@@ -544,6 +629,9 @@ func executeTransactionIfApproved(rt runtime.Runtime, st State, txnID TxnID, txn
 			&out,
 		)
 		applied = true
+		if code.IsSuccess() && !txn.Value.IsZero() {
+			builtin.EmitTransfer(rt, txn.To, txn.Value)
+		}
 
 		// This could be rearranged to happen inside the first state transaction, before the send().
 		rt.StateTransaction(&st, func() {
@@ -114,6 +114,36 @@ func (st *State) PurgeApprovals(store adt.Store, addr address.Address) error {
 	return nil
 }
 
+// Deletes the pending transactions which have expired at an epoch, returning the number deleted.
+func (st *State) PruneExpired(store adt.Store, epoch abi.ChainEpoch) (uint64, error) {
+	txns, err := adt.AsMap(store, st.PendingTxns)
+	if err != nil {
+		return 0, xerrors.Errorf("failed to load transactions: %w", err)
+	}
+
+	var expired []string
+	var txn Transaction
+	if err = txns.ForEach(&txn, func(txid string) error {
+		if txn.IsExpired(epoch) {
+			expired = append(expired, txid)
+		}
+		return nil
+	}); err != nil {
+		return 0, xerrors.Errorf("failed to traverse transactions: %w", err)
+	}
+
+	for _, txid := range expired {
+		if err := txns.Delete(StringKey(txid)); err != nil {
+			return 0, xerrors.Errorf("failed to delete expired transaction: %w", err)
+		}
+	}
+
+	if st.PendingTxns, err = txns.Root(); err != nil {
+		return 0, xerrors.Errorf("failed to persist transactions: %w", err)
+	}
+	return uint64(len(expired)), nil
+}
+
 // return nil if MultiSig maintains required locked balance after spending the amount, else return an error.
 func (st *State) assertAvailable(currBalance abi.TokenAmount, amountToSpend abi.TokenAmount, currEpoch abi.ChainEpoch) error {
 	if amountToSpend.LessThan(big.Zero()) {
@@ -10,6 +10,7 @@ import (
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
 	"github.com/filecoin-project/go-state-types/cbor"
+	"github.com/filecoin-project/go-state-types/crypto"
 	"github.com/filecoin-project/go-state-types/exitcode"
 	"github.com/filecoin-project/go-state-types/network"
 	"github.com/minio/blake2b-simd"
@@ -17,6 +18,7 @@ import (
 	require "github.com/stretchr/testify/require"
 
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/account"
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/miner"
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/multisig"
 	"github.com/filecoin-project/specs-actors/v2/actors/util/adt"
@@ -40,7 +42,7 @@ func TestConstruction(t *testing.T) {
 
 	charlie := tutil.NewIDAddr(t, 103)
 
-	builder := mock.NewBuilder(context.Background(), receiver).WithCaller(builtin.InitActorAddr, builtin.InitActorCodeID)
+	builder := mock.NewBuilder(context.Background(), receiver).WithStateInvariants(multisig.CheckStateInvariants).WithCaller(builtin.InitActorAddr, builtin.InitActorCodeID)
 
 	t.Run("simple construction", func(t *testing.T) {
 		rt := builder.Build(t)
@@ -175,7 +177,7 @@ func TestConstruction(t *testing.T) {
 	})
 
 	t.Run("fail to construct multisig if a signer is not resolvable to an ID address", func(t *testing.T) {
-		builder := mock.NewBuilder(context.Background(), receiver).WithCaller(builtin.InitActorAddr, builtin.InitActorCodeID)
+		builder := mock.NewBuilder(context.Background(), receiver).WithStateInvariants(multisig.CheckStateInvariants).WithCaller(builtin.InitActorAddr, builtin.InitActorCodeID)
 		rt := builder.Build(t)
 		params := multisig.ConstructorParams{
 			Signers:               []addr.Address{anneNonId, bob, charlie},
@@ -235,7 +237,7 @@ func TestVesting(t *testing.T) {
 	const unlockDuration = abi.ChainEpoch(10)
 	var multisigInitialBalance = abi.NewTokenAmount(100)
 
-	builder := mock.NewBuilder(context.Background(), receiver).
+	builder := mock.NewBuilder(context.Background(), receiver).WithStateInvariants(multisig.CheckStateInvariants).
 		WithCaller(builtin.InitActorAddr, builtin.InitActorCodeID).
 		WithEpoch(0).
 		WithBalance(multisigInitialBalance, multisigInitialBalance).
@@ -420,7 +422,7 @@ func TestPropose(t *testing.T) {
 	var fakeParams = builtin.CBORBytes([]byte{1, 2, 3, 4})
 	var signers = []addr.Address{anne, bob}
 
-	builder := mock.NewBuilder(context.Background(), receiver).WithCaller(builtin.InitActorAddr, builtin.InitActorCodeID)
+	builder := mock.NewBuilder(context.Background(), receiver).WithStateInvariants(multisig.CheckStateInvariants).WithCaller(builtin.InitActorAddr, builtin.InitActorCodeID)
 
 	t.Run("simple propose", func(t *testing.T) {
 		const numApprovals = uint64(2)
@@ -537,7 +539,7 @@ func TestApprove(t *testing.T) {
 	var fakeParams = builtin.CBORBytes([]byte{1, 2, 3, 4})
 	var signers = []addr.Address{anne, bob}
 
-	builder := mock.NewBuilder(context.Background(), receiver).
+	builder := mock.NewBuilder(context.Background(), receiver).WithStateInvariants(multisig.CheckStateInvariants).
 		WithCaller(builtin.InitActorAddr, builtin.InitActorCodeID).
 		WithHasher(blake2b.Sum256)
 
@@ -895,6 +897,99 @@ func TestApprove(t *testing.T) {
 	})
 }
 
+func TestApproveBySessionKey(t *testing.T) {
+	actor := msActorHarness{multisig.Actor{}, t}
+
+	receiver := tutil.NewIDAddr(t, 100)
+	anne := tutil.NewIDAddr(t, 101)
+	bob := tutil.NewIDAddr(t, 102)
+	chuck := tutil.NewIDAddr(t, 103)
+	relayer := tutil.NewIDAddr(t, 104)
+	sessionKey := tutil.NewBLSAddr(t, 1)
+
+	const txnID = int64(0)
+	const fakeMethod = abi.MethodNum(42)
+	var sendValue = abi.NewTokenAmount(10)
+	var fakeParams = builtin.CBORBytes([]byte{1, 2, 3, 4})
+	sig := crypto.Signature{Type: crypto.SigTypeBLS, Data: []byte("signature")}
+
+	builder := mock.NewBuilder(context.Background(), receiver).WithStateInvariants(multisig.CheckStateInvariants).
+		WithCaller(builtin.InitActorAddr, builtin.InitActorCodeID).
+		WithHasher(blake2b.Sum256)
+
+	// Bob's session message approving the first transaction.
+	sessionMessage := func(to addr.Address, method abi.MethodNum) []byte {
+		var approveParams bytes.Buffer
+		require.NoError(t, (&multisig.TxnIDParams{ID: multisig.TxnID(txnID)}).MarshalCBOR(&approveParams))
+		msg := account.SessionMessage{Account: bob, To: to, Method: method, Value: big.Zero(), Params: approveParams.Bytes()}
+		var buf bytes.Buffer
+		require.NoError(t, msg.MarshalCBOR(&buf))
+		return buf.Bytes()
+	}
+	setup := func(t *testing.T) *mock.Runtime {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, 2, 0, 0, anne, bob)
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		actor.proposeOK(rt, chuck, sendValue, fakeMethod, fakeParams, nil)
+		rt.SetBalance(sendValue)
+		rt.SetCaller(relayer, builtin.AccountActorCodeID)
+		return rt
+	}
+	approveBySessionKey := func(rt *mock.Runtime, message []byte) *multisig.ApproveReturn {
+		rt.ExpectValidateCallerAny()
+		ret := rt.Call(actor.a.ApproveBySessionKey, &multisig.ApproveBySessionKeyParams{
+			Signer:    bob,
+			Key:       sessionKey,
+			Signature: sig,
+			Message:   message,
+		})
+		rt.Verify()
+		return ret.(*multisig.ApproveReturn)
+	}
+
+	t.Run("approves as the signer once the account authenticates the message", func(t *testing.T) {
+		rt := setup(t)
+		message := sessionMessage(receiver, builtin.MethodsMultisig.Approve)
+
+		rt.ExpectSend(bob, builtin.MethodsAccount.AuthenticateSessionMessage, &account.AuthenticateSessionMessageParams{
+			Key:       sessionKey,
+			Signature: sig,
+			Message:   message,
+		}, big.Zero(), nil, exitcode.Ok)
+		rt.ExpectSend(chuck, fakeMethod, fakeParams, sendValue, nil, exitcode.Ok)
+		ret := approveBySessionKey(rt, message)
+		assert.True(t, ret.Applied)
+		assert.Equal(t, exitcode.Ok, ret.Code)
+
+		actor.assertTransactions(rt)
+		actor.checkState(rt)
+	})
+
+	t.Run("fails if the account rejects the message", func(t *testing.T) {
+		rt := setup(t)
+		message := sessionMessage(receiver, builtin.MethodsMultisig.Approve)
+
+		rt.ExpectSend(bob, builtin.MethodsAccount.AuthenticateSessionMessage, &account.AuthenticateSessionMessageParams{
+			Key:       sessionKey,
+			Signature: sig,
+			Message:   message,
+		}, big.Zero(), nil, exitcode.ErrForbidden)
+		rt.ExpectAbortContainsMessage(exitcode.ErrForbidden, "not authenticated", func() {
+			approveBySessionKey(rt, message)
+		})
+	})
+
+	t.Run("rejects messages that do not approve on this multisig", func(t *testing.T) {
+		rt := setup(t)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "must invoke Approve", func() {
+			approveBySessionKey(rt, sessionMessage(chuck, builtin.MethodsMultisig.Approve))
+		})
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "must invoke Approve", func() {
+			approveBySessionKey(rt, sessionMessage(receiver, builtin.MethodsMultisig.Cancel))
+		})
+	})
+}
+
 func TestCancel(t *testing.T) {
 	actor := msActorHarness{multisig.Actor{}, t}
 	startEpoch := abi.ChainEpoch(0)
@@ -912,7 +1007,7 @@ func TestCancel(t *testing.T) {
 	var sendValue = abi.NewTokenAmount(10)
 	var signers = []addr.Address{anne, bob}
 
-	builder := mock.NewBuilder(context.Background(), receiver).
+	builder := mock.NewBuilder(context.Background(), receiver).WithStateInvariants(multisig.CheckStateInvariants).
 		WithCaller(builtin.InitActorAddr, builtin.InitActorCodeID).
 		WithHasher(blake2b.Sum256)
 
@@ -1186,7 +1281,7 @@ func TestAddSigner(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.desc, func(t *testing.T) {
-			builder := mock.NewBuilder(context.Background(), multisigWalletAdd).WithCaller(builtin.InitActorAddr, builtin.InitActorCodeID)
+			builder := mock.NewBuilder(context.Background(), multisigWalletAdd).WithStateInvariants(multisig.CheckStateInvariants).WithCaller(builtin.InitActorAddr, builtin.InitActorCodeID)
 			rt := builder.Build(t)
 			for src, target := range tc.idAddrsMapping {
 				rt.AddIDAddress(src, target)
@@ -1239,7 +1334,7 @@ func TestRemoveSigner(t *testing.T) {
 	const noUnlockDuration = abi.ChainEpoch(0)
 
 	actor := msActorHarness{multisig.Actor{}, t}
-	builder := mock.NewBuilder(context.Background(), receiver).WithCaller(builtin.InitActorAddr, builtin.InitActorCodeID)
+	builder := mock.NewBuilder(context.Background(), receiver).WithStateInvariants(multisig.CheckStateInvariants).WithCaller(builtin.InitActorAddr, builtin.InitActorCodeID)
 
 	testCases := []removeSignerTestCase{
 		{
@@ -1480,7 +1575,7 @@ func TestSwapSigners(t *testing.T) {
 	const numApprovals = uint64(1)
 
 	actor := msActorHarness{multisig.Actor{}, t}
-	builder := mock.NewBuilder(context.Background(), receiver).WithCaller(builtin.InitActorAddr, builtin.InitActorCodeID)
+	builder := mock.NewBuilder(context.Background(), receiver).WithStateInvariants(multisig.CheckStateInvariants).WithCaller(builtin.InitActorAddr, builtin.InitActorCodeID)
 
 	testCases := []swapTestCase{
 		{
@@ -1686,7 +1781,7 @@ func TestChangeThreshold(t *testing.T) {
 		},
 	}
 
-	builder := mock.NewBuilder(context.Background(), multisigWalletAdd).WithCaller(builtin.InitActorAddr, builtin.InitActorCodeID)
+	builder := mock.NewBuilder(context.Background(), multisigWalletAdd).WithStateInvariants(multisig.CheckStateInvariants).WithCaller(builtin.InitActorAddr, builtin.InitActorCodeID)
 	for _, tc := range testCases {
 		t.Run(tc.desc, func(t *testing.T) {
 			rt := builder.Build(t)
@@ -1743,7 +1838,7 @@ func TestLockBalance(t *testing.T) {
 	anne := tutil.NewIDAddr(t, 101)
 	bob := tutil.NewIDAddr(t, 102)
 
-	builder := mock.NewBuilder(context.Background(), receiver).
+	builder := mock.NewBuilder(context.Background(), receiver).WithStateInvariants(multisig.CheckStateInvariants).
 		WithCaller(builtin.InitActorAddr, builtin.InitActorCodeID).
 		WithEpoch(0).
 		WithHasher(blake2b.Sum256)
@@ -1903,6 +1998,122 @@ func TestLockBalance(t *testing.T) {
 	})
 }
 
+func TestExpiration(t *testing.T) {
+	actor := msActorHarness{multisig.Actor{}, t}
+	receiver := tutil.NewIDAddr(t, 100)
+	anne := tutil.NewIDAddr(t, 101)
+	bob := tutil.NewIDAddr(t, 102)
+	chuck := tutil.NewIDAddr(t, 103)
+
+	sendValue := abi.NewTokenAmount(10)
+	expiration := abi.ChainEpoch(200)
+
+	builder := mock.NewBuilder(context.Background(), receiver).WithStateInvariants(multisig.CheckStateInvariants).
+		WithCaller(builtin.InitActorAddr, builtin.InitActorCodeID).
+		WithEpoch(100).
+		WithBalance(abi.NewTokenAmount(20), abi.NewTokenAmount(0)).
+		WithHasher(blake2b.Sum256)
+
+	t.Run("records the expiration of a proposal", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, 2, 0, 0, anne, bob)
+
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		actor.proposeWithExpirationOK(rt, chuck, sendValue, builtin.MethodSend, nil, expiration)
+		actor.assertTransactions(rt, multisig.Transaction{
+			To:         chuck,
+			Value:      sendValue,
+			Method:     builtin.MethodSend,
+			Approved:   []addr.Address{anne},
+			Expiration: expiration,
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("rejects an expiration not after the current epoch", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, 2, 0, 0, anne, bob)
+
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "expiration", func() {
+			actor.proposeWithExpiration(rt, chuck, sendValue, builtin.MethodSend, nil, rt.Epoch())
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("approves before expiration", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, 2, 0, 0, anne, bob)
+
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		actor.proposeWithExpirationOK(rt, chuck, sendValue, builtin.MethodSend, nil, expiration)
+
+		rt.SetEpoch(expiration - 1)
+		rt.SetCaller(bob, builtin.AccountActorCodeID)
+		rt.ExpectSend(chuck, builtin.MethodSend, nil, sendValue, nil, exitcode.Ok)
+		actor.approveOK(rt, 0, nil, nil)
+		actor.assertTransactions(rt)
+		actor.checkState(rt)
+	})
+
+	t.Run("rejects approval at and after expiration", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, 2, 0, 0, anne, bob)
+
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		actor.proposeWithExpirationOK(rt, chuck, sendValue, builtin.MethodSend, nil, expiration)
+
+		rt.SetCaller(bob, builtin.AccountActorCodeID)
+		for _, epoch := range []abi.ChainEpoch{expiration, expiration + 1} {
+			rt.SetEpoch(epoch)
+			rt.ExpectAbortContainsMessage(exitcode.ErrForbidden, "expired", func() {
+				actor.approve(rt, 0, nil, nil)
+			})
+		}
+		actor.checkState(rt)
+	})
+
+	t.Run("prunes only expired transactions", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, 2, 0, 0, anne, bob)
+
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		actor.proposeWithExpirationOK(rt, chuck, sendValue, builtin.MethodSend, nil, expiration)
+		actor.proposeWithExpirationOK(rt, chuck, sendValue, builtin.MethodSend, nil, expiration+10)
+		actor.proposeOK(rt, chuck, sendValue, builtin.MethodSend, nil, nil)
+
+		// Nothing has expired yet.
+		rt.SetEpoch(expiration - 1)
+		assert.Equal(t, uint64(0), actor.pruneExpired(rt))
+
+		rt.SetEpoch(expiration)
+		assert.Equal(t, uint64(1), actor.pruneExpired(rt))
+		actor.assertTransactions(rt, multisig.Transaction{
+			To:         chuck,
+			Value:      sendValue,
+			Method:     builtin.MethodSend,
+			Approved:   []addr.Address{anne},
+			Expiration: expiration + 10,
+		}, multisig.Transaction{
+			To:       chuck,
+			Value:    sendValue,
+			Method:   builtin.MethodSend,
+			Approved: []addr.Address{anne},
+		})
+
+		// A transaction without expiration is never pruned.
+		rt.SetEpoch(expiration + 1000)
+		assert.Equal(t, uint64(1), actor.pruneExpired(rt))
+		actor.assertTransactions(rt, multisig.Transaction{
+			To:       chuck,
+			Value:    sendValue,
+			Method:   builtin.MethodSend,
+			Approved: []addr.Address{anne},
+		})
+		actor.checkState(rt)
+	})
+}
+
 //
 // Helper methods for calling multisig actor methods
 //
@@ -1969,6 +2180,32 @@ func (h *msActorHarness) proposeOK(rt *mock.Runtime, to addr.Address, value abi.
 	return proposalHashData
 }
 
+func (h *msActorHarness) proposeWithExpiration(rt *mock.Runtime, to addr.Address, value abi.TokenAmount, method abi.MethodNum, params []byte, expiration abi.ChainEpoch) *multisig.ProposeReturn {
+	rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+	ret := rt.Call(h.a.Propose, &multisig.ProposeParams{
+		To:         to,
+		Value:      value,
+		Method:     method,
+		Params:     params,
+		Expiration: expiration,
+	})
+	rt.Verify()
+	return ret.(*multisig.ProposeReturn)
+}
+
+func (h *msActorHarness) proposeWithExpirationOK(rt *mock.Runtime, to addr.Address, value abi.TokenAmount, method abi.MethodNum, params []byte, expiration abi.ChainEpoch) {
+	ret := h.proposeWithExpiration(rt, to, value, method, params, expiration)
+	require.Equal(h.t, exitcode.Ok, ret.Code)
+}
+
+func (h *msActorHarness) pruneExpired(rt *mock.Runtime) uint64 {
+	rt.SetCaller(tutil.NewIDAddr(h.t, 1000), builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.a.PruneExpired, nil).(*multisig.PruneExpiredReturn)
+	rt.Verify()
+	return ret.Pruned
+}
+
 func (h *msActorHarness) approve(rt *mock.Runtime, txnID int64, proposalParams []byte, out cbor.Unmarshaler) exitcode.ExitCode {
 	rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
 	ret := rt.Call(h.a.Approve, &multisig.TxnIDParams{
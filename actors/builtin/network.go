@@ -2,25 +2,80 @@ package builtin
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
 )
 
 // PARAM_SPEC
-// The duration of a chain epoch.
+// The duration of a chain epoch, in seconds.
 // Motivation: It guarantees that a block is propagated and WinningPoSt can be successfully done in time all supported miners.
 // Usage: It is used for deriving epoch-denominated periods that are more naturally expressed in clock time.
-// TODO: In lieu of a real configuration mechanism for this value, we'd like to make it a var so that implementations
-// can override it at runtime. Doing so requires changing all the static references to it in this repo to go through
-// late-binding function calls, or they'll see the "wrong" value.
-// https://github.com/filecoin-project/specs-actors/issues/353
-// If EpochDurationSeconds is changed, update `BaselineExponent`, `lambda`, and // `expLamSubOne` in ./reward/reward_logic.go
+// This may be changed for a network with SetEpochDuration, normally by selecting a policy profile, which also
+// re-derives the epoch-denominated periods of the other actors' policies.
+// The reward actor's `BaselineExponent`, `lambda`, and `expLamSubOne` in ./reward/reward_logic.go are computed for
+// 30 second epochs and are not re-derived.
 // You can re-calculate these constants by changing the epoch duration in ./reward/reward_calc.py and running it.
-const EpochDurationSeconds = 30
+var EpochDurationSeconds = int64(30)
+
 const SecondsInHour = 60 * 60
 const SecondsInDay = 24 * SecondsInHour
-const EpochsInHour = SecondsInHour / EpochDurationSeconds
-const EpochsInDay = SecondsInDay / EpochDurationSeconds
+
+// The number of epochs in an hour and a day, derived from EpochDurationSeconds.
+var EpochsInHour = abi.ChainEpoch(SecondsInHour / EpochDurationSeconds)
+var EpochsInDay = abi.ChainEpoch(SecondsInDay / EpochDurationSeconds)
+
+// Sets the epoch duration and the values derived from it.
+// This is intended to be called during process initialization, before any actor code executes.
+// Policy values of other packages derived from the epoch duration are not updated.
+func SetEpochDuration(seconds int64) error {
+	if err := checkEpochDuration(seconds); err != nil {
+		return err
+	}
+	EpochDurationSeconds = seconds
+	EpochsInHour = abi.ChainEpoch(SecondsInHour / seconds)
+	EpochsInDay = abi.ChainEpoch(SecondsInDay / seconds)
+	return nil
+}
+
+func checkEpochDuration(seconds int64) error {
+	if seconds <= 0 {
+		return fmt.Errorf("non-positive epoch duration %d", seconds)
+	}
+	if SecondsInHour%seconds != 0 {
+		// This even division is an assumption that other code might unwittingly make.
+		// Don't rely on it on purpose, though.
+		// While we're pretty sure everything will still work fine, we're safer maintaining this invariant anyway.
+		return fmt.Errorf("epoch duration %d does not evenly divide one hour (%d)", seconds, SecondsInHour)
+	}
+	return nil
+}
+
+// Returns the number of whole epochs in a duration of clock time, rounding down.
+func EpochsIn(d time.Duration) abi.ChainEpoch {
+	return abi.ChainEpoch(d / (time.Duration(EpochDurationSeconds) * time.Second))
+}
+
+// Returns the clock time spanned by a number of epochs.
+func EpochsDuration(epochs abi.ChainEpoch) time.Duration {
+	return time.Duration(epochs) * time.Duration(EpochDurationSeconds) * time.Second
+}
+
+// Returns the timestamp (in seconds since the Unix epoch) at which an epoch starts, given the network's genesis
+// timestamp.
+func EpochTimestamp(genesisTimestamp uint64, epoch abi.ChainEpoch) uint64 {
+	return genesisTimestamp + uint64(epoch)*uint64(EpochDurationSeconds)
+}
+
+// Returns the epoch in progress at a timestamp (in seconds since the Unix epoch), given the network's genesis
+// timestamp. Timestamps before genesis are in epoch zero.
+func EpochAtTimestamp(genesisTimestamp, timestamp uint64) abi.ChainEpoch {
+	if timestamp < genesisTimestamp {
+		return 0
+	}
+	return abi.ChainEpoch((timestamp - genesisTimestamp) / uint64(EpochDurationSeconds))
+}
 
 // PARAM_SPEC
 // Expected number of block quality in an epoch (e.g. 1 block with block quality 5, or 5 blocks with quality 1)
@@ -29,12 +84,8 @@ const EpochsInDay = SecondsInDay / EpochDurationSeconds
 var ExpectedLeadersPerEpoch = int64(5)
 
 func init() {
-	//noinspection GoBoolExpressions
-	if SecondsInHour%EpochDurationSeconds != 0 {
-		// This even division is an assumption that other code might unwittingly make.
-		// Don't rely on it on purpose, though.
-		// While we're pretty sure everything will still work fine, we're safer maintaining this invariant anyway.
-		panic(fmt.Sprintf("epoch duration %d does not evenly divide one hour (%d)", EpochDurationSeconds, SecondsInHour))
+	if err := checkEpochDuration(EpochDurationSeconds); err != nil {
+		panic(err)
 	}
 }
 
@@ -0,0 +1,43 @@
+package builtin_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+)
+
+func TestEpochConversions(t *testing.T) {
+	t.Run("default epoch duration", func(t *testing.T) {
+		assert.Equal(t, abi.ChainEpoch(2880), builtin.EpochsInDay)
+		assert.Equal(t, abi.ChainEpoch(120), builtin.EpochsInHour)
+		assert.Equal(t, abi.ChainEpoch(60), builtin.EpochsIn(30*time.Minute))
+		assert.Equal(t, abi.ChainEpoch(1), builtin.EpochsIn(59*time.Second))
+		assert.Equal(t, 24*time.Hour, builtin.EpochsDuration(builtin.EpochsInDay))
+
+		genesis := uint64(1598306400)
+		assert.Equal(t, genesis+90, builtin.EpochTimestamp(genesis, 3))
+		assert.Equal(t, abi.ChainEpoch(3), builtin.EpochAtTimestamp(genesis, genesis+90))
+		assert.Equal(t, abi.ChainEpoch(3), builtin.EpochAtTimestamp(genesis, genesis+119))
+		assert.Equal(t, abi.ChainEpoch(0), builtin.EpochAtTimestamp(genesis, genesis-1))
+	})
+
+	t.Run("set epoch duration", func(t *testing.T) {
+		defer func() { require.NoError(t, builtin.SetEpochDuration(30)) }()
+
+		assert.Error(t, builtin.SetEpochDuration(0))
+		assert.Error(t, builtin.SetEpochDuration(7))
+		assert.Equal(t, int64(30), builtin.EpochDurationSeconds)
+
+		require.NoError(t, builtin.SetEpochDuration(4))
+		assert.Equal(t, abi.ChainEpoch(21600), builtin.EpochsInDay)
+		assert.Equal(t, abi.ChainEpoch(900), builtin.EpochsInHour)
+		assert.Equal(t, abi.ChainEpoch(450), builtin.EpochsIn(30*time.Minute))
+		assert.Equal(t, 8*time.Second, builtin.EpochsDuration(2))
+		assert.Equal(t, abi.ChainEpoch(25), builtin.EpochAtTimestamp(1000, 1100))
+	})
+}
@@ -0,0 +1,70 @@
+package paramreg
+
+import (
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/cbor"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/ipfs/go-cid"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v2/actors/runtime"
+	"github.com/filecoin-project/specs-actors/v2/actors/util/adt"
+)
+
+// The parameter registry holds named policy parameters for reading by other actors.
+// Parameters are set only by state migrations, which record each change in the registry's history.
+type Actor struct{}
+
+func (a Actor) Exports() []interface{} {
+	return []interface{}{
+		builtin.MethodConstructor: a.Constructor,
+		2:                         a.Get,
+	}
+}
+
+func (a Actor) Code() cid.Cid {
+	return builtin.ParameterRegistryActorCodeID
+}
+
+func (a Actor) IsSingleton() bool {
+	return true
+}
+
+func (a Actor) State() cbor.Er {
+	return new(State)
+}
+
+var _ runtime.VMActor = Actor{}
+
+func (a Actor) Constructor(rt runtime.Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
+	rt.ValidateImmediateCallerIs(builtin.SystemActorAddr)
+
+	emptyArray, err := adt.MakeEmptyArray(adt.AsStore(rt)).Root()
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to create state")
+
+	rt.StateCreate(ConstructState(emptyArray))
+	return nil
+}
+
+type GetParams struct {
+	Name string
+}
+
+type GetReturn struct {
+	Value big.Int
+}
+
+// Returns the current value of a parameter.
+// Aborts with ErrNotFound if the parameter is not set.
+func (a Actor) Get(rt runtime.Runtime, params *GetParams) *GetReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	var st State
+	rt.StateReadonly(&st)
+	value, found := st.GetParameter(params.Name)
+	if !found {
+		rt.Abortf(exitcode.ErrNotFound, "no parameter %s", params.Name)
+	}
+	return &GetReturn{Value: value}
+}
@@ -0,0 +1,81 @@
+package paramreg
+
+import (
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/network"
+	cid "github.com/ipfs/go-cid"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/util/adt"
+)
+
+type State struct {
+	// Current value of each parameter, keyed by name.
+	Parameters map[string]big.Int
+	// Every change made to a parameter, in order.
+	History cid.Cid // AMT[ParameterChange]
+}
+
+// A named policy parameter, such as a duration, limit or fee constant.
+type Parameter struct {
+	Name  string
+	Value big.Int
+}
+
+// Records the setting of a parameter's value by a state migration.
+type ParameterChange struct {
+	Name           string
+	Value          big.Int
+	Epoch          abi.ChainEpoch // The epoch prior to the migration making the change
+	NetworkVersion uint64         // network.Version; the version to which the migration upgraded
+}
+
+func ConstructState(emptyArrayCid cid.Cid) *State {
+	return &State{
+		Parameters: nil,
+		History:    emptyArrayCid,
+	}
+}
+
+// Returns the current value of a parameter, and whether it is set.
+func (st *State) GetParameter(name string) (big.Int, bool) {
+	value, found := st.Parameters[name]
+	if !found {
+		return big.Zero(), false
+	}
+	return value, true
+}
+
+// Sets the value of a parameter, recording the change in the history.
+// This is intended for use only by state migrations; there is no actor method to change a parameter.
+func (st *State) SetParameter(store adt.Store, name string, value big.Int, epoch abi.ChainEpoch, nv network.Version) error {
+	if name == "" {
+		return xerrors.Errorf("empty parameter name")
+	}
+	if value.Int == nil {
+		return xerrors.Errorf("undefined value for parameter %s", name)
+	}
+
+	history, err := adt.AsArray(store, st.History)
+	if err != nil {
+		return xerrors.Errorf("failed to load parameter history: %w", err)
+	}
+	if err := history.AppendContinuous(&ParameterChange{
+		Name:           name,
+		Value:          value,
+		Epoch:          epoch,
+		NetworkVersion: uint64(nv),
+	}); err != nil {
+		return xerrors.Errorf("failed to record change to parameter %s: %w", name, err)
+	}
+	if st.History, err = history.Root(); err != nil {
+		return xerrors.Errorf("failed to flush parameter history: %w", err)
+	}
+
+	if st.Parameters == nil {
+		st.Parameters = map[string]big.Int{}
+	}
+	st.Parameters[name] = value
+	return nil
+}
@@ -0,0 +1,140 @@
+package paramreg_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/filecoin-project/go-state-types/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/paramreg"
+	"github.com/filecoin-project/specs-actors/v2/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/v2/support/mock"
+	tutil "github.com/filecoin-project/specs-actors/v2/support/testing"
+)
+
+func TestExports(t *testing.T) {
+	mock.CheckActorExports(t, paramreg.Actor{})
+}
+
+func TestConstruction(t *testing.T) {
+	rt := constructRegistry(t)
+
+	var st paramreg.State
+	rt.GetState(&st)
+	assert.Empty(t, st.Parameters)
+	checkState(t, rt)
+}
+
+func TestGet(t *testing.T) {
+	a := paramreg.Actor{}
+
+	t.Run("returns value set by migration", func(t *testing.T) {
+		rt := constructRegistry(t)
+		setParameter(t, rt, "miner/PreCommitChallengeDelay", big.NewInt(150), 100, network.Version4)
+
+		rt.SetCaller(tutil.NewIDAddr(t, 1000), builtin.StorageMinerActorCodeID)
+		rt.ExpectValidateCallerAny()
+		ret := rt.Call(a.Get, &paramreg.GetParams{Name: "miner/PreCommitChallengeDelay"}).(*paramreg.GetReturn)
+		rt.Verify()
+		assert.Equal(t, big.NewInt(150), ret.Value)
+	})
+
+	t.Run("fails for unset parameter", func(t *testing.T) {
+		rt := constructRegistry(t)
+		rt.SetCaller(tutil.NewIDAddr(t, 1000), builtin.StorageMinerActorCodeID)
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbort(exitcode.ErrNotFound, func() {
+			rt.Call(a.Get, &paramreg.GetParams{Name: "miner/PreCommitChallengeDelay"})
+		})
+	})
+}
+
+func TestSetParameter(t *testing.T) {
+	rt := constructRegistry(t)
+	setParameter(t, rt, "b", big.NewInt(2), 100, network.Version4)
+	setParameter(t, rt, "a", big.NewInt(1), 100, network.Version4)
+	setParameter(t, rt, "b", big.NewInt(3), 200, network.Version5)
+
+	var st paramreg.State
+	rt.GetState(&st)
+	assert.Equal(t, map[string]big.Int{
+		"a": big.NewInt(1),
+		"b": big.NewInt(3),
+	}, st.Parameters)
+
+	// Every change is recorded in order.
+	history, err := adt.AsArray(adt.AsStore(rt), st.History)
+	require.NoError(t, err)
+	var changes []paramreg.ParameterChange
+	var change paramreg.ParameterChange
+	require.NoError(t, history.ForEach(&change, func(i int64) error {
+		changes = append(changes, change)
+		return nil
+	}))
+	assert.Equal(t, []paramreg.ParameterChange{
+		{Name: "b", Value: big.NewInt(2), Epoch: 100, NetworkVersion: uint64(network.Version4)},
+		{Name: "a", Value: big.NewInt(1), Epoch: 100, NetworkVersion: uint64(network.Version4)},
+		{Name: "b", Value: big.NewInt(3), Epoch: 200, NetworkVersion: uint64(network.Version5)},
+	}, changes)
+
+	assert.Error(t, st.SetParameter(adt.AsStore(rt), "", big.Zero(), 200, network.Version5))
+	checkState(t, rt)
+}
+
+func TestStateEncoding(t *testing.T) {
+	// Parameters are encoded as a CBOR map with keys in canonical order, however the map was built.
+	st := paramreg.State{
+		Parameters: map[string]big.Int{"bb": big.NewInt(1), "c": big.NewInt(2), "a": big.NewInt(3)},
+		History:    tutil.MakeCID("history", nil),
+	}
+	var buf bytes.Buffer
+	require.NoError(t, st.MarshalCBOR(&buf))
+	encoded := buf.Bytes()
+	assert.Less(t, bytes.Index(encoded, []byte("a")), bytes.Index(encoded, []byte("c")))
+	assert.Less(t, bytes.Index(encoded, []byte("c")), bytes.Index(encoded, []byte("bb")))
+
+	for i := 0; i < 10; i++ {
+		var reencoded bytes.Buffer
+		require.NoError(t, st.MarshalCBOR(&reencoded))
+		assert.Equal(t, encoded, reencoded.Bytes())
+	}
+
+	var decoded paramreg.State
+	require.NoError(t, decoded.UnmarshalCBOR(bytes.NewReader(encoded)))
+	assert.Equal(t, st, decoded)
+}
+
+func constructRegistry(t *testing.T) *mock.Runtime {
+	rt := mock.NewBuilder(context.Background(), builtin.ParameterRegistryActorAddr).
+		WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID).
+		Build(t)
+	rt.ExpectValidateCallerAddr(builtin.SystemActorAddr)
+	ret := rt.Call(paramreg.Actor{}.Constructor, nil)
+	assert.Nil(t, ret)
+	rt.Verify()
+	return rt
+}
+
+// Sets a parameter directly in state, as a migration would.
+func setParameter(t *testing.T, rt *mock.Runtime, name string, value big.Int, epoch abi.ChainEpoch, nv network.Version) {
+	var st paramreg.State
+	rt.GetState(&st)
+	require.NoError(t, st.SetParameter(adt.AsStore(rt), name, value, epoch, nv))
+	rt.ReplaceState(&st)
+}
+
+func checkState(t *testing.T, rt *mock.Runtime) {
+	var st paramreg.State
+	rt.GetState(&st)
+	_, msgs, err := paramreg.CheckStateInvariants(&st, rt.AdtStore())
+	assert.NoError(t, err)
+	assert.True(t, msgs.IsEmpty(), strings.Join(msgs.Messages(), "\n"))
+}
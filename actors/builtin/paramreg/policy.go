@@ -0,0 +1,24 @@
+package paramreg
+
+import (
+	"github.com/filecoin-project/go-state-types/big"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/cron"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/market"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/miner"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/power"
+)
+
+// Returns the parameters the registry records, with the values of the policy variables from which the actors
+// read them. The actors do not read the registry: it records their parameters so that changes made at upgrades are
+// visible on chain, and the state invariants (see states.CheckParameterRegistryAgainstPolicy) hold it equal to
+// these values, so that a migration cannot change one without the other.
+func PolicyParameters() []Parameter {
+	return []Parameter{
+		{Name: "cron/UserEntryInvocationFee", Value: cron.UserEntryInvocationFee},
+		{Name: "market/DealUpdatesInterval", Value: big.NewInt(int64(market.DealUpdatesInterval))},
+		{Name: "miner/PreCommitChallengeDelay", Value: big.NewInt(int64(miner.PreCommitChallengeDelay))},
+		{Name: "miner/WPoStProvingPeriod", Value: big.NewInt(int64(miner.WPoStProvingPeriod))},
+		{Name: "power/ConsensusMinerMinMiners", Value: big.NewInt(power.ConsensusMinerMinMiners)},
+	}
+}
@@ -0,0 +1,52 @@
+package paramreg
+
+import (
+	"github.com/filecoin-project/go-state-types/big"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v2/actors/util/adt"
+)
+
+type StateSummary struct {
+	Parameters map[string]big.Int
+}
+
+// Checks internal invariants of parameter registry state.
+func CheckStateInvariants(st *State, store adt.Store) (*StateSummary, *builtin.MessageAccumulator, error) {
+	acc := &builtin.MessageAccumulator{}
+
+	params := make(map[string]big.Int, len(st.Parameters))
+	for name, value := range st.Parameters { //nolint:nomaprange
+		acc.Require(name != "", "parameter has empty name")
+		acc.Require(value.Int != nil, "parameter %s has undefined value", name)
+		params[name] = value
+	}
+
+	// The last change to each parameter determines its current value.
+	history, err := adt.AsArray(store, st.History)
+	if err != nil {
+		return nil, nil, err
+	}
+	latest := map[string]big.Int{}
+	var change ParameterChange
+	prevVersion := uint64(0)
+	if err = history.ForEach(&change, func(i int64) error {
+		acc.Require(change.NetworkVersion >= prevVersion, "parameter change %d network version %d precedes prior change", i, change.NetworkVersion)
+		prevVersion = change.NetworkVersion
+		latest[change.Name] = change.Value
+		return nil
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	acc.Require(len(latest) == len(params), "history records %d parameters, state has %d", len(latest), len(params))
+	for name, value := range params { //nolint:nomaprange
+		last, found := latest[name]
+		acc.Require(found, "parameter %s has no recorded change", name)
+		acc.Require(!found || last.Equals(value), "parameter %s value %v does not match last change %v", name, value, last)
+	}
+
+	return &StateSummary{
+		Parameters: params,
+	}, acc, nil
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 
 	abi "github.com/filecoin-project/go-state-types/abi"
+	acbor "github.com/filecoin-project/specs-actors/v2/actors/util/cbor"
 	cbg "github.com/whyrusleeping/cbor-gen"
 	xerrors "golang.org/x/xerrors"
 )
@@ -80,14 +81,14 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "paych.State", "")
 	}
 	if maj != cbg.MajArray {
-		return fmt.Errorf("cbor input should be of type array")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "paych.State", "")
 	}
 
 	if extra != 6 {
-		return fmt.Errorf("cbor input had wrong number of fields")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "paych.State", "")
 	}
 
 	// t.From (address.Address) (struct)
@@ -95,7 +96,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.From.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.From: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.From: %w", err), "paych.State", "From")
 		}
 
 	}
@@ -104,7 +105,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.To.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.To: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.To: %w", err), "paych.State", "To")
 		}
 
 	}
@@ -113,7 +114,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.ToSend.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.ToSend: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.ToSend: %w", err), "paych.State", "ToSend")
 		}
 
 	}
@@ -122,22 +123,22 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 		var extraI int64
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "paych.State", "SettlingAt")
 		}
 		switch maj {
 		case cbg.MajUnsignedInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 positive overflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "paych.State", "SettlingAt")
 			}
 		case cbg.MajNegativeInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 negative oveflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "paych.State", "SettlingAt")
 			}
 			extraI = -1 - extraI
 		default:
-			return fmt.Errorf("wrong type for int64 field: %d", maj)
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "paych.State", "SettlingAt")
 		}
 
 		t.SettlingAt = abi.ChainEpoch(extraI)
@@ -147,22 +148,22 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 		var extraI int64
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "paych.State", "MinSettleHeight")
 		}
 		switch maj {
 		case cbg.MajUnsignedInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 positive overflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "paych.State", "MinSettleHeight")
 			}
 		case cbg.MajNegativeInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 negative oveflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "paych.State", "MinSettleHeight")
 			}
 			extraI = -1 - extraI
 		default:
-			return fmt.Errorf("wrong type for int64 field: %d", maj)
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "paych.State", "MinSettleHeight")
 		}
 
 		t.MinSettleHeight = abi.ChainEpoch(extraI)
@@ -173,7 +174,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 		c, err := cbg.ReadCid(br)
 		if err != nil {
-			return xerrors.Errorf("failed to read cid field t.LaneStates: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read cid field t.LaneStates: %w", err), "paych.State", "LaneStates")
 		}
 
 		t.LaneStates = c
@@ -217,14 +218,14 @@ func (t *LaneState) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "paych.LaneState", "")
 	}
 	if maj != cbg.MajArray {
-		return fmt.Errorf("cbor input should be of type array")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "paych.LaneState", "")
 	}
 
 	if extra != 2 {
-		return fmt.Errorf("cbor input had wrong number of fields")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "paych.LaneState", "")
 	}
 
 	// t.Redeemed (big.Int) (struct)
@@ -232,7 +233,7 @@ func (t *LaneState) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.Redeemed.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.Redeemed: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Redeemed: %w", err), "paych.LaneState", "Redeemed")
 		}
 
 	}
@@ -242,10 +243,10 @@ func (t *LaneState) UnmarshalCBOR(r io.Reader) error {
 
 		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "paych.LaneState", "Nonce")
 		}
 		if maj != cbg.MajUnsignedInt {
-			return fmt.Errorf("wrong type for uint64 field")
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "paych.LaneState", "Nonce")
 		}
 		t.Nonce = uint64(extra)
 
@@ -294,14 +295,14 @@ func (t *UpdateChannelStateParams) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "paych.UpdateChannelStateParams", "")
 	}
 	if maj != cbg.MajArray {
-		return fmt.Errorf("cbor input should be of type array")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "paych.UpdateChannelStateParams", "")
 	}
 
 	if extra != 2 {
-		return fmt.Errorf("cbor input had wrong number of fields")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "paych.UpdateChannelStateParams", "")
 	}
 
 	// t.Sv (paych.SignedVoucher) (struct)
@@ -309,7 +310,7 @@ func (t *UpdateChannelStateParams) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.Sv.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.Sv: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Sv: %w", err), "paych.UpdateChannelStateParams", "Sv")
 		}
 
 	}
@@ -317,14 +318,14 @@ func (t *UpdateChannelStateParams) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "paych.UpdateChannelStateParams", "Secret")
 	}
 
 	if extra > cbg.ByteArrayMaxLen {
-		return fmt.Errorf("t.Secret: byte array too large (%d)", extra)
+		return acbor.WrapDecodeError(fmt.Errorf("t.Secret: byte array too large (%d)", extra), "paych.UpdateChannelStateParams", "Secret")
 	}
 	if maj != cbg.MajByteString {
-		return fmt.Errorf("expected byte array")
+		return acbor.WrapDecodeError(fmt.Errorf("expected byte array"), "paych.UpdateChannelStateParams", "Secret")
 	}
 
 	if extra > 0 {
@@ -332,7 +333,71 @@ func (t *UpdateChannelStateParams) UnmarshalCBOR(r io.Reader) error {
 	}
 
 	if _, err := io.ReadFull(br, t.Secret[:]); err != nil {
+		return acbor.WrapDecodeError(err, "paych.UpdateChannelStateParams", "Secret")
+	}
+	return nil
+}
+
+var lengthBufPartialCollectReturn = []byte{129}
+
+func (t *PartialCollectReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufPartialCollectReturn); err != nil {
+		return err
+	}
+
+	// t.Collected (big.Int) (struct)
+	if err := t.Collected.MarshalCBOR(w); err != nil {
 		return err
 	}
 	return nil
 }
+
+func (t *PartialCollectReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = PartialCollectReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "paych.PartialCollectReturn", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "paych.PartialCollectReturn", "")
+	}
+
+	if extra != 1 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "paych.PartialCollectReturn", "")
+	}
+
+	// t.Collected (big.Int) (struct)
+
+	{
+
+		if err := t.Collected.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Collected: %w", err), "paych.PartialCollectReturn", "Collected")
+		}
+
+	}
+	return nil
+}
+
+func (t *State) MaxEncodedLength() int64 {
+	return 798
+}
+
+func (t *LaneState) MaxEncodedLength() int64 {
+	return 140
+}
+
+func (t *UpdateChannelStateParams) MaxEncodedLength() int64 {
+	return 6447644
+}
+
+func (t *PartialCollectReturn) MaxEncodedLength() int64 {
+	return 131
+}
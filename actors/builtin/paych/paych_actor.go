@@ -20,6 +20,12 @@ const (
 	ErrChannelStateUpdateAfterSettled = exitcode.FirstActorSpecificExitCode + iota
 )
 
+func init() {
+	builtin.RegisterExitCodeNames(builtin.PaymentChannelActorCodeID, map[exitcode.ExitCode]string{
+		ErrChannelStateUpdateAfterSettled: "ErrChannelStateUpdateAfterSettled",
+	})
+}
+
 type Actor struct{}
 
 func (a Actor) Exports() []interface{} {
@@ -28,6 +34,7 @@ func (a Actor) Exports() []interface{} {
 		2:                         a.UpdateChannelState,
 		3:                         a.Settle,
 		4:                         a.Collect,
+		5:                         a.PartialCollect,
 	}
 }
 
@@ -246,27 +253,7 @@ func (pca Actor) UpdateChannelState(rt runtime.Runtime, params *UpdateChannelSta
 
 		// The next section actually calculates the payment amounts to update the payment channel state
 		// 1. (optional) sum already redeemed value of all merging lanes
-		redeemedFromOthers := big.Zero()
-		for _, merge := range sv.Merges {
-			if merge.Lane == sv.Lane {
-				rt.Abortf(exitcode.ErrIllegalArgument, "voucher cannot merge lanes into its own lane")
-			}
-
-			otherls := findLane(rt, lstates, merge.Lane)
-			if otherls == nil {
-				rt.Abortf(exitcode.ErrIllegalArgument, "voucher specifies invalid merge lane %v", merge.Lane)
-				return // makes linters happy
-			}
-
-			if otherls.Nonce >= merge.Nonce {
-				rt.Abortf(exitcode.ErrIllegalArgument, "merged lane in voucher has outdated nonce, cannot redeem")
-			}
-
-			redeemedFromOthers = big.Add(redeemedFromOthers, otherls.Redeemed)
-			otherls.Nonce = merge.Nonce
-			err = lstates.Set(merge.Lane, otherls)
-			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to store lane %d", merge.Lane)
-		}
+		redeemedFromOthers := mergeLanes(rt, lstates, sv.Lane, sv.Merges)
 
 		// 2. To prevent double counting, remove already redeemed amounts (from
 		// voucher or other lanes) from the voucher amount
@@ -343,12 +330,88 @@ func (pca Actor) Collect(rt runtime.Runtime, _ *abi.EmptyValue) *abi.EmptyValue
 	)
 	builtin.RequireSuccess(rt, codeTo, "Failed to send funds to `To`")
 
+	// remove this actor's address mapping so that its robust address no longer resolves once it is deleted.
+	codeTombstone := rt.Send(builtin.InitActorAddr, builtin.MethodsInit.TombstoneActor, nil, big.Zero(), &builtin.Discard{})
+	builtin.RequireSuccess(rt, codeTombstone, "failed to tombstone payment channel address")
+
 	// the remaining balance will be returned to "From" upon deletion.
 	rt.DeleteActor(st.From)
 
 	return nil
 }
 
+type PartialCollectReturn struct {
+	// The amount sent to To.
+	Collected abi.TokenAmount
+}
+
+// Sends the amount redeemed so far to To, without settling the channel, so that the payee need not wait for
+// settlement to be paid. Vouchers may continue to be redeemed afterwards, their amounts remaining cumulative
+// over each lane. Once the channel has settled, Collect pays out the remainder.
+// Nothing may be collected before the channel's MinSettleHeight.
+// The state is updated before the funds are sent. Only the channel's parties, which are account actors, may
+// call this or any other method that changes the state, so the send cannot re-enter the channel.
+func (pca Actor) PartialCollect(rt runtime.Runtime, _ *abi.EmptyValue) *PartialCollectReturn {
+	var st State
+	var collected abi.TokenAmount
+	rt.StateTransaction(&st, func() {
+		rt.ValidateImmediateCallerIs(st.From, st.To)
+
+		if st.SettlingAt != 0 && rt.CurrEpoch() >= st.SettlingAt {
+			rt.Abortf(exitcode.ErrForbidden, "payment channel settled, use Collect")
+		}
+		if rt.CurrEpoch() < st.MinSettleHeight {
+			rt.Abortf(exitcode.ErrForbidden, "cannot collect before min settle height %d", st.MinSettleHeight)
+		}
+
+		collected = st.ToSend
+		st.ToSend = big.Zero()
+	})
+
+	if !collected.IsZero() {
+		code := rt.Send(st.To, builtin.MethodSend, nil, collected, &builtin.Discard{})
+		builtin.RequireSuccess(rt, code, "Failed to send funds to `To`")
+	}
+	return &PartialCollectReturn{Collected: collected}
+}
+
+// Validates a voucher's merges into a lane against the nonces of the lanes merged, and advances those nonces.
+// All merges are validated before any lane is updated, and a lane may be merged at most once.
+// Returns the total amount already redeemed by the merged lanes.
+func mergeLanes(rt runtime.Runtime, lstates *adt.Array, lane uint64, merges []Merge) abi.TokenAmount {
+	mergedLanes := make([]*LaneState, len(merges))
+	seen := make(map[uint64]struct{}, len(merges))
+	for i, merge := range merges {
+		if merge.Lane == lane {
+			rt.Abortf(exitcode.ErrIllegalArgument, "voucher cannot merge lanes into its own lane")
+		}
+		if _, ok := seen[merge.Lane]; ok {
+			rt.Abortf(exitcode.ErrIllegalArgument, "voucher merges lane %d more than once", merge.Lane)
+		}
+		seen[merge.Lane] = struct{}{}
+
+		otherls := findLane(rt, lstates, merge.Lane)
+		if otherls == nil {
+			rt.Abortf(exitcode.ErrIllegalArgument, "voucher specifies invalid merge lane %v", merge.Lane)
+			return big.Zero() // makes linters happy
+		}
+		if otherls.Nonce >= merge.Nonce {
+			rt.Abortf(exitcode.ErrIllegalArgument, "merged lane in voucher has outdated nonce, cannot redeem")
+		}
+		mergedLanes[i] = otherls
+	}
+
+	redeemed := big.Zero()
+	for i, merge := range merges {
+		otherls := mergedLanes[i]
+		redeemed = big.Add(redeemed, otherls.Redeemed)
+		otherls.Nonce = merge.Nonce
+		err := lstates.Set(merge.Lane, otherls)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to store lane %d", merge.Lane)
+	}
+	return redeemed
+}
+
 // Returns the insertion index for a lane ID, with the matching lane state if found, or nil.
 func findLane(rt runtime.Runtime, ls *adt.Array, id uint64) *LaneState {
 	if id > MaxLane {
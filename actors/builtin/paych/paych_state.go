@@ -16,7 +16,8 @@ type State struct {
 	// Recipient of payouts from channel
 	To addr.Address
 
-	// Amount successfully redeemed through the payment channel, paid out on `Collect()`
+	// Amount successfully redeemed through the payment channel and not yet paid out, paid out on `Collect()`
+	// or `PartialCollect()`
 	ToSend abi.TokenAmount
 
 	// Height at which the channel can be `Collected`
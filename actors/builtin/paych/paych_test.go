@@ -39,7 +39,7 @@ func TestPaymentChannelActor_Constructor(t *testing.T) {
 	actor := pcActorHarness{Actor{}, t, paychAddr, payerAddr, payeeAddr}
 
 	t.Run("can create a payment channel actor", func(t *testing.T) {
-		builder := mock.NewBuilder(ctx, paychAddr).
+		builder := mock.NewBuilder(ctx, paychAddr).WithStateInvariants(CheckStateInvariants).
 			WithCaller(callerAddr, builtin.InitActorCodeID).
 			WithActorType(payerAddr, builtin.AccountActorCodeID).
 			WithActorType(payeeAddr, builtin.AccountActorCodeID)
@@ -55,7 +55,7 @@ func TestPaymentChannelActor_Constructor(t *testing.T) {
 		payeeAddr := tutil.NewIDAddr(t, 103)
 		payeeNonId := tutil.NewBLSAddr(t, 104)
 
-		builder := mock.NewBuilder(ctx, paychAddr).
+		builder := mock.NewBuilder(ctx, paychAddr).WithStateInvariants(CheckStateInvariants).
 			WithCaller(callerAddr, builtin.InitActorCodeID).
 			WithActorType(payerAddr, builtin.AccountActorCodeID).
 			WithActorType(payeeAddr, builtin.AccountActorCodeID)
@@ -92,7 +92,7 @@ func TestPaymentChannelActor_Constructor(t *testing.T) {
 	}
 	for _, tc := range testCases {
 		t.Run(tc.desc, func(t *testing.T) {
-			builder := mock.NewBuilder(ctx, paychAddr).
+			builder := mock.NewBuilder(ctx, paychAddr).WithStateInvariants(CheckStateInvariants).
 				WithCaller(callerAddr, builtin.InitActorCodeID).
 				WithActorType(paychAddr, builtin.PaymentChannelActorCodeID).
 				WithActorType(payerAddr, tc.toCode).
@@ -109,7 +109,7 @@ func TestPaymentChannelActor_Constructor(t *testing.T) {
 		to := tutil.NewIDAddr(t, 101)
 		nonIdAddr := tutil.NewBLSAddr(t, 501)
 
-		rt := mock.NewBuilder(ctx, paychAddr).
+		rt := mock.NewBuilder(ctx, paychAddr).WithStateInvariants(CheckStateInvariants).
 			WithCaller(callerAddr, builtin.InitActorCodeID).
 			WithActorType(to, builtin.AccountActorCodeID).Build(t)
 
@@ -125,7 +125,7 @@ func TestPaymentChannelActor_Constructor(t *testing.T) {
 		from := tutil.NewIDAddr(t, 5555)
 		nonIdAddr := tutil.NewBLSAddr(t, 501)
 
-		rt := mock.NewBuilder(ctx, paychAddr).
+		rt := mock.NewBuilder(ctx, paychAddr).WithStateInvariants(CheckStateInvariants).
 			WithCaller(callerAddr, builtin.InitActorCodeID).
 			WithActorType(from, builtin.AccountActorCodeID).Build(t)
 
@@ -138,7 +138,7 @@ func TestPaymentChannelActor_Constructor(t *testing.T) {
 	})
 
 	t.Run("fails if actor does not exist with: no code for address", func(t *testing.T) {
-		builder := mock.NewBuilder(ctx, paychAddr).
+		builder := mock.NewBuilder(ctx, paychAddr).WithStateInvariants(CheckStateInvariants).
 			WithCaller(callerAddr, builtin.InitActorCodeID).
 			WithActorType(payerAddr, builtin.AccountActorCodeID)
 		rt := builder.Build(t)
@@ -233,7 +233,7 @@ func TestPaymentChannelActor_CreateLane(t *testing.T) {
 		t.Run(tc.desc, func(t *testing.T) {
 			hasher := func(data []byte) [32]byte { return [32]byte{} }
 
-			builder := mock.NewBuilder(ctx, paychAddr).
+			builder := mock.NewBuilder(ctx, paychAddr).WithStateInvariants(CheckStateInvariants).
 				WithBalance(payChBalance, abi.NewTokenAmount(tc.received)).
 				WithEpoch(abi.ChainEpoch(tc.epoch)).
 				WithCaller(initActorAddr, builtin.InitActorCodeID).
@@ -565,6 +565,66 @@ func TestActor_UpdateChannelStateMergeFailure(t *testing.T) {
 	})
 }
 
+func TestActor_UpdateChannelStateMultipleMerges(t *testing.T) {
+	t.Run("merges several lanes into one", func(t *testing.T) {
+		rt, actor, sv := requireCreateChannelWithLanes(t, context.Background(), 3)
+		var st1 State
+		rt.GetState(&st1)
+
+		// Lanes 0, 1 and 2 have redeemed 1, 2 and 3.
+		sv.Lane = 0
+		sv.Nonce = 10
+		sv.Amount = abi.NewTokenAmount(10)
+		sv.Merges = []Merge{{Lane: 1, Nonce: 10}, {Lane: 2, Nonce: 10}}
+		actor.updateChannelState(rt, st1.From, sv)
+
+		expState := st1
+		expState.ToSend = big.Add(st1.ToSend, big.NewInt(4))
+		expState.LaneStates = constructLaneStateAMT(t, rt, []*LaneState{
+			{Redeemed: sv.Amount, Nonce: 10},
+			{Redeemed: big.NewInt(2), Nonce: 10},
+			{Redeemed: big.NewInt(3), Nonce: 10},
+		})
+		verifyState(t, rt, 3, expState)
+		actor.checkState(rt)
+	})
+
+	t.Run("fails when a lane is merged more than once", func(t *testing.T) {
+		rt, actor, sv := requireCreateChannelWithLanes(t, context.Background(), 2)
+		var st1 State
+		rt.GetState(&st1)
+
+		sv.Lane = 0
+		sv.Nonce = 10
+		sv.Merges = []Merge{{Lane: 1, Nonce: 10}, {Lane: 1, Nonce: 11}}
+		rt.SetCaller(st1.From, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(st1.From, st1.To)
+		rt.ExpectVerifySignature(*sv.Signature, actor.payee, voucherBytes(t, sv), nil)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "more than once", func() {
+			rt.Call(actor.UpdateChannelState, &UpdateChannelStateParams{Sv: *sv})
+		})
+		rt.Verify()
+	})
+
+	t.Run("fails without updating any lane when a later merge is invalid", func(t *testing.T) {
+		rt, actor, sv := requireCreateChannelWithLanes(t, context.Background(), 3)
+		var st1 State
+		rt.GetState(&st1)
+
+		sv.Lane = 0
+		sv.Nonce = 10
+		sv.Merges = []Merge{{Lane: 1, Nonce: 10}, {Lane: 2, Nonce: 1}}
+		rt.SetCaller(st1.From, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(st1.From, st1.To)
+		rt.ExpectVerifySignature(*sv.Signature, actor.payee, voucherBytes(t, sv), nil)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "outdated nonce", func() {
+			rt.Call(actor.UpdateChannelState, &UpdateChannelStateParams{Sv: *sv})
+		})
+		rt.Verify()
+		verifyState(t, rt, 3, st1)
+	})
+}
+
 func TestActor_UpdateChannelStateExtra(t *testing.T) {
 	mnum := builtin.MethodsPaych.UpdateChannelState
 	fakeParams := cbg.CborBoolTrue
@@ -818,13 +878,42 @@ func TestActor_Collect(t *testing.T) {
 
 		rt.ExpectSend(st.To, builtin.MethodSend, nil, st.ToSend, nil, exitcode.Ok)
 
+		rt.ExpectSend(builtin.InitActorAddr, builtin.MethodsInit.TombstoneActor, nil, big.Zero(), nil, exitcode.Ok)
+
 		// Collect.
 		rt.SetCaller(st.From, builtin.AccountActorCodeID)
 		rt.ExpectValidateCallerAddr(st.From, st.To)
 		rt.ExpectDeleteActor(st.From)
+		// The remaining balance returns to the sender as the channel is deleted.
+		remaining := big.Sub(rt.Balance(), st.ToSend)
+		rt.ExpectNetTransfer(rt.Receiver(), st.From, remaining)
 		res := rt.Call(actor.Collect, nil)
 		assert.Nil(t, res)
-		actor.checkState(rt)
+		assert.Equal(t, big.Zero(), rt.Balance())
+		rt.Verify()
+	})
+
+	t.Run("fails if address cannot be tombstoned", func(t *testing.T) {
+		rt, actor, _ := requireCreateChannelWithLanes(t, context.Background(), 1)
+		rt.SetEpoch(10)
+		var st State
+		rt.GetState(&st)
+
+		rt.SetCaller(st.From, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(st.From, st.To)
+		rt.Call(actor.Settle, nil)
+		rt.GetState(&st)
+		rt.SetEpoch(st.SettlingAt + 1)
+
+		rt.ExpectSend(st.To, builtin.MethodSend, nil, st.ToSend, nil, exitcode.Ok)
+		rt.ExpectSend(builtin.InitActorAddr, builtin.MethodsInit.TombstoneActor, nil, big.Zero(), nil, exitcode.ErrIllegalState)
+
+		rt.SetCaller(st.From, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(st.From, st.To)
+		rt.ExpectAbort(exitcode.ErrIllegalState, func() {
+			rt.Call(actor.Collect, nil)
+		})
+		rt.Verify()
 	})
 
 	testCases := []struct {
@@ -866,6 +955,97 @@ func TestActor_Collect(t *testing.T) {
 	}
 }
 
+func TestActor_PartialCollect(t *testing.T) {
+	t.Run("pays out redeemed amounts without settling", func(t *testing.T) {
+		rt, actor, sv := requireCreateChannelWithLanes(t, context.Background(), 2)
+		var st State
+		rt.GetState(&st)
+		balance := rt.Balance()
+
+		collected := actor.partialCollect(rt, st.To, st.ToSend)
+		assert.Equal(t, st.ToSend, collected)
+		assert.Equal(t, big.Sub(balance, collected), rt.Balance())
+
+		// Redeeming a later voucher on a lane pays only the increase over the lane's redeemed amount.
+		sv.Amount = big.Add(sv.Amount, big.NewInt(5))
+		actor.updateChannelState(rt, st.From, sv)
+		rt.GetState(&st)
+		assert.Equal(t, big.NewInt(5), st.ToSend)
+
+		collected = actor.partialCollect(rt, st.From, big.NewInt(5))
+		assert.Equal(t, big.NewInt(5), collected)
+		rt.GetState(&st)
+		assert.Equal(t, big.Zero(), st.ToSend)
+		assert.Equal(t, abi.ChainEpoch(0), st.SettlingAt)
+		actor.checkState(rt)
+	})
+
+	t.Run("sends nothing when nothing is redeemed", func(t *testing.T) {
+		rt, actor, _ := requireCreateChannelWithLanes(t, context.Background(), 0)
+		var st State
+		rt.GetState(&st)
+
+		collected := actor.partialCollect(rt, st.To, big.Zero())
+		assert.Equal(t, big.Zero(), collected)
+		actor.checkState(rt)
+	})
+
+	t.Run("fails before min settle height", func(t *testing.T) {
+		rt, actor, sv := requireCreateChannelWithLanes(t, context.Background(), 1)
+		var st State
+		rt.GetState(&st)
+
+		sv.MinSettleHeight = rt.Epoch() + 10
+		actor.updateChannelState(rt, st.From, sv)
+
+		rt.SetCaller(st.To, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(st.From, st.To)
+		rt.ExpectAbortContainsMessage(exitcode.ErrForbidden, "min settle height", func() {
+			rt.Call(actor.PartialCollect, nil)
+		})
+		rt.Verify()
+
+		rt.SetEpoch(sv.MinSettleHeight)
+		actor.partialCollect(rt, st.To, st.ToSend)
+		actor.checkState(rt)
+	})
+
+	t.Run("fails once settled", func(t *testing.T) {
+		rt, actor, _ := requireCreateChannelWithLanes(t, context.Background(), 1)
+		var st State
+		rt.GetState(&st)
+
+		rt.SetCaller(st.From, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(st.From, st.To)
+		rt.Call(actor.Settle, nil)
+		rt.GetState(&st)
+
+		// Partial collection remains possible while the channel is settling.
+		actor.partialCollect(rt, st.To, st.ToSend)
+
+		rt.SetEpoch(st.SettlingAt)
+		rt.SetCaller(st.To, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(st.From, st.To)
+		rt.ExpectAbortContainsMessage(exitcode.ErrForbidden, "use Collect", func() {
+			rt.Call(actor.PartialCollect, nil)
+		})
+		rt.Verify()
+	})
+
+	t.Run("fails when called by a third party", func(t *testing.T) {
+		rt, actor, _ := requireCreateChannelWithLanes(t, context.Background(), 1)
+		var st State
+		rt.GetState(&st)
+
+		rt.SetCaller(tutil.NewIDAddr(t, 999), builtin.MultisigActorCodeID)
+		rt.ExpectValidateCallerAddr(st.From, st.To)
+		rt.ExpectAbort(exitcode.SysErrForbidden, func() {
+			rt.Call(actor.PartialCollect, nil)
+		})
+		rt.Verify()
+	})
+}
+
 type pcActorHarness struct {
 	Actor
 	t testing.TB
@@ -893,7 +1073,7 @@ func requireCreateChannelWithLanes(t *testing.T, ctx context.Context, numLanes i
 	curEpoch := 2
 	hasher := func(data []byte) [32]byte { return [32]byte{} }
 
-	builder := mock.NewBuilder(ctx, paychAddr).
+	builder := mock.NewBuilder(ctx, paychAddr).WithStateInvariants(CheckStateInvariants).
 		WithBalance(balance, received).
 		WithEpoch(abi.ChainEpoch(curEpoch)).
 		WithCaller(builtin.InitActorAddr, builtin.InitActorCodeID).
@@ -937,6 +1117,31 @@ func requireAddNewLane(t *testing.T, rt *mock.Runtime, actor *pcActorHarness, pa
 	return &sv
 }
 
+// Redeems a voucher signed by the other party to the caller.
+func (h *pcActorHarness) updateChannelState(rt *mock.Runtime, caller addr.Address, sv *SignedVoucher) {
+	signer := h.payee
+	if caller == h.payee {
+		signer = h.payer
+	}
+	rt.SetCaller(caller, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAddr(h.payer, h.payee)
+	rt.ExpectVerifySignature(*sv.Signature, signer, voucherBytes(h.t, sv), nil)
+	ret := rt.Call(h.UpdateChannelState, &UpdateChannelStateParams{Sv: *sv})
+	require.Nil(h.t, ret)
+	rt.Verify()
+}
+
+func (h *pcActorHarness) partialCollect(rt *mock.Runtime, caller addr.Address, expected abi.TokenAmount) abi.TokenAmount {
+	rt.SetCaller(caller, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAddr(h.payer, h.payee)
+	if !expected.IsZero() {
+		rt.ExpectSend(h.payee, builtin.MethodSend, nil, expected, nil, exitcode.Ok)
+	}
+	ret := rt.Call(h.PartialCollect, nil).(*PartialCollectReturn)
+	rt.Verify()
+	return ret.Collected
+}
+
 func (h *pcActorHarness) constructAndVerify(t *testing.T, rt *mock.Runtime, sender, receiver addr.Address) {
 	params := &ConstructorParams{To: receiver, From: sender}
 
@@ -989,7 +1194,7 @@ func verifyState(t *testing.T, rt *mock.Runtime, expLanes int, expectedState Sta
 	}
 }
 
-func voucherBytes(t *testing.T, sv *SignedVoucher) []byte {
+func voucherBytes(t testing.TB, sv *SignedVoucher) []byte {
 	bytes, err := sv.SigningBytes()
 	require.NoError(t, err)
 	return bytes
@@ -9,7 +9,7 @@ import (
 // Maximum number of lanes in a channel.
 const MaxLane = math.MaxInt64
 
-const SettleDelay = builtin.EpochsInHour * 12
+var SettleDelay = builtin.EpochsInHour * 12 // PARAM_SPEC
 
 // Maximum size of a secret that can be submitted with a payment channel update (in bytes).
 const MaxSecretSize = 256
@@ -18,7 +18,7 @@ func CheckStateInvariants(st *State, store adt.Store, balance abi.TokenAmount) (
 
 	acc.Require(st.From.Protocol() == address.ID, "from address is not ID address %v", st.From)
 	acc.Require(st.To.Protocol() == address.ID, "to address is not ID address %v", st.To)
-	acc.Require(st.SettlingAt >= st.MinSettleHeight,
+	acc.Require(st.SettlingAt == 0 || st.SettlingAt >= st.MinSettleHeight,
 		"channel is setting at epoch %d before min settle height %d", st.SettlingAt, st.MinSettleHeight)
 
 	lanes, err := adt.AsArray(store, st.LaneStates)
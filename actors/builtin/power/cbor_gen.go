@@ -8,13 +8,14 @@ import (
 
 	address "github.com/filecoin-project/go-address"
 	abi "github.com/filecoin-project/go-state-types/abi"
+	acbor "github.com/filecoin-project/specs-actors/v2/actors/util/cbor"
 	cbg "github.com/whyrusleeping/cbor-gen"
 	xerrors "golang.org/x/xerrors"
 )
 
 var _ = xerrors.Errorf
 
-var lengthBufState = []byte{143}
+var lengthBufState = []byte{145}
 
 func (t *State) MarshalCBOR(w io.Writer) error {
 	if t == nil {
@@ -72,6 +73,16 @@ func (t *State) MarshalCBOR(w io.Writer) error {
 		return err
 	}
 
+	// t.ThisEpochRawBytePowerSmoothed (smoothing.FilterEstimate) (struct)
+	if err := t.ThisEpochRawBytePowerSmoothed.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.ThisEpochPledgeCollateralSmoothed (smoothing.FilterEstimate) (struct)
+	if err := t.ThisEpochPledgeCollateralSmoothed.MarshalCBOR(w); err != nil {
+		return err
+	}
+
 	// t.MinerCount (int64) (int64)
 	if t.MinerCount >= 0 {
 		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.MinerCount)); err != nil {
@@ -140,14 +151,14 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "power.State", "")
 	}
 	if maj != cbg.MajArray {
-		return fmt.Errorf("cbor input should be of type array")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "power.State", "")
 	}
 
-	if extra != 15 {
-		return fmt.Errorf("cbor input had wrong number of fields")
+	if extra != 17 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "power.State", "")
 	}
 
 	// t.TotalRawBytePower (big.Int) (struct)
@@ -155,7 +166,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.TotalRawBytePower.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.TotalRawBytePower: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.TotalRawBytePower: %w", err), "power.State", "TotalRawBytePower")
 		}
 
 	}
@@ -164,7 +175,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.TotalBytesCommitted.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.TotalBytesCommitted: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.TotalBytesCommitted: %w", err), "power.State", "TotalBytesCommitted")
 		}
 
 	}
@@ -173,7 +184,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.TotalQualityAdjPower.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.TotalQualityAdjPower: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.TotalQualityAdjPower: %w", err), "power.State", "TotalQualityAdjPower")
 		}
 
 	}
@@ -182,7 +193,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.TotalQABytesCommitted.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.TotalQABytesCommitted: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.TotalQABytesCommitted: %w", err), "power.State", "TotalQABytesCommitted")
 		}
 
 	}
@@ -191,7 +202,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.TotalPledgeCollateral.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.TotalPledgeCollateral: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.TotalPledgeCollateral: %w", err), "power.State", "TotalPledgeCollateral")
 		}
 
 	}
@@ -200,7 +211,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.ThisEpochRawBytePower.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.ThisEpochRawBytePower: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.ThisEpochRawBytePower: %w", err), "power.State", "ThisEpochRawBytePower")
 		}
 
 	}
@@ -209,7 +220,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.ThisEpochQualityAdjPower.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.ThisEpochQualityAdjPower: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.ThisEpochQualityAdjPower: %w", err), "power.State", "ThisEpochQualityAdjPower")
 		}
 
 	}
@@ -218,7 +229,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.ThisEpochPledgeCollateral.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.ThisEpochPledgeCollateral: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.ThisEpochPledgeCollateral: %w", err), "power.State", "ThisEpochPledgeCollateral")
 		}
 
 	}
@@ -227,7 +238,25 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.ThisEpochQAPowerSmoothed.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.ThisEpochQAPowerSmoothed: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.ThisEpochQAPowerSmoothed: %w", err), "power.State", "ThisEpochQAPowerSmoothed")
+		}
+
+	}
+	// t.ThisEpochRawBytePowerSmoothed (smoothing.FilterEstimate) (struct)
+
+	{
+
+		if err := t.ThisEpochRawBytePowerSmoothed.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.ThisEpochRawBytePowerSmoothed: %w", err), "power.State", "ThisEpochRawBytePowerSmoothed")
+		}
+
+	}
+	// t.ThisEpochPledgeCollateralSmoothed (smoothing.FilterEstimate) (struct)
+
+	{
+
+		if err := t.ThisEpochPledgeCollateralSmoothed.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.ThisEpochPledgeCollateralSmoothed: %w", err), "power.State", "ThisEpochPledgeCollateralSmoothed")
 		}
 
 	}
@@ -236,22 +265,22 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 		var extraI int64
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "power.State", "MinerCount")
 		}
 		switch maj {
 		case cbg.MajUnsignedInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 positive overflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "power.State", "MinerCount")
 			}
 		case cbg.MajNegativeInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 negative oveflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "power.State", "MinerCount")
 			}
 			extraI = -1 - extraI
 		default:
-			return fmt.Errorf("wrong type for int64 field: %d", maj)
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "power.State", "MinerCount")
 		}
 
 		t.MinerCount = int64(extraI)
@@ -261,22 +290,22 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 		var extraI int64
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "power.State", "MinerAboveMinPowerCount")
 		}
 		switch maj {
 		case cbg.MajUnsignedInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 positive overflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "power.State", "MinerAboveMinPowerCount")
 			}
 		case cbg.MajNegativeInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 negative oveflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "power.State", "MinerAboveMinPowerCount")
 			}
 			extraI = -1 - extraI
 		default:
-			return fmt.Errorf("wrong type for int64 field: %d", maj)
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "power.State", "MinerAboveMinPowerCount")
 		}
 
 		t.MinerAboveMinPowerCount = int64(extraI)
@@ -287,7 +316,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 		c, err := cbg.ReadCid(br)
 		if err != nil {
-			return xerrors.Errorf("failed to read cid field t.CronEventQueue: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read cid field t.CronEventQueue: %w", err), "power.State", "CronEventQueue")
 		}
 
 		t.CronEventQueue = c
@@ -298,22 +327,22 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 		var extraI int64
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "power.State", "FirstCronEpoch")
 		}
 		switch maj {
 		case cbg.MajUnsignedInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 positive overflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "power.State", "FirstCronEpoch")
 			}
 		case cbg.MajNegativeInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 negative oveflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "power.State", "FirstCronEpoch")
 			}
 			extraI = -1 - extraI
 		default:
-			return fmt.Errorf("wrong type for int64 field: %d", maj)
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "power.State", "FirstCronEpoch")
 		}
 
 		t.FirstCronEpoch = abi.ChainEpoch(extraI)
@@ -324,7 +353,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 		c, err := cbg.ReadCid(br)
 		if err != nil {
-			return xerrors.Errorf("failed to read cid field t.Claims: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read cid field t.Claims: %w", err), "power.State", "Claims")
 		}
 
 		t.Claims = c
@@ -336,16 +365,16 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 		b, err := br.ReadByte()
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "power.State", "ProofValidationBatch")
 		}
 		if b != cbg.CborNull[0] {
 			if err := br.UnreadByte(); err != nil {
-				return err
+				return acbor.WrapDecodeError(err, "power.State", "ProofValidationBatch")
 			}
 
 			c, err := cbg.ReadCid(br)
 			if err != nil {
-				return xerrors.Errorf("failed to read cid field t.ProofValidationBatch: %w", err)
+				return acbor.WrapDecodeError(xerrors.Errorf("failed to read cid field t.ProofValidationBatch: %w", err), "power.State", "ProofValidationBatch")
 			}
 
 			t.ProofValidationBatch = &c
@@ -399,14 +428,14 @@ func (t *Claim) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "power.Claim", "")
 	}
 	if maj != cbg.MajArray {
-		return fmt.Errorf("cbor input should be of type array")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "power.Claim", "")
 	}
 
 	if extra != 3 {
-		return fmt.Errorf("cbor input had wrong number of fields")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "power.Claim", "")
 	}
 
 	// t.SealProofType (abi.RegisteredSealProof) (int64)
@@ -414,22 +443,22 @@ func (t *Claim) UnmarshalCBOR(r io.Reader) error {
 		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 		var extraI int64
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "power.Claim", "SealProofType")
 		}
 		switch maj {
 		case cbg.MajUnsignedInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 positive overflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "power.Claim", "SealProofType")
 			}
 		case cbg.MajNegativeInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 negative oveflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "power.Claim", "SealProofType")
 			}
 			extraI = -1 - extraI
 		default:
-			return fmt.Errorf("wrong type for int64 field: %d", maj)
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "power.Claim", "SealProofType")
 		}
 
 		t.SealProofType = abi.RegisteredSealProof(extraI)
@@ -439,7 +468,7 @@ func (t *Claim) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.RawBytePower.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.RawBytePower: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.RawBytePower: %w", err), "power.Claim", "RawBytePower")
 		}
 
 	}
@@ -448,7 +477,7 @@ func (t *Claim) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.QualityAdjPower.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.QualityAdjPower: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.QualityAdjPower: %w", err), "power.Claim", "QualityAdjPower")
 		}
 
 	}
@@ -496,14 +525,14 @@ func (t *CronEvent) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "power.CronEvent", "")
 	}
 	if maj != cbg.MajArray {
-		return fmt.Errorf("cbor input should be of type array")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "power.CronEvent", "")
 	}
 
 	if extra != 2 {
-		return fmt.Errorf("cbor input had wrong number of fields")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "power.CronEvent", "")
 	}
 
 	// t.MinerAddr (address.Address) (struct)
@@ -511,7 +540,7 @@ func (t *CronEvent) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.MinerAddr.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.MinerAddr: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.MinerAddr: %w", err), "power.CronEvent", "MinerAddr")
 		}
 
 	}
@@ -519,14 +548,14 @@ func (t *CronEvent) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "power.CronEvent", "CallbackPayload")
 	}
 
 	if extra > cbg.ByteArrayMaxLen {
-		return fmt.Errorf("t.CallbackPayload: byte array too large (%d)", extra)
+		return acbor.WrapDecodeError(fmt.Errorf("t.CallbackPayload: byte array too large (%d)", extra), "power.CronEvent", "CallbackPayload")
 	}
 	if maj != cbg.MajByteString {
-		return fmt.Errorf("expected byte array")
+		return acbor.WrapDecodeError(fmt.Errorf("expected byte array"), "power.CronEvent", "CallbackPayload")
 	}
 
 	if extra > 0 {
@@ -534,7 +563,7 @@ func (t *CronEvent) UnmarshalCBOR(r io.Reader) error {
 	}
 
 	if _, err := io.ReadFull(br, t.CallbackPayload[:]); err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "power.CronEvent", "CallbackPayload")
 	}
 	return nil
 }
@@ -580,14 +609,14 @@ func (t *CurrentTotalPowerReturn) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "power.CurrentTotalPowerReturn", "")
 	}
 	if maj != cbg.MajArray {
-		return fmt.Errorf("cbor input should be of type array")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "power.CurrentTotalPowerReturn", "")
 	}
 
 	if extra != 4 {
-		return fmt.Errorf("cbor input had wrong number of fields")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "power.CurrentTotalPowerReturn", "")
 	}
 
 	// t.RawBytePower (big.Int) (struct)
@@ -595,7 +624,7 @@ func (t *CurrentTotalPowerReturn) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.RawBytePower.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.RawBytePower: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.RawBytePower: %w", err), "power.CurrentTotalPowerReturn", "RawBytePower")
 		}
 
 	}
@@ -604,7 +633,7 @@ func (t *CurrentTotalPowerReturn) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.QualityAdjPower.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.QualityAdjPower: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.QualityAdjPower: %w", err), "power.CurrentTotalPowerReturn", "QualityAdjPower")
 		}
 
 	}
@@ -613,7 +642,7 @@ func (t *CurrentTotalPowerReturn) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.PledgeCollateral.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.PledgeCollateral: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.PledgeCollateral: %w", err), "power.CurrentTotalPowerReturn", "PledgeCollateral")
 		}
 
 	}
@@ -622,7 +651,83 @@ func (t *CurrentTotalPowerReturn) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.QualityAdjPowerSmoothed.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.QualityAdjPowerSmoothed: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.QualityAdjPowerSmoothed: %w", err), "power.CurrentTotalPowerReturn", "QualityAdjPowerSmoothed")
+		}
+
+	}
+	return nil
+}
+
+var lengthBufCurrentSmoothedEstimatesReturn = []byte{131}
+
+func (t *CurrentSmoothedEstimatesReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufCurrentSmoothedEstimatesReturn); err != nil {
+		return err
+	}
+
+	// t.RawBytePowerSmoothed (smoothing.FilterEstimate) (struct)
+	if err := t.RawBytePowerSmoothed.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.QualityAdjPowerSmoothed (smoothing.FilterEstimate) (struct)
+	if err := t.QualityAdjPowerSmoothed.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.PledgeCollateralSmoothed (smoothing.FilterEstimate) (struct)
+	if err := t.PledgeCollateralSmoothed.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *CurrentSmoothedEstimatesReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = CurrentSmoothedEstimatesReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "power.CurrentSmoothedEstimatesReturn", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "power.CurrentSmoothedEstimatesReturn", "")
+	}
+
+	if extra != 3 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "power.CurrentSmoothedEstimatesReturn", "")
+	}
+
+	// t.RawBytePowerSmoothed (smoothing.FilterEstimate) (struct)
+
+	{
+
+		if err := t.RawBytePowerSmoothed.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.RawBytePowerSmoothed: %w", err), "power.CurrentSmoothedEstimatesReturn", "RawBytePowerSmoothed")
+		}
+
+	}
+	// t.QualityAdjPowerSmoothed (smoothing.FilterEstimate) (struct)
+
+	{
+
+		if err := t.QualityAdjPowerSmoothed.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.QualityAdjPowerSmoothed: %w", err), "power.CurrentSmoothedEstimatesReturn", "QualityAdjPowerSmoothed")
+		}
+
+	}
+	// t.PledgeCollateralSmoothed (smoothing.FilterEstimate) (struct)
+
+	{
+
+		if err := t.PledgeCollateralSmoothed.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.PledgeCollateralSmoothed: %w", err), "power.CurrentSmoothedEstimatesReturn", "PledgeCollateralSmoothed")
 		}
 
 	}
@@ -722,14 +827,14 @@ func (t *MinerConstructorParams) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "power.MinerConstructorParams", "")
 	}
 	if maj != cbg.MajArray {
-		return fmt.Errorf("cbor input should be of type array")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "power.MinerConstructorParams", "")
 	}
 
 	if extra != 6 {
-		return fmt.Errorf("cbor input had wrong number of fields")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "power.MinerConstructorParams", "")
 	}
 
 	// t.OwnerAddr (address.Address) (struct)
@@ -737,7 +842,7 @@ func (t *MinerConstructorParams) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.OwnerAddr.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.OwnerAddr: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.OwnerAddr: %w", err), "power.MinerConstructorParams", "OwnerAddr")
 		}
 
 	}
@@ -746,7 +851,7 @@ func (t *MinerConstructorParams) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.WorkerAddr.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.WorkerAddr: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.WorkerAddr: %w", err), "power.MinerConstructorParams", "WorkerAddr")
 		}
 
 	}
@@ -754,15 +859,15 @@ func (t *MinerConstructorParams) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "power.MinerConstructorParams", "ControlAddrs")
 	}
 
 	if extra > cbg.MaxLength {
-		return fmt.Errorf("t.ControlAddrs: array too large (%d)", extra)
+		return acbor.WrapDecodeError(fmt.Errorf("t.ControlAddrs: array too large (%d)", extra), "power.MinerConstructorParams", "ControlAddrs")
 	}
 
 	if maj != cbg.MajArray {
-		return fmt.Errorf("expected cbor array")
+		return acbor.WrapDecodeError(fmt.Errorf("expected cbor array"), "power.MinerConstructorParams", "ControlAddrs")
 	}
 
 	if extra > 0 {
@@ -773,7 +878,7 @@ func (t *MinerConstructorParams) UnmarshalCBOR(r io.Reader) error {
 
 		var v address.Address
 		if err := v.UnmarshalCBOR(br); err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "power.MinerConstructorParams", fmt.Sprintf("ControlAddrs[%d]", i))
 		}
 
 		t.ControlAddrs[i] = v
@@ -784,22 +889,22 @@ func (t *MinerConstructorParams) UnmarshalCBOR(r io.Reader) error {
 		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 		var extraI int64
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "power.MinerConstructorParams", "SealProofType")
 		}
 		switch maj {
 		case cbg.MajUnsignedInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 positive overflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "power.MinerConstructorParams", "SealProofType")
 			}
 		case cbg.MajNegativeInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 negative oveflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "power.MinerConstructorParams", "SealProofType")
 			}
 			extraI = -1 - extraI
 		default:
-			return fmt.Errorf("wrong type for int64 field: %d", maj)
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "power.MinerConstructorParams", "SealProofType")
 		}
 
 		t.SealProofType = abi.RegisteredSealProof(extraI)
@@ -808,14 +913,14 @@ func (t *MinerConstructorParams) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "power.MinerConstructorParams", "PeerId")
 	}
 
 	if extra > cbg.ByteArrayMaxLen {
-		return fmt.Errorf("t.PeerId: byte array too large (%d)", extra)
+		return acbor.WrapDecodeError(fmt.Errorf("t.PeerId: byte array too large (%d)", extra), "power.MinerConstructorParams", "PeerId")
 	}
 	if maj != cbg.MajByteString {
-		return fmt.Errorf("expected byte array")
+		return acbor.WrapDecodeError(fmt.Errorf("expected byte array"), "power.MinerConstructorParams", "PeerId")
 	}
 
 	if extra > 0 {
@@ -823,21 +928,21 @@ func (t *MinerConstructorParams) UnmarshalCBOR(r io.Reader) error {
 	}
 
 	if _, err := io.ReadFull(br, t.PeerId[:]); err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "power.MinerConstructorParams", "PeerId")
 	}
 	// t.Multiaddrs ([][]uint8) (slice)
 
 	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "power.MinerConstructorParams", "Multiaddrs")
 	}
 
 	if extra > cbg.MaxLength {
-		return fmt.Errorf("t.Multiaddrs: array too large (%d)", extra)
+		return acbor.WrapDecodeError(fmt.Errorf("t.Multiaddrs: array too large (%d)", extra), "power.MinerConstructorParams", "Multiaddrs")
 	}
 
 	if maj != cbg.MajArray {
-		return fmt.Errorf("expected cbor array")
+		return acbor.WrapDecodeError(fmt.Errorf("expected cbor array"), "power.MinerConstructorParams", "Multiaddrs")
 	}
 
 	if extra > 0 {
@@ -852,14 +957,14 @@ func (t *MinerConstructorParams) UnmarshalCBOR(r io.Reader) error {
 
 			maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
 			if err != nil {
-				return err
+				return acbor.WrapDecodeError(err, "power.MinerConstructorParams", fmt.Sprintf("Multiaddrs[%d]", i))
 			}
 
 			if extra > cbg.ByteArrayMaxLen {
-				return fmt.Errorf("t.Multiaddrs[i]: byte array too large (%d)", extra)
+				return acbor.WrapDecodeError(fmt.Errorf("t.Multiaddrs[i]: byte array too large (%d)", extra), "power.MinerConstructorParams", fmt.Sprintf("Multiaddrs[%d]", i))
 			}
 			if maj != cbg.MajByteString {
-				return fmt.Errorf("expected byte array")
+				return acbor.WrapDecodeError(fmt.Errorf("expected byte array"), "power.MinerConstructorParams", fmt.Sprintf("Multiaddrs[%d]", i))
 			}
 
 			if extra > 0 {
@@ -867,10 +972,34 @@ func (t *MinerConstructorParams) UnmarshalCBOR(r io.Reader) error {
 			}
 
 			if _, err := io.ReadFull(br, t.Multiaddrs[i][:]); err != nil {
-				return err
+				return acbor.WrapDecodeError(err, "power.MinerConstructorParams", fmt.Sprintf("Multiaddrs[%d]", i))
 			}
 		}
 	}
 
 	return nil
 }
+
+func (t *State) MaxEncodedLength() int64 {
+	return 3402
+}
+
+func (t *Claim) MaxEncodedLength() int64 {
+	return 270
+}
+
+func (t *CronEvent) MaxEncodedLength() int64 {
+	return 2097224
+}
+
+func (t *CurrentTotalPowerReturn) MaxEncodedLength() int64 {
+	return 652
+}
+
+func (t *CurrentSmoothedEstimatesReturn) MaxEncodedLength() int64 {
+	return 784
+}
+
+func (t *MinerConstructorParams) MaxEncodedLength() int64 {
+	return 17182548121
+}
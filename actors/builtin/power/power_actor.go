@@ -13,6 +13,7 @@ import (
 	rtt "github.com/filecoin-project/go-state-types/rt"
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
 	initact "github.com/filecoin-project/specs-actors/v2/actors/builtin/init"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/reward"
 	"github.com/filecoin-project/specs-actors/v2/actors/runtime"
 	"github.com/filecoin-project/specs-actors/v2/actors/runtime/proof"
 	"github.com/filecoin-project/specs-actors/v2/actors/util/adt"
@@ -27,6 +28,12 @@ const (
 	ErrTooManyProveCommits = exitcode.FirstActorSpecificExitCode + iota
 )
 
+func init() {
+	builtin.RegisterExitCodeNames(builtin.StoragePowerActorCodeID, map[exitcode.ExitCode]string{
+		ErrTooManyProveCommits: "ErrTooManyProveCommits",
+	})
+}
+
 type Actor struct{}
 
 func (a Actor) Exports() []interface{} {
@@ -40,6 +47,7 @@ func (a Actor) Exports() []interface{} {
 		7:                         nil, // deprecated
 		8:                         a.SubmitPoRepForBulkVerify,
 		9:                         a.CurrentTotalPower,
+		10:                        a.CurrentSmoothedEstimates,
 	}
 }
 
@@ -170,6 +178,7 @@ func (a Actor) UpdateClaimedPower(rt Runtime, params *UpdateClaimedPowerParams)
 		st.Claims, err = claims.Root()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush claims")
 	})
+	builtin.EmitPowerUpdated(rt, minerAddr, params.RawByteDelta, params.QualityAdjustedDelta)
 	return nil
 }
 
@@ -223,18 +232,11 @@ func (a Actor) OnEpochTickEnd(rt Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
 		st.ThisEpochQualityAdjPower = qaPower
 		st.ThisEpochRawBytePower = rawBytePower
 		// we can now assume delta is one since cron is invoked on every epoch.
-		st.updateSmoothedEstimate(abi.ChainEpoch(1))
+		st.updateSmoothedEstimate(abi.ChainEpoch(1), QAPowerFilterSchedule.ForVersion(rt.NetworkVersion()))
 	})
 
 	// update network KPI in RewardActor
-	code := rt.Send(
-		builtin.RewardActorAddr,
-		builtin.MethodsReward.UpdateNetworkKPI,
-		&st.ThisEpochRawBytePower,
-		abi.NewTokenAmount(0),
-		&builtin.Discard{},
-	)
-	builtin.RequireSuccess(rt, code, "failed to update network KPI with Reward Actor")
+	reward.NewClient(rt).UpdateNetworkKPI(st.ThisEpochRawBytePower)
 
 	return nil
 }
@@ -317,10 +319,36 @@ func (a Actor) CurrentTotalPower(rt Runtime, _ *abi.EmptyValue) *CurrentTotalPow
 	}
 }
 
+type CurrentSmoothedEstimatesReturn struct {
+	RawBytePowerSmoothed     smoothing.FilterEstimate
+	QualityAdjPowerSmoothed  smoothing.FilterEstimate
+	PledgeCollateralSmoothed smoothing.FilterEstimate
+}
+
+// Returns the smoothed estimates of total raw byte power, quality-adjusted power and pledge collateral,
+// as updated in the cron tick before this epoch.
+func (a Actor) CurrentSmoothedEstimates(rt Runtime, _ *abi.EmptyValue) *CurrentSmoothedEstimatesReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+	var st State
+	rt.StateReadonly(&st)
+
+	return &CurrentSmoothedEstimatesReturn{
+		RawBytePowerSmoothed:     st.ThisEpochRawBytePowerSmoothed,
+		QualityAdjPowerSmoothed:  st.ThisEpochQAPowerSmoothed,
+		PledgeCollateralSmoothed: st.ThisEpochPledgeCollateralSmoothed,
+	}
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // Method utility functions
 ////////////////////////////////////////////////////////////////////////////////
 
+// Identifies the cron events delivered to a miner in one tick.
+type cronEventKey struct {
+	miner   addr.Address
+	payload string
+}
+
 func validateMinerHasClaim(rt Runtime, st State, minerAddr addr.Address) {
 	claims, err := adt.AsMap(adt.AsStore(rt), st.Claims)
 	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load claims")
@@ -429,6 +457,11 @@ func (a Actor) processDeferredCronEvents(rt Runtime) {
 		claims, err := adt.AsMap(adt.AsStore(rt), st.Claims)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load claims")
 
+		// Every due epoch is drained, however many there are after null rounds, since a miner's deadline cron
+		// handler works from the current epoch and would skip the deadlines of events delivered late.
+		// A miner's events with the same payload are delivered once, bounding the sends to each miner by the
+		// kinds of event it enrolls rather than their number, without delaying any.
+		delivered := map[cronEventKey]struct{}{}
 		for epoch := st.FirstCronEpoch; epoch <= rtEpoch; epoch++ {
 			epochEvents, err := loadCronEvents(events, epoch)
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load cron events at %v", epoch)
@@ -441,6 +474,11 @@ func (a Actor) processDeferredCronEvents(rt Runtime) {
 					rt.Log(rtt.WARN, "skipping cron event for unknown miner %v", evt.MinerAddr)
 					continue
 				}
+				key := cronEventKey{evt.MinerAddr, string(evt.CallbackPayload)}
+				if _, ok := delivered[key]; ok {
+					continue
+				}
+				delivered[key] = struct{}{}
 				cronEvents = append(cronEvents, evt)
 			}
 
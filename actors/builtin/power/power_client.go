@@ -0,0 +1,48 @@
+package power
+
+import (
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v2/actors/runtime"
+	"github.com/filecoin-project/specs-actors/v2/actors/runtime/proof"
+)
+
+// A typed client for calls from other actors to the storage power actor.
+// Each method aborts with the power actor's exit code if the call fails.
+type Client struct {
+	rt runtime.Runtime
+}
+
+func NewClient(rt runtime.Runtime) Client {
+	return Client{rt: rt}
+}
+
+func (c Client) CurrentTotalPower() *CurrentTotalPowerReturn {
+	var ret CurrentTotalPowerReturn
+	builtin.SendReadOnlyAndUnmarshal(c.rt, builtin.StoragePowerActorAddr, builtin.MethodsPower.CurrentTotalPower, nil, &ret)
+	return &ret
+}
+
+func (c Client) CurrentSmoothedEstimates() *CurrentSmoothedEstimatesReturn {
+	var ret CurrentSmoothedEstimatesReturn
+	builtin.SendReadOnlyAndUnmarshal(c.rt, builtin.StoragePowerActorAddr, builtin.MethodsPower.CurrentSmoothedEstimates, nil, &ret)
+	return &ret
+}
+
+func (c Client) UpdateClaimedPower(params *UpdateClaimedPowerParams) {
+	builtin.SendAndUnmarshal(c.rt, builtin.StoragePowerActorAddr, builtin.MethodsPower.UpdateClaimedPower, params, big.Zero(), nil)
+}
+
+func (c Client) UpdatePledgeTotal(pledgeDelta abi.TokenAmount) {
+	builtin.SendAndUnmarshal(c.rt, builtin.StoragePowerActorAddr, builtin.MethodsPower.UpdatePledgeTotal, &pledgeDelta, big.Zero(), nil)
+}
+
+func (c Client) EnrollCronEvent(params *EnrollCronEventParams) {
+	builtin.SendAndUnmarshal(c.rt, builtin.StoragePowerActorAddr, builtin.MethodsPower.EnrollCronEvent, params, big.Zero(), nil)
+}
+
+func (c Client) SubmitPoRepForBulkVerify(sealInfo *proof.SealVerifyInfo) {
+	builtin.SendAndUnmarshal(c.rt, builtin.StoragePowerActorAddr, builtin.MethodsPower.SubmitPoRepForBulkVerify, sealInfo, big.Zero(), nil)
+}
@@ -24,6 +24,10 @@ var InitialQAPowerEstimatePosition = big.Mul(big.NewInt(750_000), big.NewInt(1<<
 // max chain throughput in bytes per epoch = 120 ProveCommits / epoch = 3,840 GiB
 var InitialQAPowerEstimateVelocity = big.Mul(big.NewInt(3_840), big.NewInt(1<<30))
 
+// Genesis power is committed capacity, so raw byte power is initially estimated the same as quality-adjusted power.
+var InitialRawBytePowerEstimatePosition = InitialQAPowerEstimatePosition
+var InitialRawBytePowerEstimateVelocity = InitialQAPowerEstimateVelocity
+
 type State struct {
 	TotalRawBytePower abi.StoragePower
 	// TotalBytesCommitted includes claims from miners below min power threshold
@@ -39,6 +43,9 @@ type State struct {
 	ThisEpochQualityAdjPower  abi.StoragePower
 	ThisEpochPledgeCollateral abi.TokenAmount
 	ThisEpochQAPowerSmoothed  smoothing.FilterEstimate
+	// Smoothed estimates of raw byte power and pledge collateral, updated alongside ThisEpochQAPowerSmoothed.
+	ThisEpochRawBytePowerSmoothed     smoothing.FilterEstimate
+	ThisEpochPledgeCollateralSmoothed smoothing.FilterEstimate
 
 	MinerCount int64
 	// Number of miners having proven the minimum consensus power.
@@ -75,20 +82,22 @@ type CronEvent struct {
 
 func ConstructState(emptyMapCid, emptyMMapCid cid.Cid) *State {
 	return &State{
-		TotalRawBytePower:         abi.NewStoragePower(0),
-		TotalBytesCommitted:       abi.NewStoragePower(0),
-		TotalQualityAdjPower:      abi.NewStoragePower(0),
-		TotalQABytesCommitted:     abi.NewStoragePower(0),
-		TotalPledgeCollateral:     abi.NewTokenAmount(0),
-		ThisEpochRawBytePower:     abi.NewStoragePower(0),
-		ThisEpochQualityAdjPower:  abi.NewStoragePower(0),
-		ThisEpochPledgeCollateral: abi.NewTokenAmount(0),
-		ThisEpochQAPowerSmoothed:  smoothing.NewEstimate(InitialQAPowerEstimatePosition, InitialQAPowerEstimateVelocity),
-		FirstCronEpoch:            0,
-		CronEventQueue:            emptyMMapCid,
-		Claims:                    emptyMapCid,
-		MinerCount:                0,
-		MinerAboveMinPowerCount:   0,
+		TotalRawBytePower:                 abi.NewStoragePower(0),
+		TotalBytesCommitted:               abi.NewStoragePower(0),
+		TotalQualityAdjPower:              abi.NewStoragePower(0),
+		TotalQABytesCommitted:             abi.NewStoragePower(0),
+		TotalPledgeCollateral:             abi.NewTokenAmount(0),
+		ThisEpochRawBytePower:             abi.NewStoragePower(0),
+		ThisEpochQualityAdjPower:          abi.NewStoragePower(0),
+		ThisEpochPledgeCollateral:         abi.NewTokenAmount(0),
+		ThisEpochQAPowerSmoothed:          smoothing.NewEstimate(InitialQAPowerEstimatePosition, InitialQAPowerEstimateVelocity),
+		ThisEpochRawBytePowerSmoothed:     smoothing.NewEstimate(InitialRawBytePowerEstimatePosition, InitialRawBytePowerEstimateVelocity),
+		ThisEpochPledgeCollateralSmoothed: smoothing.NewEstimate(big.Zero(), big.Zero()),
+		FirstCronEpoch:                    0,
+		CronEventQueue:                    emptyMMapCid,
+		Claims:                            emptyMapCid,
+		MinerCount:                        0,
+		MinerAboveMinPowerCount:           0,
 	}
 }
 
@@ -255,9 +264,10 @@ func (st *State) appendCronEvent(events *adt.Multimap, epoch abi.ChainEpoch, eve
 	return nil
 }
 
-func (st *State) updateSmoothedEstimate(delta abi.ChainEpoch) {
-	filterQAPower := smoothing.LoadFilter(st.ThisEpochQAPowerSmoothed, smoothing.DefaultAlpha, smoothing.DefaultBeta)
-	st.ThisEpochQAPowerSmoothed = filterQAPower.NextEstimate(st.ThisEpochQualityAdjPower, delta)
+func (st *State) updateSmoothedEstimate(delta abi.ChainEpoch, config smoothing.FilterConfig) {
+	st.ThisEpochQAPowerSmoothed = smoothing.NextEstimateWithConfig(st.ThisEpochQAPowerSmoothed, st.ThisEpochQualityAdjPower, delta, config)
+	st.ThisEpochRawBytePowerSmoothed = smoothing.NextEstimateWithConfig(st.ThisEpochRawBytePowerSmoothed, st.ThisEpochRawBytePower, delta, config)
+	st.ThisEpochPledgeCollateralSmoothed = smoothing.NextEstimateWithConfig(st.ThisEpochPledgeCollateralSmoothed, st.ThisEpochPledgeCollateral, delta, config)
 }
 
 func loadCronEvents(mmap *adt.Multimap, epoch abi.ChainEpoch) ([]CronEvent, error) {
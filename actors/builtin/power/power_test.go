@@ -23,6 +23,7 @@ import (
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/power"
 	"github.com/filecoin-project/specs-actors/v2/actors/runtime/proof"
 	"github.com/filecoin-project/specs-actors/v2/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/v2/actors/util/smoothing"
 	"github.com/filecoin-project/specs-actors/v2/support/mock"
 	tutil "github.com/filecoin-project/specs-actors/v2/support/testing"
 )
@@ -37,7 +38,7 @@ func TestConstruction(t *testing.T) {
 	miner := tutil.NewIDAddr(t, 103)
 	actr := tutil.NewActorAddr(t, "actor")
 
-	builder := mock.NewBuilder(context.Background(), builtin.StoragePowerActorAddr).WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
+	builder := mock.NewBuilder(context.Background(), builtin.StoragePowerActorAddr).WithStateInvariants(power.CheckStateInvariants).WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
 
 	t.Run("simple construction", func(t *testing.T) {
 		rt := builder.Build(t)
@@ -162,6 +163,22 @@ func TestUpdateClaimedPowerFailures(t *testing.T) {
 
 		rt.Verify()
 	})
+
+	t.Run("fails if invoked read-only", func(t *testing.T) {
+		rt, ac := basicPowerSetup(t)
+		params := power.UpdateClaimedPowerParams{
+			RawByteDelta:         rawDelta,
+			QualityAdjustedDelta: qaDelta,
+		}
+		rt.SetCaller(miner, builtin.StorageMinerActorCodeID)
+		rt.ExpectValidateCallerType(builtin.StorageMinerActorCodeID)
+
+		rt.ExpectAbort(exitcode.SysErrForbidden, func() {
+			rt.CallReadOnly(ac.UpdateClaimedPower, &params)
+		})
+
+		rt.Verify()
+	})
 }
 
 func TestEnrollCronEpoch(t *testing.T) {
@@ -270,9 +287,9 @@ func TestPowerAndPledgeAccounting(t *testing.T) {
 	smallPowerUnit := big.NewInt(1_000_000)
 	require.True(t, smallPowerUnit.LessThan(powerUnit), "power.ConsensusMinerMinPower has changed requiring update to this test")
 	// Subtests implicitly rely on ConsensusMinerMinMiners = 3
-	require.Equal(t, 4, power.ConsensusMinerMinMiners, "power.ConsensusMinerMinMiners has changed requiring update to this test")
+	require.Equal(t, int64(4), power.ConsensusMinerMinMiners, "power.ConsensusMinerMinMiners has changed requiring update to this test")
 
-	builder := mock.NewBuilder(context.Background(), builtin.StoragePowerActorAddr).
+	builder := mock.NewBuilder(context.Background(), builtin.StoragePowerActorAddr).WithStateInvariants(power.CheckStateInvariants).
 		WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
 
 	t.Run("power & pledge accounted below threshold", func(t *testing.T) {
@@ -503,7 +520,7 @@ func TestUpdatePledgeTotal(t *testing.T) {
 	actor := newHarness(t)
 	owner := tutil.NewIDAddr(t, 101)
 	miner := tutil.NewIDAddr(t, 111)
-	builder := mock.NewBuilder(context.Background(), builtin.StoragePowerActorAddr).
+	builder := mock.NewBuilder(context.Background(), builtin.StoragePowerActorAddr).WithStateInvariants(power.CheckStateInvariants).
 		WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
 
 	t.Run("update pledge total aborts if miner has no claim", func(t *testing.T) {
@@ -526,7 +543,7 @@ func TestCron(t *testing.T) {
 	miner2 := tutil.NewIDAddr(t, 102)
 	owner := tutil.NewIDAddr(t, 103)
 
-	builder := mock.NewBuilder(context.Background(), builtin.StoragePowerActorAddr).WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
+	builder := mock.NewBuilder(context.Background(), builtin.StoragePowerActorAddr).WithStateInvariants(power.CheckStateInvariants).WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
 
 	t.Run("calls reward actor", func(t *testing.T) {
 		rt := builder.Build(t)
@@ -576,6 +593,29 @@ func TestCron(t *testing.T) {
 		actor.checkState(rt)
 	})
 
+	t.Run("updates smoothed estimates of power and pledge", func(t *testing.T) {
+		powerUnit, err := builtin.ConsensusMinerMinPower(abi.RegisteredSealProof_StackedDrg2KiBV1)
+		require.NoError(t, err)
+
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		actor.createMinerBasic(rt, owner, owner, miner1)
+		actor.updateClaimedPower(rt, miner1, powerUnit, big.Mul(big.NewInt(2), powerUnit))
+		pledge := abi.NewTokenAmount(1e18)
+		actor.updatePledgeTotal(rt, miner1, pledge)
+
+		initial := actor.currentSmoothedEstimates(rt)
+		actor.onEpochTickEnd(rt, 0, powerUnit, nil, nil)
+
+		config := power.QAPowerFilterSchedule.ForVersion(rt.NetworkVersion())
+		estimates := actor.currentSmoothedEstimates(rt)
+		assert.Equal(t, smoothing.NextEstimateWithConfig(initial.RawBytePowerSmoothed, powerUnit, 1, config), estimates.RawBytePowerSmoothed)
+		assert.Equal(t, smoothing.NextEstimateWithConfig(initial.QualityAdjPowerSmoothed, big.Mul(big.NewInt(2), powerUnit), 1, config), estimates.QualityAdjPowerSmoothed)
+		assert.Equal(t, smoothing.NextEstimateWithConfig(initial.PledgeCollateralSmoothed, pledge, 1, config), estimates.PledgeCollateralSmoothed)
+		assert.Equal(t, actor.currentPowerTotal(rt).QualityAdjPowerSmoothed, estimates.QualityAdjPowerSmoothed)
+		actor.checkState(rt)
+	})
+
 	t.Run("event scheduled in null round called next round", func(t *testing.T) {
 		rt := builder.Build(t)
 		actor.constructAndVerify(rt)
@@ -652,6 +692,72 @@ func TestCron(t *testing.T) {
 		actor.checkState(rt)
 	})
 
+	t.Run("drains every due epoch after null rounds", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		actor.createMinerBasic(rt, owner, owner, miner1)
+		actor.createMinerBasic(rt, owner, owner, miner2)
+
+		rt.SetEpoch(1)
+		actor.enrollCronEvent(rt, miner1, 2, []byte{0x1})
+		actor.enrollCronEvent(rt, miner2, 2, []byte{0x2})
+		actor.enrollCronEvent(rt, miner1, 3, []byte{0x3})
+
+		// No tick at epoch 2, so the tick at epoch 3 delivers the events of both epochs.
+		expectedRawBytePower := big.NewInt(0)
+		rt.SetEpoch(3)
+		rt.ExpectValidateCallerAddr(builtin.CronActorAddr)
+		rt.ExpectSend(miner1, builtin.MethodsMiner.OnDeferredCronEvent, builtin.CBORBytes([]byte{0x1}), big.Zero(), nil, exitcode.Ok)
+		rt.ExpectSend(miner2, builtin.MethodsMiner.OnDeferredCronEvent, builtin.CBORBytes([]byte{0x2}), big.Zero(), nil, exitcode.Ok)
+		rt.ExpectSend(miner1, builtin.MethodsMiner.OnDeferredCronEvent, builtin.CBORBytes([]byte{0x3}), big.Zero(), nil, exitcode.Ok)
+		rt.ExpectSend(builtin.RewardActorAddr, builtin.MethodsReward.UpdateNetworkKPI, &expectedRawBytePower, big.Zero(), nil, exitcode.Ok)
+		rt.SetCaller(builtin.CronActorAddr, builtin.CronActorCodeID)
+		rt.ExpectBatchVerifySeals(nil, nil, nil)
+		rt.Call(actor.Actor.OnEpochTickEnd, nil)
+		rt.Verify()
+		assert.Equal(t, abi.ChainEpoch(4), getState(rt).FirstCronEpoch)
+		actor.checkState(rt)
+	})
+
+	t.Run("delivers a miner's deadline event on time after more events queued ahead of it", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		actor.createMinerBasic(rt, owner, owner, miner1)
+		actor.createMinerBasic(rt, owner, owner, miner2)
+
+		terminations := []byte{0x1}
+		deadline := []byte{0x2}
+		rt.SetEpoch(1)
+		for i := 0; i < 5; i++ {
+			actor.enrollCronEvent(rt, miner1, 2, terminations)
+		}
+		actor.enrollCronEvent(rt, miner2, 2, terminations)
+		actor.enrollCronEvent(rt, miner1, 2, deadline)
+
+		// Miner 1's repeated events are delivered once, and its deadline event in the epoch it was due.
+		expectedRawBytePower := big.NewInt(0)
+		rt.SetEpoch(2)
+		rt.ExpectValidateCallerAddr(builtin.CronActorAddr)
+		rt.ExpectSend(miner1, builtin.MethodsMiner.OnDeferredCronEvent, builtin.CBORBytes(terminations), big.Zero(), nil, exitcode.Ok)
+		rt.ExpectSend(miner2, builtin.MethodsMiner.OnDeferredCronEvent, builtin.CBORBytes(terminations), big.Zero(), nil, exitcode.Ok)
+		rt.ExpectSend(miner1, builtin.MethodsMiner.OnDeferredCronEvent, builtin.CBORBytes(deadline), big.Zero(), nil, exitcode.Ok)
+		rt.ExpectSend(builtin.RewardActorAddr, builtin.MethodsReward.UpdateNetworkKPI, &expectedRawBytePower, big.Zero(), nil, exitcode.Ok)
+		rt.SetCaller(builtin.CronActorAddr, builtin.CronActorCodeID)
+		rt.ExpectBatchVerifySeals(nil, nil, nil)
+		rt.Call(actor.Actor.OnEpochTickEnd, nil)
+		rt.Verify()
+		assert.Equal(t, abi.ChainEpoch(3), getState(rt).FirstCronEpoch)
+
+		// Nothing is carried over to the next epoch.
+		rt.SetEpoch(3)
+		rt.ExpectValidateCallerAddr(builtin.CronActorAddr)
+		rt.ExpectSend(builtin.RewardActorAddr, builtin.MethodsReward.UpdateNetworkKPI, &expectedRawBytePower, big.Zero(), nil, exitcode.Ok)
+		rt.ExpectBatchVerifySeals(nil, nil, nil)
+		rt.Call(actor.Actor.OnEpochTickEnd, nil)
+		rt.Verify()
+		actor.checkState(rt)
+	})
+
 	t.Run("fails to enroll if epoch is negative", func(t *testing.T) {
 		rt := builder.Build(t)
 		actor.constructAndVerify(rt)
@@ -764,7 +870,7 @@ func TestSubmitPoRepForBulkVerify(t *testing.T) {
 	actor := newHarness(t)
 	miner := tutil.NewIDAddr(t, 101)
 	owner := tutil.NewIDAddr(t, 101)
-	builder := mock.NewBuilder(context.Background(), builtin.StoragePowerActorAddr).WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
+	builder := mock.NewBuilder(context.Background(), builtin.StoragePowerActorAddr).WithStateInvariants(power.CheckStateInvariants).WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
 
 	t.Run("registers porep and charges gas", func(t *testing.T) {
 		rt := builder.Build(t)
@@ -803,6 +909,7 @@ func TestSubmitPoRepForBulkVerify(t *testing.T) {
 
 		sealInfo := func(i int) *proof.SealVerifyInfo {
 			var sealInfo proof.SealVerifyInfo
+			sealInfo.SealProof = actor.sealProof
 			sealInfo.SealedCID = tutil.MakeCID(fmt.Sprintf("commR-%d", i), &mineract.SealedCIDPrefix)
 			sealInfo.UnsealedCID = tutil.MakeCID(fmt.Sprintf("commD-%d", i), &market.PieceCIDPrefix)
 			return &sealInfo
@@ -844,6 +951,7 @@ func TestSubmitPoRepForBulkVerify(t *testing.T) {
 func TestCronBatchProofVerifies(t *testing.T) {
 	sealInfo := func(i int) *proof.SealVerifyInfo {
 		var sealInfo proof.SealVerifyInfo
+		sealInfo.SealProof = abi.RegisteredSealProof_StackedDrg32GiBV1
 		sealInfo.SealedCID = tutil.MakeCID(fmt.Sprintf("commR-%d", i), &mineract.SealedCIDPrefix)
 		sealInfo.UnsealedCID = tutil.MakeCID(fmt.Sprintf("commD-%d", i), &market.PieceCIDPrefix)
 		sealInfo.SectorID = abi.SectorID{Number: abi.SectorNumber(i)}
@@ -953,12 +1061,10 @@ func TestCronBatchProofVerifies(t *testing.T) {
 		ac.submitPoRepForBulkVerify(rt, miner4, info7)
 		ac.submitPoRepForBulkVerify(rt, miner4, info8)
 
-		// TODO Because read order of keys in a multi-map is not as per insertion order,
-		// we have to move around the expected sends
 		cs := []confirmedSectorSend{{miner1, []abi.SectorNumber{info1.Number, info2.Number}},
+			{miner2, []abi.SectorNumber{info3.Number, info4.Number}},
 			{miner3, []abi.SectorNumber{info5.Number, info6.Number}},
-			{miner4, []abi.SectorNumber{info7.Number, info8.Number}},
-			{miner2, []abi.SectorNumber{info3.Number, info4.Number}}}
+			{miner4, []abi.SectorNumber{info7.Number, info8.Number}}}
 
 		infos := map[addr.Address][]proof.SealVerifyInfo{miner1: {*info1, *info2},
 			miner2: {*info3, *info4},
@@ -1033,6 +1139,29 @@ func TestCronBatchProofVerifies(t *testing.T) {
 		})
 		rt.Verify()
 	})
+
+	t.Run("retains batch for the next cron tick if batch verify seals fails", func(t *testing.T) {
+		rt, ac := basicPowerSetup(t)
+		ac.createMinerBasic(rt, owner, owner, miner1)
+
+		ac.submitPoRepForBulkVerify(rt, miner1, info1)
+		ac.submitPoRepForBulkVerify(rt, miner1, info2)
+
+		rt.FailSyscall(mock.SyscallBatchVerifySeals, 1, fmt.Errorf("fail"))
+		rt.ExpectValidateCallerAddr(builtin.CronActorAddr)
+		rt.SetEpoch(abi.ChainEpoch(0))
+		rt.SetCaller(builtin.CronActorAddr, builtin.CronActorCodeID)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalState, "failed to batch verify", func() {
+			rt.Call(ac.Actor.OnEpochTickEnd, nil)
+		})
+		rt.Verify()
+
+		// The aborted tick's state changes are discarded, so the next tick verifies the same batch.
+		infos := map[addr.Address][]proof.SealVerifyInfo{miner1: {*info1, *info2}}
+		cs := []confirmedSectorSend{{miner1, []abi.SectorNumber{info1.Number, info2.Number}}}
+		ac.onEpochTickEnd(rt, 1, big.Zero(), cs, infos)
+		ac.checkState(rt)
+	})
 }
 
 //
@@ -1101,11 +1230,13 @@ type confirmedSectorSend struct {
 func (h *spActorHarness) onEpochTickEnd(rt *mock.Runtime, currEpoch abi.ChainEpoch, expectedRawPower abi.StoragePower,
 	confirmedSectors []confirmedSectorSend, infos map[addr.Address][]proof.SealVerifyInfo) {
 
-	// expect sends for confirmed sectors
-	for _, cs := range confirmedSectors {
-		param := &builtin.ConfirmSectorProofsParams{Sectors: cs.sectorNums}
-		rt.ExpectSend(cs.miner, builtin.MethodsMiner.ConfirmSectorProofsValid, param, abi.NewTokenAmount(0), nil, 0)
-	}
+	// expect sends for confirmed sectors, which are made in the (hash) order of the proof validation batch's keys
+	rt.ExpectSendAnyOrder(func() {
+		for _, cs := range confirmedSectors {
+			param := &builtin.ConfirmSectorProofsParams{Sectors: cs.sectorNums}
+			rt.ExpectSend(cs.miner, builtin.MethodsMiner.ConfirmSectorProofsValid, param, abi.NewTokenAmount(0), nil, 0)
+		}
+	})
 
 	rt.ExpectBatchVerifySeals(infos, batchVerifyDefaultOutput(infos), nil)
 	//expect power sends to reward actor
@@ -1211,7 +1342,7 @@ func (h *spActorHarness) getEnrolledCronTicks(rt *mock.Runtime, epoch abi.ChainE
 }
 
 func basicPowerSetup(t *testing.T) (*mock.Runtime, *spActorHarness) {
-	builder := mock.NewBuilder(context.Background(), builtin.StoragePowerActorAddr).WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
+	builder := mock.NewBuilder(context.Background(), builtin.StoragePowerActorAddr).WithStateInvariants(power.CheckStateInvariants).WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
 	rt := builder.Build(t)
 	h := newHarness(t)
 	h.constructAndVerify(rt)
@@ -1237,6 +1368,11 @@ func (h *spActorHarness) updateClaimedPower(rt *mock.Runtime, miner addr.Address
 	rt.ExpectValidateCallerType(builtin.StorageMinerActorCodeID)
 	rt.Call(h.UpdateClaimedPower, &params)
 	rt.Verify()
+	rt.RequireEmittedEvent(builtin.EventKeyPowerUpdated, &builtin.PowerUpdatedEvent{
+		Miner:                miner,
+		RawByteDelta:         rawDelta,
+		QualityAdjustedDelta: qaDelta,
+	})
 
 	cl := h.getClaim(rt, miner)
 	expectedRaw := big.Add(prevCl.RawBytePower, rawDelta)
@@ -1270,7 +1406,14 @@ func (h *spActorHarness) updatePledgeTotal(rt *mock.Runtime, miner addr.Address,
 
 func (h *spActorHarness) currentPowerTotal(rt *mock.Runtime) *power.CurrentTotalPowerReturn {
 	rt.ExpectValidateCallerAny()
-	ret := rt.Call(h.CurrentTotalPower, nil).(*power.CurrentTotalPowerReturn)
+	ret := rt.CallReadOnly(h.CurrentTotalPower, nil).(*power.CurrentTotalPowerReturn)
+	rt.Verify()
+	return ret
+}
+
+func (h *spActorHarness) currentSmoothedEstimates(rt *mock.Runtime) *power.CurrentSmoothedEstimatesReturn {
+	rt.ExpectValidateCallerAny()
+	ret := rt.CallReadOnly(h.CurrentSmoothedEstimates, nil).(*power.CurrentSmoothedEstimatesReturn)
 	rt.Verify()
 	return ret
 }
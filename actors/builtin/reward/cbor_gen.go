@@ -7,6 +7,7 @@ import (
 	"io"
 
 	abi "github.com/filecoin-project/go-state-types/abi"
+	acbor "github.com/filecoin-project/specs-actors/v2/actors/util/cbor"
 	cbg "github.com/whyrusleeping/cbor-gen"
 	xerrors "golang.org/x/xerrors"
 )
@@ -103,14 +104,14 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "reward.State", "")
 	}
 	if maj != cbg.MajArray {
-		return fmt.Errorf("cbor input should be of type array")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "reward.State", "")
 	}
 
 	if extra != 11 {
-		return fmt.Errorf("cbor input had wrong number of fields")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "reward.State", "")
 	}
 
 	// t.CumsumBaseline (big.Int) (struct)
@@ -118,7 +119,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.CumsumBaseline.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.CumsumBaseline: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.CumsumBaseline: %w", err), "reward.State", "CumsumBaseline")
 		}
 
 	}
@@ -127,7 +128,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.CumsumRealized.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.CumsumRealized: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.CumsumRealized: %w", err), "reward.State", "CumsumRealized")
 		}
 
 	}
@@ -136,22 +137,22 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 		var extraI int64
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "reward.State", "EffectiveNetworkTime")
 		}
 		switch maj {
 		case cbg.MajUnsignedInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 positive overflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "reward.State", "EffectiveNetworkTime")
 			}
 		case cbg.MajNegativeInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 negative oveflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "reward.State", "EffectiveNetworkTime")
 			}
 			extraI = -1 - extraI
 		default:
-			return fmt.Errorf("wrong type for int64 field: %d", maj)
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "reward.State", "EffectiveNetworkTime")
 		}
 
 		t.EffectiveNetworkTime = abi.ChainEpoch(extraI)
@@ -161,7 +162,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.EffectiveBaselinePower.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.EffectiveBaselinePower: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.EffectiveBaselinePower: %w", err), "reward.State", "EffectiveBaselinePower")
 		}
 
 	}
@@ -170,7 +171,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.ThisEpochReward.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.ThisEpochReward: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.ThisEpochReward: %w", err), "reward.State", "ThisEpochReward")
 		}
 
 	}
@@ -179,7 +180,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.ThisEpochRewardSmoothed.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.ThisEpochRewardSmoothed: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.ThisEpochRewardSmoothed: %w", err), "reward.State", "ThisEpochRewardSmoothed")
 		}
 
 	}
@@ -188,7 +189,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.ThisEpochBaselinePower.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.ThisEpochBaselinePower: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.ThisEpochBaselinePower: %w", err), "reward.State", "ThisEpochBaselinePower")
 		}
 
 	}
@@ -197,22 +198,22 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 		var extraI int64
 		if err != nil {
-			return err
+			return acbor.WrapDecodeError(err, "reward.State", "Epoch")
 		}
 		switch maj {
 		case cbg.MajUnsignedInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 positive overflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "reward.State", "Epoch")
 			}
 		case cbg.MajNegativeInt:
 			extraI = int64(extra)
 			if extraI < 0 {
-				return fmt.Errorf("int64 negative oveflow")
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "reward.State", "Epoch")
 			}
 			extraI = -1 - extraI
 		default:
-			return fmt.Errorf("wrong type for int64 field: %d", maj)
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "reward.State", "Epoch")
 		}
 
 		t.Epoch = abi.ChainEpoch(extraI)
@@ -222,7 +223,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.TotalStoragePowerReward.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.TotalStoragePowerReward: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.TotalStoragePowerReward: %w", err), "reward.State", "TotalStoragePowerReward")
 		}
 
 	}
@@ -231,7 +232,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.SimpleTotal.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.SimpleTotal: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.SimpleTotal: %w", err), "reward.State", "SimpleTotal")
 		}
 
 	}
@@ -240,7 +241,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.BaselineTotal.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.BaselineTotal: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.BaselineTotal: %w", err), "reward.State", "BaselineTotal")
 		}
 
 	}
@@ -278,14 +279,14 @@ func (t *ThisEpochRewardReturn) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "reward.ThisEpochRewardReturn", "")
 	}
 	if maj != cbg.MajArray {
-		return fmt.Errorf("cbor input should be of type array")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "reward.ThisEpochRewardReturn", "")
 	}
 
 	if extra != 2 {
-		return fmt.Errorf("cbor input had wrong number of fields")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "reward.ThisEpochRewardReturn", "")
 	}
 
 	// t.ThisEpochRewardSmoothed (smoothing.FilterEstimate) (struct)
@@ -293,7 +294,7 @@ func (t *ThisEpochRewardReturn) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.ThisEpochRewardSmoothed.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.ThisEpochRewardSmoothed: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.ThisEpochRewardSmoothed: %w", err), "reward.ThisEpochRewardReturn", "ThisEpochRewardSmoothed")
 		}
 
 	}
@@ -302,9 +303,17 @@ func (t *ThisEpochRewardReturn) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.ThisEpochBaselinePower.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.ThisEpochBaselinePower: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.ThisEpochBaselinePower: %w", err), "reward.ThisEpochRewardReturn", "ThisEpochBaselinePower")
 		}
 
 	}
 	return nil
 }
+
+func (t *State) MaxEncodedLength() int64 {
+	return 1320
+}
+
+func (t *ThisEpochRewardReturn) MaxEncodedLength() int64 {
+	return 392
+}
@@ -50,7 +50,7 @@ func (a Actor) Constructor(rt runtime.Runtime, currRealizedPower *abi.StoragePow
 		rt.Abortf(exitcode.ErrIllegalArgument, "argument should not be nil")
 		return nil // linter does not understand abort exiting
 	}
-	st := ConstructState(*currRealizedPower)
+	st := ConstructStateWithMinting(*currRealizedPower, RewardMintingSchedule.ForVersion(rt.NetworkVersion()))
 	rt.StateCreate(st)
 	return nil
 }
@@ -163,6 +163,7 @@ func (a Actor) UpdateNetworkKPI(rt runtime.Runtime, currRealizedPower *abi.Stora
 		rt.Abortf(exitcode.ErrIllegalArgument, "arugment should not be nil")
 	}
 
+	minting := RewardMintingSchedule.ForVersion(rt.NetworkVersion())
 	var st State
 	rt.StateTransaction(&st, func() {
 		prev := st.Epoch
@@ -170,12 +171,12 @@ func (a Actor) UpdateNetworkKPI(rt runtime.Runtime, currRealizedPower *abi.Stora
 		// st.Epoch == rt.CurrEpoch()
 		for st.Epoch < rt.CurrEpoch() {
 			// Update to next epoch to process null rounds
-			st.updateToNextEpoch(*currRealizedPower)
+			st.updateToNextEpoch(*currRealizedPower, minting)
 		}
 
-		st.updateToNextEpochWithReward(*currRealizedPower)
+		st.updateToNextEpochWithReward(*currRealizedPower, minting)
 		// only update smoothed estimates after updating reward and epoch
-		st.updateSmoothedEstimates(st.Epoch - prev)
+		st.updateSmoothedEstimates(st.Epoch-prev, RewardFilterSchedule.ForVersion(rt.NetworkVersion()))
 	})
 	return nil
 }
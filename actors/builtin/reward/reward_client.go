@@ -0,0 +1,29 @@
+package reward
+
+import (
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v2/actors/runtime"
+)
+
+// A typed client for calls from other actors to the reward actor.
+// Each method aborts with the reward actor's exit code if the call fails.
+type Client struct {
+	rt runtime.Runtime
+}
+
+func NewClient(rt runtime.Runtime) Client {
+	return Client{rt: rt}
+}
+
+func (c Client) ThisEpochReward() *ThisEpochRewardReturn {
+	var ret ThisEpochRewardReturn
+	builtin.SendReadOnlyAndUnmarshal(c.rt, builtin.RewardActorAddr, builtin.MethodsReward.ThisEpochReward, nil, &ret)
+	return &ret
+}
+
+func (c Client) UpdateNetworkKPI(currRealizedPower abi.StoragePower) {
+	builtin.SendAndUnmarshal(c.rt, builtin.RewardActorAddr, builtin.MethodsReward.UpdateNetworkKPI, &currRealizedPower, big.Zero(), nil)
+}
@@ -3,24 +3,42 @@ package reward
 import (
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/network"
+	"golang.org/x/xerrors"
 
-	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v2/actors/util"
 	"github.com/filecoin-project/specs-actors/v2/actors/util/math"
 )
 
-const (
-	// This number is not exported because it's not suitable for
-	// calculations outside reward calculations. Importantly, there are more
-	// than 365 days in a year so this number cannot be used to calculate
-	// sector lifetimes, etc.
-	daysInYear   = 365
-	epochsInYear = daysInYear * builtin.EpochsInDay
-)
+// A minting function determines the baseline power the network targets at each epoch, and the reward minted
+// as the network's effective time advances towards it.
+// The function in effect is selected by network version (see RewardMintingSchedule), so that changes to
+// baseline growth or reward decay can be introduced at an upgrade without altering the rest of the actor.
+type MintingFunction interface {
+	// The baseline power at epoch 0.
+	BaselineInitialValue() abi.StoragePower
+	// The baseline power at epoch -1, from which the baseline power at epoch 0 is computed.
+	InitBaselinePower() abi.StoragePower
+	// Computes the baseline power at an epoch from that at the previous epoch.
+	BaselinePowerFromPrev(prevEpochBaselinePower abi.StoragePower) abi.StoragePower
+	// Computes the reward for all expected leaders at an epoch when effective network time changes from
+	// prevTheta to currTheta (Q.128), given the total supplies to be minted by the simple and baseline schemes.
+	ComputeReward(epoch abi.ChainEpoch, prevTheta, currTheta, simpleTotal, baselineTotal big.Int) abi.TokenAmount
+}
+
+// A minting function with exponentially growing baseline power, and simple and baseline rewards which
+// decay exponentially with, respectively, epoch and effective network time.
+// The baseline power at epoch t is InitialValue * BaselineExponent^t. It is computed iteratively with the
+// recurrence e(n) = e * e(n-1): the caller keeps track of the previous epoch's baseline power e(n-1), and
+// BaselinePowerFromPrev does the next multiplication.
+type ExponentialMinting struct {
+	BaselineExponent big.Int // Q.128
+	InitialValue     big.Int // Q.0
+	Lambda           big.Int // Q.128
+	ExpLamSubOne     big.Int // Q.128
+}
 
-// Baseline function = BaselineInitialValue * (BaselineExponent) ^(t), t in epochs
-// Note: we compute exponential iteratively using recurrence e(n) = e * e(n-1).
-// Caller of baseline power function is responsible for keeping track of intermediate,
-// state e(n-1), the baseline power function just does the next multiplication
+var _ MintingFunction = ExponentialMinting{}
 
 // Floor(e^(ln[1 + 200%] / epochsInYear) * 2^128
 // Q.128 formatted number such that f(epoch) = baseExponent^epoch grows 200% in one year of epochs
@@ -30,19 +48,43 @@ var BaselineExponent = big.MustFromString("3402825912986410784659641899263134736
 // 2.5057116798121726 EiB
 var BaselineInitialValue = big.NewInt(2_888_888_880_000_000_000) // Q.0
 
+// The minting function in effect since genesis.
+func DefaultMinting() ExponentialMinting {
+	return ExponentialMinting{
+		BaselineExponent: BaselineExponent,
+		InitialValue:     BaselineInitialValue,
+		Lambda:           Lambda,
+		ExpLamSubOne:     ExpLamSubOne,
+	}
+}
+
+func (m ExponentialMinting) BaselineInitialValue() abi.StoragePower {
+	return m.InitialValue
+}
+
 // Initialize baseline power for epoch -1 so that baseline power at epoch 0 is
 // BaselineInitialValue.
-func InitBaselinePower() abi.StoragePower {
-	baselineInitialValue256 := big.Lsh(BaselineInitialValue, 2*math.Precision128) // Q.0 => Q.256
-	baselineAtMinusOne := big.Div(baselineInitialValue256, BaselineExponent)   // Q.256 / Q.128 => Q.128
-	return big.Rsh(baselineAtMinusOne, math.Precision128)                         // Q.128 => Q.0
+func (m ExponentialMinting) InitBaselinePower() abi.StoragePower {
+	baselineInitialValue256 := big.Lsh(m.InitialValue, 2*math.Precision128)    // Q.0 => Q.256
+	baselineAtMinusOne := big.Div(baselineInitialValue256, m.BaselineExponent) // Q.256 / Q.128 => Q.128
+	return big.Rsh(baselineAtMinusOne, math.Precision128)                      // Q.128 => Q.0
 }
 
 // Compute BaselinePower(t) from BaselinePower(t-1) with an additional multiplication
 // of the base exponent.
+func (m ExponentialMinting) BaselinePowerFromPrev(prevEpochBaselinePower abi.StoragePower) abi.StoragePower {
+	thisEpochBaselinePower := big.Mul(prevEpochBaselinePower, m.BaselineExponent) // Q.0 * Q.128 => Q.128
+	return big.Rsh(thisEpochBaselinePower, math.Precision128)                     // Q.128 => Q.0
+}
+
+// Initialize baseline power for epoch -1 with the default minting function.
+func InitBaselinePower() abi.StoragePower {
+	return DefaultMinting().InitBaselinePower()
+}
+
+// Compute BaselinePower(t) from BaselinePower(t-1) with the default minting function.
 func BaselinePowerFromPrev(prevEpochBaselinePower abi.StoragePower) abi.StoragePower {
-	thisEpochBaselinePower := big.Mul(prevEpochBaselinePower, BaselineExponent) // Q.0 * Q.128 => Q.128
-	return big.Rsh(thisEpochBaselinePower, math.Precision128)                      // Q.128 => Q.0
+	return DefaultMinting().BaselinePowerFromPrev(prevEpochBaselinePower)
 }
 
 // These numbers are estimates of the onchain constants.  They are good for initializing state in
@@ -86,14 +128,14 @@ var (
 
 // Computes a reward for all expected leaders when effective network time changes from prevTheta to currTheta
 // Inputs are in Q.128 format
-func computeReward(epoch abi.ChainEpoch, prevTheta, currTheta, simpleTotal, baselineTotal big.Int) abi.TokenAmount {
-	simpleReward := big.Mul(simpleTotal, ExpLamSubOne)    //Q.0 * Q.128 =>  Q.128
-	epochLam := big.Mul(big.NewInt(int64(epoch)), Lambda) // Q.0 * Q.128 => Q.128
+func (m ExponentialMinting) ComputeReward(epoch abi.ChainEpoch, prevTheta, currTheta, simpleTotal, baselineTotal big.Int) abi.TokenAmount {
+	simpleReward := big.Mul(simpleTotal, m.ExpLamSubOne)    //Q.0 * Q.128 =>  Q.128
+	epochLam := big.Mul(big.NewInt(int64(epoch)), m.Lambda) // Q.0 * Q.128 => Q.128
 
 	simpleReward = big.Mul(simpleReward, big.NewFromGo(math.ExpNeg(epochLam.Int))) // Q.128 * Q.128 => Q.256
 	simpleReward = big.Rsh(simpleReward, math.Precision128)                      // Q.256 >> 128 => Q.128
 
-	baselineReward := big.Sub(computeBaselineSupply(currTheta, baselineTotal), computeBaselineSupply(prevTheta, baselineTotal)) // Q.128
+	baselineReward := big.Sub(m.computeBaselineSupply(currTheta, baselineTotal), m.computeBaselineSupply(prevTheta, baselineTotal)) // Q.128
 
 	reward := big.Add(simpleReward, baselineReward) // Q.128
 
@@ -102,19 +144,50 @@ func computeReward(epoch abi.ChainEpoch, prevTheta, currTheta, simpleTotal, base
 
 // Computes baseline supply based on theta in Q.128 format.
 // Return is in Q.128 format
-func computeBaselineSupply(theta, baselineTotal big.Int) big.Int {
-	thetaLam := big.Mul(theta, Lambda)           // Q.128 * Q.128 => Q.256
+func (m ExponentialMinting) computeBaselineSupply(theta, baselineTotal big.Int) big.Int {
+	thetaLam := big.Mul(theta, m.Lambda)            // Q.128 * Q.128 => Q.256
 	thetaLam = big.Rsh(thetaLam, math.Precision128) // Q.256 >> 128 => Q.128
 
 	eTL := big.NewFromGo(math.ExpNeg(thetaLam.Int)) // Q.128
 
 	one := big.NewInt(1)
 	one = big.Lsh(one, math.Precision128) // Q.0 => Q.128
-	oneSub := big.Sub(one, eTL)        // Q.128
+	oneSub := big.Sub(one, eTL)           // Q.128
 
 	return big.Mul(baselineTotal, oneSub) // Q.0 * Q.128 => Q.128
 }
 
+// A minting function in effect from a network version.
+type ScheduledMinting struct {
+	Version  network.Version
+	Function MintingFunction
+}
+
+// Minting functions in order of the network versions from which they are in effect, each until the next.
+type MintingSchedule []ScheduledMinting
+
+// Returns the minting function in effect at a network version.
+// Versions before the first scheduled are given the first function.
+func (s MintingSchedule) ForVersion(nv network.Version) MintingFunction {
+	return s[util.VersionIndex(s.versions(), nv)].Function
+}
+
+// Checks that the schedule is non-empty and in increasing order of version.
+func (s MintingSchedule) Validate() error {
+	if err := util.ValidateVersions(s.versions()); err != nil {
+		return xerrors.Errorf("minting schedule: %w", err)
+	}
+	return nil
+}
+
+func (s MintingSchedule) versions() []network.Version {
+	versions := make([]network.Version, len(s))
+	for i, scheduled := range s {
+		versions[i] = scheduled.Version
+	}
+	return versions
+}
+
 // SlowConvenientBaselineForEpoch computes baseline power for use in epoch t
 // by calculating the value of ThisEpochBaselinePower that shows up in block at t - 1
 // It multiplies ~t times so it should not be used in actor code directly.  It is exported as
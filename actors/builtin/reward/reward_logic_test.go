@@ -8,9 +8,12 @@ import (
 
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/network"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/xorcare/golden"
 
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
 	"github.com/filecoin-project/specs-actors/v2/actors/util/math"
 )
 
@@ -53,10 +56,10 @@ func TestBaselineReward(t *testing.T) {
 
 	b := &bytes.Buffer{}
 	b.WriteString("t0, t1, y\n")
-	simple := computeReward(0, big.Zero(), big.Zero(), DefaultSimpleTotal, DefaultBaselineTotal)
+	simple := DefaultMinting().ComputeReward(0, big.Zero(), big.Zero(), DefaultSimpleTotal, DefaultBaselineTotal)
 
 	for i := 0; i < 512; i++ {
-		reward := computeReward(0, big.NewFromGo(prevTheta), big.NewFromGo(theta), DefaultSimpleTotal, DefaultBaselineTotal)
+		reward := DefaultMinting().ComputeReward(0, big.NewFromGo(prevTheta), big.NewFromGo(theta), DefaultSimpleTotal, DefaultBaselineTotal)
 		reward = big.Sub(reward, simple)
 		fmt.Fprintf(b, "%s,%s,%s\n", prevTheta, theta, reward.Int)
 		prevTheta = prevTheta.Add(prevTheta, step)
@@ -71,7 +74,7 @@ func TestSimpleReward(t *testing.T) {
 	b.WriteString("x, y\n")
 	for i := int64(0); i < 512; i++ {
 		x := i * 5000
-		reward := computeReward(abi.ChainEpoch(x), big.Zero(), big.Zero(), DefaultSimpleTotal, DefaultBaselineTotal)
+		reward := DefaultMinting().ComputeReward(abi.ChainEpoch(x), big.Zero(), big.Zero(), DefaultSimpleTotal, DefaultBaselineTotal)
 		fmt.Fprintf(b, "%d,%s\n", x, reward.Int)
 	}
 
@@ -79,7 +82,7 @@ func TestSimpleReward(t *testing.T) {
 }
 
 func TestBaselineRewardGrowth(t *testing.T) {
-
+	epochsInYear := abi.ChainEpoch(365 * builtin.EpochsInDay)
 	baselineInYears := func(start abi.StoragePower, x abi.ChainEpoch) abi.StoragePower {
 		baseline := start
 		for i := abi.ChainEpoch(0); i < x*epochsInYear; i++ {
@@ -151,3 +154,31 @@ func TestBaselineRewardGrowth(t *testing.T) {
 		assert.Less(t, perr, testCase.ErrBound)
 	}
 }
+
+func TestMintingSchedule(t *testing.T) {
+	faster := DefaultMinting()
+	faster.BaselineExponent = big.Add(BaselineExponent, big.Lsh(big.NewInt(1), 100))
+	schedule := MintingSchedule{
+		{Version: network.Version0, Function: DefaultMinting()},
+		{Version: network.Version5, Function: faster},
+	}
+	require.NoError(t, schedule.Validate())
+	require.NoError(t, RewardMintingSchedule.Validate())
+
+	assert.Equal(t, DefaultMinting(), schedule.ForVersion(network.Version4))
+	assert.Equal(t, faster, schedule.ForVersion(network.Version5))
+	assert.Equal(t, faster, schedule.ForVersion(network.Version6))
+
+	// Baseline power grows according to the function in effect.
+	start := abi.NewStoragePower(1 << 50)
+	assert.True(t, faster.BaselinePowerFromPrev(start).GreaterThan(BaselinePowerFromPrev(start)))
+	st := ConstructStateWithMinting(big.Zero(), faster)
+	assert.Equal(t, faster.BaselinePowerFromPrev(faster.InitBaselinePower()), st.ThisEpochBaselinePower)
+	assert.Equal(t, ConstructState(big.Zero()), ConstructStateWithMinting(big.Zero(), DefaultMinting()))
+
+	assert.Error(t, MintingSchedule{}.Validate())
+	assert.Error(t, MintingSchedule{
+		{Version: network.Version5, Function: DefaultMinting()},
+		{Version: network.Version5, Function: faster},
+	}.Validate())
+}
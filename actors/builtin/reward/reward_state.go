@@ -3,6 +3,7 @@ package reward
 import (
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/network"
 
 	"github.com/filecoin-project/specs-actors/v2/actors/util/smoothing"
 )
@@ -20,6 +21,16 @@ var InitialRewardPositionEstimate = big.MustFromString(InitialRewardPositionEsti
 // https://www.wolframalpha.com/input/?i=IntegerPart%5B%28Exp%5B-Log%5B2%5D+%2F+%286+*+%281+year+%2F+30+seconds%29%29%5D+-+1%29+*+10%5E18%5D
 var InitialRewardVelocityEstimate = abi.NewTokenAmount(-109897758509)
 
+// The gains of the filter smoothing the per-epoch reward, by network version.
+var RewardFilterSchedule = smoothing.FilterSchedule{
+	{Version: network.Version0, Config: smoothing.DefaultFilterConfig()}, // PARAM_SPEC
+}
+
+// The minting functions determining baseline power and rewards, by network version.
+var RewardMintingSchedule = MintingSchedule{
+	{Version: network.Version0, Function: DefaultMinting()}, // PARAM_SPEC
+}
+
 // Changed since v0:
 // - ThisEpochRewardSmoothed is not a pointer
 type State struct {
@@ -65,14 +76,19 @@ type State struct {
 }
 
 func ConstructState(currRealizedPower abi.StoragePower) *State {
+	return ConstructStateWithMinting(currRealizedPower, RewardMintingSchedule.ForVersion(network.Version0))
+}
+
+// Constructs state with baseline power and the first epoch's reward computed by a minting function.
+func ConstructStateWithMinting(currRealizedPower abi.StoragePower, minting MintingFunction) *State {
 	st := &State{
 		CumsumBaseline:         big.Zero(),
 		CumsumRealized:         big.Zero(),
 		EffectiveNetworkTime:   0,
-		EffectiveBaselinePower: BaselineInitialValue,
+		EffectiveBaselinePower: minting.BaselineInitialValue(),
 
 		ThisEpochReward:        big.Zero(),
-		ThisEpochBaselinePower: InitBaselinePower(),
+		ThisEpochBaselinePower: minting.InitBaselinePower(),
 		Epoch:                  -1,
 
 		ThisEpochRewardSmoothed: smoothing.NewEstimate(InitialRewardPositionEstimate, InitialRewardVelocityEstimate),
@@ -82,37 +98,36 @@ func ConstructState(currRealizedPower abi.StoragePower) *State {
 		BaselineTotal: DefaultBaselineTotal,
 	}
 
-	st.updateToNextEpochWithReward(currRealizedPower)
+	st.updateToNextEpochWithReward(currRealizedPower, minting)
 
 	return st
 }
 
 // Takes in current realized power and updates internal state
 // Used for update of internal state during null rounds
-func (st *State) updateToNextEpoch(currRealizedPower abi.StoragePower) {
+func (st *State) updateToNextEpoch(currRealizedPower abi.StoragePower, minting MintingFunction) {
 	st.Epoch++
-	st.ThisEpochBaselinePower = BaselinePowerFromPrev(st.ThisEpochBaselinePower)
+	st.ThisEpochBaselinePower = minting.BaselinePowerFromPrev(st.ThisEpochBaselinePower)
 	cappedRealizedPower := big.Min(st.ThisEpochBaselinePower, currRealizedPower)
 	st.CumsumRealized = big.Add(st.CumsumRealized, cappedRealizedPower)
 
 	for st.CumsumRealized.GreaterThan(st.CumsumBaseline) {
 		st.EffectiveNetworkTime++
-		st.EffectiveBaselinePower = BaselinePowerFromPrev(st.EffectiveBaselinePower)
+		st.EffectiveBaselinePower = minting.BaselinePowerFromPrev(st.EffectiveBaselinePower)
 		st.CumsumBaseline = big.Add(st.CumsumBaseline, st.EffectiveBaselinePower)
 	}
 }
 
 // Takes in a current realized power for a reward epoch and computes
 // and updates reward state to track reward for the next epoch
-func (st *State) updateToNextEpochWithReward(currRealizedPower abi.StoragePower) {
+func (st *State) updateToNextEpochWithReward(currRealizedPower abi.StoragePower, minting MintingFunction) {
 	prevRewardTheta := ComputeRTheta(st.EffectiveNetworkTime, st.EffectiveBaselinePower, st.CumsumRealized, st.CumsumBaseline)
-	st.updateToNextEpoch(currRealizedPower)
+	st.updateToNextEpoch(currRealizedPower, minting)
 	currRewardTheta := ComputeRTheta(st.EffectiveNetworkTime, st.EffectiveBaselinePower, st.CumsumRealized, st.CumsumBaseline)
 
-	st.ThisEpochReward = computeReward(st.Epoch, prevRewardTheta, currRewardTheta, st.SimpleTotal, st.BaselineTotal)
+	st.ThisEpochReward = minting.ComputeReward(st.Epoch, prevRewardTheta, currRewardTheta, st.SimpleTotal, st.BaselineTotal)
 }
 
-func (st *State) updateSmoothedEstimates(delta abi.ChainEpoch) {
-	filterReward := smoothing.LoadFilter(st.ThisEpochRewardSmoothed, smoothing.DefaultAlpha, smoothing.DefaultBeta)
-	st.ThisEpochRewardSmoothed = filterReward.NextEstimate(st.ThisEpochReward, delta)
+func (st *State) updateSmoothedEstimates(delta abi.ChainEpoch, config smoothing.FilterConfig) {
+	st.ThisEpochRewardSmoothed = smoothing.NextEstimateWithConfig(st.ThisEpochRewardSmoothed, st.ThisEpochReward, delta, config)
 }
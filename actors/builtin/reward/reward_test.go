@@ -293,7 +293,7 @@ func (h *rewardHarness) awardBlockReward(rt *mock.Runtime, miner address.Address
 func (h *rewardHarness) thisEpochReward(rt *mock.Runtime) *reward.ThisEpochRewardReturn {
 	rt.ExpectValidateCallerAny()
 
-	ret := rt.Call(h.ThisEpochReward, nil)
+	ret := rt.CallReadOnly(h.ThisEpochReward, nil)
 	rt.Verify()
 
 	resp, ok := ret.(*reward.ThisEpochRewardReturn)
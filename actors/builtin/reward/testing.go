@@ -0,0 +1,33 @@
+package reward
+
+import (
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+)
+
+type StateSummary struct {
+	ThisEpochReward         abi.TokenAmount
+	TotalStoragePowerReward abi.TokenAmount
+}
+
+// Checks internal invariants of reward state.
+func CheckStateInvariants(st *State, priorEpoch abi.ChainEpoch) (*StateSummary, *builtin.MessageAccumulator) {
+	acc := &builtin.MessageAccumulator{}
+
+	// The reward is computed at the end of each epoch for the next.
+	acc.Require(st.Epoch <= priorEpoch+1, "reward state epoch %d is after prior epoch %d", st.Epoch, priorEpoch)
+	acc.Require(st.ThisEpochReward.GreaterThanEqual(big.Zero()), "negative epoch reward %v", st.ThisEpochReward)
+	acc.Require(st.ThisEpochBaselinePower.GreaterThanEqual(big.Zero()), "negative baseline power %v", st.ThisEpochBaselinePower)
+	acc.Require(st.TotalStoragePowerReward.GreaterThanEqual(big.Zero()), "negative total storage power reward %v", st.TotalStoragePowerReward)
+	acc.Require(st.TotalStoragePowerReward.LessThanEqual(big.Add(st.SimpleTotal, st.BaselineTotal)),
+		"total storage power reward %v exceeds simple total %v plus baseline total %v", st.TotalStoragePowerReward, st.SimpleTotal, st.BaselineTotal)
+	acc.Require(st.CumsumRealized.LessThanEqual(st.CumsumBaseline),
+		"cumulative realized power %v exceeds cumulative baseline %v", st.CumsumRealized, st.CumsumBaseline)
+
+	return &StateSummary{
+		ThisEpochReward:         st.ThisEpochReward,
+		TotalStoragePowerReward: st.TotalStoragePowerReward,
+	}, acc
+}
@@ -8,7 +8,9 @@ import (
 	addr "github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/cbor"
 	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/filecoin-project/go-state-types/network"
 
 	builtin0 "github.com/filecoin-project/specs-actors/actors/builtin"
 	"github.com/filecoin-project/specs-actors/v2/actors/runtime"
@@ -21,6 +23,30 @@ type BigFrac struct {
 	Denominator big.Int
 }
 
+// Whether the VM rejects method params which are not canonically encoded, aborting with ErrSerialization before
+// decoding them. Decoders accept some non-canonical encodings, such as map keys out of order or bytes following the
+// params, which implementations may not all accept, so requiring canonical params makes their rejection deterministic.
+// See cbor.ValidateCanonical for the rules.
+var RequireCanonicalParams = false
+
+// Implemented by types whose encodings have a maximum length, generated with their encoders from the limits the
+// decoders place on collections, strings and bytes.
+// The VM rejects params longer than this with ErrSerialization, without decoding them.
+type BoundedEncoding interface {
+	MaxEncodedLength() int64
+}
+
+// Implemented by types encoded as a tuple of their fields before a network version, and as a map keyed by field
+// name from that version on, so that fields may later be added or removed without breaking existing encodings.
+// The decoder accepts either encoding, and MarshalCBOR writes the tuple. The VM rejects params encoded as a map
+// before the version with ErrSerialization.
+type VersionedEncoding interface {
+	// The network version from which the type is encoded as a map.
+	MapEncodingVersion() network.Version
+	// Writes the type's encoding at a network version.
+	MarshalCBORForVersion(w io.Writer, nv network.Version) error
+}
+
 // Wraps already-serialized bytes as CBOR-marshalable.
 type CBORBytes []byte
 
@@ -61,11 +87,28 @@ func RequireNoErr(rt runtime.Runtime, err error, defaultExitCode exitcode.ExitCo
 	}
 }
 
+// Sends a message and unmarshals its return value into out, propagating a failure by aborting with the
+// callee's exit code. A nil out discards the return value.
+func SendAndUnmarshal(rt runtime.Runtime, to addr.Address, method abi.MethodNum, params cbor.Marshaler, value abi.TokenAmount, out cbor.Er) {
+	if out == nil {
+		out = &Discard{}
+	}
+	code := rt.Send(to, method, params, value, out)
+	RequireSuccess(rt, code, "failed to send method %d to %v", method, to)
+}
+
+// Sends a read-only message and unmarshals its return value into out, as for SendAndUnmarshal.
+func SendReadOnlyAndUnmarshal(rt runtime.Runtime, to addr.Address, method abi.MethodNum, params cbor.Marshaler, out cbor.Er) {
+	if out == nil {
+		out = &Discard{}
+	}
+	code := rt.SendReadOnly(to, method, params, out)
+	RequireSuccess(rt, code, "failed to send read-only method %d to %v", method, to)
+}
+
 func RequestMinerControlAddrs(rt runtime.Runtime, minerAddr addr.Address) (ownerAddr addr.Address, workerAddr addr.Address, controlAddrs []addr.Address) {
 	var addrs MinerAddrs
-	code := rt.Send(minerAddr, MethodsMiner.ControlAddresses, nil, abi.NewTokenAmount(0), &addrs)
-	RequireSuccess(rt, code, "failed fetching control addresses")
-
+	SendReadOnlyAndUnmarshal(rt, minerAddr, MethodsMiner.ControlAddresses, nil, &addrs)
 	return addrs.Owner, addrs.Worker, addrs.ControlAddrs
 }
 
@@ -0,0 +1,66 @@
+package builtin_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/stretchr/testify/assert"
+	cbg "github.com/whyrusleeping/cbor-gen"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v2/actors/runtime"
+	"github.com/filecoin-project/specs-actors/v2/support/mock"
+	tutil "github.com/filecoin-project/specs-actors/v2/support/testing"
+)
+
+func TestSendAndUnmarshal(t *testing.T) {
+	receiver := tutil.NewIDAddr(t, 100)
+	target := tutil.NewIDAddr(t, 101)
+	builder := mock.NewBuilder(context.Background(), receiver).
+		WithBalance(big.NewInt(10), big.Zero())
+	method := abi.MethodNum(7)
+
+	var got cbg.CborInt
+	send := func(rt runtime.Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
+		builtin.SendAndUnmarshal(rt, target, method, nil, big.NewInt(10), &got)
+		return nil
+	}
+	sendReadOnly := func(rt runtime.Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
+		builtin.SendReadOnlyAndUnmarshal(rt, target, method, nil, &got)
+		return nil
+	}
+
+	t.Run("unmarshals return value", func(t *testing.T) {
+		rt := builder.Build(t)
+		ret := cbg.CborInt(42)
+		rt.ExpectSend(target, method, nil, big.NewInt(10), &ret, exitcode.Ok)
+		rt.Call(send, nil)
+		rt.Verify()
+		assert.Equal(t, cbg.CborInt(42), got)
+
+		ret = cbg.CborInt(43)
+		rt.ExpectSendReadOnly(target, method, nil, &ret, exitcode.Ok)
+		rt.Call(sendReadOnly, nil)
+		rt.Verify()
+		assert.Equal(t, cbg.CborInt(43), got)
+	})
+
+	t.Run("aborts with callee exit code", func(t *testing.T) {
+		rt := builder.Build(t)
+		ret := cbg.CborInt(0)
+		rt.ExpectSend(target, method, nil, big.NewInt(10), &ret, exitcode.ErrInsufficientFunds)
+		rt.ExpectAbort(exitcode.ErrInsufficientFunds, func() {
+			rt.Call(send, nil)
+		})
+		rt.Verify()
+
+		rt.ExpectSendReadOnly(target, method, nil, &ret, exitcode.ErrNotFound)
+		rt.ExpectAbort(exitcode.ErrNotFound, func() {
+			rt.Call(sendReadOnly, nil)
+		})
+		rt.Verify()
+	})
+}
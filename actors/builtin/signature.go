@@ -0,0 +1,53 @@
+package builtin
+
+import (
+	addr "github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/crypto"
+	"github.com/pkg/errors"
+)
+
+// Metadata about a signature scheme with which account actors authenticate messages.
+type SignaturePolicy struct {
+	SigType crypto.SigType // Type of the signatures made by keys identified by addresses of the protocol.
+}
+
+// Signature schemes supported for account actors, keyed by the protocol of the account's pubkey address.
+// The runtime checks the type of each signature it verifies against this registry (see CheckSignatureType), so
+// supporting a new scheme requires an entry here and the cryptographic verification of its signatures only.
+var SignaturePolicies = map[addr.Protocol]*SignaturePolicy{
+	addr.SECP256K1: {
+		SigType: crypto.SigTypeSecp256k1,
+	},
+	addr.BLS: {
+		SigType: crypto.SigTypeBLS,
+	},
+}
+
+// Checks whether an address identifies a key of a supported signature scheme, and so may be the pubkey
+// address of an account actor.
+func IsSignableAddress(a addr.Address) bool {
+	_, ok := SignaturePolicies[a.Protocol()]
+	return ok
+}
+
+// Returns the type of signatures made by the key identified by an address.
+func AddressSignatureType(a addr.Address) (crypto.SigType, error) {
+	policy, ok := SignaturePolicies[a.Protocol()]
+	if !ok {
+		return 0, errors.Errorf("unsupported signature address protocol: %v", a.Protocol())
+	}
+	return policy.SigType, nil
+}
+
+// Checks that a signature is of the type made by the key a signer's pubkey address identifies, as the runtime does
+// before verifying a signature.
+func CheckSignatureType(sig crypto.Signature, signer addr.Address) error {
+	sigType, err := AddressSignatureType(signer)
+	if err != nil {
+		return err
+	}
+	if sig.Type != sigType {
+		return errors.Errorf("signature type %d does not match signer %v", sig.Type, signer)
+	}
+	return nil
+}
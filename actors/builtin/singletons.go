@@ -1,25 +1,144 @@
 package builtin
 
 import (
+	"fmt"
+	"sort"
+
 	addr "github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/ipfs/go-cid"
 )
 
 // Addresses for singleton system actors.
 var (
 	// Distinguished AccountActor that is the source of system implicit messages.
-	SystemActorAddr           = mustMakeAddress(0)
-	InitActorAddr             = mustMakeAddress(1)
-	RewardActorAddr           = mustMakeAddress(2)
-	CronActorAddr             = mustMakeAddress(3)
-	StoragePowerActorAddr     = mustMakeAddress(4)
-	StorageMarketActorAddr    = mustMakeAddress(5)
-	VerifiedRegistryActorAddr = mustMakeAddress(6)
+	SystemActorAddr = mustRegisterSingleton(singleton{name: "system", id: 0, code: &SystemActorCodeID})
+	InitActorAddr   = mustRegisterSingleton(singleton{name: "init", id: 1, code: &InitActorCodeID})
+	RewardActorAddr = mustRegisterSingleton(singleton{name: "reward", id: 2, code: &RewardActorCodeID})
+	CronActorAddr   = mustRegisterSingleton(singleton{name: "cron", id: 3, code: &CronActorCodeID})
+	// The power and market actors are invoked by cron at the end of every epoch.
+	StoragePowerActorAddr      = mustRegisterSingleton(singleton{name: "storagepower", id: 4, code: &StoragePowerActorCodeID, cronMethod: MethodsPower.OnEpochTickEnd, cronPriority: PowerCronEntryPriority})
+	StorageMarketActorAddr     = mustRegisterSingleton(singleton{name: "storagemarket", id: 5, code: &StorageMarketActorCodeID, cronMethod: MethodsMarket.CronTick, cronPriority: MarketCronEntryPriority})
+	VerifiedRegistryActorAddr  = mustRegisterSingleton(singleton{name: "verifiedregistry", id: 6, code: &VerifiedRegistryActorCodeID})
+	ParameterRegistryActorAddr = mustRegisterSingleton(singleton{name: "parameterregistry", id: 7, code: &ParameterRegistryActorCodeID})
 	// Distinguished AccountActor that is the destination of all burnt funds.
-	BurntFundsActorAddr = mustMakeAddress(99)
+	BurntFundsActorAddr = mustRegisterSingleton(singleton{name: "burntfunds", id: 99, code: &AccountActorCodeID})
 )
 
 const FirstNonSingletonActorId = 100
 
+// Priorities of the singleton actors' cron entries.
+// The power actor's cron tick is invoked before the market actor's.
+const (
+	PowerCronEntryPriority  uint64 = 100
+	MarketCronEntryPriority uint64 = 200
+)
+
+// A system actor of which there is exactly one instance, at a fixed ID-address.
+type SingletonActor struct {
+	Name    string
+	Address addr.Address
+	Code    cid.Cid
+	// The method the cron actor invokes on this actor at the end of every epoch, with empty params, or zero for none.
+	CronMethod abi.MethodNum
+	// The order of this actor's cron entry: entries with lower values are invoked first.
+	CronPriority uint64
+}
+
+type singleton struct {
+	name         string
+	id           abi.ActorID
+	code         *cid.Cid // Indirect, since code CIDs are computed after the builtin singletons are registered.
+	cronMethod   abi.MethodNum
+	cronPriority uint64
+}
+
+// Registered singletons, by ID.
+var singletons = map[abi.ActorID]singleton{}
+
+// Registers an additional singleton system actor, returning its address.
+// The actor must be created at that address by a state migration or at genesis; registration makes it known to
+// the init and cron actors and to tools describing addresses.
+// This is intended to be called during process initialization, before any actor code executes.
+func RegisterSingleton(name string, id abi.ActorID, code cid.Cid, cronMethod abi.MethodNum, cronPriority uint64) (addr.Address, error) {
+	return registerSingleton(singleton{name: name, id: id, code: &code, cronMethod: cronMethod, cronPriority: cronPriority})
+}
+
+func registerSingleton(s singleton) (addr.Address, error) {
+	if s.name == "" {
+		return addr.Undef, fmt.Errorf("empty singleton actor name")
+	}
+	if s.id >= FirstNonSingletonActorId {
+		return addr.Undef, fmt.Errorf("singleton actor %s ID %d not below %d", s.name, s.id, FirstNonSingletonActorId)
+	}
+	for id, existing := range singletons { //nolint:nomaprange
+		if id == s.id || existing.name == s.name {
+			return addr.Undef, fmt.Errorf("singleton actor %s at ID %d conflicts with %s at ID %d", s.name, s.id, existing.name, id)
+		}
+	}
+	singletons[s.id] = s
+	return addr.NewIDAddress(uint64(s.id))
+}
+
+func mustRegisterSingleton(s singleton) addr.Address {
+	address, err := registerSingleton(s)
+	if err != nil {
+		panic(err)
+	}
+	return address
+}
+
+func (s singleton) export() SingletonActor {
+	return SingletonActor{
+		Name:         s.name,
+		Address:      mustMakeAddress(uint64(s.id)),
+		Code:         *s.code,
+		CronMethod:   s.cronMethod,
+		CronPriority: s.cronPriority,
+	}
+}
+
+// Returns all registered singleton actors, ordered by ID.
+func Singletons() []SingletonActor {
+	ids := make([]abi.ActorID, 0, len(singletons))
+	for id := range singletons { //nolint:nomaprange
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	out := make([]SingletonActor, len(ids))
+	for i, id := range ids {
+		out[i] = singletons[id].export()
+	}
+	return out
+}
+
+// Returns the singleton actor with a name, and whether it is registered.
+func SingletonByName(name string) (SingletonActor, bool) {
+	for _, s := range singletons { //nolint:nomaprange
+		if s.name == name {
+			return s.export(), true
+		}
+	}
+	return SingletonActor{}, false
+}
+
+// Returns the singleton actor at an address, and whether there is one.
+// Only ID-addresses are singleton addresses.
+func SingletonByAddress(address addr.Address) (SingletonActor, bool) {
+	if address.Protocol() != addr.ID {
+		return SingletonActor{}, false
+	}
+	id, err := addr.IDFromAddress(address)
+	if err != nil {
+		return SingletonActor{}, false
+	}
+	s, ok := singletons[abi.ActorID(id)]
+	if !ok {
+		return SingletonActor{}, false
+	}
+	return s.export(), true
+}
+
 func mustMakeAddress(id uint64) addr.Address {
 	address, err := addr.NewIDAddress(id)
 	if err != nil {
@@ -0,0 +1,66 @@
+package builtin_test
+
+import (
+	"testing"
+
+	addr "github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	tutil "github.com/filecoin-project/specs-actors/v2/support/testing"
+)
+
+func TestSingletonRegistry(t *testing.T) {
+	t.Run("builtin singletons", func(t *testing.T) {
+		s, ok := builtin.SingletonByName("storagepower")
+		require.True(t, ok)
+		assert.Equal(t, builtin.StoragePowerActorAddr, s.Address)
+		assert.Equal(t, builtin.StoragePowerActorCodeID, s.Code)
+		assert.Equal(t, builtin.MethodsPower.OnEpochTickEnd, s.CronMethod)
+
+		s, ok = builtin.SingletonByAddress(builtin.BurntFundsActorAddr)
+		require.True(t, ok)
+		assert.Equal(t, "burntfunds", s.Name)
+		assert.Equal(t, builtin.AccountActorCodeID, s.Code)
+
+		_, ok = builtin.SingletonByAddress(tutil.NewIDAddr(t, builtin.FirstNonSingletonActorId))
+		assert.False(t, ok)
+		_, ok = builtin.SingletonByAddress(tutil.NewBLSAddr(t, 1))
+		assert.False(t, ok)
+		_, ok = builtin.SingletonByName("nonesuch")
+		assert.False(t, ok)
+
+		all := builtin.Singletons()
+		require.True(t, len(all) >= 9)
+		assert.Equal(t, builtin.SystemActorAddr, all[0].Address)
+		for i := 1; i < len(all); i++ {
+			prev, err := addr.IDFromAddress(all[i-1].Address)
+			require.NoError(t, err)
+			id, err := addr.IDFromAddress(all[i].Address)
+			require.NoError(t, err)
+			assert.Less(t, prev, id)
+		}
+	})
+
+	t.Run("register", func(t *testing.T) {
+		code := builtin.VerifiedRegistryActorCodeID
+		_, err := builtin.RegisterSingleton("", 50, code, 0, 0)
+		assert.Error(t, err)
+		_, err = builtin.RegisterSingleton("datacap", abi.ActorID(builtin.FirstNonSingletonActorId), code, 0, 0)
+		assert.Error(t, err)
+		_, err = builtin.RegisterSingleton("datacap", 4, code, 0, 0)
+		assert.Error(t, err)
+		_, err = builtin.RegisterSingleton("storagepower", 50, code, 0, 0)
+		assert.Error(t, err)
+
+		address, err := builtin.RegisterSingleton("datacap", 50, code, 0, 0)
+		require.NoError(t, err)
+		assert.Equal(t, tutil.NewIDAddr(t, 50), address)
+		s, ok := builtin.SingletonByAddress(address)
+		require.True(t, ok)
+		assert.Equal(t, "datacap", s.Name)
+		assert.Equal(t, code, s.Code)
+	})
+}
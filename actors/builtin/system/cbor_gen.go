@@ -6,13 +6,14 @@ import (
 	"fmt"
 	"io"
 
+	acbor "github.com/filecoin-project/specs-actors/v2/actors/util/cbor"
 	cbg "github.com/whyrusleeping/cbor-gen"
 	xerrors "golang.org/x/xerrors"
 )
 
 var _ = xerrors.Errorf
 
-var lengthBufState = []byte{128}
+var lengthBufState = []byte{130}
 
 func (t *State) MarshalCBOR(w io.Writer) error {
 	if t == nil {
@@ -23,6 +24,26 @@ func (t *State) MarshalCBOR(w io.Writer) error {
 		return err
 	}
 
+	scratch := make([]byte, 9)
+
+	// t.NetworkName (string) (string)
+	if len(t.NetworkName) > cbg.MaxLength {
+		return xerrors.Errorf("Value in field t.NetworkName was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajTextString, uint64(len(t.NetworkName))); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, string(t.NetworkName)); err != nil {
+		return err
+	}
+
+	// t.NetworkVersion (uint64) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.NetworkVersion)); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -34,15 +55,209 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
+		return acbor.WrapDecodeError(err, "system.State", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "system.State", "")
+	}
+
+	if extra != 2 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "system.State", "")
+	}
+
+	// t.NetworkName (string) (string)
+
+	{
+		sval, err := cbg.ReadStringBuf(br, scratch)
+		if err != nil {
+			return acbor.WrapDecodeError(err, "system.State", "NetworkName")
+		}
+
+		t.NetworkName = string(sval)
+	}
+	// t.NetworkVersion (uint64) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return acbor.WrapDecodeError(err, "system.State", "NetworkVersion")
+		}
+		if maj != cbg.MajUnsignedInt {
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "system.State", "NetworkVersion")
+		}
+		t.NetworkVersion = uint64(extra)
+
+	}
+	return nil
+}
+
+var lengthBufConstructorParams = []byte{130}
+
+func (t *ConstructorParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufConstructorParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.NetworkName (string) (string)
+	if len(t.NetworkName) > cbg.MaxLength {
+		return xerrors.Errorf("Value in field t.NetworkName was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajTextString, uint64(len(t.NetworkName))); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, string(t.NetworkName)); err != nil {
+		return err
+	}
+
+	// t.NetworkVersion (uint64) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.NetworkVersion)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (t *ConstructorParams) UnmarshalCBOR(r io.Reader) error {
+	*t = ConstructorParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "system.ConstructorParams", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "system.ConstructorParams", "")
+	}
+
+	if extra != 2 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "system.ConstructorParams", "")
+	}
+
+	// t.NetworkName (string) (string)
+
+	{
+		sval, err := cbg.ReadStringBuf(br, scratch)
+		if err != nil {
+			return acbor.WrapDecodeError(err, "system.ConstructorParams", "NetworkName")
+		}
+
+		t.NetworkName = string(sval)
+	}
+	// t.NetworkVersion (uint64) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return acbor.WrapDecodeError(err, "system.ConstructorParams", "NetworkVersion")
+		}
+		if maj != cbg.MajUnsignedInt {
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "system.ConstructorParams", "NetworkVersion")
+		}
+		t.NetworkVersion = uint64(extra)
+
+	}
+	return nil
+}
+
+var lengthBufNetworkIdentityReturn = []byte{130}
+
+func (t *NetworkIdentityReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufNetworkIdentityReturn); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.NetworkName (string) (string)
+	if len(t.NetworkName) > cbg.MaxLength {
+		return xerrors.Errorf("Value in field t.NetworkName was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajTextString, uint64(len(t.NetworkName))); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, string(t.NetworkName)); err != nil {
 		return err
 	}
+
+	// t.NetworkVersion (uint64) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.NetworkVersion)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (t *NetworkIdentityReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = NetworkIdentityReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "system.NetworkIdentityReturn", "")
+	}
 	if maj != cbg.MajArray {
-		return fmt.Errorf("cbor input should be of type array")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "system.NetworkIdentityReturn", "")
 	}
 
-	if extra != 0 {
-		return fmt.Errorf("cbor input had wrong number of fields")
+	if extra != 2 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "system.NetworkIdentityReturn", "")
 	}
 
+	// t.NetworkName (string) (string)
+
+	{
+		sval, err := cbg.ReadStringBuf(br, scratch)
+		if err != nil {
+			return acbor.WrapDecodeError(err, "system.NetworkIdentityReturn", "NetworkName")
+		}
+
+		t.NetworkName = string(sval)
+	}
+	// t.NetworkVersion (uint64) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return acbor.WrapDecodeError(err, "system.NetworkIdentityReturn", "NetworkVersion")
+		}
+		if maj != cbg.MajUnsignedInt {
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "system.NetworkIdentityReturn", "NetworkVersion")
+		}
+		t.NetworkVersion = uint64(extra)
+
+	}
 	return nil
 }
+
+func (t *State) MaxEncodedLength() int64 {
+	return 8205
+}
+
+func (t *ConstructorParams) MaxEncodedLength() int64 {
+	return 8205
+}
+
+func (t *NetworkIdentityReturn) MaxEncodedLength() int64 {
+	return 8205
+}
@@ -3,6 +3,7 @@ package system
 import (
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/cbor"
+	"github.com/filecoin-project/go-state-types/network"
 	"github.com/ipfs/go-cid"
 
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
@@ -14,6 +15,7 @@ type Actor struct{}
 func (a Actor) Exports() []interface{} {
 	return []interface{}{
 		builtin.MethodConstructor: a.Constructor,
+		2:                         a.NetworkIdentity,
 	}
 }
 
@@ -31,11 +33,45 @@ func (a Actor) State() cbor.Er {
 
 var _ runtime.VMActor = Actor{}
 
-func (a Actor) Constructor(rt runtime.Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
+type ConstructorParams struct {
+	NetworkName    string
+	NetworkVersion uint64 // network.Version
+}
+
+func (a Actor) Constructor(rt runtime.Runtime, params *ConstructorParams) *abi.EmptyValue {
 	rt.ValidateImmediateCallerIs(builtin.SystemActorAddr)
 
-	rt.StateCreate(&State{})
+	rt.StateCreate(ConstructState(params.NetworkName, network.Version(params.NetworkVersion)))
 	return nil
 }
 
-type State struct{}
+type NetworkIdentityReturn struct {
+	NetworkName    string
+	NetworkVersion uint64 // network.Version
+}
+
+// Returns the name of the network and the network version at which the actors' state was last migrated.
+func (a Actor) NetworkIdentity(rt runtime.Runtime, _ *abi.EmptyValue) *NetworkIdentityReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	var st State
+	rt.StateReadonly(&st)
+	return &NetworkIdentityReturn{
+		NetworkName:    st.NetworkName,
+		NetworkVersion: st.NetworkVersion,
+	}
+}
+
+type State struct {
+	// Name of the network, set at genesis.
+	NetworkName string
+	// Network version of the actors' state, set at genesis and at each state migration.
+	NetworkVersion uint64 // network.Version
+}
+
+func ConstructState(networkName string, networkVersion network.Version) *State {
+	return &State{
+		NetworkName:    networkName,
+		NetworkVersion: uint64(networkVersion),
+	}
+}
@@ -4,11 +4,14 @@ import (
 	"context"
 	"testing"
 
+	"github.com/filecoin-project/go-state-types/network"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/system"
 	"github.com/filecoin-project/specs-actors/v2/support/mock"
+	tutil "github.com/filecoin-project/specs-actors/v2/support/testing"
 )
 
 func TestExports(t *testing.T) {
@@ -21,11 +24,31 @@ func TestConstruction(t *testing.T) {
 
 	rt.ExpectValidateCallerAddr(builtin.SystemActorAddr)
 	rt.SetCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
-	rt.Call(a.Constructor, nil)
+	rt.Call(a.Constructor, &system.ConstructorParams{NetworkName: "mock", NetworkVersion: uint64(network.Version4)})
 	rt.Verify()
 
 	var st system.State
 	rt.GetState(&st)
 
-	require.Equal(t, system.State{}, st)
+	require.Equal(t, *system.ConstructState("mock", network.Version4), st)
+	_, msgs := system.CheckStateInvariants(&st)
+	assert.True(t, msgs.IsEmpty())
+}
+
+func TestNetworkIdentity(t *testing.T) {
+	rt := mock.NewBuilder(context.Background(), builtin.SystemActorAddr).Build(t)
+	a := system.Actor{}
+
+	rt.ExpectValidateCallerAddr(builtin.SystemActorAddr)
+	rt.SetCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
+	rt.Call(a.Constructor, &system.ConstructorParams{NetworkName: "mock", NetworkVersion: uint64(network.Version4)})
+	rt.Verify()
+
+	rt.SetCaller(tutil.NewIDAddr(t, 1000), builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(a.NetworkIdentity, nil).(*system.NetworkIdentityReturn)
+	rt.Verify()
+
+	assert.Equal(t, "mock", ret.NetworkName)
+	assert.Equal(t, uint64(network.Version4), ret.NetworkVersion)
 }
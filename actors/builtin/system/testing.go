@@ -0,0 +1,23 @@
+package system
+
+import (
+	"github.com/filecoin-project/go-state-types/network"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+)
+
+type StateSummary struct {
+	NetworkName    string
+	NetworkVersion network.Version
+}
+
+// Checks internal invariants of system state.
+func CheckStateInvariants(st *State) (*StateSummary, *builtin.MessageAccumulator) {
+	acc := &builtin.MessageAccumulator{}
+	acc.Require(len(st.NetworkName) > 0, "network name is empty")
+
+	return &StateSummary{
+		NetworkName:    st.NetworkName,
+		NetworkVersion: network.Version(st.NetworkVersion),
+	}, acc
+}
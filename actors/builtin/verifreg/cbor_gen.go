@@ -6,13 +6,18 @@ import (
 	"fmt"
 	"io"
 
+	address "github.com/filecoin-project/go-address"
+	abi "github.com/filecoin-project/go-state-types/abi"
+	exitcode "github.com/filecoin-project/go-state-types/exitcode"
+	verifreg "github.com/filecoin-project/specs-actors/actors/builtin/verifreg"
+	acbor "github.com/filecoin-project/specs-actors/v2/actors/util/cbor"
 	cbg "github.com/whyrusleeping/cbor-gen"
 	xerrors "golang.org/x/xerrors"
 )
 
 var _ = xerrors.Errorf
 
-var lengthBufState = []byte{131}
+var lengthBufState = []byte{133}
 
 func (t *State) MarshalCBOR(w io.Writer) error {
 	if t == nil {
@@ -42,6 +47,18 @@ func (t *State) MarshalCBOR(w io.Writer) error {
 		return xerrors.Errorf("failed to write cid field t.VerifiedClients: %w", err)
 	}
 
+	// t.DataCapEvents (cid.Cid) (struct)
+
+	if err := cbg.WriteCidBuf(scratch, w, t.DataCapEvents); err != nil {
+		return xerrors.Errorf("failed to write cid field t.DataCapEvents: %w", err)
+	}
+
+	// t.RemoveDataCapProposalIDs (cid.Cid) (struct)
+
+	if err := cbg.WriteCidBuf(scratch, w, t.RemoveDataCapProposalIDs); err != nil {
+		return xerrors.Errorf("failed to write cid field t.RemoveDataCapProposalIDs: %w", err)
+	}
+
 	return nil
 }
 
@@ -53,14 +70,14 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "verifreg.State", "")
 	}
 	if maj != cbg.MajArray {
-		return fmt.Errorf("cbor input should be of type array")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "verifreg.State", "")
 	}
 
-	if extra != 3 {
-		return fmt.Errorf("cbor input had wrong number of fields")
+	if extra != 5 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "verifreg.State", "")
 	}
 
 	// t.RootKey (address.Address) (struct)
@@ -68,7 +85,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.RootKey.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.RootKey: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.RootKey: %w", err), "verifreg.State", "RootKey")
 		}
 
 	}
@@ -78,7 +95,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 		c, err := cbg.ReadCid(br)
 		if err != nil {
-			return xerrors.Errorf("failed to read cid field t.Verifiers: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read cid field t.Verifiers: %w", err), "verifreg.State", "Verifiers")
 		}
 
 		t.Verifiers = c
@@ -90,11 +107,1406 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 		c, err := cbg.ReadCid(br)
 		if err != nil {
-			return xerrors.Errorf("failed to read cid field t.VerifiedClients: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read cid field t.VerifiedClients: %w", err), "verifreg.State", "VerifiedClients")
 		}
 
 		t.VerifiedClients = c
 
 	}
+	// t.DataCapEvents (cid.Cid) (struct)
+
+	{
+
+		c, err := cbg.ReadCid(br)
+		if err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read cid field t.DataCapEvents: %w", err), "verifreg.State", "DataCapEvents")
+		}
+
+		t.DataCapEvents = c
+
+	}
+	// t.RemoveDataCapProposalIDs (cid.Cid) (struct)
+
+	{
+
+		c, err := cbg.ReadCid(br)
+		if err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("failed to read cid field t.RemoveDataCapProposalIDs: %w", err), "verifreg.State", "RemoveDataCapProposalIDs")
+		}
+
+		t.RemoveDataCapProposalIDs = c
+
+	}
+	return nil
+}
+
+var lengthBufGetDataCapEventsParams = []byte{131}
+
+func (t *GetDataCapEventsParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufGetDataCapEventsParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.FromEpoch (abi.ChainEpoch) (int64)
+	if t.FromEpoch >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.FromEpoch)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.FromEpoch-1)); err != nil {
+			return err
+		}
+	}
+
+	// t.ToEpoch (abi.ChainEpoch) (int64)
+	if t.ToEpoch >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.ToEpoch)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.ToEpoch-1)); err != nil {
+			return err
+		}
+	}
+
+	// t.Cursor (verifreg.DataCapEventsCursor) (struct)
+	if t.Cursor == nil {
+		if _, err := w.Write(cbg.CborNull); err != nil {
+			return err
+		}
+	} else if err := t.Cursor.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *GetDataCapEventsParams) UnmarshalCBOR(r io.Reader) error {
+	*t = GetDataCapEventsParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "verifreg.GetDataCapEventsParams", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "verifreg.GetDataCapEventsParams", "")
+	}
+
+	fieldCount := extra
+	if fieldCount < 2 || fieldCount > 3 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "verifreg.GetDataCapEventsParams", "")
+	}
+
+	// t.FromEpoch (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return acbor.WrapDecodeError(err, "verifreg.GetDataCapEventsParams", "FromEpoch")
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "verifreg.GetDataCapEventsParams", "FromEpoch")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "verifreg.GetDataCapEventsParams", "FromEpoch")
+			}
+			extraI = -1 - extraI
+		default:
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "verifreg.GetDataCapEventsParams", "FromEpoch")
+		}
+
+		t.FromEpoch = abi.ChainEpoch(extraI)
+	}
+	// t.ToEpoch (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return acbor.WrapDecodeError(err, "verifreg.GetDataCapEventsParams", "ToEpoch")
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "verifreg.GetDataCapEventsParams", "ToEpoch")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "verifreg.GetDataCapEventsParams", "ToEpoch")
+			}
+			extraI = -1 - extraI
+		default:
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "verifreg.GetDataCapEventsParams", "ToEpoch")
+		}
+
+		t.ToEpoch = abi.ChainEpoch(extraI)
+	}
+	if fieldCount <= 2 {
+		return nil
+	}
+	// t.Cursor (verifreg.DataCapEventsCursor) (struct)
+
+	{
+
+		b, err := br.ReadByte()
+		if err != nil {
+			return acbor.WrapDecodeError(err, "verifreg.GetDataCapEventsParams", "Cursor")
+		}
+		if b != cbg.CborNull[0] {
+			if err := br.UnreadByte(); err != nil {
+				return acbor.WrapDecodeError(err, "verifreg.GetDataCapEventsParams", "Cursor")
+			}
+			t.Cursor = new(DataCapEventsCursor)
+			if err := t.Cursor.UnmarshalCBOR(br); err != nil {
+				return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Cursor pointer: %w", err), "verifreg.GetDataCapEventsParams", "Cursor")
+			}
+		}
+
+	}
+	return nil
+}
+
+var lengthBufGetDataCapEventsReturn = []byte{130}
+
+func (t *GetDataCapEventsReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufGetDataCapEventsReturn); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Events ([]verifreg.DataCapEventRecord) (slice)
+	if len(t.Events) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Events was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Events))); err != nil {
+		return err
+	}
+	for _, v := range t.Events {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+
+	// t.Next (verifreg.DataCapEventsCursor) (struct)
+	if t.Next == nil {
+		if _, err := w.Write(cbg.CborNull); err != nil {
+			return err
+		}
+	} else if err := t.Next.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *GetDataCapEventsReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = GetDataCapEventsReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "verifreg.GetDataCapEventsReturn", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "verifreg.GetDataCapEventsReturn", "")
+	}
+
+	fieldCount := extra
+	if fieldCount < 1 || fieldCount > 2 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "verifreg.GetDataCapEventsReturn", "")
+	}
+
+	// t.Events ([]verifreg.DataCapEventRecord) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "verifreg.GetDataCapEventsReturn", "Events")
+	}
+
+	if extra > cbg.MaxLength {
+		return acbor.WrapDecodeError(fmt.Errorf("t.Events: array too large (%d)", extra), "verifreg.GetDataCapEventsReturn", "Events")
+	}
+
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("expected cbor array"), "verifreg.GetDataCapEventsReturn", "Events")
+	}
+
+	if extra > 0 {
+		t.Events = make([]DataCapEventRecord, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v DataCapEventRecord
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(err, "verifreg.GetDataCapEventsReturn", fmt.Sprintf("Events[%d]", i))
+		}
+
+		t.Events[i] = v
+	}
+
+	if fieldCount <= 1 {
+		return nil
+	}
+	// t.Next (verifreg.DataCapEventsCursor) (struct)
+
+	{
+
+		b, err := br.ReadByte()
+		if err != nil {
+			return acbor.WrapDecodeError(err, "verifreg.GetDataCapEventsReturn", "Next")
+		}
+		if b != cbg.CborNull[0] {
+			if err := br.UnreadByte(); err != nil {
+				return acbor.WrapDecodeError(err, "verifreg.GetDataCapEventsReturn", "Next")
+			}
+			t.Next = new(DataCapEventsCursor)
+			if err := t.Next.UnmarshalCBOR(br); err != nil {
+				return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Next pointer: %w", err), "verifreg.GetDataCapEventsReturn", "Next")
+			}
+		}
+
+	}
+	return nil
+}
+
+var lengthBufAddVerifiedClientsParams = []byte{129}
+
+func (t *AddVerifiedClientsParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufAddVerifiedClientsParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Clients ([]verifreg.AddVerifiedClientParams) (slice)
+	if len(t.Clients) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Clients was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Clients))); err != nil {
+		return err
+	}
+	for _, v := range t.Clients {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *AddVerifiedClientsParams) UnmarshalCBOR(r io.Reader) error {
+	*t = AddVerifiedClientsParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "verifreg.AddVerifiedClientsParams", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "verifreg.AddVerifiedClientsParams", "")
+	}
+
+	if extra != 1 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "verifreg.AddVerifiedClientsParams", "")
+	}
+
+	// t.Clients ([]verifreg.AddVerifiedClientParams) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "verifreg.AddVerifiedClientsParams", "Clients")
+	}
+
+	if extra > cbg.MaxLength {
+		return acbor.WrapDecodeError(fmt.Errorf("t.Clients: array too large (%d)", extra), "verifreg.AddVerifiedClientsParams", "Clients")
+	}
+
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("expected cbor array"), "verifreg.AddVerifiedClientsParams", "Clients")
+	}
+
+	if extra > 0 {
+		t.Clients = make([]verifreg.AddVerifiedClientParams, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v verifreg.AddVerifiedClientParams
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(err, "verifreg.AddVerifiedClientsParams", fmt.Sprintf("Clients[%d]", i))
+		}
+
+		t.Clients[i] = v
+	}
+
+	return nil
+}
+
+var lengthBufAddVerifiedClientsReturn = []byte{129}
+
+func (t *AddVerifiedClientsReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufAddVerifiedClientsReturn); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Results ([]exitcode.ExitCode) (slice)
+	if len(t.Results) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Results was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Results))); err != nil {
+		return err
+	}
+	for _, v := range t.Results {
+		if v >= 0 {
+			if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(v)); err != nil {
+				return err
+			}
+		} else {
+			if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-v-1)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (t *AddVerifiedClientsReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = AddVerifiedClientsReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "verifreg.AddVerifiedClientsReturn", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "verifreg.AddVerifiedClientsReturn", "")
+	}
+
+	if extra != 1 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "verifreg.AddVerifiedClientsReturn", "")
+	}
+
+	// t.Results ([]exitcode.ExitCode) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "verifreg.AddVerifiedClientsReturn", "Results")
+	}
+
+	if extra > cbg.MaxLength {
+		return acbor.WrapDecodeError(fmt.Errorf("t.Results: array too large (%d)", extra), "verifreg.AddVerifiedClientsReturn", "Results")
+	}
+
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("expected cbor array"), "verifreg.AddVerifiedClientsReturn", "Results")
+	}
+
+	if extra > 0 {
+		t.Results = make([]exitcode.ExitCode, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+		{
+			maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+			var extraI int64
+			if err != nil {
+				return acbor.WrapDecodeError(err, "verifreg.AddVerifiedClientsReturn", fmt.Sprintf("Results[%d]", i))
+			}
+			switch maj {
+			case cbg.MajUnsignedInt:
+				extraI = int64(extra)
+				if extraI < 0 {
+					return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "verifreg.AddVerifiedClientsReturn", fmt.Sprintf("Results[%d]", i))
+				}
+			case cbg.MajNegativeInt:
+				extraI = int64(extra)
+				if extraI < 0 {
+					return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "verifreg.AddVerifiedClientsReturn", fmt.Sprintf("Results[%d]", i))
+				}
+				extraI = -1 - extraI
+			default:
+				return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "verifreg.AddVerifiedClientsReturn", fmt.Sprintf("Results[%d]", i))
+			}
+
+			t.Results[i] = exitcode.ExitCode(extraI)
+		}
+	}
+
 	return nil
 }
+
+var lengthBufCheckClientSeparationParams = []byte{130}
+
+func (t *CheckClientSeparationParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufCheckClientSeparationParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Client (address.Address) (struct)
+	if err := t.Client.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Miners ([]address.Address) (slice)
+	if len(t.Miners) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Miners was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Miners))); err != nil {
+		return err
+	}
+	for _, v := range t.Miners {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *CheckClientSeparationParams) UnmarshalCBOR(r io.Reader) error {
+	*t = CheckClientSeparationParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "verifreg.CheckClientSeparationParams", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "verifreg.CheckClientSeparationParams", "")
+	}
+
+	if extra != 2 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "verifreg.CheckClientSeparationParams", "")
+	}
+
+	// t.Client (address.Address) (struct)
+
+	{
+
+		if err := t.Client.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Client: %w", err), "verifreg.CheckClientSeparationParams", "Client")
+		}
+
+	}
+	// t.Miners ([]address.Address) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "verifreg.CheckClientSeparationParams", "Miners")
+	}
+
+	if extra > cbg.MaxLength {
+		return acbor.WrapDecodeError(fmt.Errorf("t.Miners: array too large (%d)", extra), "verifreg.CheckClientSeparationParams", "Miners")
+	}
+
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("expected cbor array"), "verifreg.CheckClientSeparationParams", "Miners")
+	}
+
+	if extra > 0 {
+		t.Miners = make([]address.Address, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v address.Address
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(err, "verifreg.CheckClientSeparationParams", fmt.Sprintf("Miners[%d]", i))
+		}
+
+		t.Miners[i] = v
+	}
+
+	return nil
+}
+
+var lengthBufCheckClientSeparationReturn = []byte{129}
+
+func (t *CheckClientSeparationReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufCheckClientSeparationReturn); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Conflicts ([]address.Address) (slice)
+	if len(t.Conflicts) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Conflicts was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Conflicts))); err != nil {
+		return err
+	}
+	for _, v := range t.Conflicts {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *CheckClientSeparationReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = CheckClientSeparationReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "verifreg.CheckClientSeparationReturn", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "verifreg.CheckClientSeparationReturn", "")
+	}
+
+	if extra != 1 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "verifreg.CheckClientSeparationReturn", "")
+	}
+
+	// t.Conflicts ([]address.Address) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "verifreg.CheckClientSeparationReturn", "Conflicts")
+	}
+
+	if extra > cbg.MaxLength {
+		return acbor.WrapDecodeError(fmt.Errorf("t.Conflicts: array too large (%d)", extra), "verifreg.CheckClientSeparationReturn", "Conflicts")
+	}
+
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("expected cbor array"), "verifreg.CheckClientSeparationReturn", "Conflicts")
+	}
+
+	if extra > 0 {
+		t.Conflicts = make([]address.Address, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v address.Address
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(err, "verifreg.CheckClientSeparationReturn", fmt.Sprintf("Conflicts[%d]", i))
+		}
+
+		t.Conflicts[i] = v
+	}
+
+	return nil
+}
+
+var lengthBufRemoveVerifiedClientDataCapParams = []byte{132}
+
+func (t *RemoveVerifiedClientDataCapParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufRemoveVerifiedClientDataCapParams); err != nil {
+		return err
+	}
+
+	// t.VerifiedClientToRemove (address.Address) (struct)
+	if err := t.VerifiedClientToRemove.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.DataCapAmountToRemove (big.Int) (struct)
+	if err := t.DataCapAmountToRemove.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.VerifierRequest1 (verifreg.RemoveDataCapRequest) (struct)
+	if err := t.VerifierRequest1.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.VerifierRequest2 (verifreg.RemoveDataCapRequest) (struct)
+	if err := t.VerifierRequest2.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *RemoveVerifiedClientDataCapParams) UnmarshalCBOR(r io.Reader) error {
+	*t = RemoveVerifiedClientDataCapParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "verifreg.RemoveVerifiedClientDataCapParams", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "verifreg.RemoveVerifiedClientDataCapParams", "")
+	}
+
+	if extra != 4 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "verifreg.RemoveVerifiedClientDataCapParams", "")
+	}
+
+	// t.VerifiedClientToRemove (address.Address) (struct)
+
+	{
+
+		if err := t.VerifiedClientToRemove.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.VerifiedClientToRemove: %w", err), "verifreg.RemoveVerifiedClientDataCapParams", "VerifiedClientToRemove")
+		}
+
+	}
+	// t.DataCapAmountToRemove (big.Int) (struct)
+
+	{
+
+		if err := t.DataCapAmountToRemove.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.DataCapAmountToRemove: %w", err), "verifreg.RemoveVerifiedClientDataCapParams", "DataCapAmountToRemove")
+		}
+
+	}
+	// t.VerifierRequest1 (verifreg.RemoveDataCapRequest) (struct)
+
+	{
+
+		if err := t.VerifierRequest1.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.VerifierRequest1: %w", err), "verifreg.RemoveVerifiedClientDataCapParams", "VerifierRequest1")
+		}
+
+	}
+	// t.VerifierRequest2 (verifreg.RemoveDataCapRequest) (struct)
+
+	{
+
+		if err := t.VerifierRequest2.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.VerifierRequest2: %w", err), "verifreg.RemoveVerifiedClientDataCapParams", "VerifierRequest2")
+		}
+
+	}
+	return nil
+}
+
+var lengthBufRemoveVerifiedClientDataCapReturn = []byte{130}
+
+func (t *RemoveVerifiedClientDataCapReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufRemoveVerifiedClientDataCapReturn); err != nil {
+		return err
+	}
+
+	// t.VerifiedClient (address.Address) (struct)
+	if err := t.VerifiedClient.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.DataCapRemoved (big.Int) (struct)
+	if err := t.DataCapRemoved.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *RemoveVerifiedClientDataCapReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = RemoveVerifiedClientDataCapReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "verifreg.RemoveVerifiedClientDataCapReturn", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "verifreg.RemoveVerifiedClientDataCapReturn", "")
+	}
+
+	if extra != 2 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "verifreg.RemoveVerifiedClientDataCapReturn", "")
+	}
+
+	// t.VerifiedClient (address.Address) (struct)
+
+	{
+
+		if err := t.VerifiedClient.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.VerifiedClient: %w", err), "verifreg.RemoveVerifiedClientDataCapReturn", "VerifiedClient")
+		}
+
+	}
+	// t.DataCapRemoved (big.Int) (struct)
+
+	{
+
+		if err := t.DataCapRemoved.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.DataCapRemoved: %w", err), "verifreg.RemoveVerifiedClientDataCapReturn", "DataCapRemoved")
+		}
+
+	}
+	return nil
+}
+
+var lengthBufRemoveDataCapRequest = []byte{130}
+
+func (t *RemoveDataCapRequest) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufRemoveDataCapRequest); err != nil {
+		return err
+	}
+
+	// t.Verifier (address.Address) (struct)
+	if err := t.Verifier.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.VerifierSignature (crypto.Signature) (struct)
+	if err := t.VerifierSignature.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *RemoveDataCapRequest) UnmarshalCBOR(r io.Reader) error {
+	*t = RemoveDataCapRequest{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "verifreg.RemoveDataCapRequest", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "verifreg.RemoveDataCapRequest", "")
+	}
+
+	if extra != 2 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "verifreg.RemoveDataCapRequest", "")
+	}
+
+	// t.Verifier (address.Address) (struct)
+
+	{
+
+		if err := t.Verifier.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Verifier: %w", err), "verifreg.RemoveDataCapRequest", "Verifier")
+		}
+
+	}
+	// t.VerifierSignature (crypto.Signature) (struct)
+
+	{
+
+		if err := t.VerifierSignature.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.VerifierSignature: %w", err), "verifreg.RemoveDataCapRequest", "VerifierSignature")
+		}
+
+	}
+	return nil
+}
+
+var lengthBufRemoveDataCapProposal = []byte{131}
+
+func (t *RemoveDataCapProposal) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufRemoveDataCapProposal); err != nil {
+		return err
+	}
+
+	// t.VerifiedClient (address.Address) (struct)
+	if err := t.VerifiedClient.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.DataCapAmount (big.Int) (struct)
+	if err := t.DataCapAmount.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.RemovalProposalID (verifreg.RemoveDataCapProposalID) (struct)
+	if err := t.RemovalProposalID.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *RemoveDataCapProposal) UnmarshalCBOR(r io.Reader) error {
+	*t = RemoveDataCapProposal{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "verifreg.RemoveDataCapProposal", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "verifreg.RemoveDataCapProposal", "")
+	}
+
+	if extra != 3 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "verifreg.RemoveDataCapProposal", "")
+	}
+
+	// t.VerifiedClient (address.Address) (struct)
+
+	{
+
+		if err := t.VerifiedClient.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.VerifiedClient: %w", err), "verifreg.RemoveDataCapProposal", "VerifiedClient")
+		}
+
+	}
+	// t.DataCapAmount (big.Int) (struct)
+
+	{
+
+		if err := t.DataCapAmount.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.DataCapAmount: %w", err), "verifreg.RemoveDataCapProposal", "DataCapAmount")
+		}
+
+	}
+	// t.RemovalProposalID (verifreg.RemoveDataCapProposalID) (struct)
+
+	{
+
+		if err := t.RemovalProposalID.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.RemovalProposalID: %w", err), "verifreg.RemoveDataCapProposal", "RemovalProposalID")
+		}
+
+	}
+	return nil
+}
+
+var lengthBufDataCapEvent = []byte{132}
+
+func (t *DataCapEvent) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufDataCapEvent); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Kind (verifreg.DataCapEventKind) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Kind)); err != nil {
+		return err
+	}
+
+	// t.Source (address.Address) (struct)
+	if err := t.Source.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Target (address.Address) (struct)
+	if err := t.Target.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Amount (big.Int) (struct)
+	if err := t.Amount.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *DataCapEvent) UnmarshalCBOR(r io.Reader) error {
+	*t = DataCapEvent{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "verifreg.DataCapEvent", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "verifreg.DataCapEvent", "")
+	}
+
+	if extra != 4 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "verifreg.DataCapEvent", "")
+	}
+
+	// t.Kind (verifreg.DataCapEventKind) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return acbor.WrapDecodeError(err, "verifreg.DataCapEvent", "Kind")
+		}
+		if maj != cbg.MajUnsignedInt {
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "verifreg.DataCapEvent", "Kind")
+		}
+		t.Kind = DataCapEventKind(extra)
+
+	}
+	// t.Source (address.Address) (struct)
+
+	{
+
+		if err := t.Source.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Source: %w", err), "verifreg.DataCapEvent", "Source")
+		}
+
+	}
+	// t.Target (address.Address) (struct)
+
+	{
+
+		if err := t.Target.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Target: %w", err), "verifreg.DataCapEvent", "Target")
+		}
+
+	}
+	// t.Amount (big.Int) (struct)
+
+	{
+
+		if err := t.Amount.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Amount: %w", err), "verifreg.DataCapEvent", "Amount")
+		}
+
+	}
+	return nil
+}
+
+var lengthBufDataCapEventSet = []byte{129}
+
+func (t *DataCapEventSet) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufDataCapEventSet); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Events ([]verifreg.DataCapEvent) (slice)
+	if len(t.Events) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Events was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Events))); err != nil {
+		return err
+	}
+	for _, v := range t.Events {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *DataCapEventSet) UnmarshalCBOR(r io.Reader) error {
+	*t = DataCapEventSet{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "verifreg.DataCapEventSet", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "verifreg.DataCapEventSet", "")
+	}
+
+	if extra != 1 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "verifreg.DataCapEventSet", "")
+	}
+
+	// t.Events ([]verifreg.DataCapEvent) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "verifreg.DataCapEventSet", "Events")
+	}
+
+	if extra > cbg.MaxLength {
+		return acbor.WrapDecodeError(fmt.Errorf("t.Events: array too large (%d)", extra), "verifreg.DataCapEventSet", "Events")
+	}
+
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("expected cbor array"), "verifreg.DataCapEventSet", "Events")
+	}
+
+	if extra > 0 {
+		t.Events = make([]DataCapEvent, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v DataCapEvent
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(err, "verifreg.DataCapEventSet", fmt.Sprintf("Events[%d]", i))
+		}
+
+		t.Events[i] = v
+	}
+
+	return nil
+}
+
+var lengthBufDataCapEventRecord = []byte{130}
+
+func (t *DataCapEventRecord) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufDataCapEventRecord); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Epoch (abi.ChainEpoch) (int64)
+	if t.Epoch >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Epoch)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.Epoch-1)); err != nil {
+			return err
+		}
+	}
+
+	// t.Event (verifreg.DataCapEvent) (struct)
+	if err := t.Event.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *DataCapEventRecord) UnmarshalCBOR(r io.Reader) error {
+	*t = DataCapEventRecord{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "verifreg.DataCapEventRecord", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "verifreg.DataCapEventRecord", "")
+	}
+
+	if extra != 2 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "verifreg.DataCapEventRecord", "")
+	}
+
+	// t.Epoch (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return acbor.WrapDecodeError(err, "verifreg.DataCapEventRecord", "Epoch")
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "verifreg.DataCapEventRecord", "Epoch")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "verifreg.DataCapEventRecord", "Epoch")
+			}
+			extraI = -1 - extraI
+		default:
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "verifreg.DataCapEventRecord", "Epoch")
+		}
+
+		t.Epoch = abi.ChainEpoch(extraI)
+	}
+	// t.Event (verifreg.DataCapEvent) (struct)
+
+	{
+
+		if err := t.Event.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.Event: %w", err), "verifreg.DataCapEventRecord", "Event")
+		}
+
+	}
+	return nil
+}
+
+var lengthBufDataCapEventsCursor = []byte{130}
+
+func (t *DataCapEventsCursor) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufDataCapEventsCursor); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Epoch (abi.ChainEpoch) (int64)
+	if t.Epoch >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Epoch)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.Epoch-1)); err != nil {
+			return err
+		}
+	}
+
+	// t.Index (uint64) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Index)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (t *DataCapEventsCursor) UnmarshalCBOR(r io.Reader) error {
+	*t = DataCapEventsCursor{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "verifreg.DataCapEventsCursor", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "verifreg.DataCapEventsCursor", "")
+	}
+
+	if extra != 2 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "verifreg.DataCapEventsCursor", "")
+	}
+
+	// t.Epoch (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return acbor.WrapDecodeError(err, "verifreg.DataCapEventsCursor", "Epoch")
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return acbor.WrapDecodeError(fmt.Errorf("int64 positive overflow"), "verifreg.DataCapEventsCursor", "Epoch")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return acbor.WrapDecodeError(fmt.Errorf("int64 negative oveflow"), "verifreg.DataCapEventsCursor", "Epoch")
+			}
+			extraI = -1 - extraI
+		default:
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for int64 field: %d", maj), "verifreg.DataCapEventsCursor", "Epoch")
+		}
+
+		t.Epoch = abi.ChainEpoch(extraI)
+	}
+	// t.Index (uint64) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return acbor.WrapDecodeError(err, "verifreg.DataCapEventsCursor", "Index")
+		}
+		if maj != cbg.MajUnsignedInt {
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "verifreg.DataCapEventsCursor", "Index")
+		}
+		t.Index = uint64(extra)
+
+	}
+	return nil
+}
+
+var lengthBufRemoveDataCapProposalID = []byte{129}
+
+func (t *RemoveDataCapProposalID) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufRemoveDataCapProposalID); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.ProposalID (uint64) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.ProposalID)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (t *RemoveDataCapProposalID) UnmarshalCBOR(r io.Reader) error {
+	*t = RemoveDataCapProposalID{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "verifreg.RemoveDataCapProposalID", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "verifreg.RemoveDataCapProposalID", "")
+	}
+
+	if extra != 1 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "verifreg.RemoveDataCapProposalID", "")
+	}
+
+	// t.ProposalID (uint64) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return acbor.WrapDecodeError(err, "verifreg.RemoveDataCapProposalID", "ProposalID")
+		}
+		if maj != cbg.MajUnsignedInt {
+			return acbor.WrapDecodeError(fmt.Errorf("wrong type for uint64 field"), "verifreg.RemoveDataCapProposalID", "ProposalID")
+		}
+		t.ProposalID = uint64(extra)
+
+	}
+	return nil
+}
+
+func (t *State) MaxEncodedLength() int64 {
+	return 2135
+}
+
+func (t *GetDataCapEventsParams) MaxEncodedLength() int64 {
+	return 38
+}
+
+func (t *GetDataCapEventsReturn) MaxEncodedLength() int64 {
+	return 2310167
+}
+
+func (t *AddVerifiedClientsParams) MaxEncodedLength() int64 {
+	return 1613828
+}
+
+func (t *AddVerifiedClientsReturn) MaxEncodedLength() int64 {
+	return 73732
+}
+
+func (t *CheckClientSeparationParams) MaxEncodedLength() int64 {
+	return 540742
+}
+
+func (t *CheckClientSeparationReturn) MaxEncodedLength() int64 {
+	return 540676
+}
+
+func (t *RemoveVerifiedClientDataCapParams) MaxEncodedLength() int64 {
+	return 737
+}
+
+func (t *RemoveVerifiedClientDataCapReturn) MaxEncodedLength() int64 {
+	return 197
+}
+
+func (t *RemoveDataCapRequest) MaxEncodedLength() int64 {
+	return 270
+}
+
+func (t *RemoveDataCapProposal) MaxEncodedLength() int64 {
+	return 207
+}
+
+func (t *DataCapEvent) MaxEncodedLength() int64 {
+	return 272
+}
+
+func (t *DataCapEventSet) MaxEncodedLength() int64 {
+	return 2228228
+}
+
+func (t *DataCapEventRecord) MaxEncodedLength() int64 {
+	return 282
+}
+
+func (t *DataCapEventsCursor) MaxEncodedLength() int64 {
+	return 19
+}
+
+func (t *RemoveDataCapProposalID) MaxEncodedLength() int64 {
+	return 10
+}
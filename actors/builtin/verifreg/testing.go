@@ -67,6 +67,25 @@ func CheckStateInvariants(st *State, store adt.Store) (*StateSummary, *builtin.M
 	}
 	// No need to iterate all clients; any overlap must have been one of all verifiers.
 
+	// Check DataCap events
+	events, err := adt.AsArray(store, st.DataCapEvents)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var eventSet DataCapEventSet
+	if err = events.ForEach(&eventSet, func(epoch int64) error {
+		acc.Require(len(eventSet.Events) > 0, "empty datacap event set at epoch %d", epoch)
+		for _, event := range eventSet.Events {
+			acc.Require(event.Kind <= DataCapRemoved, "datacap event at epoch %d has unknown kind %d", epoch, event.Kind)
+			acc.Require(event.Target.Protocol() == addr.ID, "datacap event at epoch %d target %v should have ID protocol", epoch, event.Target)
+			acc.Require(event.Amount.GreaterThanEqual(big.Zero()), "datacap event at epoch %d amount %v is negative", epoch, event.Amount)
+		}
+		return nil
+	}); err != nil {
+		return nil, nil, err
+	}
+
 	return &StateSummary{
 		Verifiers: allVerifiers,
 		Clients:   allClients,
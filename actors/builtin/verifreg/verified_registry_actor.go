@@ -1,13 +1,17 @@
 package verifreg
 
 import (
+	"bytes"
+
 	addr "github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/cbor"
+	"github.com/filecoin-project/go-state-types/crypto"
 	"github.com/ipfs/go-cid"
 
 	"github.com/filecoin-project/go-state-types/big"
 	"github.com/filecoin-project/go-state-types/exitcode"
+	rtt "github.com/filecoin-project/go-state-types/rt"
 	verifreg0 "github.com/filecoin-project/specs-actors/actors/builtin/verifreg"
 
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
@@ -26,6 +30,10 @@ func (a Actor) Exports() []interface{} {
 		4:                         a.AddVerifiedClient,
 		5:                         a.UseBytes,
 		6:                         a.RestoreBytes,
+		7:                         a.GetDataCapEvents,
+		8:                         a.AddVerifiedClients,
+		9:                         a.CheckClientSeparation,
+		10:                        a.RemoveVerifiedClientDataCap,
 	}
 }
 
@@ -57,7 +65,10 @@ func (a Actor) Constructor(rt runtime.Runtime, rootKey *addr.Address) *abi.Empty
 	emptyMap, err := adt.MakeEmptyMap(adt.AsStore(rt)).Root()
 	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to create state")
 
-	st := ConstructState(emptyMap, idAddr)
+	emptyArray, err := adt.MakeEmptyArray(adt.AsStore(rt)).Root()
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to create state")
+
+	st := ConstructState(emptyMap, emptyArray, idAddr)
 	rt.StateCreate(st)
 	return nil
 }
@@ -102,6 +113,14 @@ func (a Actor) AddVerifier(rt runtime.Runtime, params *AddVerifierParams) *abi.E
 
 		st.Verifiers, err = verifiers.Root()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush verifiers")
+
+		err = st.RecordDataCapEvents(adt.AsStore(rt), rt.CurrEpoch(), DataCapEvent{
+			Kind:   DataCapGranted,
+			Source: st.RootKey,
+			Target: verifier,
+			Amount: params.Allowance,
+		})
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to record datacap grant")
 	})
 
 	return nil
@@ -119,11 +138,25 @@ func (a Actor) RemoveVerifier(rt runtime.Runtime, verifierAddr *addr.Address) *a
 		verifiers, err := adt.AsMap(adt.AsStore(rt), st.Verifiers)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load verifiers")
 
+		var verifierCap DataCap
+		found, err := verifiers.Get(abi.AddrKey(verifier), &verifierCap)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get verifier %v", verifier)
+
 		err = verifiers.Delete(abi.AddrKey(verifier))
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to remove verifier")
 
 		st.Verifiers, err = verifiers.Root()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush verifiers")
+
+		if found {
+			err = st.RecordDataCapEvents(adt.AsStore(rt), rt.CurrEpoch(), DataCapEvent{
+				Kind:   DataCapRemoved,
+				Source: st.RootKey,
+				Target: verifier,
+				Amount: verifierCap,
+			})
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to record datacap removal")
+		}
 	})
 
 	return nil
@@ -161,41 +194,83 @@ func (a Actor) AddVerifiedClient(rt runtime.Runtime, params *AddVerifiedClientPa
 
 		// Validate caller is one of the verifiers.
 		verifier := rt.Caller()
-		var verifierCap DataCap
-		found, err := verifiers.Get(abi.AddrKey(verifier), &verifierCap)
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get verifier %v", verifier)
-		if !found {
-			rt.Abortf(exitcode.ErrNotFound, "no such verifier %v", verifier)
-		}
+		verifierCap := loadVerifierCap(rt, verifiers, verifier)
 
-		// Validate client to be added isn't a verifier
-		found, err = verifiers.Get(abi.AddrKey(client), nil)
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get verifier")
-		if found {
-			rt.Abortf(exitcode.ErrIllegalArgument, "verifier %v cannot be added as a verified client", client)
-		}
+		err = grantDataCap(rt, &st, verifiers, verifiedClients, verifier, &verifierCap, client, params.Allowance)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to add verified client %v", client)
 
-		// Compute new verifier cap and update.
-		if verifierCap.LessThan(params.Allowance) {
-			rt.Abortf(exitcode.ErrIllegalArgument, "add more DataCap (%d) for VerifiedClient than allocated %d", params.Allowance, verifierCap)
-		}
-		newVerifierCap := big.Sub(verifierCap, params.Allowance)
+		st.Verifiers, err = verifiers.Root()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush verifiers")
 
-		err = verifiers.Put(abi.AddrKey(verifier), &newVerifierCap)
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to update new verifier cap (%d) for %v", newVerifierCap, verifier)
+		st.VerifiedClients, err = verifiedClients.Root()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush verified clients")
+	})
 
-		// This is a one-time, upfront allocation.
-		// This allowance cannot be changed by calls to AddVerifiedClient as long as the client has not been removed.
-		// If parties need more allowance, they need to create a new verified client or use up the the current allowance
-		// and then create a new verified client.
-		found, err = verifiedClients.Get(abi.AddrKey(client), &verifierCap)
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get verified client %v", client)
-		if found {
-			rt.Abortf(exitcode.ErrIllegalArgument, "verified client already exists: %v", client)
+	return nil
+}
+
+type AddVerifiedClientsParams struct {
+	Clients []AddVerifiedClientParams
+}
+
+type AddVerifiedClientsReturn struct {
+	// Exit code for each entry of the parameters, in order. Ok indicates the client was added.
+	Results []exitcode.ExitCode
+}
+
+// Adds many verified clients from the calling verifier in a single message.
+// Each entry is validated independently, as for AddVerifiedClient, and an invalid entry does not
+// prevent the others from being added. The verifier's DataCap is spent in parameter order.
+func (a Actor) AddVerifiedClients(rt runtime.Runtime, params *AddVerifiedClientsParams) *AddVerifiedClientsReturn {
+	// The caller will be verified by checking the verifiers table below.
+	rt.ValidateImmediateCallerAcceptAny()
+	if len(params.Clients) > MaxVerifiedClientsBatchSize {
+		rt.Abortf(exitcode.ErrIllegalArgument, "too many verified clients %d, max %d", len(params.Clients), MaxVerifiedClientsBatchSize)
+	}
+
+	// Validate caller is one of the verifiers before resolving client addresses, which may create actors.
+	var st State
+	rt.StateReadonly(&st)
+	verifiers, err := adt.AsMap(adt.AsStore(rt), st.Verifiers)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load verifiers")
+	loadVerifierCap(rt, verifiers, rt.Caller())
+
+	results := make([]exitcode.ExitCode, len(params.Clients))
+	clients := make([]addr.Address, len(params.Clients))
+	for i, entry := range params.Clients {
+		if entry.Allowance.LessThan(MinVerifiedDealSize) {
+			results[i] = exitcode.ErrIllegalArgument
+			continue
+		}
+		client, err := builtin.ResolveToIDAddr(rt, entry.Address)
+		if err != nil {
+			rt.Log(rtt.INFO, "failed to resolve verified client address %v: %s", entry.Address, err)
+			results[i] = exitcode.Unwrap(err, exitcode.ErrIllegalArgument)
+			continue
 		}
+		clients[i] = client
+	}
 
-		err = verifiedClients.Put(abi.AddrKey(client), &params.Allowance)
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to add verified client %v with cap %d", client, params.Allowance)
+	rt.StateTransaction(&st, func() {
+		verifiers, err := adt.AsMap(adt.AsStore(rt), st.Verifiers)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load verifiers")
+
+		verifiedClients, err := adt.AsMap(adt.AsStore(rt), st.VerifiedClients)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load verified clients")
+
+		verifier := rt.Caller()
+		verifierCap := loadVerifierCap(rt, verifiers, verifier)
+
+		for i, entry := range params.Clients {
+			if results[i] != exitcode.Ok {
+				continue
+			}
+			err = grantDataCap(rt, &st, verifiers, verifiedClients, verifier, &verifierCap, clients[i], entry.Allowance)
+			if err != nil {
+				rt.Log(rtt.INFO, "failed to add verified client %v: %s", entry.Address, err)
+				results[i] = exitcode.Unwrap(err, exitcode.ErrIllegalState)
+			}
+		}
 
 		st.Verifiers, err = verifiers.Root()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush verifiers")
@@ -204,7 +279,7 @@ func (a Actor) AddVerifiedClient(rt runtime.Runtime, params *AddVerifiedClientPa
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush verified clients")
 	})
 
-	return nil
+	return &AddVerifiedClientsReturn{Results: results}
 }
 
 //type UseBytesParams struct {
@@ -243,6 +318,13 @@ func (a Actor) UseBytes(rt runtime.Runtime, params *UseBytesParams) *abi.EmptyVa
 			rt.Abortf(exitcode.ErrIllegalArgument, "DealSize %d exceeds allowable cap: %d for VerifiedClient %v", params.DealSize, vcCap, client)
 		}
 
+		events := []DataCapEvent{{
+			Kind:   DataCapUsed,
+			Source: rt.Caller(),
+			Target: client,
+			Amount: params.DealSize,
+		}}
+
 		newVcCap := big.Sub(vcCap, params.DealSize)
 		if newVcCap.LessThan(MinVerifiedDealSize) {
 			// Delete entry if remaining DataCap is less than MinVerifiedDealSize.
@@ -252,6 +334,15 @@ func (a Actor) UseBytes(rt runtime.Runtime, params *UseBytesParams) *abi.EmptyVa
 			// See: https://github.com/filecoin-project/specs-actors/issues/727
 			err = verifiedClients.Delete(abi.AddrKey(client))
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete verified client %v", client)
+
+			if !newVcCap.IsZero() {
+				events = append(events, DataCapEvent{
+					Kind:   DataCapRemoved,
+					Source: rt.Caller(),
+					Target: client,
+					Amount: newVcCap,
+				})
+			}
 		} else {
 			err = verifiedClients.Put(abi.AddrKey(client), &newVcCap)
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to update verified client %v with %v", client, newVcCap)
@@ -259,6 +350,9 @@ func (a Actor) UseBytes(rt runtime.Runtime, params *UseBytesParams) *abi.EmptyVa
 
 		st.VerifiedClients, err = verifiedClients.Root()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush verified clients")
+
+		err = st.RecordDataCapEvents(adt.AsStore(rt), rt.CurrEpoch(), events...)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to record datacap use")
 	})
 
 	return nil
@@ -315,7 +409,313 @@ func (a Actor) RestoreBytes(rt runtime.Runtime, params *RestoreBytesParams) *abi
 
 		st.VerifiedClients, err = verifiedClients.Root()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load verifiers")
+
+		err = st.RecordDataCapEvents(adt.AsStore(rt), rt.CurrEpoch(), DataCapEvent{
+			Kind:   DataCapRestored,
+			Source: rt.Caller(),
+			Target: client,
+			Amount: params.DealSize,
+		})
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to record datacap restoration")
 	})
 
 	return nil
 }
+
+type GetDataCapEventsParams struct {
+	// Inclusive range of epochs for which to return events.
+	FromEpoch abi.ChainEpoch
+	ToEpoch   abi.ChainEpoch
+	// The first event to return, from the Next cursor of a previous call with the same range. Absent to start
+	// at the beginning of the range.
+	Cursor *DataCapEventsCursor `cborgen:"optional"`
+}
+
+type GetDataCapEventsReturn struct {
+	Events []DataCapEventRecord
+	// The first event not returned, if the events were truncated to fit the return size limits, else absent.
+	Next *DataCapEventsCursor `cborgen:"optional"`
+}
+
+// Returns the DataCap events recorded in a range of epochs, in the order they happened.
+// Only events within the last DataCapEventRetention epochs are retained.
+// The events returned are limited in number and encoded size. If they are truncated, the return includes a cursor
+// with which to call again for the remainder.
+func (a Actor) GetDataCapEvents(rt runtime.Runtime, params *GetDataCapEventsParams) *GetDataCapEventsReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	if params.FromEpoch > params.ToEpoch {
+		rt.Abortf(exitcode.ErrIllegalArgument, "invalid epoch range from %d to %d", params.FromEpoch, params.ToEpoch)
+	}
+	if params.Cursor != nil && (params.Cursor.Epoch < params.FromEpoch || params.Cursor.Epoch > params.ToEpoch) {
+		rt.Abortf(exitcode.ErrIllegalArgument, "cursor epoch %d outside range from %d to %d",
+			params.Cursor.Epoch, params.FromEpoch, params.ToEpoch)
+	}
+
+	var st State
+	rt.StateReadonly(&st)
+
+	events, next, err := st.LoadDataCapEvents(adt.AsStore(rt), params.FromEpoch, params.ToEpoch, params.Cursor)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load datacap events")
+
+	return &GetDataCapEventsReturn{Events: events, Next: next}
+}
+
+type CheckClientSeparationParams struct {
+	Client addr.Address   // Candidate verified client.
+	Miners []addr.Address // Storage miners against which to check the client.
+}
+
+type CheckClientSeparationReturn struct {
+	// Miners, as ID addresses, for which the client is the owner, worker or a control address.
+	Conflicts []addr.Address
+}
+
+// Checks a candidate verified client against a set of storage miners, returning those miners which
+// the client controls. A client which controls a miner could grant its DataCap to itself as a provider,
+// so notaries may use this method to screen candidate clients before allocating DataCap to them.
+// The same check is enforced by the storage market when a verified deal is published.
+func (a Actor) CheckClientSeparation(rt runtime.Runtime, params *CheckClientSeparationParams) *CheckClientSeparationReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+	if len(params.Miners) > MaxCheckClientSeparationMiners {
+		rt.Abortf(exitcode.ErrIllegalArgument, "too many miners %d, max %d", len(params.Miners), MaxCheckClientSeparationMiners)
+	}
+
+	// An address without an ID cannot be a control address of any miner, since those are all ID addresses.
+	client, ok := rt.ResolveAddress(params.Client)
+	if !ok {
+		return &CheckClientSeparationReturn{Conflicts: []addr.Address{}}
+	}
+
+	conflicts := []addr.Address{}
+	for _, minerAddr := range params.Miners {
+		miner, ok := rt.ResolveAddress(minerAddr)
+		if !ok {
+			rt.Abortf(exitcode.ErrNotFound, "failed to resolve miner address %v", minerAddr)
+		}
+		code, ok := rt.GetActorCodeCID(miner)
+		if !ok || !code.Equals(builtin.StorageMinerActorCodeID) {
+			rt.Abortf(exitcode.ErrIllegalArgument, "%v is not a storage miner actor", minerAddr)
+		}
+
+		owner, worker, controlAddrs := builtin.RequestMinerControlAddrs(rt, miner)
+		if err := CheckProviderClientSeparation(client, owner, worker, controlAddrs); err != nil {
+			conflicts = append(conflicts, miner)
+		}
+	}
+	return &CheckClientSeparationReturn{Conflicts: conflicts}
+}
+
+// A verifier's signed approval of a DataCap removal.
+type RemoveDataCapRequest struct {
+	Verifier          addr.Address
+	VerifierSignature crypto.Signature // Signature over the RemoveDataCapProposal, see SignatureDomainSeparation_RemoveDataCap.
+}
+
+type RemoveVerifiedClientDataCapParams struct {
+	VerifiedClientToRemove addr.Address
+	DataCapAmountToRemove  DataCap
+	VerifierRequest1       RemoveDataCapRequest
+	VerifierRequest2       RemoveDataCapRequest
+}
+
+type RemoveVerifiedClientDataCapReturn struct {
+	VerifiedClient addr.Address
+	DataCapRemoved DataCap
+}
+
+// The proposal signed by each verifier approving a DataCap removal.
+type RemoveDataCapProposal struct {
+	VerifiedClient    addr.Address // ID address of the client.
+	DataCapAmount     DataCap
+	RemovalProposalID RemoveDataCapProposalID // The verifier's next proposal ID for the client, see State.GetRemoveDataCapProposalID.
+}
+
+// Prefixed to the serialized RemoveDataCapProposal to form the bytes signed by a verifier, so that the signature
+// cannot be valid for a message or any other signed payload.
+const SignatureDomainSeparation_RemoveDataCap = "fil_removedatacap:"
+
+// Returns the bytes a verifier signs to approve a DataCap removal proposal.
+func (p *RemoveDataCapProposal) SigningBytes() ([]byte, error) {
+	buf := bytes.Buffer{}
+	buf.WriteString(SignatureDomainSeparation_RemoveDataCap)
+	if err := p.MarshalCBOR(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Removes DataCap from a verified client, e.g. one found to be misbehaving or holding cap it will not use.
+// The removal is made by the root key, and must be approved by signatures from two distinct verifiers over
+// the proposed removal. Each signature covers the verifier's next proposal ID for the client, which is then
+// incremented, so an approval cannot be replayed.
+// At most the client's remaining DataCap is removed. If the remainder would fall below MinVerifiedDealSize,
+// the client is removed altogether, along with the remainder.
+func (a Actor) RemoveVerifiedClientDataCap(rt runtime.Runtime, params *RemoveVerifiedClientDataCapParams) *RemoveVerifiedClientDataCapReturn {
+	var st State
+	rt.StateReadonly(&st)
+	rt.ValidateImmediateCallerIs(st.RootKey)
+
+	if params.DataCapAmountToRemove.LessThanEqual(big.Zero()) {
+		rt.Abortf(exitcode.ErrIllegalArgument, "DataCap amount to remove %d must be positive", params.DataCapAmountToRemove)
+	}
+
+	client, err := builtin.ResolveToIDAddr(rt, params.VerifiedClientToRemove)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to resolve verified client address %v", params.VerifiedClientToRemove)
+
+	verifier1, err := builtin.ResolveToIDAddr(rt, params.VerifierRequest1.Verifier)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to resolve verifier address %v", params.VerifierRequest1.Verifier)
+
+	verifier2, err := builtin.ResolveToIDAddr(rt, params.VerifierRequest2.Verifier)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to resolve verifier address %v", params.VerifierRequest2.Verifier)
+
+	if verifier1 == verifier2 {
+		rt.Abortf(exitcode.ErrIllegalArgument, "need two different verifiers to send remove datacap request")
+	}
+
+	var removed DataCap
+	rt.StateTransaction(&st, func() {
+		verifiers, err := adt.AsMap(adt.AsStore(rt), st.Verifiers)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load verifiers")
+
+		verifiedClients, err := adt.AsMap(adt.AsStore(rt), st.VerifiedClients)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load verified clients")
+
+		loadVerifierCap(rt, verifiers, verifier1)
+		loadVerifierCap(rt, verifiers, verifier2)
+
+		var vcCap DataCap
+		found, err := verifiedClients.Get(abi.AddrKey(client), &vcCap)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get verified client %v", client)
+		if !found {
+			rt.Abortf(exitcode.ErrNotFound, "%v is not a verified client", client)
+		}
+
+		useRemoveDataCapRequest(rt, &st, verifier1, &params.VerifierRequest1, client, params.DataCapAmountToRemove)
+		useRemoveDataCapRequest(rt, &st, verifier2, &params.VerifierRequest2, client, params.DataCapAmountToRemove)
+
+		removed = big.Min(params.DataCapAmountToRemove, vcCap)
+		newVcCap := big.Sub(vcCap, removed)
+		if newVcCap.LessThan(MinVerifiedDealSize) {
+			removed = vcCap
+			err = verifiedClients.Delete(abi.AddrKey(client))
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete verified client %v", client)
+		} else {
+			err = verifiedClients.Put(abi.AddrKey(client), &newVcCap)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to update verified client %v with %v", client, newVcCap)
+		}
+
+		st.VerifiedClients, err = verifiedClients.Root()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush verified clients")
+
+		err = st.RecordDataCapEvents(adt.AsStore(rt), rt.CurrEpoch(), DataCapEvent{
+			Kind:   DataCapRemoved,
+			Source: st.RootKey,
+			Target: client,
+			Amount: removed,
+		})
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to record datacap removal")
+	})
+
+	return &RemoveVerifiedClientDataCapReturn{
+		VerifiedClient: client,
+		DataCapRemoved: removed,
+	}
+}
+
+// Checks that a verified client is not one of the owner, worker or control addresses of a storage provider.
+// All addresses are expected to be ID addresses.
+// Returns an error with exit code ErrForbidden if the client controls the provider.
+func CheckProviderClientSeparation(client, owner, worker addr.Address, controlAddrs []addr.Address) error {
+	if client == owner || client == worker {
+		return exitcode.ErrForbidden.Wrapf("verified client %v is the owner or worker of the provider", client)
+	}
+	for _, ca := range controlAddrs {
+		if client == ca {
+			return exitcode.ErrForbidden.Wrapf("verified client %v is a control address of the provider", client)
+		}
+	}
+	return nil
+}
+
+// Loads the remaining DataCap of a verifier, aborting if the address is not a verifier.
+func loadVerifierCap(rt runtime.Runtime, verifiers *adt.Map, verifier addr.Address) DataCap {
+	var verifierCap DataCap
+	found, err := verifiers.Get(abi.AddrKey(verifier), &verifierCap)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get verifier %v", verifier)
+	if !found {
+		rt.Abortf(exitcode.ErrNotFound, "no such verifier %v", verifier)
+	}
+	return verifierCap
+}
+
+// Verifies a verifier's signature approving the removal of DataCap from a client, and increments the verifier's
+// proposal ID for the client so that the signature cannot be used again.
+func useRemoveDataCapRequest(rt runtime.Runtime, st *State, verifier addr.Address, request *RemoveDataCapRequest,
+	client addr.Address, amount DataCap) {
+	id, err := st.UseRemoveDataCapProposalID(adt.AsStore(rt), verifier, client)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to use datacap removal proposal id")
+
+	proposal := RemoveDataCapProposal{
+		VerifiedClient:    client,
+		DataCapAmount:     amount,
+		RemovalProposalID: id,
+	}
+	b, err := proposal.SigningBytes()
+	builtin.RequireNoErr(rt, err, exitcode.ErrSerialization, "failed to serialize datacap removal proposal")
+
+	err = rt.VerifySignature(request.VerifierSignature, verifier, b)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "invalid signature for datacap removal request from verifier %v", verifier)
+}
+
+// Grants an allowance from a verifier to a new verified client, deducting it from verifierCap and
+// writing the new cap to the verifiers map.
+// Returns an error carrying an exit code, without modifying state, if the grant is invalid.
+func grantDataCap(rt runtime.Runtime, st *State, verifiers, verifiedClients *adt.Map, verifier addr.Address,
+	verifierCap *DataCap, client addr.Address, allowance DataCap) error {
+	if allowance.LessThan(MinVerifiedDealSize) {
+		return exitcode.ErrIllegalArgument.Wrapf("allowance %d below MinVerifiedDealSize for add verified client %v", allowance, client)
+	}
+	if st.RootKey == client {
+		return exitcode.ErrIllegalArgument.Wrapf("Rootkey cannot be added as a verified client")
+	}
+
+	// Validate client to be added isn't a verifier
+	found, err := verifiers.Get(abi.AddrKey(client), nil)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get verifier")
+	if found {
+		return exitcode.ErrIllegalArgument.Wrapf("verifier %v cannot be added as a verified client", client)
+	}
+
+	// Compute new verifier cap.
+	if verifierCap.LessThan(allowance) {
+		return exitcode.ErrIllegalArgument.Wrapf("add more DataCap (%d) for VerifiedClient than allocated %d", allowance, *verifierCap)
+	}
+
+	// This is a one-time, upfront allocation.
+	// This allowance cannot be changed by calls to AddVerifiedClient as long as the client has not been removed.
+	// If parties need more allowance, they need to create a new verified client or use up the the current allowance
+	// and then create a new verified client.
+	found, err = verifiedClients.Get(abi.AddrKey(client), nil)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get verified client %v", client)
+	if found {
+		return exitcode.ErrIllegalArgument.Wrapf("verified client already exists: %v", client)
+	}
+
+	*verifierCap = big.Sub(*verifierCap, allowance)
+	err = verifiers.Put(abi.AddrKey(verifier), verifierCap)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to update new verifier cap (%d) for %v", *verifierCap, verifier)
+
+	err = verifiedClients.Put(abi.AddrKey(client), &allowance)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to add verified client %v with cap %d", client, allowance)
+
+	err = st.RecordDataCapEvents(adt.AsStore(rt), rt.CurrEpoch(), DataCapEvent{
+		Kind:   DataCapGranted,
+		Source: verifier,
+		Target: client,
+		Amount: allowance,
+	})
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to record datacap grant")
+	builtin.EmitDataCapGranted(rt, verifier, client, allowance)
+	return nil
+}
@@ -4,6 +4,12 @@ import (
 	addr "github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/abi"
 	cid "github.com/ipfs/go-cid"
+	cbg "github.com/whyrusleeping/cbor-gen"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v2/actors/util/adt"
+	acbor "github.com/filecoin-project/specs-actors/v2/actors/util/cbor"
 )
 
 // DataCap is an integer number of bytes.
@@ -21,15 +27,219 @@ type State struct {
 
 	// VerifiedClients can add VerifiedClientData, up to DataCap.
 	VerifiedClients cid.Cid // HAMT[addr.Address]DataCap
+
+	// Log of DataCap grants, uses and removals, bucketed by the epoch at which they happened.
+	// Buckets older than DataCapEventRetention are pruned as new events are recorded.
+	DataCapEvents cid.Cid // AMT[ChainEpoch]DataCapEventSet
+
+	// The ID of the next DataCap removal proposal each verifier may sign for each client, which increments with
+	// every removal so that a signed proposal cannot be replayed.
+	RemoveDataCapProposalIDs cid.Cid // HAMT[AddrPairKey]RemoveDataCapProposalID
 }
 
 var MinVerifiedDealSize = abi.NewStoragePower(1 << 20)
 
+// Maximum number of clients which may be added together by one AddVerifiedClients call.
+const MaxVerifiedClientsBatchSize = 256
+
+// Maximum number of miners against which one CheckClientSeparation call may check a client.
+const MaxCheckClientSeparationMiners = 256
+
+// Number of epochs for which DataCap events are retained in state.
+var DataCapEventRetention = abi.ChainEpoch(30 * builtin.EpochsInDay)
+
+// Maximum number of DataCap events returned by one GetDataCapEvents call, beyond which decoders reject the array.
+var DataCapEventsPageLength = uint64(cbg.MaxLength)
+
+// Maximum encoded size in bytes of the DataCap events returned by one GetDataCapEvents call.
+var DataCapEventsPageSize = int64(1 << 20)
+
 // rootKeyAddress comes from genesis.
-func ConstructState(emptyMapCid cid.Cid, rootKeyAddress addr.Address) *State {
+func ConstructState(emptyMapCid, emptyArrayCid cid.Cid, rootKeyAddress addr.Address) *State {
 	return &State{
 		RootKey:         rootKeyAddress,
 		Verifiers:       emptyMapCid,
 		VerifiedClients: emptyMapCid,
+		DataCapEvents:   emptyArrayCid,
+
+		RemoveDataCapProposalIDs: emptyMapCid,
+	}
+}
+
+// The sequence number of a DataCap removal proposal signed by a verifier for a client.
+type RemoveDataCapProposalID struct {
+	ProposalID uint64
+}
+
+// A key for a map indexed by an ordered pair of ID addresses, such as a verifier and a client.
+type AddrPairKey struct {
+	First  addr.Address
+	Second addr.Address
+}
+
+var _ abi.Keyer = AddrPairKey{}
+
+// The address bytes are self-delimiting for ID addresses, so their concatenation identifies the pair.
+func (k AddrPairKey) Key() string {
+	return string(k.First.Bytes()) + string(k.Second.Bytes())
+}
+
+// Returns the ID of the next DataCap removal proposal that a verifier may sign for a client.
+func (st *State) GetRemoveDataCapProposalID(store adt.Store, verifier, client addr.Address) (RemoveDataCapProposalID, error) {
+	ids, err := adt.AsMap(store, st.RemoveDataCapProposalIDs)
+	if err != nil {
+		return RemoveDataCapProposalID{}, xerrors.Errorf("failed to load datacap removal proposal ids: %w", err)
+	}
+	var id RemoveDataCapProposalID
+	if _, err := ids.Get(AddrPairKey{verifier, client}, &id); err != nil {
+		return RemoveDataCapProposalID{}, xerrors.Errorf("failed to get datacap removal proposal id for verifier %v client %v: %w", verifier, client, err)
+	}
+	return id, nil
+}
+
+// Increments the ID of the next DataCap removal proposal that a verifier may sign for a client, returning the
+// ID it had, which the verifier was expected to sign.
+func (st *State) UseRemoveDataCapProposalID(store adt.Store, verifier, client addr.Address) (RemoveDataCapProposalID, error) {
+	ids, err := adt.AsMap(store, st.RemoveDataCapProposalIDs)
+	if err != nil {
+		return RemoveDataCapProposalID{}, xerrors.Errorf("failed to load datacap removal proposal ids: %w", err)
+	}
+	key := AddrPairKey{verifier, client}
+	var id RemoveDataCapProposalID
+	if _, err := ids.Get(key, &id); err != nil {
+		return RemoveDataCapProposalID{}, xerrors.Errorf("failed to get datacap removal proposal id for verifier %v client %v: %w", verifier, client, err)
+	}
+	next := RemoveDataCapProposalID{ProposalID: id.ProposalID + 1}
+	if err := ids.Put(key, &next); err != nil {
+		return RemoveDataCapProposalID{}, xerrors.Errorf("failed to put datacap removal proposal id for verifier %v client %v: %w", verifier, client, err)
+	}
+	if st.RemoveDataCapProposalIDs, err = ids.Root(); err != nil {
+		return RemoveDataCapProposalID{}, xerrors.Errorf("failed to flush datacap removal proposal ids: %w", err)
+	}
+	return id, nil
+}
+
+type DataCapEventKind uint64
+
+const (
+	// DataCap allowance was granted to a verifier (by the root key) or to a client (by a verifier).
+	DataCapGranted DataCapEventKind = iota
+	// Client DataCap was consumed by a verified deal.
+	DataCapUsed
+	// Client DataCap was restored after a verified deal failed to activate.
+	DataCapRestored
+	// DataCap was removed from the registry, e.g. by removal of a verifier or a client's remainder
+	// falling below the minimum verified deal size.
+	DataCapRemoved
+)
+
+// A single DataCap flow.
+type DataCapEvent struct {
+	Kind DataCapEventKind
+	// The party initiating the flow, e.g. the root key, a verifier, or the storage market actor.
+	Source addr.Address
+	// The verifier or client whose DataCap is affected.
+	Target addr.Address
+	Amount DataCap
+}
+
+// The DataCap events recorded at a single epoch, in order of occurrence.
+type DataCapEventSet struct {
+	Events []DataCapEvent
+}
+
+// A DataCap event along with the epoch at which it was recorded.
+type DataCapEventRecord struct {
+	Epoch abi.ChainEpoch
+	Event DataCapEvent
+}
+
+// Appends events to the log at an epoch and prunes any buckets that have fallen out of the retention window.
+func (st *State) RecordDataCapEvents(store adt.Store, epoch abi.ChainEpoch, events ...DataCapEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	log, err := adt.AsArray(store, st.DataCapEvents)
+	if err != nil {
+		return xerrors.Errorf("failed to load datacap events: %w", err)
+	}
+
+	var set DataCapEventSet
+	if _, err := log.Get(uint64(epoch), &set); err != nil {
+		return xerrors.Errorf("failed to load datacap events at epoch %d: %w", epoch, err)
+	}
+	set.Events = append(set.Events, events...)
+	if err := log.Set(uint64(epoch), &set); err != nil {
+		return xerrors.Errorf("failed to store datacap events at epoch %d: %w", epoch, err)
+	}
+
+	// Buckets are keyed by epoch so iteration is in ascending epoch order and can stop at the first one retained.
+	var expired []uint64
+	stopErr := xerrors.New("stop")
+	if err := log.ForEach(nil, func(i int64) error {
+		if abi.ChainEpoch(i)+DataCapEventRetention > epoch {
+			return stopErr
+		}
+		expired = append(expired, uint64(i))
+		return nil
+	}); err != nil && err != stopErr {
+		return xerrors.Errorf("failed to iterate datacap events: %w", err)
+	}
+	if err := log.BatchDelete(expired); err != nil {
+		return xerrors.Errorf("failed to prune datacap events: %w", err)
+	}
+
+	if st.DataCapEvents, err = log.Root(); err != nil {
+		return xerrors.Errorf("failed to flush datacap events: %w", err)
+	}
+	return nil
+}
+
+// The position of a DataCap event in the log: the epoch at which it was recorded, and its index among the events
+// recorded at that epoch.
+type DataCapEventsCursor struct {
+	Epoch abi.ChainEpoch
+	Index uint64
+}
+
+// Loads retained DataCap events recorded at epochs in the inclusive range [from, to], starting at a cursor if not nil,
+// until the events loaded would exceed DataCapEventsPageLength events or DataCapEventsPageSize bytes when encoded.
+// Returns a cursor for the first event not loaded if the events are truncated, else nil.
+func (st *State) LoadDataCapEvents(store adt.Store, from, to abi.ChainEpoch, start *DataCapEventsCursor) ([]DataCapEventRecord, *DataCapEventsCursor, error) {
+	log, err := adt.AsArray(store, st.DataCapEvents)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("failed to load datacap events: %w", err)
+	}
+
+	records := []DataCapEventRecord{}
+	var next *DataCapEventsCursor
+	enc := acbor.NewArrayEncoder(DataCapEventsPageLength, DataCapEventsPageSize)
+	var set DataCapEventSet
+	stopErr := xerrors.New("stop")
+	if err := log.ForEach(&set, func(i int64) error {
+		epoch := abi.ChainEpoch(i)
+		if epoch > to {
+			return stopErr
+		}
+		if epoch < from || (start != nil && epoch < start.Epoch) {
+			return nil
+		}
+		for j, event := range set.Events {
+			if start != nil && epoch == start.Epoch && uint64(j) < start.Index {
+				continue
+			}
+			record := DataCapEventRecord{Epoch: epoch, Event: event}
+			if ok, err := enc.Append(&record); err != nil {
+				return err
+			} else if !ok {
+				next = &DataCapEventsCursor{Epoch: epoch, Index: uint64(j)}
+				return stopErr
+			}
+			records = append(records, record)
+		}
+		return nil
+	}); err != nil && err != stopErr {
+		return nil, nil, xerrors.Errorf("failed to iterate datacap events: %w", err)
 	}
+	return records, next, nil
 }
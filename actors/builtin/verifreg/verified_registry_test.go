@@ -2,12 +2,14 @@ package verifreg_test
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"testing"
 
 	"github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/crypto"
 	"github.com/filecoin-project/go-state-types/exitcode"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -27,7 +29,7 @@ func TestConstruction(t *testing.T) {
 	receiver := tutil.NewIDAddr(t, 100)
 
 	runtimeSetup := func() *mock.RuntimeBuilder {
-		builder := mock.NewBuilder(context.Background(), receiver).
+		builder := mock.NewBuilder(context.Background(), receiver).WithStateInvariants(verifreg.CheckStateInvariants).
 			WithCaller(builtin.SystemActorAddr, builtin.InitActorCodeID)
 
 		return builder
@@ -447,6 +449,174 @@ func TestAddVerifiedClient(t *testing.T) {
 	})
 }
 
+func TestAddVerifiedClients(t *testing.T) {
+	root := tutil.NewIDAddr(t, 101)
+	clientAddr := tutil.NewIDAddr(t, 201)
+	clientAddr2 := tutil.NewIDAddr(t, 202)
+	clientAddr3 := tutil.NewIDAddr(t, 203)
+	verifierAddr := tutil.NewIDAddr(t, 301)
+	verifierAddr2 := tutil.NewIDAddr(t, 302)
+	clientAllowance := big.Add(verifreg.MinVerifiedDealSize, big.NewInt(42))
+
+	t.Run("successfully add many verified clients", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+		verifierAllowance := big.Mul(clientAllowance, big.NewInt(3))
+		ac.addVerifier(rt, verifierAddr, verifierAllowance)
+
+		results := ac.addVerifiedClients(rt, verifierAddr,
+			*mkClientParams(clientAddr, clientAllowance),
+			*mkClientParams(clientAddr2, clientAllowance),
+			*mkClientParams(clientAddr3, clientAllowance),
+		)
+		assert.Equal(t, []exitcode.ExitCode{exitcode.Ok, exitcode.Ok, exitcode.Ok}, results)
+
+		assert.EqualValues(t, clientAllowance, ac.getClientCap(rt, clientAddr))
+		assert.EqualValues(t, clientAllowance, ac.getClientCap(rt, clientAddr2))
+		assert.EqualValues(t, clientAllowance, ac.getClientCap(rt, clientAddr3))
+		assert.EqualValues(t, big.Zero(), ac.getVerifierCap(rt, verifierAddr))
+		assert.Len(t, ac.getDataCapEvents(rt, 0, rt.Epoch()), 4)
+		ac.checkState(rt)
+	})
+
+	t.Run("reports failure of individual entries and adds the rest", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+		ac.addVerifier(rt, verifierAddr, big.Mul(clientAllowance, big.NewInt(2)))
+		ac.addVerifier(rt, verifierAddr2, clientAllowance)
+		ac.addVerifiedClient(rt, verifierAddr, clientAddr, clientAllowance)
+
+		results := ac.addVerifiedClients(rt, verifierAddr,
+			*mkClientParams(clientAddr2, big.Sub(verifreg.MinVerifiedDealSize, big.NewInt(1))), // allowance too small
+			*mkClientParams(clientAddr, clientAllowance),                                       // already a client
+			*mkClientParams(verifierAddr2, clientAllowance),                                    // a verifier
+			*mkClientParams(root, clientAllowance),                                             // the root key
+			*mkClientParams(clientAddr2, clientAllowance),
+			*mkClientParams(clientAddr3, clientAllowance), // exceeds remaining verifier cap
+		)
+		assert.Equal(t, []exitcode.ExitCode{
+			exitcode.ErrIllegalArgument,
+			exitcode.ErrIllegalArgument,
+			exitcode.ErrIllegalArgument,
+			exitcode.ErrIllegalArgument,
+			exitcode.Ok,
+			exitcode.ErrIllegalArgument,
+		}, results)
+
+		assert.EqualValues(t, clientAllowance, ac.getClientCap(rt, clientAddr2))
+		ac.assertClientRemoved(rt, clientAddr3)
+		assert.EqualValues(t, big.Zero(), ac.getVerifierCap(rt, verifierAddr))
+		ac.checkState(rt)
+	})
+
+	t.Run("reports failure to resolve a client address", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+		ac.addVerifier(rt, verifierAddr, clientAllowance)
+
+		clientNonIdAddr := tutil.NewBLSAddr(t, 1)
+		rt.ExpectSend(clientNonIdAddr, builtin.MethodSend, nil, abi.NewTokenAmount(0), nil, exitcode.ErrForbidden)
+
+		results := ac.addVerifiedClients(rt, verifierAddr,
+			*mkClientParams(clientNonIdAddr, clientAllowance),
+			*mkClientParams(clientAddr, clientAllowance),
+		)
+		assert.Equal(t, []exitcode.ExitCode{exitcode.ErrForbidden, exitcode.Ok}, results)
+		assert.EqualValues(t, clientAllowance, ac.getClientCap(rt, clientAddr))
+		ac.checkState(rt)
+	})
+
+	t.Run("fails when caller is not a verifier without resolving client addresses", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+
+		// No send is expected to create an account for the client's address.
+		rt.SetCaller(verifierAddr, builtin.VerifiedRegistryActorCodeID)
+		rt.ExpectValidateCallerAny()
+		params := &verifreg.AddVerifiedClientsParams{Clients: []verifreg.AddVerifiedClientParams{*mkClientParams(tutil.NewBLSAddr(t, 1), clientAllowance)}}
+		rt.ExpectAbort(exitcode.ErrNotFound, func() {
+			rt.Call(ac.AddVerifiedClients, params)
+		})
+		ac.checkState(rt)
+	})
+
+	t.Run("fails with too many clients", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+		ac.addVerifier(rt, verifierAddr, clientAllowance)
+
+		clients := make([]verifreg.AddVerifiedClientParams, verifreg.MaxVerifiedClientsBatchSize+1)
+		for i := range clients {
+			clients[i] = *mkClientParams(tutil.NewIDAddr(t, uint64(1000+i)), clientAllowance)
+		}
+		rt.SetCaller(verifierAddr, builtin.VerifiedRegistryActorCodeID)
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(ac.AddVerifiedClients, &verifreg.AddVerifiedClientsParams{Clients: clients})
+		})
+		ac.checkState(rt)
+	})
+}
+
+func TestCheckClientSeparation(t *testing.T) {
+	root := tutil.NewIDAddr(t, 101)
+	clientAddr := tutil.NewIDAddr(t, 201)
+	minerAddr := tutil.NewIDAddr(t, 301)
+	minerAddr2 := tutil.NewIDAddr(t, 302)
+	owner := tutil.NewIDAddr(t, 401)
+	worker := tutil.NewIDAddr(t, 402)
+
+	t.Run("reports miners controlled by the client", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+		rt.SetAddressActorType(minerAddr, builtin.StorageMinerActorCodeID)
+		rt.SetAddressActorType(minerAddr2, builtin.StorageMinerActorCodeID)
+		rt.SetAddressActorType(clientAddr, builtin.AccountActorCodeID)
+
+		ac.expectMinerControlAddresses(rt, minerAddr, owner, worker)
+		ac.expectMinerControlAddresses(rt, minerAddr2, owner, worker, clientAddr)
+		conflicts := ac.checkClientSeparation(rt, clientAddr, minerAddr, minerAddr2)
+		assert.Equal(t, []address.Address{minerAddr2}, conflicts)
+
+		ac.expectMinerControlAddresses(rt, minerAddr, owner, worker)
+		conflicts = ac.checkClientSeparation(rt, owner, minerAddr)
+		assert.Equal(t, []address.Address{minerAddr}, conflicts)
+
+		ac.expectMinerControlAddresses(rt, minerAddr, owner, worker)
+		conflicts = ac.checkClientSeparation(rt, worker, minerAddr)
+		assert.Equal(t, []address.Address{minerAddr}, conflicts)
+		ac.checkState(rt)
+	})
+
+	t.Run("reports no conflicts for a client without an ID address", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+		rt.SetAddressActorType(minerAddr, builtin.StorageMinerActorCodeID)
+
+		conflicts := ac.checkClientSeparation(rt, tutil.NewBLSAddr(t, 1), minerAddr)
+		assert.Empty(t, conflicts)
+		ac.checkState(rt)
+	})
+
+	t.Run("fails when a listed address is not a miner", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+		rt.SetAddressActorType(minerAddr, builtin.AccountActorCodeID)
+
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(ac.CheckClientSeparation, &verifreg.CheckClientSeparationParams{Client: clientAddr, Miners: []address.Address{minerAddr}})
+		})
+		ac.checkState(rt)
+	})
+
+	t.Run("fails with too many miners", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+
+		miners := make([]address.Address, verifreg.MaxCheckClientSeparationMiners+1)
+		for i := range miners {
+			miners[i] = tutil.NewIDAddr(t, uint64(1000+i))
+		}
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "too many miners", func() {
+			rt.Call(ac.CheckClientSeparation, &verifreg.CheckClientSeparationParams{Client: clientAddr, Miners: miners})
+		})
+		ac.checkState(rt)
+	})
+}
+
 func TestUseBytes(t *testing.T) {
 	root := tutil.NewIDAddr(t, 101)
 	clientAddr := tutil.NewIDAddr(t, 201)
@@ -772,6 +942,271 @@ func TestRestoreBytes(t *testing.T) {
 	})
 }
 
+func TestRemoveVerifiedClientDataCap(t *testing.T) {
+	root := tutil.NewIDAddr(t, 101)
+	verifier1 := tutil.NewIDAddr(t, 201)
+	verifier2 := tutil.NewIDAddr(t, 202)
+	clientAddr := tutil.NewIDAddr(t, 301)
+	clientAllowance := big.Mul(verifreg.MinVerifiedDealSize, big.NewInt(3))
+
+	setup := func(t *testing.T) (*mock.Runtime, *verifRegActorTestHarness) {
+		rt, ac := basicVerifRegSetup(t, root)
+		ac.generateAndAddVerifierAndVerifiedClient(rt, verifier1, clientAddr, verifreg.MinVerifiedDealSize, clientAllowance)
+		ac.addVerifier(rt, verifier2, verifreg.MinVerifiedDealSize)
+		return rt, ac
+	}
+
+	t.Run("removes part of a client's datacap", func(t *testing.T) {
+		rt, ac := setup(t)
+		rt.SetEpoch(10)
+
+		removed := ac.removeVerifiedClientDataCap(rt, clientAddr, verifreg.MinVerifiedDealSize, verifier1, verifier2)
+		assert.Equal(t, verifreg.MinVerifiedDealSize, removed)
+		assert.Equal(t, big.Sub(clientAllowance, verifreg.MinVerifiedDealSize), ac.getClientCap(rt, clientAddr))
+
+		events := ac.getDataCapEvents(rt, 10, 10)
+		assert.Equal(t, []verifreg.DataCapEventRecord{
+			{Epoch: 10, Event: verifreg.DataCapEvent{Kind: verifreg.DataCapRemoved, Source: root, Target: clientAddr, Amount: verifreg.MinVerifiedDealSize}},
+		}, events)
+		ac.checkState(rt)
+	})
+
+	t.Run("removes the client when the remainder falls below the minimum deal size", func(t *testing.T) {
+		rt, ac := setup(t)
+
+		amount := big.Sub(clientAllowance, big.NewInt(1))
+		removed := ac.removeVerifiedClientDataCap(rt, clientAddr, amount, verifier1, verifier2)
+		assert.Equal(t, clientAllowance, removed)
+		ac.assertClientRemoved(rt, clientAddr)
+		ac.checkState(rt)
+	})
+
+	t.Run("removes at most the client's datacap", func(t *testing.T) {
+		rt, ac := setup(t)
+
+		removed := ac.removeVerifiedClientDataCap(rt, clientAddr, big.Mul(clientAllowance, big.NewInt(2)), verifier1, verifier2)
+		assert.Equal(t, clientAllowance, removed)
+		ac.assertClientRemoved(rt, clientAddr)
+		ac.checkState(rt)
+	})
+
+	t.Run("proposal ids increment so signatures cannot be replayed", func(t *testing.T) {
+		rt, ac := setup(t)
+
+		ac.removeVerifiedClientDataCap(rt, clientAddr, verifreg.MinVerifiedDealSize, verifier1, verifier2)
+		st := ac.state(rt)
+		for _, v := range []address.Address{verifier1, verifier2} {
+			id, err := st.GetRemoveDataCapProposalID(rt.AdtStore(), v, clientAddr)
+			require.NoError(t, err)
+			assert.Equal(t, uint64(1), id.ProposalID)
+		}
+
+		// The second removal must be signed over the next proposal ID, so the first signatures are not valid for it.
+		ac.removeVerifiedClientDataCap(rt, clientAddr, verifreg.MinVerifiedDealSize, verifier1, verifier2)
+		assert.Equal(t, verifreg.MinVerifiedDealSize, ac.getClientCap(rt, clientAddr))
+		ac.checkState(rt)
+	})
+
+	t.Run("fails when caller is not the root key", func(t *testing.T) {
+		rt, ac := setup(t)
+		params := mkRemoveDataCapParams(clientAddr, verifreg.MinVerifiedDealSize, verifier1, verifier2)
+
+		rt.SetCaller(tutil.NewIDAddr(t, 501), builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(ac.rootkey)
+		rt.ExpectAbort(exitcode.SysErrForbidden, func() {
+			rt.Call(ac.RemoveVerifiedClientDataCap, params)
+		})
+		ac.checkState(rt)
+	})
+
+	t.Run("fails with the same verifier twice", func(t *testing.T) {
+		rt, ac := setup(t)
+		params := mkRemoveDataCapParams(clientAddr, verifreg.MinVerifiedDealSize, verifier1, verifier1)
+
+		rt.SetCaller(ac.rootkey, builtin.MultisigActorCodeID)
+		rt.ExpectValidateCallerAddr(ac.rootkey)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "two different verifiers", func() {
+			rt.Call(ac.RemoveVerifiedClientDataCap, params)
+		})
+		ac.checkState(rt)
+	})
+
+	t.Run("fails with a non-positive amount", func(t *testing.T) {
+		rt, ac := setup(t)
+		params := mkRemoveDataCapParams(clientAddr, big.Zero(), verifier1, verifier2)
+
+		rt.SetCaller(ac.rootkey, builtin.MultisigActorCodeID)
+		rt.ExpectValidateCallerAddr(ac.rootkey)
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(ac.RemoveVerifiedClientDataCap, params)
+		})
+		ac.checkState(rt)
+	})
+
+	t.Run("fails when a signer is not a verifier", func(t *testing.T) {
+		rt, ac := setup(t)
+		params := mkRemoveDataCapParams(clientAddr, verifreg.MinVerifiedDealSize, verifier1, tutil.NewIDAddr(t, 501))
+
+		rt.SetCaller(ac.rootkey, builtin.MultisigActorCodeID)
+		rt.ExpectValidateCallerAddr(ac.rootkey)
+		rt.ExpectAbort(exitcode.ErrNotFound, func() {
+			rt.Call(ac.RemoveVerifiedClientDataCap, params)
+		})
+		ac.checkState(rt)
+	})
+
+	t.Run("fails when the client is not a verified client", func(t *testing.T) {
+		rt, ac := setup(t)
+		params := mkRemoveDataCapParams(tutil.NewIDAddr(t, 501), verifreg.MinVerifiedDealSize, verifier1, verifier2)
+
+		rt.SetCaller(ac.rootkey, builtin.MultisigActorCodeID)
+		rt.ExpectValidateCallerAddr(ac.rootkey)
+		rt.ExpectAbort(exitcode.ErrNotFound, func() {
+			rt.Call(ac.RemoveVerifiedClientDataCap, params)
+		})
+		ac.checkState(rt)
+	})
+
+	t.Run("fails with an invalid signature", func(t *testing.T) {
+		rt, ac := setup(t)
+		params := mkRemoveDataCapParams(clientAddr, verifreg.MinVerifiedDealSize, verifier1, verifier2)
+
+		rt.SetCaller(ac.rootkey, builtin.MultisigActorCodeID)
+		rt.ExpectValidateCallerAddr(ac.rootkey)
+		ac.expectRemoveDataCapSignature(rt, verifier1, clientAddr, verifreg.MinVerifiedDealSize, 0, nil)
+		ac.expectRemoveDataCapSignature(rt, verifier2, clientAddr, verifreg.MinVerifiedDealSize, 0, errors.New("bad signature"))
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "invalid signature", func() {
+			rt.Call(ac.RemoveVerifiedClientDataCap, params)
+		})
+		assert.Equal(t, clientAllowance, ac.getClientCap(rt, clientAddr))
+		ac.checkState(rt)
+	})
+}
+
+func TestDataCapEvents(t *testing.T) {
+	root := tutil.NewIDAddr(t, 101)
+	verifierAddr := tutil.NewIDAddr(t, 201)
+	clientAddr := tutil.NewIDAddr(t, 301)
+	clientAllowance := big.Mul(verifreg.MinVerifiedDealSize, big.NewInt(3))
+	dealSize := verifreg.MinVerifiedDealSize
+
+	t.Run("records grants, uses, restorations and removals", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+
+		rt.SetEpoch(10)
+		ac.addVerifier(rt, verifierAddr, clientAllowance)
+		rt.SetEpoch(11)
+		ac.addVerifiedClient(rt, verifierAddr, clientAddr, clientAllowance)
+		rt.SetEpoch(12)
+		ac.useBytes(rt, clientAddr, dealSize, &capExpectation{expectedCap: big.Sub(clientAllowance, dealSize)})
+		ac.restoreBytes(rt, clientAddr, dealSize, &capExpectation{expectedCap: clientAllowance})
+		rt.SetEpoch(13)
+		ac.removeVerifier(rt, verifierAddr)
+
+		events := ac.getDataCapEvents(rt, 0, 13)
+		assert.Equal(t, []verifreg.DataCapEventRecord{
+			{Epoch: 10, Event: verifreg.DataCapEvent{Kind: verifreg.DataCapGranted, Source: root, Target: verifierAddr, Amount: clientAllowance}},
+			{Epoch: 11, Event: verifreg.DataCapEvent{Kind: verifreg.DataCapGranted, Source: verifierAddr, Target: clientAddr, Amount: clientAllowance}},
+			{Epoch: 12, Event: verifreg.DataCapEvent{Kind: verifreg.DataCapUsed, Source: builtin.StorageMarketActorAddr, Target: clientAddr, Amount: dealSize}},
+			{Epoch: 12, Event: verifreg.DataCapEvent{Kind: verifreg.DataCapRestored, Source: builtin.StorageMarketActorAddr, Target: clientAddr, Amount: dealSize}},
+			{Epoch: 13, Event: verifreg.DataCapEvent{Kind: verifreg.DataCapRemoved, Source: root, Target: verifierAddr, Amount: big.Zero()}},
+		}, events)
+
+		// Query a sub-range.
+		events = ac.getDataCapEvents(rt, 11, 11)
+		require.Len(t, events, 1)
+		assert.Equal(t, verifreg.DataCapGranted, events[0].Event.Kind)
+		assert.Equal(t, clientAddr, events[0].Event.Target)
+		ac.checkState(rt)
+	})
+
+	t.Run("records removal of client remainder below minimum deal size", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+		allowance := big.Add(verifreg.MinVerifiedDealSize, big.NewInt(5))
+		ac.generateAndAddVerifierAndVerifiedClient(rt, verifierAddr, clientAddr, allowance, allowance)
+
+		rt.SetEpoch(20)
+		ac.useBytes(rt, clientAddr, dealSize, &capExpectation{removed: true})
+
+		events := ac.getDataCapEvents(rt, 20, 20)
+		assert.Equal(t, []verifreg.DataCapEventRecord{
+			{Epoch: 20, Event: verifreg.DataCapEvent{Kind: verifreg.DataCapUsed, Source: builtin.StorageMarketActorAddr, Target: clientAddr, Amount: dealSize}},
+			{Epoch: 20, Event: verifreg.DataCapEvent{Kind: verifreg.DataCapRemoved, Source: builtin.StorageMarketActorAddr, Target: clientAddr, Amount: big.NewInt(5)}},
+		}, events)
+		ac.checkState(rt)
+	})
+
+	t.Run("prunes events outside the retention window", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+
+		rt.SetEpoch(10)
+		ac.addVerifier(rt, verifierAddr, clientAllowance)
+		require.Len(t, ac.getDataCapEvents(rt, 0, 10), 1)
+
+		// Recording a new event once the first has aged out removes it.
+		rt.SetEpoch(10 + verifreg.DataCapEventRetention)
+		ac.addVerifiedClient(rt, verifierAddr, clientAddr, clientAllowance)
+
+		events := ac.getDataCapEvents(rt, 0, rt.Epoch())
+		require.Len(t, events, 1)
+		assert.Equal(t, rt.Epoch(), events[0].Epoch)
+		assert.Equal(t, clientAddr, events[0].Event.Target)
+		ac.checkState(rt)
+	})
+
+	t.Run("pages through events truncated to the return limits", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+
+		rt.SetEpoch(10)
+		ac.addVerifier(rt, verifierAddr, clientAllowance)
+		rt.SetEpoch(11)
+		ac.addVerifiedClient(rt, verifierAddr, clientAddr, clientAllowance)
+		rt.SetEpoch(12)
+		ac.useBytes(rt, clientAddr, dealSize, &capExpectation{expectedCap: big.Sub(clientAllowance, dealSize)})
+		ac.useBytes(rt, clientAddr, dealSize, &capExpectation{expectedCap: big.Sub(clientAllowance, big.Mul(dealSize, big.NewInt(2)))})
+		all := ac.getDataCapEvents(rt, 0, 12)
+		require.Len(t, all, 4)
+
+		defer func(length uint64) { verifreg.DataCapEventsPageLength = length }(verifreg.DataCapEventsPageLength)
+		verifreg.DataCapEventsPageLength = 3
+
+		page := ac.getDataCapEventsPage(rt, 0, 12, nil)
+		assert.Equal(t, all[:3], page.Events)
+		assert.Equal(t, &verifreg.DataCapEventsCursor{Epoch: 12, Index: 1}, page.Next)
+
+		page = ac.getDataCapEventsPage(rt, 0, 12, page.Next)
+		assert.Equal(t, all[3:], page.Events)
+		assert.Nil(t, page.Next)
+
+		// Pages are limited by encoded size as well as number.
+		verifreg.DataCapEventsPageLength = 100
+		defer func(size int64) { verifreg.DataCapEventsPageSize = size }(verifreg.DataCapEventsPageSize)
+		verifreg.DataCapEventsPageSize = 1
+		page = ac.getDataCapEventsPage(rt, 0, 12, nil)
+		assert.Empty(t, page.Events)
+		assert.Equal(t, &verifreg.DataCapEventsCursor{Epoch: 10, Index: 0}, page.Next)
+
+		// A cursor outside the range is rejected.
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(ac.GetDataCapEvents, &verifreg.GetDataCapEventsParams{FromEpoch: 11, ToEpoch: 12,
+				Cursor: &verifreg.DataCapEventsCursor{Epoch: 10}})
+		})
+		rt.Verify()
+		ac.checkState(rt)
+	})
+
+	t.Run("fails with invalid epoch range", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(ac.GetDataCapEvents, &verifreg.GetDataCapEventsParams{FromEpoch: 5, ToEpoch: 4})
+		})
+		rt.Verify()
+	})
+}
+
 type verifRegActorTestHarness struct {
 	rootkey address.Address
 	verifreg.Actor
@@ -779,7 +1214,7 @@ type verifRegActorTestHarness struct {
 }
 
 func basicVerifRegSetup(t *testing.T, root address.Address) (*mock.Runtime, *verifRegActorTestHarness) {
-	builder := mock.NewBuilder(context.Background(), builtin.StorageMarketActorAddr).
+	builder := mock.NewBuilder(context.Background(), builtin.StorageMarketActorAddr).WithStateInvariants(verifreg.CheckStateInvariants).
 		WithCaller(builtin.SystemActorAddr, builtin.InitActorCodeID).
 		WithActorType(root, builtin.VerifiedRegistryActorCodeID)
 
@@ -834,16 +1269,43 @@ func (h *verifRegActorTestHarness) generateAndAddVerifierAndVerifiedClient(rt *m
 func (h *verifRegActorTestHarness) addVerifiedClient(rt *mock.Runtime, verifier, client address.Address, allowance verifreg.DataCap) {
 	rt.SetCaller(verifier, builtin.VerifiedRegistryActorCodeID)
 	rt.ExpectValidateCallerAny()
+	clientIdAddr, found := rt.GetIdAddr(client)
+	if found {
+		rt.ExpectEvent(builtin.EventKeyDataCapGranted, &builtin.DataCapGrantedEvent{Verifier: verifier, Client: clientIdAddr, Allowance: allowance})
+	}
 
 	params := &verifreg.AddVerifiedClientParams{Address: client, Allowance: allowance}
 	rt.Call(h.AddVerifiedClient, params)
 	rt.Verify()
 
-	clientIdAddr, found := rt.GetIdAddr(client)
 	require.True(h.t, found)
 	assert.EqualValues(h.t, allowance, h.getClientCap(rt, clientIdAddr))
 }
 
+func (h *verifRegActorTestHarness) addVerifiedClients(rt *mock.Runtime, verifier address.Address, clients ...verifreg.AddVerifiedClientParams) []exitcode.ExitCode {
+	rt.SetCaller(verifier, builtin.VerifiedRegistryActorCodeID)
+	rt.ExpectValidateCallerAny()
+
+	ret := rt.Call(h.AddVerifiedClients, &verifreg.AddVerifiedClientsParams{Clients: clients}).(*verifreg.AddVerifiedClientsReturn)
+	rt.Verify()
+	require.Len(h.t, ret.Results, len(clients))
+	return ret.Results
+}
+
+func (h *verifRegActorTestHarness) checkClientSeparation(rt *mock.Runtime, client address.Address, miners ...address.Address) []address.Address {
+	rt.SetCaller(tutil.NewIDAddr(h.t, 1000), builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAny()
+
+	ret := rt.Call(h.CheckClientSeparation, &verifreg.CheckClientSeparationParams{Client: client, Miners: miners}).(*verifreg.CheckClientSeparationReturn)
+	rt.Verify()
+	return ret.Conflicts
+}
+
+func (h *verifRegActorTestHarness) expectMinerControlAddresses(rt *mock.Runtime, miner, owner, worker address.Address, controlAddrs ...address.Address) {
+	rt.ExpectSendReadOnly(miner, builtin.MethodsMiner.ControlAddresses, nil,
+		&builtin.MinerAddrs{Owner: owner, Worker: worker, ControlAddrs: controlAddrs}, exitcode.Ok)
+}
+
 func (h *verifRegActorTestHarness) addVerifier(rt *mock.Runtime, verifier address.Address, datacap verifreg.DataCap) {
 	param := verifreg.AddVerifierParams{Address: verifier, Allowance: datacap}
 
@@ -870,6 +1332,51 @@ func (h *verifRegActorTestHarness) removeVerifier(rt *mock.Runtime, verifier add
 	h.assertVerifierRemoved(rt, verifier)
 }
 
+func (h *verifRegActorTestHarness) removeVerifiedClientDataCap(rt *mock.Runtime, client address.Address, amount verifreg.DataCap,
+	verifier1, verifier2 address.Address) verifreg.DataCap {
+	st := h.state(rt)
+	id1, err := st.GetRemoveDataCapProposalID(rt.AdtStore(), verifier1, client)
+	require.NoError(h.t, err)
+	id2, err := st.GetRemoveDataCapProposalID(rt.AdtStore(), verifier2, client)
+	require.NoError(h.t, err)
+
+	rt.SetCaller(h.rootkey, builtin.MultisigActorCodeID)
+	rt.ExpectValidateCallerAddr(h.rootkey)
+	h.expectRemoveDataCapSignature(rt, verifier1, client, amount, id1.ProposalID, nil)
+	h.expectRemoveDataCapSignature(rt, verifier2, client, amount, id2.ProposalID, nil)
+
+	params := mkRemoveDataCapParams(client, amount, verifier1, verifier2)
+	ret := rt.Call(h.RemoveVerifiedClientDataCap, params).(*verifreg.RemoveVerifiedClientDataCapReturn)
+	rt.Verify()
+	assert.Equal(h.t, client, ret.VerifiedClient)
+	return ret.DataCapRemoved
+}
+
+func (h *verifRegActorTestHarness) expectRemoveDataCapSignature(rt *mock.Runtime, verifier, client address.Address,
+	amount verifreg.DataCap, id uint64, result error) {
+	proposal := verifreg.RemoveDataCapProposal{
+		VerifiedClient:    client,
+		DataCapAmount:     amount,
+		RemovalProposalID: verifreg.RemoveDataCapProposalID{ProposalID: id},
+	}
+	b, err := proposal.SigningBytes()
+	require.NoError(h.t, err)
+	rt.ExpectVerifySignature(removeDataCapSignature(verifier), verifier, b, result)
+}
+
+func (h *verifRegActorTestHarness) getDataCapEvents(rt *mock.Runtime, from, to abi.ChainEpoch) []verifreg.DataCapEventRecord {
+	ret := h.getDataCapEventsPage(rt, from, to, nil)
+	require.Nil(h.t, ret.Next)
+	return ret.Events
+}
+
+func (h *verifRegActorTestHarness) getDataCapEventsPage(rt *mock.Runtime, from, to abi.ChainEpoch, cursor *verifreg.DataCapEventsCursor) *verifreg.GetDataCapEventsReturn {
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.GetDataCapEvents, &verifreg.GetDataCapEventsParams{FromEpoch: from, ToEpoch: to, Cursor: cursor}).(*verifreg.GetDataCapEventsReturn)
+	rt.Verify()
+	return ret
+}
+
 type capExpectation struct {
 	expectedCap verifreg.DataCap
 	removed     bool
@@ -975,3 +1482,15 @@ func mkClientParams(a address.Address, cap verifreg.DataCap) *verifreg.AddVerifi
 	return &verifreg.AddVerifiedClientParams{Address: a, Allowance: cap}
 }
 
+func mkRemoveDataCapParams(client address.Address, amount verifreg.DataCap, verifier1, verifier2 address.Address) *verifreg.RemoveVerifiedClientDataCapParams {
+	return &verifreg.RemoveVerifiedClientDataCapParams{
+		VerifiedClientToRemove: client,
+		DataCapAmountToRemove:  amount,
+		VerifierRequest1:       verifreg.RemoveDataCapRequest{Verifier: verifier1, VerifierSignature: removeDataCapSignature(verifier1)},
+		VerifierRequest2:       verifreg.RemoveDataCapRequest{Verifier: verifier2, VerifierSignature: removeDataCapSignature(verifier2)},
+	}
+}
+
+func removeDataCapSignature(verifier address.Address) crypto.Signature {
+	return crypto.Signature{Type: crypto.SigTypeBLS, Data: verifier.Bytes()}
+}
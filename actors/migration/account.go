@@ -4,23 +4,32 @@ import (
 	"context"
 
 	"github.com/filecoin-project/go-state-types/big"
+	builtin0 "github.com/filecoin-project/specs-actors/actors/builtin"
 	account0 "github.com/filecoin-project/specs-actors/actors/builtin/account"
 	cid "github.com/ipfs/go-cid"
 	cbor "github.com/ipfs/go-ipld-cbor"
 
+	builtin2 "github.com/filecoin-project/specs-actors/v2/actors/builtin"
 	account2 "github.com/filecoin-project/specs-actors/v2/actors/builtin/account"
 )
 
 type accountMigrator struct {
 }
 
+func init() {
+	RegisterActorMigration(builtin0.AccountActorCodeID, ActorsVersion0, ActorMigration{
+		OutCodeCID:     builtin2.AccountActorCodeID,
+		StateMigration: accountMigrator{},
+	})
+}
+
 func (m accountMigrator) MigrateState(ctx context.Context, store cbor.IpldStore, head cid.Cid, _ MigrationInfo) (*StateMigrationResult, error) {
 	var inState account0.State
 	if err := store.Get(ctx, head, &inState); err != nil {
 		return nil, err
 	}
 
-	outState := account2.State(inState) // Identical
+	outState := account2.State{Address: inState.Address}
 	newHead, err := store.Put(ctx, &outState)
 	return &StateMigrationResult{
 		NewHead:  newHead,
@@ -0,0 +1,51 @@
+package migration
+
+import (
+	"sync"
+
+	address "github.com/filecoin-project/go-address"
+	"github.com/ipfs/go-cid"
+)
+
+// A cache of migrated actor state, mapping an actor's state head prior to migration to the head migrated from it.
+// Implementations may persist the cache, so that a migration may be computed ahead of the upgrade epoch, or
+// interrupted and resumed, and then repeated at the upgrade epoch migrating only the actors whose state has
+// changed since. The migrated state must also be present in the store.
+// Implementations must be safe for concurrent use.
+type MigrationCache interface {
+	Write(key string, newHead cid.Cid) error
+	Read(key string) (found bool, newHead cid.Cid, err error)
+}
+
+// Returns the cache key for an actor's state head.
+func ActorHeadKey(addr address.Address, head cid.Cid) string {
+	return addr.String() + "-h-" + head.String()
+}
+
+// Returns the key under which the head migrated from a state head is shared between actors with the same code
+// within a migration.
+func codeHeadKey(code, head cid.Cid) string {
+	return code.String() + "-h-" + head.String()
+}
+
+// A migration cache held in memory.
+type MemMigrationCache struct {
+	heads sync.Map
+}
+
+func NewMemMigrationCache() *MemMigrationCache {
+	return new(MemMigrationCache)
+}
+
+func (c *MemMigrationCache) Write(key string, newHead cid.Cid) error {
+	c.heads.Store(key, newHead)
+	return nil
+}
+
+func (c *MemMigrationCache) Read(key string) (bool, cid.Cid, error) {
+	val, found := c.heads.Load(key)
+	if !found {
+		return false, cid.Undef, nil
+	}
+	return true, val.(cid.Cid), nil
+}
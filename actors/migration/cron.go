@@ -4,27 +4,43 @@ import (
 	"context"
 
 	"github.com/filecoin-project/go-state-types/big"
+	builtin0 "github.com/filecoin-project/specs-actors/actors/builtin"
 	cron0 "github.com/filecoin-project/specs-actors/actors/builtin/cron"
 	cid "github.com/ipfs/go-cid"
 	cbor "github.com/ipfs/go-ipld-cbor"
 
+	builtin2 "github.com/filecoin-project/specs-actors/v2/actors/builtin"
 	cron2 "github.com/filecoin-project/specs-actors/v2/actors/builtin/cron"
 )
 
 type cronMigrator struct {
 }
 
+func init() {
+	RegisterActorMigration(builtin0.CronActorCodeID, ActorsVersion0, ActorMigration{
+		OutCodeCID:     builtin2.CronActorCodeID,
+		StateMigration: cronMigrator{},
+	})
+}
+
 func (m cronMigrator) MigrateState(ctx context.Context, store cbor.IpldStore, head cid.Cid, _ MigrationInfo) (*StateMigrationResult, error) {
 	var inState cron0.State
 	if err := store.Get(ctx, head, &inState); err != nil {
 		return nil, err
 	}
 
-	outState := cron2.State{Entries: make([]cron2.Entry, len(inState.Entries))}
+	// Builtin entries are assigned their priorities; others retain their order after them.
+	entries := make([]cron2.Entry, len(inState.Entries))
 	for i, e := range inState.Entries {
-		outState.Entries[i] = cron2.Entry(e) // Identical
+		entries[i] = cron2.Entry{Receiver: e.Receiver, MethodNum: e.MethodNum, Priority: cron2.DefaultEntryPriority}
+		for _, b := range cron2.BuiltInEntries() {
+			if b.Receiver == e.Receiver && b.MethodNum == e.MethodNum {
+				entries[i].Priority = b.Priority
+			}
+		}
 	}
-	newHead, err := store.Put(ctx, &outState)
+	outState := cron2.ConstructState(entries)
+	newHead, err := store.Put(ctx, outState)
 	return &StateMigrationResult{
 		NewHead:  newHead,
 		Transfer: big.Zero(),
@@ -3,18 +3,31 @@ package migration
 import (
 	"context"
 
+	addr "github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
+	builtin0 "github.com/filecoin-project/specs-actors/actors/builtin"
 	init0 "github.com/filecoin-project/specs-actors/actors/builtin/init"
 	cid "github.com/ipfs/go-cid"
 	cbor "github.com/ipfs/go-ipld-cbor"
+	cbg "github.com/whyrusleeping/cbor-gen"
 	"golang.org/x/xerrors"
 
+	builtin2 "github.com/filecoin-project/specs-actors/v2/actors/builtin"
 	init2 "github.com/filecoin-project/specs-actors/v2/actors/builtin/init"
+	adt2 "github.com/filecoin-project/specs-actors/v2/actors/util/adt"
 )
 
 type initMigrator struct {
 }
 
+func init() {
+	RegisterActorMigration(builtin0.InitActorCodeID, ActorsVersion0, ActorMigration{
+		OutCodeCID:     builtin2.InitActorCodeID,
+		StateMigration: initMigrator{},
+	})
+}
+
 func (m initMigrator) MigrateState(ctx context.Context, store cbor.IpldStore, head cid.Cid, _ MigrationInfo) (*StateMigrationResult, error) {
 	var inState init0.State
 	if err := store.Get(ctx, head, &inState); err != nil {
@@ -27,10 +40,24 @@ func (m initMigrator) MigrateState(ctx context.Context, store cbor.IpldStore, he
 		return nil, xerrors.Errorf("migrate addrs: %w", err)
 	}
 
+	// Build the reverse map from the migrated address map.
+	robustAddrMapRoot, err := m.buildRobustAddrs(ctx, store, addrMapRoot)
+	if err != nil {
+		return nil, xerrors.Errorf("build robust addrs: %w", err)
+	}
+
+	tombstonesRoot, err := adt2.MakeEmptyMap(adt2.WrapStore(ctx, store)).Root()
+	if err != nil {
+		return nil, xerrors.Errorf("tombstones: %w", err)
+	}
+
 	outState := init2.State{
-		AddressMap:  addrMapRoot,
-		NextID:      inState.NextID,
-		NetworkName: inState.NetworkName,
+		AddressMap:       addrMapRoot,
+		NextID:           inState.NextID,
+		NetworkName:      inState.NetworkName,
+		RobustAddressMap: robustAddrMapRoot,
+		Tombstones:       tombstonesRoot,
+		ExecAllowlist:    init2.DefaultExecAllowlist(),
 	}
 	newHead, err := store.Put(ctx, &outState)
 	return &StateMigrationResult{
@@ -43,3 +70,24 @@ func (m *initMigrator) migrateAddrs(ctx context.Context, store cbor.IpldStore, r
 	// The HAMT has changed, but the value type (Address) is identical.
 	return migrateHAMTRaw(ctx, store, root)
 }
+
+func (m *initMigrator) buildRobustAddrs(ctx context.Context, store cbor.IpldStore, addrMapRoot cid.Cid) (cid.Cid, error) {
+	adtStore := adt2.WrapStore(ctx, store)
+	addrs, err := adt2.AsMap(adtStore, addrMapRoot)
+	if err != nil {
+		return cid.Undef, err
+	}
+	robustAddrs := adt2.MakeEmptyMap(adtStore)
+
+	var actorID cbg.CborInt
+	if err = addrs.ForEach(&actorID, func(key string) error {
+		robust, err := addr.NewFromBytes([]byte(key))
+		if err != nil {
+			return err
+		}
+		return robustAddrs.Put(abi.UIntKey(uint64(actorID)), &robust)
+	}); err != nil {
+		return cid.Undef, err
+	}
+	return robustAddrs.Root()
+}
@@ -3,18 +3,29 @@ package migration
 import (
 	"context"
 
+	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
+	builtin0 "github.com/filecoin-project/specs-actors/actors/builtin"
 	market0 "github.com/filecoin-project/specs-actors/actors/builtin/market"
 	cid "github.com/ipfs/go-cid"
 	cbor "github.com/ipfs/go-ipld-cbor"
 	"golang.org/x/xerrors"
 
+	builtin2 "github.com/filecoin-project/specs-actors/v2/actors/builtin"
 	market2 "github.com/filecoin-project/specs-actors/v2/actors/builtin/market"
+	adt2 "github.com/filecoin-project/specs-actors/v2/actors/util/adt"
 )
 
 type marketMigrator struct {
 }
 
+func init() {
+	RegisterActorMigration(builtin0.StorageMarketActorCodeID, ActorsVersion0, ActorMigration{
+		OutCodeCID:     builtin2.StorageMarketActorCodeID,
+		StateMigration: marketMigrator{},
+	})
+}
+
 func (m marketMigrator) MigrateState(ctx context.Context, store cbor.IpldStore, head cid.Cid, _ MigrationInfo) (*StateMigrationResult, error) {
 	var inState market0.State
 	if err := store.Get(ctx, head, &inState); err != nil {
@@ -51,6 +62,11 @@ func (m marketMigrator) MigrateState(ctx context.Context, store cbor.IpldStore,
 		return nil, xerrors.Errorf("deal ops by priorEpoch: %w", err)
 	}
 
+	dealsByProviderRoot, err := m.buildDealsByProvider(ctx, store, proposalsRoot)
+	if err != nil {
+		return nil, xerrors.Errorf("deals by provider: %w", err)
+	}
+
 	outState := market2.State{
 		Proposals:                     proposalsRoot,
 		States:                        statesRoot,
@@ -63,6 +79,7 @@ func (m marketMigrator) MigrateState(ctx context.Context, store cbor.IpldStore,
 		TotalClientLockedCollateral:   inState.TotalClientLockedCollateral,
 		TotalProviderLockedCollateral: inState.TotalProviderLockedCollateral,
 		TotalClientStorageFee:         inState.TotalClientStorageFee,
+		DealsByProvider:               dealsByProviderRoot,
 	}
 	newHead, err := store.Put(ctx, &outState)
 	return &StateMigrationResult{
@@ -100,3 +117,20 @@ func (m *marketMigrator) migrateDealOps(ctx context.Context, store cbor.IpldStor
 	// The HAMT has changed, at each level, but the final value type (abi.DealID) is identical.
 	return migrateHAMTHAMTRaw(ctx, store, root)
 }
+
+func (m *marketMigrator) buildDealsByProvider(ctx context.Context, store cbor.IpldStore, proposalsRoot cid.Cid) (cid.Cid, error) {
+	// The index is new in v2, so is built from the (migrated) proposals.
+	adtStore := adt2.WrapStore(ctx, store)
+	proposals, err := market2.AsDealProposalArray(adtStore, proposalsRoot)
+	if err != nil {
+		return cid.Undef, err
+	}
+	index := market2.MakeEmptyProviderDealIndex(adtStore)
+	var proposal market2.DealProposal
+	if err = proposals.ForEach(&proposal, func(dealID int64) error {
+		return index.Put(proposal.Provider, abi.DealID(dealID))
+	}); err != nil {
+		return cid.Undef, err
+	}
+	return index.Root()
+}
@@ -0,0 +1,90 @@
+package migration
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	address "github.com/filecoin-project/go-address"
+	"github.com/ipfs/go-cid"
+	cbor "github.com/ipfs/go-ipld-cbor"
+	cbg "github.com/whyrusleeping/cbor-gen"
+
+	acbor "github.com/filecoin-project/specs-actors/v2/actors/util/cbor"
+)
+
+// Receives reports of a migration's progress, so that node operators get feedback during a long migration.
+// Methods are called concurrently from the migration's workers, so implementations must be safe for concurrent use,
+// and should return quickly.
+type MigrationMetrics interface {
+	// Reports the migration of an actor's state by a worker, and the time it took.
+	ActorMigrated(addr address.Address, codeIn cid.Cid, duration time.Duration)
+	// Reports totals periodically while actors are migrated, and once when the migration completes.
+	Progress(progress MigrationProgress)
+}
+
+type MigrationProgress struct {
+	ActorsProcessed int64         // Actors whose migration is complete, or deferred to the end
+	ActorsQueued    int64         // Actors read from the state tree and not yet processed by a worker
+	ActorsShared    int64         // Processed actors whose state head had already been migrated for another actor
+	BytesWritten    int64         // Bytes of blocks written to the store
+	Elapsed         time.Duration // Time since the migration started
+	Done            bool          // Whether the migration is complete
+}
+
+// Counts a migration's progress for reporting to metrics.
+type progressCounter struct {
+	start     time.Time
+	read      int64
+	processed int64
+	shared    int64
+	written   int64
+}
+
+func (c *progressCounter) progress(done bool) MigrationProgress {
+	read := atomic.LoadInt64(&c.read)
+	processed := atomic.LoadInt64(&c.processed)
+	return MigrationProgress{
+		ActorsProcessed: processed,
+		ActorsQueued:    read - processed,
+		ActorsShared:    atomic.LoadInt64(&c.shared),
+		BytesWritten:    atomic.LoadInt64(&c.written),
+		Elapsed:         time.Since(c.start),
+		Done:            done,
+	}
+}
+
+// Reports progress every period until the context is done or the stop channel is closed.
+func (c *progressCounter) report(ctx context.Context, metrics MigrationMetrics, period time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			metrics.Progress(c.progress(false))
+		case <-stop:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// A store counting the bytes of the blocks written to it.
+type countingStore struct {
+	cbor.IpldStore
+	written *int64
+}
+
+func (s countingStore) Put(ctx context.Context, v interface{}) (cid.Cid, error) {
+	m, ok := v.(cbg.CBORMarshaler)
+	if !ok {
+		return s.IpldStore.Put(ctx, v)
+	}
+	raw, err := acbor.Marshal(m)
+	if err != nil {
+		return cid.Undef, err
+	}
+	atomic.AddInt64(s.written, int64(len(raw)))
+	return s.IpldStore.Put(ctx, &cbg.Deferred{Raw: raw})
+}
@@ -5,12 +5,14 @@ import (
 
 	"github.com/filecoin-project/go-bitfield"
 	"github.com/filecoin-project/go-state-types/big"
+	builtin0 "github.com/filecoin-project/specs-actors/actors/builtin"
 	miner0 "github.com/filecoin-project/specs-actors/actors/builtin/miner"
 	adt0 "github.com/filecoin-project/specs-actors/actors/util/adt"
 	cid "github.com/ipfs/go-cid"
 	cbor "github.com/ipfs/go-ipld-cbor"
 	"golang.org/x/xerrors"
 
+	builtin2 "github.com/filecoin-project/specs-actors/v2/actors/builtin"
 	miner2 "github.com/filecoin-project/specs-actors/v2/actors/builtin/miner"
 	adt2 "github.com/filecoin-project/specs-actors/v2/actors/util/adt"
 )
@@ -18,6 +20,13 @@ import (
 type minerMigrator struct {
 }
 
+func init() {
+	RegisterActorMigration(builtin0.StorageMinerActorCodeID, ActorsVersion0, ActorMigration{
+		OutCodeCID:     builtin2.StorageMinerActorCodeID,
+		StateMigration: minerMigrator{},
+	})
+}
+
 func (m minerMigrator) MigrateState(ctx context.Context, store cbor.IpldStore, head cid.Cid, info MigrationInfo) (*StateMigrationResult, error) {
 	// first correct issues with miners due problems in old code
 	result, err := m.CorrectState(ctx, store, head, info.priorEpoch, info.address)
@@ -5,17 +5,28 @@ import (
 
 	addr "github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/big"
+	builtin0 "github.com/filecoin-project/specs-actors/actors/builtin"
 	multisig0 "github.com/filecoin-project/specs-actors/actors/builtin/multisig"
+	adt0 "github.com/filecoin-project/specs-actors/actors/util/adt"
 	cid "github.com/ipfs/go-cid"
 	cbor "github.com/ipfs/go-ipld-cbor"
 	"golang.org/x/xerrors"
 
+	builtin2 "github.com/filecoin-project/specs-actors/v2/actors/builtin"
 	multisig2 "github.com/filecoin-project/specs-actors/v2/actors/builtin/multisig"
+	adt2 "github.com/filecoin-project/specs-actors/v2/actors/util/adt"
 )
 
 type multisigMigrator struct {
 }
 
+func init() {
+	RegisterActorMigration(builtin0.MultisigActorCodeID, ActorsVersion0, ActorMigration{
+		OutCodeCID:     builtin2.MultisigActorCodeID,
+		StateMigration: multisigMigrator{},
+	})
+}
+
 func (m multisigMigrator) MigrateState(ctx context.Context, store cbor.IpldStore, head cid.Cid, _ MigrationInfo) (*StateMigrationResult, error) {
 	var inState multisig0.State
 	if err := store.Get(ctx, head, &inState); err != nil {
@@ -52,8 +63,27 @@ func (m multisigMigrator) MigrateState(ctx context.Context, store cbor.IpldStore
 }
 
 func (m *multisigMigrator) migratePending(ctx context.Context, store cbor.IpldStore, root cid.Cid) (cid.Cid, error) {
-	// The HAMT has changed, but the value type is identical.
-	var _ = multisig2.Transaction(multisig0.Transaction{})
+	// The HAMT has changed, and the value type has gained an expiration.
+	// Existing transactions do not expire.
+	inMap, err := adt0.AsMap(adt0.WrapStore(ctx, store), root)
+	if err != nil {
+		return cid.Undef, err
+	}
+	outMap := adt2.MakeEmptyMap(adt2.WrapStore(ctx, store))
 
-	return migrateHAMTRaw(ctx, store, root)
+	var inTxn multisig0.Transaction
+	if err = inMap.ForEach(&inTxn, func(key string) error {
+		outTxn := multisig2.Transaction{
+			To:         inTxn.To,
+			Value:      inTxn.Value,
+			Method:     inTxn.Method,
+			Params:     inTxn.Params,
+			Approved:   inTxn.Approved,
+			Expiration: 0,
+		}
+		return outMap.Put(multisig2.StringKey(key), &outTxn)
+	}); err != nil {
+		return cid.Undef, err
+	}
+	return outMap.Root()
 }
@@ -0,0 +1,40 @@
+package migration
+
+import (
+	"context"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	cbor "github.com/ipfs/go-ipld-cbor"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/paramreg"
+	"github.com/filecoin-project/specs-actors/v2/actors/states"
+	adt2 "github.com/filecoin-project/specs-actors/v2/actors/util/adt"
+)
+
+// Creates the parameter registry actor, which did not exist prior to this migration, recording the policy parameters.
+func createParameterRegistry(ctx context.Context, store cbor.IpldStore, actorsOut *states.Tree, priorEpoch abi.ChainEpoch) error {
+	adtStore := adt2.WrapStore(ctx, store)
+	emptyArray, err := adt2.MakeEmptyArray(adtStore).Root()
+	if err != nil {
+		return err
+	}
+	st := paramreg.ConstructState(emptyArray)
+	for _, p := range paramreg.PolicyParameters() {
+		if err := st.SetParameter(adtStore, p.Name, p.Value, priorEpoch, NetworkVersion); err != nil {
+			return xerrors.Errorf("failed to set parameter %s: %w", p.Name, err)
+		}
+	}
+	head, err := store.Put(ctx, st)
+	if err != nil {
+		return err
+	}
+	return actorsOut.SetActor(builtin.ParameterRegistryActorAddr, &states.Actor{
+		Code:       builtin.ParameterRegistryActorCodeID,
+		Head:       head,
+		CallSeqNum: 0,
+		Balance:    big.Zero(),
+	})
+}
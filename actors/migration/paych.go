@@ -5,17 +5,26 @@ import (
 
 	addr "github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/big"
+	builtin0 "github.com/filecoin-project/specs-actors/actors/builtin"
 	paych0 "github.com/filecoin-project/specs-actors/actors/builtin/paych"
 	cid "github.com/ipfs/go-cid"
 	cbor "github.com/ipfs/go-ipld-cbor"
 	"golang.org/x/xerrors"
 
+	builtin2 "github.com/filecoin-project/specs-actors/v2/actors/builtin"
 	paych2 "github.com/filecoin-project/specs-actors/v2/actors/builtin/paych"
 )
 
 type paychMigrator struct {
 }
 
+func init() {
+	RegisterActorMigration(builtin0.PaymentChannelActorCodeID, ActorsVersion0, ActorMigration{
+		OutCodeCID:     builtin2.PaymentChannelActorCodeID,
+		StateMigration: paychMigrator{},
+	})
+}
+
 func (m paychMigrator) MigrateState(ctx context.Context, store cbor.IpldStore, head cid.Cid, _ MigrationInfo) (*StateMigrationResult, error) {
 	var inState paych0.State
 	if err := store.Get(ctx, head, &inState); err != nil {
@@ -6,6 +6,7 @@ import (
 	address "github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
+	builtin0 "github.com/filecoin-project/specs-actors/actors/builtin"
 	miner0 "github.com/filecoin-project/specs-actors/actors/builtin/miner"
 	power0 "github.com/filecoin-project/specs-actors/actors/builtin/power"
 	states0 "github.com/filecoin-project/specs-actors/actors/states"
@@ -14,6 +15,7 @@ import (
 	cbor "github.com/ipfs/go-ipld-cbor"
 	"golang.org/x/xerrors"
 
+	builtin2 "github.com/filecoin-project/specs-actors/v2/actors/builtin"
 	power2 "github.com/filecoin-project/specs-actors/v2/actors/builtin/power"
 	adt2 "github.com/filecoin-project/specs-actors/v2/actors/util/adt"
 	smoothing2 "github.com/filecoin-project/specs-actors/v2/actors/util/smoothing"
@@ -24,6 +26,13 @@ type powerMigrator struct {
 	powerUpdates *PowerUpdates
 }
 
+func init() {
+	RegisterActorMigration(builtin0.StoragePowerActorCodeID, ActorsVersion0, ActorMigration{
+		OutCodeCID:     builtin2.StoragePowerActorCodeID,
+		StateMigration: powerMigrator{},
+	})
+}
+
 func (m powerMigrator) MigrateState(ctx context.Context, store cbor.IpldStore, head cid.Cid, info MigrationInfo) (*StateMigrationResult, error) {
 	var inState power0.State
 	if err := store.Get(ctx, head, &inState); err != nil {
@@ -60,12 +69,15 @@ func (m powerMigrator) MigrateState(ctx context.Context, store cbor.IpldStore, h
 		ThisEpochQualityAdjPower:  inState.ThisEpochQualityAdjPower,
 		ThisEpochPledgeCollateral: inState.ThisEpochPledgeCollateral,
 		ThisEpochQAPowerSmoothed:  smoothing2.FilterEstimate(*inState.ThisEpochQAPowerSmoothed),
-		MinerCount:                inState.MinerCount,
-		MinerAboveMinPowerCount:   inState.MinerAboveMinPowerCount,
-		CronEventQueue:            cronEventsRoot,
-		FirstCronEpoch:            inState.FirstCronEpoch,
-		Claims:                    claimsRoot,
-		ProofValidationBatch:      nil, // Set nil at the end of every epoch in cron handler
+		// Estimates not tracked by v0 start from the current values, with no trend.
+		ThisEpochRawBytePowerSmoothed:     smoothing2.NewEstimate(inState.ThisEpochRawBytePower, big.Zero()),
+		ThisEpochPledgeCollateralSmoothed: smoothing2.NewEstimate(inState.ThisEpochPledgeCollateral, big.Zero()),
+		MinerCount:                        inState.MinerCount,
+		MinerAboveMinPowerCount:           inState.MinerAboveMinPowerCount,
+		CronEventQueue:                    cronEventsRoot,
+		FirstCronEpoch:                    inState.FirstCronEpoch,
+		Claims:                            claimsRoot,
+		ProofValidationBatch:              nil, // Set nil at the end of every epoch in cron handler
 	}
 
 	newHead, err := store.Put(ctx, &outState)
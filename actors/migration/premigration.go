@@ -0,0 +1,78 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/ipfs/go-cid"
+	cbor "github.com/ipfs/go-ipld-cbor"
+	"golang.org/x/xerrors"
+)
+
+// A pass over the state tree at an epoch before the upgrade, recording results in the migration cache so that
+// expensive work need not all be done at the upgrade epoch.
+// The state may change between a pre-migration and the upgrade, so results must be recorded such that the
+// migration uses them only for state that is unchanged, e.g. keyed by the state's head. A pre-migration does not
+// change the state tree, though it may write new blocks to the store for the migration to use.
+type PreMigration struct {
+	Name string
+	// The pre-migration runs at epochs within StartWithin epochs before the upgrade epoch, but not within
+	// DontStartWithin, after which it may not complete before the upgrade.
+	StartWithin     abi.ChainEpoch
+	DontStartWithin abi.ChainEpoch
+	PreMigrate      func(ctx context.Context, store cbor.IpldStore, stateRoot cid.Cid, epoch abi.ChainEpoch, cfg Config) error
+}
+
+var preMigrations []PreMigration
+
+func init() {
+	// Migrates the state of every actor whose migration may be cached, so that at the upgrade only actors
+	// whose state has changed since are migrated again.
+	RegisterPreMigration(PreMigration{
+		Name:            "actor states",
+		StartWithin:     120,
+		DontStartWithin: 15,
+		PreMigrate: func(ctx context.Context, store cbor.IpldStore, stateRoot cid.Cid, epoch abi.ChainEpoch, cfg Config) error {
+			_, err := MigrateStateTree(ctx, store, stateRoot, epoch, cfg)
+			return err
+		},
+	})
+}
+
+// Registers a pre-migration, for RunPreMigrations to run.
+// Panics if a pre-migration is already registered with the name.
+func RegisterPreMigration(pm PreMigration) {
+	for _, registered := range preMigrations {
+		if registered.Name == pm.Name {
+			panic(fmt.Sprintf("pre-migration %s already registered", pm.Name))
+		}
+	}
+	preMigrations = append(preMigrations, pm)
+}
+
+// Returns the registered pre-migrations, in order of registration.
+func PreMigrations() []PreMigration {
+	return append([]PreMigration(nil), preMigrations...)
+}
+
+// Runs the registered pre-migrations which are due at an epoch before the upgrade, in order of registration,
+// recording their results in the config's cache. Returns the names of the pre-migrations run.
+// The final migration must be given the same cache.
+func RunPreMigrations(ctx context.Context, store cbor.IpldStore, stateRoot cid.Cid, epoch, upgradeEpoch abi.ChainEpoch, cfg Config) ([]string, error) {
+	if cfg.Cache == nil {
+		return nil, xerrors.Errorf("pre-migrations require a migration cache")
+	}
+	var run []string
+	for _, pm := range preMigrations {
+		untilUpgrade := upgradeEpoch - epoch
+		if untilUpgrade > pm.StartWithin || untilUpgrade <= pm.DontStartWithin {
+			continue
+		}
+		if err := pm.PreMigrate(ctx, store, stateRoot, epoch, cfg); err != nil {
+			return run, xerrors.Errorf("pre-migration %s failed: %w", pm.Name, err)
+		}
+		run = append(run, pm.Name)
+	}
+	return run, nil
+}
@@ -0,0 +1,46 @@
+package migration
+
+import (
+	"fmt"
+
+	"github.com/ipfs/go-cid"
+)
+
+// A version of the builtin actors, which determines their code CIDs and state schemas.
+type ActorsVersion int
+
+const (
+	ActorsVersion0 ActorsVersion = 0
+	ActorsVersion2 ActorsVersion = 2
+)
+
+// Identifies the actors to which a registered migration applies: those with a code CID of an actors version.
+type MigrationKey struct {
+	CodeIn      cid.Cid
+	FromVersion ActorsVersion
+}
+
+var registry = map[MigrationKey]ActorMigration{}
+
+// Registers the migration of actors with a code from an actors version, for MigrateStateTree to perform.
+// Each migrator registers itself from its own file, so that adding an actor to a migration is one entry.
+// Panics if a migration is already registered for the code and version.
+func RegisterActorMigration(codeIn cid.Cid, from ActorsVersion, m ActorMigration) {
+	key := MigrationKey{CodeIn: codeIn, FromVersion: from}
+	if _, found := registry[key]; found {
+		panic(fmt.Sprintf("migration already registered for code %v from actors version %d", codeIn, from))
+	}
+	registry[key] = m
+}
+
+// Returns the migrations registered from an actors version, keyed by the code CID of the actors they migrate.
+// The map is a copy, which the caller may modify.
+func ActorMigrations(from ActorsVersion) map[cid.Cid]ActorMigration {
+	migrations := map[cid.Cid]ActorMigration{}
+	for key, m := range registry { //nolint:nomaprange
+		if key.FromVersion == from {
+			migrations[key.CodeIn] = m
+		}
+	}
+	return migrations
+}
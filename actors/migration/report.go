@@ -0,0 +1,326 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+
+	address "github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	block "github.com/ipfs/go-block-format"
+	"github.com/ipfs/go-cid"
+	cbor "github.com/ipfs/go-ipld-cbor"
+	cbg "github.com/whyrusleeping/cbor-gen"
+	"golang.org/x/xerrors"
+
+	builtin0 "github.com/filecoin-project/specs-actors/actors/builtin"
+	account0 "github.com/filecoin-project/specs-actors/actors/builtin/account"
+	cron0 "github.com/filecoin-project/specs-actors/actors/builtin/cron"
+	init0 "github.com/filecoin-project/specs-actors/actors/builtin/init"
+	market0 "github.com/filecoin-project/specs-actors/actors/builtin/market"
+	miner0 "github.com/filecoin-project/specs-actors/actors/builtin/miner"
+	multisig0 "github.com/filecoin-project/specs-actors/actors/builtin/multisig"
+	paych0 "github.com/filecoin-project/specs-actors/actors/builtin/paych"
+	power0 "github.com/filecoin-project/specs-actors/actors/builtin/power"
+	reward0 "github.com/filecoin-project/specs-actors/actors/builtin/reward"
+	system0 "github.com/filecoin-project/specs-actors/actors/builtin/system"
+	verifreg0 "github.com/filecoin-project/specs-actors/actors/builtin/verifreg"
+	states0 "github.com/filecoin-project/specs-actors/actors/states"
+	adt0 "github.com/filecoin-project/specs-actors/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/exported"
+	"github.com/filecoin-project/specs-actors/v2/actors/states"
+	adt2 "github.com/filecoin-project/specs-actors/v2/actors/util/adt"
+)
+
+// A report of a migration's effect on each actor, for upgrade reviewers to validate a migration against a
+// snapshot of chain state.
+type MigrationReport struct {
+	StateRootIn  cid.Cid
+	StateRootOut cid.Cid
+	Actors       []ActorReport // Every actor in either state tree, in the output tree's order then removed actors
+}
+
+// The effect of a migration on one actor.
+type ActorReport struct {
+	Address      address.Address
+	CodeIn       cid.Cid // Undefined if the migration created the actor
+	CodeOut      cid.Cid // Undefined if the migration removed the actor
+	BalanceIn    abi.TokenAmount
+	BalanceOut   abi.TokenAmount
+	BalanceDelta abi.TokenAmount
+	Fields       []FieldChange    // Top-level state fields whose values changed, in declaration order
+	Collections  []CollectionSize // Sizes of the HAMTs and AMTs rooted in top-level state fields
+}
+
+// A change to the value of a state field. A field absent from one version of the state has an empty value.
+type FieldChange struct {
+	Name   string
+	Before string
+	After  string
+}
+
+// The number of entries in a collection rooted in a state field, before and after migration.
+// The size is -1 where the field is absent or does not root a collection.
+type CollectionSize struct {
+	Name   string
+	Before int64
+	After  int64
+}
+
+// Runs a migration without committing it, returning a report of its effect on each actor.
+// Migrated state is held in memory and discarded, so nothing is written to the store. The config's cache is not
+// used, since it would refer to discarded state.
+// Counting the entries of every collection is expensive for a large state tree; this is a tool for review, not for
+// use at the upgrade epoch.
+func DryRunMigration(ctx context.Context, store cbor.IpldStore, stateRootIn cid.Cid, priorEpoch abi.ChainEpoch, cfg Config) (*MigrationReport, error) {
+	buffered := cbor.NewCborStore(&bufferedBlocks{ctx: ctx, base: store, written: map[cid.Cid]block.Block{}})
+	cfg.Cache = nil
+	stateRootOut, err := MigrateStateTree(ctx, buffered, stateRootIn, priorEpoch, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return ReportMigration(ctx, buffered, stateRootIn, stateRootOut)
+}
+
+// Reports the effect on each actor of a migration from a v0 state tree to a v2 one, both held in the store.
+func ReportMigration(ctx context.Context, store cbor.IpldStore, stateRootIn, stateRootOut cid.Cid) (*MigrationReport, error) {
+	actorsIn, err := states0.LoadTree(adt0.WrapStore(ctx, store), stateRootIn)
+	if err != nil {
+		return nil, err
+	}
+	actorsOut, err := states.LoadTree(adt2.WrapStore(ctx, store), stateRootOut)
+	if err != nil {
+		return nil, err
+	}
+	statesOut := map[cid.Cid]func() interface{}{}
+	for _, a := range exported.BuiltinActors() {
+		a := a
+		statesOut[a.Code()] = func() interface{} { return a.State() }
+	}
+
+	report := &MigrationReport{StateRootIn: stateRootIn, StateRootOut: stateRootOut}
+	if err := actorsOut.ForEach(func(addr address.Address, actorOut *states.Actor) error {
+		actorIn, found, err := actorsIn.GetActor(addr)
+		if err != nil {
+			return err
+		}
+		if !found {
+			actorIn = nil
+		}
+		actorReport, err := reportActor(ctx, store, addr, actorIn, actorOut, statesOut)
+		if err != nil {
+			return xerrors.Errorf("failed to report actor %s: %w", addr, err)
+		}
+		report.Actors = append(report.Actors, *actorReport)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := actorsIn.ForEach(func(addr address.Address, actorIn *states0.Actor) error {
+		if _, found, err := actorsOut.GetActor(addr); err != nil || found {
+			return err
+		}
+		actorReport, err := reportActor(ctx, store, addr, actorIn, nil, statesOut)
+		if err != nil {
+			return xerrors.Errorf("failed to report actor %s: %w", addr, err)
+		}
+		report.Actors = append(report.Actors, *actorReport)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// Constructors for the v0 state of each actor code.
+var statesIn = map[cid.Cid]func() interface{}{
+	builtin0.AccountActorCodeID:          func() interface{} { return new(account0.State) },
+	builtin0.CronActorCodeID:             func() interface{} { return new(cron0.State) },
+	builtin0.InitActorCodeID:             func() interface{} { return new(init0.State) },
+	builtin0.StorageMarketActorCodeID:    func() interface{} { return new(market0.State) },
+	builtin0.StorageMinerActorCodeID:     func() interface{} { return new(miner0.State) },
+	builtin0.MultisigActorCodeID:         func() interface{} { return new(multisig0.State) },
+	builtin0.PaymentChannelActorCodeID:   func() interface{} { return new(paych0.State) },
+	builtin0.StoragePowerActorCodeID:     func() interface{} { return new(power0.State) },
+	builtin0.RewardActorCodeID:           func() interface{} { return new(reward0.State) },
+	builtin0.SystemActorCodeID:           func() interface{} { return new(system0.State) },
+	builtin0.VerifiedRegistryActorCodeID: func() interface{} { return new(verifreg0.State) },
+}
+
+// Reports the effect of a migration on an actor, either side of which may be nil.
+func reportActor(ctx context.Context, store cbor.IpldStore, addr address.Address, actorIn, actorOut *states.Actor,
+	statesOut map[cid.Cid]func() interface{}) (*ActorReport, error) {
+	report := &ActorReport{Address: addr, BalanceIn: big.Zero(), BalanceOut: big.Zero()}
+	var fieldsIn, fieldsOut []stateField
+	var err error
+	if actorIn != nil {
+		report.CodeIn = actorIn.Code
+		report.BalanceIn = actorIn.Balance
+		if fieldsIn, err = loadStateFields(ctx, store, actorIn, statesIn); err != nil {
+			return nil, err
+		}
+	}
+	if actorOut != nil {
+		report.CodeOut = actorOut.Code
+		report.BalanceOut = actorOut.Balance
+		if fieldsOut, err = loadStateFields(ctx, store, actorOut, statesOut); err != nil {
+			return nil, err
+		}
+	}
+	report.BalanceDelta = big.Sub(report.BalanceOut, report.BalanceIn)
+
+	// Fields are matched by name, those of the input state first.
+	names := []string{}
+	before := map[string]stateField{}
+	after := map[string]stateField{}
+	for _, f := range fieldsIn {
+		names = append(names, f.name)
+		before[f.name] = f
+	}
+	for _, f := range fieldsOut {
+		if _, found := before[f.name]; !found {
+			names = append(names, f.name)
+		}
+		after[f.name] = f
+	}
+
+	adtIn := adt0.WrapStore(ctx, store)
+	adtOut := adt2.WrapStore(ctx, store)
+	for _, name := range names {
+		fIn, inFound := before[name]
+		fOut, outFound := after[name]
+		change := FieldChange{Name: name}
+		if inFound {
+			change.Before = formatField(fIn.value)
+		}
+		if outFound {
+			change.After = formatField(fOut.value)
+		}
+		if change.Before != change.After || inFound != outFound {
+			report.Fields = append(report.Fields, change)
+		}
+
+		size := CollectionSize{Name: name, Before: -1, After: -1}
+		if inFound {
+			if root, ok := fIn.value.Interface().(cid.Cid); ok {
+				size.Before = collectionSize(countCollection0(adtIn, root))
+			}
+		}
+		if outFound {
+			if root, ok := fOut.value.Interface().(cid.Cid); ok {
+				size.After = collectionSize(countCollection2(adtOut, root))
+			}
+		}
+		if size.Before >= 0 || size.After >= 0 {
+			report.Collections = append(report.Collections, size)
+		}
+	}
+	return report, nil
+}
+
+type stateField struct {
+	name  string
+	value reflect.Value
+}
+
+// Loads an actor's state and returns its top-level fields.
+func loadStateFields(ctx context.Context, store cbor.IpldStore, actor *states.Actor, constructors map[cid.Cid]func() interface{}) ([]stateField, error) {
+	construct, found := constructors[actor.Code]
+	if !found {
+		return nil, xerrors.Errorf("no state type for actor code %s", actor.Code)
+	}
+	st := construct()
+	if err := store.Get(ctx, actor.Head, st); err != nil {
+		return nil, xerrors.Errorf("failed to load state: %w", err)
+	}
+	v := reflect.ValueOf(st).Elem()
+	fields := make([]stateField, v.NumField())
+	for i := range fields {
+		fields[i] = stateField{name: v.Type().Field(i).Name, value: v.Field(i)}
+	}
+	return fields, nil
+}
+
+// Formats a field's value, dereferencing a pointer so that a value compares equal to a pointer to it.
+func formatField(v reflect.Value) string {
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	return fmt.Sprintf("%v", v.Interface())
+}
+
+// Returns the number of entries counted in a collection, or -1 if the root could not be loaded as a collection.
+func collectionSize(n uint64, err error) int64 {
+	if err != nil {
+		return -1
+	}
+	return int64(n)
+}
+
+// Counts the entries of a v0 AMT or HAMT.
+func countCollection0(store adt0.Store, root cid.Cid) (uint64, error) {
+	if arr, err := adt0.AsArray(store, root); err == nil {
+		return arr.Length(), nil
+	}
+	m, err := adt0.AsMap(store, root)
+	if err != nil {
+		return 0, err
+	}
+	var n uint64
+	var val cbg.Deferred
+	err = m.ForEach(&val, func(string) error {
+		n++
+		return nil
+	})
+	return n, err
+}
+
+// Counts the entries of a v2 AMT or HAMT.
+func countCollection2(store adt2.Store, root cid.Cid) (uint64, error) {
+	if arr, err := adt2.AsArray(store, root); err == nil {
+		return arr.Length(), nil
+	}
+	m, err := adt2.AsMap(store, root)
+	if err != nil {
+		return 0, err
+	}
+	var n uint64
+	var val cbg.Deferred
+	err = m.ForEach(&val, func(string) error {
+		n++
+		return nil
+	})
+	return n, err
+}
+
+// A blockstore holding written blocks in memory, over a store from which blocks are only read.
+type bufferedBlocks struct {
+	ctx     context.Context
+	base    cbor.IpldStore
+	mu      sync.Mutex
+	written map[cid.Cid]block.Block
+}
+
+func (b *bufferedBlocks) Get(c cid.Cid) (block.Block, error) {
+	b.mu.Lock()
+	blk, found := b.written[c]
+	b.mu.Unlock()
+	if found {
+		return blk, nil
+	}
+	var raw cbg.Deferred
+	if err := b.base.Get(b.ctx, c, &raw); err != nil {
+		return nil, err
+	}
+	return block.NewBlockWithCid(raw.Raw, c)
+}
+
+func (b *bufferedBlocks) Put(blk block.Block) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.written[blk.Cid()] = blk
+	return nil
+}
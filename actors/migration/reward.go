@@ -7,6 +7,7 @@ import (
 
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
+	builtin0 "github.com/filecoin-project/specs-actors/actors/builtin"
 	reward0 "github.com/filecoin-project/specs-actors/actors/builtin/reward"
 	cid "github.com/ipfs/go-cid"
 	cbor "github.com/ipfs/go-ipld-cbor"
@@ -40,6 +41,13 @@ type rewardMigrator struct {
 	actorsOut *states.Tree
 }
 
+func init() {
+	RegisterActorMigration(builtin0.RewardActorCodeID, ActorsVersion0, ActorMigration{
+		OutCodeCID:     builtin2.RewardActorCodeID,
+		StateMigration: rewardMigrator{},
+	})
+}
+
 func (m rewardMigrator) MigrateState(ctx context.Context, store cbor.IpldStore, head cid.Cid, migInfo MigrationInfo) (*StateMigrationResult, error) {
 	var inState reward0.State
 	if err := store.Get(ctx, head, &inState); err != nil {
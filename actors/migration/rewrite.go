@@ -0,0 +1,116 @@
+package migration
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+
+	hamt2 "github.com/filecoin-project/go-hamt-ipld/v2"
+	cid "github.com/ipfs/go-cid"
+	cbor "github.com/ipfs/go-ipld-cbor"
+	"golang.org/x/xerrors"
+)
+
+// Number of entries written to a rewritten HAMT between flushes, after which its nodes are released from memory.
+const rewriteFlushInterval = 1 << 14
+
+// Rewrites a HAMT with new options, e.g. a new bitwidth, without re-encoding keys or values.
+// Any migration changing a HAMT's parameters may use this. The input HAMT is walked one path at a time, and the
+// output flushed and reloaded periodically, so memory use is bounded however large the HAMT.
+// The input's options are not needed to walk it. The output is checked to hold exactly the input's entries.
+// Note the AMT's width is fixed by its implementation, so AMTs have no parameters to change.
+func RewriteHAMT(ctx context.Context, store cbor.IpldStore, root cid.Cid, opts ...hamt2.Option) (cid.Cid, error) {
+	out := hamt2.NewNode(store, opts...)
+	var written uint64
+	inDigest, err := walkHAMT(ctx, store, root, func(k, raw []byte) error {
+		if err := out.SetRaw(ctx, string(k), raw); err != nil {
+			return err
+		}
+		written++
+		if written%rewriteFlushInterval == 0 {
+			// Flush the output and reload it from its root, dropping the nodes it holds in memory.
+			c, err := flushHAMT(ctx, store, out)
+			if err != nil {
+				return err
+			}
+			if out, err = hamt2.LoadNode(ctx, store, c, opts...); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return cid.Undef, xerrors.Errorf("failed to rewrite HAMT %v: %w", root, err)
+	}
+	outRoot, err := flushHAMT(ctx, store, out)
+	if err != nil {
+		return cid.Undef, err
+	}
+
+	outDigest, err := walkHAMT(ctx, store, outRoot, func(_, _ []byte) error { return nil })
+	if err != nil {
+		return cid.Undef, xerrors.Errorf("failed to check rewritten HAMT %v: %w", outRoot, err)
+	}
+	if inDigest != outDigest {
+		return cid.Undef, xerrors.Errorf("rewritten HAMT %v has %d entries with digest %x, but HAMT %v has %d with digest %x",
+			outRoot, outDigest.count, outDigest.sum, root, inDigest.count, inDigest.sum)
+	}
+	return outRoot, nil
+}
+
+// A digest of a collection's entries independent of their order: the count and XOR of the hashes of the entries.
+type entriesDigest struct {
+	count uint64
+	sum   [sha256.Size]byte
+}
+
+func (d *entriesDigest) add(k, raw []byte) {
+	var buf bytes.Buffer
+	lengthBytes := make([]byte, binary.MaxVarintLen64)
+	buf.Write(lengthBytes[:binary.PutUvarint(lengthBytes, uint64(len(k)))])
+	buf.Write(k)
+	buf.Write(raw)
+	h := sha256.Sum256(buf.Bytes())
+	for i := range d.sum {
+		d.sum[i] ^= h[i]
+	}
+	d.count++
+}
+
+// Walks the entries of a HAMT depth first, loading only the nodes on the path to the current entry, and returns
+// a digest of the entries.
+func walkHAMT(ctx context.Context, store cbor.IpldStore, root cid.Cid, f func(k, raw []byte) error) (entriesDigest, error) {
+	var digest entriesDigest
+	var walk func(c cid.Cid) error
+	walk = func(c cid.Cid) error {
+		var node hamt2.Node
+		if err := store.Get(ctx, c, &node); err != nil {
+			return err
+		}
+		for _, p := range node.Pointers {
+			if p.Link.Defined() {
+				if err := walk(p.Link); err != nil {
+					return err
+				}
+				continue
+			}
+			for _, kv := range p.KVs {
+				digest.add(kv.Key, kv.Value.Raw)
+				if err := f(kv.Key, kv.Value.Raw); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	err := walk(root)
+	return digest, err
+}
+
+func flushHAMT(ctx context.Context, store cbor.IpldStore, node *hamt2.Node) (cid.Cid, error) {
+	if err := node.Flush(ctx); err != nil {
+		return cid.Undef, err
+	}
+	return store.Put(ctx, node)
+}
@@ -4,21 +4,40 @@ import (
 	"context"
 
 	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/network"
+	builtin0 "github.com/filecoin-project/specs-actors/actors/builtin"
 	system0 "github.com/filecoin-project/specs-actors/actors/builtin/system"
 	cid "github.com/ipfs/go-cid"
 	cbor "github.com/ipfs/go-ipld-cbor"
 
+	builtin2 "github.com/filecoin-project/specs-actors/v2/actors/builtin"
 	system2 "github.com/filecoin-project/specs-actors/v2/actors/builtin/system"
 )
 
+// The network version at which the state is migrated to actors v2.
+const NetworkVersion = network.Version4
+
 type systemMigrator struct {
+	networkName string // Read from the init actor, which held the network name in v0.
+}
+
+func init() {
+	RegisterActorMigration(builtin0.SystemActorCodeID, ActorsVersion0, ActorMigration{
+		OutCodeCID:     builtin2.SystemActorCodeID,
+		StateMigration: systemMigrator{},
+	})
 }
 
 func (m systemMigrator) MigrateState(ctx context.Context, store cbor.IpldStore, head cid.Cid, _ MigrationInfo) (*StateMigrationResult, error) {
-	// No change
-	var _ = system2.State(system0.State{})
+	var inState system0.State
+	if err := store.Get(ctx, head, &inState); err != nil {
+		return nil, err
+	}
+
+	outState := system2.ConstructState(m.networkName, NetworkVersion)
+	newHead, err := store.Put(ctx, outState)
 	return &StateMigrationResult{
-		NewHead:  head,
+		NewHead:  newHead,
 		Transfer: big.Zero(),
-	}, nil
+	}, err
 }
@@ -0,0 +1,121 @@
+package test_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	addr "github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/specs-actors/actors/builtin"
+	"github.com/filecoin-project/specs-actors/actors/builtin/multisig"
+	"github.com/filecoin-project/specs-actors/actors/states"
+	"github.com/filecoin-project/specs-actors/actors/util/adt"
+	cid "github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/migration"
+	states2 "github.com/filecoin-project/specs-actors/v2/actors/states"
+	ipld2 "github.com/filecoin-project/specs-actors/v2/support/ipld"
+)
+
+func TestMigrationMetrics(t *testing.T) {
+	ctx := context.Background()
+	store := ipld2.NewSyncADTStore(ctx)
+	actorsIn := newV0StateTree(ctx, t, store)
+	startRoot, err := actorsIn.Flush()
+	require.NoError(t, err)
+	actorCount := 0
+	require.NoError(t, actorsIn.ForEachKey(func(addr.Address) error {
+		actorCount++
+		return nil
+	}))
+
+	expectedRoot, err := migration.MigrateStateTree(ctx, store, startRoot, abi.ChainEpoch(0), migration.DefaultConfig())
+	require.NoError(t, err)
+
+	metrics := &recordingMetrics{migrated: map[addr.Address]cid.Cid{}}
+	cfg := migration.Config{MaxWorkers: 2, Metrics: metrics, ProgressPeriod: time.Millisecond}
+	root, err := migration.MigrateStateTree(ctx, store, startRoot, abi.ChainEpoch(0), cfg)
+	require.NoError(t, err)
+	assert.Equal(t, expectedRoot, root)
+
+	// Power, reward and verified registry actors are migrated at the end, not by workers.
+	assert.Len(t, metrics.migrated, actorCount-3)
+	require.NotEmpty(t, metrics.progress)
+	final := metrics.progress[len(metrics.progress)-1]
+	assert.True(t, final.Done)
+	assert.Equal(t, int64(actorCount), final.ActorsProcessed)
+	assert.Equal(t, int64(0), final.ActorsQueued)
+	assert.Greater(t, final.BytesWritten, int64(0))
+	for _, p := range metrics.progress[:len(metrics.progress)-1] {
+		assert.False(t, p.Done)
+	}
+}
+
+func TestSharedHeadsMigratedOnce(t *testing.T) {
+	ctx := context.Background()
+	store := ipld2.NewSyncADTStore(ctx)
+	actorsIn := newV0StateTree(ctx, t, store)
+
+	// Two multisigs with identical state share a head.
+	emptyMap, err := adt.MakeEmptyMap(store).Root()
+	require.NoError(t, err)
+	signer, err := addr.NewIDAddress(1001)
+	require.NoError(t, err)
+	msigHead, err := store.Put(ctx, &multisig.State{
+		Signers:               []addr.Address{signer},
+		NumApprovalsThreshold: 1,
+		InitialBalance:        big.Zero(),
+		PendingTxns:           emptyMap,
+	})
+	require.NoError(t, err)
+	var msigAddrs []addr.Address
+	for id := uint64(1002); id < 1004; id++ {
+		a, err := addr.NewIDAddress(id)
+		require.NoError(t, err)
+		require.NoError(t, actorsIn.SetActor(a, &states.Actor{Code: builtin.MultisigActorCodeID, Head: msigHead, Balance: big.Zero()}))
+		msigAddrs = append(msigAddrs, a)
+	}
+	startRoot, err := actorsIn.Flush()
+	require.NoError(t, err)
+
+	metrics := &recordingMetrics{migrated: map[addr.Address]cid.Cid{}}
+	root, err := migration.MigrateStateTree(ctx, store, startRoot, abi.ChainEpoch(0), migration.Config{MaxWorkers: 1, Metrics: metrics})
+	require.NoError(t, err)
+	final := metrics.progress[len(metrics.progress)-1]
+	assert.Equal(t, int64(1), final.ActorsShared)
+
+	// Both actors have the same migrated head, whichever migrated it.
+	actorsOut, err := states2.LoadTree(store, root)
+	require.NoError(t, err)
+	var heads []cid.Cid
+	for _, a := range msigAddrs {
+		actor, found, err := actorsOut.GetActor(a)
+		require.NoError(t, err)
+		require.True(t, found)
+		heads = append(heads, actor.Head)
+	}
+	assert.Equal(t, heads[0], heads[1])
+}
+
+type recordingMetrics struct {
+	lk       sync.Mutex
+	migrated map[addr.Address]cid.Cid
+	progress []migration.MigrationProgress
+}
+
+func (m *recordingMetrics) ActorMigrated(a addr.Address, codeIn cid.Cid, _ time.Duration) {
+	m.lk.Lock()
+	defer m.lk.Unlock()
+	m.migrated[a] = codeIn
+}
+
+func (m *recordingMetrics) Progress(progress migration.MigrationProgress) {
+	m.lk.Lock()
+	defer m.lk.Unlock()
+	m.progress = append(m.progress, progress)
+}
@@ -2,6 +2,7 @@ package test_test
 
 import (
 	"context"
+	"sync/atomic"
 	"testing"
 
 	addr "github.com/filecoin-project/go-address"
@@ -19,11 +20,15 @@ import (
 	"github.com/filecoin-project/specs-actors/actors/builtin/verifreg"
 	"github.com/filecoin-project/specs-actors/actors/states"
 	"github.com/filecoin-project/specs-actors/actors/util/adt"
+	builtin2 "github.com/filecoin-project/specs-actors/v2/actors/builtin"
 	"github.com/filecoin-project/specs-actors/v2/actors/migration"
+	states2 "github.com/filecoin-project/specs-actors/v2/actors/states"
 	ipld2 "github.com/filecoin-project/specs-actors/v2/support/ipld"
+	tutil "github.com/filecoin-project/specs-actors/v2/support/testing"
 	cid "github.com/ipfs/go-cid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	cbg "github.com/whyrusleeping/cbor-gen"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -31,6 +36,173 @@ func TestParallelMigrationCalls(t *testing.T) {
 	// Construct simple v0 state tree over a locking store
 	ctx := context.Background()
 	syncStore := ipld2.NewSyncADTStore(ctx)
+	actorsIn := newV0StateTree(ctx, t, syncStore)
+
+	startRoot, err := actorsIn.Flush()
+	require.NoError(t, err)
+
+	// Migrate to v2
+
+	endRootSerial, err := migration.MigrateStateTree(ctx, syncStore, startRoot, abi.ChainEpoch(0), migration.Config{MaxWorkers: 1})
+	require.NoError(t, err)
+
+	// Migrate in parallel
+	var endRootParallel1, endRootParallel2 cid.Cid
+	grp, ctx := errgroup.WithContext(ctx)
+	grp.Go(func() error {
+		var err1 error
+		endRootParallel1, err1 = migration.MigrateStateTree(ctx, syncStore, startRoot, abi.ChainEpoch(0), migration.Config{MaxWorkers: 2})
+		return err1
+	})
+	grp.Go(func() error {
+		var err2 error
+		endRootParallel2, err2 = migration.MigrateStateTree(ctx, syncStore, startRoot, abi.ChainEpoch(0), migration.Config{MaxWorkers: 2})
+		return err2
+	})
+	require.NoError(t, grp.Wait())
+	assert.Equal(t, endRootSerial, endRootParallel1)
+	assert.Equal(t, endRootParallel1, endRootParallel2)
+}
+
+func TestMigrationErrors(t *testing.T) {
+	ctx := context.Background()
+	store := ipld2.NewSyncADTStore(ctx)
+	actorsIn := newV0StateTree(ctx, t, store)
+	validRoot, err := actorsIn.Flush()
+	require.NoError(t, err)
+
+	t.Run("rejects a pool without workers", func(t *testing.T) {
+		_, err := migration.MigrateStateTree(ctx, store, validRoot, abi.ChainEpoch(0), migration.Config{MaxWorkers: 0})
+		assert.Error(t, err)
+	})
+
+	t.Run("fails on an actor without a migration", func(t *testing.T) {
+		unknown, err := addr.NewIDAddress(1000)
+		require.NoError(t, err)
+		require.NoError(t, actorsIn.SetActor(unknown, &states.Actor{
+			Code:    tutil.MakeCID("unknown actor", nil),
+			Head:    validRoot,
+			Balance: big.Zero(),
+		}))
+		root, err := actorsIn.Flush()
+		require.NoError(t, err)
+
+		_, err = migration.MigrateStateTree(ctx, store, root, abi.ChainEpoch(0), migration.DefaultConfig())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no migration for actor code")
+	})
+}
+
+func TestCachedMigration(t *testing.T) {
+	ctx := context.Background()
+	store := ipld2.NewSyncADTStore(ctx)
+	actorsIn := newV0StateTree(ctx, t, store)
+	startRoot, err := actorsIn.Flush()
+	require.NoError(t, err)
+
+	uncachedRoot, err := migration.MigrateStateTree(ctx, store, startRoot, abi.ChainEpoch(0), migration.DefaultConfig())
+	require.NoError(t, err)
+
+	// Migrate ahead of the upgrade epoch, populating the cache.
+	cache := &countingCache{MigrationCache: migration.NewMemMigrationCache()}
+	cachedRoot, err := migration.MigrateStateTree(ctx, store, startRoot, abi.ChainEpoch(0), migration.Config{MaxWorkers: 2, Cache: cache})
+	require.NoError(t, err)
+	assert.Equal(t, uncachedRoot, cachedRoot)
+	writes := cache.writes
+	assert.Greater(t, writes, int64(0))
+	assert.Equal(t, int64(0), cache.hits)
+
+	// Repeat the migration at the upgrade epoch, reading every cacheable actor from the cache.
+	upgradeRoot, err := migration.MigrateStateTree(ctx, store, startRoot, abi.ChainEpoch(1), migration.DefaultConfig())
+	require.NoError(t, err)
+	cache.writes = 0
+	resumedRoot, err := migration.MigrateStateTree(ctx, store, startRoot, abi.ChainEpoch(1), migration.Config{MaxWorkers: 2, Cache: cache})
+	require.NoError(t, err)
+	assert.Equal(t, upgradeRoot, resumedRoot)
+	assert.Equal(t, writes, cache.hits)
+	assert.Equal(t, int64(0), cache.writes)
+
+	// A cached head is used in place of migrating the actor's state.
+	cronIn, found, err := actorsIn.GetActor(builtin.CronActorAddr)
+	require.NoError(t, err)
+	require.True(t, found)
+	fakeHead := tutil.MakeCID("migrated cron state", nil)
+	require.NoError(t, cache.Write(migration.ActorHeadKey(builtin.CronActorAddr, cronIn.Head), fakeHead))
+	fakedRoot, err := migration.MigrateStateTree(ctx, store, startRoot, abi.ChainEpoch(1), migration.Config{MaxWorkers: 2, Cache: cache})
+	require.NoError(t, err)
+	actorsOut, err := states2.LoadTree(store, fakedRoot)
+	require.NoError(t, err)
+	cronOut, found, err := actorsOut.GetActor(builtin.CronActorAddr)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, fakeHead, cronOut.Head)
+}
+
+func TestDryRunMigration(t *testing.T) {
+	ctx := context.Background()
+	store := ipld2.NewSyncADTStore(ctx)
+	actorsIn := newV0StateTree(ctx, t, store)
+	startRoot, err := actorsIn.Flush()
+	require.NoError(t, err)
+
+	report, err := migration.DryRunMigration(ctx, store, startRoot, abi.ChainEpoch(0), migration.DefaultConfig())
+	require.NoError(t, err)
+	assert.Equal(t, startRoot, report.StateRootIn)
+
+	// Nothing is written to the store.
+	var raw cbg.Deferred
+	assert.Error(t, store.Get(ctx, report.StateRootOut, &raw))
+	endRoot, err := migration.MigrateStateTree(ctx, store, startRoot, abi.ChainEpoch(0), migration.DefaultConfig())
+	require.NoError(t, err)
+	assert.Equal(t, endRoot, report.StateRootOut)
+
+	// The report is the same as that of the committed migration.
+	committed, err := migration.ReportMigration(ctx, store, startRoot, endRoot)
+	require.NoError(t, err)
+	assert.Equal(t, committed, report)
+
+	reports := map[addr.Address]migration.ActorReport{}
+	for _, r := range report.Actors {
+		reports[r.Address] = r
+	}
+	paramreg := reports[builtin2.ParameterRegistryActorAddr]
+	assert.False(t, paramreg.CodeIn.Defined())
+	assert.True(t, paramreg.CodeOut.Defined())
+
+	power := reports[builtin.StoragePowerActorAddr]
+	assert.Equal(t, builtin.StoragePowerActorCodeID, power.CodeIn)
+	assert.True(t, power.BalanceDelta.IsZero())
+	assert.NotEmpty(t, power.Fields)
+	sizes := map[string]migration.CollectionSize{}
+	for _, c := range power.Collections {
+		sizes[c.Name] = c
+	}
+	assert.Equal(t, migration.CollectionSize{Name: "Claims", Before: 0, After: 0}, sizes["Claims"])
+	assert.Equal(t, migration.CollectionSize{Name: "CronEventQueue", Before: 0, After: 0}, sizes["CronEventQueue"])
+}
+
+// A migration cache counting its writes and the reads that find an entry.
+type countingCache struct {
+	migration.MigrationCache
+	writes int64
+	hits   int64
+}
+
+func (c *countingCache) Write(key string, newHead cid.Cid) error {
+	atomic.AddInt64(&c.writes, 1)
+	return c.MigrationCache.Write(key, newHead)
+}
+
+func (c *countingCache) Read(key string) (bool, cid.Cid, error) {
+	found, newHead, err := c.MigrationCache.Read(key)
+	if found {
+		atomic.AddInt64(&c.hits, 1)
+	}
+	return found, newHead, err
+}
+
+// Constructs a v0 state tree holding the singleton actors.
+func newV0StateTree(ctx context.Context, t *testing.T, store adt.Store) *states.Tree {
 	initializeActor := func(ctx context.Context, t *testing.T, actors *states.Tree, state cbor.Marshaler, code cid.Cid, a addr.Address, balance abi.TokenAmount) {
 		stateCID, err := actors.Store.Put(ctx, state)
 		require.NoError(t, err)
@@ -42,14 +214,14 @@ func TestParallelMigrationCalls(t *testing.T) {
 		err = actors.SetActor(a, actor)
 		require.NoError(t, err)
 	}
-	actorsIn, err := states.NewTree(syncStore)
+	actorsIn, err := states.NewTree(store)
 	require.NoError(t, err)
 
-	emptyMapCID, err := adt.MakeEmptyMap(syncStore).Root()
+	emptyMapCID, err := adt.MakeEmptyMap(store).Root()
 	require.NoError(t, err)
-	emptyArrayCID, err := adt.MakeEmptyArray(syncStore).Root()
+	emptyArrayCID, err := adt.MakeEmptyArray(store).Root()
 	require.NoError(t, err)
-	emptyMultimapCID, err := adt.MakeEmptyMultimap(syncStore).Root()
+	emptyMultimapCID, err := adt.MakeEmptyMultimap(store).Root()
 	require.NoError(t, err)
 
 	initializeActor(ctx, t, actorsIn, &system.State{}, builtin.SystemActorCodeID, builtin.SystemActorAddr, big.Zero())
@@ -79,28 +251,5 @@ func TestParallelMigrationCalls(t *testing.T) {
 	// burnt funds
 	initializeActor(ctx, t, actorsIn, &account.State{Address: builtin.BurntFundsActorAddr}, builtin.AccountActorCodeID, builtin.BurntFundsActorAddr, big.Zero())
 
-	startRoot, err := actorsIn.Flush()
-	require.NoError(t, err)
-
-	// Migrate to v2
-
-	endRootSerial, err := migration.MigrateStateTree(ctx, syncStore, startRoot, abi.ChainEpoch(0), migration.Config{MaxWorkers: 1})
-	require.NoError(t, err)
-
-	// Migrate in parallel
-	var endRootParallel1, endRootParallel2 cid.Cid
-	grp, ctx := errgroup.WithContext(ctx)
-	grp.Go(func() error {
-		var err1 error
-		endRootParallel1, err1 = migration.MigrateStateTree(ctx, syncStore, startRoot, abi.ChainEpoch(0), migration.Config{MaxWorkers: 2})
-		return err1
-	})
-	grp.Go(func() error {
-		var err2 error
-		endRootParallel2, err2 = migration.MigrateStateTree(ctx, syncStore, startRoot, abi.ChainEpoch(0), migration.Config{MaxWorkers: 2})
-		return err2
-	})
-	require.NoError(t, grp.Wait())
-	assert.Equal(t, endRootSerial, endRootParallel1)
-	assert.Equal(t, endRootParallel1, endRootParallel2)
+	return actorsIn
 }
@@ -0,0 +1,55 @@
+package test_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/migration"
+	ipld2 "github.com/filecoin-project/specs-actors/v2/support/ipld"
+)
+
+func TestPreMigrations(t *testing.T) {
+	ctx := context.Background()
+	store := ipld2.NewSyncADTStore(ctx)
+	actorsIn := newV0StateTree(ctx, t, store)
+	startRoot, err := actorsIn.Flush()
+	require.NoError(t, err)
+	upgradeEpoch := abi.ChainEpoch(1000)
+
+	t.Run("requires a cache", func(t *testing.T) {
+		_, err := migration.RunPreMigrations(ctx, store, startRoot, upgradeEpoch-100, upgradeEpoch, migration.DefaultConfig())
+		assert.Error(t, err)
+	})
+
+	t.Run("runs only within the window before the upgrade", func(t *testing.T) {
+		cfg := migration.Config{MaxWorkers: 2, Cache: &countingCache{MigrationCache: migration.NewMemMigrationCache()}}
+		for _, epoch := range []abi.ChainEpoch{upgradeEpoch - 121, upgradeEpoch - 15, upgradeEpoch - 1} {
+			run, err := migration.RunPreMigrations(ctx, store, startRoot, epoch, upgradeEpoch, cfg)
+			require.NoError(t, err)
+			assert.Empty(t, run, "at epoch %d", epoch)
+		}
+		run, err := migration.RunPreMigrations(ctx, store, startRoot, upgradeEpoch-120, upgradeEpoch, cfg)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"actor states"}, run)
+	})
+
+	t.Run("migration uses pre-migrated state", func(t *testing.T) {
+		cache := &countingCache{MigrationCache: migration.NewMemMigrationCache()}
+		cfg := migration.Config{MaxWorkers: 2, Cache: cache}
+		_, err := migration.RunPreMigrations(ctx, store, startRoot, upgradeEpoch-60, upgradeEpoch, cfg)
+		require.NoError(t, err)
+		preMigrated := cache.writes
+		assert.Greater(t, preMigrated, int64(0))
+
+		expected, err := migration.MigrateStateTree(ctx, store, startRoot, upgradeEpoch-1, migration.DefaultConfig())
+		require.NoError(t, err)
+		actual, err := migration.MigrateStateTree(ctx, store, startRoot, upgradeEpoch-1, cfg)
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual)
+		assert.Equal(t, preMigrated, cache.hits)
+	})
+}
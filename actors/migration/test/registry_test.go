@@ -0,0 +1,50 @@
+package test_test
+
+import (
+	"strings"
+	"testing"
+
+	builtin0 "github.com/filecoin-project/specs-actors/actors/builtin"
+	exported0 "github.com/filecoin-project/specs-actors/actors/builtin/exported"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	builtin2 "github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v2/actors/migration"
+)
+
+func TestActorMigrationRegistry(t *testing.T) {
+	migrations := migration.ActorMigrations(migration.ActorsVersion0)
+
+	t.Run("every v0 actor migrates to the v2 actor of the same name", func(t *testing.T) {
+		actors := exported0.BuiltinActors()
+		assert.Len(t, migrations, len(actors))
+		for _, a := range actors {
+			m, found := migrations[a.Code()]
+			require.True(t, found, "no migration for %v", a.Code())
+			nameOut := builtin2.ActorNameByCode(m.OutCodeCID)
+			assert.True(t, strings.HasPrefix(nameOut, "fil/2/"), "migration of %v to %s", a.Code(), nameOut)
+			assert.Equal(t, strings.TrimPrefix(nameOut, "fil/2/"), strings.TrimPrefix(builtin0.ActorNameByCode(a.Code()), "fil/1/"))
+		}
+	})
+
+	t.Run("returns a copy", func(t *testing.T) {
+		for code := range migrations { //nolint:nomaprange
+			delete(migrations, code)
+		}
+		assert.NotEmpty(t, migration.ActorMigrations(migration.ActorsVersion0))
+	})
+
+	t.Run("no migrations from an unregistered version", func(t *testing.T) {
+		assert.Empty(t, migration.ActorMigrations(migration.ActorsVersion2))
+	})
+
+	t.Run("rejects a duplicate registration", func(t *testing.T) {
+		for code, m := range migration.ActorMigrations(migration.ActorsVersion0) { //nolint:nomaprange
+			assert.Panics(t, func() {
+				migration.RegisterActorMigration(code, migration.ActorsVersion0, m)
+			})
+			break
+		}
+	})
+}
@@ -0,0 +1,68 @@
+package test_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"testing"
+
+	hamt2 "github.com/filecoin-project/go-hamt-ipld/v2"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	cbg "github.com/whyrusleeping/cbor-gen"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/migration"
+	adt2 "github.com/filecoin-project/specs-actors/v2/actors/util/adt"
+	ipld2 "github.com/filecoin-project/specs-actors/v2/support/ipld"
+)
+
+func TestRewriteHAMT(t *testing.T) {
+	ctx := context.Background()
+	store := ipld2.NewADTStore(ctx)
+	wideOptions := []hamt2.Option{
+		hamt2.UseTreeBitWidth(8),
+		hamt2.UseHashFunction(func(input []byte) []byte {
+			res := sha256.Sum256(input)
+			return res[:]
+		}),
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		root, err := adt2.MakeEmptyMap(store).Root()
+		require.NoError(t, err)
+		wideRoot, err := migration.RewriteHAMT(ctx, store, root, wideOptions...)
+		require.NoError(t, err)
+		narrowRoot, err := migration.RewriteHAMT(ctx, store, wideRoot, adt2.HamtOptions...)
+		require.NoError(t, err)
+		assert.Equal(t, root, narrowRoot)
+	})
+
+	t.Run("entries preserved across bitwidths", func(t *testing.T) {
+		// Enough entries that the output is flushed and reloaded during the rewrite.
+		const count = 20_000
+		m := adt2.MakeEmptyMap(store)
+		for i := int64(0); i < count; i++ {
+			v := cbg.CborInt(i * 3)
+			require.NoError(t, m.Put(abi.IntKey(i), &v))
+		}
+		root, err := m.Root()
+		require.NoError(t, err)
+
+		wideRoot, err := migration.RewriteHAMT(ctx, store, root, wideOptions...)
+		require.NoError(t, err)
+		assert.NotEqual(t, root, wideRoot)
+
+		wide, err := hamt2.LoadNode(ctx, store, wideRoot, wideOptions...)
+		require.NoError(t, err)
+		for _, i := range []int64{0, 1, count / 2, count - 1} {
+			var v cbg.CborInt
+			require.NoError(t, wide.Find(ctx, abi.IntKey(i).Key(), &v))
+			assert.Equal(t, cbg.CborInt(i*3), v)
+		}
+
+		// HAMTs are canonical, so rewriting with the original options restores the original root.
+		narrowRoot, err := migration.RewriteHAMT(ctx, store, wideRoot, adt2.HamtOptions...)
+		require.NoError(t, err)
+		assert.Equal(t, root, narrowRoot)
+	})
+}
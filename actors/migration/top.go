@@ -3,6 +3,8 @@ package migration
 import (
 	"context"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 
@@ -14,6 +16,7 @@ import (
 	"golang.org/x/xerrors"
 
 	builtin0 "github.com/filecoin-project/specs-actors/actors/builtin"
+	init0 "github.com/filecoin-project/specs-actors/actors/builtin/init"
 	miner0 "github.com/filecoin-project/specs-actors/actors/builtin/miner"
 	power0 "github.com/filecoin-project/specs-actors/actors/builtin/power"
 	states0 "github.com/filecoin-project/specs-actors/actors/states"
@@ -28,7 +31,10 @@ var (
 
 // Config parameterizes a state tree migration
 type Config struct {
-	MaxWorkers int
+	MaxWorkers     int              // Number of actors migrated concurrently; must be positive.
+	Cache          MigrationCache   // Cache of migrated state heads, or nil.
+	Metrics        MigrationMetrics // Receiver of progress reports, or nil.
+	ProgressPeriod time.Duration    // Period between progress reports; if zero, progress is reported only on completion.
 }
 
 func DefaultConfig() Config {
@@ -95,54 +101,24 @@ var deferredMigrations = map[cid.Cid]bool{
 	builtin0.RewardActorCodeID:           true,
 }
 
+// Migrations whose result depends on more than the actor's prior state head, i.e. on its balance or the epoch,
+// or which produce transfers or power updates, so cannot be cached.
+// The state heads of all other actors are migrated at most once per migration, however many actors share them.
+var uncachedMigrations = map[cid.Cid]bool{
+	builtin0.StorageMinerActorCodeID: true,
+}
+
 // Migrates the filecoin state tree starting from the global state tree and upgrading all actor state.
 func MigrateStateTree(ctx context.Context, store cbor.IpldStore, stateRootIn cid.Cid, priorEpoch abi.ChainEpoch, cfg Config) (cid.Cid, error) {
+	if cfg.MaxWorkers < 1 {
+		return cid.Undef, xerrors.Errorf("invalid migration config with %d workers", cfg.MaxWorkers)
+	}
 
-	var migrations = map[cid.Cid]ActorMigration{ // nolint:varcheck,deadcode,unused
-		builtin0.AccountActorCodeID: ActorMigration{
-			OutCodeCID:     builtin.AccountActorCodeID,
-			StateMigration: accountMigrator{},
-		},
-		builtin0.CronActorCodeID: ActorMigration{
-			OutCodeCID:     builtin.CronActorCodeID,
-			StateMigration: cronMigrator{},
-		},
-		builtin0.InitActorCodeID: ActorMigration{
-			OutCodeCID:     builtin.InitActorCodeID,
-			StateMigration: initMigrator{},
-		},
-		builtin0.StorageMarketActorCodeID: ActorMigration{
-			OutCodeCID:     builtin.StorageMarketActorCodeID,
-			StateMigration: marketMigrator{},
-		},
-		builtin0.MultisigActorCodeID: ActorMigration{
-			OutCodeCID:     builtin.MultisigActorCodeID,
-			StateMigration: multisigMigrator{},
-		},
-		builtin0.PaymentChannelActorCodeID: ActorMigration{
-			OutCodeCID:     builtin.PaymentChannelActorCodeID,
-			StateMigration: paychMigrator{},
-		},
-		builtin0.StoragePowerActorCodeID: ActorMigration{
-			OutCodeCID:     builtin.StoragePowerActorCodeID,
-			StateMigration: powerMigrator{},
-		},
-		builtin0.RewardActorCodeID: ActorMigration{
-			OutCodeCID:     builtin.RewardActorCodeID,
-			StateMigration: rewardMigrator{},
-		},
-		builtin0.SystemActorCodeID: ActorMigration{
-			OutCodeCID:     builtin.SystemActorCodeID,
-			StateMigration: systemMigrator{},
-		},
-		builtin0.VerifiedRegistryActorCodeID: ActorMigration{
-			OutCodeCID:     builtin.VerifiedRegistryActorCodeID,
-			StateMigration: verifregMigrator{},
-		},
-		builtin0.StorageMinerActorCodeID: ActorMigration{
-			OutCodeCID:     builtin.StorageMinerActorCodeID,
-			StateMigration: minerMigrator{},
-		},
+	migrations := ActorMigrations(ActorsVersion0)
+
+	counter := &progressCounter{start: time.Now()}
+	if cfg.Metrics != nil {
+		store = countingStore{IpldStore: store, written: &counter.written}
 	}
 
 	// Setup input and output state tree helpers
@@ -151,6 +127,23 @@ func MigrateStateTree(ctx context.Context, store cbor.IpldStore, stateRootIn cid
 	if err != nil {
 		return cid.Undef, err
 	}
+
+	// The system actor state records the network name, held only by the init actor before migration.
+	initActorIn, found, err := actorsIn.GetActor(builtin0.InitActorAddr)
+	if err != nil {
+		return cid.Undef, err
+	}
+	if !found {
+		return cid.Undef, xerrors.Errorf("could not find init actor in state")
+	}
+	var initStateIn init0.State
+	if err := store.Get(ctx, initActorIn.Head, &initStateIn); err != nil {
+		return cid.Undef, err
+	}
+	migrations[builtin0.SystemActorCodeID] = ActorMigration{
+		OutCodeCID:     builtin.SystemActorCodeID,
+		StateMigration: systemMigrator{networkName: initStateIn.NetworkName},
+	}
 	stateRootOut, err := adt.MakeEmptyMap(adtStore).Root()
 	if err != nil {
 		return cid.Undef, err
@@ -182,6 +175,7 @@ func MigrateStateTree(ctx context.Context, store cbor.IpldStore, stateRootIn cid
 			}
 			select {
 			case inputCh <- nextInput:
+				atomic.AddInt64(&counter.read, 1)
 			case <-ctx.Done():
 				return ctx.Err()
 			}
@@ -190,17 +184,38 @@ func MigrateStateTree(ctx context.Context, store cbor.IpldStore, stateRootIn cid
 		return err
 	})
 
-	// Worker threads run migrations on inputs
+	// Report progress until workers are done
+	workersDone := make(chan struct{})
+	reporterDone := make(chan struct{})
+	if cfg.Metrics != nil && cfg.ProgressPeriod > 0 {
+		go func() {
+			defer close(reporterDone)
+			counter.report(ctx, cfg.Metrics, cfg.ProgressPeriod, workersDone)
+		}()
+	} else {
+		close(reporterDone)
+	}
+
+	// Worker threads run migrations on inputs, sharing the heads migrated by each other
+	heads := NewMemMigrationCache()
 	var workerWg sync.WaitGroup
 	for i := 0; i < cfg.MaxWorkers; i++ {
 		workerWg.Add(1)
 		grp.Go(func() error {
 			defer workerWg.Done()
 			for input := range inputCh {
-				result, err := migrateOneActor(ctx, store, input, priorEpoch, migrations)
+				start := time.Now()
+				result, err := migrateOneActor(ctx, store, input, priorEpoch, migrations, cfg.Cache, heads)
 				if err != nil {
 					return err
 				}
+				atomic.AddInt64(&counter.processed, 1)
+				if result != nil && result.shared {
+					atomic.AddInt64(&counter.shared, 1)
+				}
+				if cfg.Metrics != nil && result != nil {
+					cfg.Metrics.ActorMigrated(input.Address, input.Actor.Code, time.Since(start))
+				}
 				if result == nil { // deferred actor migration
 					continue
 				}
@@ -219,6 +234,7 @@ func MigrateStateTree(ctx context.Context, store cbor.IpldStore, stateRootIn cid
 	grp.Go(func() error {
 		workerWg.Wait()
 		close(resultCh)
+		close(workersDone)
 		return nil
 	})
 
@@ -328,6 +344,10 @@ func MigrateStateTree(ctx context.Context, store cbor.IpldStore, stateRootIn cid
 		return cid.Undef, err
 	}
 
+	if err := createParameterRegistry(ctx, store, actorsOut, priorEpoch); err != nil {
+		return cid.Undef, xerrors.Errorf("failed to create parameter registry: %w", err)
+	}
+
 	// Track deductions to burntFunds actor's balance
 	burntFundsActor, found, err := actorsOut.GetActor(builtin.BurntFundsActorAddr)
 	if err != nil {
@@ -345,7 +365,15 @@ func MigrateStateTree(ctx context.Context, store cbor.IpldStore, stateRootIn cid
 		return cid.Undef, err
 	}
 
-	return actorsOut.Flush()
+	stateRootOut, err = actorsOut.Flush()
+	if err != nil {
+		return cid.Undef, err
+	}
+	if cfg.Metrics != nil {
+		<-reporterDone
+		cfg.Metrics.Progress(counter.progress(true))
+	}
+	return stateRootOut, nil
 }
 
 type migrationInput struct {
@@ -357,10 +385,14 @@ type migrationResult struct {
 	address.Address
 	states.Actor
 	*StateMigrationResult
+	shared bool // Whether the state head had already been migrated for another actor
 }
 
-func migrateOneActor(ctx context.Context, store cbor.IpldStore, input *migrationInput,
-	priorEpoch abi.ChainEpoch, migrations map[cid.Cid]ActorMigration) (*migrationResult, error) {
+// Migrates an actor, or returns nil if its migration is deferred.
+// The new heads of cacheable migrations are read from and written to both the (optional) persistent cache, keyed by
+// address and head, and the cache of heads shared by actors within this migration, keyed by code and head.
+func migrateOneActor(ctx context.Context, store cbor.IpldStore, input *migrationInput, priorEpoch abi.ChainEpoch,
+	migrations map[cid.Cid]ActorMigration, cache MigrationCache, heads *MemMigrationCache) (*migrationResult, error) {
 	actorIn := input.Actor
 	addr := input.Address
 	// This will be migrated at the end
@@ -368,17 +400,57 @@ func migrateOneActor(ctx context.Context, store cbor.IpldStore, input *migration
 		return nil, nil
 	}
 
-	migration := migrations[actorIn.Code]
+	migration, found := migrations[actorIn.Code]
+	if !found {
+		return nil, xerrors.Errorf("no migration for actor code %s at addr %s", actorIn.Code, addr)
+	}
 	codeOut := migration.OutCodeCID
-	result, err := migration.StateMigration.MigrateState(ctx, store, actorIn.Head, MigrationInfo{
-		address:    addr,
-		balance:    actorIn.Balance,
-		priorEpoch: priorEpoch,
-	})
-
-	if err != nil {
-		err = xerrors.Errorf("state migration error on %s actor at addr %s: %w", builtin.ActorNameByCode(codeOut), addr, err)
-		return nil, err
+	shareable := !uncachedMigrations[actorIn.Code]
+	cacheable := cache != nil && shareable
+	key := ActorHeadKey(addr, actorIn.Head)
+	sharedKey := codeHeadKey(actorIn.Code, actorIn.Head)
+
+	var result *StateMigrationResult
+	shared := false
+	if cacheable {
+		found, newHead, err := cache.Read(key)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to read migration cache for addr %s: %w", addr, err)
+		}
+		if found {
+			result = &StateMigrationResult{NewHead: newHead, Transfer: big.Zero()}
+		}
+	}
+	if result == nil && shareable {
+		if found, newHead, _ := heads.Read(sharedKey); found {
+			result = &StateMigrationResult{NewHead: newHead, Transfer: big.Zero()}
+			shared = true
+			if cacheable {
+				if err := cache.Write(key, result.NewHead); err != nil {
+					return nil, xerrors.Errorf("failed to write migration cache for addr %s: %w", addr, err)
+				}
+			}
+		}
+	}
+	if result == nil {
+		var err error
+		result, err = migration.StateMigration.MigrateState(ctx, store, actorIn.Head, MigrationInfo{
+			address:    addr,
+			balance:    actorIn.Balance,
+			priorEpoch: priorEpoch,
+		})
+		if err != nil {
+			err = xerrors.Errorf("state migration error on %s actor at addr %s: %w", builtin.ActorNameByCode(codeOut), addr, err)
+			return nil, err
+		}
+		if shareable {
+			_ = heads.Write(sharedKey, result.NewHead)
+		}
+		if cacheable {
+			if err := cache.Write(key, result.NewHead); err != nil {
+				return nil, xerrors.Errorf("failed to write migration cache for addr %s: %w", addr, err)
+			}
+		}
 	}
 
 	// set up new state root with the migrated state
@@ -391,6 +463,7 @@ func migrateOneActor(ctx context.Context, store cbor.IpldStore, input *migration
 			Balance:    big.Add(actorIn.Balance, result.Transfer),
 		},
 		result,
+		shared,
 	}, nil
 }
 
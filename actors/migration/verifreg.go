@@ -6,6 +6,7 @@ import (
 	addr "github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
+	builtin0 "github.com/filecoin-project/specs-actors/actors/builtin"
 	verifreg0 "github.com/filecoin-project/specs-actors/actors/builtin/verifreg"
 	adt0 "github.com/filecoin-project/specs-actors/actors/util/adt"
 	cid "github.com/ipfs/go-cid"
@@ -24,6 +25,13 @@ type verifregMigrator struct {
 	actorsOut *states.Tree
 }
 
+func init() {
+	RegisterActorMigration(builtin0.VerifiedRegistryActorCodeID, ActorsVersion0, ActorMigration{
+		OutCodeCID:     builtin2.VerifiedRegistryActorCodeID,
+		StateMigration: verifregMigrator{},
+	})
+}
+
 func (m verifregMigrator) MigrateState(ctx context.Context, store cbor.IpldStore, head cid.Cid, _ MigrationInfo) (*StateMigrationResult, error) {
 	var inState verifreg0.State
 	if err := store.Get(ctx, head, &inState); err != nil {
@@ -44,10 +52,23 @@ func (m verifregMigrator) MigrateState(ctx context.Context, store cbor.IpldStore
 		return nil, xerrors.Errorf("unexpected non-ID root key address %v", inState.RootKey)
 	}
 
+	eventsRoot, err := adt2.MakeEmptyArray(adt2.WrapStore(ctx, store)).Root()
+	if err != nil {
+		return nil, xerrors.Errorf("datacap events: %w", err)
+	}
+
+	proposalIDsRoot, err := adt2.MakeEmptyMap(adt2.WrapStore(ctx, store)).Root()
+	if err != nil {
+		return nil, xerrors.Errorf("datacap removal proposal ids: %w", err)
+	}
+
 	outState := verifreg2.State{
 		RootKey:         inState.RootKey,
 		Verifiers:       verifiersRoot,
 		VerifiedClients: clientsRoot,
+		DataCapEvents:   eventsRoot,
+
+		RemoveDataCapProposalIDs: proposalIDsRoot,
 	}
 	newHead, err := store.Put(ctx, &outState)
 	return &StateMigrationResult{
@@ -122,7 +143,7 @@ func (m *verifregMigrator) resolveAndMaybeCreateAccount(ctx context.Context, inA
 		return idInAddr, nil
 	}
 	// must create new id mapping
-	if inAddr.Protocol() != addr.SECP256K1 && inAddr.Protocol() != addr.BLS {
+	if !builtin2.IsSignableAddress(inAddr) {
 		// Don't implicitly create an account actor for an address without an associated key.
 		return addr.Undef, xerrors.Errorf("addr %v is not signable, cannot create account", inAddr)
 	}
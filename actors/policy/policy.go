@@ -0,0 +1,406 @@
+package policy
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/cron"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/market"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/miner"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/paych"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/power"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/verifreg"
+)
+
+// A Profile is a complete set of the economic and protocol parameters of the builtin actors which may differ
+// between networks.
+// Parameters not in a profile, such as the number of WindowPoSt deadlines (which fixes the layout of miner state),
+// are the same on every network.
+type Profile struct {
+	// The duration of a chain epoch. The other parameters are denominated in epochs of this duration.
+	EpochDurationSeconds int64
+
+	Miner    MinerPolicy
+	Market   MarketPolicy
+	Power    PowerPolicy
+	Verifreg VerifregPolicy
+	Paych    PaychPolicy
+	Cron     CronPolicy
+}
+
+type MinerPolicy struct {
+	// Proof types which may be used when creating a new miner or pre-committing a sector.
+	SupportedProofTypes []abi.RegisteredSealProof
+	// Minimum power of an individual miner to meet the threshold for leader election, by proof type.
+	ConsensusMinerMinPower map[abi.RegisteredSealProof]abi.StoragePower
+	// Maximum duration of a sector between activation and expiration, by proof type.
+	SectorMaxLifetime map[abi.RegisteredSealProof]abi.ChainEpoch
+
+	WPoStProvingPeriod             abi.ChainEpoch
+	WPoStChallengeWindow           abi.ChainEpoch
+	WPoStChallengeLookback         abi.ChainEpoch
+	FaultDeclarationCutoff         abi.ChainEpoch
+	FaultMaxAge                    abi.ChainEpoch
+	PreCommitChallengeDelay        abi.ChainEpoch
+	MaxPreCommitRandomnessLookback abi.ChainEpoch
+	MaxProveCommitDuration         map[abi.RegisteredSealProof]abi.ChainEpoch
+	MinSectorExpiration            abi.ChainEpoch
+	MaxSectorExpirationExtension   abi.ChainEpoch
+
+	PreCommitDepositProjectionPeriod             abi.ChainEpoch
+	InitialPledgeProjectionPeriod                abi.ChainEpoch
+	ContinuedFaultProjectionPeriod               abi.ChainEpoch
+	TerminationPenaltyLowerBoundProjectionPeriod abi.ChainEpoch
+	TerminationRewardFactor                      builtin.BigFrac
+	RewardVestingSpec                            miner.VestSpec
+}
+
+type MarketPolicy struct {
+	DealUpdatesInterval            abi.ChainEpoch
+	DealMinDuration                abi.ChainEpoch
+	DealMaxDuration                abi.ChainEpoch
+	ProviderCollateralSupplyTarget builtin.BigFrac
+}
+
+type PowerPolicy struct {
+	ConsensusMinerMinMiners int64
+}
+
+type VerifregPolicy struct {
+	MinVerifiedDealSize   abi.StoragePower
+	DataCapEventRetention abi.ChainEpoch
+}
+
+type PaychPolicy struct {
+	SettleDelay abi.ChainEpoch
+}
+
+type CronPolicy struct {
+	MaxOutOfGasBackoff abi.ChainEpoch
+}
+
+var (
+	lk       sync.Mutex
+	selected string
+	// The profile built from the values the actor packages are initialized with, captured before any selection.
+	defaultProfile = current()
+)
+
+// Returns the profile of the parameters with which the actors are compiled, i.e. those of mainnet.
+func Default() Profile {
+	return defaultProfile.clone()
+}
+
+// Returns the profile currently in effect.
+func Current() Profile {
+	lk.Lock()
+	defer lk.Unlock()
+	return current()
+}
+
+// Returns the name of the profile selected with Select, or the empty string if none has been selected.
+func Selected() string {
+	lk.Lock()
+	defer lk.Unlock()
+	return selected
+}
+
+// Selects the parameters for the process, overwriting the actor packages' policy variables and the epoch duration.
+// This is intended to be called once, during process initialization and before any actor code executes.
+// Selecting a second profile is an error, since actors already executed will have observed the first.
+func Select(name string, p Profile) error {
+	lk.Lock()
+	defer lk.Unlock()
+	if selected != "" {
+		return xerrors.Errorf("cannot select policy profile %s, profile %s already selected", name, selected)
+	}
+	if err := p.Validate(); err != nil {
+		return xerrors.Errorf("invalid policy profile %s: %w", name, err)
+	}
+	if err := builtin.SetEpochDuration(p.EpochDurationSeconds); err != nil {
+		return xerrors.Errorf("invalid policy profile %s: %w", name, err)
+	}
+	p.apply()
+	selected = name
+	return nil
+}
+
+// Checks that a profile's parameters are consistent with each other and with the fixed parameters.
+func (p *Profile) Validate() error {
+	if p.EpochDurationSeconds <= 0 || builtin.SecondsInHour%p.EpochDurationSeconds != 0 {
+		return xerrors.Errorf("epoch duration %d does not evenly divide one hour", p.EpochDurationSeconds)
+	}
+
+	m := &p.Miner
+	if len(m.SupportedProofTypes) == 0 {
+		return xerrors.Errorf("no supported proof types")
+	}
+	for _, pt := range m.SupportedProofTypes {
+		if _, ok := builtin.SealProofPolicies[pt]; !ok {
+			return xerrors.Errorf("unknown proof type %d", pt)
+		}
+		if _, ok := m.ConsensusMinerMinPower[pt]; !ok {
+			return xerrors.Errorf("no consensus miner minimum power for proof type %d", pt)
+		}
+		if _, ok := m.MaxProveCommitDuration[pt]; !ok {
+			return xerrors.Errorf("no maximum prove-commit duration for proof type %d", pt)
+		}
+		lifetime, ok := m.SectorMaxLifetime[pt]
+		if !ok {
+			return xerrors.Errorf("no sector maximum lifetime for proof type %d", pt)
+		}
+		if m.MaxSectorExpirationExtension > lifetime {
+			return xerrors.Errorf("maximum sector expiration extension %d exceeds maximum lifetime %d for proof type %d",
+				m.MaxSectorExpirationExtension, lifetime, pt)
+		}
+	}
+	if m.WPoStChallengeWindow <= 0 {
+		return xerrors.Errorf("non-positive challenge window %d", m.WPoStChallengeWindow)
+	}
+	if abi.ChainEpoch(miner.WPoStPeriodDeadlines)*m.WPoStChallengeWindow != m.WPoStProvingPeriod {
+		return xerrors.Errorf("proving period %d is not %d challenge windows of %d", m.WPoStProvingPeriod,
+			miner.WPoStPeriodDeadlines, m.WPoStChallengeWindow)
+	}
+	if m.FaultDeclarationCutoff < m.WPoStChallengeLookback {
+		return xerrors.Errorf("fault declaration cutoff %d less than challenge lookback %d",
+			m.FaultDeclarationCutoff, m.WPoStChallengeLookback)
+	}
+	if m.PreCommitChallengeDelay <= 0 {
+		return xerrors.Errorf("non-positive pre-commit challenge delay %d", m.PreCommitChallengeDelay)
+	}
+	if m.MinSectorExpiration > m.MaxSectorExpirationExtension {
+		return xerrors.Errorf("minimum sector expiration %d exceeds maximum extension %d",
+			m.MinSectorExpiration, m.MaxSectorExpirationExtension)
+	}
+	if m.TerminationRewardFactor.Denominator.Sign() <= 0 {
+		return xerrors.Errorf("non-positive termination reward factor denominator")
+	}
+	if m.RewardVestingSpec.StepDuration <= 0 || m.RewardVestingSpec.Quantization <= 0 {
+		return xerrors.Errorf("non-positive reward vesting step %d or quantization %d",
+			m.RewardVestingSpec.StepDuration, m.RewardVestingSpec.Quantization)
+	}
+
+	k := &p.Market
+	if k.DealUpdatesInterval <= 0 {
+		return xerrors.Errorf("non-positive deal updates interval %d", k.DealUpdatesInterval)
+	}
+	if k.DealMinDuration > k.DealMaxDuration {
+		return xerrors.Errorf("minimum deal duration %d exceeds maximum %d", k.DealMinDuration, k.DealMaxDuration)
+	}
+	if k.ProviderCollateralSupplyTarget.Denominator.Sign() <= 0 {
+		return xerrors.Errorf("non-positive provider collateral supply target denominator")
+	}
+
+	if p.Power.ConsensusMinerMinMiners < 0 {
+		return xerrors.Errorf("negative consensus miner minimum miners %d", p.Power.ConsensusMinerMinMiners)
+	}
+	if p.Verifreg.MinVerifiedDealSize.Sign() <= 0 {
+		return xerrors.Errorf("non-positive minimum verified deal size %v", p.Verifreg.MinVerifiedDealSize)
+	}
+	if p.Verifreg.DataCapEventRetention <= 0 {
+		return xerrors.Errorf("non-positive DataCap event retention %d", p.Verifreg.DataCapEventRetention)
+	}
+	if p.Paych.SettleDelay < 0 {
+		return xerrors.Errorf("negative payment channel settle delay %d", p.Paych.SettleDelay)
+	}
+	if p.Cron.MaxOutOfGasBackoff <= 0 {
+		return xerrors.Errorf("non-positive cron out-of-gas backoff %d", p.Cron.MaxOutOfGasBackoff)
+	}
+	return nil
+}
+
+// Returns a copy of a profile for a different epoch duration.
+// Periods which are defined in clock time, such as the proving period and deal durations, are rescaled to span
+// the same time. Periods defined in epochs, such as the WindowPoSt challenge lookback, pre-commit challenge delay
+// and chain finality, are unchanged.
+func (p Profile) WithEpochDuration(seconds int64) (Profile, error) {
+	c := p.clone()
+	from := abi.ChainEpoch(p.EpochDurationSeconds)
+	to := abi.ChainEpoch(seconds)
+	c.EpochDurationSeconds = seconds
+	if to <= 0 {
+		return Profile{}, xerrors.Errorf("non-positive epoch duration %d", seconds)
+	}
+	rescale := func(e *abi.ChainEpoch) {
+		*e = *e * from / to
+	}
+
+	m := &c.Miner
+	rescale(&m.WPoStProvingPeriod)
+	rescale(&m.WPoStChallengeWindow)
+	rescale(&m.FaultMaxAge)
+	// The randomness lookback and prove-commit durations are a day beyond chain finality and the challenge delay.
+	m.MaxPreCommitRandomnessLookback -= miner.ChainFinality
+	rescale(&m.MaxPreCommitRandomnessLookback)
+	m.MaxPreCommitRandomnessLookback += miner.ChainFinality
+	for pt, d := range m.MaxProveCommitDuration { // nolint:nomaprange
+		d -= m.PreCommitChallengeDelay
+		rescale(&d)
+		m.MaxProveCommitDuration[pt] = d + m.PreCommitChallengeDelay
+	}
+	for pt, lifetime := range m.SectorMaxLifetime { // nolint:nomaprange
+		rescale(&lifetime)
+		m.SectorMaxLifetime[pt] = lifetime
+	}
+	rescale(&m.MinSectorExpiration)
+	rescale(&m.MaxSectorExpirationExtension)
+	rescale(&m.PreCommitDepositProjectionPeriod)
+	rescale(&m.InitialPledgeProjectionPeriod)
+	rescale(&m.ContinuedFaultProjectionPeriod)
+	rescale(&m.TerminationPenaltyLowerBoundProjectionPeriod)
+	rescale(&m.RewardVestingSpec.InitialDelay)
+	rescale(&m.RewardVestingSpec.VestPeriod)
+	rescale(&m.RewardVestingSpec.StepDuration)
+	rescale(&m.RewardVestingSpec.Quantization)
+
+	k := &c.Market
+	rescale(&k.DealUpdatesInterval)
+	rescale(&k.DealMinDuration)
+	rescale(&k.DealMaxDuration)
+
+	rescale(&c.Verifreg.DataCapEventRetention)
+	rescale(&c.Paych.SettleDelay)
+	rescale(&c.Cron.MaxOutOfGasBackoff)
+
+	if err := c.Validate(); err != nil {
+		return Profile{}, xerrors.Errorf("invalid profile for epoch duration %d: %w", seconds, err)
+	}
+	return c, nil
+}
+
+// Reads a profile from the actor packages' policy variables.
+func current() Profile {
+	supported := make([]abi.RegisteredSealProof, 0, len(miner.SupportedProofTypes))
+	for pt := range miner.SupportedProofTypes { // nolint:nomaprange
+		supported = append(supported, pt)
+	}
+	sort.Slice(supported, func(i, j int) bool { return supported[i] < supported[j] })
+	minPower := make(map[abi.RegisteredSealProof]abi.StoragePower, len(builtin.SealProofPolicies))
+	lifetime := make(map[abi.RegisteredSealProof]abi.ChainEpoch, len(builtin.SealProofPolicies))
+	for pt, info := range builtin.SealProofPolicies { // nolint:nomaprange
+		minPower[pt] = info.ConsensusMinerMinPower
+		lifetime[pt] = info.SectorMaxLifetime
+	}
+	p := Profile{
+		EpochDurationSeconds: builtin.EpochDurationSeconds,
+		Miner: MinerPolicy{
+			SupportedProofTypes:            supported,
+			ConsensusMinerMinPower:         minPower,
+			SectorMaxLifetime:              lifetime,
+			WPoStProvingPeriod:             miner.WPoStProvingPeriod,
+			WPoStChallengeWindow:           miner.WPoStChallengeWindow,
+			WPoStChallengeLookback:         miner.WPoStChallengeLookback,
+			FaultDeclarationCutoff:         miner.FaultDeclarationCutoff,
+			FaultMaxAge:                    miner.FaultMaxAge,
+			PreCommitChallengeDelay:        miner.PreCommitChallengeDelay,
+			MaxPreCommitRandomnessLookback: miner.MaxPreCommitRandomnessLookback,
+			MaxProveCommitDuration:         miner.MaxProveCommitDuration,
+			MinSectorExpiration:            miner.MinSectorExpiration,
+			MaxSectorExpirationExtension:   miner.MaxSectorExpirationExtension,
+
+			PreCommitDepositProjectionPeriod:             miner.PreCommitDepositProjectionPeriod,
+			InitialPledgeProjectionPeriod:                miner.InitialPledgeProjectionPeriod,
+			ContinuedFaultProjectionPeriod:               miner.ContinuedFaultProjectionPeriod,
+			TerminationPenaltyLowerBoundProjectionPeriod: miner.TerminationPenaltyLowerBoundProjectionPeriod,
+			TerminationRewardFactor:                      miner.TerminationRewardFactor,
+			RewardVestingSpec:                            miner.RewardVestingSpec,
+		},
+		Market: MarketPolicy{
+			DealUpdatesInterval:            market.DealUpdatesInterval,
+			DealMinDuration:                market.DealMinDuration,
+			DealMaxDuration:                market.DealMaxDuration,
+			ProviderCollateralSupplyTarget: market.ProviderCollateralSupplyTarget,
+		},
+		Power: PowerPolicy{
+			ConsensusMinerMinMiners: power.ConsensusMinerMinMiners,
+		},
+		Verifreg: VerifregPolicy{
+			MinVerifiedDealSize:   verifreg.MinVerifiedDealSize,
+			DataCapEventRetention: verifreg.DataCapEventRetention,
+		},
+		Paych: PaychPolicy{
+			SettleDelay: paych.SettleDelay,
+		},
+		Cron: CronPolicy{
+			MaxOutOfGasBackoff: cron.MaxOutOfGasBackoff,
+		},
+	}
+	return p.clone()
+}
+
+// Writes a profile to the actor packages' policy variables.
+func (p *Profile) apply() {
+	c := p.clone()
+	m := &c.Miner
+	miner.SupportedProofTypes = make(map[abi.RegisteredSealProof]struct{}, len(m.SupportedProofTypes))
+	for _, pt := range m.SupportedProofTypes {
+		miner.SupportedProofTypes[pt] = struct{}{}
+	}
+	for pt, minPower := range m.ConsensusMinerMinPower { // nolint:nomaprange
+		if info, ok := builtin.SealProofPolicies[pt]; ok {
+			info.ConsensusMinerMinPower = minPower
+		}
+	}
+	for pt, lifetime := range m.SectorMaxLifetime { // nolint:nomaprange
+		if info, ok := builtin.SealProofPolicies[pt]; ok {
+			info.SectorMaxLifetime = lifetime
+		}
+	}
+	miner.WPoStProvingPeriod = m.WPoStProvingPeriod
+	miner.WPoStChallengeWindow = m.WPoStChallengeWindow
+	miner.WPoStChallengeLookback = m.WPoStChallengeLookback
+	miner.FaultDeclarationCutoff = m.FaultDeclarationCutoff
+	miner.FaultMaxAge = m.FaultMaxAge
+	miner.PreCommitChallengeDelay = m.PreCommitChallengeDelay
+	miner.MaxPreCommitRandomnessLookback = m.MaxPreCommitRandomnessLookback
+	miner.MaxProveCommitDuration = m.MaxProveCommitDuration
+	miner.MinSectorExpiration = m.MinSectorExpiration
+	miner.MaxSectorExpirationExtension = m.MaxSectorExpirationExtension
+	miner.PreCommitDepositProjectionPeriod = m.PreCommitDepositProjectionPeriod
+	miner.InitialPledgeProjectionPeriod = m.InitialPledgeProjectionPeriod
+	miner.ContinuedFaultProjectionPeriod = m.ContinuedFaultProjectionPeriod
+	miner.TerminationPenaltyLowerBoundProjectionPeriod = m.TerminationPenaltyLowerBoundProjectionPeriod
+	miner.TerminationRewardFactor = m.TerminationRewardFactor
+	miner.RewardVestingSpec = m.RewardVestingSpec
+
+	market.DealUpdatesInterval = c.Market.DealUpdatesInterval
+	market.DealMinDuration = c.Market.DealMinDuration
+	market.DealMaxDuration = c.Market.DealMaxDuration
+	market.ProviderCollateralSupplyTarget = c.Market.ProviderCollateralSupplyTarget
+
+	power.ConsensusMinerMinMiners = c.Power.ConsensusMinerMinMiners
+
+	verifreg.MinVerifiedDealSize = c.Verifreg.MinVerifiedDealSize
+	verifreg.DataCapEventRetention = c.Verifreg.DataCapEventRetention
+
+	paych.SettleDelay = c.Paych.SettleDelay
+
+	cron.MaxOutOfGasBackoff = c.Cron.MaxOutOfGasBackoff
+}
+
+// Returns a copy of a profile sharing no mutable state with the original.
+func (p Profile) clone() Profile {
+	m := &p.Miner
+	m.SupportedProofTypes = append([]abi.RegisteredSealProof(nil), m.SupportedProofTypes...)
+	minPower := make(map[abi.RegisteredSealProof]abi.StoragePower, len(m.ConsensusMinerMinPower))
+	for pt, pow := range m.ConsensusMinerMinPower { // nolint:nomaprange
+		minPower[pt] = big.Add(pow, big.Zero())
+	}
+	m.ConsensusMinerMinPower = minPower
+	proveCommitDuration := make(map[abi.RegisteredSealProof]abi.ChainEpoch, len(m.MaxProveCommitDuration))
+	for pt, d := range m.MaxProveCommitDuration { // nolint:nomaprange
+		proveCommitDuration[pt] = d
+	}
+	m.MaxProveCommitDuration = proveCommitDuration
+	lifetime := make(map[abi.RegisteredSealProof]abi.ChainEpoch, len(m.SectorMaxLifetime))
+	for pt, d := range m.SectorMaxLifetime { // nolint:nomaprange
+		lifetime[pt] = d
+	}
+	m.SectorMaxLifetime = lifetime
+	return p
+}
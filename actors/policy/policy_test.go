@@ -0,0 +1,163 @@
+package policy_test
+
+import (
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/miner"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/verifreg"
+	"github.com/filecoin-project/specs-actors/v2/actors/policy"
+)
+
+func TestBuiltinProfilesValid(t *testing.T) {
+	assert.Equal(t, []string{policy.Devnet, policy.Mainnet}, policy.Names())
+	for _, name := range policy.Names() {
+		p, ok := policy.Named(name)
+		require.True(t, ok)
+		assert.NoError(t, p.Validate(), name)
+	}
+	_, ok := policy.Named("nonesuch")
+	assert.False(t, ok)
+}
+
+func TestValidate(t *testing.T) {
+	p := policy.Default()
+	p.Miner.WPoStChallengeWindow = p.Miner.WPoStChallengeWindow / 2
+	assert.Error(t, p.Validate())
+
+	p = policy.Default()
+	p.Miner.SupportedProofTypes = append(p.Miner.SupportedProofTypes, abi.RegisteredSealProof(1000))
+	assert.Error(t, p.Validate())
+
+	p = policy.Default()
+	p.Market.DealMinDuration = p.Market.DealMaxDuration + 1
+	assert.Error(t, p.Validate())
+
+	p = policy.Default()
+	p.Miner.SectorMaxLifetime[abi.RegisteredSealProof_StackedDrg32GiBV1] = p.Miner.MaxSectorExpirationExtension - 1
+	assert.Error(t, p.Validate())
+}
+
+func TestSelect(t *testing.T) {
+	// Modifying a returned profile does not affect the default.
+	p := policy.Default()
+	p.Miner.MaxProveCommitDuration[abi.RegisteredSealProof_StackedDrg32GiBV1] = 1
+	assert.NotEqual(t, abi.ChainEpoch(1), policy.Default().Miner.MaxProveCommitDuration[abi.RegisteredSealProof_StackedDrg32GiBV1])
+	assert.Equal(t, policy.Default(), policy.Current())
+
+	invalid := policy.Default()
+	invalid.Verifreg.MinVerifiedDealSize = abi.NewStoragePower(0)
+	require.Error(t, policy.Select("invalid", invalid))
+	assert.Equal(t, "", policy.Selected())
+
+	devnet, _ := policy.Named(policy.Devnet)
+	require.NoError(t, policy.Select(policy.Devnet, devnet))
+	assert.Equal(t, policy.Devnet, policy.Selected())
+	assert.Equal(t, devnet, policy.Current())
+
+	// The actor packages' variables reflect the selection.
+	_, ok := miner.SupportedProofTypes[abi.RegisteredSealProof_StackedDrg2KiBV1]
+	assert.True(t, ok)
+	_, ok = miner.SupportedProofTypes[abi.RegisteredSealProof_StackedDrg32GiBV1]
+	assert.False(t, ok)
+	assert.Equal(t, abi.ChainEpoch(10), miner.PreCommitChallengeDelay)
+	assert.Equal(t, abi.NewStoragePower(256), verifreg.MinVerifiedDealSize)
+	minPower, err := builtin.ConsensusMinerMinPower(abi.RegisteredSealProof_StackedDrg32GiBV1)
+	require.NoError(t, err)
+	assert.Equal(t, abi.NewStoragePower(2048), minPower)
+
+	// The default is unchanged, and a second selection is rejected.
+	assert.Equal(t, abi.ChainEpoch(150), policy.Default().Miner.PreCommitChallengeDelay)
+	assert.Error(t, policy.Select(policy.Mainnet, policy.Default()))
+	assert.Equal(t, policy.Devnet, policy.Selected())
+}
+
+func TestWithEpochDuration(t *testing.T) {
+	p := policy.Default()
+	fast, err := p.WithEpochDuration(6)
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), fast.EpochDurationSeconds)
+
+	// Periods defined in clock time span the same time.
+	assert.Equal(t, 5*p.Miner.WPoStProvingPeriod, fast.Miner.WPoStProvingPeriod)
+	assert.Equal(t, 5*p.Miner.WPoStChallengeWindow, fast.Miner.WPoStChallengeWindow)
+	assert.Equal(t, 5*p.Market.DealMinDuration, fast.Market.DealMinDuration)
+	assert.Equal(t, 5*p.Paych.SettleDelay, fast.Paych.SettleDelay)
+	assert.Equal(t, 5*p.Miner.SectorMaxLifetime[abi.RegisteredSealProof_StackedDrg32GiBV1], fast.Miner.SectorMaxLifetime[abi.RegisteredSealProof_StackedDrg32GiBV1])
+	assert.Equal(t, 5*p.Verifreg.DataCapEventRetention, fast.Verifreg.DataCapEventRetention)
+	assert.Equal(t, 5*p.Cron.MaxOutOfGasBackoff, fast.Cron.MaxOutOfGasBackoff)
+	day := abi.ChainEpoch(builtin.SecondsInDay / 6)
+	assert.Equal(t, day+miner.ChainFinality, fast.Miner.MaxPreCommitRandomnessLookback)
+	assert.Equal(t, day+p.Miner.PreCommitChallengeDelay, fast.Miner.MaxProveCommitDuration[abi.RegisteredSealProof_StackedDrg32GiBV1])
+
+	// Periods defined in epochs are unchanged.
+	assert.Equal(t, p.Miner.WPoStChallengeLookback, fast.Miner.WPoStChallengeLookback)
+	assert.Equal(t, p.Miner.PreCommitChallengeDelay, fast.Miner.PreCommitChallengeDelay)
+
+	// The original is unchanged, and rescaling back restores it.
+	assert.Equal(t, policy.Default(), p)
+	back, err := fast.WithEpochDuration(30)
+	require.NoError(t, err)
+	assert.Equal(t, p, back)
+
+	// A challenge window that is not a whole number of epochs is rejected.
+	_, err = p.WithEpochDuration(240)
+	assert.Error(t, err)
+	_, err = p.WithEpochDuration(7)
+	assert.Error(t, err)
+}
+
+func TestSetChallengeWindow(t *testing.T) {
+	p, _ := policy.Named(policy.Devnet)
+	policy.SetChallengeWindow(20)(&p)
+	require.NoError(t, p.Validate())
+	assert.Equal(t, abi.ChainEpoch(20), p.Miner.WPoStChallengeWindow)
+	assert.Equal(t, abi.ChainEpoch(20*miner.WPoStPeriodDeadlines), p.Miner.WPoStProvingPeriod)
+}
+
+func TestSchedule(t *testing.T) {
+	devnet, _ := policy.Named(policy.Devnet)
+	s := policy.Schedule{
+		{Version: network.Version2, Profile: policy.Default()},
+		{Version: network.Version5, Profile: devnet},
+	}
+	require.NoError(t, s.Validate())
+	assert.Equal(t, policy.Default(), s.ForVersion(network.Version0))
+	assert.Equal(t, policy.Default(), s.ForVersion(network.Version4))
+	assert.Equal(t, devnet, s.ForVersion(network.Version5))
+	assert.Equal(t, devnet, s.ForVersion(network.Version6))
+
+	// Versions must increase, and the epoch duration may not change.
+	assert.Error(t, policy.Schedule{}.Validate())
+	assert.Error(t, policy.Schedule{s[1], s[0]}.Validate())
+	fast, err := devnet.WithEpochDuration(6)
+	require.NoError(t, err)
+	assert.Error(t, policy.Schedule{s[0], {Version: network.Version5, Profile: fast}}.Validate())
+
+	// A setter applies from a version on, scheduling a profile at the version.
+	shortened := s.With(network.Version3, policy.SetChallengeWindow(20))
+	require.NoError(t, shortened.Validate())
+	require.Len(t, shortened, 3)
+	assert.Equal(t, policy.Default(), shortened.ForVersion(network.Version2))
+	assert.Equal(t, abi.ChainEpoch(20), shortened.ForVersion(network.Version3).Miner.WPoStChallengeWindow)
+	assert.Equal(t, abi.ChainEpoch(20*miner.WPoStPeriodDeadlines), shortened.ForVersion(network.Version3).Miner.WPoStProvingPeriod)
+	assert.Equal(t, abi.ChainEpoch(20), shortened.ForVersion(network.Version5).Miner.WPoStChallengeWindow)
+	assert.Equal(t, devnet.Miner.PreCommitChallengeDelay, shortened.ForVersion(network.Version5).Miner.PreCommitChallengeDelay)
+	assert.Equal(t, policy.Default(), s.ForVersion(network.Version3))
+
+	// A setter from a version already scheduled, or after the last, adds no earlier profile.
+	assert.Len(t, s.With(network.Version5, policy.SetChallengeWindow(20)), 2)
+	assert.Len(t, s.With(network.Version6, policy.SetChallengeWindow(20)), 3)
+	assert.Len(t, policy.ScheduleOf(devnet).With(network.Version0, policy.SetChallengeWindow(20)), 1)
+
+	// Querying a schedule leaves the parameters the actors read unchanged.
+	before := policy.Current()
+	_ = shortened.ForVersion(network.Version6)
+	assert.Equal(t, before, policy.Current())
+	assert.NotEqual(t, abi.ChainEpoch(20), miner.WPoStChallengeWindow)
+}
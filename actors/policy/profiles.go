@@ -0,0 +1,73 @@
+package policy
+
+import (
+	"sort"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/miner"
+)
+
+// Names of the built-in profiles.
+const (
+	Mainnet = "mainnet"
+	Devnet  = "devnet"
+)
+
+var profiles = map[string]func() Profile{
+	Mainnet: Default,
+	Devnet:  DevnetProfile,
+}
+
+// Returns the built-in profile with a name, and whether it exists.
+func Named(name string) (Profile, bool) {
+	build, ok := profiles[name]
+	if !ok {
+		return Profile{}, false
+	}
+	return build(), true
+}
+
+// Returns the names of the built-in profiles, sorted.
+func Names() []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles { // nolint:nomaprange
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Returns a profile for small development and test networks, with small sectors, a low consensus minimum power,
+// and a short pre-commit challenge delay so sectors may be committed within minutes.
+func DevnetProfile() Profile {
+	p := Default()
+	m := &p.Miner
+	m.SupportedProofTypes = []abi.RegisteredSealProof{
+		abi.RegisteredSealProof_StackedDrg2KiBV1,
+		abi.RegisteredSealProof_StackedDrg8MiBV1,
+	}
+	for pt := range m.ConsensusMinerMinPower { // nolint:nomaprange
+		m.ConsensusMinerMinPower[pt] = abi.NewStoragePower(2048)
+	}
+	m.PreCommitChallengeDelay = abi.ChainEpoch(10)
+	for pt := range m.MaxProveCommitDuration { // nolint:nomaprange
+		m.MaxProveCommitDuration[pt] = abi.ChainEpoch(builtin.SecondsInDay/p.EpochDurationSeconds) + m.PreCommitChallengeDelay
+	}
+	p.Verifreg.MinVerifiedDealSize = big.NewInt(256)
+	return p
+}
+
+// Returns a setter of the WindowPoSt challenge window, and with it the proving period of the fixed number of
+// deadlines. A test network may shorten its proving period by applying this to a profile before selecting it, or
+// to a schedule's profiles with Schedule.With. The fault declaration cutoff and challenge lookback must not exceed
+// the window.
+func SetChallengeWindow(window abi.ChainEpoch) func(p *Profile) {
+	return func(p *Profile) {
+		m := &p.Miner
+		m.WPoStChallengeWindow = window
+		m.WPoStProvingPeriod = abi.ChainEpoch(miner.WPoStPeriodDeadlines) * window
+	}
+}
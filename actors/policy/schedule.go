@@ -0,0 +1,86 @@
+package policy
+
+import (
+	"github.com/filecoin-project/go-state-types/network"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/util"
+)
+
+// A profile in effect from a network version.
+type ScheduledProfile struct {
+	Version network.Version
+	Profile Profile
+}
+
+// Profiles in order of the network versions from which they are in effect, each until the next, describing how
+// a network's parameters change at its upgrades.
+// A schedule is only queried, e.g. by tooling presenting a network's parameters at a version, or by a devnet
+// selecting the profile for the version at which it starts with Select(name, s.ForVersion(nv)).
+// The actors read their parameters from the package variables set once by Select, which hold for every epoch a
+// process executes, so a schedule never switches the profile at an upgrade: the miner's deadline arithmetic for
+// state already on chain would otherwise change underneath it.
+type Schedule []ScheduledProfile
+
+// Returns a schedule with a single profile, in effect at every network version.
+func ScheduleOf(p Profile) Schedule {
+	return Schedule{{Version: 0, Profile: p.clone()}}
+}
+
+// Returns the profile in effect at a network version.
+// Versions before the first scheduled are given the first profile.
+func (s Schedule) ForVersion(nv network.Version) Profile {
+	return s[util.VersionIndex(s.versions(), nv)].Profile.clone()
+}
+
+// Checks that the schedule is non-empty, in increasing order of version, and that every profile is valid.
+// The epoch duration may not change, since epochs already elapsed cannot be re-denominated.
+func (s Schedule) Validate() error {
+	if err := util.ValidateVersions(s.versions()); err != nil {
+		return xerrors.Errorf("policy schedule: %w", err)
+	}
+	for _, scheduled := range s {
+		if err := scheduled.Profile.Validate(); err != nil {
+			return xerrors.Errorf("policy profile for version %d: %w", scheduled.Version, err)
+		}
+		if scheduled.Profile.EpochDurationSeconds != s[0].Profile.EpochDurationSeconds {
+			return xerrors.Errorf("policy profile for version %d changes epoch duration from %d to %d",
+				scheduled.Version, s[0].Profile.EpochDurationSeconds, scheduled.Profile.EpochDurationSeconds)
+		}
+	}
+	return nil
+}
+
+func (s Schedule) versions() []network.Version {
+	versions := make([]network.Version, len(s))
+	for i, scheduled := range s {
+		versions[i] = scheduled.Version
+	}
+	return versions
+}
+
+// Returns a copy of the schedule with the profiles in effect from a network version on changed by a setter such
+// as SetChallengeWindow. A profile is scheduled at the version, if none is already, copied from the one in effect
+// before it.
+func (s Schedule) With(from network.Version, set func(p *Profile)) Schedule {
+	c := make(Schedule, 0, len(s)+1)
+	scheduled := false
+	for _, sp := range s {
+		if !scheduled && sp.Version >= from {
+			if sp.Version > from {
+				c = append(c, ScheduledProfile{Version: from, Profile: s.ForVersion(from)})
+			}
+			scheduled = true
+		}
+		c = append(c, ScheduledProfile{Version: sp.Version, Profile: sp.Profile.clone()})
+	}
+	if !scheduled {
+		c = append(c, ScheduledProfile{Version: from, Profile: s.ForVersion(from)})
+	}
+	for i := range c {
+		if c[i].Version >= from {
+			set(&c[i].Profile)
+		}
+	}
+	return c
+}
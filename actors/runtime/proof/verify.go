@@ -1,7 +1,9 @@
 package proof
 
 import (
+	"github.com/filecoin-project/go-state-types/abi"
 	proof0 "github.com/filecoin-project/specs-actors/actors/runtime/proof"
+	"github.com/ipfs/go-cid"
 )
 
 ///
@@ -23,6 +25,58 @@ import (
 //}
 type SealVerifyInfo = proof0.SealVerifyInfo
 
+// A scheme by which many seal proofs are aggregated into a single proof.
+type RegisteredAggregationProof int64
+
+const (
+	RegisteredAggregationProof_SnarkPackV1 = RegisteredAggregationProof(0)
+)
+
+// Information needed to verify one of the seal proofs in an aggregate, for a sector of the aggregating miner.
+type AggregateSealVerifyInfo struct {
+	Number                abi.SectorNumber
+	Randomness            abi.SealRandomness
+	InteractiveRandomness abi.InteractiveSealRandomness
+
+	// Safe because we get those from the miner actor
+	SealedCID   cid.Cid `checked:"true"` // CommR
+	UnsealedCID cid.Cid `checked:"true"` // CommD
+}
+
+// An aggregate of seal proofs for sectors of a single miner, all with the same seal proof type,
+// and the information needed to verify each of them.
+type AggregateSealVerifyProofAndInfos struct {
+	Miner          abi.ActorID
+	SealProof      abi.RegisteredSealProof
+	AggregateProof RegisteredAggregationProof
+	Proof          []byte
+	Infos          []AggregateSealVerifyInfo
+}
+
+///
+/// Replica updates
+///
+
+// A proof scheme for updating the data in a committed sector replica.
+type RegisteredUpdateProof int64
+
+const (
+	RegisteredUpdateProof_StackedDrg2KiBV1   = RegisteredUpdateProof(0)
+	RegisteredUpdateProof_StackedDrg8MiBV1   = RegisteredUpdateProof(1)
+	RegisteredUpdateProof_StackedDrg512MiBV1 = RegisteredUpdateProof(2)
+	RegisteredUpdateProof_StackedDrg32GiBV1  = RegisteredUpdateProof(3)
+	RegisteredUpdateProof_StackedDrg64GiBV1  = RegisteredUpdateProof(4)
+)
+
+// Information needed to verify that a sector replica has been updated to encode new data.
+type ReplicaUpdateInfo struct {
+	UpdateProofType      RegisteredUpdateProof
+	OldSealedSectorCID   cid.Cid
+	NewSealedSectorCID   cid.Cid
+	NewUnsealedSectorCID cid.Cid
+	Proof                []byte
+}
+
 ///
 /// PoSting
 ///
@@ -69,26 +69,47 @@ type Runtime interface {
 	// The address will be resolved as if via ResolveAddress, if necessary, so need not be an ID-address.
 	GetActorCodeCID(addr addr.Address) (ret cid.Cid, ok bool)
 
-	// GetRandomnessFromBeacon returns a (pseudo)random byte array drawing from a random beacon at a prior epoch.
-	// The beacon value is combined with the personalization tag, epoch number, and explicitly provided entropy.
+	// GetRandomness returns a (pseudo)random byte array drawing from a source of randomness at a prior epoch.
+	// The source value is combined with the personalization tag, epoch number, and explicitly provided entropy.
 	// The personalization tag may be any int64 value.
-	// The epoch must be less than the current epoch. The epoch may be negative, in which case
-	// it addresses the beacon value from genesis block.
+	// The epoch must be at least MinRandomnessLookback and at most the source's maximum lookback
+	// (MaxTicketLookback or MaxBeaconLookback) prior to the current epoch, as checked by CheckRandomnessLookback;
+	// the runtime aborts with exitcode.SysErrorIllegalArgument otherwise.
+	// The epoch may be negative, in which case it addresses the value from the genesis block.
 	// The entropy may be any byte array, or nil.
-	GetRandomnessFromBeacon(personalization crypto.DomainSeparationTag, randEpoch abi.ChainEpoch, entropy []byte) abi.Randomness
-
-	// GetRandomnessFromTickets samples randomness from the ticket chain. Randomess
-	// sampled through this method is unique per potential fork, and as a
-	// result, processes relying on this randomness are tied to whichever fork
-	// they choose.
-	// See GetRandomnessFromBeacon for notes about the personalization tag, epoch, and entropy.
-	GetRandomnessFromTickets(personalization crypto.DomainSeparationTag, randEpoch abi.ChainEpoch, entropy []byte) abi.Randomness
+	GetRandomness(source RandomnessSource, personalization crypto.DomainSeparationTag, randEpoch abi.ChainEpoch, entropy []byte) abi.Randomness
+
+	// Returns the data of the random beacon entry recorded at a prior epoch.
+	// If no entry was recorded for the epoch (e.g. a null round), returns the most recent entry prior to it.
+	// This is one of a small set of historical values the runtime records at each epoch, which let an actor
+	// evaluate claims about prior chain state, e.g. when a dispute references a proof submitted some time ago.
+	// The epoch must be non-negative and at least MinHistoricalLookback and at most MaxHistoricalLookback prior
+	// to the current epoch, as checked by CheckHistoricalLookback; the runtime aborts with
+	// exitcode.SysErrorIllegalArgument otherwise.
+	GetBeaconEntry(epoch abi.ChainEpoch) []byte
+
+	// Returns a storage miner's power claim as it stood at the end of a prior epoch, and whether the miner
+	// had a claim at that epoch.
+	// The epoch is bounded as for GetBeaconEntry.
+	GetMinerPowerSnapshot(miner addr.Address, epoch abi.ChainEpoch) (PowerSnapshot, bool)
 
 	// Sends a message to another actor, returning the exit code and return value envelope.
 	// If the invoked method does not return successfully, its state changes (and that of any messages it sent in turn)
 	// will be rolled back.
 	Send(toAddr addr.Address, methodNum abi.MethodNum, params cbor.Marshaler, value abi.TokenAmount, out cbor.Er) exitcode.ExitCode
 
+	// Sends a message to another actor as for Send, but limits the gas the invoked method (and any messages it sends
+	// in turn) may consume to `gasLimit`. If the limit is exhausted the invocation fails with exitcode.SysErrOutOfGas
+	// and its state changes are rolled back, while execution of the sender continues.
+	// The gas consumed counts toward the sender's own gas as usual.
+	SendWithGasLimit(toAddr addr.Address, methodNum abi.MethodNum, params cbor.Marshaler, value abi.TokenAmount, out cbor.Er, gasLimit int64) exitcode.ExitCode
+
+	// Sends a message carrying no value to another actor, invoking the method in a read-only context.
+	// Within that context (including any messages the invoked method sends in turn) the runtime aborts any attempt
+	// to mutate actor state, create or delete actors, transfer value or emit events, with exitcode.SysErrForbidden.
+	// The invocation is thus guaranteed free of side effects, and cheaper than a Send.
+	SendReadOnly(toAddr addr.Address, methodNum abi.MethodNum, params cbor.Marshaler, out cbor.Er) exitcode.ExitCode
+
 	// Halts execution upon an error from which the receiver cannot recover. The caller will receive the exitcode and
 	// an empty return value. State changes made within this call will be rolled back.
 	// This method does not return.
@@ -97,6 +118,15 @@ type Runtime interface {
 	// passing to fmt.Errorf(msg, args...).
 	Abortf(errExitCode exitcode.ExitCode, msg string, args ...interface{})
 
+	// Halts execution as for Abortf, additionally providing data describing the failure. The runtime records the
+	// data as the return value of the failed invocation, and so in the message receipt if it is the top-level call.
+	// A calling actor receives the data through Send's out parameter alongside the error exit code, and so can act on
+	// machine-readable failure details. Callers must check the exit code before interpreting a return value.
+	// Builtin actors describe failures with the types in the builtin package, e.g. builtin.SectorsFailure, which
+	// callers may receive with builtin.SendWithFailureData.
+	// This method does not return.
+	AbortWithData(errExitCode exitcode.ExitCode, data cbor.Marshaler, msg string, args ...interface{})
+
 	// Computes an address for a new actor. The returned address is intended to uniquely refer to
 	// the actor even in the event of a chain re-org (whereas an ID-address might refer to a
 	// different actor after messages are re-ordered).
@@ -113,6 +143,15 @@ type Runtime interface {
 	// May only be called by the actor itself.
 	DeleteActor(beneficiary addr.Address)
 
+	// Replaces the code of the executing actor with `newCodeID`, preserving its address, balance and state.
+	// The upgrade must be authorized by the Init actor, which permits it only for code approved by governance
+	// and within the approval's migration window. The actor implementation for the new code must be an
+	// UpgradableActor, whose UpgradeState method is then invoked with this runtime to validate (and possibly
+	// migrate) the existing state. The remainder of the current invocation continues to execute the old code.
+	// Aborts if the upgrade is not authorized or the state is incompatible, in which case no change is made.
+	// May not be called within a state transaction or a read-only context.
+	UpgradeActor(newCodeID cid.Cid)
+
 	// Returns the total token supply in circulation at the beginning of the current epoch.
 	// The circulating supply is the sum of:
 	// - rewards emitted by the reward actor,
@@ -138,8 +177,35 @@ type Runtime interface {
 	// in total gas charged if amount of gas charged was to be changed.
 	ChargeGas(name string, gas int64, virtual int64)
 
+	// Returns the gas remaining to the current invocation: its gas limit less the gas used so far.
+	// Methods processing a batch of work can consult this to stop at a safe point, leaving the remainder for
+	// later, rather than aborting with exitcode.SysErrOutOfGas part way through.
+	GasAvailable() int64
+
+	// Returns the gas consumed so far by the top-level message, including gas charged to invocations
+	// made before the current one.
+	GasUsed() int64
+
+	// Suggests the largest batch of a kind of work that the current invocation should undertake.
+	// Methods that process a queue of work incrementally consult this to bound the work done by one message,
+	// leaving the remainder for a later one, rather than relying on fixed limits of their own.
+	// A zero limit indicates that no work should be attempted. The suggestion depends only on the kind of
+	// work and the gas available, as computed by DefaultBatchLimit, so is deterministic.
+	SuggestedBatchLimit(work BatchWork) BatchLimit
+
 	// Note events that may make debugging easier
 	Log(level rt.LogLevel, msg string, args ...interface{})
+
+	// Emits an event for observation by clients such as chain indexers.
+	// An event has no effect on execution or state, and is discarded if the emitting invocation
+	// (or any invocation that called it) does not return successfully.
+	EmitEvent(entries ...EventEntry)
+}
+
+// A named value in an emitted event.
+type EventEntry struct {
+	Key   string
+	Value cbor.Marshaler
 }
 
 // Store defines the storage module exposed to actors.
@@ -172,18 +238,33 @@ type Syscalls interface {
 	// If the address is a public-key type address, it is used directly.
 	// If it's an ID-address, the actor is looked up in state. It must be an account actor, and the
 	// public key is obtained from it's state.
+	// The signature's type must be that of the key's scheme (see builtin.CheckSignatureType).
 	VerifySignature(signature crypto.Signature, signer addr.Address, plaintext []byte) error
+	// Verifies that an aggregate BLS signature is valid for some signers, each having signed the plaintext at the
+	// same index. Signers are resolved to public keys as for VerifySignature, and must all be BLS keys.
+	// The same signer may appear more than once, signing different plaintexts.
+	VerifyAggregateSignature(signature crypto.Signature, signers []addr.Address, plaintexts [][]byte) error
 	// Hashes input data using blake2b with 256 bit output.
 	HashBlake2b(data []byte) [32]byte
 	// Computes an unsealed sector CID (CommD) from its constituent piece CIDs (CommPs) and sizes.
 	ComputeUnsealedSectorCID(reg abi.RegisteredSealProof, pieces []abi.PieceInfo) (cid.Cid, error)
 	// Verifies a sector seal proof.
 	VerifySeal(vi proof.SealVerifyInfo) error
-
+	// Verifies a batch of sector seal proofs, grouped by miner address.
+	// The result holds, for each address, whether each proof is valid, in the same order as the input. An invalid
+	// proof does not fail the batch; an error indicates that verification itself could not be performed.
 	BatchVerifySeals(vis map[addr.Address][]proof.SealVerifyInfo) (map[addr.Address][]bool, error)
+	// Verifies an aggregate of seal proofs. The aggregate is valid only if every proof within it is valid.
+	VerifyAggregateSeals(aggregate proof.AggregateSealVerifyProofAndInfos) error
+	// Verifies a proof that a sector replica has been updated from an old sealed CID to encode new data.
+	VerifyReplicaUpdate(replica proof.ReplicaUpdateInfo) error
 
 	// Verifies a proof of spacetime.
 	VerifyPoSt(vi proof.WindowPoStVerifyInfo) error
+	// Verifies a batch of proofs of spacetime.
+	// The result holds whether each proof is valid, in the same order as the input. An invalid proof does not fail
+	// the batch; an error indicates that verification itself could not be performed.
+	BatchVerifyPoSts(vis []proof.WindowPoStVerifyInfo) ([]bool, error)
 	// Verifies that two block headers provide proof of a consensus fault:
 	// - both headers mined by the same actor
 	// - headers are different
@@ -203,6 +284,7 @@ type StateHandle interface {
 	StateCreate(obj cbor.Marshaler)
 
 	// Readonly loads a readonly copy of the state into the argument.
+	// Within a transaction, the copy reflects the transaction's changes so far.
 	//
 	// Any modification to the state is illegal and will result in an abort.
 	StateReadonly(obj cbor.Unmarshaler)
@@ -227,5 +309,13 @@ type StateHandle interface {
 	// })
 	// // state.ImLoaded = false // BAD!! state is readonly outside the lambda, it will panic
 	// ```
+	//
+	// # Nesting
+	// A transaction may be opened within another, so that helper functions needing transactional semantics
+	// can be composed inside a method's transaction. The nested transaction loads the enclosing transaction's
+	// state as modified so far (rather than the committed state), and on return its changes are loaded back into
+	// the enclosing transaction's object, which must not be accessed by the nested function.
+	// Only the outermost transaction commits state, and side effects remain prohibited until it returns.
+	// An abort at any depth aborts the whole invocation, discarding the changes of all enclosing transactions.
 	StateTransaction(obj cbor.Er, f func())
 }
@@ -1,8 +1,12 @@
 package runtime
 
 import (
+	"fmt"
+
+	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/rt"
 	runtime0 "github.com/filecoin-project/specs-actors/actors/runtime"
+	cid "github.com/ipfs/go-cid"
 )
 
 // Concrete types associated with the runtime interface.
@@ -28,3 +32,167 @@ const (
 )
 
 type VMActor = rt.VMActor
+
+// An actor implementation which may replace the code of an existing actor in place.
+type UpgradableActor interface {
+	VMActor
+	// Validates that state written by an actor with code `oldCodeID` is compatible with this implementation,
+	// migrating it if necessary. Invoked by the runtime as part of an upgrade, after the code is replaced,
+	// and so executing as the new code. Aborts if the state cannot be used.
+	UpgradeState(rt Runtime, oldCodeID cid.Cid)
+}
+
+// Source from which randomness is drawn.
+type RandomnessSource int64
+
+const (
+	// The ticket chain. Randomness drawn from tickets is unique per potential fork, so processes
+	// relying on it are tied to whichever fork they choose.
+	RandomnessTickets RandomnessSource = iota
+	// The random beacon, whose values are common to all forks.
+	RandomnessBeacon
+)
+
+func (s RandomnessSource) String() string {
+	switch s {
+	case RandomnessTickets:
+		return "tickets"
+	case RandomnessBeacon:
+		return "beacon"
+	default:
+		return fmt.Sprintf("RandomnessSource(%d)", int64(s))
+	}
+}
+
+// The minimum number of epochs prior to the current epoch from which randomness may be drawn, for any source.
+// Randomness is never available for the current epoch.
+const MinRandomnessLookback = abi.ChainEpoch(1)
+
+// The maximum number of epochs prior to the current epoch from which ticket randomness may be drawn.
+// A seal commits to ticket randomness at most one day and finality before pre-commitment, and must be
+// proven within one day (plus the challenge delay) after, so four days bounds every use with margin.
+const MaxTicketLookback = abi.ChainEpoch(4 * 2880)
+
+// The maximum number of epochs prior to the current epoch from which beacon randomness may be drawn.
+// Interactive seal challenges are drawn at most a little over one day in the past, and window PoSt
+// challenges within a proving period, so two days bounds every use with margin.
+const MaxBeaconLookback = abi.ChainEpoch(2 * 2880)
+
+// Checks that randomness may be drawn from a source at randEpoch when the current epoch is currEpoch.
+// Epochs prior to genesis address the genesis value, and are treated as epoch zero for the maximum lookback.
+func CheckRandomnessLookback(source RandomnessSource, currEpoch, randEpoch abi.ChainEpoch) error {
+	var maxLookback abi.ChainEpoch
+	switch source {
+	case RandomnessTickets:
+		maxLookback = MaxTicketLookback
+	case RandomnessBeacon:
+		maxLookback = MaxBeaconLookback
+	default:
+		return fmt.Errorf("unknown randomness source %d", source)
+	}
+	if randEpoch > currEpoch-MinRandomnessLookback {
+		return fmt.Errorf("randomness epoch %d from %s must be at least %d prior to current epoch %d",
+			randEpoch, source, MinRandomnessLookback, currEpoch)
+	}
+	effectiveEpoch := randEpoch
+	if effectiveEpoch < 0 {
+		effectiveEpoch = 0
+	}
+	if currEpoch-effectiveEpoch > maxLookback {
+		return fmt.Errorf("randomness epoch %d from %s is more than %d prior to current epoch %d",
+			randEpoch, source, maxLookback, currEpoch)
+	}
+	return nil
+}
+
+// A storage miner's power claim, as recorded by the runtime at some epoch.
+type PowerSnapshot struct {
+	RawBytePower    abi.StoragePower
+	QualityAdjPower abi.StoragePower
+}
+
+// The minimum number of epochs prior to the current epoch for which historical values may be read.
+// Values for the current epoch are not final until the epoch ends.
+const MinHistoricalLookback = abi.ChainEpoch(1)
+
+// The maximum number of epochs prior to the current epoch for which historical values may be read.
+// This bounds the history the runtime must retain. Window PoSt disputes, the longest-lived use, must be raised
+// within a couple of proving periods of the proof, so two days covers every use with margin.
+const MaxHistoricalLookback = abi.ChainEpoch(2 * 2880)
+
+// Checks that historical values may be read for epoch when the current epoch is currEpoch.
+func CheckHistoricalLookback(currEpoch, epoch abi.ChainEpoch) error {
+	if epoch < 0 {
+		return fmt.Errorf("historical epoch %d is prior to genesis", epoch)
+	}
+	if epoch > currEpoch-MinHistoricalLookback {
+		return fmt.Errorf("historical epoch %d must be at least %d prior to current epoch %d",
+			epoch, MinHistoricalLookback, currEpoch)
+	}
+	if currEpoch-epoch > MaxHistoricalLookback {
+		return fmt.Errorf("historical epoch %d is more than %d prior to current epoch %d",
+			epoch, MaxHistoricalLookback, currEpoch)
+	}
+	return nil
+}
+
+// A kind of work that actor methods perform in batches, processing a queue incrementally over many messages.
+// Work that cannot be deferred, such as the delivery of the storage power actor's deferred cron events, is not
+// batched.
+type BatchWork int64
+
+const (
+	// Sectors terminated early and awaiting processing by a storage miner, grouped by partition.
+	BatchEarlyTerminations BatchWork = iota
+)
+
+func (w BatchWork) String() string {
+	switch w {
+	case BatchEarlyTerminations:
+		return "early terminations"
+	default:
+		return fmt.Sprintf("BatchWork(%d)", int64(w))
+	}
+}
+
+// A bound on the size of a batch of work.
+type BatchLimit struct {
+	// The maximum number of items to process.
+	Items uint64
+	// The maximum number of groups (e.g. partitions, epochs) across which the items may lie.
+	Groups uint64
+}
+
+func (l BatchLimit) IsZero() bool {
+	return l.Items == 0 || l.Groups == 0
+}
+
+type batchCost struct {
+	itemGas   int64  // Gas consumed processing one item, including its share of the per-batch overhead.
+	maxItems  uint64 // Items in the largest batch, bounding the state loaded by one message.
+	maxGroups uint64 // Groups in the largest batch.
+}
+
+var batchCosts = map[BatchWork]batchCost{
+	// A full batch of 10,000 sectors across up to 3,000 partitions (a full deadline) requires 4e9 gas.
+	BatchEarlyTerminations: {itemGas: 400_000, maxItems: 10_000, maxGroups: 3_000},
+}
+
+// Computes the batch limit suggested for a kind of work given the gas available to an invocation.
+// The limit is a full batch if the gas suffices, and shrinks in proportion to the gas otherwise,
+// down to zero when not even a single item can be afforded.
+func DefaultBatchLimit(work BatchWork, gasAvailable int64) BatchLimit {
+	cost, ok := batchCosts[work]
+	if !ok || gasAvailable <= 0 {
+		return BatchLimit{}
+	}
+	items := cost.maxItems
+	if affordable := uint64(gasAvailable / cost.itemGas); affordable < items {
+		items = affordable
+	}
+	groups := cost.maxGroups
+	if items < groups {
+		groups = items
+	}
+	return BatchLimit{Items: items, Groups: groups}
+}
@@ -0,0 +1,65 @@
+package runtime_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/runtime"
+)
+
+func TestCheckRandomnessLookback(t *testing.T) {
+	curr := abi.ChainEpoch(100_000)
+
+	t.Run("bounds per source", func(t *testing.T) {
+		for source, max := range map[runtime.RandomnessSource]abi.ChainEpoch{ // nolint:nomaprange
+			runtime.RandomnessTickets: runtime.MaxTicketLookback,
+			runtime.RandomnessBeacon:  runtime.MaxBeaconLookback,
+		} {
+			assert.NoError(t, runtime.CheckRandomnessLookback(source, curr, curr-runtime.MinRandomnessLookback))
+			assert.NoError(t, runtime.CheckRandomnessLookback(source, curr, curr-max))
+
+			assert.Error(t, runtime.CheckRandomnessLookback(source, curr, curr))
+			assert.Error(t, runtime.CheckRandomnessLookback(source, curr, curr+1))
+			assert.Error(t, runtime.CheckRandomnessLookback(source, curr, curr-max-1))
+		}
+	})
+
+	t.Run("epochs before genesis address genesis", func(t *testing.T) {
+		assert.NoError(t, runtime.CheckRandomnessLookback(runtime.RandomnessBeacon, 10, -1000))
+		assert.Error(t, runtime.CheckRandomnessLookback(runtime.RandomnessBeacon, runtime.MaxBeaconLookback+1, -1000))
+	})
+
+	t.Run("unknown source", func(t *testing.T) {
+		assert.Error(t, runtime.CheckRandomnessLookback(runtime.RandomnessSource(2), curr, curr-1))
+	})
+}
+
+func TestCheckHistoricalLookback(t *testing.T) {
+	curr := abi.ChainEpoch(100_000)
+
+	assert.NoError(t, runtime.CheckHistoricalLookback(curr, curr-runtime.MinHistoricalLookback))
+	assert.NoError(t, runtime.CheckHistoricalLookback(curr, curr-runtime.MaxHistoricalLookback))
+	assert.NoError(t, runtime.CheckHistoricalLookback(10, 0))
+
+	assert.Error(t, runtime.CheckHistoricalLookback(curr, curr))
+	assert.Error(t, runtime.CheckHistoricalLookback(curr, curr+1))
+	assert.Error(t, runtime.CheckHistoricalLookback(curr, curr-runtime.MaxHistoricalLookback-1))
+	assert.Error(t, runtime.CheckHistoricalLookback(10, -1))
+}
+
+func TestDefaultBatchLimit(t *testing.T) {
+	full := runtime.DefaultBatchLimit(runtime.BatchEarlyTerminations, math.MaxInt64)
+	assert.Equal(t, runtime.BatchLimit{Items: 10_000, Groups: 3_000}, full)
+	assert.Equal(t, full, runtime.DefaultBatchLimit(runtime.BatchEarlyTerminations, 4e9))
+
+	// The limit shrinks with the gas available.
+	assert.Equal(t, runtime.BatchLimit{Items: 5_000, Groups: 3_000}, runtime.DefaultBatchLimit(runtime.BatchEarlyTerminations, 2e9))
+	assert.Equal(t, runtime.BatchLimit{Items: 1, Groups: 1}, runtime.DefaultBatchLimit(runtime.BatchEarlyTerminations, 400_000))
+	assert.True(t, runtime.DefaultBatchLimit(runtime.BatchEarlyTerminations, 399_999).IsZero())
+	assert.True(t, runtime.DefaultBatchLimit(runtime.BatchEarlyTerminations, 0).IsZero())
+
+	assert.True(t, runtime.DefaultBatchLimit(runtime.BatchWork(99), math.MaxInt64).IsZero())
+}
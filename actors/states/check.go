@@ -15,7 +15,10 @@ import (
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/market"
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/miner"
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/multisig"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/paramreg"
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/paych"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/reward"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/system"
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/verifreg"
 )
 
@@ -25,25 +28,37 @@ import (
 func CheckStateInvariants(tree *Tree, expectedBalanceTotal abi.TokenAmount, priorEpoch abi.ChainEpoch) (*builtin.MessageAccumulator, error) {
 	acc := &builtin.MessageAccumulator{}
 	totalFIl := big.Zero()
+	var systemSummary *system.StateSummary
 	var initSummary *init_.StateSummary
 	var cronSummary *cron.StateSummary
 	var verifregSummary *verifreg.StateSummary
 	var marketSummary *market.StateSummary
 	var accountSummaries []*account.StateSummary
 	var powerSummary *power.StateSummary
+	var rewardSummary *reward.StateSummary
 	var paychSummaries []*paych.StateSummary
 	var multisigSummaries []*multisig.StateSummary
+	var paramregSummary *paramreg.StateSummary
 	minerSummaries := make(map[addr.Address]*miner.StateSummary)
+	var actorAddrs []addr.Address
 
 	if err := tree.ForEach(func(key addr.Address, actor *Actor) error {
 		acc := acc.WithPrefix("%v ", key) // Intentional shadow
 		if key.Protocol() != addr.ID {
 			acc.Addf("unexpected address protocol in state tree root: %v", key)
 		}
+		actorAddrs = append(actorAddrs, key)
 		totalFIl = big.Add(totalFIl, actor.Balance)
 
 		switch actor.Code {
 		case builtin.SystemActorCodeID:
+			var st system.State
+			if err := tree.Store.Get(tree.Store.Context(), actor.Head, &st); err != nil {
+				return err
+			}
+			summary, msgs := system.CheckStateInvariants(&st)
+			acc.WithPrefix("system: ").AddAll(msgs)
+			systemSummary = summary
 
 		case builtin.InitActorCodeID:
 			var st init_.State
@@ -61,7 +76,7 @@ func CheckStateInvariants(tree *Tree, expectedBalanceTotal abi.TokenAmount, prio
 			if err := tree.Store.Get(tree.Store.Context(), actor.Head, &st); err != nil {
 				return err
 			}
-			if summary, msgs, err := cron.CheckStateInvariants(&st, tree.Store); err != nil {
+			if summary, msgs, err := cron.CheckStateInvariants(&st, tree.Store, actor.Balance); err != nil {
 				return err
 			} else {
 				acc.WithPrefix("cron: ").AddAll(msgs)
@@ -135,6 +150,25 @@ func CheckStateInvariants(tree *Tree, expectedBalanceTotal abi.TokenAmount, prio
 			}
 
 		case builtin.RewardActorCodeID:
+			var st reward.State
+			if err := tree.Store.Get(tree.Store.Context(), actor.Head, &st); err != nil {
+				return err
+			}
+			summary, msgs := reward.CheckStateInvariants(&st, priorEpoch)
+			acc.WithPrefix("reward: ").AddAll(msgs)
+			rewardSummary = summary
+
+		case builtin.ParameterRegistryActorCodeID:
+			var st paramreg.State
+			if err := tree.Store.Get(tree.Store.Context(), actor.Head, &st); err != nil {
+				return err
+			}
+			if summary, msgs, err := paramreg.CheckStateInvariants(&st, tree.Store); err != nil {
+				return err
+			} else {
+				acc.WithPrefix("paramreg: ").AddAll(msgs)
+				paramregSummary = summary
+			}
 
 		case builtin.VerifiedRegistryActorCodeID:
 			var st verifreg.State
@@ -160,13 +194,15 @@ func CheckStateInvariants(tree *Tree, expectedBalanceTotal abi.TokenAmount, prio
 	// Perform cross-actor checks from state summaries here.
 	//
 
+	CheckSystemAgainstInit(acc, systemSummary, initSummary)
+	CheckActorsAgainstInit(acc, actorAddrs, initSummary)
 	CheckMinersAgainstPower(acc, minerSummaries, powerSummary)
 	CheckDealStatesAgainstSectors(acc, minerSummaries, marketSummary)
+	CheckParameterRegistryAgainstPolicy(acc, paramregSummary)
 
-	_ = initSummary
 	_ = verifregSummary
 	_ = cronSummary
-	_ = marketSummary
+	_ = rewardSummary
 
 	if !totalFIl.Equals(expectedBalanceTotal) {
 		acc.Addf("total token balance is %v, expected %v", totalFIl, expectedBalanceTotal)
@@ -175,7 +211,62 @@ func CheckStateInvariants(tree *Tree, expectedBalanceTotal abi.TokenAmount, prio
 	return acc, nil
 }
 
+func CheckSystemAgainstInit(acc *builtin.MessageAccumulator, systemSummary *system.StateSummary, initSummary *init_.StateSummary) {
+	if systemSummary == nil || initSummary == nil {
+		acc.Addf("missing system or init actor state")
+		return
+	}
+	acc.Require(systemSummary.NetworkName == initSummary.NetworkName,
+		"system network name %s does not match init network name %s", systemSummary.NetworkName, initSummary.NetworkName)
+}
+
+// Checks that every actor's ID, and every ID to which the init actor maps an address, has been allocated by the
+// init actor.
+func CheckActorsAgainstInit(acc *builtin.MessageAccumulator, actorAddrs []addr.Address, initSummary *init_.StateSummary) {
+	if initSummary == nil {
+		return // Missing init actor noted by CheckSystemAgainstInit
+	}
+	for _, a := range actorAddrs {
+		id, err := addr.IDFromAddress(a)
+		if err != nil {
+			continue // Non-ID address noted when traversing the tree
+		}
+		acc.Require(abi.ActorID(id) < initSummary.NextID, "actor %v has ID not yet allocated by init, next ID %d", a, initSummary.NextID)
+	}
+	for a, id := range initSummary.AddrIDs { // nolint:nomaprange
+		acc.Require(id < initSummary.NextID, "init maps %v to ID %d, not yet allocated, next ID %d", a, id, initSummary.NextID)
+	}
+}
+
+// Checks that the parameter registry records the values of the policy parameters the actors read.
+func CheckParameterRegistryAgainstPolicy(acc *builtin.MessageAccumulator, paramregSummary *paramreg.StateSummary) {
+	if paramregSummary == nil {
+		acc.Addf("missing parameter registry state")
+		return
+	}
+	for _, p := range paramreg.PolicyParameters() {
+		value, found := paramregSummary.Parameters[p.Name]
+		acc.Require(found, "parameter registry has no value for %s", p.Name)
+		acc.Require(!found || value.Equals(p.Value), "parameter registry value %v for %s does not match policy value %v", value, p.Name, p.Value)
+	}
+}
+
 func CheckMinersAgainstPower(acc *builtin.MessageAccumulator, minerSummaries map[addr.Address]*miner.StateSummary, powerSummary *power.StateSummary) {
+	if powerSummary == nil {
+		acc.Addf("missing power actor state")
+		return
+	}
+
+	// Every claim, and so every miner counted towards the power totals, must belong to a miner.
+	for addr := range powerSummary.Claims { // nolint:nomaprange
+		_, found := minerSummaries[addr]
+		acc.Require(found, "power claim for %v, which is not a miner", addr)
+	}
+	for addr := range powerSummary.Proofs { // nolint:nomaprange
+		_, found := minerSummaries[addr]
+		acc.Require(found, "batched proofs for %v, which is not a miner", addr)
+	}
+
 	for addr, minerSummary := range minerSummaries { // nolint:nomaprange
 
 		// check claim
@@ -216,6 +307,24 @@ func CheckMinersAgainstPower(acc *builtin.MessageAccumulator, minerSummaries map
 }
 
 func CheckDealStatesAgainstSectors(acc *builtin.MessageAccumulator, minerSummaries map[addr.Address]*miner.StateSummary, marketSummary *market.StateSummary) {
+	if marketSummary == nil {
+		acc.Addf("missing market actor state")
+		return
+	}
+
+	// Check that every deal still in the market and referenced by a sector was made with the sector's miner and
+	// has been activated.
+	for minerAddr, minerSummary := range minerSummaries { // nolint:nomaprange
+		for dealID := range minerSummary.Deals { // nolint:nomaprange
+			deal, found := marketSummary.Deals[dealID]
+			if !found {
+				continue // The deal may have expired or been terminated
+			}
+			acc.Require(deal.Provider == minerAddr, "deal %d in sector of miner %v has provider %v", dealID, minerAddr, deal.Provider)
+			acc.Require(deal.SectorStartEpoch != abi.ChainEpoch(-1), "deal %d in sector of miner %v not activated", dealID, minerAddr)
+		}
+	}
+
 	// Check that all active deals are included within a non-terminated sector.
 	// We cannot check that all deals referenced within a sector are in the market, because deals
 	// can be terminated independently of the sector in which they are included.
@@ -0,0 +1,147 @@
+package states_test
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	init_ "github.com/filecoin-project/specs-actors/v2/actors/builtin/init"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/miner"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/paramreg"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/power"
+	"github.com/filecoin-project/specs-actors/v2/actors/states"
+	"github.com/filecoin-project/specs-actors/v2/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/v2/support/ipld"
+	tutil "github.com/filecoin-project/specs-actors/v2/support/testing"
+	vm "github.com/filecoin-project/specs-actors/v2/support/vm"
+)
+
+func TestCheckStateInvariants(t *testing.T) {
+	ctx := context.Background()
+	v := vm.NewVMWithSingletons(ctx, t)
+	vm.CreateAccounts(ctx, t, v, 2, big.Mul(big.NewInt(100), builtin.TokenPrecision), 93837778)
+	tree, err := v.GetStateTree()
+	require.NoError(t, err)
+	total, err := v.GetTotalActorBalance()
+	require.NoError(t, err)
+
+	t.Run("valid state", func(t *testing.T) {
+		acc, err := states.CheckStateInvariants(tree, total, v.GetEpoch())
+		require.NoError(t, err)
+		assert.True(t, acc.IsEmpty(), strings.Join(acc.Messages(), "\n"))
+	})
+
+	t.Run("token balance not conserved", func(t *testing.T) {
+		acc, err := states.CheckStateInvariants(tree, big.Add(total, big.NewInt(1)), v.GetEpoch())
+		require.NoError(t, err)
+		require.Len(t, acc.Messages(), 1)
+		assert.Contains(t, acc.Messages()[0], "total token balance")
+	})
+
+	t.Run("actor ID not allocated by init", func(t *testing.T) {
+		tree, err := v.GetStateTree()
+		require.NoError(t, err)
+		initActor, found, err := tree.GetActor(builtin.InitActorAddr)
+		require.NoError(t, err)
+		require.True(t, found)
+		var st init_.State
+		require.NoError(t, tree.Store.Get(ctx, initActor.Head, &st))
+
+		st.NextID = abi.ActorID(builtin.FirstNonSingletonActorId)
+		initActor.Head, err = tree.Store.Put(ctx, &st)
+		require.NoError(t, err)
+		require.NoError(t, tree.SetActor(builtin.InitActorAddr, initActor))
+
+		acc, err := states.CheckStateInvariants(tree, total, v.GetEpoch())
+		require.NoError(t, err)
+		require.False(t, acc.IsEmpty())
+		for _, msg := range acc.Messages() {
+			assert.Contains(t, msg, "not yet allocated")
+		}
+	})
+
+	t.Run("power claim without miner", func(t *testing.T) {
+		tree, err := v.GetStateTree()
+		require.NoError(t, err)
+		powerActor, found, err := tree.GetActor(builtin.StoragePowerActorAddr)
+		require.NoError(t, err)
+		require.True(t, found)
+		var st power.State
+		require.NoError(t, tree.Store.Get(ctx, powerActor.Head, &st))
+
+		claims, err := adt.AsMap(tree.Store, st.Claims)
+		require.NoError(t, err)
+		notMiner := tutil.NewIDAddr(t, builtin.FirstNonSingletonActorId)
+		require.NoError(t, claims.Put(abi.AddrKey(notMiner), &power.Claim{
+			SealProofType:   abi.RegisteredSealProof_StackedDrg32GiBV1,
+			RawBytePower:    big.Zero(),
+			QualityAdjPower: big.Zero(),
+		}))
+		st.Claims, err = claims.Root()
+		require.NoError(t, err)
+		powerActor.Head, err = tree.Store.Put(ctx, &st)
+		require.NoError(t, err)
+		require.NoError(t, tree.SetActor(builtin.StoragePowerActorAddr, powerActor))
+
+		acc, err := states.CheckStateInvariants(tree, total, v.GetEpoch())
+		require.NoError(t, err)
+		require.Len(t, acc.Messages(), 1)
+		assert.Contains(t, acc.Messages()[0], "which is not a miner")
+	})
+
+	t.Run("parameter registry differs from policy", func(t *testing.T) {
+		tree, err := v.GetStateTree()
+		require.NoError(t, err)
+		registryActor, found, err := tree.GetActor(builtin.ParameterRegistryActorAddr)
+		require.NoError(t, err)
+		require.True(t, found)
+		var st paramreg.State
+		require.NoError(t, tree.Store.Get(ctx, registryActor.Head, &st))
+
+		changed := big.NewInt(int64(miner.WPoStProvingPeriod) + 1)
+		require.NoError(t, st.SetParameter(tree.Store, "miner/WPoStProvingPeriod", changed, v.GetEpoch(), network.VersionMax))
+		registryActor.Head, err = tree.Store.Put(ctx, &st)
+		require.NoError(t, err)
+		require.NoError(t, tree.SetActor(builtin.ParameterRegistryActorAddr, registryActor))
+
+		acc, err := states.CheckStateInvariants(tree, total, v.GetEpoch())
+		require.NoError(t, err)
+		require.Len(t, acc.Messages(), 1)
+		assert.Contains(t, acc.Messages()[0], "does not match policy value")
+	})
+}
+
+// Checks the state tree at the root of the CAR file named by the environment variable SPECS_ACTORS_STATE_CAR,
+// if set, at the epoch named by SPECS_ACTORS_STATE_EPOCH, so that invariants may be checked against real state,
+// e.g. a mainnet snapshot. All tokens are expected to be held by actors in the tree.
+func TestCheckStateInvariantsAtRoot(t *testing.T) {
+	path := os.Getenv("SPECS_ACTORS_STATE_CAR")
+	if path == "" {
+		t.Skip("SPECS_ACTORS_STATE_CAR not set")
+	}
+	epoch, err := strconv.ParseInt(os.Getenv("SPECS_ACTORS_STATE_EPOCH"), 10, 64)
+	require.NoError(t, err, "SPECS_ACTORS_STATE_EPOCH must be set to the state's epoch")
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+	store := ipld.NewADTStore(context.Background())
+	roots, err := ipld.ReadCAR(store, f)
+	require.NoError(t, err)
+	require.Len(t, roots, 1, "CAR must have the state tree as its only root")
+
+	tree, err := states.LoadTree(store, roots[0])
+	require.NoError(t, err)
+	acc, err := states.CheckStateInvariants(tree, builtin.TotalFilecoin, abi.ChainEpoch(epoch))
+	require.NoError(t, err)
+	assert.True(t, acc.IsEmpty(), strings.Join(acc.Messages(), "\n"))
+}
@@ -0,0 +1,297 @@
+package inspection
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"strconv"
+
+	addr "github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-bitfield"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/ipfs/go-cid"
+	cbg "github.com/whyrusleeping/cbor-gen"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/util/adt"
+)
+
+// A summary of a HAMT or AMT: its size, and its entries if requested.
+// Entries are keyed by the HAMT key or AMT index formatted as a string.
+type Collection struct {
+	Size    uint64
+	Entries map[string]interface{} `json:",omitempty"`
+}
+
+// An entry of a collection in a dump, keyed by the HAMT key or AMT index formatted as a string.
+type Entry struct {
+	Key   string
+	Value interface{}
+}
+
+// Parses a HAMT key into a readable string.
+type keyParser func(key string) (string, error)
+
+func addrKey(key string) (string, error) {
+	a, err := addr.NewFromBytes([]byte(key))
+	if err != nil {
+		return "", err
+	}
+	return a.String(), nil
+}
+
+func intKey(key string) (string, error) {
+	i, err := abi.ParseIntKey(key)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(i, 10), nil
+}
+
+func uintKey(key string) (string, error) {
+	u, err := abi.ParseUIntKey(key)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatUint(u, 10), nil
+}
+
+func cidKey(key string) (string, error) {
+	c, err := cid.Cast([]byte(key))
+	if err != nil {
+		return "", err
+	}
+	return c.String(), nil
+}
+
+// Parses a key of a pair of ID addresses (see verifreg.AddrPairKey) into the addresses separated by a comma.
+func addrPairKey(key string) (string, error) {
+	// An ID address is its protocol byte followed by the ID as a uvarint, so its length can be read from the key.
+	if len(key) < 2 || key[0] != addr.ID {
+		return "", xerrors.Errorf("key does not begin with an ID address")
+	}
+	_, n := binary.Uvarint([]byte(key[1:]))
+	if n <= 0 {
+		return "", xerrors.Errorf("invalid ID in key")
+	}
+	first, err := addrKey(key[:1+n])
+	if err != nil {
+		return "", err
+	}
+	second, err := addrKey(key[1+n:])
+	if err != nil {
+		return "", err
+	}
+	return first + "," + second, nil
+}
+
+// A CID value held in a collection, e.g. the root of an inner collection of a multimap.
+// It serializes to JSON as a CID.
+type cidValue struct {
+	cid.Cid
+}
+
+func (v *cidValue) UnmarshalCBOR(r io.Reader) error {
+	c, err := cbg.ReadCid(r)
+	if err != nil {
+		return err
+	}
+	v.Cid = c
+	return nil
+}
+
+// Summarizes a HAMT, decoding each entry into a new value if entries are requested.
+func summarizeMap(store adt.Store, root cid.Cid, parseKey keyParser, newValue func() cbg.CBORUnmarshaler, opts Options) (*Collection, error) {
+	m, err := adt.AsMap(store, root)
+	if err != nil {
+		return nil, err
+	}
+	summary := &Collection{}
+	if !opts.Entries {
+		var value cbg.Deferred
+		err = m.ForEach(&value, func(string) error {
+			summary.Size++
+			return nil
+		})
+		return summary, err
+	}
+
+	summary.Entries = map[string]interface{}{}
+	var raw cbg.Deferred
+	err = m.ForEach(&raw, func(k string) error {
+		key, err := parseKey(k)
+		if err != nil {
+			return xerrors.Errorf("failed to parse key %x: %w", k, err)
+		}
+		value, err := decodeValue(&raw, newValue)
+		if err != nil {
+			return xerrors.Errorf("failed to decode value at %s: %w", key, err)
+		}
+		summary.Entries[key] = value
+		summary.Size++
+		return nil
+	})
+	return summary, err
+}
+
+// Summarizes an AMT, decoding each entry into a new value if entries are requested.
+func summarizeArray(store adt.Store, root cid.Cid, newValue func() cbg.CBORUnmarshaler, opts Options) (*Collection, error) {
+	arr, err := adt.AsArray(store, root)
+	if err != nil {
+		return nil, err
+	}
+	summary := &Collection{Size: arr.Length()}
+	if !opts.Entries {
+		return summary, nil
+	}
+
+	summary.Entries = map[string]interface{}{}
+	var raw cbg.Deferred
+	err = arr.ForEach(&raw, func(i int64) error {
+		value, err := decodeValue(&raw, newValue)
+		if err != nil {
+			return xerrors.Errorf("failed to decode value at %d: %w", i, err)
+		}
+		summary.Entries[strconv.FormatInt(i, 10)] = value
+		return nil
+	})
+	return summary, err
+}
+
+func decodeValue(raw *cbg.Deferred, newValue func() cbg.CBORUnmarshaler) (cbg.CBORUnmarshaler, error) {
+	value := newValue()
+	if err := value.UnmarshalCBOR(bytes.NewReader(raw.Raw)); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// Decodes an entry of a collection into the value to dump for it.
+type valueDecoder func(raw *cbg.Deferred) (interface{}, error)
+
+// Decodes entries into new values.
+func decodeAs(newValue func() cbg.CBORUnmarshaler) valueDecoder {
+	return func(raw *cbg.Deferred) (interface{}, error) {
+		return decodeValue(raw, newValue)
+	}
+}
+
+// Decodes entries which are bitfields into the numbers they hold.
+func decodeBitField(raw *cbg.Deferred) (interface{}, error) {
+	var bf bitfield.BitField
+	if err := bf.UnmarshalCBOR(bytes.NewReader(raw.Raw)); err != nil {
+		return nil, err
+	}
+	return dumpBitField(bf)
+}
+
+// Decodes entries which are roots of sets, e.g. in a multimap, into the keys of the sets.
+func dumpSet(store adt.Store, parseKey keyParser) valueDecoder {
+	return func(raw *cbg.Deferred) (interface{}, error) {
+		var root cidValue
+		if err := root.UnmarshalCBOR(bytes.NewReader(raw.Raw)); err != nil {
+			return nil, err
+		}
+		set, err := adt.AsSet(store, root.Cid)
+		if err != nil {
+			return nil, err
+		}
+		keys := []string{}
+		err = set.ForEach(func(k string) error {
+			key, err := parseKey(k)
+			if err != nil {
+				return xerrors.Errorf("failed to parse key %x: %w", k, err)
+			}
+			keys = append(keys, key)
+			return nil
+		})
+		return keys, err
+	}
+}
+
+// Decodes entries which are roots of AMTs, e.g. in a multimap, into the entries of the AMTs.
+func dumpInnerArray(store adt.Store, newValue func() cbg.CBORUnmarshaler) valueDecoder {
+	return func(raw *cbg.Deferred) (interface{}, error) {
+		var root cidValue
+		if err := root.UnmarshalCBOR(bytes.NewReader(raw.Raw)); err != nil {
+			return nil, err
+		}
+		return dumpArray(store, root.Cid, decodeAs(newValue))
+	}
+}
+
+// Decodes entries which are roots of AMTs holding values only as their indices, e.g. in an index of deals, into
+// the indices of the AMTs.
+func dumpInnerArrayIndices(store adt.Store) valueDecoder {
+	return func(raw *cbg.Deferred) (interface{}, error) {
+		var root cidValue
+		if err := root.UnmarshalCBOR(bytes.NewReader(raw.Raw)); err != nil {
+			return nil, err
+		}
+		arr, err := adt.AsArray(store, root.Cid)
+		if err != nil {
+			return nil, err
+		}
+		indices := []string{}
+		err = arr.ForEach(nil, func(i int64) error {
+			indices = append(indices, strconv.FormatInt(i, 10))
+			return nil
+		})
+		return indices, err
+	}
+}
+
+// Dumps the entries of a HAMT, in the HAMT's order.
+func dumpMap(store adt.Store, root cid.Cid, parseKey keyParser, decode valueDecoder) ([]*Entry, error) {
+	m, err := adt.AsMap(store, root)
+	if err != nil {
+		return nil, err
+	}
+	entries := []*Entry{}
+	var raw cbg.Deferred
+	err = m.ForEach(&raw, func(k string) error {
+		key, err := parseKey(k)
+		if err != nil {
+			return xerrors.Errorf("failed to parse key %x: %w", k, err)
+		}
+		value, err := decode(&raw)
+		if err != nil {
+			return xerrors.Errorf("failed to decode value at %s: %w", key, err)
+		}
+		entries = append(entries, &Entry{Key: key, Value: value})
+		return nil
+	})
+	return entries, err
+}
+
+// Dumps the entries of an AMT, in order of index.
+func dumpArray(store adt.Store, root cid.Cid, decode valueDecoder) ([]*Entry, error) {
+	arr, err := adt.AsArray(store, root)
+	if err != nil {
+		return nil, err
+	}
+	entries := []*Entry{}
+	var raw cbg.Deferred
+	err = arr.ForEach(&raw, func(i int64) error {
+		value, err := decode(&raw)
+		if err != nil {
+			return xerrors.Errorf("failed to decode value at %d: %w", i, err)
+		}
+		entries = append(entries, &Entry{Key: strconv.FormatInt(i, 10), Value: value})
+		return nil
+	})
+	return entries, err
+}
+
+// Expands a bitfield into the numbers it holds, in increasing order.
+func dumpBitField(bf bitfield.BitField) ([]uint64, error) {
+	bits, err := bf.All(math.MaxUint64)
+	if err != nil {
+		return nil, err
+	}
+	if bits == nil {
+		bits = []uint64{}
+	}
+	return bits, nil
+}
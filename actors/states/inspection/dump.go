@@ -0,0 +1,577 @@
+package inspection
+
+import (
+	"encoding/json"
+
+	addr "github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-bitfield"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/ipfs/go-cid"
+	cbg "github.com/whyrusleeping/cbor-gen"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/account"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/cron"
+	init_ "github.com/filecoin-project/specs-actors/v2/actors/builtin/init"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/market"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/miner"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/multisig"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/paramreg"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/paych"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/power"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/reward"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/system"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/verifreg"
+	"github.com/filecoin-project/specs-actors/v2/actors/runtime/proof"
+	"github.com/filecoin-project/specs-actors/v2/actors/states"
+	"github.com/filecoin-project/specs-actors/v2/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/v2/actors/util/smoothing"
+)
+
+// Dumps are fully-resolved views of the state of builtin actors, for debugging failed tests and state issues.
+// Unlike a summary, a dump expands every collection into its entries, including the collections whose roots are
+// held within entries, every bitfield into the numbers it holds, and the CIDs of state objects into the objects.
+// A dump marshals with MarshalDump to JSON which is the same for the same state, so dumps may be diffed.
+//
+// Dumping loads the whole state, which for mainnet miners and the market is very large.
+
+type TreeDump struct {
+	Actors []*ActorDump // In state tree order
+}
+
+type ActorDump struct {
+	Address    addr.Address
+	Code       string // Name of the actor's code, e.g. "fil/2/storageminer"
+	Head       cid.Cid
+	CallSeqNum uint64
+	Balance    abi.TokenAmount
+	// A dump of the actor's state, of the type dumping the actor's code.
+	// Actors whose state holds no collections (account, cron, reward and system) are dumped as the state itself.
+	State interface{}
+}
+
+type InitDump struct {
+	NetworkName      string
+	NextID           abi.ActorID
+	AddressMap       []*Entry
+	RobustAddressMap []*Entry
+	Tombstones       []*Entry
+	ExecAllowlist    []init_.ExecPermission
+	UpgradeApprovals []init_.UpgradeApproval
+}
+
+type MarketDump struct {
+	Proposals                     []*Entry
+	States                        []*Entry
+	PendingProposals              []*Entry
+	EscrowTable                   []*Entry
+	LockedTable                   []*Entry
+	NextID                        abi.DealID
+	DealOpsByEpoch                []*Entry // Values are deal IDs
+	LastCron                      abi.ChainEpoch
+	TotalClientLockedCollateral   abi.TokenAmount
+	TotalProviderLockedCollateral abi.TokenAmount
+	TotalClientStorageFee         abi.TokenAmount
+	DealsByProvider               []*Entry // Values are deal IDs
+}
+
+type MinerDump struct {
+	Info                      *miner.MinerInfo
+	PreCommitDeposits         abi.TokenAmount
+	LockedFunds               abi.TokenAmount
+	VestingFunds              []miner.VestingFund
+	FeeDebt                   abi.TokenAmount
+	InitialPledge             abi.TokenAmount
+	PreCommittedSectors       []*Entry
+	PreCommittedSectorsExpiry []*Entry // Values are sector numbers
+	AllocatedSectors          []uint64
+	Sectors                   []*Entry
+	ProvingPeriodStart        abi.ChainEpoch
+	CurrentDeadline           uint64
+	Deadlines                 []*DeadlineDump // Indexed by deadline
+	EarlyTerminations         []uint64        // Deadline indices
+}
+
+type DeadlineDump struct {
+	Partitions        []*Entry // Values are PartitionDumps
+	ExpirationsEpochs []*Entry // Values are partition numbers
+	PostSubmissions   []uint64
+	EarlyTerminations []uint64
+	LiveSectors       uint64
+	TotalSectors      uint64
+	FaultyPower       miner.PowerPair
+}
+
+type PartitionDump struct {
+	Sectors           []uint64
+	Unproven          []uint64
+	Faults            []uint64
+	Recoveries        []uint64
+	Terminated        []uint64
+	ExpirationsEpochs []*Entry // Values are ExpirationSetDumps
+	EarlyTerminated   []*Entry // Values are sector numbers
+	LivePower         miner.PowerPair
+	UnprovenPower     miner.PowerPair
+	FaultyPower       miner.PowerPair
+	RecoveringPower   miner.PowerPair
+}
+
+type ExpirationSetDump struct {
+	OnTimeSectors []uint64
+	EarlySectors  []uint64
+	OnTimePledge  abi.TokenAmount
+	ActivePower   miner.PowerPair
+	FaultyPower   miner.PowerPair
+}
+
+type MultisigDump struct {
+	Signers               []addr.Address
+	NumApprovalsThreshold uint64
+	NextTxnID             multisig.TxnID
+	InitialBalance        abi.TokenAmount
+	StartEpoch            abi.ChainEpoch
+	UnlockDuration        abi.ChainEpoch
+	PendingTxns           []*Entry
+}
+
+type ParamregDump struct {
+	Parameters map[string]big.Int
+	History    []*Entry
+}
+
+type PaychDump struct {
+	From            addr.Address
+	To              addr.Address
+	ToSend          abi.TokenAmount
+	SettlingAt      abi.ChainEpoch
+	MinSettleHeight abi.ChainEpoch
+	LaneStates      []*Entry
+}
+
+type PowerDump struct {
+	TotalRawBytePower                 abi.StoragePower
+	TotalBytesCommitted               abi.StoragePower
+	TotalQualityAdjPower              abi.StoragePower
+	TotalQABytesCommitted             abi.StoragePower
+	TotalPledgeCollateral             abi.TokenAmount
+	ThisEpochRawBytePower             abi.StoragePower
+	ThisEpochQualityAdjPower          abi.StoragePower
+	ThisEpochPledgeCollateral         abi.TokenAmount
+	ThisEpochQAPowerSmoothed          smoothing.FilterEstimate
+	ThisEpochRawBytePowerSmoothed     smoothing.FilterEstimate
+	ThisEpochPledgeCollateralSmoothed smoothing.FilterEstimate
+	MinerCount                        int64
+	MinerAboveMinPowerCount           int64
+	CronEventQueue                    []*Entry // Values are entries of events
+	FirstCronEpoch                    abi.ChainEpoch
+	Claims                            []*Entry
+	ProofValidationBatch              []*Entry `json:",omitempty"` // Values are entries of seal infos
+}
+
+type VerifregDump struct {
+	RootKey                  addr.Address
+	Verifiers                []*Entry
+	VerifiedClients          []*Entry
+	DataCapEvents            []*Entry
+	RemoveDataCapProposalIDs []*Entry // Keyed by verifier and client
+}
+
+// Marshals a dump to indented JSON.
+func MarshalDump(dump interface{}) ([]byte, error) {
+	return json.MarshalIndent(dump, "", "  ")
+}
+
+// Dumps every actor in a state tree.
+func DumpTree(tree *states.Tree) (*TreeDump, error) {
+	dump := &TreeDump{}
+	err := tree.ForEach(func(a addr.Address, actor *states.Actor) error {
+		actorDump, err := DumpActor(tree.Store, a, actor)
+		if err != nil {
+			return err
+		}
+		dump.Actors = append(dump.Actors, actorDump)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dump, nil
+}
+
+// Dumps an actor and its state.
+func DumpActor(store adt.Store, a addr.Address, actor *states.Actor) (*ActorDump, error) {
+	st, err := DumpState(store, actor.Code, actor.Head)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to dump state of actor %v: %w", a, err)
+	}
+	return &ActorDump{
+		Address:    a,
+		Code:       builtin.ActorNameByCode(actor.Code),
+		Head:       actor.Head,
+		CallSeqNum: actor.CallSeqNum,
+		Balance:    actor.Balance,
+		State:      st,
+	}, nil
+}
+
+// Dumps the state of a builtin actor.
+func DumpState(store adt.Store, code, head cid.Cid) (interface{}, error) {
+	switch code {
+	case builtin.AccountActorCodeID:
+		var st account.State
+		if err := store.Get(store.Context(), head, &st); err != nil {
+			return nil, err
+		}
+		return &st, nil
+	case builtin.CronActorCodeID:
+		var st cron.State
+		if err := store.Get(store.Context(), head, &st); err != nil {
+			return nil, err
+		}
+		return &st, nil
+	case builtin.InitActorCodeID:
+		return DumpInit(store, head)
+	case builtin.StorageMarketActorCodeID:
+		return DumpMarket(store, head)
+	case builtin.StorageMinerActorCodeID:
+		return DumpMiner(store, head)
+	case builtin.MultisigActorCodeID:
+		return DumpMultisig(store, head)
+	case builtin.ParameterRegistryActorCodeID:
+		return DumpParamreg(store, head)
+	case builtin.PaymentChannelActorCodeID:
+		return DumpPaych(store, head)
+	case builtin.StoragePowerActorCodeID:
+		return DumpPower(store, head)
+	case builtin.RewardActorCodeID:
+		var st reward.State
+		if err := store.Get(store.Context(), head, &st); err != nil {
+			return nil, err
+		}
+		return &st, nil
+	case builtin.SystemActorCodeID:
+		var st system.State
+		if err := store.Get(store.Context(), head, &st); err != nil {
+			return nil, err
+		}
+		return &st, nil
+	case builtin.VerifiedRegistryActorCodeID:
+		return DumpVerifreg(store, head)
+	default:
+		return nil, xerrors.Errorf("unexpected actor code %v", code)
+	}
+}
+
+func DumpInit(store adt.Store, head cid.Cid) (*InitDump, error) {
+	var st init_.State
+	if err := store.Get(store.Context(), head, &st); err != nil {
+		return nil, err
+	}
+	dump := &InitDump{
+		NetworkName:      st.NetworkName,
+		NextID:           st.NextID,
+		ExecAllowlist:    st.ExecAllowlist,
+		UpgradeApprovals: st.UpgradeApprovals,
+	}
+	var err error
+	if dump.AddressMap, err = dumpMap(store, st.AddressMap, addrKey, decodeAs(func() cbg.CBORUnmarshaler { return new(cbg.CborInt) })); err != nil {
+		return nil, xerrors.Errorf("address map: %w", err)
+	}
+	if dump.RobustAddressMap, err = dumpMap(store, st.RobustAddressMap, uintKey, decodeAs(func() cbg.CBORUnmarshaler { return new(addr.Address) })); err != nil {
+		return nil, xerrors.Errorf("robust address map: %w", err)
+	}
+	if dump.Tombstones, err = dumpMap(store, st.Tombstones, addrKey, decodeAs(func() cbg.CBORUnmarshaler { return new(init_.Tombstone) })); err != nil {
+		return nil, xerrors.Errorf("tombstones: %w", err)
+	}
+	return dump, nil
+}
+
+func DumpMarket(store adt.Store, head cid.Cid) (*MarketDump, error) {
+	var st market.State
+	if err := store.Get(store.Context(), head, &st); err != nil {
+		return nil, err
+	}
+	dump := &MarketDump{
+		NextID:                        st.NextID,
+		LastCron:                      st.LastCron,
+		TotalClientLockedCollateral:   st.TotalClientLockedCollateral,
+		TotalProviderLockedCollateral: st.TotalProviderLockedCollateral,
+		TotalClientStorageFee:         st.TotalClientStorageFee,
+	}
+	var err error
+	if dump.Proposals, err = dumpArray(store, st.Proposals, decodeAs(func() cbg.CBORUnmarshaler { return new(market.DealProposal) })); err != nil {
+		return nil, xerrors.Errorf("proposals: %w", err)
+	}
+	if dump.States, err = dumpArray(store, st.States, decodeAs(func() cbg.CBORUnmarshaler { return new(market.DealState) })); err != nil {
+		return nil, xerrors.Errorf("states: %w", err)
+	}
+	if dump.PendingProposals, err = dumpMap(store, st.PendingProposals, cidKey, decodeAs(func() cbg.CBORUnmarshaler { return new(market.DealProposal) })); err != nil {
+		return nil, xerrors.Errorf("pending proposals: %w", err)
+	}
+	if dump.EscrowTable, err = dumpMap(store, st.EscrowTable, addrKey, decodeAs(func() cbg.CBORUnmarshaler { return new(abi.TokenAmount) })); err != nil {
+		return nil, xerrors.Errorf("escrow table: %w", err)
+	}
+	if dump.LockedTable, err = dumpMap(store, st.LockedTable, addrKey, decodeAs(func() cbg.CBORUnmarshaler { return new(abi.TokenAmount) })); err != nil {
+		return nil, xerrors.Errorf("locked table: %w", err)
+	}
+	if dump.DealOpsByEpoch, err = dumpMap(store, st.DealOpsByEpoch, uintKey, dumpSet(store, uintKey)); err != nil {
+		return nil, xerrors.Errorf("deal ops by epoch: %w", err)
+	}
+	if dump.DealsByProvider, err = dumpMap(store, st.DealsByProvider, addrKey, dumpInnerArrayIndices(store)); err != nil {
+		return nil, xerrors.Errorf("deals by provider: %w", err)
+	}
+	return dump, nil
+}
+
+func DumpMiner(store adt.Store, head cid.Cid) (*MinerDump, error) {
+	var st miner.State
+	if err := store.Get(store.Context(), head, &st); err != nil {
+		return nil, err
+	}
+	info, err := st.GetInfo(store)
+	if err != nil {
+		return nil, err
+	}
+	vesting, err := st.LoadVestingFunds(store)
+	if err != nil {
+		return nil, err
+	}
+	var allocated bitfield.BitField
+	if err := store.Get(store.Context(), st.AllocatedSectors, &allocated); err != nil {
+		return nil, xerrors.Errorf("failed to load allocated sectors: %w", err)
+	}
+	dump := &MinerDump{
+		Info:               info,
+		PreCommitDeposits:  st.PreCommitDeposits,
+		LockedFunds:        st.LockedFunds,
+		VestingFunds:       vesting.Funds,
+		FeeDebt:            st.FeeDebt,
+		InitialPledge:      st.InitialPledge,
+		ProvingPeriodStart: st.ProvingPeriodStart,
+		CurrentDeadline:    st.CurrentDeadline,
+	}
+	if dump.AllocatedSectors, err = dumpBitField(allocated); err != nil {
+		return nil, xerrors.Errorf("allocated sectors: %w", err)
+	}
+	if dump.EarlyTerminations, err = dumpBitField(st.EarlyTerminations); err != nil {
+		return nil, xerrors.Errorf("early terminations: %w", err)
+	}
+	if dump.PreCommittedSectors, err = dumpMap(store, st.PreCommittedSectors, uintKey, decodeAs(func() cbg.CBORUnmarshaler { return new(miner.SectorPreCommitOnChainInfo) })); err != nil {
+		return nil, xerrors.Errorf("precommitted sectors: %w", err)
+	}
+	if dump.PreCommittedSectorsExpiry, err = dumpArray(store, st.PreCommittedSectorsExpiry, decodeBitField); err != nil {
+		return nil, xerrors.Errorf("precommitted sectors expiry: %w", err)
+	}
+	if dump.Sectors, err = dumpArray(store, st.Sectors, decodeAs(func() cbg.CBORUnmarshaler { return new(miner.SectorOnChainInfo) })); err != nil {
+		return nil, xerrors.Errorf("sectors: %w", err)
+	}
+
+	deadlines, err := st.LoadDeadlines(store)
+	if err != nil {
+		return nil, err
+	}
+	for dlIdx := range deadlines.Due {
+		deadline, err := deadlines.LoadDeadline(store, uint64(dlIdx))
+		if err != nil {
+			return nil, err
+		}
+		deadlineDump, err := dumpDeadline(store, deadline)
+		if err != nil {
+			return nil, xerrors.Errorf("deadline %d: %w", dlIdx, err)
+		}
+		dump.Deadlines = append(dump.Deadlines, deadlineDump)
+	}
+	return dump, nil
+}
+
+func dumpDeadline(store adt.Store, deadline *miner.Deadline) (*DeadlineDump, error) {
+	dump := &DeadlineDump{
+		LiveSectors:  deadline.LiveSectors,
+		TotalSectors: deadline.TotalSectors,
+		FaultyPower:  deadline.FaultyPower,
+	}
+	var err error
+	if dump.PostSubmissions, err = dumpBitField(deadline.PostSubmissions); err != nil {
+		return nil, xerrors.Errorf("post submissions: %w", err)
+	}
+	if dump.EarlyTerminations, err = dumpBitField(deadline.EarlyTerminations); err != nil {
+		return nil, xerrors.Errorf("early terminations: %w", err)
+	}
+	if dump.ExpirationsEpochs, err = dumpArray(store, deadline.ExpirationsEpochs, decodeBitField); err != nil {
+		return nil, xerrors.Errorf("expirations epochs: %w", err)
+	}
+	if dump.Partitions, err = dumpArray(store, deadline.Partitions, func(raw *cbg.Deferred) (interface{}, error) {
+		value, err := decodeValue(raw, func() cbg.CBORUnmarshaler { return new(miner.Partition) })
+		if err != nil {
+			return nil, err
+		}
+		return dumpPartition(store, value.(*miner.Partition))
+	}); err != nil {
+		return nil, xerrors.Errorf("partitions: %w", err)
+	}
+	return dump, nil
+}
+
+func dumpPartition(store adt.Store, partition *miner.Partition) (*PartitionDump, error) {
+	dump := &PartitionDump{
+		LivePower:       partition.LivePower,
+		UnprovenPower:   partition.UnprovenPower,
+		FaultyPower:     partition.FaultyPower,
+		RecoveringPower: partition.RecoveringPower,
+	}
+	var err error
+	for _, bf := range []struct {
+		name string
+		in   bitfield.BitField
+		out  *[]uint64
+	}{
+		{"sectors", partition.Sectors, &dump.Sectors},
+		{"unproven", partition.Unproven, &dump.Unproven},
+		{"faults", partition.Faults, &dump.Faults},
+		{"recoveries", partition.Recoveries, &dump.Recoveries},
+		{"terminated", partition.Terminated, &dump.Terminated},
+	} {
+		if *bf.out, err = dumpBitField(bf.in); err != nil {
+			return nil, xerrors.Errorf("%s: %w", bf.name, err)
+		}
+	}
+	if dump.ExpirationsEpochs, err = dumpArray(store, partition.ExpirationsEpochs, decodeExpirationSet); err != nil {
+		return nil, xerrors.Errorf("expirations epochs: %w", err)
+	}
+	if dump.EarlyTerminated, err = dumpArray(store, partition.EarlyTerminated, decodeBitField); err != nil {
+		return nil, xerrors.Errorf("early terminated: %w", err)
+	}
+	return dump, nil
+}
+
+func decodeExpirationSet(raw *cbg.Deferred) (interface{}, error) {
+	value, err := decodeValue(raw, func() cbg.CBORUnmarshaler { return new(miner.ExpirationSet) })
+	if err != nil {
+		return nil, err
+	}
+	set := value.(*miner.ExpirationSet)
+	dump := &ExpirationSetDump{
+		OnTimePledge: set.OnTimePledge,
+		ActivePower:  set.ActivePower,
+		FaultyPower:  set.FaultyPower,
+	}
+	if dump.OnTimeSectors, err = dumpBitField(set.OnTimeSectors); err != nil {
+		return nil, err
+	}
+	if dump.EarlySectors, err = dumpBitField(set.EarlySectors); err != nil {
+		return nil, err
+	}
+	return dump, nil
+}
+
+func DumpMultisig(store adt.Store, head cid.Cid) (*MultisigDump, error) {
+	var st multisig.State
+	if err := store.Get(store.Context(), head, &st); err != nil {
+		return nil, err
+	}
+	dump := &MultisigDump{
+		Signers:               st.Signers,
+		NumApprovalsThreshold: st.NumApprovalsThreshold,
+		NextTxnID:             st.NextTxnID,
+		InitialBalance:        st.InitialBalance,
+		StartEpoch:            st.StartEpoch,
+		UnlockDuration:        st.UnlockDuration,
+	}
+	var err error
+	if dump.PendingTxns, err = dumpMap(store, st.PendingTxns, intKey, decodeAs(func() cbg.CBORUnmarshaler { return new(multisig.Transaction) })); err != nil {
+		return nil, xerrors.Errorf("pending transactions: %w", err)
+	}
+	return dump, nil
+}
+
+func DumpParamreg(store adt.Store, head cid.Cid) (*ParamregDump, error) {
+	var st paramreg.State
+	if err := store.Get(store.Context(), head, &st); err != nil {
+		return nil, err
+	}
+	dump := &ParamregDump{Parameters: st.Parameters}
+	var err error
+	if dump.History, err = dumpArray(store, st.History, decodeAs(func() cbg.CBORUnmarshaler { return new(paramreg.ParameterChange) })); err != nil {
+		return nil, xerrors.Errorf("history: %w", err)
+	}
+	return dump, nil
+}
+
+func DumpPaych(store adt.Store, head cid.Cid) (*PaychDump, error) {
+	var st paych.State
+	if err := store.Get(store.Context(), head, &st); err != nil {
+		return nil, err
+	}
+	dump := &PaychDump{
+		From:            st.From,
+		To:              st.To,
+		ToSend:          st.ToSend,
+		SettlingAt:      st.SettlingAt,
+		MinSettleHeight: st.MinSettleHeight,
+	}
+	var err error
+	if dump.LaneStates, err = dumpArray(store, st.LaneStates, decodeAs(func() cbg.CBORUnmarshaler { return new(paych.LaneState) })); err != nil {
+		return nil, xerrors.Errorf("lane states: %w", err)
+	}
+	return dump, nil
+}
+
+func DumpPower(store adt.Store, head cid.Cid) (*PowerDump, error) {
+	var st power.State
+	if err := store.Get(store.Context(), head, &st); err != nil {
+		return nil, err
+	}
+	dump := &PowerDump{
+		TotalRawBytePower:                 st.TotalRawBytePower,
+		TotalBytesCommitted:               st.TotalBytesCommitted,
+		TotalQualityAdjPower:              st.TotalQualityAdjPower,
+		TotalQABytesCommitted:             st.TotalQABytesCommitted,
+		TotalPledgeCollateral:             st.TotalPledgeCollateral,
+		ThisEpochRawBytePower:             st.ThisEpochRawBytePower,
+		ThisEpochQualityAdjPower:          st.ThisEpochQualityAdjPower,
+		ThisEpochPledgeCollateral:         st.ThisEpochPledgeCollateral,
+		ThisEpochQAPowerSmoothed:          st.ThisEpochQAPowerSmoothed,
+		ThisEpochRawBytePowerSmoothed:     st.ThisEpochRawBytePowerSmoothed,
+		ThisEpochPledgeCollateralSmoothed: st.ThisEpochPledgeCollateralSmoothed,
+		MinerCount:                        st.MinerCount,
+		MinerAboveMinPowerCount:           st.MinerAboveMinPowerCount,
+		FirstCronEpoch:                    st.FirstCronEpoch,
+	}
+	var err error
+	if dump.CronEventQueue, err = dumpMap(store, st.CronEventQueue, intKey, dumpInnerArray(store, func() cbg.CBORUnmarshaler { return new(power.CronEvent) })); err != nil {
+		return nil, xerrors.Errorf("cron event queue: %w", err)
+	}
+	if dump.Claims, err = dumpMap(store, st.Claims, addrKey, decodeAs(func() cbg.CBORUnmarshaler { return new(power.Claim) })); err != nil {
+		return nil, xerrors.Errorf("claims: %w", err)
+	}
+	if st.ProofValidationBatch != nil {
+		if dump.ProofValidationBatch, err = dumpMap(store, *st.ProofValidationBatch, addrKey, dumpInnerArray(store, func() cbg.CBORUnmarshaler { return new(proof.SealVerifyInfo) })); err != nil {
+			return nil, xerrors.Errorf("proof validation batch: %w", err)
+		}
+	}
+	return dump, nil
+}
+
+func DumpVerifreg(store adt.Store, head cid.Cid) (*VerifregDump, error) {
+	var st verifreg.State
+	if err := store.Get(store.Context(), head, &st); err != nil {
+		return nil, err
+	}
+	dump := &VerifregDump{RootKey: st.RootKey}
+	var err error
+	if dump.Verifiers, err = dumpMap(store, st.Verifiers, addrKey, decodeAs(func() cbg.CBORUnmarshaler { return new(big.Int) })); err != nil {
+		return nil, xerrors.Errorf("verifiers: %w", err)
+	}
+	if dump.VerifiedClients, err = dumpMap(store, st.VerifiedClients, addrKey, decodeAs(func() cbg.CBORUnmarshaler { return new(big.Int) })); err != nil {
+		return nil, xerrors.Errorf("verified clients: %w", err)
+	}
+	if dump.DataCapEvents, err = dumpArray(store, st.DataCapEvents, decodeAs(func() cbg.CBORUnmarshaler { return new(verifreg.DataCapEventSet) })); err != nil {
+		return nil, xerrors.Errorf("data cap events: %w", err)
+	}
+	if dump.RemoveDataCapProposalIDs, err = dumpMap(store, st.RemoveDataCapProposalIDs, addrPairKey, decodeAs(func() cbg.CBORUnmarshaler { return new(verifreg.RemoveDataCapProposalID) })); err != nil {
+		return nil, xerrors.Errorf("remove data cap proposal IDs: %w", err)
+	}
+	return dump, nil
+}
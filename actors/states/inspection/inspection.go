@@ -0,0 +1,444 @@
+// Package inspection summarizes the state of builtin actors in forms that serialize to JSON, for CLIs and tests
+// to display and diff state trees at a human level.
+// Collections are summarized by their sizes unless their entries are requested.
+// Dumps (see DumpState) resolve state fully, for debugging.
+package inspection
+
+import (
+	addr "github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-bitfield"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/ipfs/go-cid"
+	cbg "github.com/whyrusleeping/cbor-gen"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/account"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/cron"
+	init_ "github.com/filecoin-project/specs-actors/v2/actors/builtin/init"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/market"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/miner"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/multisig"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/paramreg"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/paych"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/power"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/reward"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/system"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/verifreg"
+	"github.com/filecoin-project/specs-actors/v2/actors/states"
+	"github.com/filecoin-project/specs-actors/v2/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/v2/actors/util/smoothing"
+)
+
+// Options control the detail of a summary.
+type Options struct {
+	// Whether to include the entries of collections rather than only their sizes.
+	// On mainnet some collections, e.g. market deals and miner sectors, are very large.
+	Entries bool
+}
+
+type TreeSummary struct {
+	Actors []*ActorSummary // In state tree order
+}
+
+type ActorSummary struct {
+	Address    addr.Address
+	Code       string // Name of the actor's code, e.g. "fil/2/storageminer"
+	Head       cid.Cid
+	CallSeqNum uint64
+	Balance    abi.TokenAmount
+	// A summary of the actor's state, of the type summarizing the actor's code.
+	// Actors whose state holds no collections (account, cron, reward and system) are summarized by the state itself.
+	State interface{}
+}
+
+type InitSummary struct {
+	NetworkName      string
+	NextID           abi.ActorID
+	AddressMap       *Collection
+	RobustAddressMap *Collection
+	Tombstones       *Collection
+	ExecAllowlist    []init_.ExecPermission
+	UpgradeApprovals []init_.UpgradeApproval
+}
+
+type MarketSummary struct {
+	NextID                        abi.DealID
+	LastCron                      abi.ChainEpoch
+	TotalClientLockedCollateral   abi.TokenAmount
+	TotalProviderLockedCollateral abi.TokenAmount
+	TotalClientStorageFee         abi.TokenAmount
+	Proposals                     *Collection
+	States                        *Collection
+	PendingProposals              *Collection
+	EscrowTable                   *Collection
+	LockedTable                   *Collection
+	DealOpsByEpoch                *Collection // Entries are roots of sets of deal IDs
+}
+
+type MinerSummary struct {
+	Owner                     addr.Address
+	Worker                    addr.Address
+	ControlAddresses          []addr.Address
+	SealProofType             abi.RegisteredSealProof
+	SectorSize                abi.SectorSize
+	PreCommitDeposits         abi.TokenAmount
+	LockedFunds               abi.TokenAmount
+	FeeDebt                   abi.TokenAmount
+	InitialPledge             abi.TokenAmount
+	VestingFunds              []miner.VestingFund
+	PreCommittedSectors       *Collection
+	PreCommittedSectorsExpiry *Collection
+	AllocatedSectorCount      uint64
+	Sectors                   *Collection
+	ProvingPeriodStart        abi.ChainEpoch
+	CurrentDeadline           uint64
+	EarlyTerminationCount     uint64 // Number of deadlines with early terminations
+}
+
+type MultisigSummary struct {
+	Signers               []addr.Address
+	NumApprovalsThreshold uint64
+	NextTxnID             multisig.TxnID
+	InitialBalance        abi.TokenAmount
+	StartEpoch            abi.ChainEpoch
+	UnlockDuration        abi.ChainEpoch
+	PendingTxns           *Collection
+}
+
+type ParamregSummary struct {
+	Parameters map[string]big.Int
+	History    *Collection
+}
+
+type PaychSummary struct {
+	From            addr.Address
+	To              addr.Address
+	ToSend          abi.TokenAmount
+	SettlingAt      abi.ChainEpoch
+	MinSettleHeight abi.ChainEpoch
+	LaneStates      *Collection
+}
+
+type PowerSummary struct {
+	TotalRawBytePower                 abi.StoragePower
+	TotalBytesCommitted               abi.StoragePower
+	TotalQualityAdjPower              abi.StoragePower
+	TotalQABytesCommitted             abi.StoragePower
+	TotalPledgeCollateral             abi.TokenAmount
+	ThisEpochRawBytePower             abi.StoragePower
+	ThisEpochQualityAdjPower          abi.StoragePower
+	ThisEpochPledgeCollateral         abi.TokenAmount
+	ThisEpochQAPowerSmoothed          smoothing.FilterEstimate
+	ThisEpochRawBytePowerSmoothed     smoothing.FilterEstimate
+	ThisEpochPledgeCollateralSmoothed smoothing.FilterEstimate
+	MinerCount                        int64
+	MinerAboveMinPowerCount           int64
+	FirstCronEpoch                    abi.ChainEpoch
+	CronEventQueue                    *Collection // Entries are roots of arrays of events
+	Claims                            *Collection
+	ProofValidationBatch              *Collection `json:",omitempty"` // Entries are roots of arrays of seal infos
+}
+
+type VerifregSummary struct {
+	RootKey         addr.Address
+	Verifiers       *Collection
+	VerifiedClients *Collection
+	DataCapEvents   *Collection
+}
+
+// Summarizes every actor in a state tree.
+func SummarizeTree(tree *states.Tree, opts Options) (*TreeSummary, error) {
+	summary := &TreeSummary{}
+	err := tree.ForEach(func(a addr.Address, actor *states.Actor) error {
+		actorSummary, err := SummarizeActor(tree.Store, a, actor, opts)
+		if err != nil {
+			return err
+		}
+		summary.Actors = append(summary.Actors, actorSummary)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// Summarizes an actor and its state.
+func SummarizeActor(store adt.Store, a addr.Address, actor *states.Actor, opts Options) (*ActorSummary, error) {
+	st, err := SummarizeState(store, actor.Code, actor.Head, opts)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to summarize state of actor %v: %w", a, err)
+	}
+	return &ActorSummary{
+		Address:    a,
+		Code:       builtin.ActorNameByCode(actor.Code),
+		Head:       actor.Head,
+		CallSeqNum: actor.CallSeqNum,
+		Balance:    actor.Balance,
+		State:      st,
+	}, nil
+}
+
+// Summarizes the state of a builtin actor.
+func SummarizeState(store adt.Store, code, head cid.Cid, opts Options) (interface{}, error) {
+	switch code {
+	case builtin.AccountActorCodeID:
+		var st account.State
+		if err := store.Get(store.Context(), head, &st); err != nil {
+			return nil, err
+		}
+		return &st, nil
+	case builtin.CronActorCodeID:
+		var st cron.State
+		if err := store.Get(store.Context(), head, &st); err != nil {
+			return nil, err
+		}
+		return &st, nil
+	case builtin.InitActorCodeID:
+		return summarizeInit(store, head, opts)
+	case builtin.StorageMarketActorCodeID:
+		return summarizeMarket(store, head, opts)
+	case builtin.StorageMinerActorCodeID:
+		return summarizeMiner(store, head, opts)
+	case builtin.MultisigActorCodeID:
+		return summarizeMultisig(store, head, opts)
+	case builtin.ParameterRegistryActorCodeID:
+		return summarizeParamreg(store, head, opts)
+	case builtin.PaymentChannelActorCodeID:
+		return summarizePaych(store, head, opts)
+	case builtin.StoragePowerActorCodeID:
+		return summarizePower(store, head, opts)
+	case builtin.RewardActorCodeID:
+		var st reward.State
+		if err := store.Get(store.Context(), head, &st); err != nil {
+			return nil, err
+		}
+		return &st, nil
+	case builtin.SystemActorCodeID:
+		var st system.State
+		if err := store.Get(store.Context(), head, &st); err != nil {
+			return nil, err
+		}
+		return &st, nil
+	case builtin.VerifiedRegistryActorCodeID:
+		return summarizeVerifreg(store, head, opts)
+	default:
+		return nil, xerrors.Errorf("unexpected actor code %v", code)
+	}
+}
+
+func summarizeInit(store adt.Store, head cid.Cid, opts Options) (*InitSummary, error) {
+	var st init_.State
+	if err := store.Get(store.Context(), head, &st); err != nil {
+		return nil, err
+	}
+	summary := &InitSummary{
+		NetworkName:      st.NetworkName,
+		NextID:           st.NextID,
+		ExecAllowlist:    st.ExecAllowlist,
+		UpgradeApprovals: st.UpgradeApprovals,
+	}
+	var err error
+	if summary.AddressMap, err = summarizeMap(store, st.AddressMap, addrKey, func() cbg.CBORUnmarshaler { return new(cbg.CborInt) }, opts); err != nil {
+		return nil, xerrors.Errorf("address map: %w", err)
+	}
+	if summary.RobustAddressMap, err = summarizeMap(store, st.RobustAddressMap, uintKey, func() cbg.CBORUnmarshaler { return new(addr.Address) }, opts); err != nil {
+		return nil, xerrors.Errorf("robust address map: %w", err)
+	}
+	if summary.Tombstones, err = summarizeMap(store, st.Tombstones, addrKey, func() cbg.CBORUnmarshaler { return new(init_.Tombstone) }, opts); err != nil {
+		return nil, xerrors.Errorf("tombstones: %w", err)
+	}
+	return summary, nil
+}
+
+func summarizeMarket(store adt.Store, head cid.Cid, opts Options) (*MarketSummary, error) {
+	var st market.State
+	if err := store.Get(store.Context(), head, &st); err != nil {
+		return nil, err
+	}
+	summary := &MarketSummary{
+		NextID:                        st.NextID,
+		LastCron:                      st.LastCron,
+		TotalClientLockedCollateral:   st.TotalClientLockedCollateral,
+		TotalProviderLockedCollateral: st.TotalProviderLockedCollateral,
+		TotalClientStorageFee:         st.TotalClientStorageFee,
+	}
+	var err error
+	if summary.Proposals, err = summarizeArray(store, st.Proposals, func() cbg.CBORUnmarshaler { return new(market.DealProposal) }, opts); err != nil {
+		return nil, xerrors.Errorf("proposals: %w", err)
+	}
+	if summary.States, err = summarizeArray(store, st.States, func() cbg.CBORUnmarshaler { return new(market.DealState) }, opts); err != nil {
+		return nil, xerrors.Errorf("states: %w", err)
+	}
+	if summary.PendingProposals, err = summarizeMap(store, st.PendingProposals, cidKey, func() cbg.CBORUnmarshaler { return new(market.DealProposal) }, opts); err != nil {
+		return nil, xerrors.Errorf("pending proposals: %w", err)
+	}
+	if summary.EscrowTable, err = summarizeMap(store, st.EscrowTable, addrKey, func() cbg.CBORUnmarshaler { return new(abi.TokenAmount) }, opts); err != nil {
+		return nil, xerrors.Errorf("escrow table: %w", err)
+	}
+	if summary.LockedTable, err = summarizeMap(store, st.LockedTable, addrKey, func() cbg.CBORUnmarshaler { return new(abi.TokenAmount) }, opts); err != nil {
+		return nil, xerrors.Errorf("locked table: %w", err)
+	}
+	if summary.DealOpsByEpoch, err = summarizeMap(store, st.DealOpsByEpoch, uintKey, func() cbg.CBORUnmarshaler { return new(cidValue) }, opts); err != nil {
+		return nil, xerrors.Errorf("deal ops by epoch: %w", err)
+	}
+	return summary, nil
+}
+
+func summarizeMiner(store adt.Store, head cid.Cid, opts Options) (*MinerSummary, error) {
+	var st miner.State
+	if err := store.Get(store.Context(), head, &st); err != nil {
+		return nil, err
+	}
+	info, err := st.GetInfo(store)
+	if err != nil {
+		return nil, err
+	}
+	vesting, err := st.LoadVestingFunds(store)
+	if err != nil {
+		return nil, err
+	}
+	var allocated bitfield.BitField
+	if err := store.Get(store.Context(), st.AllocatedSectors, &allocated); err != nil {
+		return nil, xerrors.Errorf("failed to load allocated sectors: %w", err)
+	}
+	summary := &MinerSummary{
+		Owner:              info.Owner,
+		Worker:             info.Worker,
+		ControlAddresses:   info.ControlAddresses,
+		SealProofType:      info.SealProofType,
+		SectorSize:         info.SectorSize,
+		PreCommitDeposits:  st.PreCommitDeposits,
+		LockedFunds:        st.LockedFunds,
+		FeeDebt:            st.FeeDebt,
+		InitialPledge:      st.InitialPledge,
+		VestingFunds:       vesting.Funds,
+		ProvingPeriodStart: st.ProvingPeriodStart,
+		CurrentDeadline:    st.CurrentDeadline,
+	}
+	if summary.AllocatedSectorCount, err = allocated.Count(); err != nil {
+		return nil, err
+	}
+	if summary.EarlyTerminationCount, err = st.EarlyTerminations.Count(); err != nil {
+		return nil, err
+	}
+	if summary.PreCommittedSectors, err = summarizeMap(store, st.PreCommittedSectors, uintKey, func() cbg.CBORUnmarshaler { return new(miner.SectorPreCommitOnChainInfo) }, opts); err != nil {
+		return nil, xerrors.Errorf("precommitted sectors: %w", err)
+	}
+	if summary.PreCommittedSectorsExpiry, err = summarizeArray(store, st.PreCommittedSectorsExpiry, func() cbg.CBORUnmarshaler { return new(bitfield.BitField) }, opts); err != nil {
+		return nil, xerrors.Errorf("precommitted sectors expiry: %w", err)
+	}
+	if summary.Sectors, err = summarizeArray(store, st.Sectors, func() cbg.CBORUnmarshaler { return new(miner.SectorOnChainInfo) }, opts); err != nil {
+		return nil, xerrors.Errorf("sectors: %w", err)
+	}
+	return summary, nil
+}
+
+func summarizeMultisig(store adt.Store, head cid.Cid, opts Options) (*MultisigSummary, error) {
+	var st multisig.State
+	if err := store.Get(store.Context(), head, &st); err != nil {
+		return nil, err
+	}
+	summary := &MultisigSummary{
+		Signers:               st.Signers,
+		NumApprovalsThreshold: st.NumApprovalsThreshold,
+		NextTxnID:             st.NextTxnID,
+		InitialBalance:        st.InitialBalance,
+		StartEpoch:            st.StartEpoch,
+		UnlockDuration:        st.UnlockDuration,
+	}
+	var err error
+	if summary.PendingTxns, err = summarizeMap(store, st.PendingTxns, intKey, func() cbg.CBORUnmarshaler { return new(multisig.Transaction) }, opts); err != nil {
+		return nil, xerrors.Errorf("pending transactions: %w", err)
+	}
+	return summary, nil
+}
+
+func summarizeParamreg(store adt.Store, head cid.Cid, opts Options) (*ParamregSummary, error) {
+	var st paramreg.State
+	if err := store.Get(store.Context(), head, &st); err != nil {
+		return nil, err
+	}
+	summary := &ParamregSummary{Parameters: st.Parameters}
+	var err error
+	if summary.History, err = summarizeArray(store, st.History, func() cbg.CBORUnmarshaler { return new(paramreg.ParameterChange) }, opts); err != nil {
+		return nil, xerrors.Errorf("history: %w", err)
+	}
+	return summary, nil
+}
+
+func summarizePaych(store adt.Store, head cid.Cid, opts Options) (*PaychSummary, error) {
+	var st paych.State
+	if err := store.Get(store.Context(), head, &st); err != nil {
+		return nil, err
+	}
+	summary := &PaychSummary{
+		From:            st.From,
+		To:              st.To,
+		ToSend:          st.ToSend,
+		SettlingAt:      st.SettlingAt,
+		MinSettleHeight: st.MinSettleHeight,
+	}
+	var err error
+	if summary.LaneStates, err = summarizeArray(store, st.LaneStates, func() cbg.CBORUnmarshaler { return new(paych.LaneState) }, opts); err != nil {
+		return nil, xerrors.Errorf("lane states: %w", err)
+	}
+	return summary, nil
+}
+
+func summarizePower(store adt.Store, head cid.Cid, opts Options) (*PowerSummary, error) {
+	var st power.State
+	if err := store.Get(store.Context(), head, &st); err != nil {
+		return nil, err
+	}
+	summary := &PowerSummary{
+		TotalRawBytePower:                 st.TotalRawBytePower,
+		TotalBytesCommitted:               st.TotalBytesCommitted,
+		TotalQualityAdjPower:              st.TotalQualityAdjPower,
+		TotalQABytesCommitted:             st.TotalQABytesCommitted,
+		TotalPledgeCollateral:             st.TotalPledgeCollateral,
+		ThisEpochRawBytePower:             st.ThisEpochRawBytePower,
+		ThisEpochQualityAdjPower:          st.ThisEpochQualityAdjPower,
+		ThisEpochPledgeCollateral:         st.ThisEpochPledgeCollateral,
+		ThisEpochQAPowerSmoothed:          st.ThisEpochQAPowerSmoothed,
+		ThisEpochRawBytePowerSmoothed:     st.ThisEpochRawBytePowerSmoothed,
+		ThisEpochPledgeCollateralSmoothed: st.ThisEpochPledgeCollateralSmoothed,
+		MinerCount:                        st.MinerCount,
+		MinerAboveMinPowerCount:           st.MinerAboveMinPowerCount,
+		FirstCronEpoch:                    st.FirstCronEpoch,
+	}
+	var err error
+	if summary.CronEventQueue, err = summarizeMap(store, st.CronEventQueue, intKey, func() cbg.CBORUnmarshaler { return new(cidValue) }, opts); err != nil {
+		return nil, xerrors.Errorf("cron event queue: %w", err)
+	}
+	if summary.Claims, err = summarizeMap(store, st.Claims, addrKey, func() cbg.CBORUnmarshaler { return new(power.Claim) }, opts); err != nil {
+		return nil, xerrors.Errorf("claims: %w", err)
+	}
+	if st.ProofValidationBatch != nil {
+		if summary.ProofValidationBatch, err = summarizeMap(store, *st.ProofValidationBatch, addrKey, func() cbg.CBORUnmarshaler { return new(cidValue) }, opts); err != nil {
+			return nil, xerrors.Errorf("proof validation batch: %w", err)
+		}
+	}
+	return summary, nil
+}
+
+func summarizeVerifreg(store adt.Store, head cid.Cid, opts Options) (*VerifregSummary, error) {
+	var st verifreg.State
+	if err := store.Get(store.Context(), head, &st); err != nil {
+		return nil, err
+	}
+	summary := &VerifregSummary{RootKey: st.RootKey}
+	var err error
+	if summary.Verifiers, err = summarizeMap(store, st.Verifiers, addrKey, func() cbg.CBORUnmarshaler { return new(big.Int) }, opts); err != nil {
+		return nil, xerrors.Errorf("verifiers: %w", err)
+	}
+	if summary.VerifiedClients, err = summarizeMap(store, st.VerifiedClients, addrKey, func() cbg.CBORUnmarshaler { return new(big.Int) }, opts); err != nil {
+		return nil, xerrors.Errorf("verified clients: %w", err)
+	}
+	if summary.DataCapEvents, err = summarizeArray(store, st.DataCapEvents, func() cbg.CBORUnmarshaler { return new(verifreg.DataCapEventSet) }, opts); err != nil {
+		return nil, xerrors.Errorf("data cap events: %w", err)
+	}
+	return summary, nil
+}
@@ -0,0 +1,130 @@
+package inspection_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/miner"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/power"
+	"github.com/filecoin-project/specs-actors/v2/actors/states"
+	"github.com/filecoin-project/specs-actors/v2/actors/states/inspection"
+	tutil "github.com/filecoin-project/specs-actors/v2/support/testing"
+	vm "github.com/filecoin-project/specs-actors/v2/support/vm"
+)
+
+func TestSummarizeTree(t *testing.T) {
+	ctx := context.Background()
+	v := vm.NewVMWithSingletons(ctx, t)
+	addrs := vm.CreateAccounts(ctx, t, v, 3, big.Mul(big.NewInt(1000), big.NewInt(1e18)), 93837778)
+	root, err := v.StateRoot()
+	require.NoError(t, err)
+	tree, err := states.LoadTree(v.Store(), root)
+	require.NoError(t, err)
+
+	summarize := func(opts inspection.Options) map[string]*inspection.ActorSummary {
+		summary, err := inspection.SummarizeTree(tree, opts)
+		require.NoError(t, err)
+		_, err = json.Marshal(summary)
+		require.NoError(t, err)
+
+		byAddress := map[string]*inspection.ActorSummary{}
+		for _, a := range summary.Actors {
+			byAddress[a.Address.String()] = a
+		}
+		return byAddress
+	}
+
+	t.Run("sizes", func(t *testing.T) {
+		actors := summarize(inspection.Options{})
+		initActor := actors[builtin.InitActorAddr.String()]
+		require.NotNil(t, initActor)
+		assert.Equal(t, "fil/2/init", initActor.Code)
+		initSummary := initActor.State.(*inspection.InitSummary)
+		assert.Equal(t, uint64(len(addrs)), initSummary.AddressMap.Size)
+		assert.Nil(t, initSummary.AddressMap.Entries)
+
+		power := actors[builtin.StoragePowerActorAddr.String()].State.(*inspection.PowerSummary)
+		assert.Equal(t, uint64(0), power.Claims.Size)
+		assert.Equal(t, int64(0), power.MinerCount)
+	})
+
+	t.Run("entries", func(t *testing.T) {
+		actors := summarize(inspection.Options{Entries: true})
+		initSummary := actors[builtin.InitActorAddr.String()].State.(*inspection.InitSummary)
+		require.Len(t, initSummary.AddressMap.Entries, len(addrs))
+		for _, a := range addrs {
+			assert.Contains(t, initSummary.AddressMap.Entries, a.String())
+		}
+		assert.Equal(t, uint64(len(addrs)), initSummary.RobustAddressMap.Size)
+		assert.Len(t, initSummary.RobustAddressMap.Entries, len(addrs))
+	})
+}
+
+func TestDumpTree(t *testing.T) {
+	ctx := context.Background()
+	v := vm.NewVMWithSingletons(ctx, t)
+	addrs := vm.CreateAccounts(ctx, t, v, 1, big.Mul(big.NewInt(10_000), vm.FIL), 93837778)
+	sealProof := abi.RegisteredSealProof_StackedDrg32GiBV1
+	ret := vm.ApplyOk(t, v, addrs[0], builtin.StoragePowerActorAddr, big.Mul(big.NewInt(1_000), vm.FIL), builtin.MethodsPower.CreateMiner, &power.CreateMinerParams{
+		Owner:         addrs[0],
+		Worker:        addrs[0],
+		SealProofType: sealProof,
+		Peer:          abi.PeerID("not really a peer id"),
+	})
+	minerAddrs := ret.(*power.CreateMinerReturn)
+	vm.ApplyOk(t, v, addrs[0], minerAddrs.RobustAddress, big.Zero(), builtin.MethodsMiner.PreCommitSector, &miner.PreCommitSectorParams{
+		SealProof:     sealProof,
+		SectorNumber:  100,
+		SealedCID:     tutil.MakeCID("100", &miner.SealedCIDPrefix),
+		SealRandEpoch: v.GetEpoch() - 1,
+		Expiration:    v.GetEpoch() + 200*builtin.EpochsInDay,
+	})
+
+	root, err := v.StateRoot()
+	require.NoError(t, err)
+	tree, err := states.LoadTree(v.Store(), root)
+	require.NoError(t, err)
+	dump, err := inspection.DumpTree(tree)
+	require.NoError(t, err)
+
+	byAddress := map[string]*inspection.ActorDump{}
+	for _, a := range dump.Actors {
+		byAddress[a.Address.String()] = a
+	}
+
+	minerDump := byAddress[minerAddrs.IDAddress.String()].State.(*inspection.MinerDump)
+	assert.Equal(t, sealProof, minerDump.Info.SealProofType)
+	assert.Equal(t, []uint64{100}, minerDump.AllocatedSectors)
+	require.Len(t, minerDump.PreCommittedSectors, 1)
+	assert.Equal(t, "100", minerDump.PreCommittedSectors[0].Key)
+	require.Len(t, minerDump.PreCommittedSectorsExpiry, 1)
+	assert.Equal(t, []uint64{100}, minerDump.PreCommittedSectorsExpiry[0].Value)
+	assert.Len(t, minerDump.Deadlines, int(miner.WPoStPeriodDeadlines))
+	assert.Empty(t, minerDump.Sectors)
+
+	// The miner's proving period cron event is expanded from its array.
+	powerDump := byAddress[builtin.StoragePowerActorAddr.String()].State.(*inspection.PowerDump)
+	require.Len(t, powerDump.CronEventQueue, 1)
+	events := powerDump.CronEventQueue[0].Value.([]*inspection.Entry)
+	require.Len(t, events, 1)
+	assert.Equal(t, minerAddrs.IDAddress, events[0].Value.(*power.CronEvent).MinerAddr)
+
+	// The same state dumps to the same JSON.
+	first, err := inspection.MarshalDump(dump)
+	require.NoError(t, err)
+	again, err := inspection.DumpTree(tree)
+	require.NoError(t, err)
+	second, err := inspection.MarshalDump(again)
+	require.NoError(t, err)
+	assert.Equal(t, string(first), string(second))
+	assert.Contains(t, string(first), `"AllocatedSectors": [
+          100
+        ]`)
+}
@@ -25,6 +25,7 @@ func TestCommitPoStFlow(t *testing.T) {
 	ctx := context.Background()
 	v := vm.NewVMWithSingletons(ctx, t)
 	addrs := vm.CreateAccounts(ctx, t, v, 1, big.Mul(big.NewInt(10_000), big.NewInt(1e18)), 93837778)
+	rec, v := vm.NewRecorder(t, v)
 
 	minerBalance := big.Mul(big.NewInt(10_000), vm.FIL)
 	sealProof := abi.RegisteredSealProof_StackedDrg32GiBV1
@@ -256,6 +257,8 @@ func TestCommitPoStFlow(t *testing.T) {
 		acc, err := states.CheckStateInvariants(stateTree, totalBalance, tv.GetEpoch())
 		require.NoError(t, err)
 		assert.True(t, acc.IsEmpty(), strings.Join(acc.Messages(), "\n"))
+
+		rec.Finish(t, tv, "commit-post")
 	})
 
 	t.Run("skip sector", func(t *testing.T) {
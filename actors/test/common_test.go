@@ -12,31 +12,24 @@ import (
 
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/market"
-	tutil "github.com/filecoin-project/specs-actors/v2/support/testing"
+	"github.com/filecoin-project/specs-actors/v2/support/fixtures"
 	vm "github.com/filecoin-project/specs-actors/v2/support/vm"
 )
 
 func publishDeal(t *testing.T, v *vm.VM, provider, dealClient, minerID addr.Address, dealLabel string,
 	pieceSize abi.PaddedPieceSize, verifiedDeal bool, dealStart abi.ChainEpoch, dealLifetime abi.ChainEpoch,
 ) *market.PublishStorageDealsReturn {
-	deal := market.DealProposal{
-		PieceCID:             tutil.MakeCID(dealLabel, &market.PieceCIDPrefix),
-		PieceSize:            pieceSize,
-		VerifiedDeal:         verifiedDeal,
-		Client:               dealClient,
-		Provider:             minerID,
-		Label:                dealLabel,
-		StartEpoch:           dealStart,
-		EndEpoch:             dealStart + dealLifetime,
-		StoragePricePerEpoch: abi.NewTokenAmount(1 << 20),
-		ProviderCollateral:   big.Mul(big.NewInt(2), vm.FIL),
-		ClientCollateral:     big.Mul(big.NewInt(1), vm.FIL),
-	}
+	deal := fixtures.NewDealBuilder(dealClient, minerID, dealStart, dealStart+dealLifetime).
+		WithPiece(dealLabel, pieceSize).
+		WithVerified(verifiedDeal).
+		WithPrice(abi.NewTokenAmount(1<<20)).
+		WithCollateral(big.Mul(big.NewInt(2), vm.FIL), big.Mul(big.NewInt(1), vm.FIL)).
+		Build()
 
 	publishDealParams := market.PublishStorageDealsParams{
 		Deals: []market.ClientDealProposal{{
 			Proposal:        deal,
-			ClientSignature: crypto.Signature{},
+			ClientSignature: crypto.Signature{Type: crypto.SigTypeBLS},
 		}},
 	}
 	ret, code := v.ApplyMessage(provider, builtin.StorageMarketActorAddr, big.Zero(), builtin.MethodsMarket.PublishStorageDeals, &publishDealParams)
@@ -0,0 +1,290 @@
+package test_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-bitfield"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/cbor"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/exported"
+	init_ "github.com/filecoin-project/specs-actors/v2/actors/builtin/init"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/multisig"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/paych"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/power"
+	"github.com/filecoin-project/specs-actors/v2/actors/runtime"
+	tutil "github.com/filecoin-project/specs-actors/v2/support/testing"
+	vm "github.com/filecoin-project/specs-actors/v2/support/vm"
+)
+
+var (
+	dispatchFuzzIterations = flag.Int("dispatch-fuzz-iterations", 8, "mutated params per method and caller in TestDispatchFuzz")
+	dispatchFuzzSeed       = flag.Int64("dispatch-fuzz-seed", 1, "random seed for TestDispatchFuzz")
+)
+
+// Sends arbitrary CBOR params to every exported method of every builtin actor, from a variety of callers, and
+// checks that each message completes with an exit code rather than a panic, and with the same exit code and
+// state when repeated. Inputs are mutations of seeds generated from each method's parameter type.
+// Run longer with e.g. -dispatch-fuzz-iterations=1000 -dispatch-fuzz-seed=<n>.
+func TestDispatchFuzz(t *testing.T) {
+	v, targets, callers := newDispatchFixture(t)
+
+	actors := map[cid.Cid]runtime.VMActor{}
+	for _, a := range exported.BuiltinActors() {
+		actors[a.Code()] = a
+	}
+	iterations := *dispatchFuzzIterations
+	if testing.Short() {
+		iterations = 1
+	}
+	rnd := rand.New(rand.NewSource(*dispatchFuzzSeed))
+	covered := map[cid.Cid]bool{}
+	for _, target := range targets {
+		act, found, err := v.GetActor(target)
+		require.NoError(t, err)
+		require.True(t, found, "no actor at %v", target)
+		impl := actors[act.Code]
+		covered[act.Code] = true
+
+		// Include one method number beyond the exports, which must fail cleanly too.
+		exports := impl.Exports()
+		for method := 1; method <= len(exports); method++ {
+			var seeds [][]byte
+			if method < len(exports) && exports[method] != nil {
+				paramType := reflect.TypeOf(exports[method]).In(1)
+				seeds = paramSeeds(t, paramType, rnd)
+			}
+			seeds = append(seeds, []byte{})
+
+			for _, caller := range callers {
+				for _, seed := range seeds {
+					inputs := [][]byte{seed}
+					for i := 0; i < iterations; i++ {
+						inputs = append(inputs, mutateCBOR(seed, rnd))
+					}
+					for _, params := range inputs {
+						checkDispatch(t, v, caller, target, abi.MethodNum(method), params)
+					}
+				}
+			}
+		}
+	}
+	for code := range actors { //nolint:nomaprange
+		require.True(t, covered[code], "no instance of %v to fuzz", builtin.ActorNameByCode(code))
+	}
+}
+
+// Creates a VM with an instance of every builtin actor, returning it with the actors to send messages to
+// and the callers to send them from.
+func newDispatchFixture(t testing.TB) (v *vm.VM, targets, callers []address.Address) {
+	ctx := context.Background()
+	v = vm.NewVMWithSingletons(ctx, t)
+	addrs := vm.CreateAccounts(ctx, t, v, 2, big.Mul(big.NewInt(10_000), vm.FIL), 93837778)
+	owner, other := addrs[0], addrs[1]
+
+	createParams := power.CreateMinerParams{
+		Owner:         owner,
+		Worker:        owner,
+		SealProofType: abi.RegisteredSealProof_StackedDrg32GiBV1,
+		Peer:          abi.PeerID("not really a peer id"),
+	}
+	minerAddrs := vm.ApplyOk(t, v, owner, builtin.StoragePowerActorAddr, big.Mul(big.NewInt(100), vm.FIL),
+		builtin.MethodsPower.CreateMiner, &createParams).(*power.CreateMinerReturn)
+	multisigAddr := execActor(t, v, owner, builtin.MultisigActorCodeID, &multisig.ConstructorParams{
+		Signers:               []address.Address{owner, other},
+		NumApprovalsThreshold: 1,
+	})
+	paychAddr := execActor(t, v, owner, builtin.PaymentChannelActorCodeID, &paych.ConstructorParams{From: owner, To: other})
+
+	targets = []address.Address{
+		builtin.SystemActorAddr,
+		builtin.InitActorAddr,
+		builtin.RewardActorAddr,
+		builtin.CronActorAddr,
+		builtin.StoragePowerActorAddr,
+		builtin.StorageMarketActorAddr,
+		builtin.VerifiedRegistryActorAddr,
+		builtin.ParameterRegistryActorAddr,
+		owner,
+		minerAddrs.IDAddress,
+		multisigAddr,
+		paychAddr,
+	}
+	callers = []address.Address{
+		owner,
+		other,
+		builtin.SystemActorAddr,
+		builtin.CronActorAddr,
+		builtin.StoragePowerActorAddr,
+		builtin.StorageMarketActorAddr,
+		builtin.RewardActorAddr,
+	}
+	return v, targets, callers
+}
+
+// Applies a message to a fork of the VM twice, checking it neither panics nor behaves non-deterministically.
+func checkDispatch(t *testing.T, v *vm.VM, from, to address.Address, method abi.MethodNum, params []byte) {
+	describe := func() string {
+		return fmt.Sprintf("message from %v to %v method %d with params %s", from, to, method, hex.EncodeToString(params))
+	}
+	apply := func() (exitcode.ExitCode, cid.Cid) {
+		fork, err := v.WithEpoch(v.GetEpoch())
+		require.NoError(t, err)
+		var code exitcode.ExitCode
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Fatalf("%s panicked: %v", describe(), r)
+				}
+			}()
+			_, code = fork.ApplyMessage(from, to, big.Zero(), method, builtin.CBORBytes(params))
+		}()
+		root, err := fork.StateRoot()
+		require.NoError(t, err)
+		return code, root
+	}
+
+	code1, root1 := apply()
+	code2, root2 := apply()
+	require.Equal(t, code1, code2, "%s exited inconsistently", describe())
+	require.Equal(t, root1, root2, "%s produced inconsistent state", describe())
+}
+
+func execActor(t testing.TB, v *vm.VM, from address.Address, code cid.Cid, params cbor.Marshaler) address.Address {
+	buf := new(bytes.Buffer)
+	require.NoError(t, params.MarshalCBOR(buf))
+	ret := vm.ApplyOk(t, v, from, builtin.InitActorAddr, big.Zero(), builtin.MethodsInit.Exec, &init_.ExecParams{
+		CodeCID:           code,
+		ConstructorParams: buf.Bytes(),
+	})
+	return ret.(*init_.ExecReturn).IDAddress
+}
+
+// Generates seed params for a method parameter type: its zero value and a few randomly populated values.
+// Values that fail to serialize are omitted.
+func paramSeeds(t testing.TB, paramType reflect.Type, rnd *rand.Rand) [][]byte {
+	var seeds [][]byte
+	for i := 0; i < 4; i++ {
+		val := reflect.New(paramType.Elem())
+		if i > 0 {
+			populate(val.Elem(), rnd, 0)
+		}
+		marshaler, ok := val.Interface().(cbor.Marshaler)
+		require.True(t, ok, "param type %v is not a CBOR marshaler", paramType)
+		buf := new(bytes.Buffer)
+		if err := marshaler.MarshalCBOR(buf); err == nil {
+			seeds = append(seeds, buf.Bytes())
+		}
+	}
+	return seeds
+}
+
+var (
+	addressType  = reflect.TypeOf(address.Address{})
+	cidType      = reflect.TypeOf(cid.Cid{})
+	bigIntType   = reflect.TypeOf(big.Int{})
+	bitfieldType = reflect.TypeOf(bitfield.BitField{})
+)
+
+// Fills a value with random content of the right shape, using small numbers and short collections so that
+// values are plausible enough to get past decoding into actor logic.
+func populate(v reflect.Value, rnd *rand.Rand, depth int) {
+	switch v.Type() {
+	case addressType:
+		addr, _ := address.NewIDAddress(uint64(rnd.Intn(200)))
+		v.Set(reflect.ValueOf(addr))
+		return
+	case cidType:
+		v.Set(reflect.ValueOf(tutil.MakeCID(fmt.Sprint(rnd.Int()), nil)))
+		return
+	case bigIntType:
+		v.Set(reflect.ValueOf(big.NewInt(rnd.Int63n(1 << 40))))
+		return
+	case bitfieldType:
+		v.Set(reflect.ValueOf(bitfield.NewFromSet([]uint64{uint64(rnd.Intn(100)), uint64(rnd.Intn(100))})))
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(rnd.Intn(2) == 0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(rnd.Int63n(5000))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(uint64(rnd.Intn(256)))
+	case reflect.String:
+		v.SetString(fmt.Sprint(rnd.Intn(1000)))
+	case reflect.Ptr:
+		if depth < 4 {
+			v.Set(reflect.New(v.Type().Elem()))
+			populate(v.Elem(), rnd, depth+1)
+		}
+	case reflect.Slice:
+		if depth < 4 {
+			n := 1 + rnd.Intn(3)
+			if v.Type().Elem().Kind() == reflect.Uint8 {
+				n = rnd.Intn(64)
+			}
+			v.Set(reflect.MakeSlice(v.Type(), n, n))
+			for i := 0; i < n; i++ {
+				populate(v.Index(i), rnd, depth+1)
+			}
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			populate(v.Index(i), rnd, depth+1)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Field(i).CanSet() {
+				populate(v.Field(i), rnd, depth+1)
+			}
+		}
+	}
+}
+
+// Returns a random mutation of serialized params.
+func mutateCBOR(seed []byte, rnd *rand.Rand) []byte {
+	out := append([]byte(nil), seed...)
+	randomBytes := func(n int) []byte {
+		b := make([]byte, n)
+		rnd.Read(b)
+		return b
+	}
+	for mutations := 1 + rnd.Intn(3); mutations > 0; mutations-- {
+		if len(out) == 0 {
+			out = randomBytes(1 + rnd.Intn(16))
+			continue
+		}
+		i := rnd.Intn(len(out))
+		switch rnd.Intn(6) {
+		case 0: // flip a bit
+			out[i] ^= 1 << uint(rnd.Intn(8))
+		case 1: // replace a byte
+			out[i] = byte(rnd.Intn(256))
+		case 2: // truncate
+			out = out[:i]
+		case 3: // insert random bytes
+			out = append(out[:i], append(randomBytes(1+rnd.Intn(8)), out[i:]...)...)
+		case 4: // repeat a span
+			j := i + rnd.Intn(len(out)-i)
+			out = append(out[:j], append(append([]byte(nil), out[i:j]...), out[j:]...)...)
+		case 5: // replace entirely
+			out = randomBytes(rnd.Intn(64))
+		}
+	}
+	return out
+}
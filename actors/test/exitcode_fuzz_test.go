@@ -0,0 +1,119 @@
+package test_test
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"reflect"
+	goruntime "runtime"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/exported"
+	"github.com/filecoin-project/specs-actors/v2/actors/runtime"
+	vm "github.com/filecoin-project/specs-actors/v2/support/vm"
+)
+
+// A coverage-guided fuzz target mutating well-formed params for every exported method of every builtin actor,
+// sent from a variety of callers. It records the exit codes each method reaches, and fails if a message panics or
+// reaches ErrIllegalState. The VM's state is valid, so ErrIllegalState can only result from input that the actor
+// failed to validate, which should be rejected with ErrIllegalArgument (or another user error) instead.
+// The seed corpus, generated from each method's parameter type, runs as a test; fuzz with e.g.
+//   go test -run '^$' -fuzz FuzzMethodExitCodes ./actors/test
+// and run with -v to log the exit codes reached by each method.
+func FuzzMethodExitCodes(f *testing.F) {
+	v, targets, callers := newDispatchFixture(f)
+
+	actors := map[cid.Cid]runtime.VMActor{}
+	for _, a := range exported.BuiltinActors() {
+		actors[a.Code()] = a
+	}
+	impls := make([]runtime.VMActor, len(targets))
+	rnd := rand.New(rand.NewSource(1))
+	for i, target := range targets {
+		act, found, err := v.GetActor(target)
+		require.NoError(f, err)
+		require.True(f, found, "no actor at %v", target)
+		impls[i] = actors[act.Code]
+
+		for method, export := range impls[i].Exports() {
+			if export == nil {
+				continue
+			}
+			for _, seed := range paramSeeds(f, reflect.TypeOf(export).In(1), rnd) {
+				for c := range callers {
+					f.Add(uint8(i), uint8(method), uint8(c), seed)
+				}
+			}
+		}
+	}
+
+	reached := map[string]map[exitcode.ExitCode]bool{}
+	f.Cleanup(func() {
+		names := make([]string, 0, len(reached))
+		for name := range reached { //nolint:nomaprange
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			var codes []string
+			for code := range reached[name] { //nolint:nomaprange
+				codes = append(codes, code.String())
+			}
+			sort.Strings(codes)
+			f.Logf("%s: %s", name, strings.Join(codes, ", "))
+		}
+	})
+
+	f.Fuzz(func(t *testing.T, target, method, caller uint8, params []byte) {
+		if int(target) >= len(targets) || int(caller) >= len(callers) {
+			t.Skip()
+		}
+		impl := impls[target]
+		exports := impl.Exports()
+		if int(method) >= len(exports) || exports[method] == nil {
+			t.Skip()
+		}
+
+		name := fmt.Sprintf("%s.%s", builtin.ActorNameByCode(impl.Code()), methodName(exports[method]))
+		code := applyFuzzMessage(t, v, callers[caller], targets[target], abi.MethodNum(method), params)
+		if reached[name] == nil {
+			reached[name] = map[exitcode.ExitCode]bool{}
+		}
+		reached[name][code] = true
+
+		if code == exitcode.ErrIllegalState {
+			t.Errorf("%s from %v reached %v with params %s, which should be rejected as an illegal argument",
+				name, callers[caller], code, hex.EncodeToString(params))
+		}
+	})
+}
+
+// Applies a message to a fork of the VM, failing the test if it panics.
+func applyFuzzMessage(t *testing.T, v *vm.VM, from, to address.Address, method abi.MethodNum, params []byte) (code exitcode.ExitCode) {
+	fork, err := v.WithEpoch(v.GetEpoch())
+	require.NoError(t, err)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatalf("message from %v to %v method %d with params %s panicked: %v", from, to, method,
+				hex.EncodeToString(params), r)
+		}
+	}()
+	_, code = fork.ApplyMessage(from, to, big.Zero(), method, builtin.CBORBytes(params))
+	return code
+}
+
+// Returns the unqualified name of an exported method, e.g. "ControlAddresses".
+func methodName(export interface{}) string {
+	name := strings.TrimSuffix(goruntime.FuncForPC(reflect.ValueOf(export).Pointer()).Name(), "-fm")
+	return name[strings.LastIndex(name, ".")+1:]
+}
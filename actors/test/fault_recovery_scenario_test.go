@@ -0,0 +1,146 @@
+package test_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-bitfield"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/dline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/miner"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/power"
+	"github.com/filecoin-project/specs-actors/v2/actors/runtime/proof"
+	tutil "github.com/filecoin-project/specs-actors/v2/support/testing"
+	vm "github.com/filecoin-project/specs-actors/v2/support/vm"
+)
+
+// Onboards a sector, misses its window PoSt so it's faulted, then declares and proves its recovery,
+// running cron every epoch throughout.
+func TestMissedPoStFaultAndRecovery(t *testing.T) {
+	ctx := context.Background()
+	v := vm.NewVMWithSingletons(ctx, t)
+	addrs := vm.CreateAccounts(ctx, t, v, 1, big.Mul(big.NewInt(10_000), vm.FIL), 93837778)
+	worker := addrs[0]
+
+	minerBalance := big.Mul(big.NewInt(1_000), vm.FIL)
+	sectorNumber := abi.SectorNumber(100)
+	sealedCid := tutil.MakeCID("100", &miner.SealedCIDPrefix)
+	sealProof := abi.RegisteredSealProof_StackedDrg32GiBV1
+
+	// create miner
+	params := power.CreateMinerParams{
+		Owner:         worker,
+		Worker:        worker,
+		SealProofType: sealProof,
+		Peer:          abi.PeerID("not really a peer id"),
+	}
+	ret := vm.ApplyOk(t, v, worker, builtin.StoragePowerActorAddr, minerBalance, builtin.MethodsPower.CreateMiner, &params)
+	minerAddrs, ok := ret.(*power.CreateMinerReturn)
+	require.True(t, ok)
+
+	// precommit and prove the sector, letting cron confirm the proof at the end of the epoch
+	preCommitParams := miner.PreCommitSectorParams{
+		SealProof:     sealProof,
+		SectorNumber:  sectorNumber,
+		SealedCID:     sealedCid,
+		SealRandEpoch: v.GetEpoch() - 1,
+		Expiration:    v.GetEpoch() + 200*builtin.EpochsInDay,
+	}
+	vm.ApplyOk(t, v, worker, minerAddrs.RobustAddress, big.Zero(), builtin.MethodsMiner.PreCommitSector, &preCommitParams)
+
+	v = vm.AdvanceTillEpoch(t, v, v.GetEpoch()+miner.PreCommitChallengeDelay+1)
+	proveCommitParams := miner.ProveCommitSectorParams{SectorNumber: sectorNumber}
+	vm.ApplyOk(t, v, worker, minerAddrs.RobustAddress, big.Zero(), builtin.MethodsMiner.ProveCommitSector, &proveCommitParams)
+	v = vm.AdvanceEpochs(t, v, 1)
+
+	// the sector gains power once proven in its first proving deadline
+	sectorPower := vm.PowerForMinerSector(t, v, minerAddrs.IDAddress, sectorNumber)
+	assertMinerPower(t, v, minerAddrs.IDAddress, miner.NewPowerPairZero())
+	dlIdx, pIdx := vm.SectorDeadline(t, v, minerAddrs.IDAddress, sectorNumber)
+	v, dlInfo := advanceTillDeadlineOpen(t, v, minerAddrs.IDAddress, dlIdx)
+	submitWindowPoSt(t, v, worker, minerAddrs.RobustAddress, dlInfo, pIdx)
+	assertMinerPower(t, v, minerAddrs.IDAddress, sectorPower)
+
+	// miss the next proving deadline; cron at its close marks the sector faulty and removes its power
+	v = vm.AdvanceTillEpoch(t, v, dlInfo.Close+miner.WPoStProvingPeriod)
+	assertMinerPower(t, v, minerAddrs.IDAddress, miner.NewPowerPairZero())
+	assertSectorFaulty(t, v, minerAddrs.IDAddress, sectorNumber, true)
+
+	// declare the recovery, well ahead of the deadline's next fault cutoff
+	recoverParams := miner.DeclareFaultsRecoveredParams{Recoveries: []miner.RecoveryDeclaration{{
+		Deadline:  dlIdx,
+		Partition: pIdx,
+		Sectors:   bitfield.NewFromSet([]uint64{uint64(sectorNumber)}),
+	}}}
+	vm.ApplyOk(t, v, worker, minerAddrs.RobustAddress, big.Zero(), builtin.MethodsMiner.DeclareFaultsRecovered, &recoverParams)
+
+	// the recovering sector regains power when proven
+	v, dlInfo = advanceTillDeadlineOpen(t, v, minerAddrs.IDAddress, dlIdx)
+	assertMinerPower(t, v, minerAddrs.IDAddress, miner.NewPowerPairZero())
+	submitWindowPoSt(t, v, worker, minerAddrs.RobustAddress, dlInfo, pIdx)
+	assertMinerPower(t, v, minerAddrs.IDAddress, sectorPower)
+	assertSectorFaulty(t, v, minerAddrs.IDAddress, sectorNumber, false)
+
+	// and keeps it through the deadline's close
+	v = vm.AdvanceTillEpoch(t, v, dlInfo.Close)
+	assertMinerPower(t, v, minerAddrs.IDAddress, sectorPower)
+}
+
+// Advances epoch by epoch, with cron, to the next opening of a miner's deadline.
+func advanceTillDeadlineOpen(t *testing.T, v *vm.VM, minerIDAddr address.Address, dlIdx uint64) (*vm.VM, *dline.Info) {
+	current := vm.MinerDLInfo(t, v, minerIDAddr)
+	open := current.Open + abi.ChainEpoch((dlIdx+miner.WPoStPeriodDeadlines-current.Index)%miner.WPoStPeriodDeadlines)*miner.WPoStChallengeWindow
+	if open < v.GetEpoch() {
+		open += miner.WPoStProvingPeriod
+	}
+	v = vm.AdvanceTillEpoch(t, v, open)
+	dlInfo := vm.MinerDLInfo(t, v, minerIDAddr)
+	require.Equal(t, dlIdx, dlInfo.Index)
+	return v, dlInfo
+}
+
+func submitWindowPoSt(t *testing.T, v *vm.VM, worker, minerAddr address.Address, dlInfo *dline.Info, pIdx uint64) {
+	submitParams := miner.SubmitWindowedPoStParams{
+		Deadline: dlInfo.Index,
+		Partitions: []miner.PoStPartition{{
+			Index:   pIdx,
+			Skipped: bitfield.New(),
+		}},
+		Proofs: []proof.PoStProof{{
+			PoStProof: abi.RegisteredPoStProof_StackedDrgWindow32GiBV1,
+		}},
+		ChainCommitEpoch: dlInfo.Challenge,
+		ChainCommitRand:  fakeChainRandomness,
+	}
+	vm.ApplyOk(t, v, worker, minerAddr, big.Zero(), builtin.MethodsMiner.SubmitWindowedPoSt, &submitParams)
+}
+
+func assertMinerPower(t *testing.T, v *vm.VM, minerIDAddr address.Address, expected miner.PowerPair) {
+	actual := vm.MinerPower(t, v, minerIDAddr)
+	assert.Equal(t, expected.Raw, actual.Raw, "raw power at epoch %d", v.GetEpoch())
+	assert.Equal(t, expected.QA, actual.QA, "QA power at epoch %d", v.GetEpoch())
+}
+
+func assertSectorFaulty(t *testing.T, v *vm.VM, minerIDAddr address.Address, sectorNumber abi.SectorNumber, expected bool) {
+	var st miner.State
+	require.NoError(t, v.GetState(minerIDAddr, &st))
+	dlIdx, pIdx, err := st.FindSector(v.Store(), sectorNumber)
+	require.NoError(t, err)
+
+	deadlines, err := st.LoadDeadlines(v.Store())
+	require.NoError(t, err)
+	deadline, err := deadlines.LoadDeadline(v.Store(), dlIdx)
+	require.NoError(t, err)
+	partition, err := deadline.LoadPartition(v.Store(), pIdx)
+	require.NoError(t, err)
+
+	faulty, err := partition.Faults.IsSet(uint64(sectorNumber))
+	require.NoError(t, err)
+	assert.Equal(t, expected, faulty, "sector %d faulty at epoch %d", sectorNumber, v.GetEpoch())
+}
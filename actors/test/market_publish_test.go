@@ -0,0 +1,61 @@
+package test_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/crypto"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/market"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/miner"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/power"
+	"github.com/filecoin-project/specs-actors/v2/support/fixtures"
+	vm "github.com/filecoin-project/specs-actors/v2/support/vm"
+)
+
+func TestPublishDealRejectsSignatureOfWrongType(t *testing.T) {
+	ctx := context.Background()
+	v := vm.NewVMWithSingletons(ctx, t)
+	addrs := vm.CreateAccounts(ctx, t, v, 2, big.Mul(big.NewInt(10_000), vm.FIL), 93837778)
+	worker, client := addrs[0], addrs[1]
+	sealProof := abi.RegisteredSealProof_StackedDrg32GiBV1
+
+	ret := vm.ApplyOk(t, v, worker, builtin.StoragePowerActorAddr, big.Mul(big.NewInt(1_000), vm.FIL), builtin.MethodsPower.CreateMiner, &power.CreateMinerParams{
+		Owner:         worker,
+		Worker:        worker,
+		SealProofType: sealProof,
+		Peer:          abi.PeerID("not really a peer id"),
+	})
+	minerAddrs, ok := ret.(*power.CreateMinerReturn)
+	require.True(t, ok)
+
+	collateral := big.Mul(big.NewInt(64), vm.FIL)
+	vm.ApplyOk(t, v, client, builtin.StorageMarketActorAddr, collateral, builtin.MethodsMarket.AddBalance, &client)
+	vm.ApplyOk(t, v, worker, builtin.StorageMarketActorAddr, collateral, builtin.MethodsMarket.AddBalance, &minerAddrs.IDAddress)
+
+	dealStart := v.GetEpoch() + miner.MaxProveCommitDuration[sealProof]
+	deal := fixtures.NewDealBuilder(client, minerAddrs.IDAddress, dealStart, dealStart+200*builtin.EpochsInDay).
+		WithPiece("deal", 1<<30).
+		WithPrice(abi.NewTokenAmount(1<<20)).
+		WithCollateral(big.Mul(big.NewInt(2), vm.FIL), big.Mul(big.NewInt(1), vm.FIL)).
+		Build()
+
+	// The client's key is BLS, so a secp256k1 signature is rejected before any cryptographic check.
+	params := market.PublishStorageDealsParams{
+		Deals: []market.ClientDealProposal{{
+			Proposal:        deal,
+			ClientSignature: crypto.Signature{Type: crypto.SigTypeSecp256k1},
+		}},
+	}
+	_, code := v.ApplyMessage(worker, builtin.StorageMarketActorAddr, big.Zero(), builtin.MethodsMarket.PublishStorageDeals, &params)
+	assert.Equal(t, exitcode.ErrIllegalArgument, code)
+
+	params.Deals[0].ClientSignature = crypto.Signature{Type: crypto.SigTypeBLS}
+	vm.ApplyOk(t, v, worker, builtin.StorageMarketActorAddr, big.Zero(), builtin.MethodsMarket.PublishStorageDeals, &params)
+}
@@ -1,6 +1,7 @@
 package test_test
 
 import (
+	"bytes"
 	"context"
 	"testing"
 
@@ -19,6 +20,7 @@ func TestMarketWithdraw(t *testing.T) {
 	initialBalance := big.Mul(big.NewInt(6), big.NewInt(1e18))
 	addrs := vm.CreateAccounts(ctx, t, v, 1, initialBalance, 93837778)
 	caller := addrs[0]
+	rec, v := vm.NewRecorder(t, v)
 
 	// add market collateral for clients and miner
 	collateral := big.Mul(big.NewInt(3), vm.FIL)
@@ -28,6 +30,7 @@ func TestMarketWithdraw(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, found)
 	assert.Equal(t, big.Sub(initialBalance, collateral), a.Balance)
+	vm.ExpectNetTransfer(t, v, caller, builtin.StorageMarketActorAddr, collateral)
 
 	// withdraw collateral from market
 	params := &market.WithdrawBalanceParams{
@@ -40,4 +43,21 @@ func TestMarketWithdraw(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, found)
 	assert.Equal(t, initialBalance, a.Balance)
+	vm.ExpectNetTransfer(t, v, caller, builtin.StorageMarketActorAddr, big.Zero())
+
+	// withdrawal is recorded as a transfer event emitted by the market
+	events := v.Events()
+	require.NotEmpty(t, events)
+	last := events[len(events)-1]
+	assert.Equal(t, builtin.StorageMarketActorAddr, last.Emitter)
+	require.Len(t, last.Entries, 1)
+	assert.Equal(t, builtin.EventKeyTransfer, last.Entries[0].Key)
+	var transfer builtin.TransferEvent
+	require.NoError(t, transfer.UnmarshalCBOR(bytes.NewReader(last.Entries[0].Value.(builtin.CBORBytes))))
+	callerID, found := v.NormalizeAddress(caller)
+	require.True(t, found)
+	assert.Equal(t, callerID, transfer.To)
+	assert.Equal(t, collateral, transfer.Amount)
+
+	rec.Finish(t, v, "market-withdraw")
 }
@@ -0,0 +1,113 @@
+package test_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	addr "github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/crypto"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/account"
+	init_ "github.com/filecoin-project/specs-actors/v2/actors/builtin/init"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/multisig"
+	tutil "github.com/filecoin-project/specs-actors/v2/support/testing"
+	vm "github.com/filecoin-project/specs-actors/v2/support/vm"
+)
+
+func TestMultisigApproveBySessionKey(t *testing.T) {
+	ctx := context.Background()
+	v := vm.NewVMWithSingletons(ctx, t)
+	addrs := vm.CreateAccounts(ctx, t, v, 3, big.Mul(big.NewInt(10_000), vm.FIL), 93837778)
+	anne, bob, relayer := addrs[0], addrs[1], addrs[2]
+	bobID, found := v.NormalizeAddress(bob)
+	require.True(t, found)
+	relayerID, found := v.NormalizeAddress(relayer)
+	require.True(t, found)
+	sessionKey := tutil.NewBLSAddr(t, 1)
+
+	// create a 2 of 2 multisig holding funds
+	var ctorParams bytes.Buffer
+	require.NoError(t, (&multisig.ConstructorParams{Signers: []addr.Address{anne, bob}, NumApprovalsThreshold: 2}).MarshalCBOR(&ctorParams))
+	ret := vm.ApplyOk(t, v, anne, builtin.InitActorAddr, vm.FIL, builtin.MethodsInit.Exec, &init_.ExecParams{
+		CodeCID:           builtin.MultisigActorCodeID,
+		ConstructorParams: ctorParams.Bytes(),
+	})
+	multisigAddr := ret.(*init_.ExecReturn).IDAddress
+
+	// anne proposes paying the relayer
+	vm.ApplyOk(t, v, anne, multisigAddr, big.Zero(), builtin.MethodsMultisig.Propose, &multisig.ProposeParams{
+		To:     relayer,
+		Value:  vm.FIL,
+		Method: builtin.MethodSend,
+	})
+
+	// bob authorizes a session key to approve multisig transactions
+	vm.ApplyOk(t, v, bob, bob, big.Zero(), builtin.MethodsAccount.AuthorizeSessionKey, &account.AuthorizeSessionKeyParams{
+		Key:        sessionKey,
+		Expiration: v.GetEpoch() + 100,
+		Methods:    []abi.MethodNum{builtin.MethodsMultisig.Approve},
+		MaxValue:   big.Zero(),
+	})
+
+	var approveParams bytes.Buffer
+	require.NoError(t, (&multisig.TxnIDParams{ID: 0}).MarshalCBOR(&approveParams))
+	var message bytes.Buffer
+	require.NoError(t, (&account.SessionMessage{
+		Account: bobID,
+		Nonce:   0,
+		To:      multisigAddr,
+		Method:  builtin.MethodsMultisig.Approve,
+		Value:   big.Zero(),
+		Params:  approveParams.Bytes(),
+	}).MarshalCBOR(&message))
+	sig := crypto.Signature{Type: crypto.SigTypeBLS, Data: []byte("session signature")}
+
+	// the relayer cannot consume the message's nonce by presenting it to the account itself
+	_, code := v.ApplyMessage(relayer, bob, big.Zero(), builtin.MethodsAccount.AuthenticateSessionMessage, &account.AuthenticateSessionMessageParams{
+		Key:       sessionKey,
+		Signature: sig,
+		Message:   message.Bytes(),
+	})
+	assert.Equal(t, exitcode.ErrForbidden, code)
+
+	// the relayer presents bob's session approval to the multisig, which executes the transaction
+	relayerBalance := balanceOf(t, v, relayer)
+	approveBySessionKey := &multisig.ApproveBySessionKeyParams{
+		Signer:    bob,
+		Key:       sessionKey,
+		Signature: sig,
+		Message:   message.Bytes(),
+	}
+	ret = vm.ApplyOk(t, v, relayer, multisigAddr, big.Zero(), builtin.MethodsMultisig.ApproveBySessionKey, approveBySessionKey)
+	approveRet := ret.(*multisig.ApproveReturn)
+	assert.True(t, approveRet.Applied)
+	assert.Equal(t, exitcode.Ok, approveRet.Code)
+	assert.Equal(t, big.Add(relayerBalance, vm.FIL), balanceOf(t, v, relayer))
+
+	vm.ExpectInvocation{
+		To:     multisigAddr,
+		Method: builtin.MethodsMultisig.ApproveBySessionKey,
+		SubInvocations: []vm.ExpectInvocation{
+			{To: bobID, Method: builtin.MethodsAccount.AuthenticateSessionMessage},
+			{To: relayerID, Method: builtin.MethodSend},
+		},
+	}.Matches(t, v.LastInvocation())
+
+	// the message is accepted only once
+	_, code = v.ApplyMessage(relayer, multisigAddr, big.Zero(), builtin.MethodsMultisig.ApproveBySessionKey, approveBySessionKey)
+	assert.Equal(t, exitcode.ErrIllegalArgument, code)
+}
+
+func balanceOf(t *testing.T, v *vm.VM, a addr.Address) abi.TokenAmount {
+	act, found, err := v.GetActor(a)
+	require.NoError(t, err)
+	require.True(t, found)
+	return act.Balance
+}
@@ -0,0 +1,59 @@
+package test_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/filecoin-project/go-state-types/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/miner"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/power"
+	vm "github.com/filecoin-project/specs-actors/v2/support/vm"
+)
+
+func TestChangePeerIDMapEncoding(t *testing.T) {
+	ctx := context.Background()
+	v := vm.NewVMWithSingletons(ctx, t)
+	addrs := vm.CreateAccounts(ctx, t, v, 1, big.Mul(big.NewInt(10_000), big.NewInt(1e18)), 93837778)
+	owner := addrs[0]
+
+	ret := vm.ApplyOk(t, v, owner, builtin.StoragePowerActorAddr, big.Mul(big.NewInt(100), vm.FIL), builtin.MethodsPower.CreateMiner, &power.CreateMinerParams{
+		Owner:         owner,
+		Worker:        owner,
+		SealProofType: abi.RegisteredSealProof_StackedDrg32GiBV1,
+		Peer:          abi.PeerID("original"),
+	})
+	minerAddr := ret.(*power.CreateMinerReturn).IDAddress
+
+	params := miner.ChangePeerIDParams{NewID: abi.PeerID("changed")}
+	var asMap bytes.Buffer
+	require.NoError(t, params.MarshalCBORForVersion(&asMap, params.MapEncodingVersion()))
+
+	// Before the map encoding version, params encoded as a map are rejected.
+	v, err := v.WithNetworkVersion(network.Version5)
+	require.NoError(t, err)
+	_, code := v.ApplyMessage(owner, minerAddr, big.Zero(), builtin.MethodsMiner.ChangePeerID, builtin.CBORBytes(asMap.Bytes()))
+	assert.Equal(t, exitcode.ErrSerialization, code)
+
+	v, err = v.WithNetworkVersion(network.Version6)
+	require.NoError(t, err)
+	vm.ApplyOk(t, v, owner, minerAddr, big.Zero(), builtin.MethodsMiner.ChangePeerID, builtin.CBORBytes(asMap.Bytes()))
+
+	var st miner.State
+	require.NoError(t, v.GetState(minerAddr, &st))
+	info, err := st.GetInfo(v.Store())
+	require.NoError(t, err)
+	assert.Equal(t, params.NewID, abi.PeerID(info.PeerId))
+
+	// The tuple encoding remains valid.
+	var tuple bytes.Buffer
+	require.NoError(t, (&miner.ChangePeerIDParams{NewID: abi.PeerID("again")}).MarshalCBOR(&tuple))
+	vm.ApplyOk(t, v, owner, minerAddr, big.Zero(), builtin.MethodsMiner.ChangePeerID, builtin.CBORBytes(tuple.Bytes()))
+}
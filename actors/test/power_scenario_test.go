@@ -4,13 +4,17 @@ import (
 	"context"
 	"testing"
 
+	"github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/miner"
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/power"
+	"github.com/filecoin-project/specs-actors/v2/support/mock"
 	vm "github.com/filecoin-project/specs-actors/v2/support/vm"
 )
 
@@ -18,6 +22,7 @@ func TestCreateMiner(t *testing.T) {
 	ctx := context.Background()
 	v := vm.NewVMWithSingletons(ctx, t)
 	addrs := vm.CreateAccounts(ctx, t, v, 1, big.Mul(big.NewInt(10_000), big.NewInt(1e18)), 93837778)
+	rec, v := vm.NewRecorder(t, v)
 
 	params := power.CreateMinerParams{
 		Owner:         addrs[0],
@@ -63,6 +68,8 @@ func TestCreateMiner(t *testing.T) {
 			}},
 		}},
 	}.Matches(t, v.Invocations()[0])
+
+	rec.Finish(t, v, "create-miner")
 }
 
 func TestOnEpochTickEnd(t *testing.T) {
@@ -134,3 +141,54 @@ func TestOnEpochTickEnd(t *testing.T) {
 		}},
 	}.Matches(t, v.Invocations()[0])
 }
+
+func TestOnEpochTickEndAfterNullRounds(t *testing.T) {
+	ctx := context.Background()
+	v := vm.NewVMWithSingletons(ctx, t)
+	addrs := vm.CreateAccounts(ctx, t, v, 1, big.Mul(big.NewInt(10_000), big.NewInt(1e18)), 93837778)
+
+	// Create more miners than a cron tick's gas would deliver events for, were each budgeted the 100M gas of a
+	// full deadline.
+	const minerCount = 60
+	params := power.CreateMinerParams{Owner: addrs[0], Worker: addrs[0], SealProofType: abi.RegisteredSealProof_StackedDrg32GiBV1, Peer: abi.PeerID("pid")}
+	miners := map[address.Address]bool{}
+	lastEventEpoch := abi.ChainEpoch(0)
+	for i := 0; i < minerCount; i++ {
+		ret := vm.ApplyOk(t, v, addrs[0], builtin.StoragePowerActorAddr, big.NewInt(1e10), builtin.MethodsPower.CreateMiner, &params)
+		minerAddrs, ok := ret.(*power.CreateMinerReturn)
+		require.True(t, ok)
+		miners[minerAddrs.IDAddress] = true
+
+		cronConfig, ok := vm.ParamsForInvocation(t, v, 0, 0, 0, 0).(*power.EnrollCronEventParams)
+		require.True(t, ok)
+		if cronConfig.EventEpoch > lastEventEpoch {
+			lastEventEpoch = cronConfig.EventEpoch
+		}
+	}
+
+	// Cron does not run in the null rounds up to some epochs after the miners' events, and then runs in a single
+	// tick metered against a gas limit.
+	v, err := v.WithEpoch(lastEventEpoch + 10)
+	require.NoError(t, err)
+	v.SetGasCharger(mock.DefaultPriceList)
+	v.SetGasLimit(5_000_000_000)
+	vm.ApplyOk(t, v, builtin.SystemActorAddr, builtin.CronActorAddr, big.Zero(), builtin.MethodsCron.EpochTick, nil)
+
+	// Every miner's event is delivered.
+	delivered := map[address.Address]bool{}
+	var find func(inv *vm.Invocation)
+	find = func(inv *vm.Invocation) {
+		if inv.Msg.Method() == builtin.MethodsMiner.OnDeferredCronEvent && inv.Exitcode == exitcode.Ok {
+			delivered[inv.Msg.Receiver()] = true
+		}
+		for _, sub := range inv.SubInvocations {
+			find(sub)
+		}
+	}
+	find(v.LastInvocation())
+	assert.Equal(t, miners, delivered)
+
+	var st power.State
+	require.NoError(t, v.GetState(builtin.StoragePowerActorAddr, &st))
+	assert.Equal(t, v.GetEpoch()+1, st.FirstCronEpoch)
+}
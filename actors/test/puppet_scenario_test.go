@@ -0,0 +1,152 @@
+package test_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/cbor"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/cron"
+	"github.com/filecoin-project/specs-actors/v2/support/mock"
+	"github.com/filecoin-project/specs-actors/v2/support/puppet"
+	vm "github.com/filecoin-project/specs-actors/v2/support/vm"
+)
+
+func TestPuppetCronCallee(t *testing.T) {
+	ctx := context.Background()
+	v := vm.NewVMWithSingletons(ctx, t)
+	addrs := vm.CreateAccounts(ctx, t, v, 1, big.Mul(big.NewInt(10), vm.FIL), 93837778)
+	sender := addrs[0]
+	puppets := vm.CreatePuppets(ctx, t, v, 1, vm.FIL, 93837778)
+	p := puppets[0]
+
+	// The puppet registers a cron entry calling back to it.
+	bond := big.Mul(cron.UserEntryInvocationFee, big.NewInt(10))
+	vm.ApplyOk(t, v, sender, p, big.Zero(), puppet.Methods.Run, &puppet.Script{Steps: []puppet.Step{{
+		Op: puppet.OpSend,
+		Send: &puppet.SendStep{
+			To:     builtin.CronActorAddr,
+			Method: builtin.MethodsCron.RegisterUserEntry,
+			Value:  bond,
+			Params: mustSerialize(t, &cron.RegisterUserEntryParams{MethodNum: puppet.Methods.Callback, Priority: cron.DefaultEntryPriority}),
+		},
+		Code: exitcode.ErrIllegalState,
+	}}})
+
+	// The callback sets the puppet's data when cron ticks.
+	vm.ApplyOk(t, v, sender, p, big.Zero(), puppet.Methods.SetCallback, &puppet.Script{Steps: []puppet.Step{
+		{Op: puppet.OpSetData, Data: []byte("ticked")},
+	}})
+	vm.ApplyOk(t, v, builtin.SystemActorAddr, builtin.CronActorAddr, big.Zero(), builtin.MethodsCron.EpochTick, nil)
+
+	var st puppet.State
+	require.NoError(t, v.GetState(p, &st))
+	assert.Equal(t, []byte("ticked"), st.Data)
+	require.Len(t, st.Invocations, 2)
+	assert.Equal(t, builtin.CronActorAddr, st.Invocations[1].Caller)
+	assert.Equal(t, puppet.Methods.Callback, st.Invocations[1].Method)
+	assert.Equal(t, uint64(0), st.Invocations[1].Depth)
+
+	// A callback aborting after changing state leaves the puppet unchanged, and does not stop cron.
+	vm.ApplyOk(t, v, sender, p, big.Zero(), puppet.Methods.SetCallback, &puppet.Script{Steps: []puppet.Step{
+		{Op: puppet.OpSetData, Data: []byte("aborted")},
+		{Op: puppet.OpAbort, Code: exitcode.ErrForbidden},
+	}})
+	vm.ApplyOk(t, v, builtin.SystemActorAddr, builtin.CronActorAddr, big.Zero(), builtin.MethodsCron.EpochTick, nil)
+
+	require.NoError(t, v.GetState(p, &st))
+	assert.Equal(t, []byte("ticked"), st.Data)
+	assert.Len(t, st.Invocations, 2)
+}
+
+func TestPuppetCronCalleeOutOfGas(t *testing.T) {
+	ctx := context.Background()
+	v := vm.NewVMWithSingletons(ctx, t)
+	addrs := vm.CreateAccounts(ctx, t, v, 1, big.Mul(big.NewInt(10), vm.FIL), 93837778)
+	sender := addrs[0]
+	puppets := vm.CreatePuppets(ctx, t, v, 1, vm.FIL, 93837778)
+	p := puppets[0]
+
+	bond := big.Mul(cron.UserEntryInvocationFee, big.NewInt(10))
+	vm.ApplyOk(t, v, sender, p, big.Zero(), puppet.Methods.Run, &puppet.Script{Steps: []puppet.Step{{
+		Op: puppet.OpSend,
+		Send: &puppet.SendStep{
+			To:     builtin.CronActorAddr,
+			Method: builtin.MethodsCron.RegisterUserEntry,
+			Value:  bond,
+			Params: mustSerialize(t, &cron.RegisterUserEntryParams{MethodNum: puppet.Methods.Callback, Priority: cron.DefaultEntryPriority}),
+		},
+		Code: exitcode.ErrIllegalState,
+	}}})
+
+	// The callback makes three sends, which cost more than a user entry's gas limit. The builtin entries' sends
+	// cost as much, but run with the cron tick's unlimited gas.
+	send := puppet.Step{Op: puppet.OpSend, Send: &puppet.SendStep{To: sender, Method: builtin.MethodSend, Value: big.Zero()}}
+	vm.ApplyOk(t, v, sender, p, big.Zero(), puppet.Methods.SetCallback, &puppet.Script{Steps: []puppet.Step{
+		{Op: puppet.OpSetData, Data: []byte("ticked")}, send, send, send,
+	}})
+	v.SetGasCharger(mock.PriceList{SendBase: cron.UserEntryGasLimit / 2})
+	vm.ApplyOk(t, v, builtin.SystemActorAddr, builtin.CronActorAddr, big.Zero(), builtin.MethodsCron.EpochTick, nil)
+
+	// The callback's changes are rolled back, and the entry records running out of gas.
+	var st puppet.State
+	require.NoError(t, v.GetState(p, &st))
+	assert.Empty(t, st.Data)
+	assert.Len(t, st.Invocations, 1)
+
+	var cronSt cron.State
+	require.NoError(t, v.GetState(builtin.CronActorAddr, &cronSt))
+	require.Len(t, cronSt.UserEntries, 1)
+	assert.Equal(t, uint64(1), cronSt.UserEntries[0].ConsecutiveOutOfGas)
+}
+
+func TestPuppetReentrancy(t *testing.T) {
+	ctx := context.Background()
+	v := vm.NewVMWithSingletons(ctx, t)
+	addrs := vm.CreateAccounts(ctx, t, v, 1, big.Mul(big.NewInt(10), vm.FIL), 93837778)
+	sender := addrs[0]
+	puppets := vm.CreatePuppets(ctx, t, v, 2, vm.FIL, 93837778)
+	a, b := puppets[0], puppets[1]
+
+	// Puppet b calls back into puppet a, which is still executing the script that called b.
+	vm.ApplyOk(t, v, sender, a, big.Zero(), puppet.Methods.SetCallback, &puppet.Script{Steps: []puppet.Step{
+		{Op: puppet.OpSetData, Data: []byte("reentered")},
+	}})
+	vm.ApplyOk(t, v, sender, b, big.Zero(), puppet.Methods.SetCallback, &puppet.Script{Steps: []puppet.Step{
+		{Op: puppet.OpSend, Send: &puppet.SendStep{To: a, Method: puppet.Methods.Callback, Value: big.NewInt(7)}, Code: exitcode.ErrIllegalState},
+	}})
+	ret := vm.ApplyOk(t, v, sender, a, big.Zero(), puppet.Methods.Run, &puppet.Script{Steps: []puppet.Step{
+		{Op: puppet.OpSend, Send: &puppet.SendStep{To: b, Method: puppet.Methods.Callback, Value: big.Zero()}},
+		{Op: puppet.OpSend, Send: &puppet.SendStep{To: builtin.StoragePowerActorAddr, Method: builtin.MethodsPower.OnEpochTickEnd, Value: big.Zero()}},
+	}})
+
+	// The send to the power actor from a non-cron caller failed, but did not abort the script.
+	results := ret.(*puppet.ScriptReturn)
+	require.Len(t, results.Results, 2)
+	assert.Equal(t, exitcode.Ok, results.Results[0].Code)
+	assert.Equal(t, exitcode.ErrForbidden, results.Results[1].Code)
+
+	var st puppet.State
+	require.NoError(t, v.GetState(a, &st))
+	assert.Equal(t, []byte("reentered"), st.Data)
+	assert.Equal(t, uint64(0), st.Depth)
+	require.Len(t, st.Invocations, 2)
+	// The invocation records are in order of starting.
+	assert.Equal(t, puppet.Methods.Run, st.Invocations[0].Method)
+	assert.Equal(t, uint64(0), st.Invocations[0].Depth)
+	assert.Equal(t, b, st.Invocations[1].Caller)
+	assert.Equal(t, big.NewInt(7), st.Invocations[1].Value)
+	assert.Equal(t, uint64(1), st.Invocations[1].Depth)
+}
+
+func mustSerialize(t *testing.T, v cbor.Marshaler) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, v.MarshalCBOR(&buf))
+	return buf.Bytes()
+}
@@ -2,6 +2,7 @@ package adt
 
 import (
 	"bytes"
+	"sort"
 
 	amt "github.com/filecoin-project/go-amt-ipld/v2"
 	"github.com/filecoin-project/go-state-types/cbor"
@@ -67,18 +68,58 @@ func (a *Array) Delete(i uint64) error {
 	return nil
 }
 
+// Deletes the values at a set of indices, all of which must be present.
+// Indices are deleted in ascending order, so that successive deletions traverse the same nodes.
 func (a *Array) BatchDelete(ix []uint64) error {
-	if err := a.root.BatchDelete(a.store.Context(), ix); err != nil {
+	if err := a.root.BatchDelete(a.store.Context(), sortedIndices(ix)); err != nil {
 		return xerrors.Errorf("array delete failed to batchdelete: %w", err)
 	}
 	return nil
 }
 
+// Sets the values at a set of indices, each index to the value at the same position in values.
+// Indices are set in ascending order, so that successive updates traverse the same nodes, which are written to
+// the store only when the array root is next flushed.
+func (a *Array) BatchSet(ix []uint64, values []cbor.Marshaler) error {
+	if len(ix) != len(values) {
+		return xerrors.Errorf("array batch set of %d indices with %d values", len(ix), len(values))
+	}
+	for _, p := range sortedPositions(ix) {
+		if err := a.Set(ix[p], values[p]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Retrieves the values at a set of indices, each into the unmarshaler at the same position in outs, returning
+// whether each was found.
+// Indices are retrieved in ascending order, so that successive lookups traverse the same nodes.
+func (a *Array) BatchGet(ix []uint64, outs []cbor.Unmarshaler) ([]bool, error) {
+	if len(ix) != len(outs) {
+		return nil, xerrors.Errorf("array batch get of %d indices into %d values", len(ix), len(outs))
+	}
+	found := make([]bool, len(ix))
+	for _, p := range sortedPositions(ix) {
+		var err error
+		if found[p], err = a.Get(ix[p], outs[p]); err != nil {
+			return nil, xerrors.Errorf("array batch get failed to get index %v: %w", ix[p], err)
+		}
+	}
+	return found, nil
+}
+
 // Iterates all entries in the array, deserializing each value in turn into `out` and then calling a function.
 // Iteration halts if the function returns an error.
 // If the output parameter is nil, deserialization is skipped.
 func (a *Array) ForEach(out cbor.Unmarshaler, fn func(i int64) error) error {
-	return a.root.ForEach(a.store.Context(), func(k uint64, val *cbg.Deferred) error {
+	return a.ForEachFrom(0, out, fn)
+}
+
+// Iterates the entries in the array from an index on, as for ForEach, without visiting the nodes holding only
+// lower indices.
+func (a *Array) ForEachFrom(start uint64, out cbor.Unmarshaler, fn func(i int64) error) error {
+	return a.root.ForEachAt(a.store.Context(), start, func(k uint64, val *cbg.Deferred) error {
 		if out != nil {
 			if deferred, ok := out.(*cbg.Deferred); ok {
 				// fast-path deferred -> deferred to avoid re-decoding.
@@ -107,3 +148,20 @@ func (a *Array) Get(k uint64, out cbor.Unmarshaler) (bool, error) {
 		return false, err
 	}
 }
+
+// Returns a sorted copy of a slice of indices.
+func sortedIndices(ix []uint64) []uint64 {
+	sorted := append([]uint64(nil), ix...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted
+}
+
+// Returns the positions of a slice of indices in ascending order of the index at each position.
+func sortedPositions(ix []uint64) []int {
+	positions := make([]int, len(ix))
+	for i := range positions {
+		positions[i] = i
+	}
+	sort.SliceStable(positions, func(i, j int) bool { return ix[positions[i]] < ix[positions[j]] })
+	return positions
+}
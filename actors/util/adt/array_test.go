@@ -5,10 +5,14 @@ import (
 	"testing"
 
 	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/cbor"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	cbg "github.com/whyrusleeping/cbor-gen"
 
 	"github.com/filecoin-project/specs-actors/v2/actors/util/adt"
 	"github.com/filecoin-project/specs-actors/v2/support/mock"
+	tutil "github.com/filecoin-project/specs-actors/v2/support/testing"
 )
 
 func TestArrayNotFound(t *testing.T) {
@@ -20,3 +24,69 @@ func TestArrayNotFound(t *testing.T) {
 	require.NoError(t, err)
 	require.False(t, found)
 }
+
+func TestArrayBatch(t *testing.T) {
+	rt := mock.NewBuilder(context.Background(), address.Undef).Build(t)
+	store := adt.AsStore(rt)
+	indices := []uint64{1000, 3, 70, 0, 5}
+
+	batched := adt.MakeEmptyArray(store)
+	sequential := adt.MakeEmptyArray(store)
+	values := make([]cbor.Marshaler, len(indices))
+	for i, ix := range indices {
+		v := cbg.CborInt(ix * 2)
+		values[i] = &v
+		require.NoError(t, sequential.Set(ix, &v))
+	}
+	require.NoError(t, batched.BatchSet(indices, values))
+	assert.Equal(t, tutil.MustRoot(t, sequential), tutil.MustRoot(t, batched))
+
+	t.Run("get returns values in key order", func(t *testing.T) {
+		keys := append([]uint64{8}, indices...)
+		outs := make([]cbor.Unmarshaler, len(keys))
+		for i := range outs {
+			outs[i] = new(cbg.CborInt)
+		}
+		found, err := batched.BatchGet(keys, outs)
+		require.NoError(t, err)
+		assert.False(t, found[0])
+		for i, ix := range indices {
+			assert.True(t, found[i+1])
+			assert.Equal(t, cbg.CborInt(ix*2), *outs[i+1].(*cbg.CborInt))
+		}
+	})
+
+	t.Run("delete unsorted keys", func(t *testing.T) {
+		require.NoError(t, batched.BatchDelete([]uint64{70, 1000, 0}))
+		for _, ix := range []uint64{1000, 70, 0} {
+			require.NoError(t, sequential.Delete(ix))
+		}
+		assert.Equal(t, tutil.MustRoot(t, sequential), tutil.MustRoot(t, batched))
+		assert.Equal(t, uint64(2), batched.Length())
+	})
+
+	t.Run("mismatched lengths", func(t *testing.T) {
+		assert.Error(t, batched.BatchSet(indices, values[1:]))
+		_, err := batched.BatchGet(indices, nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestArrayForEachFrom(t *testing.T) {
+	rt := mock.NewBuilder(context.Background(), address.Undef).Build(t)
+	store := adt.AsStore(rt)
+	arr := adt.MakeEmptyArray(store)
+	for _, i := range []uint64{1, 7, 8, 100, 1000} {
+		v := cbg.CborInt(i)
+		require.NoError(t, arr.Set(i, &v))
+	}
+
+	var visited []int64
+	var v cbg.CborInt
+	require.NoError(t, arr.ForEachFrom(8, &v, func(i int64) error {
+		assert.Equal(t, cbg.CborInt(i), v)
+		visited = append(visited, i)
+		return nil
+	}))
+	assert.Equal(t, []int64{8, 100, 1000}, visited)
+}
@@ -1,11 +1,15 @@
 package adt
 
 import (
+	"fmt"
+
 	addr "github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
 	cid "github.com/ipfs/go-cid"
 	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/util/math"
 )
 
 // A specialization of a map of addresses to (positive) token amounts.
@@ -65,7 +69,7 @@ func (t *BalanceTable) SubtractWithMinimum(key addr.Address, req abi.TokenAmount
 		return big.Zero(), err
 	}
 
-	available := big.Max(big.Zero(), big.Sub(prev, floor))
+	available := math.SubWithFloorZero(prev, floor)
 	sub := big.Min(available, req)
 	if sub.Sign() > 0 {
 		err = t.Add(key, sub.Neg())
@@ -77,7 +81,8 @@ func (t *BalanceTable) SubtractWithMinimum(key addr.Address, req abi.TokenAmount
 }
 
 // MustSubtract subtracts the given amount from the account's balance.
-// Returns an error if the account has insufficient balance
+// Returns an error if the account has insufficient balance, leaving the balance unchanged, so that a caller
+// may handle the error without reverting the table.
 func (t *BalanceTable) MustSubtract(key addr.Address, req abi.TokenAmount) error {
 	prev, err := t.Get(key)
 	if err != nil {
@@ -99,3 +104,69 @@ func (t *BalanceTable) Total() (abi.TokenAmount, error) {
 	})
 	return total, err
 }
+
+// The result of auditing a table of balances and a table of amounts locked from them, see AuditBalances.
+type BalancesAudit struct {
+	Total       abi.TokenAmount // Sum of the balances.
+	Count       uint64          // Number of entries in the balance table.
+	LockedTotal abi.TokenAmount // Sum of the locked amounts.
+	LockedCount uint64          // Number of entries in the locked table.
+	Problems    []string        // Descriptions of the inconsistencies found, empty if none.
+}
+
+// Audits a table of the balances an actor holds for parties against the actor's own balance, and a table of the
+// amounts locked from those balances against them, so that drift between the tables and the funds actually held
+// can be detected. It reports:
+// - negative entries, which Add never stores,
+// - locked amounts exceeding the corresponding balance,
+// - balances totalling more than the actor's balance.
+// An error is returned only if the tables cannot be read.
+func AuditBalances(balances, locked *BalanceTable, actorBalance abi.TokenAmount) (*BalancesAudit, error) {
+	audit := &BalancesAudit{Total: big.Zero(), LockedTotal: big.Zero()}
+	problemf := func(format string, args ...interface{}) {
+		audit.Problems = append(audit.Problems, fmt.Sprintf(format, args...))
+	}
+
+	var amount abi.TokenAmount
+	if err := (*Map)(balances).ForEach(&amount, func(key string) error {
+		a, err := addr.NewFromBytes([]byte(key))
+		if err != nil {
+			return err
+		}
+		if amount.Sign() < 0 {
+			problemf("balance for %s, %s, negative", a, amount)
+		}
+		audit.Total = big.Add(audit.Total, amount)
+		audit.Count++
+		return nil
+	}); err != nil {
+		return nil, xerrors.Errorf("failed to iterate balances: %w", err)
+	}
+
+	if err := (*Map)(locked).ForEach(&amount, func(key string) error {
+		a, err := addr.NewFromBytes([]byte(key))
+		if err != nil {
+			return err
+		}
+		if amount.Sign() < 0 {
+			problemf("locked funds for %s, %s, negative", a, amount)
+		}
+		balance, err := balances.Get(a)
+		if err != nil {
+			return err
+		}
+		if amount.GreaterThan(balance) {
+			problemf("locked funds for %s, %s, greater than escrow amount, %s", a, amount, balance)
+		}
+		audit.LockedTotal = big.Add(audit.LockedTotal, amount)
+		audit.LockedCount++
+		return nil
+	}); err != nil {
+		return nil, xerrors.Errorf("failed to iterate locked funds: %w", err)
+	}
+
+	if audit.Total.GreaterThan(actorBalance) {
+		problemf("escrow total, %v, greater than actor balance, %v", audit.Total, actorBalance)
+	}
+	return audit, nil
+}
@@ -170,3 +170,51 @@ func TestSubtractWithMinimum(t *testing.T) {
 		require.EqualValues(t, abi.NewTokenAmount(2), remaining)
 	})
 }
+
+func TestAuditBalances(t *testing.T) {
+	rt := mock.NewBuilder(context.Background(), address.Undef).Build(t)
+	store := adt.AsStore(rt)
+	buildBalanceTable := func() *adt.BalanceTable {
+		bt, err := adt.AsBalanceTable(store, tutil.MustRoot(t, adt.MakeEmptyMap(store)))
+		require.NoError(t, err)
+		return bt
+	}
+	addr1 := tutil.NewIDAddr(t, 100)
+	addr2 := tutil.NewIDAddr(t, 101)
+	addr3 := tutil.NewIDAddr(t, 102)
+
+	t.Run("consistent tables", func(t *testing.T) {
+		escrow, locked := buildBalanceTable(), buildBalanceTable()
+		require.NoError(t, escrow.Add(addr1, abi.NewTokenAmount(10)))
+		require.NoError(t, escrow.Add(addr2, abi.NewTokenAmount(20)))
+		require.NoError(t, locked.Add(addr1, abi.NewTokenAmount(10)))
+
+		audit, err := adt.AuditBalances(escrow, locked, abi.NewTokenAmount(30))
+		require.NoError(t, err)
+		assert.Empty(t, audit.Problems)
+		assert.Equal(t, abi.NewTokenAmount(30), audit.Total)
+		assert.Equal(t, uint64(2), audit.Count)
+		assert.Equal(t, abi.NewTokenAmount(10), audit.LockedTotal)
+		assert.Equal(t, uint64(1), audit.LockedCount)
+	})
+
+	t.Run("reports drift", func(t *testing.T) {
+		escrow, locked := buildBalanceTable(), buildBalanceTable()
+		require.NoError(t, escrow.Add(addr1, abi.NewTokenAmount(10)))
+		require.NoError(t, locked.Add(addr1, abi.NewTokenAmount(15)))
+		require.NoError(t, locked.Add(addr2, abi.NewTokenAmount(5)))
+		// A negative balance can only be stored by writing the table's map directly.
+		negative := abi.NewTokenAmount(-1)
+		require.NoError(t, (*adt.Map)(escrow).Put(abi.AddrKey(addr3), &negative))
+
+		audit, err := adt.AuditBalances(escrow, locked, abi.NewTokenAmount(5))
+		require.NoError(t, err)
+		assert.Equal(t, abi.NewTokenAmount(9), audit.Total)
+		assert.ElementsMatch(t, []string{
+			"balance for t0102, -1, negative",
+			"locked funds for t0100, 15, greater than escrow amount, 10",
+			"locked funds for t0101, 5, greater than escrow amount, 0",
+			"escrow total, 9, greater than actor balance, 5",
+		}, audit.Problems)
+	})
+}
@@ -0,0 +1,75 @@
+package adt
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/filecoin-project/go-state-types/cbor"
+	cbg "github.com/whyrusleeping/cbor-gen"
+	"golang.org/x/xerrors"
+
+	acbor "github.com/filecoin-project/specs-actors/v2/actors/util/cbor"
+)
+
+// A CBOR value embedded in another, kept in its encoded form until accessed.
+// A state object with a large embedded value may hold it as a Lazy, so that methods using only the object's other
+// fields do not pay to decode it. A Lazy decodes from and encodes to exactly the value's (canonical) encoding, so
+// replacing a field with a Lazy changes neither the object's encoding nor its CID, and objects holding a Lazy may
+// be loaded from and stored in an ADT store, or an Array or Map, as any other.
+// The zero Lazy holds no value, encoded as CBOR null.
+type Lazy struct {
+	raw []byte
+}
+
+// Returns a Lazy holding the encoding of a value.
+func NewLazy(value cbor.Marshaler) (Lazy, error) {
+	var l Lazy
+	err := l.Set(value)
+	return l, err
+}
+
+// Decodes the value into out. Each call decodes the value afresh, so out is not shared with other callers.
+func (l *Lazy) Get(out cbor.Unmarshaler) error {
+	if l.IsEmpty() {
+		return xerrors.Errorf("lazy value is empty")
+	}
+	return out.UnmarshalCBOR(bytes.NewReader(l.raw))
+}
+
+// Replaces the value, encoding it now.
+func (l *Lazy) Set(value cbor.Marshaler) error {
+	raw, err := acbor.Marshal(value)
+	if err != nil {
+		return xerrors.Errorf("failed to encode lazy value: %w", err)
+	}
+	l.raw = raw
+	return nil
+}
+
+// Whether the Lazy holds no value.
+func (l *Lazy) IsEmpty() bool {
+	return len(l.raw) == 0 || bytes.Equal(l.raw, cbg.CborNull)
+}
+
+// Returns the encoded value, without decoding it.
+func (l *Lazy) Raw() []byte {
+	if len(l.raw) == 0 {
+		return cbg.CborNull
+	}
+	return l.raw
+}
+
+func (l *Lazy) MarshalCBOR(w io.Writer) error {
+	_, err := w.Write(l.Raw())
+	return err
+}
+
+// Reads one CBOR value without decoding it.
+func (l *Lazy) UnmarshalCBOR(r io.Reader) error {
+	var d cbg.Deferred
+	if err := d.UnmarshalCBOR(r); err != nil {
+		return err
+	}
+	l.raw = d.Raw
+	return nil
+}
@@ -0,0 +1,79 @@
+package adt_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/miner"
+	"github.com/filecoin-project/specs-actors/v2/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/v2/support/mock"
+)
+
+func TestLazy(t *testing.T) {
+	funds := &miner.VestingFunds{Funds: []miner.VestingFund{
+		{Epoch: 10, Amount: abi.NewTokenAmount(100)},
+		{Epoch: 20, Amount: abi.NewTokenAmount(200)},
+	}}
+	var encoded bytes.Buffer
+	require.NoError(t, funds.MarshalCBOR(&encoded))
+
+	t.Run("decodes and encodes the value's encoding", func(t *testing.T) {
+		var lazy adt.Lazy
+		require.NoError(t, lazy.UnmarshalCBOR(bytes.NewReader(encoded.Bytes())))
+		assert.Equal(t, encoded.Bytes(), lazy.Raw())
+
+		var reencoded bytes.Buffer
+		require.NoError(t, lazy.MarshalCBOR(&reencoded))
+		assert.Equal(t, encoded.Bytes(), reencoded.Bytes())
+
+		var decoded miner.VestingFunds
+		require.NoError(t, lazy.Get(&decoded))
+		assert.Equal(t, funds, &decoded)
+	})
+
+	t.Run("set replaces the value", func(t *testing.T) {
+		lazy, err := adt.NewLazy(&miner.VestingFunds{})
+		require.NoError(t, err)
+		require.NoError(t, lazy.Set(funds))
+		assert.Equal(t, encoded.Bytes(), lazy.Raw())
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		var lazy adt.Lazy
+		assert.True(t, lazy.IsEmpty())
+		var buf bytes.Buffer
+		require.NoError(t, lazy.MarshalCBOR(&buf))
+
+		var decoded adt.Lazy
+		require.NoError(t, decoded.UnmarshalCBOR(&buf))
+		assert.True(t, decoded.IsEmpty())
+		assert.Error(t, decoded.Get(&miner.VestingFunds{}))
+	})
+
+	t.Run("stored as the value", func(t *testing.T) {
+		rt := mock.NewBuilder(context.Background(), address.Undef).Build(t)
+		store := adt.AsStore(rt)
+		c, err := store.Put(store.Context(), funds)
+		require.NoError(t, err)
+
+		var lazy adt.Lazy
+		require.NoError(t, store.Get(store.Context(), c, &lazy))
+		lazyCid, err := store.Put(store.Context(), &lazy)
+		require.NoError(t, err)
+		assert.Equal(t, c, lazyCid)
+
+		arr := adt.MakeEmptyArray(store)
+		require.NoError(t, arr.Set(0, &lazy))
+		var decoded miner.VestingFunds
+		found, err := arr.Get(0, &decoded)
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Equal(t, funds, &decoded)
+	})
+}
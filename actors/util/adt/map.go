@@ -3,6 +3,7 @@ package adt
 import (
 	"bytes"
 	"crypto/sha256"
+	"sort"
 
 	hamt "github.com/filecoin-project/go-hamt-ipld/v2"
 	"github.com/filecoin-project/go-state-types/abi"
@@ -13,18 +14,23 @@ import (
 	"golang.org/x/xerrors"
 )
 
-// Branching factor of the HAMT.
+// Default branching factor of the HAMT, as log2 of the number of children of each node.
 // This value has been empirically chosen, but the optimal value for maps with different mutation profiles
-// may differ, in which case we can expose it for configuration.
-const hamtBitwidth = 5
-
-// HamtOptions specifies all the options used to construct filecoin HAMTs.
-var HamtOptions = []hamt.Option{
-	hamt.UseTreeBitWidth(hamtBitwidth),
-	hamt.UseHashFunction(func(input []byte) []byte {
-		res := sha256.Sum256(input)
-		return res[:]
-	}),
+// may differ, in which case a map may be constructed with a different bitwidth.
+const DefaultHamtBitwidth = 5
+
+// HamtOptions specifies all the options used to construct filecoin HAMTs with the default bitwidth.
+var HamtOptions = HamtOptionsWithBitwidth(DefaultHamtBitwidth)
+
+// Returns the options used to construct filecoin HAMTs with a bitwidth.
+func HamtOptionsWithBitwidth(bitwidth int) []hamt.Option {
+	return []hamt.Option{
+		hamt.UseTreeBitWidth(bitwidth),
+		hamt.UseHashFunction(func(input []byte) []byte {
+			res := sha256.Sum256(input)
+			return res[:]
+		}),
+	}
 }
 
 // Map stores key-value pairs in a HAMT.
@@ -36,7 +42,12 @@ type Map struct {
 
 // AsMap interprets a store as a HAMT-based map with root `r`.
 func AsMap(s Store, r cid.Cid) (*Map, error) {
-	nd, err := hamt.LoadNode(s.Context(), s, r, HamtOptions...)
+	return AsMapWithBitwidth(s, r, DefaultHamtBitwidth)
+}
+
+// AsMapWithBitwidth interprets a store as a HAMT-based map with root `r`, and the bitwidth with which it was built.
+func AsMapWithBitwidth(s Store, r cid.Cid, bitwidth int) (*Map, error) {
+	nd, err := hamt.LoadNode(s.Context(), s, r, HamtOptionsWithBitwidth(bitwidth)...)
 	if err != nil {
 		return nil, xerrors.Errorf("failed to load hamt node: %w", err)
 	}
@@ -50,7 +61,14 @@ func AsMap(s Store, r cid.Cid) (*Map, error) {
 
 // Creates a new map backed by an empty HAMT and flushes it to the store.
 func MakeEmptyMap(s Store) *Map {
-	nd := hamt.NewNode(s, HamtOptions...)
+	return MakeEmptyMapWithBitwidth(s, DefaultHamtBitwidth)
+}
+
+// Creates a new map backed by an empty HAMT with a bitwidth.
+// The root of an empty HAMT doesn't depend on its bitwidth, but the map must subsequently be loaded with the same
+// bitwidth.
+func MakeEmptyMapWithBitwidth(s Store, bitwidth int) *Map {
+	nd := hamt.NewNode(s, HamtOptionsWithBitwidth(bitwidth)...)
 	return &Map{
 		lastCid: cid.Undef,
 		root:    nd,
@@ -58,6 +76,26 @@ func MakeEmptyMap(s Store) *Map {
 	}
 }
 
+// Re-builds a HAMT-based map built with one bitwidth into one with another, returning the new root.
+// The entries are copied without being deserialized.
+func MigrateMapBitwidth(s Store, r cid.Cid, from, to int) (cid.Cid, error) {
+	if from == to {
+		return r, nil
+	}
+	in, err := AsMapWithBitwidth(s, r, from)
+	if err != nil {
+		return cid.Undef, err
+	}
+	out := MakeEmptyMapWithBitwidth(s, to)
+	var value cbg.Deferred
+	if err = in.ForEach(&value, func(k string) error {
+		return out.Put(rawKey(k), &value)
+	}); err != nil {
+		return cid.Undef, xerrors.Errorf("failed to migrate map %v to bitwidth %d: %w", r, to, err)
+	}
+	return out.Root()
+}
+
 // Returns the root cid of underlying HAMT.
 func (m *Map) Root() (cid.Cid, error) {
 	if err := m.root.Flush(m.store.Context()); err != nil {
@@ -112,6 +150,64 @@ func (m *Map) Delete(k abi.Keyer) error {
 	return nil
 }
 
+// Puts values at a set of keys, each key to the value at the same position in values.
+// Keys are put in the order of their hashes, so that successive updates traverse the same nodes, which are written
+// to the store only when the map root is next flushed.
+func (m *Map) BatchPut(keys []abi.Keyer, values []cbor.Marshaler) error {
+	if len(keys) != len(values) {
+		return xerrors.Errorf("map batch put of %d keys with %d values", len(keys), len(values))
+	}
+	for _, p := range hashOrder(keys) {
+		if err := m.Put(keys[p], values[p]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Retrieves the values at a set of keys, each into the unmarshaler at the same position in outs, returning
+// whether each was found.
+// Keys are retrieved in the order of their hashes, so that successive lookups traverse the same nodes.
+func (m *Map) BatchGet(keys []abi.Keyer, outs []cbor.Unmarshaler) ([]bool, error) {
+	if len(keys) != len(outs) {
+		return nil, xerrors.Errorf("map batch get of %d keys into %d values", len(keys), len(outs))
+	}
+	found := make([]bool, len(keys))
+	for _, p := range hashOrder(keys) {
+		var err error
+		if found[p], err = m.Get(keys[p], outs[p]); err != nil {
+			return nil, err
+		}
+	}
+	return found, nil
+}
+
+// Deletes the values at a set of keys, all of which must be present.
+// Keys are deleted in the order of their hashes, so that successive deletions traverse the same nodes.
+func (m *Map) BatchDelete(keys []abi.Keyer) error {
+	for _, p := range hashOrder(keys) {
+		if err := m.Delete(keys[p]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns the positions of a slice of keys in order of their hashes, which is the order of their locations in the
+// HAMT.
+func hashOrder(keys []abi.Keyer) []int {
+	hashes := make([][sha256.Size]byte, len(keys))
+	positions := make([]int, len(keys))
+	for i, k := range keys {
+		hashes[i] = sha256.Sum256([]byte(k.Key()))
+		positions[i] = i
+	}
+	sort.SliceStable(positions, func(i, j int) bool {
+		return bytes.Compare(hashes[positions[i]][:], hashes[positions[j]][:]) < 0
+	})
+	return positions
+}
+
 // Iterates all entries in the map, deserializing each value in turn into `out` and then
 // calling a function with the corresponding key.
 // Iteration halts if the function returns an error.
@@ -137,3 +233,10 @@ func (m *Map) CollectKeys() (out []string, err error) {
 	})
 	return
 }
+
+// A key already in the string form of some abi.Keyer, as enumerated from a map.
+type rawKey string
+
+func (k rawKey) Key() string {
+	return string(k)
+}
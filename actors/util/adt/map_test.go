@@ -0,0 +1,107 @@
+package adt_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/cbor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	cbg "github.com/whyrusleeping/cbor-gen"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/v2/support/mock"
+	tutil "github.com/filecoin-project/specs-actors/v2/support/testing"
+)
+
+func TestMapBatch(t *testing.T) {
+	rt := mock.NewBuilder(context.Background(), address.Undef).Build(t)
+	store := adt.AsStore(rt)
+	var keys []abi.Keyer
+	var values []cbor.Marshaler
+	for i := int64(0); i < 50; i++ {
+		keys = append(keys, abi.IntKey(i))
+		v := cbg.CborInt(i * 3)
+		values = append(values, &v)
+	}
+
+	batched := adt.MakeEmptyMap(store)
+	sequential := adt.MakeEmptyMap(store)
+	for i, k := range keys {
+		require.NoError(t, sequential.Put(k, values[i]))
+	}
+	require.NoError(t, batched.BatchPut(keys, values))
+	assert.Equal(t, tutil.MustRoot(t, sequential), tutil.MustRoot(t, batched))
+
+	t.Run("get returns values in key order", func(t *testing.T) {
+		getKeys := append([]abi.Keyer{abi.IntKey(-1)}, keys...)
+		outs := make([]cbor.Unmarshaler, len(getKeys))
+		for i := range outs {
+			outs[i] = new(cbg.CborInt)
+		}
+		found, err := batched.BatchGet(getKeys, outs)
+		require.NoError(t, err)
+		assert.False(t, found[0])
+		for i := range keys {
+			assert.True(t, found[i+1])
+			assert.Equal(t, cbg.CborInt(i*3), *outs[i+1].(*cbg.CborInt))
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, batched.BatchDelete(keys[10:]))
+		for _, k := range keys[10:] {
+			require.NoError(t, sequential.Delete(k))
+		}
+		assert.Equal(t, tutil.MustRoot(t, sequential), tutil.MustRoot(t, batched))
+
+		// All keys must be present.
+		assert.Error(t, batched.BatchDelete(keys[9:11]))
+	})
+
+	t.Run("mismatched lengths", func(t *testing.T) {
+		assert.Error(t, batched.BatchPut(keys, values[1:]))
+		_, err := batched.BatchGet(keys, nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestMapBitwidth(t *testing.T) {
+	rt := mock.NewBuilder(context.Background(), address.Undef).Build(t)
+	store := adt.AsStore(rt)
+
+	wide := adt.MakeEmptyMapWithBitwidth(store, 8)
+	narrow := adt.MakeEmptyMapWithBitwidth(store, 2)
+	assert.Equal(t, tutil.MustRoot(t, wide), tutil.MustRoot(t, narrow))
+
+	for i := int64(0); i < 100; i++ {
+		v := cbg.CborInt(i)
+		require.NoError(t, wide.Put(abi.IntKey(i), &v))
+		require.NoError(t, narrow.Put(abi.IntKey(i), &v))
+	}
+	wideRoot := tutil.MustRoot(t, wide)
+	narrowRoot := tutil.MustRoot(t, narrow)
+	assert.NotEqual(t, wideRoot, narrowRoot)
+
+	t.Run("load with bitwidth", func(t *testing.T) {
+		m, err := adt.AsMapWithBitwidth(store, narrowRoot, 2)
+		require.NoError(t, err)
+		var v cbg.CborInt
+		found, err := m.Get(abi.IntKey(42), &v)
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, cbg.CborInt(42), v)
+	})
+
+	t.Run("migrate", func(t *testing.T) {
+		migrated, err := adt.MigrateMapBitwidth(store, narrowRoot, 2, 8)
+		require.NoError(t, err)
+		assert.Equal(t, wideRoot, migrated)
+
+		unchanged, err := adt.MigrateMapBitwidth(store, wideRoot, 8, 8)
+		require.NoError(t, err)
+		assert.Equal(t, wideRoot, unchanged)
+	})
+}
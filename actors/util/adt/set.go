@@ -12,7 +12,12 @@ type Set struct {
 
 // AsSet interprets a store as a HAMT-based set with root `r`.
 func AsSet(s Store, r cid.Cid) (*Set, error) {
-	m, err := AsMap(s, r)
+	return AsSetWithBitwidth(s, r, DefaultHamtBitwidth)
+}
+
+// AsSetWithBitwidth interprets a store as a HAMT-based set with root `r`, and the bitwidth with which it was built.
+func AsSetWithBitwidth(s Store, r cid.Cid, bitwidth int) (*Set, error) {
+	m, err := AsMapWithBitwidth(s, r, bitwidth)
 	if err != nil {
 		return nil, err
 	}
@@ -24,7 +29,12 @@ func AsSet(s Store, r cid.Cid) (*Set, error) {
 
 // NewSet creates a new HAMT with root `r` and store `s`.
 func MakeEmptySet(s Store) *Set {
-	m := MakeEmptyMap(s)
+	return MakeEmptySetWithBitwidth(s, DefaultHamtBitwidth)
+}
+
+// Creates a new set backed by an empty HAMT with a bitwidth.
+func MakeEmptySetWithBitwidth(s Store, bitwidth int) *Set {
+	m := MakeEmptyMapWithBitwidth(s, bitwidth)
 	return &Set{m}
 }
 
@@ -7,8 +7,10 @@ import (
 	"github.com/filecoin-project/go-state-types/exitcode"
 	cid "github.com/ipfs/go-cid"
 	ipldcbor "github.com/ipfs/go-ipld-cbor"
+	cbg "github.com/whyrusleeping/cbor-gen"
 
 	vmr "github.com/filecoin-project/specs-actors/v2/actors/runtime"
+	acbor "github.com/filecoin-project/specs-actors/v2/actors/util/cbor"
 )
 
 // Store defines an interface required to back the ADTs in this package.
@@ -36,6 +38,23 @@ func (s *wstore) Context() context.Context {
 	return s.ctx
 }
 
+// Encodes a value into a pooled buffer before putting it in the underlying store, which then copies the encoding
+// once, rather than encoding it into a new buffer grown from empty.
+// The buffer is reused once Put returns, so the underlying store must not retain the value it is given, as an IPLD
+// store which encodes values as they are put does not.
+func (s *wstore) Put(ctx context.Context, v interface{}) (cid.Cid, error) {
+	m, ok := v.(cbg.CBORMarshaler)
+	if !ok {
+		return s.IpldStore.Put(ctx, v)
+	}
+	buf := acbor.GetBuffer()
+	defer acbor.PutBuffer(buf)
+	if err := m.MarshalCBOR(buf); err != nil {
+		return cid.Undef, err
+	}
+	return s.IpldStore.Put(ctx, &cbg.Deferred{Raw: buf.Bytes()})
+}
+
 // Adapter for a Runtime as an ADT Store.
 
 // Adapts a Runtime as an ADT store.
@@ -0,0 +1,39 @@
+package adt_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	cbor "github.com/ipfs/go-ipld-cbor"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/miner"
+	"github.com/filecoin-project/specs-actors/v2/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/v2/support/ipld"
+)
+
+// Compares putting a large value in an ADT store, which encodes it with a pooled buffer, with putting it in the
+// underlying IPLD store directly.
+func BenchmarkStorePut(b *testing.B) {
+	funds := &miner.VestingFunds{}
+	for i := 0; i < 2000; i++ {
+		funds.Funds = append(funds.Funds, miner.VestingFund{Epoch: abi.ChainEpoch(i), Amount: abi.NewTokenAmount(int64(i) * 1e15)})
+	}
+	ctx := context.Background()
+	for _, bc := range []struct {
+		name  string
+		store cbor.IpldStore
+	}{
+		{"ADT store", adt.WrapStore(ctx, cbor.NewCborStore(ipld.NewBlockStoreInMemory()))},
+		{"IPLD store", cbor.NewCborStore(ipld.NewBlockStoreInMemory())},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, err := bc.store.Put(ctx, funds)
+				require.NoError(b, err)
+			}
+		})
+	}
+}
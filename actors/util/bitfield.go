@@ -1,8 +1,13 @@
 package util
 
 import (
+	"bytes"
+	"math"
+
 	"github.com/filecoin-project/go-bitfield"
 	"github.com/filecoin-project/go-bitfield/rle"
+	cbg "github.com/whyrusleeping/cbor-gen"
+	"golang.org/x/xerrors"
 )
 
 type BitField = bitfield.BitField
@@ -67,3 +72,202 @@ func BitFieldContainsAll(a, b BitField) (bool, error) {
 	}
 	return isEmpty(combined)
 }
+
+// Returns the runs of the first n set bits from a run iterator, and whether the iterator has any set bits beyond them.
+// Runs after the n-th set bit are read only as far as the next set bit.
+func firstNRuns(iter rlepluslazy.RunIterator, n uint64) ([]rlepluslazy.Run, bool, error) {
+	var runs []rlepluslazy.Run
+	var gap uint64 // Unset bits since the last set run
+	for n > 0 && iter.HasNext() {
+		r, err := iter.NextRun()
+		if err != nil {
+			return nil, false, err
+		}
+		if !r.Val || r.Len == 0 {
+			gap += r.Len
+			continue
+		}
+		if gap > 0 {
+			runs = append(runs, rlepluslazy.Run{Val: false, Len: gap})
+			gap = 0
+		}
+		if r.Len > n {
+			return append(runs, rlepluslazy.Run{Val: true, Len: n}), true, nil
+		}
+		runs = append(runs, r)
+		n -= r.Len
+	}
+	empty, err := isEmpty(iter)
+	if err != nil {
+		return nil, false, err
+	}
+	return runs, !empty, nil
+}
+
+// Returns a bitfield of the first n bits set in a bitfield, or all of them if fewer than n are set.
+// Unlike BitField.Slice, reads the bitfield only as far as its n-th set bit.
+func BitFieldSliceFirstN(bf BitField, n uint64) (BitField, error) {
+	iter, err := bf.RunIterator()
+	if err != nil {
+		return BitField{}, err
+	}
+	runs, _, err := firstNRuns(iter, n)
+	if err != nil {
+		return BitField{}, err
+	}
+	return bitfield.NewFromIter(&rlepluslazy.RunSliceIterator{Runs: runs})
+}
+
+// Returns a bitfield of up to count bits set in a bitfield, starting from its start-th set bit, or fewer if fewer
+// are set. Unlike BitField.Slice, it is not an error for the bitfield to have fewer bits set than requested.
+// Reads the bitfield only as far as the last bit of the slice.
+func BitFieldSliceBounded(bf BitField, start, count uint64) (BitField, error) {
+	if count > math.MaxUint64-start {
+		count = math.MaxUint64 - start
+	}
+	head, err := BitFieldSliceFirstN(bf, start+count)
+	if err != nil {
+		return BitField{}, err
+	}
+	// The head has at most start+count bits set, so slicing it is cheap.
+	headCount, err := head.Count()
+	if err != nil {
+		return BitField{}, err
+	}
+	if headCount <= start {
+		return bitfield.New(), nil
+	}
+	return head.Slice(start, headCount-start)
+}
+
+// Returns the intersection of bitfields `a` and `b`, truncated to its first `limit` bits, and whether it was truncated.
+// The bitfields are read only as far as the intersection's first bit beyond the limit.
+func BitFieldIntersectWithLimit(a, b BitField, limit uint64) (BitField, bool, error) {
+	aruns, err := a.RunIterator()
+	if err != nil {
+		return BitField{}, false, err
+	}
+	bruns, err := b.RunIterator()
+	if err != nil {
+		return BitField{}, false, err
+	}
+	combined, err := rlepluslazy.And(aruns, bruns)
+	if err != nil {
+		return BitField{}, false, err
+	}
+	runs, truncated, err := firstNRuns(combined, limit)
+	if err != nil {
+		return BitField{}, false, err
+	}
+	intersection, err := bitfield.NewFromIter(&rlepluslazy.RunSliceIterator{Runs: runs})
+	if err != nil {
+		return BitField{}, false, err
+	}
+	return intersection, truncated, nil
+}
+
+// Returns the number of bits set in a bitfield, or `max` if at least that many are set.
+// The bitfield is read only as far as its max-th set bit, so a method enforcing a limit on the bits set may count
+// up to one more than the limit to detect an excess without expanding the rest of a large bitfield.
+func BitFieldCountUpTo(bf BitField, max uint64) (uint64, error) {
+	iter, err := bf.RunIterator()
+	if err != nil {
+		return 0, err
+	}
+	var count uint64
+	for count < max && iter.HasNext() {
+		r, err := iter.NextRun()
+		if err != nil {
+			return 0, err
+		}
+		if r.Val {
+			if r.Len >= max-count {
+				return max, nil
+			}
+			count += r.Len
+		}
+	}
+	return count, nil
+}
+
+// Returns the number of bits set in a bitfield, or an error if more than max are set.
+// The bitfield is read only as far as its (max+1)-th set bit.
+func BitFieldCountAtMost(bf BitField, max uint64) (uint64, error) {
+	if max == math.MaxUint64 {
+		return bf.Count()
+	}
+	count, err := BitFieldCountUpTo(bf, max+1)
+	if err != nil {
+		return 0, err
+	}
+	if count > max {
+		return 0, xerrors.Errorf("bitfield has more than %d bits set", max)
+	}
+	return count, nil
+}
+
+// Limits on the size of a bitfield, checked by ValidateBitField. A zero limit is not checked.
+type BitFieldLimits struct {
+	// Maximum length in bytes of the bitfield's RLE+ encoding.
+	MaxEncodedSize uint64
+	// Maximum number of runs of set or unset bits.
+	MaxRuns uint64
+	// Maximum number of bits set.
+	MaxSetBits uint64
+}
+
+// Checks that a bitfield is within limits, such as a bitfield decoded from method parameters before it is
+// expanded or combined with others.
+// The encoding is read only as far as the first run beyond a limit, so the cost of rejecting an arbitrarily large
+// bitfield is bounded by the limits rather than its size. A bitfield with bits set or unset since it was decoded
+// is re-encoded to check its size.
+func ValidateBitField(bf BitField, limits BitFieldLimits) error {
+	if limits.MaxEncodedSize > 0 {
+		size, err := bitFieldEncodedSize(bf)
+		if err != nil {
+			return err
+		}
+		if size > limits.MaxEncodedSize {
+			return xerrors.Errorf("bitfield encoding of %d bytes exceeds limit %d", size, limits.MaxEncodedSize)
+		}
+	}
+	if limits.MaxRuns == 0 && limits.MaxSetBits == 0 {
+		return nil
+	}
+
+	iter, err := bf.RunIterator()
+	if err != nil {
+		return err
+	}
+	var runs, setBits uint64
+	for iter.HasNext() {
+		r, err := iter.NextRun()
+		if err != nil {
+			return err
+		}
+		runs++
+		if limits.MaxRuns > 0 && runs > limits.MaxRuns {
+			return xerrors.Errorf("bitfield has more than %d runs", limits.MaxRuns)
+		}
+		if r.Val && limits.MaxSetBits > 0 {
+			if r.Len > limits.MaxSetBits-setBits {
+				return xerrors.Errorf("bitfield has more than %d bits set", limits.MaxSetBits)
+			}
+			setBits += r.Len
+		}
+	}
+	return nil
+}
+
+// Returns the length of a bitfield's RLE+ encoding, without the CBOR header.
+func bitFieldEncodedSize(bf BitField) (uint64, error) {
+	buf := new(bytes.Buffer)
+	if err := bf.MarshalCBOR(buf); err != nil {
+		return 0, err
+	}
+	_, size, err := cbg.CborReadHeader(buf)
+	if err != nil {
+		return 0, err
+	}
+	return size, nil
+}
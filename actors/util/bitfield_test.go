@@ -2,10 +2,13 @@ package util_test
 
 import (
 	"bytes"
+	"math"
 	"testing"
 
 	"github.com/filecoin-project/go-bitfield"
+	rlepluslazy "github.com/filecoin-project/go-bitfield/rle"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/filecoin-project/specs-actors/v2/actors/util"
 )
@@ -94,3 +97,146 @@ func TestBitFieldContains(t *testing.T) {
 	assertContainsAll(b, c, false)
 	assertContainsAll(c, b, false)
 }
+
+func TestBitFieldLimits(t *testing.T) {
+	// Bits 1-3, 6, and 10-12.
+	bf := bitfield.NewFromSet([]uint64{1, 2, 3, 6, 10, 11, 12})
+
+	assertBits := func(expected []uint64, actual bitfield.BitField) {
+		t.Helper()
+		bits, err := actual.All(100)
+		require.NoError(t, err)
+		if len(expected) == 0 {
+			assert.Empty(t, bits)
+		} else {
+			assert.Equal(t, expected, bits)
+		}
+	}
+
+	t.Run("slice first n", func(t *testing.T) {
+		for n, expected := range [][]uint64{{}, {1}, {1, 2}, {1, 2, 3}, {1, 2, 3, 6}, {1, 2, 3, 6, 10}} {
+			sliced, err := util.BitFieldSliceFirstN(bf, uint64(n))
+			require.NoError(t, err)
+			assertBits(expected, sliced)
+		}
+		sliced, err := util.BitFieldSliceFirstN(bf, 100)
+		require.NoError(t, err)
+		assertBits([]uint64{1, 2, 3, 6, 10, 11, 12}, sliced)
+
+		// The result encodes as a bitfield constructed directly from its bits.
+		sliced, err = util.BitFieldSliceFirstN(bf, 5)
+		require.NoError(t, err)
+		var actual, expected bytes.Buffer
+		require.NoError(t, sliced.MarshalCBOR(&actual))
+		require.NoError(t, bitfield.NewFromSet([]uint64{1, 2, 3, 6, 10}).MarshalCBOR(&expected))
+		assert.Equal(t, expected.Bytes(), actual.Bytes())
+	})
+
+	t.Run("intersect with limit", func(t *testing.T) {
+		other := bitfield.NewFromSet([]uint64{0, 2, 3, 4, 6, 11, 20})
+
+		intersection, truncated, err := util.BitFieldIntersectWithLimit(bf, other, 100)
+		require.NoError(t, err)
+		assert.False(t, truncated)
+		assertBits([]uint64{2, 3, 6, 11}, intersection)
+
+		intersection, truncated, err = util.BitFieldIntersectWithLimit(bf, other, 4)
+		require.NoError(t, err)
+		assert.False(t, truncated)
+		assertBits([]uint64{2, 3, 6, 11}, intersection)
+
+		intersection, truncated, err = util.BitFieldIntersectWithLimit(bf, other, 3)
+		require.NoError(t, err)
+		assert.True(t, truncated)
+		assertBits([]uint64{2, 3, 6}, intersection)
+
+		intersection, truncated, err = util.BitFieldIntersectWithLimit(bf, other, 1)
+		require.NoError(t, err)
+		assert.True(t, truncated)
+		assertBits([]uint64{2}, intersection)
+
+		intersection, truncated, err = util.BitFieldIntersectWithLimit(bf, bitfield.NewFromSet([]uint64{0, 4}), 1)
+		require.NoError(t, err)
+		assert.False(t, truncated)
+		assertBits(nil, intersection)
+	})
+
+	t.Run("count up to", func(t *testing.T) {
+		for max, expected := range []uint64{0, 1, 2, 3, 4, 5, 6, 7, 7, 7} {
+			count, err := util.BitFieldCountUpTo(bf, uint64(max))
+			require.NoError(t, err)
+			assert.Equal(t, expected, count, "max %d", max)
+		}
+
+		// A run far larger than the limit is not expanded.
+		huge, err := bitfield.NewFromIter(&rlepluslazy.RunSliceIterator{Runs: []rlepluslazy.Run{{Val: true, Len: math.MaxUint64 >> 1}}})
+		require.NoError(t, err)
+		count, err := util.BitFieldCountUpTo(huge, 10)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(10), count)
+	})
+
+	t.Run("count at most", func(t *testing.T) {
+		count, err := util.BitFieldCountAtMost(bf, 7)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(7), count)
+		count, err = util.BitFieldCountAtMost(bf, math.MaxUint64)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(7), count)
+
+		_, err = util.BitFieldCountAtMost(bf, 6)
+		assert.Error(t, err)
+		huge, err := bitfield.NewFromIter(&rlepluslazy.RunSliceIterator{Runs: []rlepluslazy.Run{{Val: true, Len: math.MaxUint64 >> 1}}})
+		require.NoError(t, err)
+		_, err = util.BitFieldCountAtMost(huge, 10)
+		assert.Error(t, err)
+	})
+
+	t.Run("slice bounded", func(t *testing.T) {
+		sliced, err := util.BitFieldSliceBounded(bf, 2, 3)
+		require.NoError(t, err)
+		assertBits([]uint64{3, 6, 10}, sliced)
+
+		// Fewer bits than requested are not an error.
+		sliced, err = util.BitFieldSliceBounded(bf, 5, 100)
+		require.NoError(t, err)
+		assertBits([]uint64{11, 12}, sliced)
+		sliced, err = util.BitFieldSliceBounded(bf, 7, 1)
+		require.NoError(t, err)
+		assertBits(nil, sliced)
+		sliced, err = util.BitFieldSliceBounded(bf, 1, math.MaxUint64)
+		require.NoError(t, err)
+		assertBits([]uint64{2, 3, 6, 10, 11, 12}, sliced)
+	})
+}
+
+func TestValidateBitField(t *testing.T) {
+	// Bits 1-3, 6, and 10-12 make 6 runs, including the leading unset run.
+	bf := bitfield.NewFromSet([]uint64{1, 2, 3, 6, 10, 11, 12})
+	var encoded bytes.Buffer
+	require.NoError(t, bf.MarshalCBOR(&encoded))
+	size := uint64(encoded.Len() - 1) // Less the one-byte CBOR header
+
+	assert.NoError(t, util.ValidateBitField(bf, util.BitFieldLimits{}))
+	assert.NoError(t, util.ValidateBitField(bf, util.BitFieldLimits{MaxEncodedSize: size, MaxRuns: 6, MaxSetBits: 7}))
+	assert.NoError(t, util.ValidateBitField(bitfield.New(), util.BitFieldLimits{MaxEncodedSize: 1, MaxRuns: 1, MaxSetBits: 1}))
+
+	err := util.ValidateBitField(bf, util.BitFieldLimits{MaxEncodedSize: size - 1})
+	assert.Error(t, err)
+	err = util.ValidateBitField(bf, util.BitFieldLimits{MaxRuns: 5})
+	assert.EqualError(t, err, "bitfield has more than 5 runs")
+	err = util.ValidateBitField(bf, util.BitFieldLimits{MaxSetBits: 6})
+	assert.EqualError(t, err, "bitfield has more than 6 bits set")
+
+	// A modified bitfield is checked as it would be encoded.
+	modified := bitfield.NewFromSet([]uint64{1, 2, 3, 6, 10, 11, 12})
+	modified.Set(20)
+	err = util.ValidateBitField(modified, util.BitFieldLimits{MaxRuns: 7})
+	assert.EqualError(t, err, "bitfield has more than 7 runs")
+
+	// A run far larger than the limit is rejected without being expanded.
+	huge, err := bitfield.NewFromIter(&rlepluslazy.RunSliceIterator{Runs: []rlepluslazy.Run{{Val: true, Len: math.MaxUint64 >> 1}}})
+	require.NoError(t, err)
+	err = util.ValidateBitField(huge, util.BitFieldLimits{MaxRuns: 10, MaxSetBits: 10})
+	assert.EqualError(t, err, "bitfield has more than 10 bits set")
+}
@@ -0,0 +1,107 @@
+package cbor
+
+import (
+	"bytes"
+	"io"
+
+	cbg "github.com/whyrusleeping/cbor-gen"
+	"golang.org/x/xerrors"
+)
+
+// Size of the chunks in which an ArrayEncoder holds encoded elements.
+const arrayChunkSize = 1 << 12
+
+// Encodes the elements of a CBOR array one at a time, up to limits on the number of elements and on the
+// encoded size of the whole array, so that a method returning a large collection need neither load the whole
+// collection nor encode it into one buffer before finding where to truncate it.
+// Encoded elements are held in fixed size chunks, which are written out after the array's header.
+// An element which would exceed a limit is not appended, and truncates the array: no more may be appended after it,
+// so the elements encoded are always a prefix of those offered. The caller resumes from the first element not
+// appended, e.g. by returning a pagination token identifying it.
+type ArrayEncoder struct {
+	maxLength uint64
+	maxSize   int64
+
+	length    uint64
+	size      int64 // Encoded size of the elements, excluding the array header
+	chunks    [][]byte
+	truncated bool
+	scratch   bytes.Buffer
+}
+
+// Creates an encoder for an array of at most maxLength elements whose encoding, including the array header,
+// is at most maxSize bytes.
+func NewArrayEncoder(maxLength uint64, maxSize int64) *ArrayEncoder {
+	return &ArrayEncoder{maxLength: maxLength, maxSize: maxSize}
+}
+
+// Appends an element to the array, returning false if it would exceed the encoder's limits, in which case the
+// array is truncated before it. Returns false without encoding the element if the array is already truncated.
+func (e *ArrayEncoder) Append(v cbg.CBORMarshaler) (bool, error) {
+	if e.truncated {
+		return false, nil
+	}
+	if e.length == e.maxLength {
+		e.truncated = true
+		return false, nil
+	}
+
+	e.scratch.Reset()
+	if err := v.MarshalCBOR(&e.scratch); err != nil {
+		return false, xerrors.Errorf("failed to encode array element %d: %w", e.length, err)
+	}
+	elem := e.scratch.Bytes()
+	size := e.size + int64(len(elem))
+	if headerSize(e.length+1)+size > e.maxSize {
+		e.truncated = true
+		return false, nil
+	}
+
+	for len(elem) > 0 {
+		if len(e.chunks) == 0 || len(e.chunks[len(e.chunks)-1]) == arrayChunkSize {
+			e.chunks = append(e.chunks, make([]byte, 0, arrayChunkSize))
+		}
+		last := &e.chunks[len(e.chunks)-1]
+		n := copy((*last)[len(*last):arrayChunkSize], elem)
+		*last = (*last)[:len(*last)+n]
+		elem = elem[n:]
+	}
+	e.length++
+	e.size = size
+	return true, nil
+}
+
+// The number of elements appended.
+func (e *ArrayEncoder) Length() uint64 {
+	return e.length
+}
+
+// The size of the array's encoding, including its header.
+func (e *ArrayEncoder) Size() int64 {
+	return headerSize(e.length) + e.size
+}
+
+// Whether an element has been refused for exceeding the encoder's limits.
+func (e *ArrayEncoder) Truncated() bool {
+	return e.truncated
+}
+
+// Writes the array of the elements appended.
+func (e *ArrayEncoder) MarshalCBOR(w io.Writer) error {
+	if err := cbg.WriteMajorTypeHeader(w, cbg.MajArray, e.length); err != nil {
+		return err
+	}
+	for _, chunk := range e.chunks {
+		if _, err := w.Write(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// The size of the header of an array of some length.
+func headerSize(length uint64) int64 {
+	return int64(len(cbg.CborEncodeMajorType(cbg.MajArray, length)))
+}
+
+var _ cbg.CBORMarshaler = (*ArrayEncoder)(nil)
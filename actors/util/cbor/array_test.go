@@ -0,0 +1,86 @@
+package cbor_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	cbg "github.com/whyrusleeping/cbor-gen"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/util/cbor"
+)
+
+func TestArrayEncoder(t *testing.T) {
+	// Byte strings of 3003 bytes each, so that elements span the encoder's chunks.
+	elements := make([]*cbg.Deferred, 5)
+	for i := range elements {
+		raw := append(cbg.CborEncodeMajorType(cbg.MajByteString, 3000), bytes.Repeat([]byte{byte(i)}, 3000)...)
+		elements[i] = &cbg.Deferred{Raw: raw}
+	}
+	expected := func(n int) []byte {
+		buf := bytes.NewBuffer(cbg.CborEncodeMajorType(cbg.MajArray, uint64(n)))
+		for _, e := range elements[:n] {
+			buf.Write(e.Raw)
+		}
+		return buf.Bytes()
+	}
+	encode := func(enc *cbor.ArrayEncoder) []byte {
+		var buf bytes.Buffer
+		require.NoError(t, enc.MarshalCBOR(&buf))
+		assert.Equal(t, int64(buf.Len()), enc.Size())
+		require.NoError(t, cbor.ValidateCanonical(buf.Bytes()))
+		return buf.Bytes()
+	}
+
+	t.Run("encodes all elements within limits", func(t *testing.T) {
+		enc := cbor.NewArrayEncoder(10, 1<<20)
+		for _, e := range elements {
+			ok, err := enc.Append(e)
+			require.NoError(t, err)
+			assert.True(t, ok)
+		}
+		assert.False(t, enc.Truncated())
+		assert.Equal(t, uint64(len(elements)), enc.Length())
+		assert.Equal(t, expected(len(elements)), encode(enc))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		enc := cbor.NewArrayEncoder(10, 1<<20)
+		assert.Equal(t, expected(0), encode(enc))
+	})
+
+	t.Run("truncates at maximum length", func(t *testing.T) {
+		enc := cbor.NewArrayEncoder(2, 1<<20)
+		for i, e := range elements {
+			ok, err := enc.Append(e)
+			require.NoError(t, err)
+			assert.Equal(t, i < 2, ok)
+		}
+		assert.True(t, enc.Truncated())
+		assert.Equal(t, expected(2), encode(enc))
+	})
+
+	t.Run("truncates at maximum size", func(t *testing.T) {
+		size := int64(len(expected(3)))
+		enc := cbor.NewArrayEncoder(10, size)
+		for i, e := range elements {
+			ok, err := enc.Append(e)
+			require.NoError(t, err)
+			assert.Equal(t, i < 3, ok)
+		}
+		assert.Equal(t, expected(3), encode(enc))
+
+		// An element which would fit after the array is truncated is not appended.
+		enc = cbor.NewArrayEncoder(10, size-1)
+		for range elements[:3] {
+			_, err := enc.Append(elements[0])
+			require.NoError(t, err)
+		}
+		assert.True(t, enc.Truncated())
+		ok, err := enc.Append(&cbg.Deferred{Raw: []byte{0}})
+		require.NoError(t, err)
+		assert.False(t, ok)
+		assert.Equal(t, uint64(2), enc.Length())
+	})
+}
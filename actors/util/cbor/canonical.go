@@ -0,0 +1,130 @@
+package cbor
+
+import (
+	"bytes"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	majByteString = 2
+	majTextString = 3
+	majArray      = 4
+	majMap        = 5
+	majTag        = 6
+	majOther      = 7
+)
+
+// Checks that bytes are a single CBOR item in canonical form, so that implementations decoding them agree on
+// whether they are valid, and re-encoding them reproduces them exactly.
+// In canonical form integers, lengths, tags and simple values are encoded in the fewest bytes possible, strings,
+// arrays and maps have definite lengths, and map keys are ordered by their encoding (shorter first, then bytewise)
+// without duplicates. No bytes may follow the item.
+func ValidateCanonical(b []byte) error {
+	n, err := validateItem(b)
+	if err != nil {
+		return err
+	}
+	if n != len(b) {
+		return xerrors.Errorf("%d bytes follow CBOR item", len(b)-n)
+	}
+	return nil
+}
+
+// Validates the item at the start of b, returning its length.
+func validateItem(b []byte) (int, error) {
+	maj, val, n, err := readHeader(b)
+	if err != nil {
+		return 0, err
+	}
+	switch maj {
+	case majByteString, majTextString:
+		if val > uint64(len(b)-n) {
+			return 0, xerrors.Errorf("string of length %d exceeds remaining %d bytes", val, len(b)-n)
+		}
+		return n + int(val), nil
+	case majArray:
+		for i := uint64(0); i < val; i++ {
+			m, err := validateItem(b[n:])
+			if err != nil {
+				return 0, xerrors.Errorf("array element %d: %w", i, err)
+			}
+			n += m
+		}
+		return n, nil
+	case majMap:
+		var prevKey []byte
+		for i := uint64(0); i < val; i++ {
+			m, err := validateItem(b[n:])
+			if err != nil {
+				return 0, xerrors.Errorf("map key %d: %w", i, err)
+			}
+			key := b[n : n+m]
+			if prevKey != nil && !canonicalLess(prevKey, key) {
+				return 0, xerrors.Errorf("map key %d is out of order or duplicated", i)
+			}
+			prevKey = key
+			n += m
+			if m, err = validateItem(b[n:]); err != nil {
+				return 0, xerrors.Errorf("map value %d: %w", i, err)
+			}
+			n += m
+		}
+		return n, nil
+	case majTag:
+		m, err := validateItem(b[n:])
+		if err != nil {
+			return 0, xerrors.Errorf("tag %d content: %w", val, err)
+		}
+		return n + m, nil
+	default:
+		return n, nil
+	}
+}
+
+// Reads an item's header, returning its major type, value (or length) and the header's length.
+func readHeader(b []byte) (maj byte, val uint64, n int, err error) {
+	if len(b) == 0 {
+		return 0, 0, 0, xerrors.Errorf("unexpected end of CBOR")
+	}
+	maj = b[0] >> 5
+	info := b[0] & 0x1f
+	switch {
+	case info < 24:
+		return maj, uint64(info), 1, nil
+	case info <= 27:
+		n = 1 << (info - 24) // 1, 2, 4 or 8 bytes follow
+		if len(b) < 1+n {
+			return 0, 0, 0, xerrors.Errorf("unexpected end of CBOR header")
+		}
+		for _, c := range b[1 : 1+n] {
+			val = val<<8 | uint64(c)
+		}
+		if maj == majOther {
+			// Floats are encoded at a fixed width; only a one-byte simple value could be shorter.
+			if info == 24 && val < 32 {
+				return 0, 0, 0, xerrors.Errorf("simple value %d not encoded in one byte", val)
+			}
+			return maj, val, 1 + n, nil
+		}
+		if val < minimalThreshold[info-24] {
+			return 0, 0, 0, xerrors.Errorf("value %d not encoded in the fewest bytes", val)
+		}
+		return maj, val, 1 + n, nil
+	case info == 31:
+		return 0, 0, 0, xerrors.Errorf("indefinite length item of major type %d", maj)
+	default:
+		return 0, 0, 0, xerrors.Errorf("reserved additional information %d", info)
+	}
+}
+
+// The least value requiring each header width of 1, 2, 4 and 8 bytes.
+var minimalThreshold = [4]uint64{24, 1 << 8, 1 << 16, 1 << 32}
+
+// Whether encoded key a precedes b in canonical order.
+func canonicalLess(a, b []byte) bool {
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+	return bytes.Compare(a, b) < 0
+}
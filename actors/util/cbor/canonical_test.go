@@ -0,0 +1,80 @@
+package cbor_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/miner"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/paramreg"
+	"github.com/filecoin-project/specs-actors/v2/actors/util/cbor"
+	tutil "github.com/filecoin-project/specs-actors/v2/support/testing"
+)
+
+func TestValidateCanonical(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		hex   string
+		valid bool
+	}{
+		{"small int", "17", true},
+		{"one byte int", "1818", true},
+		{"int padded to one byte", "1817", false},
+		{"int padded to two bytes", "1900ff", false},
+		{"int padded to four bytes", "1a0000ffff", false},
+		{"int padded to eight bytes", "1b00000000ffffffff", false},
+		{"eight byte int", "1b0000000100000000", true},
+		{"negative int padded", "3800", false},
+		{"byte string", "43010203", true},
+		{"byte string length padded", "5803010203", false},
+		{"truncated byte string", "430102", false},
+		{"indefinite byte string", "5f4101ff", false},
+		{"array", "820102", true},
+		{"indefinite array", "9f0102ff", false},
+		{"truncated array", "8301", false},
+		{"invalid nested element", "82011800", false},
+		{"map sorted", "a2616101616202", true},
+		{"map shorter key first", "a261620162616102", true},
+		{"map unsorted", "a2616201616102", false},
+		{"map longer key first", "a262616101616202", false},
+		{"map duplicate key", "a2616101616102", false},
+		{"tagged", "d82a420001", true},
+		{"tag padded", "d8012a", false},
+		{"null", "f6", true},
+		{"simple value padded", "f814", false},
+		{"float", "fb3ff0000000000000", true},
+		{"reserved", "1c", false},
+		{"trailing bytes", "0101", false},
+		{"empty", "", false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := hex.DecodeString(tc.hex)
+			require.NoError(t, err)
+			err = cbor.ValidateCanonical(b)
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+
+	t.Run("generated encodings are canonical", func(t *testing.T) {
+		recipient := tutil.NewIDAddr(t, 100)
+		st := paramreg.State{
+			Parameters: map[string]big.Int{"bb": big.NewInt(1), "c": big.NewInt(-2), "a": big.Zero()},
+			History:    tutil.MakeCID("history", nil),
+		}
+		params := miner.WithdrawBalanceParams{AmountRequested: big.NewInt(1 << 40), Recipient: &recipient}
+		var buf bytes.Buffer
+		require.NoError(t, st.MarshalCBOR(&buf))
+		assert.NoError(t, cbor.ValidateCanonical(buf.Bytes()))
+		buf.Reset()
+		require.NoError(t, params.MarshalCBOR(&buf))
+		assert.NoError(t, cbor.ValidateCanonical(buf.Bytes()))
+	})
+}
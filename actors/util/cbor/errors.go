@@ -0,0 +1,39 @@
+package cbor
+
+import (
+	"fmt"
+
+	"golang.org/x/xerrors"
+)
+
+// An error decoding a value, with the path from the value's type to the field whose decoding failed, e.g.
+// "miner.Deadline.ExpirationsEpochs: ..." or "market.PublishStorageDealsParams.Deals[3].Proposal.Label: ...",
+// so that a failure to decode a large state can be located without bisecting its encoding by hand.
+type DecodeError struct {
+	Type string // The type of the value decoded, e.g. "miner.Deadline"
+	Path string // The path to the field within the value, e.g. ".Deals[3].Proposal", empty for the value itself
+	Err  error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("%s%s: %s", e.Type, e.Path, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// Wraps an error decoding a field of a type, for generated decoders. The field may include an element index,
+// e.g. "Deals[3]", or be empty for an error decoding the type's own header. If the error is from decoding a value
+// within the field, the field's path is prefixed to the value's.
+func WrapDecodeError(err error, typ, field string) error {
+	path := ""
+	if field != "" {
+		path = "." + field
+	}
+	var inner *DecodeError
+	if xerrors.As(err, &inner) {
+		return &DecodeError{Type: typ, Path: path + inner.Path, Err: inner.Err}
+	}
+	return &DecodeError{Type: typ, Path: path, Err: err}
+}
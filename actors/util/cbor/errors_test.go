@@ -0,0 +1,51 @@
+package cbor_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	cbg "github.com/whyrusleeping/cbor-gen"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/verifreg"
+	"github.com/filecoin-project/specs-actors/v2/actors/util/cbor"
+	tutil "github.com/filecoin-project/specs-actors/v2/support/testing"
+)
+
+func TestDecodeErrorPath(t *testing.T) {
+	addr := tutil.NewIDAddr(t, 100)
+	valid := verifreg.DataCapEventRecord{
+		Epoch: 5,
+		Event: verifreg.DataCapEvent{Kind: verifreg.DataCapEventKind(0), Source: addr, Target: addr, Amount: big.NewInt(1)},
+	}
+	var validBuf bytes.Buffer
+	require.NoError(t, valid.MarshalCBOR(&validBuf))
+
+	// The second record's event has a string for its kind.
+	var encoded []byte
+	encoded = append(encoded, cbg.CborEncodeMajorType(cbg.MajArray, 1)...)
+	encoded = append(encoded, cbg.CborEncodeMajorType(cbg.MajArray, 2)...)
+	encoded = append(encoded, validBuf.Bytes()...)
+	encoded = append(encoded, cbg.CborEncodeMajorType(cbg.MajArray, 2)...)
+	encoded = append(encoded, cbg.CborEncodeMajorType(cbg.MajUnsignedInt, 6)...)
+	encoded = append(encoded, cbg.CborEncodeMajorType(cbg.MajArray, 4)...)
+	encoded = append(encoded, cbg.CborEncodeMajorType(cbg.MajTextString, 1)...)
+	encoded = append(encoded, 'x')
+
+	var ret verifreg.GetDataCapEventsReturn
+	err := ret.UnmarshalCBOR(bytes.NewReader(encoded))
+	require.Error(t, err)
+	assert.Equal(t, "verifreg.GetDataCapEventsReturn.Events[1].Event.Kind: wrong type for uint64 field", err.Error())
+
+	var decodeErr *cbor.DecodeError
+	require.True(t, xerrors.As(err, &decodeErr))
+	assert.Equal(t, "verifreg.GetDataCapEventsReturn", decodeErr.Type)
+	assert.Equal(t, ".Events[1].Event.Kind", decodeErr.Path)
+
+	// An error decoding the value's own header has no path.
+	err = ret.UnmarshalCBOR(bytes.NewReader(cbg.CborEncodeMajorType(cbg.MajUnsignedInt, 1)))
+	assert.Equal(t, "verifreg.GetDataCapEventsReturn: cbor input should be of type array", err.Error())
+}
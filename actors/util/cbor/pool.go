@@ -0,0 +1,44 @@
+package cbor
+
+import (
+	"bytes"
+	"sync"
+
+	cbg "github.com/whyrusleeping/cbor-gen"
+)
+
+// Buffers for encoding values, shared so that each encoding need not grow a new buffer from empty.
+// Flushing a large state, such as a miner's deadlines or the market's deal tables, encodes many blocks of up to tens
+// of kilobytes, each of which otherwise reallocates and copies its buffer several times as it grows.
+var bufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// Buffers which have grown beyond this are dropped rather than pooled, so that encoding an occasional very large
+// value does not pin its buffer in memory.
+const maxPooledBufferSize = 1 << 20
+
+// Returns an empty buffer from the shared pool. The caller should return it with PutBuffer once no longer using it
+// or any slice of its bytes.
+func GetBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+// Returns a buffer obtained from GetBuffer to the pool.
+func PutBuffer(buf *bytes.Buffer) {
+	if buf.Cap() <= maxPooledBufferSize {
+		bufferPool.Put(buf)
+	}
+}
+
+// Returns the encoding of a value, encoded into a pooled buffer and then copied to a slice of its exact length.
+func Marshal(v cbg.CBORMarshaler) ([]byte, error) {
+	buf := GetBuffer()
+	defer PutBuffer(buf)
+	if err := v.MarshalCBOR(buf); err != nil {
+		return nil, err
+	}
+	return append(make([]byte, 0, buf.Len()), buf.Bytes()...), nil
+}
@@ -0,0 +1,38 @@
+package cbor_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/miner"
+	"github.com/filecoin-project/specs-actors/v2/actors/util/cbor"
+)
+
+func TestMarshal(t *testing.T) {
+	encode := func(n int) (*miner.VestingFunds, []byte) {
+		funds := &miner.VestingFunds{}
+		for i := 0; i < n; i++ {
+			funds.Funds = append(funds.Funds, miner.VestingFund{Epoch: abi.ChainEpoch(i), Amount: abi.NewTokenAmount(int64(i))})
+		}
+		var buf bytes.Buffer
+		require.NoError(t, funds.MarshalCBOR(&buf))
+		return funds, buf.Bytes()
+	}
+	large, largeEncoding := encode(100)
+	small, smallEncoding := encode(1)
+
+	first, err := cbor.Marshal(large)
+	require.NoError(t, err)
+	assert.Equal(t, largeEncoding, first)
+	assert.Equal(t, len(first), cap(first))
+
+	// A later encoding, which may reuse the first's buffer, leaves the first's bytes unchanged.
+	second, err := cbor.Marshal(small)
+	require.NoError(t, err)
+	assert.Equal(t, smallEncoding, second)
+	assert.Equal(t, largeEncoding, first)
+}
@@ -0,0 +1,48 @@
+package math
+
+import (
+	"github.com/filecoin-project/go-state-types/big"
+)
+
+// Helpers for arithmetic on quantities which must not go negative, such as balances, pledge and penalties,
+// so that code states whether a subtraction may underflow rather than checking the result after the fact.
+
+// CheckedSub returns a - b and true if a >= b, else zero and false.
+func CheckedSub(a, b big.Int) (big.Int, bool) {
+	if a.LessThan(b) {
+		return big.Zero(), false
+	}
+	return big.Sub(a, b), true
+}
+
+// SubWithFloorZero returns a - b, or zero if b exceeds a.
+func SubWithFloorZero(a, b big.Int) big.Int {
+	diff, _ := CheckedSub(a, b)
+	return diff
+}
+
+// Clamp returns x bounded to the range [lo, hi], which must not be empty.
+func Clamp(x, lo, hi big.Int) big.Int {
+	if lo.GreaterThan(hi) {
+		panic("clamp to empty range")
+	}
+	return big.Min(big.Max(x, lo), hi)
+}
+
+// Min returns the least of one or more values.
+func Min(first big.Int, rest ...big.Int) big.Int {
+	min := first
+	for _, x := range rest {
+		min = big.Min(min, x)
+	}
+	return min
+}
+
+// Max returns the greatest of one or more values.
+func Max(first big.Int, rest ...big.Int) big.Int {
+	max := first
+	for _, x := range rest {
+		max = big.Max(max, x)
+	}
+	return max
+}
@@ -0,0 +1,45 @@
+package math_test
+
+import (
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/util/math"
+)
+
+func TestCheckedSub(t *testing.T) {
+	diff, ok := math.CheckedSub(big.NewInt(5), big.NewInt(3))
+	assert.True(t, ok)
+	assert.Equal(t, big.NewInt(2), diff)
+
+	diff, ok = math.CheckedSub(big.NewInt(3), big.NewInt(3))
+	assert.True(t, ok)
+	assert.True(t, diff.IsZero())
+
+	diff, ok = math.CheckedSub(big.NewInt(3), big.NewInt(5))
+	assert.False(t, ok)
+	assert.Equal(t, big.Zero(), diff)
+}
+
+func TestSubWithFloorZero(t *testing.T) {
+	assert.Equal(t, big.NewInt(2), math.SubWithFloorZero(big.NewInt(5), big.NewInt(3)))
+	assert.Equal(t, big.Zero(), math.SubWithFloorZero(big.NewInt(3), big.NewInt(5)))
+}
+
+func TestClamp(t *testing.T) {
+	lo, hi := big.NewInt(-2), big.NewInt(7)
+	assert.Equal(t, lo, math.Clamp(big.NewInt(-5), lo, hi))
+	assert.Equal(t, big.NewInt(3), math.Clamp(big.NewInt(3), lo, hi))
+	assert.Equal(t, hi, math.Clamp(big.NewInt(9), lo, hi))
+	assert.Equal(t, lo, math.Clamp(big.NewInt(9), lo, lo))
+	assert.Panics(t, func() { math.Clamp(big.Zero(), hi, lo) })
+}
+
+func TestMinMax(t *testing.T) {
+	assert.Equal(t, big.NewInt(4), math.Min(big.NewInt(4)))
+	assert.Equal(t, big.NewInt(-1), math.Min(big.NewInt(4), big.NewInt(-1), big.NewInt(2)))
+	assert.Equal(t, big.NewInt(4), math.Max(big.NewInt(4)))
+	assert.Equal(t, big.NewInt(6), math.Max(big.NewInt(4), big.NewInt(-1), big.NewInt(6)))
+}
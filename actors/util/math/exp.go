@@ -1,6 +1,10 @@
 package math
 
-import "github.com/filecoin-project/go-state-types/big"
+import (
+	gbig "math/big"
+
+	"github.com/filecoin-project/go-state-types/big"
+)
 
 // ExpBySquaring takes a Q.128 base b and an int64 exponent n and computes n^b
 // using the exponentiation by squaring method, returning a Q.128 value.
@@ -27,3 +31,27 @@ func ExpBySquaring(base big.Int, n int64) big.Int {
 	result := big.Mul(base, ExpBySquaring(baseSquared, (n-1)/2)) // Q.128 * Q.128 => Q.256
 	return big.Rsh(result, Precision128)                         // Q.256 => Q.128
 }
+
+// Exp takes a Q.128 exponent x and computes e^x, returning a Q.128 value.
+// The exponent is reduced to x = k*ln(2) + r for integer k and 0 <= r < ln(2), so that e^x = 2^(k+1) * e^-(ln(2)-r),
+// where e^-(ln(2)-r) is computed by ExpNeg within its most precise range.
+// For x < 88 (results below 2^127) the relative error is less than 1e-29, so results below one have an absolute
+// error less than 1e-29. Results below 2^-128 are zero.
+func Exp(x big.Int) big.Int {
+	k, r := new(gbig.Int).DivMod(x.Int, ln2.Int, new(gbig.Int)) // Q.128 / Q.128 => Q.0, remainder Q.128
+	y := ExpNeg(r.Sub(ln2.Int, r))                              // Q.128, in (0.5, 1]
+	shift := k.Int64() + 1
+	if shift >= 0 {
+		return big.NewFromGo(y.Lsh(y, uint(shift))) // Q.128
+	}
+	return big.NewFromGo(y.Rsh(y, uint(-shift))) // Q.128
+}
+
+// Pow takes a positive Q.128 base x and a Q.128 exponent y and computes x^y = e^(y*ln(x)), returning a Q.128
+// value.
+// Ln has an absolute error up to about 1e-16, which y scales in the exponent y*ln(x), so the relative error of the
+// result is up to about |y| * 1e-16 (for |y*ln(x)| < 88).
+func Pow(x, y big.Int) big.Int {
+	exponent := big.Mul(y, Ln(x))               // Q.128 * Q.128 => Q.256
+	return Exp(big.Rsh(exponent, Precision128)) // Q.256 => Q.128
+}
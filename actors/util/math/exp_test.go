@@ -6,6 +6,7 @@ import (
 	"github.com/filecoin-project/go-state-types/big"
 	"github.com/filecoin-project/specs-actors/v2/actors/util/math"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestExpBySquaring(t *testing.T) {
@@ -36,3 +37,71 @@ func TestExpBySquaring(t *testing.T) {
 		math.ExpBySquaring(seven, 6),
 	)
 }
+
+func TestExp(t *testing.T) {
+	expInputs := math.Parse([]string{
+		"0", // Q.128 format of 0
+		"340282366920938463463374607431768211456",   // Q.128 format of 1
+		"-340282366920938463463374607431768211456",  // Q.128 format of -1
+		"235865763225513294137944142764154484399",   // Q.128 format of ln(2)
+		"3402823669209384634633746074317682114560",  // Q.128 format of 10
+		"-7996635622642053891389303274646552969216", // Q.128 format of -23.5
+		"27222589353675077077069968594541456916480", // Q.128 format of 80
+		"204169420152563078078024764459060926",      // Q.128 format of 0.0006
+	})
+
+	expectedExpOutputs := math.Parse([]string{
+		"340282366920938463463374607431768211456",                                    // Q.128 format of 1 = e^0
+		"924983374546220337150911035843336795079",                                    // Q.128 format of 2.718... = e^1
+		"125182886983370532117250726298150828301",                                    // Q.128 format of 0.3678... = e^-1
+		"680564733841876926926749214863536422911",                                    // Q.128 format of 2 = e^ln(2)
+		"7495217915559919573679589385952004519405957",                                // Q.128 format of 22026.46... = e^10
+		"21179666643412924634411189228",                                              // Q.128 format of 6.224e-11 = e^-23.5
+		"18853760991765573410478413238158081388047243217739705754402135078981362987", // Q.128 format of 5.54e34 = e^80
+		"340486597604169075264836239308352650102",                                    // Q.128 format of 1.0006... = e^0.0006
+	})
+	require.Equal(t, len(expInputs), len(expectedExpOutputs))
+	for i := 0; i < len(expInputs); i++ {
+		x := big.NewFromGo(expInputs[i])
+		expected := big.NewFromGo(expectedExpOutputs[i])
+		assertWithinError(t, expected, math.Exp(x), 29, "failed exp of %v", x)
+	}
+}
+
+func TestPow(t *testing.T) {
+	powInputs := math.Parse([]string{
+		"680564733841876926926749214863536422912", "170141183460469231731687303715884105728", // 2^0.5
+		"3402823669209384634633746074317682114560", "1020847100762815390390123822295304634368", // 10^3
+		"170141183460469231731687303715884105728", "3402823669209384634633746074317682114560", // 0.5^10
+		"340282366920938463463374607431768211456000000", "-85070591730234615865843651857942052864", // 1e6^-0.25
+		"2381976568446569244243622252022377480192", "-680564733841876926926749214863536422912", // 7^-2
+	})
+
+	expectedPowOutputs := math.Parse([]string{
+		"481231938336009023090067544955250113854",    // Q.128 format of 1.414...
+		"340282366920938463463374607431768211455999", // Q.128 format of 1000
+		"332306998946228968225951765070086143",       // Q.128 format of 0.0009765625
+		"10760673270633032068781057572644926252",     // Q.128 format of 0.0316...
+		"6944538100427315580885196070036085948",      // Q.128 format of 0.0204...
+	})
+	require.Equal(t, len(powInputs), 2*len(expectedPowOutputs))
+	for i := 0; i < len(expectedPowOutputs); i++ {
+		x := big.NewFromGo(powInputs[2*i])
+		y := big.NewFromGo(powInputs[2*i+1])
+		expected := big.NewFromGo(expectedPowOutputs[i])
+		assertWithinError(t, expected, math.Pow(x, y), 14, "failed pow of %v, %v", x, y)
+	}
+}
+
+// Asserts that a Q.128 value is within a relative error of 10^-digits of an expected value, or within that
+// absolute error of an expected value less than one.
+func assertWithinError(t *testing.T, expected, actual big.Int, digits int64, msgAndArgs ...interface{}) {
+	one := big.Lsh(big.NewInt(1), math.Precision128)
+	scale := expected
+	if scale.LessThan(one) {
+		scale = one
+	}
+	diff := big.Sub(expected, actual).Abs()
+	bound := big.Div(scale, big.Exp(big.NewInt(10), big.NewInt(digits)))
+	assert.True(t, diff.LessThanEqual(bound), msgAndArgs...)
+}
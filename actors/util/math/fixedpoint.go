@@ -0,0 +1,200 @@
+package math
+
+import (
+	"fmt"
+	gbig "math/big"
+
+	"github.com/filecoin-project/go-state-types/big"
+)
+
+// Q128 is a signed fixed-point number with 128 fractional bits, i.e. the rational value raw * 2^-128, where raw is
+// the integer representation in "Q.128 format" used throughout the actors' arithmetic.
+// Operations on Q128 values track the format so that callers need not shift by Precision128 by hand.
+// The zero value is not usable; construct values with Q128FromRaw, Q128FromInt or Q128FromInt64.
+type Q128 struct {
+	raw big.Int
+}
+
+// Q256 is a signed fixed-point number with 256 fractional bits.
+// It holds the exact product of two Q128 values, as an intermediate of calculations which would lose precision if
+// truncated to Q.128 after every multiplication.
+type Q256 struct {
+	raw big.Int
+}
+
+// Interprets an integer in Q.128 format as a Q128.
+func Q128FromRaw(raw big.Int) Q128 {
+	checkFormat(raw, Precision128, maxIntegerBits, "Q.128")
+	return Q128{raw: raw}
+}
+
+// Converts an integer to a Q128.
+func Q128FromInt(i big.Int) Q128 {
+	return Q128FromRaw(big.Lsh(i, Precision128)) // Q.0 => Q.128
+}
+
+// Converts an integer to a Q128.
+func Q128FromInt64(i int64) Q128 {
+	return Q128FromInt(big.NewInt(i))
+}
+
+// Returns the Q.128 format integer representation.
+func (q Q128) Raw() big.Int {
+	return q.raw
+}
+
+// Returns the integer part, rounding towards negative infinity.
+func (q Q128) ToInt() big.Int {
+	return big.Rsh(q.raw, Precision128) // Q.128 => Q.0
+}
+
+func (q Q128) Add(o Q128) Q128 {
+	return Q128FromRaw(big.Add(q.raw, o.raw))
+}
+
+func (q Q128) Sub(o Q128) Q128 {
+	return Q128FromRaw(big.Sub(q.raw, o.raw))
+}
+
+func (q Q128) Neg() Q128 {
+	return Q128FromRaw(q.raw.Neg())
+}
+
+// Returns the product, rounded to the nearest Q.128 value (halves rounding up).
+func (q Q128) Mul(o Q128) Q128 {
+	half := big.Lsh(big.NewInt(1), Precision128-1)
+	product := big.Add(big.Mul(q.raw, o.raw), half)    // Q.128 * Q.128 => Q.256
+	return Q128FromRaw(big.Rsh(product, Precision128)) // Q.256 => Q.128
+}
+
+// Returns the product, rounded towards negative infinity.
+func (q Q128) MulTrunc(o Q128) Q128 {
+	return q.MulWide(o).Trunc()
+}
+
+// Returns the exact product.
+func (q Q128) MulWide(o Q128) Q256 {
+	return Q256FromRaw(big.Mul(q.raw, o.raw)) // Q.128 * Q.128 => Q.256
+}
+
+// Returns the exact product with an integer.
+func (q Q128) MulInt(i big.Int) Q128 {
+	return Q128FromRaw(big.Mul(q.raw, i)) // Q.128 * Q.0 => Q.128
+}
+
+// Returns the quotient, rounded towards negative infinity for a positive divisor (see big.Int.Div).
+func (q Q128) Div(o Q128) Q128 {
+	return q.Widen().Div(o)
+}
+
+// Returns the quotient by an integer, rounded towards negative infinity for a positive divisor.
+func (q Q128) DivInt(i big.Int) Q128 {
+	return Q128FromRaw(big.Div(q.raw, i)) // Q.128 / Q.0 => Q.128
+}
+
+// Returns the value as a Q256.
+func (q Q128) Widen() Q256 {
+	return Q256FromRaw(big.Lsh(q.raw, Precision128)) // Q.128 => Q.256
+}
+
+// Returns the square root of a non-negative value, rounded towards zero.
+func (q Q128) Sqrt() Q128 {
+	wide := big.Lsh(q.raw, Precision128)                            // Q.128 => Q.256
+	return Q128FromRaw(big.NewFromGo(new(gbig.Int).Sqrt(wide.Int))) // sqrt(Q.256) => Q.128
+}
+
+// The natural logarithm (see Ln).
+func (q Q128) Ln() Q128 {
+	return Q128FromRaw(Ln(q.raw))
+}
+
+// The natural exponential (see Exp).
+func (q Q128) Exp() Q128 {
+	return Q128FromRaw(Exp(q.raw))
+}
+
+func (q Q128) Sign() int {
+	return q.raw.Sign()
+}
+
+func (q Q128) Equals(o Q128) bool {
+	return q.raw.Equals(o.raw)
+}
+
+func (q Q128) LessThan(o Q128) bool {
+	return q.raw.LessThan(o.raw)
+}
+
+func (q Q128) GreaterThan(o Q128) bool {
+	return q.raw.GreaterThan(o.raw)
+}
+
+// Formats the value in decimal, truncated to 12 fractional digits.
+func (q Q128) String() string {
+	if q.raw.Int == nil {
+		return "<nil>"
+	}
+	scale := big.Exp(big.NewInt(10), big.NewInt(12))
+	scaled := big.Rsh(big.Mul(q.raw.Abs(), scale), Precision128)
+	intPart, fracPart := big.Div(scaled, scale), big.Mod(scaled, scale)
+	sign := ""
+	if q.raw.Sign() < 0 {
+		sign = "-"
+	}
+	return fmt.Sprintf("%s%s.%012d", sign, intPart, fracPart.Int)
+}
+
+// Interprets an integer in Q.256 format as a Q256.
+func Q256FromRaw(raw big.Int) Q256 {
+	checkFormat(raw, 2*Precision128, 2*maxIntegerBits, "Q.256")
+	return Q256{raw: raw}
+}
+
+// Returns the Q.256 format integer representation.
+func (q Q256) Raw() big.Int {
+	return q.raw
+}
+
+func (q Q256) Add(o Q256) Q256 {
+	return Q256FromRaw(big.Add(q.raw, o.raw))
+}
+
+func (q Q256) Sub(o Q256) Q256 {
+	return Q256FromRaw(big.Sub(q.raw, o.raw))
+}
+
+// Returns the product with a Q128, rounded towards negative infinity.
+func (q Q256) MulTrunc(o Q128) Q256 {
+	product := big.Mul(q.raw, o.raw)                   // Q.256 * Q.128 => Q.384
+	return Q256FromRaw(big.Rsh(product, Precision128)) // Q.384 => Q.256
+}
+
+// Returns the quotient by a Q128, rounded towards negative infinity for a positive divisor.
+func (q Q256) Div(o Q128) Q128 {
+	return Q128FromRaw(big.Div(q.raw, o.raw)) // Q.256 / Q.128 => Q.128
+}
+
+// Returns the value truncated to a Q128, rounding towards negative infinity.
+func (q Q256) Trunc() Q128 {
+	return Q128FromRaw(big.Rsh(q.raw, Precision128)) // Q.256 => Q.128
+}
+
+// The greatest number of integer bits expected of a Q128 value, and half that expected of a Q256 (which may be a
+// product of two Q128s). Values which exceed this when format checking is enabled are assumed to be in a wider
+// format than their type claims, e.g. a Q.256 integer representation interpreted as Q.128.
+// Token amounts and storage power are well within this bound.
+const maxIntegerBits = 160
+
+// Panics if format checking is enabled (with the fixedpointdebug build tag) and a fixed-point representation is
+// uninitialized or too large to be in the claimed format.
+func checkFormat(raw big.Int, fractionalBits, integerBits uint, format string) {
+	if !checkFixedPointFormat {
+		return
+	}
+	if raw.Int == nil {
+		panic(fmt.Sprintf("uninitialized %s value", format))
+	}
+	if bits := uint(raw.BitLen()); bits > fractionalBits+integerBits {
+		panic(fmt.Sprintf("%s value %s has %d bits, more than %d integer bits", format, raw, bits, integerBits))
+	}
+}
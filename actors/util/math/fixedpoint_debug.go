@@ -0,0 +1,6 @@
+//+build fixedpointdebug
+
+package math
+
+// Whether fixed-point values are checked for plausibility of their format, which has a cost in every operation.
+const checkFixedPointFormat = true
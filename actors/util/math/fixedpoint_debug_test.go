@@ -0,0 +1,24 @@
+//+build fixedpointdebug
+
+package math_test
+
+import (
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/util/math"
+)
+
+func TestQ128FormatChecks(t *testing.T) {
+	// An uninitialized value.
+	assert.Panics(t, func() { math.Q128FromRaw(big.Int{}) })
+	assert.Panics(t, func() { math.Q128{}.Add(math.Q128FromInt64(1)) })
+
+	// A Q.256 representation of a plausible token amount interpreted as Q.128.
+	amount := big.Mul(big.NewInt(1e18), big.NewInt(2e9))
+	wide := math.Q128FromInt(amount).Widen()
+	assert.NotPanics(t, func() { wide.Trunc() })
+	assert.Panics(t, func() { math.Q128FromRaw(wide.Raw()) })
+}
@@ -0,0 +1,84 @@
+package math_test
+
+import (
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/util/math"
+)
+
+func TestQ128(t *testing.T) {
+	one := math.Q128FromInt64(1)
+	two := math.Q128FromInt64(2)
+	three := math.Q128FromInt64(3)
+	half := one.DivInt(big.NewInt(2))
+	third := one.Div(three)
+
+	t.Run("conversion", func(t *testing.T) {
+		assert.Equal(t, big.Lsh(big.NewInt(3), math.Precision128), three.Raw())
+		assert.Equal(t, three, math.Q128FromRaw(three.Raw()))
+		assert.Equal(t, big.NewInt(3), three.ToInt())
+		assert.Equal(t, big.NewInt(1), three.Div(two).ToInt())
+		// Integer conversion rounds towards negative infinity.
+		assert.Equal(t, big.NewInt(-2), three.Div(two).Neg().ToInt())
+		assert.Equal(t, "1.500000000000", three.Div(two).String())
+		assert.Equal(t, "-0.333333333333", third.Neg().String())
+	})
+
+	t.Run("arithmetic", func(t *testing.T) {
+		assert.Equal(t, three, one.Add(two))
+		assert.Equal(t, one, three.Sub(two))
+		assert.Equal(t, math.Q128FromInt64(-1), two.Sub(three))
+		assert.Equal(t, math.Q128FromInt64(6), two.Mul(three))
+		assert.Equal(t, math.Q128FromInt64(6), two.MulTrunc(three))
+		assert.Equal(t, math.Q128FromInt64(6), two.MulInt(big.NewInt(3)))
+		assert.Equal(t, half, one.Div(two))
+		assert.Equal(t, one, two.Mul(half))
+		assert.True(t, third.LessThan(half))
+		assert.True(t, half.GreaterThan(third))
+		assert.Equal(t, -1, third.Neg().Sign())
+	})
+
+	t.Run("rounding", func(t *testing.T) {
+		// 1/3 is truncated, so 3 * 1/3 is just less than one.
+		assert.True(t, third.MulTrunc(three).LessThan(one))
+		assert.Equal(t, big.Sub(one.Raw(), big.NewInt(1)), third.MulTrunc(three).Raw())
+
+		// The smallest positive value squared is zero when truncated, and when rounded to nearest.
+		epsilon := math.Q128FromRaw(big.NewInt(1))
+		assert.Equal(t, 0, epsilon.MulTrunc(epsilon).Sign())
+		assert.Equal(t, 0, epsilon.Mul(epsilon).Sign())
+		// Half the smallest value rounds up to it, but truncates to zero.
+		assert.Equal(t, epsilon, epsilon.Mul(half))
+		assert.Equal(t, 0, epsilon.MulTrunc(half).Sign())
+		// Negative products truncate towards negative infinity.
+		assert.Equal(t, epsilon.Neg(), epsilon.Neg().MulTrunc(half))
+	})
+
+	t.Run("wide intermediates", func(t *testing.T) {
+		// Truncating each product of (1/3 * 1/3) * 9 loses precision that the exact intermediate retains.
+		nine := math.Q128FromInt64(9)
+		truncated := third.MulTrunc(third).MulTrunc(nine)
+		wide := third.MulWide(third).MulTrunc(nine).Trunc()
+		assert.True(t, truncated.LessThan(wide))
+		assert.Equal(t, big.Sub(one.Raw(), big.NewInt(2)), wide.Raw())
+		assert.Equal(t, third, one.Widen().Div(three))
+		assert.Equal(t, two, three.Widen().Sub(one.Widen()).Trunc())
+	})
+
+	t.Run("square root", func(t *testing.T) {
+		assert.Equal(t, three, math.Q128FromInt64(9).Sqrt())
+		assert.Equal(t, half, one.DivInt(big.NewInt(4)).Sqrt())
+		assert.Equal(t, "1.414213562373", two.Sqrt().String())
+		assert.Equal(t, 0, math.Q128FromInt64(0).Sqrt().Sign())
+	})
+
+	t.Run("transcendental", func(t *testing.T) {
+		assert.Equal(t, big.Zero(), one.Ln().ToInt())
+		assert.Equal(t, big.NewInt(1), math.Q128FromInt64(8).Ln().Div(two).ToInt())
+		assert.Equal(t, big.NewInt(2), one.Exp().ToInt())
+		assert.Equal(t, big.NewInt(7), two.Exp().ToInt())
+	})
+}
@@ -1,8 +1,11 @@
 package smoothing
 
 import (
+	"fmt"
+
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
+	"golang.org/x/xerrors"
 
 	"github.com/filecoin-project/specs-actors/v2/actors/util/math"
 )
@@ -43,7 +46,12 @@ type FilterEstimate struct {
 
 // Returns the Q.0 position estimate of the filter
 func (fe *FilterEstimate) Estimate() big.Int {
-	return big.Rsh(fe.PositionEstimate, math.Precision128) // Q.128 => Q.0
+	return math.Q128FromRaw(fe.PositionEstimate).ToInt()
+}
+
+// Formats the estimate's position and velocity in decimal, e.g. for test failure messages.
+func (fe FilterEstimate) String() string {
+	return fmt.Sprintf("{position: %v, velocity: %v}", math.Q128FromRaw(fe.PositionEstimate), math.Q128FromRaw(fe.VelocityEstimate))
 }
 
 func DefaultInitialEstimate() FilterEstimate {
@@ -56,107 +64,205 @@ func DefaultInitialEstimate() FilterEstimate {
 // Create a new filter estimate given two Q.0 format ints.
 func NewEstimate(position, velocity big.Int) FilterEstimate {
 	return FilterEstimate{
-		PositionEstimate: big.Lsh(position, math.Precision128), // Q.0 => Q.128
-		VelocityEstimate: big.Lsh(velocity, math.Precision128), // Q.0 => Q.128
+		PositionEstimate: math.Q128FromInt(position).Raw(),
+		VelocityEstimate: math.Q128FromInt(velocity).Raw(),
+	}
+}
+
+// Variance of alpha beta filter "position" and "velocity" estimates, measuring their uncertainty.
+// The variances are exponentially weighted moving averages of the squared residuals of the filter's observations:
+// of the position residual with weight alpha, and of the residual per epoch elapsed with weight beta.
+// They are heuristic, since the filter assumes no model of the noise in its observations.
+// Variances are in Q.128 format
+type FilterVariance struct {
+	PositionVariance big.Int // Q.128
+	VelocityVariance big.Int // Q.128
+}
+
+func DefaultInitialVariance() FilterVariance {
+	return FilterVariance{
+		PositionVariance: big.Zero(),
+		VelocityVariance: big.Zero(),
 	}
 }
 
 type AlphaBetaFilter struct {
 	prevEstimate FilterEstimate
-	alpha        big.Int // Q.128
-	beta         big.Int // Q.128
+	prevVariance FilterVariance
+	alpha        math.Q128
+	beta         math.Q128
 }
 
+// Loads a filter from a previous estimate and Q.128 alpha and beta parameters.
+// The variance of the previous estimate is taken to be zero.
 func LoadFilter(prevEstimate FilterEstimate, alpha, beta big.Int) *AlphaBetaFilter {
+	return LoadFilterWithVariance(prevEstimate, DefaultInitialVariance(), alpha, beta)
+}
+
+// Loads a filter from a previous estimate and its variance, and Q.128 alpha and beta parameters.
+func LoadFilterWithVariance(prevEstimate FilterEstimate, prevVariance FilterVariance, alpha, beta big.Int) *AlphaBetaFilter {
 	return &AlphaBetaFilter{
 		prevEstimate: prevEstimate,
-		alpha:        alpha,
-		beta:         beta,
+		prevVariance: prevVariance,
+		alpha:        math.Q128FromRaw(alpha),
+		beta:         math.Q128FromRaw(beta),
 	}
 }
 
 func (f *AlphaBetaFilter) NextEstimate(observation big.Int, epochDelta abi.ChainEpoch) FilterEstimate {
-	deltaT := big.Lsh(big.NewInt(int64(epochDelta)), math.Precision128) // Q.0 => Q.128
-	deltaX := big.Mul(deltaT, f.prevEstimate.VelocityEstimate)          // Q.128 * Q.128 => Q.256
-	deltaX = big.Rsh(deltaX, math.Precision128)                         // Q.256 => Q.128
-	position := big.Sum(f.prevEstimate.PositionEstimate, deltaX)
+	estimate, _ := f.next(observation, epochDelta)
+	return estimate
+}
 
-	observation = big.Lsh(observation, math.Precision128) // Q.0 => Q.128
-	residual := big.Sub(observation, position)
-	revisionX := big.Mul(f.alpha, residual)           // Q.128 * Q.128 => Q.256
-	revisionX = big.Rsh(revisionX, math.Precision128) // Q.256 => Q.128
-	position = big.Sum(position, revisionX)
+// Returns the next estimate, as NextEstimate, along with its variance.
+func (f *AlphaBetaFilter) NextEstimateWithVariance(observation big.Int, epochDelta abi.ChainEpoch) (FilterEstimate, FilterVariance) {
+	estimate, residual := f.next(observation, epochDelta)
+	one := math.Q128FromInt64(1)
+	deltaT := math.Q128FromInt64(int64(epochDelta))
 
-	revisionV := big.Mul(f.beta, residual) // Q.128 * Q.128 => Q.256
-	revisionV = big.Div(revisionV, deltaT) // Q.256 / Q.128 => Q.128
-	velocity := big.Sum(f.prevEstimate.VelocityEstimate, revisionV)
+	positionVariance := math.Q128FromRaw(f.prevVariance.PositionVariance)
+	positionVariance = positionVariance.Add(f.alpha.MulTrunc(residual.MulTrunc(residual)))
+	positionVariance = one.Sub(f.alpha).MulTrunc(positionVariance)
 
-	return FilterEstimate{
-		PositionEstimate: position,
-		VelocityEstimate: velocity,
+	velocityResidual := residual.Div(deltaT)
+	velocityVariance := math.Q128FromRaw(f.prevVariance.VelocityVariance)
+	velocityVariance = velocityVariance.Add(f.beta.MulTrunc(velocityResidual.MulTrunc(velocityResidual)))
+	velocityVariance = one.Sub(f.beta).MulTrunc(velocityVariance)
+
+	return estimate, FilterVariance{
+		PositionVariance: positionVariance.Raw(),
+		VelocityVariance: velocityVariance.Raw(),
 	}
 }
 
+// Returns the next estimate and the residual of the observation from the extrapolated previous estimate.
+func (f *AlphaBetaFilter) next(observation big.Int, epochDelta abi.ChainEpoch) (FilterEstimate, math.Q128) {
+	prevPosition := math.Q128FromRaw(f.prevEstimate.PositionEstimate)
+	prevVelocity := math.Q128FromRaw(f.prevEstimate.VelocityEstimate)
+
+	deltaT := math.Q128FromInt64(int64(epochDelta))
+	position := prevPosition.Add(deltaT.MulTrunc(prevVelocity))
+
+	residual := math.Q128FromInt(observation).Sub(position)
+	position = position.Add(f.alpha.MulTrunc(residual))
+	velocity := prevVelocity.Add(f.beta.MulWide(residual).Div(deltaT))
+
+	return FilterEstimate{
+		PositionEstimate: position.Raw(),
+		VelocityEstimate: velocity.Raw(),
+	}, residual
+}
+
 // Extrapolate the CumSumRatio given two filters.
 // Output is in Q.128 format
 func ExtrapolatedCumSumOfRatio(delta abi.ChainEpoch, relativeStart abi.ChainEpoch, estimateNum, estimateDenom FilterEstimate) big.Int {
-	deltaT := big.Lsh(big.NewInt(int64(delta)), math.Precision128)     // Q.0 => Q.128
-	t0 := big.Lsh(big.NewInt(int64(relativeStart)), math.Precision128) // Q.0 => Q.128
+	deltaT := math.Q128FromInt64(int64(delta))
+	t0 := math.Q128FromInt64(int64(relativeStart))
 	// Renaming for ease of following spec and clarity
-	position1 := estimateNum.PositionEstimate
-	position2 := estimateDenom.PositionEstimate
-	velocity1 := estimateNum.VelocityEstimate
-	velocity2 := estimateDenom.VelocityEstimate
-
-	squaredVelocity2 := big.Mul(velocity2, velocity2)               // Q.128 * Q.128 => Q.256
-	squaredVelocity2 = big.Rsh(squaredVelocity2, math.Precision128) // Q.256 => Q.128
+	position1 := math.Q128FromRaw(estimateNum.PositionEstimate)
+	position2 := math.Q128FromRaw(estimateDenom.PositionEstimate)
+	velocity1 := math.Q128FromRaw(estimateNum.VelocityEstimate)
+	velocity2 := math.Q128FromRaw(estimateDenom.VelocityEstimate)
 
-	if squaredVelocity2.GreaterThan(ExtrapolatedCumSumRatioEpsilon) {
-		x2a := big.Mul(t0, velocity2)         // Q.128 * Q.128 => Q.256
-		x2a = big.Rsh(x2a, math.Precision128) // Q.256 => Q.128
-		x2a = big.Sum(position2, x2a)
+	squaredVelocity2 := velocity2.MulTrunc(velocity2)
 
-		x2b := big.Mul(deltaT, velocity2)     // Q.128 * Q.128 => Q.256
-		x2b = big.Rsh(x2b, math.Precision128) // Q.256 => Q.128
-		x2b = big.Sum(x2a, x2b)
+	if squaredVelocity2.GreaterThan(math.Q128FromRaw(ExtrapolatedCumSumRatioEpsilon)) {
+		x2a := position2.Add(t0.MulTrunc(velocity2))
+		x2b := x2a.Add(deltaT.MulTrunc(velocity2))
+		lnX2a := x2a.Ln()
+		lnX2b := x2b.Ln()
 
-		x2a = math.Ln(x2a) // Q.128
-		x2b = math.Ln(x2b) // Q.128
+		m1 := position1.MulWide(lnX2b.Sub(lnX2a)).MulTrunc(velocity2)
 
-		m1 := big.Sub(x2b, x2a)
-		m1 = big.Mul(velocity2, big.Mul(position1, m1)) // Q.128 * Q.128 * Q.128 => Q.384
-		m1 = big.Rsh(m1, math.Precision128)             //Q.384 => Q.256
+		m2L := position2.MulWide(lnX2a.Sub(lnX2b))
+		m2R := velocity2.MulWide(deltaT)
+		m2 := m2L.Add(m2R).MulTrunc(velocity1)
 
-		m2L := big.Sub(x2a, x2b)
-		m2L = big.Mul(position2, m2L)     // Q.128 * Q.128 => Q.256
-		m2R := big.Mul(velocity2, deltaT) // Q.128 * Q.128 => Q.256
-		m2 := big.Sum(m2L, m2R)
-		m2 = big.Mul(velocity1, m2)         // Q.256 => Q.384
-		m2 = big.Rsh(m2, math.Precision128) //Q.384 => Q.256
+		return m1.Add(m2).Div(squaredVelocity2).Raw()
+	}
 
-		return big.Div(big.Sum(m1, m2), squaredVelocity2) // Q.256 / Q.128 => Q.128
+	halfDeltaT := deltaT.DivInt(big.NewInt(2))
+	x1m := position1.Add(velocity1.MulTrunc(t0.Add(halfDeltaT)))
+	return x1m.MulWide(deltaT).Div(position2).Raw()
+}
 
-	}
+// Errors returned by ExtrapolatedCumSumOfRatioChecked.
+var (
+	// The denominator estimate is not positive at the start of the interval, so there is no ratio to sum.
+	ErrDenominatorNotPositive = xerrors.New("denominator estimate not positive at start of interval")
+	// The denominator estimate reaches zero within the interval, so the ratio is summed only up to the crossing.
+	ErrDenominatorCrossesZero = xerrors.New("denominator estimate crosses zero within interval")
+)
 
-	halfDeltaT := big.Rsh(deltaT, 1)                   // Q.128 / Q.0 => Q.128
-	x1m := big.Mul(velocity1, big.Sum(t0, halfDeltaT)) // Q.128 * Q.128 => Q.256
-	x1m = big.Rsh(x1m, math.Precision128)              // Q.256 => Q.128
-	x1m = big.Add(position1, x1m)
+// Extrapolate the CumSumRatio given two filters, as ExtrapolatedCumSumOfRatio, but only over the part of the
+// interval in which the denominator estimate is positive.
+// Returns the cumsum and the number of epochs from relativeStart over which it is taken. If the denominator
+// estimate reaches zero within the interval, these are for the epochs before it does, with
+// ErrDenominatorCrossesZero. If the denominator estimate is not positive at relativeStart, the cumsum and epochs
+// are zero, with ErrDenominatorNotPositive.
+// Output is in Q.128 format
+func ExtrapolatedCumSumOfRatioChecked(delta abi.ChainEpoch, relativeStart abi.ChainEpoch, estimateNum, estimateDenom FilterEstimate) (big.Int, abi.ChainEpoch, error) {
+	t0 := math.Q128FromInt64(int64(relativeStart))
+	position2 := math.Q128FromRaw(estimateDenom.PositionEstimate)
+	velocity2 := math.Q128FromRaw(estimateDenom.VelocityEstimate)
 
-	cumsumRatio := big.Mul(x1m, deltaT)           // Q.128 * Q.128 => Q.256
-	cumsumRatio = big.Div(cumsumRatio, position2) // Q.256 / Q.128 => Q.128
-	return cumsumRatio
+	x2a := position2.Add(t0.MulTrunc(velocity2))
+	if x2a.Sign() <= 0 {
+		return big.Zero(), 0, ErrDenominatorNotPositive
+	}
+	x2b := x2a.Add(math.Q128FromInt64(int64(delta)).MulTrunc(velocity2))
+	if x2b.Sign() > 0 {
+		return ExtrapolatedCumSumOfRatio(delta, relativeStart, estimateNum, estimateDenom), delta, nil
+	}
 
+	// The denominator is decreasing, and positive for the epochs d < x2a / -velocity2.
+	validDelta := big.Div(big.Sub(x2a.Raw(), big.NewInt(1)), velocity2.Neg().Raw()) // Q.128 / Q.128 => Q.0
+	valid := abi.ChainEpoch(validDelta.Int64())
+	if valid == 0 {
+		return big.Zero(), 0, ErrDenominatorCrossesZero
+	}
+	return ExtrapolatedCumSumOfRatio(valid, relativeStart, estimateNum, estimateDenom), valid, ErrDenominatorCrossesZero
 }
 
 // Extrapolate filter "position" delta epochs in the future.
-// Note this is currently only used in testing.
-// Output is Q.256 format for use in numerator of ratio in test caller
+// Output is Q.256 format for use in numerator of ratio (see RatioEstimate.Extrapolate)
 func (fe *FilterEstimate) Extrapolate(delta abi.ChainEpoch) big.Int {
-	deltaT := big.NewInt(int64(delta))                          // Q.0
-	deltaT = big.Lsh(deltaT, math.Precision128)                 // Q.0 => Q.128
-	extrapolation := big.Mul(fe.VelocityEstimate, deltaT)       // Q.128 * Q.128 => Q.256
-	position := big.Lsh(fe.PositionEstimate, math.Precision128) // Q.128 => Q.256
-	extrapolation = big.Sum(position, extrapolation)
-	return extrapolation // Q.256
+	deltaT := math.Q128FromInt64(int64(delta))
+	position := math.Q128FromRaw(fe.PositionEstimate).Widen()
+	extrapolation := math.Q128FromRaw(fe.VelocityEstimate).MulWide(deltaT)
+	return position.Add(extrapolation).Raw()
+}
+
+// Returns the variance of the filter "position" extrapolated delta epochs in the future, taking the position and
+// velocity errors to be independent.
+// Output is in Q.128 format
+func (fv *FilterVariance) Extrapolate(delta abi.ChainEpoch) big.Int {
+	deltaT := math.Q128FromInt64(int64(delta))
+	velocityVariance := math.Q128FromRaw(fv.VelocityVariance).MulTrunc(deltaT.MulTrunc(deltaT))
+	return math.Q128FromRaw(fv.PositionVariance).Add(velocityVariance).Raw()
+}
+
+// Returns an upper bound of the filter "position" delta epochs in the future: the extrapolated position plus
+// a (non-negative, Q.128) confidence number of standard deviations of the extrapolated position.
+// Output is in Q.0 format, rounded up
+func (fe *FilterEstimate) ExtrapolateUpperBound(variance FilterVariance, delta abi.ChainEpoch, confidence math.Q128) big.Int {
+	bound := fe.extrapolatePosition(delta).Add(extrapolatedDeviation(variance, delta, confidence))
+	return bound.Neg().ToInt().Neg() // Rounds towards positive infinity.
+}
+
+// Returns a lower bound of the filter "position" delta epochs in the future: the extrapolated position less
+// a (non-negative, Q.128) confidence number of standard deviations of the extrapolated position.
+// Output is in Q.0 format, rounded down
+func (fe *FilterEstimate) ExtrapolateLowerBound(variance FilterVariance, delta abi.ChainEpoch, confidence math.Q128) big.Int {
+	bound := fe.extrapolatePosition(delta).Sub(extrapolatedDeviation(variance, delta, confidence))
+	return bound.ToInt()
+}
+
+func (fe *FilterEstimate) extrapolatePosition(delta abi.ChainEpoch) math.Q128 {
+	deltaT := math.Q128FromInt64(int64(delta))
+	return math.Q128FromRaw(fe.PositionEstimate).Add(math.Q128FromRaw(fe.VelocityEstimate).MulTrunc(deltaT))
+}
+
+func extrapolatedDeviation(variance FilterVariance, delta abi.ChainEpoch, confidence math.Q128) math.Q128 {
+	return math.Q128FromRaw(variance.Extrapolate(delta)).Sqrt().MulTrunc(confidence)
 }
@@ -1,11 +1,13 @@
 package smoothing_test
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
 	"github.com/filecoin-project/specs-actors/v2/actors/util/math"
@@ -44,10 +46,7 @@ func TestCumSumRatioProjection(t *testing.T) {
 	iterativeCumSumOfRatio := func(num, denom smoothing.FilterEstimate, t0, delta abi.ChainEpoch) big.Int {
 		ratio := big.Zero() // Q.128
 		for i := abi.ChainEpoch(0); i < delta; i++ {
-			numEpsilon := num.Extrapolate(t0 + i)                   // Q.256
-			denomEpsilon := denom.Extrapolate(t0 + i)               // Q.256
-			denomEpsilon = big.Rsh(denomEpsilon, math.Precision128) // Q.256 => Q.128
-			epsilon := big.Div(numEpsilon, denomEpsilon)            // Q.256 / Q.128 => Q.128
+			epsilon := num.DivideBy(denom).Extrapolate(t0 + i) // Q.128
 			if i != abi.ChainEpoch(0) && i != delta-1 {
 				epsilon = big.Mul(big.NewInt(2), epsilon) // Q.128 * Q.0 => Q.128
 			}
@@ -151,6 +150,114 @@ func TestCumSumRatioProjection(t *testing.T) {
 
 }
 
+func TestCumSumRatioDenominatorCrossingZero(t *testing.T) {
+	numEstimate := smoothing.TestingConstantEstimate(big.NewInt(500))
+	// The denominator falls from 1000 to zero at epoch 100.
+	denomEstimate := smoothing.TestingEstimate(big.NewInt(1000), big.NewInt(-10))
+
+	t.Run("positive throughout", func(t *testing.T) {
+		csr, epochs, err := smoothing.ExtrapolatedCumSumOfRatioChecked(50, 0, numEstimate, denomEstimate)
+		require.NoError(t, err)
+		assert.Equal(t, abi.ChainEpoch(50), epochs)
+		assert.Equal(t, smoothing.ExtrapolatedCumSumOfRatio(50, 0, numEstimate, denomEstimate), csr)
+
+		// An increasing denominator never crosses zero.
+		rising := smoothing.TestingEstimate(big.NewInt(1000), big.NewInt(10))
+		_, epochs, err = smoothing.ExtrapolatedCumSumOfRatioChecked(1e6, 0, numEstimate, rising)
+		require.NoError(t, err)
+		assert.Equal(t, abi.ChainEpoch(1e6), epochs)
+	})
+
+	t.Run("crossing within interval", func(t *testing.T) {
+		csr, epochs, err := smoothing.ExtrapolatedCumSumOfRatioChecked(200, 0, numEstimate, denomEstimate)
+		assert.Equal(t, smoothing.ErrDenominatorCrossesZero, err)
+		// The sum is over the epochs before the denominator reaches zero.
+		assert.Equal(t, abi.ChainEpoch(99), epochs)
+		assert.Equal(t, smoothing.ExtrapolatedCumSumOfRatio(99, 0, numEstimate, denomEstimate), csr)
+		// The integral of 500 / (1000 - 10t) over [0, 99] is 50 ln(100) ~= 230.
+		assert.Equal(t, big.NewInt(230), big.Rsh(csr, math.Precision128))
+
+		// Ending at the crossing epoch is a crossing; ending just before is not.
+		_, epochs, err = smoothing.ExtrapolatedCumSumOfRatioChecked(100, 0, numEstimate, denomEstimate)
+		assert.Equal(t, smoothing.ErrDenominatorCrossesZero, err)
+		assert.Equal(t, abi.ChainEpoch(99), epochs)
+		_, epochs, err = smoothing.ExtrapolatedCumSumOfRatioChecked(99, 0, numEstimate, denomEstimate)
+		assert.NoError(t, err)
+		assert.Equal(t, abi.ChainEpoch(99), epochs)
+
+		// Starting in the last positive epoch leaves nothing to sum.
+		csr, epochs, err = smoothing.ExtrapolatedCumSumOfRatioChecked(10, 99, numEstimate, denomEstimate)
+		assert.Equal(t, smoothing.ErrDenominatorCrossesZero, err)
+		assert.Equal(t, abi.ChainEpoch(0), epochs)
+		assert.Equal(t, big.Zero(), csr)
+	})
+
+	t.Run("not positive at start", func(t *testing.T) {
+		for _, start := range []abi.ChainEpoch{100, 150} {
+			csr, epochs, err := smoothing.ExtrapolatedCumSumOfRatioChecked(10, start, numEstimate, denomEstimate)
+			assert.Equal(t, smoothing.ErrDenominatorNotPositive, err)
+			assert.Equal(t, abi.ChainEpoch(0), epochs)
+			assert.Equal(t, big.Zero(), csr)
+		}
+	})
+}
+
+func TestFilterVariance(t *testing.T) {
+	t.Run("exact observations have no variance", func(t *testing.T) {
+		estimate := smoothing.TestingEstimate(big.NewInt(1000), big.NewInt(10))
+		variance := smoothing.DefaultInitialVariance()
+		for i := int64(1); i <= 100; i++ {
+			filter := smoothing.LoadFilterWithVariance(estimate, variance, smoothing.DefaultAlpha, smoothing.DefaultBeta)
+			estimate, variance = filter.NextEstimateWithVariance(big.NewInt(1000+10*i), 1)
+		}
+		assert.Equal(t, big.Zero(), variance.PositionVariance)
+		assert.Equal(t, big.Zero(), variance.VelocityVariance)
+
+		two := math.Q128FromInt64(2)
+		assert.Equal(t, big.NewInt(3000), estimate.ExtrapolateUpperBound(variance, 100, two))
+		assert.Equal(t, big.NewInt(3000), estimate.ExtrapolateLowerBound(variance, 100, two))
+	})
+
+	t.Run("variance tracks noise", func(t *testing.T) {
+		noise := int64(1000)
+		estimate := smoothing.TestingConstantEstimate(big.NewInt(1e9))
+		variance := smoothing.DefaultInitialVariance()
+		for i := 0; i < 20000; i++ {
+			observation := big.NewInt(1e9 + noise)
+			if i%2 == 0 {
+				observation = big.NewInt(1e9 - noise)
+			}
+			filter := smoothing.LoadFilterWithVariance(estimate, variance, smoothing.DefaultAlpha, smoothing.DefaultBeta)
+			next, nextVariance := filter.NextEstimateWithVariance(observation, 1)
+			// The estimate is the same as that of a filter not tracking variance.
+			assert.Equal(t, filter.NextEstimate(observation, 1), next)
+			estimate, variance = next, nextVariance
+		}
+
+		// The standard deviation of the position converges towards the noise.
+		deviation := math.Q128FromRaw(variance.PositionVariance).Sqrt().ToInt()
+		assert.True(t, deviation.GreaterThan(big.NewInt(noise*9/10)), "deviation %v", deviation)
+		assert.True(t, deviation.LessThan(big.NewInt(noise*11/10)), "deviation %v", deviation)
+
+		one := math.Q128FromInt64(1)
+		three := math.Q128FromInt64(3)
+		upper1 := estimate.ExtrapolateUpperBound(variance, 0, one)
+		lower1 := estimate.ExtrapolateLowerBound(variance, 0, one)
+		upper3 := estimate.ExtrapolateUpperBound(variance, 0, three)
+		lower3 := estimate.ExtrapolateLowerBound(variance, 0, three)
+		assert.True(t, lower3.LessThan(lower1))
+		assert.True(t, lower1.LessThan(estimate.Estimate()))
+		assert.True(t, estimate.Estimate().LessThan(upper1))
+		assert.True(t, upper1.LessThan(upper3))
+
+		// Uncertainty in the velocity widens the bounds further in the future.
+		width := big.Sub(upper1, lower1)
+		laterWidth := big.Sub(estimate.ExtrapolateUpperBound(variance, 1000, one), estimate.ExtrapolateLowerBound(variance, 1000, one))
+		assert.True(t, width.LessThan(laterWidth))
+		assert.Equal(t, variance.PositionVariance, variance.Extrapolate(0))
+	})
+}
+
 // Millionths of difference between val1 and val2
 // (val1 - val2) / val1 * 1e6
 // all inputs Q.128, output Q.0
@@ -167,3 +274,38 @@ func perMillionError(val1, val2 big.Int) big.Int {
 	}
 	return big.Rsh(perMillion, 2*math.Precision128)
 }
+
+func TestFilterEstimateFormat(t *testing.T) {
+	fe := smoothing.NewEstimate(big.NewInt(100), big.NewInt(-3))
+	assert.Equal(t, "{position: 100.000000000000, velocity: -3.000000000000}", fe.String())
+
+	// Estimates round-trip through JSON as decimal strings of their Q.128 values.
+	data, err := json.Marshal(fe)
+	require.NoError(t, err)
+	var decoded smoothing.FilterEstimate
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, fe, decoded)
+}
+
+func TestRatioEstimate(t *testing.T) {
+	ratio := smoothing.TestingConstantEstimate(big.NewInt(4e6)).DivideBy(smoothing.TestingConstantEstimate(big.NewInt(2)))
+	assert.Equal(t, big.Lsh(big.NewInt(2e6), math.Precision128), ratio.Extrapolate(1000))
+	assert.Equal(t, big.NewInt(2e9), big.Rsh(ratio.ExtrapolatedCumSum(1000, 0), math.Precision128))
+	assert.Equal(t, big.NewInt(6e9), ratio.ExtrapolatedCumSumTimes(big.NewInt(3), 1000, 0))
+
+	// The ratio of 1e12 to 200e12 for 100 epochs is 1/2, which is lost unless multiplied before truncating to Q.0.
+	ratio = smoothing.TestingConstantEstimate(big.NewInt(1e12)).DivideBy(smoothing.TestingConstantEstimate(big.NewInt(200e12)))
+	assert.Equal(t, big.Zero(), big.Rsh(ratio.ExtrapolatedCumSum(100, 0), math.Precision128))
+	assert.Equal(t, big.NewInt(5000), ratio.ExtrapolatedCumSumTimes(big.NewInt(10000), 100, 0))
+
+	// The ratio of linear estimates varies over time.
+	ratio = smoothing.TestingEstimate(big.NewInt(100), big.NewInt(10)).DivideBy(smoothing.TestingConstantEstimate(big.NewInt(10)))
+	assert.Equal(t, big.Lsh(big.NewInt(10), math.Precision128), ratio.Extrapolate(0))
+	assert.Equal(t, big.Lsh(big.NewInt(20), math.Precision128), ratio.Extrapolate(10))
+
+	sum, epochs, err := smoothing.TestingConstantEstimate(big.NewInt(1)).
+		DivideBy(smoothing.TestingEstimate(big.NewInt(1000), big.NewInt(-10))).ExtrapolatedCumSumChecked(200, 0)
+	assert.Equal(t, smoothing.ErrDenominatorCrossesZero, err)
+	assert.Equal(t, abi.ChainEpoch(99), epochs)
+	assert.True(t, sum.GreaterThan(big.Zero()))
+}
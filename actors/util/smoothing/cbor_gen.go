@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 
+	acbor "github.com/filecoin-project/specs-actors/v2/actors/util/cbor"
 	cbg "github.com/whyrusleeping/cbor-gen"
 	xerrors "golang.org/x/xerrors"
 )
@@ -43,14 +44,14 @@ func (t *FilterEstimate) UnmarshalCBOR(r io.Reader) error {
 
 	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
-		return err
+		return acbor.WrapDecodeError(err, "smoothing.FilterEstimate", "")
 	}
 	if maj != cbg.MajArray {
-		return fmt.Errorf("cbor input should be of type array")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "smoothing.FilterEstimate", "")
 	}
 
 	if extra != 2 {
-		return fmt.Errorf("cbor input had wrong number of fields")
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "smoothing.FilterEstimate", "")
 	}
 
 	// t.PositionEstimate (big.Int) (struct)
@@ -58,7 +59,7 @@ func (t *FilterEstimate) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.PositionEstimate.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.PositionEstimate: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.PositionEstimate: %w", err), "smoothing.FilterEstimate", "PositionEstimate")
 		}
 
 	}
@@ -67,9 +68,79 @@ func (t *FilterEstimate) UnmarshalCBOR(r io.Reader) error {
 	{
 
 		if err := t.VelocityEstimate.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.VelocityEstimate: %w", err)
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.VelocityEstimate: %w", err), "smoothing.FilterEstimate", "VelocityEstimate")
 		}
 
 	}
 	return nil
 }
+
+var lengthBufFilterVariance = []byte{130}
+
+func (t *FilterVariance) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufFilterVariance); err != nil {
+		return err
+	}
+
+	// t.PositionVariance (big.Int) (struct)
+	if err := t.PositionVariance.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.VelocityVariance (big.Int) (struct)
+	if err := t.VelocityVariance.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *FilterVariance) UnmarshalCBOR(r io.Reader) error {
+	*t = FilterVariance{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return acbor.WrapDecodeError(err, "smoothing.FilterVariance", "")
+	}
+	if maj != cbg.MajArray {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input should be of type array"), "smoothing.FilterVariance", "")
+	}
+
+	if extra != 2 {
+		return acbor.WrapDecodeError(fmt.Errorf("cbor input had wrong number of fields"), "smoothing.FilterVariance", "")
+	}
+
+	// t.PositionVariance (big.Int) (struct)
+
+	{
+
+		if err := t.PositionVariance.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.PositionVariance: %w", err), "smoothing.FilterVariance", "PositionVariance")
+		}
+
+	}
+	// t.VelocityVariance (big.Int) (struct)
+
+	{
+
+		if err := t.VelocityVariance.UnmarshalCBOR(br); err != nil {
+			return acbor.WrapDecodeError(xerrors.Errorf("unmarshaling t.VelocityVariance: %w", err), "smoothing.FilterVariance", "VelocityVariance")
+		}
+
+	}
+	return nil
+}
+
+func (t *FilterEstimate) MaxEncodedLength() int64 {
+	return 261
+}
+
+func (t *FilterVariance) MaxEncodedLength() int64 {
+	return 261
+}
@@ -0,0 +1,113 @@
+package smoothing
+
+import (
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/network"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/util"
+	"github.com/filecoin-project/specs-actors/v2/actors/util/math"
+)
+
+// The gains of an alpha beta filter, which determine how aggressively its estimates follow observations.
+type FilterConfig struct {
+	Alpha big.Int // Q.128
+	Beta  big.Int // Q.128
+}
+
+func DefaultFilterConfig() FilterConfig {
+	return FilterConfig{
+		Alpha: DefaultAlpha,
+		Beta:  DefaultBeta,
+	}
+}
+
+// Checks that the gains are those of a stable filter: 0 < alpha < 1, 0 < beta, and 2*alpha + beta < 4.
+func (c FilterConfig) Validate() error {
+	alpha := math.Q128FromRaw(c.Alpha)
+	beta := math.Q128FromRaw(c.Beta)
+	if alpha.Sign() <= 0 || !alpha.LessThan(math.Q128FromInt64(1)) {
+		return xerrors.Errorf("filter alpha %v not in (0, 1)", alpha)
+	}
+	if beta.Sign() <= 0 {
+		return xerrors.Errorf("filter beta %v not positive", beta)
+	}
+	if !alpha.MulInt(big.NewInt(2)).Add(beta).LessThan(math.Q128FromInt64(4)) {
+		return xerrors.Errorf("filter with alpha %v and beta %v is unstable", alpha, beta)
+	}
+	return nil
+}
+
+// Loads a filter with the config's gains from a previous estimate.
+func (c FilterConfig) LoadFilter(prevEstimate FilterEstimate) *AlphaBetaFilter {
+	return LoadFilter(prevEstimate, c.Alpha, c.Beta)
+}
+
+func (c FilterConfig) Equals(o FilterConfig) bool {
+	return c.Alpha.Equals(o.Alpha) && c.Beta.Equals(o.Beta)
+}
+
+// Create a new filter estimate given two Q.0 format ints, for a filter with the given config.
+func NewEstimateWithConfig(position, velocity big.Int, config FilterConfig) (FilterEstimate, error) {
+	if err := config.Validate(); err != nil {
+		return FilterEstimate{}, err
+	}
+	return NewEstimate(position, velocity), nil
+}
+
+// Returns the next estimate of a filter with the given config.
+func NextEstimateWithConfig(prevEstimate FilterEstimate, observation big.Int, epochDelta abi.ChainEpoch, config FilterConfig) FilterEstimate {
+	return config.LoadFilter(prevEstimate).NextEstimate(observation, epochDelta)
+}
+
+// Returns an estimate to continue from when a filter's config changes from one to another, given the current Q.0
+// observation.
+// The estimate's position lags observations by an amount depending on the gains with which it was computed, so
+// the position is re-seeded at the observation. The velocity is retained.
+// If the configs are the same, the estimate is unchanged.
+func ReseedEstimate(prevEstimate FilterEstimate, observation big.Int, from, to FilterConfig) FilterEstimate {
+	if from.Equals(to) {
+		return prevEstimate
+	}
+	return FilterEstimate{
+		PositionEstimate: math.Q128FromInt(observation).Raw(),
+		VelocityEstimate: prevEstimate.VelocityEstimate,
+	}
+}
+
+// A filter config in effect from a network version.
+type ScheduledFilterConfig struct {
+	Version network.Version
+	Config  FilterConfig
+}
+
+// Filter configs in order of the network versions from which they are in effect, each until the next.
+type FilterSchedule []ScheduledFilterConfig
+
+// Returns the config in effect at a network version.
+// Versions before the first scheduled are given the first config.
+func (s FilterSchedule) ForVersion(nv network.Version) FilterConfig {
+	return s[util.VersionIndex(s.versions(), nv)].Config
+}
+
+// Checks that the schedule is non-empty, in increasing order of version, and that every config is valid.
+func (s FilterSchedule) Validate() error {
+	if err := util.ValidateVersions(s.versions()); err != nil {
+		return xerrors.Errorf("filter schedule: %w", err)
+	}
+	for _, scheduled := range s {
+		if err := scheduled.Config.Validate(); err != nil {
+			return xerrors.Errorf("filter config for version %d: %w", scheduled.Version, err)
+		}
+	}
+	return nil
+}
+
+func (s FilterSchedule) versions() []network.Version {
+	versions := make([]network.Version, len(s))
+	for i, scheduled := range s {
+		versions[i] = scheduled.Version
+	}
+	return versions
+}
@@ -0,0 +1,74 @@
+package smoothing_test
+
+import (
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/util/math"
+	"github.com/filecoin-project/specs-actors/v2/actors/util/smoothing"
+)
+
+func TestFilterConfig(t *testing.T) {
+	// Q.128 gains twice the defaults.
+	fast := smoothing.FilterConfig{
+		Alpha: big.Mul(smoothing.DefaultAlpha, big.NewInt(2)),
+		Beta:  big.Mul(smoothing.DefaultBeta, big.NewInt(2)),
+	}
+
+	t.Run("validate", func(t *testing.T) {
+		assert.NoError(t, smoothing.DefaultFilterConfig().Validate())
+		assert.NoError(t, fast.Validate())
+
+		one := math.Q128FromInt64(1).Raw()
+		half := math.Q128FromRaw(one).DivInt(big.NewInt(2)).Raw()
+		assert.Error(t, smoothing.FilterConfig{Alpha: big.Zero(), Beta: half}.Validate())
+		assert.Error(t, smoothing.FilterConfig{Alpha: one, Beta: half}.Validate())
+		assert.Error(t, smoothing.FilterConfig{Alpha: half, Beta: big.Zero()}.Validate())
+		assert.Error(t, smoothing.FilterConfig{Alpha: half, Beta: math.Q128FromInt64(3).Raw()}.Validate())
+
+		_, err := smoothing.NewEstimateWithConfig(big.NewInt(1), big.NewInt(1), smoothing.FilterConfig{Alpha: big.Zero(), Beta: half})
+		assert.Error(t, err)
+		estimate, err := smoothing.NewEstimateWithConfig(big.NewInt(1), big.NewInt(1), fast)
+		require.NoError(t, err)
+		assert.Equal(t, smoothing.NewEstimate(big.NewInt(1), big.NewInt(1)), estimate)
+	})
+
+	t.Run("gains determine responsiveness", func(t *testing.T) {
+		prev := smoothing.TestingConstantEstimate(big.NewInt(1000))
+		observation := big.NewInt(2000)
+		slowNext := smoothing.NextEstimateWithConfig(prev, observation, 1, smoothing.DefaultFilterConfig())
+		fastNext := smoothing.NextEstimateWithConfig(prev, observation, 1, fast)
+		assert.Equal(t, smoothing.LoadFilter(prev, smoothing.DefaultAlpha, smoothing.DefaultBeta).NextEstimate(observation, 1), slowNext)
+		assert.True(t, slowNext.PositionEstimate.LessThan(fastNext.PositionEstimate))
+		assert.True(t, slowNext.VelocityEstimate.LessThan(fastNext.VelocityEstimate))
+	})
+
+	t.Run("schedule", func(t *testing.T) {
+		schedule := smoothing.FilterSchedule{
+			{Version: network.Version0, Config: smoothing.DefaultFilterConfig()},
+			{Version: network.Version5, Config: fast},
+		}
+		require.NoError(t, schedule.Validate())
+		assert.Equal(t, smoothing.DefaultFilterConfig(), schedule.ForVersion(network.Version0))
+		assert.Equal(t, smoothing.DefaultFilterConfig(), schedule.ForVersion(network.Version4))
+		assert.Equal(t, fast, schedule.ForVersion(network.Version5))
+		assert.Equal(t, fast, schedule.ForVersion(network.Version6))
+
+		assert.Error(t, smoothing.FilterSchedule{}.Validate())
+		assert.Error(t, smoothing.FilterSchedule{schedule[1], schedule[0]}.Validate())
+		assert.Error(t, smoothing.FilterSchedule{{Version: network.Version0, Config: smoothing.FilterConfig{Alpha: big.Zero(), Beta: big.Zero()}}}.Validate())
+	})
+
+	t.Run("reseed", func(t *testing.T) {
+		prev := smoothing.TestingEstimate(big.NewInt(1000), big.NewInt(5))
+		assert.Equal(t, prev, smoothing.ReseedEstimate(prev, big.NewInt(1200), smoothing.DefaultFilterConfig(), smoothing.DefaultFilterConfig()))
+
+		reseeded := smoothing.ReseedEstimate(prev, big.NewInt(1200), smoothing.DefaultFilterConfig(), fast)
+		assert.Equal(t, big.NewInt(1200), reseeded.Estimate())
+		assert.Equal(t, prev.VelocityEstimate, reseeded.VelocityEstimate)
+	})
+}
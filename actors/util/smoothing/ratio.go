@@ -0,0 +1,57 @@
+package smoothing
+
+import (
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/util/math"
+)
+
+// An estimate of the ratio of two filtered series, such as the block reward per unit of network power, projected
+// from the two filters' estimates at the same epoch.
+//
+// Precision: ratios are Q.128, truncated, so a ratio is exact to within 2^-128 of the ratio of the extrapolated
+// positions. A cumulative sum is computed in closed form from the integral of the ratio of the two linear
+// extrapolations, rather than by summing each epoch's ratio. Its intermediate values are truncated to Q.128, and
+// it differs from the sum of the epochs' ratios by the integral's approximation of the sum, which is within a few
+// hundred parts per million for sums over a day or more of the reward and power estimates seen on the network,
+// but worse over a few epochs.
+type RatioEstimate struct {
+	Numerator   FilterEstimate
+	Denominator FilterEstimate
+}
+
+// Returns an estimate of the ratio of this filter's series to another's.
+func (fe FilterEstimate) DivideBy(denom FilterEstimate) RatioEstimate {
+	return RatioEstimate{Numerator: fe, Denominator: denom}
+}
+
+// Returns the ratio of the extrapolated positions delta epochs in the future.
+// The denominator's extrapolated position must not be zero.
+// Output is in Q.128 format
+func (r RatioEstimate) Extrapolate(delta abi.ChainEpoch) big.Int {
+	num := math.Q256FromRaw(r.Numerator.Extrapolate(delta))
+	denom := math.Q256FromRaw(r.Denominator.Extrapolate(delta)).Trunc()
+	return num.Div(denom).Raw()
+}
+
+// Returns the sum of the ratio over the delta epochs from relativeStart epochs in the future.
+// See ExtrapolatedCumSumOfRatio.
+// Output is in Q.128 format
+func (r RatioEstimate) ExtrapolatedCumSum(delta, relativeStart abi.ChainEpoch) big.Int {
+	return ExtrapolatedCumSumOfRatio(delta, relativeStart, r.Numerator, r.Denominator)
+}
+
+// Returns the sum of the ratio over the delta epochs from relativeStart epochs in the future, over only those
+// epochs in which the denominator's extrapolated position is positive. See ExtrapolatedCumSumOfRatioChecked.
+// Output is in Q.128 format
+func (r RatioEstimate) ExtrapolatedCumSumChecked(delta, relativeStart abi.ChainEpoch) (big.Int, abi.ChainEpoch, error) {
+	return ExtrapolatedCumSumOfRatioChecked(delta, relativeStart, r.Numerator, r.Denominator)
+}
+
+// Returns a quantity multiplied by the sum of the ratio over the delta epochs from relativeStart epochs in the
+// future, e.g. the reward expected to be earned by some power over a period.
+// Input and output are in Q.0 format, the output rounded down
+func (r RatioEstimate) ExtrapolatedCumSumTimes(quantity big.Int, delta, relativeStart abi.ChainEpoch) big.Int {
+	return math.Q128FromRaw(r.ExtrapolatedCumSum(delta, relativeStart)).MulInt(quantity).ToInt()
+}
@@ -0,0 +1,33 @@
+package util
+
+import (
+	"github.com/filecoin-project/go-state-types/network"
+	"golang.org/x/xerrors"
+)
+
+// Returns the index of the entry of a schedule in effect at a network version, given the versions from which the
+// schedule's entries are in effect, each until the next.
+// Versions before the first scheduled are given the first entry. The versions must be valid (see ValidateVersions).
+func VersionIndex(versions []network.Version, nv network.Version) int {
+	index := 0
+	for i, v := range versions[1:] {
+		if v > nv {
+			break
+		}
+		index = i + 1
+	}
+	return index
+}
+
+// Checks that the versions from which a schedule's entries are in effect are non-empty and increasing.
+func ValidateVersions(versions []network.Version) error {
+	if len(versions) == 0 {
+		return xerrors.Errorf("empty schedule")
+	}
+	for i := 1; i < len(versions); i++ {
+		if versions[i] <= versions[i-1] {
+			return xerrors.Errorf("version %d does not follow %d", versions[i], versions[i-1])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package util_test
+
+import (
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/network"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/util"
+)
+
+func TestVersionIndex(t *testing.T) {
+	versions := []network.Version{network.Version2, network.Version5}
+	assert.Equal(t, 0, util.VersionIndex(versions, network.Version0))
+	assert.Equal(t, 0, util.VersionIndex(versions, network.Version4))
+	assert.Equal(t, 1, util.VersionIndex(versions, network.Version5))
+	assert.Equal(t, 1, util.VersionIndex(versions, network.Version6))
+	assert.Equal(t, 0, util.VersionIndex([]network.Version{network.Version0}, network.Version6))
+}
+
+func TestValidateVersions(t *testing.T) {
+	assert.NoError(t, util.ValidateVersions([]network.Version{network.Version0}))
+	assert.NoError(t, util.ValidateVersions([]network.Version{network.Version2, network.Version5}))
+	assert.Error(t, util.ValidateVersions(nil))
+	assert.Error(t, util.ValidateVersions([]network.Version{network.Version5, network.Version2}))
+	assert.Error(t, util.ValidateVersions([]network.Version{network.Version2, network.Version2}))
+}
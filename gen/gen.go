@@ -1,26 +1,47 @@
 package main
 
 import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"math"
+	"reflect"
+	"regexp"
+	goruntime "runtime"
+	"sort"
+	"strings"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-bitfield"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/crypto"
+	"github.com/filecoin-project/go-state-types/network"
+	"github.com/ipfs/go-cid"
 	gen "github.com/whyrusleeping/cbor-gen"
 
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/account"
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/cron"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/exported"
 	init_ "github.com/filecoin-project/specs-actors/v2/actors/builtin/init"
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/market"
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/miner"
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/multisig"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/paramreg"
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/paych"
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/power"
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/reward"
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/system"
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/verifreg"
 	"github.com/filecoin-project/specs-actors/v2/actors/util/smoothing"
+	"github.com/filecoin-project/specs-actors/v2/support/puppet"
+	vm "github.com/filecoin-project/specs-actors/v2/support/vm"
 )
 
 func main() {
 	// Common types
-	//if err := gen.WriteTupleEncodersToFile("./actors/runtime/proof/cbor_gen.go", "proof",
+	//if err := writeTupleEncodersToFile("./actors/runtime/proof/cbor_gen.go", "proof",
 	//proof.SectorInfo{}, // Aliased from v0
 	//proof.SealVerifyInfo{}, // Aliased from v0
 	//proof.PoStProof{}, // Aliased from v0
@@ -30,57 +51,85 @@ func main() {
 	//	panic(err)
 	//}
 
-	if err := gen.WriteTupleEncodersToFile("./actors/builtin/cbor_gen.go", "builtin",
+	if err := writeTupleEncodersToFile("./actors/builtin/cbor_gen.go", "builtin",
 		builtin.MinerAddrs{},
 		//builtin.ConfirmSectorProofsParams{},  // Aliased from v0
 		builtin.ApplyRewardParams{},
+		builtin.TransferEvent{},
+		builtin.DealPublishedEvent{},
+		builtin.SectorActivatedEvent{},
+		builtin.PowerUpdatedEvent{},
+		builtin.SectorsFaultedEvent{},
+		builtin.DataCapGrantedEvent{},
+		builtin.SectorsFailure{},
+		builtin.DealsFailure{},
 	); err != nil {
 		panic(err)
 	}
 
-	// if err := gen.WriteTupleEncodersToFile("./actors/states/cbor_gen.go", "states",
+	// if err := writeTupleEncodersToFile("./actors/states/cbor_gen.go", "states",
 	// 	states.Actor{},
 	// ); err != nil {
 	// 	panic(err)
 	// }
 
 	// Actors
-	if err := gen.WriteTupleEncodersToFile("./actors/builtin/system/cbor_gen.go", "system",
+	if err := writeTupleEncodersToFile("./actors/builtin/system/cbor_gen.go", "system",
 		// actor state
 		system.State{},
+		// method params and returns
+		system.ConstructorParams{},
+		system.NetworkIdentityReturn{},
 	); err != nil {
 		panic(err)
 	}
 
-	if err := gen.WriteTupleEncodersToFile("./actors/builtin/account/cbor_gen.go", "account",
+	if err := writeTupleEncodersToFile("./actors/builtin/account/cbor_gen.go", "account",
 		// actor state
 		account.State{},
+		account.SessionKey{},
+		// method params and returns
+		account.AuthenticateMessageParams{},
+		account.AuthorizeSessionKeyParams{},
+		account.SessionMessage{},
+		account.AuthenticateSessionMessageParams{},
 	); err != nil {
 		panic(err)
 	}
 
-	if err := gen.WriteTupleEncodersToFile("./actors/builtin/init/cbor_gen.go", "init",
+	if err := writeTupleEncodersToFile("./actors/builtin/init/cbor_gen.go", "init",
 		// actor state
 		init_.State{},
 		// method params and returns
 		//init_.ConstructorParams{}, // Aliased from v0
 		//init_.ExecParams{}, // Aliased from v0
 		//init_.ExecReturn{}, // Aliased from v0
+		init_.Exec4Params{},
+		init_.AddressStatusReturn{},
+		// other types
+		init_.Tombstone{},
+		init_.ExecPermission{},
+		init_.UpgradeApproval{},
+		init_.ApproveUpgradeParams{},
+		init_.AuthorizeUpgradeParams{},
 	); err != nil {
 		panic(err)
 	}
 
-	if err := gen.WriteTupleEncodersToFile("./actors/builtin/cron/cbor_gen.go", "cron",
+	if err := writeTupleEncodersToFile("./actors/builtin/cron/cbor_gen.go", "cron",
 		// actor state
 		cron.State{},
 		cron.Entry{},
+		cron.UserEntry{},
 		// method params and returns
-		//cron.ConstructorParams{}, // Aliased from v0
+		cron.ConstructorParams{},
+		cron.RegisterUserEntryParams{},
+		cron.DeregisterUserEntryParams{},
 	); err != nil {
 		panic(err)
 	}
 
-	if err := gen.WriteTupleEncodersToFile("./actors/builtin/reward/cbor_gen.go", "reward",
+	if err := writeTupleEncodersToFile("./actors/builtin/reward/cbor_gen.go", "reward",
 		// actor state
 		reward.State{},
 		// method params and returns
@@ -90,14 +139,14 @@ func main() {
 		panic(err)
 	}
 
-	if err := gen.WriteTupleEncodersToFile("./actors/builtin/multisig/cbor_gen.go", "multisig",
+	if err := writeTupleEncodersToFile("./actors/builtin/multisig/cbor_gen.go", "multisig",
 		// actor state
 		multisig.State{},
-		//multisig.Transaction{}, // Aliased from v0
+		multisig.Transaction{},
 		//multisig.ProposalHashData{}, // Aliased from v0
 		// method params and returns
 		multisig.ConstructorParams{},
-		//multisig.ProposeParams{}, // Aliased from v0
+		multisig.ProposeParams{},
 		//multisig.ProposeReturn{}, // Aliased from v0
 		//multisig.AddSignerParams{}, // Aliased from v0
 		//multisig.RemoveSignerParams{}, // Aliased from v0
@@ -106,17 +155,20 @@ func main() {
 		//multisig.ChangeNumApprovalsThresholdParams{}, // Aliased from v0
 		//multisig.SwapSignerParams{}, // Aliased from v0
 		//multisig.LockBalanceParams{}, // Aliased from v0
+		multisig.PruneExpiredReturn{},
+		multisig.ApproveBySessionKeyParams{},
 	); err != nil {
 		panic(err)
 	}
 
-	if err := gen.WriteTupleEncodersToFile("./actors/builtin/paych/cbor_gen.go", "paych",
+	if err := writeTupleEncodersToFile("./actors/builtin/paych/cbor_gen.go", "paych",
 		// actor state
 		paych.State{},
 		paych.LaneState{},
 		// method params and returns
 		//paych.ConstructorParams{}, // Aliased from v0
 		paych.UpdateChannelStateParams{},
+		paych.PartialCollectReturn{},
 		//paych.SignedVoucher{}, // Aliased from v0
 		//paych.ModVerifyParams{}, // Aliased from v0
 		// other types
@@ -125,7 +177,7 @@ func main() {
 		panic(err)
 	}
 
-	if err := gen.WriteTupleEncodersToFile("./actors/builtin/power/cbor_gen.go", "power",
+	if err := writeTupleEncodersToFile("./actors/builtin/power/cbor_gen.go", "power",
 		// actors state
 		power.State{},
 		power.Claim{},
@@ -136,33 +188,40 @@ func main() {
 		//power.EnrollCronEventParams{}, // Aliased from v0
 		//power.UpdateClaimedPowerParams{}, // Aliased from v0
 		power.CurrentTotalPowerReturn{},
+		power.CurrentSmoothedEstimatesReturn{},
 		// other types
 		power.MinerConstructorParams{},
 	); err != nil {
 		panic(err)
 	}
 
-	if err := gen.WriteTupleEncodersToFile("./actors/builtin/market/cbor_gen.go", "market",
+	if err := writeTupleEncodersToFile("./actors/builtin/market/cbor_gen.go", "market",
 		// actor state
 		market.State{},
 		// method params and returns
 		//market.WithdrawBalanceParams{}, // Aliased from v0
 		//market.PublishStorageDealsParams{}, // Aliased from v0
 		//market.PublishStorageDealsReturn{}, // Aliased from v0
+		market.PublishStorageDealsAggregateParams{},
 		//market.ActivateDealsParams{}, // Aliased from v0
 		//market.VerifyDealsForActivationParams{}, // Aliased from v0
 		market.VerifyDealsForActivationReturn{},
 		//market.ComputeDataCommitmentParams{}, // Aliased from v0
 		//market.OnMinerSectorsTerminateParams{}, // Aliased from v0
+		market.GetDealsParams{},
+		market.GetDealsReturn{},
+		market.GetDealsByProviderParams{},
+		market.GetDealsByProviderReturn{},
 		// other types
 		//market.DealProposal{}, // Aliased from v0
 		//market.ClientDealProposal{}, // Aliased from v0
 		market.DealState{},
+		market.DealInfo{},
 	); err != nil {
 		panic(err)
 	}
 
-	if err := gen.WriteTupleEncodersToFile("./actors/builtin/miner/cbor_gen.go", "miner",
+	if err := writeTupleEncodersToFile("./actors/builtin/miner/cbor_gen.go", "miner",
 		// actor state
 		miner.State{},
 		miner.MinerInfo{},
@@ -182,7 +241,7 @@ func main() {
 		//miner.SubmitWindowedPoStParams{}, // Aliased from v0
 		//miner.TerminateSectorsParams{}, // Aliased from v0
 		//miner.TerminateSectorsReturn{}, // Aliased from v0
-		//miner.ChangePeerIDParams{}, // Aliased from v0
+		mapEncodedFrom{miner.ChangePeerIDParams{}, network.Version6},
 		//miner.ChangeMultiaddrsParams{}, // Aliased from v0
 		//miner.ProveCommitSectorParams{}, // Aliased from v0
 		//miner.ChangeWorkerAddressParams{},  // Aliased from v0
@@ -191,8 +250,12 @@ func main() {
 		//miner.DeclareFaultsRecoveredParams{}, // Aliased from v0
 		//miner.ReportConsensusFaultParams{}, // Aliased from v0
 		miner.GetControlAddressesReturn{},
+		miner.ProveCommitSectorsParams{},
+		miner.ProveCommitSectorsReturn{},
+		miner.PreCommitSectorBatchParams{},
+		miner.SubmitWindowedPoStBatchParams{},
 		//miner.CheckSectorProvenParams{}, // Aliased from v0
-		//miner.WithdrawBalanceParams{}, // Aliased from v0
+		miner.WithdrawBalanceParams{},
 		//miner.CompactPartitionsParams{}, // Aliased from v0
 		//miner.CompactSectorNumbersParams{}, // Aliased from v0
 		//miner.CronEventPayload{}, // Aliased from v0
@@ -206,7 +269,7 @@ func main() {
 		panic(err)
 	}
 
-	if err := gen.WriteTupleEncodersToFile("./actors/builtin/verifreg/cbor_gen.go", "verifreg",
+	if err := writeTupleEncodersToFile("./actors/builtin/verifreg/cbor_gen.go", "verifreg",
 		// actor state
 		verifreg.State{},
 		// method params and returns
@@ -214,15 +277,619 @@ func main() {
 		//verifreg.AddVerifiedClientParams{}, // Aliased from v0
 		//verifreg.UseBytesParams{}, // Aliased from v0
 		//verifreg.RestoreBytesParams{}, // Aliased from v0
+		verifreg.GetDataCapEventsParams{},
+		verifreg.GetDataCapEventsReturn{},
+		verifreg.AddVerifiedClientsParams{},
+		verifreg.AddVerifiedClientsReturn{},
+		verifreg.CheckClientSeparationParams{},
+		verifreg.CheckClientSeparationReturn{},
+		verifreg.RemoveVerifiedClientDataCapParams{},
+		verifreg.RemoveVerifiedClientDataCapReturn{},
+		verifreg.RemoveDataCapRequest{},
+		verifreg.RemoveDataCapProposal{},
 		// other types
+		verifreg.DataCapEvent{},
+		verifreg.DataCapEventSet{},
+		verifreg.DataCapEventRecord{},
+		verifreg.DataCapEventsCursor{},
+		verifreg.RemoveDataCapProposalID{},
 	); err != nil {
 		panic(err)
 	}
 
-	if err := gen.WriteTupleEncodersToFile("./actors/util/smoothing/cbor_gen.go", "smoothing",
+	if err := writeTupleEncodersToFile("./actors/builtin/paramreg/cbor_gen.go", "paramreg",
+		// actor state
+		paramreg.State{},
+		// method params and returns
+		paramreg.GetParams{},
+		paramreg.GetReturn{},
+		// other types
+		paramreg.Parameter{},
+		paramreg.ParameterChange{},
+	); err != nil {
+		panic(err)
+	}
+
+	if err := writeTupleEncodersToFile("./actors/util/smoothing/cbor_gen.go", "smoothing",
 		smoothing.FilterEstimate{},
+		smoothing.FilterVariance{},
+	); err != nil {
+		panic(err)
+	}
+
+	// Test support
+	if err := writeTupleEncodersToFile("./support/vm/cbor_gen.go", "vm_test",
+		vm.ChainMessage{},
+		vm.ExportedVM{},
 	); err != nil {
 		panic(err)
 	}
 
+	if err := writeTupleEncodersToFile("./support/puppet/cbor_gen.go", "puppet",
+		puppet.State{},
+		puppet.Invocation{},
+		puppet.Script{},
+		puppet.Step{},
+		puppet.SendStep{},
+		puppet.StepResult{},
+		puppet.ScriptReturn{},
+	); err != nil {
+		panic(err)
+	}
+
+	// Method metadata
+	if err := writeMethodTableToFile("./actors/builtin/exported/methods_gen.go", "exported"); err != nil {
+		panic(err)
+	}
+}
+
+// Writes tuple encoders for types to a file, as gen.WriteTupleEncodersToFile does, extending the generated code
+// for map and optional fields, and with the maximum length of each type's encoding.
+//
+// Maps must have string keys and struct values. The generated encoder writes a map's entries sorted by key; this
+// sorts them in CBOR canonical order (shorter keys first, then bytewise), as do other DAG-CBOR encoders, and imports
+// the sort package the encoder needs.
+//
+// Fields tagged `cborgen:"optional"` must follow all other fields, and be pointers, to a struct or CID, or integers.
+// An absent (nil) optional pointer field is encoded as null, and null decodes to nil, so a value round trips exactly.
+// An optional integer field is always encoded.
+// A tuple may also omit optional fields at its end, which decode as absent or zero, so that an optional field can be
+// added to a struct without breaking the decoding of encodings made before it existed.
+//
+// A type given as mapEncodedFrom is encoded as a tuple before a network version and as a map from it on, and its
+// decoder accepts either encoding.
+func writeTupleEncodersToFile(fname, pkg string, entries ...interface{}) error {
+	types := make([]interface{}, len(entries))
+	mapFrom := map[reflect.Type]network.Version{}
+	for i, entry := range entries {
+		types[i] = entry
+		if versioned, ok := entry.(mapEncodedFrom); ok {
+			types[i] = versioned.value
+			mapFrom[reflect.TypeOf(versioned.value)] = versioned.version
+			mapEncodedTypes[reflect.TypeOf(versioned.value)] = true
+		}
+	}
+	if err := gen.WriteTupleEncodersToFile(fname, pkg, types...); err != nil {
+		return err
+	}
+	src, err := ioutil.ReadFile(fname)
+	if err != nil {
+		return err
+	}
+	orig := src
+	if bytes.Contains(src, []byte("sort.Strings(keys)")) {
+		src = bytes.ReplaceAll(src, []byte("sort.Strings(keys)"), []byte(`sort.Slice(keys, func(i, j int) bool {
+			if len(keys[i]) != len(keys[j]) {
+				return len(keys[i]) < len(keys[j])
+			}
+			return keys[i] < keys[j]
+		})`))
+		src = bytes.Replace(src, []byte("\t\"io\"\n"), []byte("\t\"io\"\n\t\"sort\"\n"), 1)
+	}
+	for _, typ := range types {
+		if src, err = addDecodeErrorPaths(src, reflect.TypeOf(typ), "UnmarshalCBOR"); err != nil {
+			return err
+		}
+	}
+	src = addImport(src, "acbor", "github.com/filecoin-project/specs-actors/v2/actors/util/cbor")
+	for _, typ := range types {
+		if src, err = allowOptionalFields(src, reflect.TypeOf(typ)); err != nil {
+			return err
+		}
+	}
+	for _, typ := range types {
+		if version, ok := mapFrom[reflect.TypeOf(typ)]; ok {
+			if src, err = addMapEncoding(src, typ, version); err != nil {
+				return err
+			}
+		}
+	}
+	for _, typ := range types {
+		src = appendMaxEncodedLength(src, reflect.TypeOf(typ))
+	}
+	if bytes.Equal(src, orig) {
+		return nil
+	}
+	if src, err = format.Source(src); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fname, src, 0644)
+}
+
+// Rewrites the generated encoder and decoder of a type with optional fields, to write null for an absent field
+// and accept tuples omitting the fields.
+func allowOptionalFields(src []byte, typ reflect.Type) ([]byte, error) {
+	if typ.Kind() != reflect.Struct {
+		return src, nil
+	}
+	var fields []reflect.StructField
+	for i := 0; i < typ.NumField(); i++ {
+		if f := typ.Field(i); f.PkgPath == "" {
+			fields = append(fields, f)
+		}
+	}
+	required := len(fields)
+	for i, f := range fields {
+		optional := f.Tag.Get("cborgen") == "optional"
+		if optional && !isOptionalKind(f.Type.Kind()) {
+			return nil, fmt.Errorf("optional field %s.%s must be a pointer or integer", typ.Name(), f.Name)
+		}
+		if optional && required == len(fields) {
+			required = i
+		} else if !optional && required < len(fields) {
+			return nil, fmt.Errorf("field %s.%s follows an optional field", typ.Name(), f.Name)
+		}
+	}
+	if required == len(fields) {
+		return src, nil
+	}
+
+	// The encoder writes null for an absent field, rather than relying on the field type's encoder to do so.
+	src, err := rewriteMethod(src, typ, "MarshalCBOR", func(encoder []byte) []byte {
+		for _, f := range fields[required:] {
+			if f.Type.Kind() != reflect.Ptr {
+				continue
+			}
+			// The generated encoder already writes null for an absent CID.
+			call := []byte(fmt.Sprintf("if err := t.%s.MarshalCBOR(w); err != nil {", f.Name))
+			encoder = bytes.Replace(encoder, call, append([]byte(fmt.Sprintf(
+				"if t.%s == nil {\nif _, err := w.Write(cbg.CborNull); err != nil {\nreturn err\n}\n} else ", f.Name)), call...), 1)
+		}
+		return encoder
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The decoder accepts a tuple ending before any optional field.
+	// The field count is kept aside, as decoders of some field types reuse the variable holding it.
+	countCheck := []byte(fmt.Sprintf("if extra != %d {", len(fields)))
+	if !bytes.Contains(src, countCheck) {
+		return nil, fmt.Errorf("no field count check generated for %s", typ.Name())
+	}
+	return rewriteMethod(src, typ, "UnmarshalCBOR", func(decoder []byte) []byte {
+		decoder = bytes.Replace(decoder, countCheck, []byte(fmt.Sprintf("fieldCount := extra\nif fieldCount < %d || fieldCount > %d {", required, len(fields))), 1)
+		for i := required; i < len(fields); i++ {
+			comment := []byte(fmt.Sprintf("// t.%s (", fields[i].Name))
+			decoder = bytes.Replace(decoder, comment, append([]byte(fmt.Sprintf("if fieldCount <= %d {\nreturn nil\n}\n", i)), comment...), 1)
+		}
+		return decoder
+	})
+}
+
+func isOptionalKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Ptr, reflect.Int64, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// A type encoded as a tuple of its fields before a network version, and as a map keyed by field name from it on.
+type mapEncodedFrom struct {
+	value   interface{}
+	version network.Version
+}
+
+// Types encoded as maps from some network version, whose maximum encoded length is that of the map.
+var mapEncodedTypes = map[reflect.Type]bool{}
+
+// Adds a map encoding to a type with generated tuple encoders, renaming those to marshalCBORTuple and
+// unmarshalCBORTuple. The type's MarshalCBOR writes the tuple, MarshalCBORForVersion the encoding for a network
+// version, and UnmarshalCBOR reads either.
+// The map's keys are written in the order of the fields, which must be canonical so that the encoding is.
+func addMapEncoding(src []byte, value interface{}, version network.Version) ([]byte, error) {
+	typ := reflect.TypeOf(value)
+	var fields []string
+	for i := 0; i < typ.NumField(); i++ {
+		if f := typ.Field(i); f.PkgPath == "" {
+			fields = append(fields, f.Name)
+		}
+	}
+	for i := 1; i < len(fields); i++ {
+		prev, next := fields[i-1], fields[i]
+		if len(prev) > len(next) || (len(prev) == len(next) && prev >= next) {
+			return nil, fmt.Errorf("fields %s and %s of map encoded %s are not in canonical order", prev, next, typ.Name())
+		}
+	}
+
+	for _, method := range []string{"MarshalCBOR", "UnmarshalCBOR"} {
+		name := []byte(fmt.Sprintf("func (t *%s) %s(", typ.Name(), method))
+		if !bytes.Contains(src, name) {
+			return nil, fmt.Errorf("no %s generated for %s", method, typ.Name())
+		}
+		src = bytes.Replace(src, name, []byte(fmt.Sprintf("func (t *%s) %sTuple(", typ.Name(), lowerFirst(method))), 1)
+	}
+
+	gti, err := gen.ParseTypeInfo(value)
+	if err != nil {
+		return nil, err
+	}
+	var mapSrc bytes.Buffer
+	if err := gen.GenMapEncodersForType(gti, &mapSrc); err != nil {
+		return nil, err
+	}
+	mapCode, err := addDecodeErrorPaths(mapSrc.Bytes(), typ, "UnmarshalCBOR")
+	if err != nil {
+		return nil, err
+	}
+	for _, method := range []string{"MarshalCBOR", "UnmarshalCBOR"} {
+		name := []byte(fmt.Sprintf("func (t *%s) %s(", typ.Name(), method))
+		mapCode = bytes.Replace(mapCode, name, []byte(fmt.Sprintf("func (t *%s) %sMap(", typ.Name(), lowerFirst(method))), 1)
+	}
+	// The decoder rejects unknown fields, so a map with more entries than the type has fields repeats a field.
+	mapCode = bytes.Replace(mapCode, []byte("if extra > cbg.MaxLength {"), []byte(fmt.Sprintf("if extra > %d {", len(fields))), 1)
+
+	src = append(append(src, '\n'), mapCode...)
+	src = append(src, fmt.Sprintf(`
+func (t *%[1]s) MarshalCBOR(w io.Writer) error {
+	return t.marshalCBORTuple(w)
+}
+
+func (t *%[1]s) MarshalCBORForVersion(w io.Writer, nv network.Version) error {
+	if nv >= t.MapEncodingVersion() {
+		return t.marshalCBORMap(w)
+	}
+	return t.marshalCBORTuple(w)
+}
+
+func (t *%[1]s) MapEncodingVersion() network.Version {
+	return %[2]d
+}
+
+func (t *%[1]s) UnmarshalCBOR(r io.Reader) error {
+	br := cbg.GetPeeker(r)
+	first, err := br.ReadByte()
+	if err != nil {
+		return err
+	}
+	if err := br.UnreadByte(); err != nil {
+		return err
+	}
+	if first>>5 == cbg.MajMap {
+		return t.unmarshalCBORMap(br)
+	}
+	return t.unmarshalCBORTuple(br)
+}
+`, typ.Name(), version)...)
+
+	return addImport(src, "network", "github.com/filecoin-project/go-state-types/network"), nil
+}
+
+// Adds a named import to generated source, alongside the cbor-gen import, if not already present.
+func addImport(src []byte, name, path string) []byte {
+	spec := []byte(fmt.Sprintf("\t%s %q\n", name, path))
+	if bytes.Contains(src, spec) {
+		return src
+	}
+	cbgSpec := []byte("\tcbg \"github.com/whyrusleeping/cbor-gen\"\n")
+	return bytes.Replace(src, cbgSpec, append(spec, cbgSpec...), 1)
+}
+
+var fieldComment = regexp.MustCompile(`^// t\.(\w+) \(`)
+
+// Rewrites the generated decoder of a type to wrap each error it returns with the type and the field being decoded,
+// including the index of the element for an error decoding an element of a collection, as a DecodeError.
+// Errors from decoding a field's value extend the path, so that the error from decoding a nested value locates it
+// from the outermost type.
+func addDecodeErrorPaths(src []byte, typ reflect.Type, method string) ([]byte, error) {
+	return rewriteMethod(src, typ, method, func(decoder []byte) []byte {
+		lines := bytes.Split(decoder, []byte("\n"))
+		field := ""       // The field being decoded, if any
+		loopDepth := -1   // The depth of braces at which a loop over the field's elements began, if any
+		switchDepth := -1 // The depth of braces within a map's switch on field names, if any
+		depth := 0
+		for i, line := range lines {
+			trimmed := bytes.TrimSpace(line)
+			if m := fieldComment.FindSubmatch(trimmed); m != nil {
+				field, loopDepth = string(m[1]), -1
+			} else if bytes.Equal(trimmed, []byte("switch name {")) {
+				switchDepth = depth + 1
+			} else if bytes.Equal(trimmed, []byte("default:")) && depth == switchDepth {
+				field = "" // An unknown field of a map
+			} else if field != "" && loopDepth < 0 && bytes.HasPrefix(trimmed, []byte("for i")) {
+				loopDepth = depth
+			} else if bytes.HasPrefix(trimmed, []byte("return ")) && !bytes.Equal(trimmed, []byte("return nil")) {
+				location := `""`
+				if field != "" && loopDepth >= 0 {
+					location = fmt.Sprintf(`fmt.Sprintf("%s[%%d]", i)`, field)
+				} else if field != "" {
+					location = fmt.Sprintf("%q", field)
+				}
+				indent := line[:len(line)-len(bytes.TrimLeft(line, "\t"))]
+				lines[i] = []byte(fmt.Sprintf("%sreturn acbor.WrapDecodeError(%s, %q, %s)",
+					indent, trimmed[len("return "):], typ.String(), location))
+			}
+			depth += bytes.Count(line, []byte("{")) - bytes.Count(line, []byte("}"))
+			if loopDepth >= 0 && depth <= loopDepth {
+				loopDepth = -1
+			}
+		}
+		return bytes.Join(lines, []byte("\n"))
+	})
+}
+
+func lowerFirst(s string) string {
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+// Replaces the generated source of a type's method with the result of rewriting it.
+func rewriteMethod(src []byte, typ reflect.Type, method string, rewrite func([]byte) []byte) ([]byte, error) {
+	start := bytes.Index(src, []byte(fmt.Sprintf("func (t *%s) %s(", typ.Name(), method)))
+	if start < 0 {
+		return nil, fmt.Errorf("no %s generated for %s", method, typ.Name())
+	}
+	end := len(src)
+	if next := bytes.Index(src[start+1:], []byte("\nfunc ")); next >= 0 {
+		end = start + 1 + next
+	}
+	rewritten := rewrite(append([]byte{}, src[start:end]...))
+	return append(append(append([]byte{}, src[:start]...), rewritten...), src[end:]...), nil
+}
+
+// Appends a method returning the maximum length of an encoding of a type accepted by its decoder, if the length
+// is bounded. The VM rejects params longer than this without decoding them.
+func appendMaxEncodedLength(src []byte, typ reflect.Type) []byte {
+	max, ok := maxEncodedLength(typ)
+	if !ok {
+		return src
+	}
+	return append(src, fmt.Sprintf("\nfunc (t *%s) MaxEncodedLength() int64 {\nreturn %d\n}\n", typ.Name(), max)...)
+}
+
+var (
+	addressType   = reflect.TypeOf(address.Address{})
+	bigIntType    = reflect.TypeOf(big.Int{})
+	bitfieldType  = reflect.TypeOf(bitfield.BitField{})
+	cidType       = reflect.TypeOf(cid.Cid{})
+	deferredType  = reflect.TypeOf(gen.Deferred{})
+	signatureType = reflect.TypeOf(crypto.Signature{})
+	cborBytesType = reflect.TypeOf(builtin.CBORBytes{})
+)
+
+// Lengths beyond which an encoding is considered unbounded.
+const unboundedLength = uint64(1) << 53
+
+// Returns the maximum length of an encoding of a type accepted by the decoders, which limit the length of
+// collections, strings and bytes, and whether it is bounded.
+// Structs other than those with their own encodings are encoded by the generated code as tuples.
+func maxEncodedLength(typ reflect.Type) (uint64, bool) {
+	switch typ {
+	case addressType:
+		return bytesLength(64), true // The address decoder's limit
+	case bigIntType:
+		return bytesLength(big.BigIntMaxSerializedLen), true
+	case bitfieldType:
+		return bytesLength(bitfield.MaxEncodedSize), true
+	case cidType:
+		return 2 + bytesLength(512), true // A tag and bytes, limited by the CID decoder
+	case signatureType:
+		return bytesLength(1 + crypto.SignatureMaxLength), true
+	case deferredType, cborBytesType:
+		return 0, false
+	}
+	if typ.ConvertibleTo(cidType) {
+		return maxEncodedLength(cidType) // A CID wrapper such as typegen.CborCid
+	}
+
+	var max uint64
+	switch typ.Kind() {
+	case reflect.Bool:
+		max = 1
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		max = headerLength(math.MaxUint64)
+	case reflect.String:
+		max = bytesLength(gen.MaxLength)
+	case reflect.Array:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return bytesLength(uint64(typ.Len())), true
+		}
+		elem, ok := maxEncodedLength(typ.Elem())
+		if !ok {
+			return 0, false
+		}
+		max = headerLength(uint64(typ.Len())) + uint64(typ.Len())*elem
+	case reflect.Slice:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return bytesLength(gen.ByteArrayMaxLen), true
+		}
+		elem, ok := maxEncodedLength(typ.Elem())
+		if !ok || elem > unboundedLength/gen.MaxLength {
+			return 0, false
+		}
+		max = headerLength(gen.MaxLength) + gen.MaxLength*elem
+	case reflect.Map:
+		const maxEntries = 4096 // The generated decoder's limit
+		key, ok := maxEncodedLength(typ.Key())
+		if !ok {
+			return 0, false
+		}
+		value, ok := maxEncodedLength(typ.Elem())
+		if !ok || key+value > unboundedLength/maxEntries {
+			return 0, false
+		}
+		max = headerLength(maxEntries) + maxEntries*(key+value)
+	case reflect.Ptr:
+		elem, ok := maxEncodedLength(typ.Elem())
+		if !ok {
+			return 0, false
+		}
+		max = elem
+		if max < 1 {
+			max = 1 // null
+		}
+	case reflect.Struct:
+		if mapEncodedTypes[typ] {
+			return maxMapEncodedLength(typ)
+		}
+		max = headerLength(uint64(typ.NumField()))
+		for i := 0; i < typ.NumField(); i++ {
+			if typ.Field(i).PkgPath != "" {
+				return 0, false // A struct with unexported fields has its own encoding
+			}
+			field, ok := maxEncodedLength(typ.Field(i).Type)
+			if !ok {
+				return 0, false
+			}
+			max += field
+		}
+	default:
+		return 0, false
+	}
+	return max, max <= unboundedLength
+}
+
+// Returns the maximum length of either the tuple or the map encoding of a type encoded as a map from a network
+// version. The map decoder accepts at most one entry per field, but may accept any field for each entry.
+func maxMapEncodedLength(typ reflect.Type) (uint64, bool) {
+	var maxEntry uint64
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.PkgPath != "" {
+			return 0, false
+		}
+		field, ok := maxEncodedLength(f.Type)
+		if !ok {
+			return 0, false
+		}
+		if entry := bytesLength(uint64(len(f.Name))) + field; entry > maxEntry {
+			maxEntry = entry
+		}
+	}
+	n := uint64(typ.NumField())
+	// Each map entry is longer than the corresponding tuple element, so the map is the longer encoding.
+	max := headerLength(n) + n*maxEntry
+	return max, max <= unboundedLength
+}
+
+// The length of a byte string of at most n bytes.
+func bytesLength(n uint64) uint64 {
+	return headerLength(n) + n
+}
+
+// The length of a CBOR header encoding n.
+func headerLength(n uint64) uint64 {
+	switch {
+	case n < 24:
+		return 1
+	case n <= math.MaxUint8:
+		return 2
+	case n <= math.MaxUint16:
+		return 3
+	case n <= math.MaxUint32:
+		return 5
+	default:
+		return 9
+	}
+}
+
+// Writes a table of the methods exported by each builtin actor, keyed by actor name, with each method's name and
+// params and return types.
+// The table is generated, rather than built by reflection when first used, so that a change to an actor's methods
+// shows up in review as a change to the table.
+func writeMethodTableToFile(fname, pkg string) error {
+	type method struct {
+		num         int
+		name        string
+		params, ret reflect.Type
+	}
+	type actor struct {
+		name    string
+		methods []method
+	}
+	var actors []actor
+	imports := map[string]string{} // Package path to name
+	for _, a := range exported.BuiltinActors() {
+		entry := actor{name: builtin.ActorNameByCode(a.Code())}
+		for num, m := range a.Exports() {
+			if m == nil {
+				continue
+			}
+			name := goruntime.FuncForPC(reflect.ValueOf(m).Pointer()).Name()
+			name = strings.TrimSuffix(name, "-fm")
+			name = name[strings.LastIndexByte(name, '.')+1:]
+			typ := reflect.TypeOf(m)
+			params, ret := typ.In(1).Elem(), typ.Out(0).Elem()
+			for _, t := range []reflect.Type{params, ret} {
+				if t.Name() == "" || t.PkgPath() == "" {
+					return fmt.Errorf("method %s of %s has unnamed params or return type %v", name, entry.name, t)
+				}
+				imports[t.PkgPath()] = strings.SplitN(t.String(), ".", 2)[0]
+			}
+			entry.methods = append(entry.methods, method{num: num, name: name, params: params, ret: ret})
+		}
+		actors = append(actors, entry)
+	}
+
+	// Alias each imported package by its name, suffixed to distinguish packages from earlier actors versions and
+	// any others of the same name.
+	paths := make([]string, 0, len(imports))
+	for path := range imports { // nolint:nomaprange
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	aliases := map[string]string{"reflect": "reflect"}
+	used := map[string]bool{"reflect": true}
+	for _, path := range paths {
+		alias := imports[path]
+		if alias == "init" {
+			alias = "init_"
+		}
+		if strings.HasPrefix(path, "github.com/filecoin-project/specs-actors/actors/") {
+			alias += "0"
+		}
+		for base, i := alias, 1; used[alias]; i++ {
+			alias = fmt.Sprintf("%s%d", base, i)
+		}
+		aliases[path] = alias
+		used[alias] = true
+	}
+	typeName := func(t reflect.Type) string {
+		return aliases[t.PkgPath()] + "." + t.Name()
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by github.com/filecoin-project/specs-actors/v2/gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\nimport (\n\t\"reflect\"\n\n", pkg)
+	for _, path := range paths {
+		fmt.Fprintf(&buf, "\t%s %q\n", aliases[path], path)
+	}
+	fmt.Fprintf(&buf, ")\n\n")
+	fmt.Fprintf(&buf, "var builtinMethods = map[string][]MethodMeta{\n")
+	for _, a := range actors {
+		fmt.Fprintf(&buf, "\t%q: {\n", a.name)
+		for _, m := range a.methods {
+			fmt.Fprintf(&buf, "\t\t{Num: %d, Name: %q, Params: reflect.TypeOf((*%s)(nil)).Elem(), Return: reflect.TypeOf((*%s)(nil)).Elem()},\n",
+				m.num, m.name, typeName(m.params), typeName(m.ret))
+		}
+		fmt.Fprintf(&buf, "\t},\n")
+	}
+	fmt.Fprintf(&buf, "}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fname, src, 0644)
 }
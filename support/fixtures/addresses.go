@@ -0,0 +1,53 @@
+package fixtures
+
+import (
+	"fmt"
+	"testing"
+
+	addr "github.com/filecoin-project/go-address"
+
+	tutil "github.com/filecoin-project/specs-actors/v2/support/testing"
+)
+
+// Allocates distinct addresses deterministically, so tests needn't pick and track address numbers by hand.
+// Factories constructed with the same arguments produce the same sequence of addresses.
+type AddressFactory struct {
+	t      testing.TB
+	nextID uint64
+	seq    int
+}
+
+// Creates a factory allocating ID addresses from `firstID` upwards.
+func NewAddressFactory(t testing.TB, firstID uint64) *AddressFactory {
+	return &AddressFactory{t: t, nextID: firstID}
+}
+
+// Returns the next ID address.
+func (f *AddressFactory) NextID() addr.Address {
+	a := tutil.NewIDAddr(f.t, f.nextID)
+	f.nextID++
+	return a
+}
+
+// Returns the next n ID addresses.
+func (f *AddressFactory) NextIDs(n int) []addr.Address {
+	addrs := make([]addr.Address, n)
+	for i := range addrs {
+		addrs[i] = f.NextID()
+	}
+	return addrs
+}
+
+// Returns the next robust actor address, as the init actor assigns to an actor it creates.
+func (f *AddressFactory) NextActor() addr.Address {
+	a := tutil.NewActorAddr(f.t, fmt.Sprintf("actor-%d", f.seq))
+	f.seq++
+	return a
+}
+
+// Returns the next BLS public key address.
+func (f *AddressFactory) NextBLS() addr.Address {
+	a := tutil.NewBLSAddr(f.t, int64(f.seq))
+	f.seq++
+	return a
+}
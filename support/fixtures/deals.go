@@ -0,0 +1,60 @@
+package fixtures
+
+import (
+	addr "github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/market"
+	tutil "github.com/filecoin-project/specs-actors/v2/support/testing"
+)
+
+// Builds deal proposals, defaulting all but the parties and term to small valid values.
+type DealBuilder struct {
+	proposal market.DealProposal
+}
+
+// Starts a proposal between a client and provider for the term [start, end).
+// The default piece is a 2KiB piece labelled "label", and the price and both collaterals are 10 attoFIL.
+func NewDealBuilder(client, provider addr.Address, start, end abi.ChainEpoch) *DealBuilder {
+	return &DealBuilder{market.DealProposal{
+		PieceCID:             tutil.MakeCID("1", &market.PieceCIDPrefix),
+		PieceSize:            abi.PaddedPieceSize(2048),
+		Client:               client,
+		Provider:             provider,
+		Label:                "label",
+		StartEpoch:           start,
+		EndEpoch:             end,
+		StoragePricePerEpoch: big.NewInt(10),
+		ProviderCollateral:   big.NewInt(10),
+		ClientCollateral:     big.NewInt(10),
+	}}
+}
+
+// Sets the piece, with a CID derived from the label, so that distinctly labelled deals have distinct pieces.
+func (b *DealBuilder) WithPiece(label string, size abi.PaddedPieceSize) *DealBuilder {
+	b.proposal.PieceCID = tutil.MakeCID(label, &market.PieceCIDPrefix)
+	b.proposal.PieceSize = size
+	b.proposal.Label = label
+	return b
+}
+
+func (b *DealBuilder) WithPrice(perEpoch abi.TokenAmount) *DealBuilder {
+	b.proposal.StoragePricePerEpoch = perEpoch
+	return b
+}
+
+func (b *DealBuilder) WithCollateral(provider, client abi.TokenAmount) *DealBuilder {
+	b.proposal.ProviderCollateral = provider
+	b.proposal.ClientCollateral = client
+	return b
+}
+
+func (b *DealBuilder) WithVerified(verified bool) *DealBuilder {
+	b.proposal.VerifiedDeal = verified
+	return b
+}
+
+func (b *DealBuilder) Build() market.DealProposal {
+	return b.proposal
+}
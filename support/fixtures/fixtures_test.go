@@ -0,0 +1,68 @@
+package fixtures_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/miner"
+	"github.com/filecoin-project/specs-actors/v2/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/v2/support/fixtures"
+	"github.com/filecoin-project/specs-actors/v2/support/ipld"
+)
+
+func TestMinerBuilder(t *testing.T) {
+	t.Run("builds empty state", func(t *testing.T) {
+		store := ipld.NewADTStore(context.Background())
+		st := fixtures.NewMinerBuilder(t, store).Build()
+		summary := checkMinerState(t, st, store)
+		assert.True(t, summary.LivePower.IsZero())
+	})
+
+	t.Run("builds sectors and faults", func(t *testing.T) {
+		store := ipld.NewADTStore(context.Background())
+		pledged := fixtures.NewSectorBuilder(10).WithPledge(abi.NewTokenAmount(1000)).Build()
+		st := fixtures.NewMinerBuilder(t, store).
+			WithSealProof(abi.RegisteredSealProof_StackedDrg2KiBV1).
+			WithSectors(5).
+			WithSectorInfos(pledged).
+			WithFaults(1, 10).
+			Build()
+
+		summary := checkMinerState(t, st, store)
+		sectorPower := miner.QAPowerForWeight(2048, fixtures.DefaultSectorExpiration, big.Zero(), big.Zero())
+		assert.Equal(t, big.Mul(big.NewInt(2), sectorPower), summary.FaultyPower.QA)
+		assert.Equal(t, abi.NewTokenAmount(1000), st.InitialPledge)
+
+		for sno := abi.SectorNumber(0); sno < 5; sno++ {
+			found, err := st.HasSectorNo(store, sno)
+			require.NoError(t, err)
+			assert.True(t, found)
+		}
+	})
+
+	t.Run("deal builder and address factory are deterministic", func(t *testing.T) {
+		a, b := fixtures.NewAddressFactory(t, 100), fixtures.NewAddressFactory(t, 100)
+		assert.Equal(t, a.NextIDs(3), b.NextIDs(3))
+		assert.Equal(t, a.NextActor(), b.NextActor())
+		assert.NotEqual(t, a.NextActor(), b.NextBLS())
+
+		client, provider := a.NextID(), a.NextID()
+		deal := fixtures.NewDealBuilder(client, provider, 10, 20).WithPiece("p", 1024).WithVerified(true).Build()
+		assert.Equal(t, client, deal.Client)
+		assert.Equal(t, abi.PaddedPieceSize(1024), deal.PieceSize)
+		assert.True(t, deal.VerifiedDeal)
+		assert.NotEqual(t, fixtures.NewDealBuilder(client, provider, 10, 20).Build().PieceCID, deal.PieceCID)
+	})
+}
+
+func checkMinerState(t *testing.T, st *miner.State, store adt.Store) *miner.StateSummary {
+	summary, msgs := miner.CheckStateInvariants(st, store, st.InitialPledge)
+	assert.True(t, msgs.IsEmpty(), strings.Join(msgs.Messages(), "\n"))
+	return summary
+}
@@ -0,0 +1,193 @@
+package fixtures
+
+import (
+	"testing"
+
+	addr "github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-bitfield"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/miner"
+	"github.com/filecoin-project/specs-actors/v2/actors/util/adt"
+)
+
+// Builds miner actor state directly in a store, for tests of state methods and of code reading miner state,
+// without driving the actor through the messages that would produce it.
+type MinerBuilder struct {
+	t           testing.TB
+	store       adt.Store
+	info        miner.MinerInfo
+	periodStart abi.ChainEpoch
+	epoch       abi.ChainEpoch
+	sectors     []*miner.SectorOnChainInfo
+	faults      []abi.SectorNumber
+}
+
+// Starts a miner with 32GiB seal proofs, owned and operated by ID addresses 100 and 101, with no sectors.
+// The state is built as of epoch zero.
+func NewMinerBuilder(t testing.TB, store adt.Store) *MinerBuilder {
+	addrs := NewAddressFactory(t, 100)
+	b := &MinerBuilder{
+		t:     t,
+		store: store,
+		info: miner.MinerInfo{
+			Owner:  addrs.NextID(),
+			Worker: addrs.NextID(),
+			PeerId: abi.PeerID("peer"),
+		},
+	}
+	return b.WithSealProof(abi.RegisteredSealProof_StackedDrg32GiBV1)
+}
+
+func (b *MinerBuilder) WithOwner(owner, worker addr.Address) *MinerBuilder {
+	b.info.Owner = owner
+	b.info.Worker = worker
+	return b
+}
+
+func (b *MinerBuilder) WithControlAddrs(addrs ...addr.Address) *MinerBuilder {
+	b.info.ControlAddresses = addrs
+	return b
+}
+
+func (b *MinerBuilder) WithPeer(peer abi.PeerID, multiaddrs ...abi.Multiaddrs) *MinerBuilder {
+	b.info.PeerId = peer
+	b.info.Multiaddrs = multiaddrs
+	return b
+}
+
+// Sets the seal proof type, and the sector and partition sizes following from it.
+func (b *MinerBuilder) WithSealProof(proof abi.RegisteredSealProof) *MinerBuilder {
+	sectorSize, err := proof.SectorSize()
+	require.NoError(b.t, err)
+	partitionSectors, err := builtin.SealProofWindowPoStPartitionSectors(proof)
+	require.NoError(b.t, err)
+	b.info.SealProofType = proof
+	b.info.SectorSize = sectorSize
+	b.info.WindowPoStPartitionSectors = partitionSectors
+	return b
+}
+
+// Sets the start of the proving period current at the build epoch.
+func (b *MinerBuilder) WithProvingPeriodStart(start abi.ChainEpoch) *MinerBuilder {
+	b.periodStart = start
+	return b
+}
+
+// Sets the epoch at which sectors are assigned to deadlines and faults declared.
+func (b *MinerBuilder) WithEpoch(epoch abi.ChainEpoch) *MinerBuilder {
+	b.epoch = epoch
+	return b
+}
+
+// Adds n sectors with default values and the miner's seal proof, numbered after any sectors already added.
+func (b *MinerBuilder) WithSectors(n int) *MinerBuilder {
+	next := abi.SectorNumber(0)
+	if len(b.sectors) > 0 {
+		next = b.sectors[len(b.sectors)-1].SectorNumber + 1
+	}
+	for i := 0; i < n; i++ {
+		b.sectors = append(b.sectors, NewSectorBuilder(next).WithSealProof(b.info.SealProofType).Build())
+		next++
+	}
+	return b
+}
+
+// Adds sectors, which must have numbers greater than those already added.
+func (b *MinerBuilder) WithSectorInfos(sectors ...*miner.SectorOnChainInfo) *MinerBuilder {
+	b.sectors = append(b.sectors, sectors...)
+	return b
+}
+
+// Declares sectors faulty at the build epoch. The sectors must have been added.
+func (b *MinerBuilder) WithFaults(sectorNos ...abi.SectorNumber) *MinerBuilder {
+	b.faults = append(b.faults, sectorNos...)
+	return b
+}
+
+// Builds the state: allocates the sectors' numbers, stores them and assigns them to deadlines, then declares
+// the faults.
+func (b *MinerBuilder) Build() *miner.State {
+	st := b.constructEmpty()
+	if len(b.sectors) == 0 {
+		return st
+	}
+
+	sectorNos := make([]uint64, len(b.sectors))
+	pledge := big.Zero()
+	for i, s := range b.sectors {
+		sectorNos[i] = uint64(s.SectorNumber)
+		pledge = big.Add(pledge, s.InitialPledge)
+	}
+	require.NoError(b.t, st.MaskSectorNumbers(b.store, bitfield.NewFromSet(sectorNos)))
+	require.NoError(b.t, st.PutSectors(b.store, b.sectors...))
+	st.AddInitialPledge(pledge)
+	_, err := st.AssignSectorsToDeadlines(b.store, b.epoch, append([]*miner.SectorOnChainInfo(nil), b.sectors...),
+		b.info.WindowPoStPartitionSectors, b.info.SectorSize)
+	require.NoError(b.t, err)
+
+	if len(b.faults) > 0 {
+		b.declareFaults(st)
+	}
+	return st
+}
+
+func (b *MinerBuilder) constructEmpty() *miner.State {
+	store := b.store
+	emptyMap, err := adt.MakeEmptyMap(store).Root()
+	require.NoError(b.t, err)
+	emptyArray, err := adt.MakeEmptyArray(store).Root()
+	require.NoError(b.t, err)
+	emptyBitfieldCid, err := store.Put(store.Context(), bitfield.NewFromSet(nil))
+	require.NoError(b.t, err)
+	emptyDeadlineCid, err := store.Put(store.Context(), miner.ConstructDeadline(emptyArray))
+	require.NoError(b.t, err)
+	emptyDeadlinesCid, err := store.Put(store.Context(), miner.ConstructDeadlines(emptyDeadlineCid))
+	require.NoError(b.t, err)
+	emptyVestingFundsCid, err := store.Put(store.Context(), miner.ConstructVestingFunds())
+	require.NoError(b.t, err)
+
+	info := b.info
+	infoCid, err := store.Put(store.Context(), &info)
+	require.NoError(b.t, err)
+
+	st, err := miner.ConstructState(infoCid, b.periodStart, 0, emptyBitfieldCid, emptyArray, emptyMap,
+		emptyDeadlinesCid, emptyVestingFundsCid)
+	require.NoError(b.t, err)
+	return st
+}
+
+func (b *MinerBuilder) declareFaults(st *miner.State) {
+	deadlines, err := st.LoadDeadlines(b.store)
+	require.NoError(b.t, err)
+	sectors, err := miner.LoadSectors(b.store, st.Sectors)
+	require.NoError(b.t, err)
+
+	faultsByDeadline := map[uint64]miner.PartitionSectorMap{}
+	for _, sno := range b.faults {
+		dlIdx, partIdx, err := miner.FindSector(b.store, deadlines, sno)
+		require.NoError(b.t, err)
+		if faultsByDeadline[dlIdx] == nil {
+			faultsByDeadline[dlIdx] = miner.PartitionSectorMap{}
+		}
+		require.NoError(b.t, faultsByDeadline[dlIdx].AddValues(partIdx, uint64(sno)))
+	}
+
+	faultExpiration := b.epoch + miner.FaultMaxAge
+	for dlIdx := uint64(0); dlIdx < miner.WPoStPeriodDeadlines; dlIdx++ {
+		partitions, ok := faultsByDeadline[dlIdx]
+		if !ok {
+			continue
+		}
+		dl, err := deadlines.LoadDeadline(b.store, dlIdx)
+		require.NoError(b.t, err)
+		_, _, err = dl.DeclareFaults(b.store, sectors, b.info.SectorSize, st.QuantSpecForDeadline(dlIdx),
+			faultExpiration, partitions)
+		require.NoError(b.t, err)
+		require.NoError(b.t, deadlines.UpdateDeadline(b.store, dlIdx, dl))
+	}
+	require.NoError(b.t, st.SaveDeadlines(b.store, deadlines))
+}
@@ -0,0 +1,84 @@
+package fixtures
+
+import (
+	"fmt"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/ipfs/go-cid"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/miner"
+	tutil "github.com/filecoin-project/specs-actors/v2/support/testing"
+)
+
+// The expiration of sectors built without an explicit expiration.
+const DefaultSectorExpiration = abi.ChainEpoch(1_000_000)
+
+// Builds on-chain sector infos, defaulting all but the sector number.
+type SectorBuilder struct {
+	info miner.SectorOnChainInfo
+}
+
+// Starts a 32GiB sector activated at epoch zero, with a sealed CID derived from its number, no deals,
+// and zero pledge and rewards.
+func NewSectorBuilder(number abi.SectorNumber) *SectorBuilder {
+	return &SectorBuilder{miner.SectorOnChainInfo{
+		SectorNumber:          number,
+		SealProof:             abi.RegisteredSealProof_StackedDrg32GiBV1,
+		SealedCID:             tutil.MakeCID(fmt.Sprintf("commR-%d", number), &miner.SealedCIDPrefix),
+		Activation:            0,
+		Expiration:            DefaultSectorExpiration,
+		DealWeight:            big.Zero(),
+		VerifiedDealWeight:    big.Zero(),
+		InitialPledge:         big.Zero(),
+		ExpectedDayReward:     big.Zero(),
+		ExpectedStoragePledge: big.Zero(),
+		ReplacedSectorAge:     0,
+		ReplacedDayReward:     big.Zero(),
+	}}
+}
+
+func (b *SectorBuilder) WithSealProof(proof abi.RegisteredSealProof) *SectorBuilder {
+	b.info.SealProof = proof
+	return b
+}
+
+func (b *SectorBuilder) WithSealedCID(sealed cid.Cid) *SectorBuilder {
+	b.info.SealedCID = sealed
+	return b
+}
+
+func (b *SectorBuilder) WithActivation(epoch abi.ChainEpoch) *SectorBuilder {
+	b.info.Activation = epoch
+	return b
+}
+
+func (b *SectorBuilder) WithExpiration(epoch abi.ChainEpoch) *SectorBuilder {
+	b.info.Expiration = epoch
+	return b
+}
+
+func (b *SectorBuilder) WithDeals(ids ...abi.DealID) *SectorBuilder {
+	b.info.DealIDs = ids
+	return b
+}
+
+func (b *SectorBuilder) WithDealWeights(weight, verifiedWeight abi.DealWeight) *SectorBuilder {
+	b.info.DealWeight = weight
+	b.info.VerifiedDealWeight = verifiedWeight
+	return b
+}
+
+func (b *SectorBuilder) WithPledge(pledge abi.TokenAmount) *SectorBuilder {
+	b.info.InitialPledge = pledge
+	return b
+}
+
+// Returns a new sector info on each call, so a builder may produce several sectors differing in some field.
+func (b *SectorBuilder) Build() *miner.SectorOnChainInfo {
+	info := b.info
+	if b.info.DealIDs != nil {
+		info.DealIDs = append([]abi.DealID(nil), b.info.DealIDs...)
+	}
+	return &info
+}
@@ -0,0 +1,125 @@
+package fuzz
+
+import (
+	"flag"
+	"math/rand"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	vm "github.com/filecoin-project/specs-actors/v2/support/vm"
+)
+
+var (
+	fuzzSequences      = flag.Int("fuzz-sequences", 2, "random sequences run by TestRandomSequences")
+	fuzzSequenceLength = flag.Int("fuzz-sequence-length", 40, "ops in each sequence run by TestRandomSequences")
+	fuzzSeed           = flag.Int64("fuzz-seed", 1, "random seed for TestRandomSequences")
+)
+
+// Runs random sequences, shrinking and reporting any which fail.
+// Run longer with e.g. -fuzz-sequences=100 -fuzz-sequence-length=200 -fuzz-seed=<n>.
+func TestRandomSequences(t *testing.T) {
+	sequences := *fuzzSequences
+	if testing.Short() {
+		sequences = 1
+	}
+	rnd := rand.New(rand.NewSource(*fuzzSeed))
+	for i := 0; i < sequences; i++ {
+		Check(t, Generate(rnd, *fuzzSequenceLength))
+	}
+}
+
+// A coverage-guided fuzz target running sequences decoded from bytes. Fuzz with e.g.
+//   go test -run '^$' -fuzz FuzzSequences ./support/fuzz
+func FuzzSequences(f *testing.F) {
+	f.Add(Encode(lifecycle))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		Check(t, Decode(data))
+	})
+}
+
+// A sequence taking a sector with a deal through its life, which each kind of op acts in.
+var lifecycle = []Op{
+	{Kind: CreateMiner},
+	{Wait: 1, Kind: PublishDeal},
+	{Wait: 1, Kind: PreCommitSector, Arg: 1},
+	{Kind: ProveCommitSector},
+	{Wait: 1, Kind: SubmitWindowedPoSt},
+	{Wait: 1, Kind: DeclareFault},
+	{Wait: 1, Kind: DeclareRecovery},
+	{Kind: SubmitWindowedPoSt},
+	{Kind: WithdrawMinerBalance},
+	{Kind: WithdrawMarketBalance},
+}
+
+func TestLifecycle(t *testing.T) {
+	r := newRunner(t)
+	for i, op := range lifecycle {
+		require.NoError(t, r.step(op), "step %d: %v", i, op)
+	}
+	require.NoError(t, r.advance(1))
+
+	require.Len(t, r.miners, 1)
+	m := r.miners[0]
+	assert.Empty(t, m.deals)
+	assert.Empty(t, m.precommits)
+	require.Len(t, m.sectors, 1)
+
+	// The sector was proven, faulted and recovered, and has power.
+	_, _, _, ok := r.locateSector(m, 0)
+	require.True(t, ok)
+	assert.True(t, vm.MinerPower(t, r.v, m.id).Raw.GreaterThan(big.Zero()))
+}
+
+func TestShrink(t *testing.T) {
+	// Fails if a fault with argument at least 10 follows a miner's creation, however long after.
+	fails := func(ops []Op) bool {
+		created := false
+		for _, op := range ops {
+			created = created || op.Kind == CreateMiner
+			if created && op.Kind == DeclareFault && op.Arg >= 10 {
+				return true
+			}
+		}
+		return false
+	}
+	var ops []Op
+	for _, op := range Generate(rand.New(rand.NewSource(1)), 60) {
+		if op.Kind != DeclareFault {
+			ops = append(ops, op)
+		}
+	}
+	ops = append(ops, Op{Kind: CreateMiner, Party: 3}, Op{Wait: 17, Kind: DeclareFault, Party: 2, Arg: 200})
+	require.True(t, fails(ops))
+
+	assert.Equal(t, []Op{
+		{Kind: CreateMiner},
+		{Kind: DeclareFault, Arg: 200},
+	}, Shrink(ops, fails))
+}
+
+func TestShrinkRunsFailures(t *testing.T) {
+	// The runner panics at an op of a kind it does not know.
+	ops := []Op{{Kind: CreateMiner}, {Wait: 3, Kind: PublishDeal}, {Wait: 2, Kind: kindCount}}
+	failure := Run(t, ops)
+	require.NotNil(t, failure)
+	assert.Equal(t, 2, failure.Step)
+	assert.Equal(t, startEpoch+5, failure.Epoch)
+	assert.Equal(t, CausePanic, failure.Cause)
+
+	shrunk := Shrink(ops, func(candidate []Op) bool { return Run(t, candidate) != nil })
+	assert.Equal(t, []Op{{Kind: kindCount}}, shrunk)
+}
+
+func TestDecode(t *testing.T) {
+	ops := Generate(rand.New(rand.NewSource(1)), 20)
+	assert.Equal(t, ops, Decode(Encode(ops)))
+
+	// Every byte string decodes, ignoring a partial op.
+	assert.Equal(t, []Op{{Wait: 1, Kind: Kind(200 % uint8(kindCount)), Party: 3, Arg: 4}},
+		Decode([]byte{1, 200, 3, 4, 5, 6}))
+	assert.Equal(t, abi.ChainEpoch(0), Decode([]byte{0, 0, 0, 0})[0].Wait)
+}
@@ -0,0 +1,119 @@
+// Package fuzz explores interactions among the builtin actors by running random sequences of plausible messages
+// against the test VM, checking the state invariants at the end of every epoch, and shrinking the sequences which
+// fail to a minimal reproduction.
+package fuzz
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/filecoin-project/go-state-types/abi"
+)
+
+// The action an Op takes.
+type Kind uint8
+
+const (
+	CreateMiner Kind = iota
+	PublishDeal
+	PreCommitSector
+	ProveCommitSector
+	SubmitWindowedPoSt
+	DeclareFault
+	DeclareRecovery
+	WithdrawMarketBalance
+	WithdrawMinerBalance
+	kindCount
+)
+
+var kindNames = [...]string{
+	CreateMiner:           "CreateMiner",
+	PublishDeal:           "PublishDeal",
+	PreCommitSector:       "PreCommitSector",
+	ProveCommitSector:     "ProveCommitSector",
+	SubmitWindowedPoSt:    "SubmitWindowedPoSt",
+	DeclareFault:          "DeclareFault",
+	DeclareRecovery:       "DeclareRecovery",
+	WithdrawMarketBalance: "WithdrawMarketBalance",
+	WithdrawMinerBalance:  "WithdrawMinerBalance",
+}
+
+func (k Kind) String() string {
+	if k < kindCount {
+		return kindNames[k]
+	}
+	return fmt.Sprintf("Kind(%d)", uint8(k))
+}
+
+// An Op is one step of a sequence: advancing some epochs, then sending the messages which take an action.
+// Ops select the parties and objects they act on by index, modulo the number available when they run, so that
+// every subsequence of a sequence is itself a sequence which runs, as shrinking requires. An op with nothing to
+// act on, such as proving a sector before any has been pre-committed, does nothing after advancing.
+type Op struct {
+	Wait  abi.ChainEpoch // Epochs to advance, running cron at the end of each, before acting.
+	Kind  Kind
+	Party uint8 // Selects the miner, or for some kinds the account, taking the action.
+	Arg   uint8 // Selects the object of the action, or scales an amount.
+}
+
+// The number of bytes from which Decode reads each op.
+const OpSize = 4
+
+// Decodes a sequence of ops from bytes, OpSize bytes to an op, ignoring any trailing partial op.
+// Every byte string decodes to a sequence, so that coverage-guided fuzzing may mutate encoded sequences freely.
+func Decode(data []byte) []Op {
+	ops := make([]Op, 0, len(data)/OpSize)
+	for ; len(data) >= OpSize; data = data[OpSize:] {
+		ops = append(ops, Op{
+			Wait:  abi.ChainEpoch(data[0]),
+			Kind:  Kind(data[1] % uint8(kindCount)),
+			Party: data[2],
+			Arg:   data[3],
+		})
+	}
+	return ops
+}
+
+// Encodes a sequence of ops such that Decode returns it. The waits of the ops must each be less than 256 epochs.
+func Encode(ops []Op) []byte {
+	data := make([]byte, 0, len(ops)*OpSize)
+	for _, op := range ops {
+		data = append(data, byte(op.Wait), byte(op.Kind), op.Party, op.Arg)
+	}
+	return data
+}
+
+// Generates a random sequence of n ops. Most ops follow the last within a few epochs, so that the actions of a
+// sequence interact, but some wait longer, letting deadlines and pre-commits lapse.
+func Generate(rnd *rand.Rand, n int) []Op {
+	ops := make([]Op, n)
+	for i := range ops {
+		wait := rnd.Intn(8)
+		if rnd.Intn(8) == 0 {
+			wait = rnd.Intn(256)
+		}
+		ops[i] = Op{
+			Wait:  abi.ChainEpoch(wait),
+			Kind:  Kind(rnd.Intn(int(kindCount))),
+			Party: uint8(rnd.Intn(256)),
+			Arg:   uint8(rnd.Intn(256)),
+		}
+	}
+	return ops
+}
+
+func (op Op) String() string {
+	return fmt.Sprintf("wait %d, %v(party %d, arg %d)", op.Wait, op.Kind, op.Party, op.Arg)
+}
+
+// Formats a sequence as a Go literal, from which a failing sequence may be reproduced in a test.
+func Format(ops []Op) string {
+	var b strings.Builder
+	b.WriteString("[]fuzz.Op{\n")
+	for _, op := range ops {
+		fmt.Fprintf(&b, "\t{Wait: %d, Kind: fuzz.%v, Party: %d, Arg: %d},\n", op.Wait, op.Kind, op.Party, op.Arg)
+	}
+	b.WriteString("}")
+	return b.String()
+}
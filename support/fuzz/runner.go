@@ -0,0 +1,525 @@
+package fuzz
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-bitfield"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/cbor"
+	"github.com/filecoin-project/go-state-types/crypto"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/market"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/miner"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/power"
+	"github.com/filecoin-project/specs-actors/v2/actors/runtime/proof"
+	"github.com/filecoin-project/specs-actors/v2/actors/states"
+	"github.com/filecoin-project/specs-actors/v2/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/v2/support/fixtures"
+	tutil "github.com/filecoin-project/specs-actors/v2/support/testing"
+	vm "github.com/filecoin-project/specs-actors/v2/support/vm"
+)
+
+const (
+	accountCount = 3
+	maxMiners    = 4
+	startEpoch   = abi.ChainEpoch(200) // Late enough for seal randomness to be drawn from the past.
+	sealProof    = abi.RegisteredSealProof_StackedDrg32GiBV1
+	postProof    = abi.RegisteredPoStProof_StackedDrgWindow32GiBV1
+	pieceSize    = abi.PaddedPieceSize(1 << 20)
+)
+
+var (
+	accountBalance = big.Mul(big.NewInt(1_000_000), vm.FIL)
+	minerBalance   = big.Mul(big.NewInt(10_000), vm.FIL)
+	chainRand      = []byte("not really random")
+)
+
+// What went wrong in a failing sequence.
+type Cause int
+
+const (
+	// A message aborted with an exit code which plausible messages should not reach, indicating that an actor
+	// found its state inconsistent or misbehaved.
+	CauseExitCode Cause = iota
+	// The state invariants did not hold at the end of an epoch.
+	CauseInvariants
+	// Cron failed, which the chain does not permit.
+	CauseCron
+	// The VM panicked, as it does on internal errors.
+	CausePanic
+)
+
+func (c Cause) String() string {
+	switch c {
+	case CauseExitCode:
+		return "exit code"
+	case CauseInvariants:
+		return "invariants"
+	case CauseCron:
+		return "cron"
+	case CausePanic:
+		return "panic"
+	default:
+		return fmt.Sprintf("Cause(%d)", int(c))
+	}
+}
+
+// A Failure describes how a sequence failed.
+type Failure struct {
+	Step  int // The index of the op during which the sequence failed, or the sequence's length if after the last.
+	Epoch abi.ChainEpoch
+	Cause Cause
+	Err   error
+}
+
+func (f *Failure) Error() string {
+	return fmt.Sprintf("step %d at epoch %d: %v: %v", f.Step, f.Epoch, f.Cause, f.Err)
+}
+
+// Runs a sequence of ops against a VM with the builtin singletons and some funded accounts, checking the state
+// invariants at the end of every epoch, including one after the last op. Returns the first failure, or nil.
+// The test fails, rather than the sequence, only if the VM cannot be set up.
+func Run(t testing.TB, ops []Op) (failure *Failure) {
+	r := newRunner(t)
+	step := 0
+	defer func() {
+		if p := recover(); p != nil {
+			failure = &Failure{Step: step, Epoch: r.v.GetEpoch(), Cause: CausePanic, Err: fmt.Errorf("%v", p)}
+		}
+	}()
+	for ; step < len(ops); step++ {
+		if err := r.step(ops[step]); err != nil {
+			return r.failure(step, err)
+		}
+	}
+	if err := r.advance(1); err != nil {
+		return r.failure(step, err)
+	}
+	return nil
+}
+
+// Runs a sequence and, if it fails, shrinks it to a minimal sequence failing with the same cause and fails the
+// test, reporting the minimal sequence as a Go literal from which to reproduce the failure.
+func Check(t testing.TB, ops []Op) {
+	failure := Run(t, ops)
+	if failure == nil {
+		return
+	}
+	shrunk := Shrink(ops, func(candidate []Op) bool {
+		f := Run(t, candidate)
+		if f != nil && f.Cause == failure.Cause {
+			failure = f
+			return true
+		}
+		return false
+	})
+	t.Fatalf("sequence of %d ops failed, shrunk to %d: %v\n%s", len(ops), len(shrunk), failure, Format(shrunk))
+}
+
+// The parties and objects a sequence has created, from which its ops select those they act on.
+type runner struct {
+	t        testing.TB
+	v        *vm.VM
+	total    abi.TokenAmount
+	accounts []address.Address
+	miners   []*minerModel
+	deals    int
+}
+
+type minerModel struct {
+	owner      address.Address
+	id         address.Address
+	robust     address.Address
+	nextSector abi.SectorNumber
+	deals      []dealModel      // Deals published with the miner as provider, not yet pre-committed in a sector.
+	precommits []precommitModel // Sectors pre-committed, not yet proven.
+	sectors    []abi.SectorNumber
+}
+
+type dealModel struct {
+	id  abi.DealID
+	end abi.ChainEpoch
+}
+
+type precommitModel struct {
+	number abi.SectorNumber
+	epoch  abi.ChainEpoch
+}
+
+// An error ending a sequence, with its cause.
+type stepError struct {
+	cause Cause
+	err   error
+}
+
+func (e *stepError) Error() string {
+	return e.err.Error()
+}
+
+func newRunner(t testing.TB) *runner {
+	ctx := context.Background()
+	v := vm.NewVMWithSingletons(ctx, t)
+	accounts := vm.CreateAccounts(ctx, t, v, accountCount, accountBalance, 93837778)
+	v, err := v.WithEpoch(startEpoch)
+	require.NoError(t, err)
+	return &runner{
+		t:        t,
+		v:        v,
+		total:    vm.TotalBalance(t, v),
+		accounts: accounts,
+	}
+}
+
+// Describes an error returned by a step, all of which are stepErrors.
+func (r *runner) failure(step int, err error) *Failure {
+	se := err.(*stepError)
+	return &Failure{Step: step, Epoch: r.v.GetEpoch(), Cause: se.cause, Err: se.err}
+}
+
+func (r *runner) step(op Op) error {
+	if err := r.advance(op.Wait); err != nil {
+		return err
+	}
+	switch op.Kind {
+	case CreateMiner:
+		return r.createMiner(op)
+	case PublishDeal:
+		return r.publishDeal(op)
+	case PreCommitSector:
+		return r.preCommitSector(op)
+	case ProveCommitSector:
+		return r.proveCommitSector(op)
+	case SubmitWindowedPoSt:
+		return r.submitWindowedPoSt(op)
+	case DeclareFault:
+		return r.declareFault(op)
+	case DeclareRecovery:
+		return r.declareRecovery(op)
+	case WithdrawMarketBalance:
+		return r.withdrawMarketBalance(op)
+	case WithdrawMinerBalance:
+		return r.withdrawMinerBalance(op)
+	default:
+		panic(fmt.Sprintf("unknown op kind %v", op.Kind))
+	}
+}
+
+// Advances epochs one at a time, running cron at the end of each and then checking the state invariants.
+func (r *runner) advance(epochs abi.ChainEpoch) error {
+	for i := abi.ChainEpoch(0); i < epochs; i++ {
+		next, err := r.v.AdvanceToEpoch(r.v.GetEpoch() + 1)
+		if err != nil {
+			return &stepError{CauseCron, err}
+		}
+		r.v = next
+		if err := r.checkInvariants(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *runner) advanceTo(epoch abi.ChainEpoch) error {
+	if epoch <= r.v.GetEpoch() {
+		return nil
+	}
+	return r.advance(epoch - r.v.GetEpoch())
+}
+
+func (r *runner) checkInvariants() error {
+	tree, err := r.v.GetStateTree()
+	require.NoError(r.t, err)
+	acc, err := states.CheckStateInvariants(tree, r.total, r.v.GetEpoch())
+	if err != nil {
+		return &stepError{CauseInvariants, err}
+	}
+	if !acc.IsEmpty() {
+		return &stepError{CauseInvariants, fmt.Errorf("%s", strings.Join(acc.Messages(), "\n"))}
+	}
+	return nil
+}
+
+// Applies a message, returning its return value and whether it succeeded. Plausible messages may fail, but an
+// error is returned if one fails with a code indicating an actor bug rather than an invalid request.
+func (r *runner) apply(from, to address.Address, value abi.TokenAmount, method abi.MethodNum, params interface{}) (cbor.Marshaler, bool, error) {
+	ret, code := r.v.ApplyMessage(from, to, value, method, params)
+	switch code {
+	case exitcode.ErrIllegalState, exitcode.SysErrorIllegalActor, exitcode.SysErrorIllegalArgument:
+		return nil, false, &stepError{CauseExitCode, fmt.Errorf("method %d from %v to %v exited with %v", method, from, to, code)}
+	}
+	return ret, code == exitcode.Ok, nil
+}
+
+func (r *runner) account(i uint8) address.Address {
+	return r.accounts[int(i)%len(r.accounts)]
+}
+
+func (r *runner) miner(i uint8) *minerModel {
+	if len(r.miners) == 0 {
+		return nil
+	}
+	return r.miners[int(i)%len(r.miners)]
+}
+
+func (r *runner) minerState(m *minerModel) *miner.State {
+	var st miner.State
+	require.NoError(r.t, r.v.GetState(m.id, &st))
+	return &st
+}
+
+// Returns an amount of FIL scaled by an op's argument, from one hundredth up.
+func scaledAmount(arg uint8) abi.TokenAmount {
+	return big.Div(big.Mul(big.NewInt(int64(arg)+1), vm.FIL), big.NewInt(100))
+}
+
+func (r *runner) createMiner(op Op) error {
+	if len(r.miners) >= maxMiners {
+		return nil
+	}
+	owner := r.account(op.Party)
+	params := power.CreateMinerParams{
+		Owner:         owner,
+		Worker:        owner,
+		SealProofType: sealProof,
+		Peer:          abi.PeerID(fmt.Sprintf("miner-%d", len(r.miners))),
+	}
+	ret, ok, err := r.apply(owner, builtin.StoragePowerActorAddr, minerBalance, builtin.MethodsPower.CreateMiner, &params)
+	if err != nil || !ok {
+		return err
+	}
+	created := ret.(*power.CreateMinerReturn)
+	r.miners = append(r.miners, &minerModel{
+		owner:      owner,
+		id:         created.IDAddress,
+		robust:     created.RobustAddress,
+		nextSector: 100,
+	})
+	return nil
+}
+
+// Publishes a deal starting once a sector pre-committed now could be proven, after funding both parties' escrow.
+func (r *runner) publishDeal(op Op) error {
+	m := r.miner(op.Party)
+	if m == nil {
+		return nil
+	}
+	client := r.account(op.Arg)
+	start := r.v.GetEpoch() + miner.MaxProveCommitDuration[sealProof]
+	end := start + market.DealMinDuration + abi.ChainEpoch(op.Arg)
+	r.deals++
+	proposal := fixtures.NewDealBuilder(client, m.id, start, end).
+		WithPiece(fmt.Sprintf("deal-%d", r.deals), pieceSize).
+		WithCollateral(vm.FIL, big.Zero()).
+		Build()
+
+	if _, _, err := r.apply(client, builtin.StorageMarketActorAddr, proposal.ClientBalanceRequirement(), builtin.MethodsMarket.AddBalance, &client); err != nil {
+		return err
+	}
+	if _, _, err := r.apply(m.owner, builtin.StorageMarketActorAddr, proposal.ProviderBalanceRequirement(), builtin.MethodsMarket.AddBalance, &m.id); err != nil {
+		return err
+	}
+	params := market.PublishStorageDealsParams{
+		Deals: []market.ClientDealProposal{{Proposal: proposal, ClientSignature: crypto.Signature{Type: crypto.SigTypeBLS}}},
+	}
+	ret, ok, err := r.apply(m.owner, builtin.StorageMarketActorAddr, big.Zero(), builtin.MethodsMarket.PublishStorageDeals, &params)
+	if err != nil || !ok {
+		return err
+	}
+	for _, id := range ret.(*market.PublishStorageDealsReturn).IDs {
+		m.deals = append(m.deals, dealModel{id: id, end: end})
+	}
+	return nil
+}
+
+// Pre-commits a sector, with the miner's oldest unsealed deal if the argument is odd.
+func (r *runner) preCommitSector(op Op) error {
+	m := r.miner(op.Party)
+	if m == nil {
+		return nil
+	}
+	epoch := r.v.GetEpoch()
+	expiration := epoch + miner.MinSectorExpiration + miner.MaxProveCommitDuration[sealProof] + abi.ChainEpoch(op.Arg)
+	var dealIDs []abi.DealID
+	if op.Arg%2 == 1 && len(m.deals) > 0 {
+		deal := m.deals[0]
+		m.deals = m.deals[1:]
+		dealIDs = append(dealIDs, deal.id)
+		if deal.end > expiration {
+			expiration = deal.end
+		}
+	}
+	number := m.nextSector
+	m.nextSector++
+	params := miner.PreCommitSectorParams{
+		SealProof:     sealProof,
+		SectorNumber:  number,
+		SealedCID:     tutil.MakeCID(fmt.Sprintf("%v-%d", m.id, number), &miner.SealedCIDPrefix),
+		SealRandEpoch: epoch - 1,
+		DealIDs:       dealIDs,
+		Expiration:    expiration,
+	}
+	_, ok, err := r.apply(m.owner, m.robust, big.Zero(), builtin.MethodsMiner.PreCommitSector, &params)
+	if err != nil || !ok {
+		return err
+	}
+	m.precommits = append(m.precommits, precommitModel{number: number, epoch: epoch})
+	return nil
+}
+
+// Proves a pre-committed sector, first advancing past the challenge delay if it has not elapsed. The proof is
+// confirmed by cron at the end of the epoch.
+func (r *runner) proveCommitSector(op Op) error {
+	m := r.miner(op.Party)
+	if m == nil || len(m.precommits) == 0 {
+		return nil
+	}
+	i := int(op.Arg) % len(m.precommits)
+	precommit := m.precommits[i]
+	m.precommits = append(m.precommits[:i], m.precommits[i+1:]...)
+	if err := r.advanceTo(precommit.epoch + miner.PreCommitChallengeDelay + 1); err != nil {
+		return err
+	}
+	params := miner.ProveCommitSectorParams{SectorNumber: precommit.number}
+	_, ok, err := r.apply(m.owner, m.robust, big.Zero(), builtin.MethodsMiner.ProveCommitSector, &params)
+	if err != nil || !ok {
+		return err
+	}
+	m.sectors = append(m.sectors, precommit.number)
+	return nil
+}
+
+// Proves all partitions of the miner's next deadline with live sectors, advancing to it if it is not current.
+func (r *runner) submitWindowedPoSt(op Op) error {
+	m := r.miner(op.Party)
+	if m == nil {
+		return nil
+	}
+	st := r.minerState(m)
+	dlInfo := st.DeadlineInfo(r.v.GetEpoch()).NextNotElapsed()
+	deadlines, err := st.LoadDeadlines(r.v.Store())
+	require.NoError(r.t, err)
+	for i := uint64(0); i < miner.WPoStPeriodDeadlines; i++ {
+		dlIdx := (dlInfo.Index + i) % miner.WPoStPeriodDeadlines
+		dl, err := deadlines.LoadDeadline(r.v.Store(), dlIdx)
+		require.NoError(r.t, err)
+		if dl.LiveSectors == 0 {
+			continue
+		}
+		if err := r.advanceTo(dlInfo.Open + abi.ChainEpoch(i)*miner.WPoStChallengeWindow); err != nil {
+			return err
+		}
+		return r.proveDeadline(m)
+	}
+	return nil
+}
+
+func (r *runner) proveDeadline(m *minerModel) error {
+	st := r.minerState(m)
+	dlInfo := st.DeadlineInfo(r.v.GetEpoch())
+	deadlines, err := st.LoadDeadlines(r.v.Store())
+	require.NoError(r.t, err)
+	dl, err := deadlines.LoadDeadline(r.v.Store(), dlInfo.Index)
+	require.NoError(r.t, err)
+	partitions, err := adt.AsArray(r.v.Store(), dl.Partitions)
+	require.NoError(r.t, err)
+	if partitions.Length() == 0 {
+		return nil
+	}
+	params := miner.SubmitWindowedPoStParams{
+		Deadline:         dlInfo.Index,
+		Proofs:           []proof.PoStProof{{PoStProof: postProof}},
+		ChainCommitEpoch: dlInfo.Challenge,
+		ChainCommitRand:  chainRand,
+	}
+	for pIdx := uint64(0); pIdx < partitions.Length(); pIdx++ {
+		params.Partitions = append(params.Partitions, miner.PoStPartition{Index: pIdx, Skipped: bitfield.New()})
+	}
+	_, _, err = r.apply(m.owner, m.robust, big.Zero(), builtin.MethodsMiner.SubmitWindowedPoSt, &params)
+	return err
+}
+
+// Returns the location of a proven sector selected by an op's argument, if it has one.
+func (r *runner) locateSector(m *minerModel, arg uint8) (abi.SectorNumber, uint64, uint64, bool) {
+	if len(m.sectors) == 0 {
+		return 0, 0, 0, false
+	}
+	number := m.sectors[int(arg)%len(m.sectors)]
+	dlIdx, pIdx, err := r.minerState(m).FindSector(r.v.Store(), number)
+	if err != nil {
+		// Not yet confirmed, or confirmation failed, or terminated.
+		return 0, 0, 0, false
+	}
+	return number, dlIdx, pIdx, true
+}
+
+func (r *runner) declareFault(op Op) error {
+	m := r.miner(op.Party)
+	if m == nil {
+		return nil
+	}
+	number, dlIdx, pIdx, ok := r.locateSector(m, op.Arg)
+	if !ok {
+		return nil
+	}
+	params := miner.DeclareFaultsParams{Faults: []miner.FaultDeclaration{{
+		Deadline:  dlIdx,
+		Partition: pIdx,
+		Sectors:   bitfield.NewFromSet([]uint64{uint64(number)}),
+	}}}
+	_, _, err := r.apply(m.owner, m.robust, big.Zero(), builtin.MethodsMiner.DeclareFaults, &params)
+	return err
+}
+
+func (r *runner) declareRecovery(op Op) error {
+	m := r.miner(op.Party)
+	if m == nil {
+		return nil
+	}
+	number, dlIdx, pIdx, ok := r.locateSector(m, op.Arg)
+	if !ok {
+		return nil
+	}
+	params := miner.DeclareFaultsRecoveredParams{Recoveries: []miner.RecoveryDeclaration{{
+		Deadline:  dlIdx,
+		Partition: pIdx,
+		Sectors:   bitfield.NewFromSet([]uint64{uint64(number)}),
+	}}}
+	_, _, err := r.apply(m.owner, m.robust, big.Zero(), builtin.MethodsMiner.DeclareFaultsRecovered, &params)
+	return err
+}
+
+// Withdraws from the market escrow of an account if the argument is even, else of a miner, by its owner.
+func (r *runner) withdrawMarketBalance(op Op) error {
+	caller, escrow := r.account(op.Party), r.account(op.Party)
+	if op.Arg%2 == 1 {
+		m := r.miner(op.Party)
+		if m == nil {
+			return nil
+		}
+		caller, escrow = m.owner, m.id
+	}
+	params := market.WithdrawBalanceParams{
+		ProviderOrClientAddress: escrow,
+		Amount:                  scaledAmount(op.Arg),
+	}
+	_, _, err := r.apply(caller, builtin.StorageMarketActorAddr, big.Zero(), builtin.MethodsMarket.WithdrawBalance, &params)
+	return err
+}
+
+func (r *runner) withdrawMinerBalance(op Op) error {
+	m := r.miner(op.Party)
+	if m == nil {
+		return nil
+	}
+	params := miner.WithdrawBalanceParams{AmountRequested: big.Mul(scaledAmount(op.Arg), big.NewInt(100))}
+	_, _, err := r.apply(m.owner, m.robust, big.Zero(), builtin.MethodsMiner.WithdrawBalance, &params)
+	return err
+}
@@ -0,0 +1,78 @@
+package fuzz
+
+// Shrinks a failing sequence to a smaller one which still fails, as judged by the fails predicate.
+// It first removes runs of ops, halving the run length down to single ops, and then simplifies the ops which
+// remain, zeroing or halving their waits and zeroing their selectors, repeating both until neither shrinks the
+// sequence further. The result is minimal in that removing any one op, or simplifying any one field, no longer
+// fails, though the predicate may be run many times to find it.
+func Shrink(ops []Op, fails func([]Op) bool) []Op {
+	ops = append([]Op(nil), ops...)
+	for {
+		removed := removeOps(ops, fails)
+		simplified, changed := simplifyOps(removed, fails)
+		if !changed && len(removed) == len(ops) {
+			return simplified
+		}
+		ops = simplified
+	}
+}
+
+// Removes runs of ops, halving the length of the runs tried until single ops are tried.
+func removeOps(ops []Op, fails func([]Op) bool) []Op {
+	for n := len(ops) / 2; n >= 1; {
+		removed := false
+		for i := 0; i+n <= len(ops); {
+			candidate := append(append([]Op(nil), ops[:i]...), ops[i+n:]...)
+			if fails(candidate) {
+				ops = candidate
+				removed = true
+			} else {
+				i += n
+			}
+		}
+		if !removed {
+			n /= 2
+		}
+		if n > len(ops) {
+			n = len(ops)
+		}
+	}
+	return ops
+}
+
+// Simplifies each op in turn, keeping the simplifications with which the sequence still fails.
+func simplifyOps(ops []Op, fails func([]Op) bool) ([]Op, bool) {
+	changed := false
+	try := func(i int, op Op) bool {
+		if op == ops[i] {
+			return false
+		}
+		candidate := append([]Op(nil), ops...)
+		candidate[i] = op
+		if !fails(candidate) {
+			return false
+		}
+		ops = candidate
+		changed = true
+		return true
+	}
+	for i := range ops {
+		op := ops[i]
+		op.Wait = 0
+		if !try(i, op) {
+			for op = ops[i]; op.Wait > 1; op = ops[i] {
+				op.Wait /= 2
+				if !try(i, op) {
+					break
+				}
+			}
+		}
+		op = ops[i]
+		op.Party = 0
+		try(i, op)
+		op = ops[i]
+		op.Arg = 0
+		try(i, op)
+	}
+	return ops, changed
+}
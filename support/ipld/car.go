@@ -0,0 +1,132 @@
+package ipld
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	block "github.com/ipfs/go-block-format"
+	"github.com/ipfs/go-cid"
+	cbor "github.com/ipfs/go-ipld-cbor"
+	mh "github.com/multiformats/go-multihash"
+	cbg "github.com/whyrusleeping/cbor-gen"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/util/adt"
+)
+
+// The header of a CAR (content addressable archive), version 1.
+type carHeader struct {
+	Roots   []cid.Cid `refmt:"roots"`
+	Version uint64    `refmt:"version"`
+}
+
+func init() {
+	cbor.RegisterCborType(carHeader{})
+}
+
+// Reads the blocks of a CAR (version 1) into a store, returning the archive's roots.
+// Each block is checked against its CID as stored, so must be DAG-CBOR hashed with blake2b-256, as are all
+// state objects.
+func ReadCAR(store adt.Store, r io.Reader) ([]cid.Cid, error) {
+	br := bufio.NewReader(r)
+	headerBytes, err := readCARSection(br)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CAR header: %w", err)
+	}
+	var header carHeader
+	if err := cbor.DecodeInto(headerBytes, &header); err != nil {
+		return nil, fmt.Errorf("failed to decode CAR header: %w", err)
+	}
+	if header.Version != 1 {
+		return nil, fmt.Errorf("unsupported CAR version %d", header.Version)
+	}
+
+	for {
+		section, err := readCARSection(br)
+		if err == io.EOF {
+			return header.Roots, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read CAR block: %w", err)
+		}
+		n, c, err := cid.CidFromBytes(section)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CAR block CID: %w", err)
+		}
+		stored, err := store.Put(store.Context(), &cbg.Deferred{Raw: section[n:]})
+		if err != nil {
+			return nil, fmt.Errorf("failed to store block %v: %w", c, err)
+		}
+		if !stored.Equals(c) {
+			return nil, fmt.Errorf("block %v stored as %v, only blake2b-256 DAG-CBOR blocks are supported", c, stored)
+		}
+	}
+}
+
+// Writes the DAG under a root from a store to a CAR (version 1) with that root.
+// Links to DAG-CBOR blocks are followed; identity CIDs, which embed their content, and links with other codecs,
+// such as actor code CIDs, are not.
+func WriteCAR(store adt.Store, w io.Writer, root cid.Cid) error {
+	headerBytes, err := cbor.DumpObject(&carHeader{Roots: []cid.Cid{root}, Version: 1})
+	if err != nil {
+		return fmt.Errorf("failed to encode CAR header: %w", err)
+	}
+	if err := writeCARSection(w, headerBytes); err != nil {
+		return err
+	}
+
+	seen := map[cid.Cid]struct{}{}
+	var walk func(c cid.Cid) error
+	walk = func(c cid.Cid) error {
+		if _, ok := seen[c]; ok || c.Type() != cid.DagCBOR || c.Prefix().MhType == mh.IDENTITY {
+			return nil
+		}
+		seen[c] = struct{}{}
+
+		var raw cbg.Deferred
+		if err := store.Get(store.Context(), c, &raw); err != nil {
+			return fmt.Errorf("failed to load block %v: %w", c, err)
+		}
+		if err := writeCARSection(w, append(c.Bytes(), raw.Raw...)); err != nil {
+			return err
+		}
+		blk, err := block.NewBlockWithCid(raw.Raw, c)
+		if err != nil {
+			return err
+		}
+		node, err := cbor.DecodeBlock(blk)
+		if err != nil {
+			return fmt.Errorf("failed to decode block %v: %w", c, err)
+		}
+		for _, link := range node.Links() {
+			if err := walk(link.Cid); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return walk(root)
+}
+
+// Reads a section, prefixed by its length as an unsigned varint.
+func readCARSection(br *bufio.Reader) ([]byte, error) {
+	length, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, err
+	}
+	section := make([]byte, length)
+	if _, err := io.ReadFull(br, section); err != nil {
+		return nil, err
+	}
+	return section, nil
+}
+
+func writeCARSection(w io.Writer, section []byte) error {
+	var buf bytes.Buffer
+	lengthBytes := make([]byte, binary.MaxVarintLen64)
+	buf.Write(lengthBytes[:binary.PutUvarint(lengthBytes, uint64(len(section)))])
+	buf.Write(section)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
@@ -2,6 +2,7 @@ package mock
 
 import (
 	"context"
+	"math"
 	"testing"
 
 	addr "github.com/filecoin-project/go-address"
@@ -35,23 +36,24 @@ func NewBuilder(ctx context.Context, receiver addr.Address) *RuntimeBuilder {
 
 		balance:       abi.NewTokenAmount(0),
 		valueReceived: abi.NewTokenAmount(0),
+		gasLimit:      math.MaxInt64,
+		gas:           PriceList{},
 
 		actorCodeCIDs: make(map[addr.Address]cid.Cid),
 		newActorAddr:  addr.Undef,
 
-		t:                        nil, // Initialized at Build()
-		expectValidateCallerAny:  false,
-		expectValidateCallerAddr: nil,
-		expectValidateCallerType: nil,
-		expectCreateActor:        nil,
-
-		expectSends:      make([]*expectedMessage, 0),
-		expectVerifySigs: make([]*expectVerifySig, 0),
+		t: nil, // Initialized at Build()
+		expectations: expectations{
+			expectSends:      make([]*expectedMessage, 0),
+			expectVerifySigs: make([]*expectVerifySig, 0),
+		},
 	}
 	return &RuntimeBuilder{m}
 }
 
 // Builds a new runtime object with the configured values.
+// The runtime shares no mutable values with the builder or other runtimes built from it, so a builder may be
+// shared by parallel tests, and configured further after building without affecting the runtimes already built.
 func (b *RuntimeBuilder) Build(t testing.TB) *Runtime {
 	cpy := *b.rt
 
@@ -60,6 +62,12 @@ func (b *RuntimeBuilder) Build(t testing.TB) *Runtime {
 	for k, v := range b.rt.store { //nolint:nomaprange
 		cpy.store[k] = v
 	}
+	cpy.idAddresses = copyAddressMap(b.rt.idAddresses)
+	cpy.actorCodeCIDs = copyCodeMap(b.rt.actorCodeCIDs)
+	cpy.gasByMethod = copyGasMap(b.rt.gasByMethod)
+	cpy.batchLimits = copyBatchLimits(b.rt.batchLimits)
+	cpy.expectSends = make([]*expectedMessage, 0)
+	cpy.expectVerifySigs = make([]*expectVerifySig, 0)
 
 	cpy.t = t
 	return &cpy
@@ -92,6 +100,42 @@ func (b *RuntimeBuilder) WithActorType(addr addr.Address, code cid.Cid) *Runtime
 	return b
 }
 
+// Configures the prices charged for operations performed by calls. See DefaultPriceList.
+func (b *RuntimeBuilder) WithPriceList(prices PriceList) *RuntimeBuilder {
+	b.rt.gas = prices
+	return b
+}
+
+// Configures the charger pricing operations performed by calls.
+func (b *RuntimeBuilder) WithGasCharger(gas GasCharger) *RuntimeBuilder {
+	b.rt.gas = gas
+	return b
+}
+
+// Checks state invariants after every call. See Runtime.SetInvariantCheck.
+func (b *RuntimeBuilder) WithInvariantCheck(check InvariantCheck) *RuntimeBuilder {
+	b.rt.invariantCheck = check
+	return b
+}
+
+// Checks state invariants after every call with an actor package's CheckStateInvariants function. See
+// StateInvariants.
+func (b *RuntimeBuilder) WithStateInvariants(checkStateInvariants interface{}) *RuntimeBuilder {
+	return b.WithInvariantCheck(StateInvariants(checkStateInvariants))
+}
+
+// Programs randomness as a function of the request. See Runtime.SetRandomness.
+func (b *RuntimeBuilder) WithRandomness(f RandomnessFunc) *RuntimeBuilder {
+	b.rt.randomness = f
+	return b
+}
+
+// Enables tracing of calls. See Runtime.SetTracing.
+func (b *RuntimeBuilder) WithTracing() *RuntimeBuilder {
+	b.rt.tracing = true
+	return b
+}
+
 func (b *RuntimeBuilder) WithHasher(f func(data []byte) [32]byte) *RuntimeBuilder {
 	b.rt.hashfunc = f
 	return b
@@ -0,0 +1,232 @@
+package mock
+
+import (
+	"bytes"
+	"reflect"
+
+	addr "github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/cbor"
+	"github.com/filecoin-project/go-state-types/exitcode"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v2/actors/runtime"
+)
+
+// An actor to which sends are dispatched, with the runtime on which its methods are invoked.
+type dispatchTarget struct {
+	rt    *Runtime
+	actor runtime.VMActor
+}
+
+// The changes made by invocations of actors to which sends are dispatched, so that a failed send can undo the
+// changes made within it to every actor it reached, including the sender's own, when the callee calls back to it.
+type dispatchJournal struct {
+	undo  []func()
+	sends int // Number of dispatched sends in progress.
+}
+
+// Records how to undo a change made by an invocation.
+func (j *dispatchJournal) record(undo func()) {
+	if j.sends > 0 {
+		j.undo = append(j.undo, undo)
+	}
+}
+
+// Undoes the changes recorded since the mark, latest first.
+func (j *dispatchJournal) revert(mark int) {
+	for i := len(j.undo) - 1; i >= mark; i-- {
+		j.undo[i]()
+	}
+	j.undo = j.undo[:mark]
+}
+
+// Dispatches sends to the callee runtime's receiver to the actor's methods, invoked on the callee runtime, in place
+// of matching them against expected sends. The send returns the method's return value and exit code, and an abort
+// rolls back the changes made within the send, to this actor and the callee alike. The callee's own expectations, e.g. of the caller validation and sends
+// of the methods invoked, are set and verified on the callee runtime, which must know the code of this runtime's
+// receiver (see SetAddressActorType).
+// The callee may in turn dispatch sends back to this runtime, re-entering its actor while a call is in progress, as
+// expected with ExpectNestedCall.
+func (rt *Runtime) DispatchSendsTo(callee *Runtime, actor runtime.VMActor) {
+	if rt.dispatch == nil {
+		rt.dispatch = make(map[addr.Address]*dispatchTarget)
+	}
+	if rt.journal == nil {
+		rt.journal = callee.journal
+	}
+	if rt.journal == nil {
+		rt.journal = &dispatchJournal{}
+	}
+	if callee.journal == nil {
+		callee.journal = rt.journal
+	}
+	rt.require(callee.journal == rt.journal, "cannot dispatch between runtimes dispatching to distinct others")
+	rt.dispatch[callee.receiver] = &dispatchTarget{rt: callee, actor: actor}
+	rt.actorCodeCIDs[callee.receiver] = actor.Code()
+}
+
+// Expects the actor to be invoked again while a call is in progress, e.g. by a callee to which a send is dispatched
+// calling back to it. The expectations set by expect() apply to the nested invocation alone, and are verified when it
+// returns, after which those of the enclosing invocation are restored. Nested invocations are expected in order, and
+// expect() may itself expect invocations nested further.
+func (rt *Runtime) ExpectNestedCall(expect func()) {
+	rt.expectNestedCalls = append(rt.expectNestedCalls, expect)
+}
+
+// Sends a message to an actor registered with DispatchSendsTo.
+func (rt *Runtime) dispatchSend(target *dispatchTarget, methodNum abi.MethodNum, params cbor.Marshaler, value abi.TokenAmount, out cbor.Er, readOnly bool) exitcode.ExitCode {
+	rt.charge(rt.gas.OnSend(!value.IsZero()))
+	if value.GreaterThan(rt.balance) {
+		rt.Abortf(exitcode.SysErrSenderStateInvalid, "cannot send value: %v exceeds balance: %v", value, rt.balance)
+	}
+
+	// Debit the value first, as the callee may observe this actor's balance by calling back to it.
+	mark := len(rt.journal.undo)
+	rt.journal.sends++
+	rt.balance = big.Sub(rt.balance, value)
+	ret, code := target.rt.invoke(rt.receiver, target.actor, methodNum, params, value, readOnly)
+	rt.journal.sends--
+	if !code.IsSuccess() {
+		rt.journal.revert(mark)
+		rt.balance = big.Add(rt.balance, value)
+		return code
+	}
+	if rt.journal.sends == 0 {
+		rt.journal.undo = nil
+	}
+	rt.recordTransfer(rt.receiver, target.rt.receiver, value)
+	if err := out.UnmarshalCBOR(bytes.NewReader(ret)); err != nil {
+		rt.failTestNow("error deserializing return of method %d of %v: %v", methodNum, target.rt.receiver, err)
+	}
+	return code
+}
+
+// Invokes a method of the actor on this runtime as if sent by another actor, returning its serialized return value
+// and exit code. An abort rolls back the invocation's changes to state, balance and transfers.
+// If a call is already in progress, the invocation is nested within it, with the expectations of the next nested
+// call expected.
+func (rt *Runtime) invoke(from addr.Address, actor runtime.VMActor, methodNum abi.MethodNum, params cbor.Marshaler, value abi.TokenAmount, readOnly bool) ([]byte, exitcode.ExitCode) {
+	rt.t.Helper()
+	nested := rt.inCall
+	fromCode, ok := rt.actorCodeCIDs[from]
+	rt.require(ok, "invocation of %v by %v, whose code is unknown", rt.receiver, from)
+
+	// A top-level invocation's transfers are committed when it returns, so only a nested one has prior call transfers.
+	stateBefore, balanceBefore, transfersBefore := rt.state, rt.balance, len(rt.transfers)
+	callTransfersBefore := 0
+	if nested {
+		callTransfersBefore = len(rt.callTransfers)
+	}
+	undo := func() {
+		rt.state, rt.balance = stateBefore, balanceBefore
+		rt.callTransfers = rt.callTransfers[:callTransfersBefore]
+		rt.transfers = rt.transfers[:transfersBefore]
+	}
+
+	if methodNum == builtin.MethodSend {
+		// A bare transfer of value invokes no method.
+		rt.journal.record(undo)
+		rt.balance = big.Add(rt.balance, value)
+		if nested {
+			rt.recordTransfer(from, rt.receiver, value)
+		} else if !value.NilOrZero() {
+			rt.transfers = append(rt.transfers, Transfer{From: from, To: rt.receiver, Amount: value})
+		}
+		return nil, exitcode.Ok
+	}
+	exports := actor.Exports()
+	if int(methodNum) >= len(exports) || exports[methodNum] == nil {
+		return nil, exitcode.SysErrInvalidMethod
+	}
+	method := reflect.ValueOf(exports[methodNum])
+	rt.verifyExportedMethodType(method)
+	arg, err := decodeParams(method.Type().In(1), params)
+	if err != nil {
+		return nil, exitcode.ErrSerialization
+	}
+
+	// Save the context of the call in progress, if any, and start the invocation.
+	caller, callerType, received, readOnlyBefore := rt.caller, rt.callerType, rt.valueReceived, rt.readOnly
+	var enclosing expectations
+	if nested {
+		if len(rt.expectNestedCalls) == 0 {
+			rt.failTestNow("unexpected nested call to method %d of %v by %v", methodNum, rt.receiver, from)
+		}
+		expect := rt.expectNestedCalls[0]
+		rt.expectNestedCalls = rt.expectNestedCalls[1:]
+		enclosing = rt.expectations
+		rt.expectations = expectations{}
+		expect()
+	} else {
+		rt.events = nil
+		rt.traceEntries = nil
+		rt.callTransfers = nil
+		rt.deleted = false
+	}
+	rt.caller, rt.callerType, rt.valueReceived = from, fromCode, value
+	rt.readOnly = readOnlyBefore || readOnly
+	rt.balance = big.Add(rt.balance, value)
+	rt.inCall = true
+
+	ret, code := callRecoveringAbort(rt, method, arg)
+
+	if code.IsSuccess() {
+		if nested {
+			rt.recordTransfer(from, rt.receiver, value)
+		} else {
+			rt.commitTransfers()
+		}
+		rt.journal.record(undo)
+	} else {
+		undo()
+	}
+	rt.caller, rt.callerType, rt.valueReceived, rt.readOnly = caller, callerType, received, readOnlyBefore
+	rt.inCall = nested
+	if nested {
+		rt.verifyExpectations()
+		rt.expectations = enclosing
+	} else if code.IsSuccess() {
+		rt.checkInvariants()
+	}
+	return ret, code
+}
+
+// Calls an exported method, returning its serialized return value, or the exit code with which it aborted.
+func callRecoveringAbort(rt *Runtime, method reflect.Value, arg reflect.Value) (ret []byte, code exitcode.ExitCode) {
+	defer func() {
+		if r := recover(); r != nil {
+			a, ok := r.(abort)
+			if !ok {
+				panic(r)
+			}
+			ret, code = nil, a.code
+		}
+	}()
+	out := method.Call([]reflect.Value{reflect.ValueOf(rt), arg})
+	var buf bytes.Buffer
+	if m, ok := out[0].Interface().(cbor.Marshaler); ok {
+		if err := m.MarshalCBOR(&buf); err != nil {
+			rt.failTestNow("error serializing return value %v: %v", m, err)
+		}
+	}
+	return buf.Bytes(), exitcode.Ok
+}
+
+// Decodes params sent by one actor into a new value of the params type of another's method, as a nil pointer if
+// there are none, so that the actors share no objects.
+func decodeParams(typ reflect.Type, params cbor.Marshaler) (reflect.Value, error) {
+	if params == nil {
+		return reflect.New(typ).Elem(), nil
+	}
+	var buf bytes.Buffer
+	if err := params.MarshalCBOR(&buf); err != nil {
+		return reflect.Value{}, err
+	}
+	arg := reflect.New(typ.Elem())
+	if err := arg.Interface().(cbor.Unmarshaler).UnmarshalCBOR(&buf); err != nil {
+		return reflect.Value{}, err
+	}
+	return arg, nil
+}
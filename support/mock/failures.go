@@ -0,0 +1,91 @@
+package mock
+
+import (
+	"fmt"
+)
+
+// A syscall, priced by a GasCharger. Failures may be injected for those up to SyscallVerifyConsensusFault.
+type Syscall int
+
+const (
+	SyscallVerifySeal Syscall = iota
+	SyscallBatchVerifySeals
+	SyscallBatchVerifyPoSts
+	SyscallComputeUnsealedSectorCID
+	SyscallVerifyConsensusFault
+	SyscallVerifySignature
+	SyscallHashBlake2b
+	SyscallVerifyAggregateSeals
+	SyscallVerifyReplicaUpdate
+	SyscallVerifyPoSt
+	SyscallVerifyAggregateSignature
+	numSyscalls
+)
+
+func (s Syscall) String() string {
+	switch s {
+	case SyscallVerifySeal:
+		return "VerifySeal"
+	case SyscallBatchVerifySeals:
+		return "BatchVerifySeals"
+	case SyscallBatchVerifyPoSts:
+		return "BatchVerifyPoSts"
+	case SyscallComputeUnsealedSectorCID:
+		return "ComputeUnsealedSectorCID"
+	case SyscallVerifyConsensusFault:
+		return "VerifyConsensusFault"
+	case SyscallVerifySignature:
+		return "VerifySignature"
+	case SyscallHashBlake2b:
+		return "HashBlake2b"
+	case SyscallVerifyAggregateSeals:
+		return "VerifyAggregateSeals"
+	case SyscallVerifyReplicaUpdate:
+		return "VerifyReplicaUpdate"
+	case SyscallVerifyPoSt:
+		return "VerifyPoSt"
+	case SyscallVerifyAggregateSignature:
+		return "VerifyAggregateSignature"
+	default:
+		return fmt.Sprintf("Syscall(%d)", int(s))
+	}
+}
+
+type injectedFailure struct {
+	syscall Syscall
+	// The call to fail, numbered by the count of calls to the syscall made by the runtime.
+	call int
+	err  error
+}
+
+func (f *injectedFailure) String() string {
+	return fmt.Sprintf("%v call %d: %v", f.syscall, f.call, f.err)
+}
+
+// Makes the nth subsequent call to a syscall, counting from one, return err in place of its result.
+// The failing call needs no expectation, and its arguments are not checked. Calls before it are matched
+// with expectations as usual. Verify fails if the call is not made.
+// Tests may thus fail a syscall part-way through a sequence of calls, or an actor method that makes
+// several, to exercise the actor's handling of the error.
+func (rt *Runtime) FailSyscall(syscall Syscall, n int, err error) {
+	rt.require(syscall >= 0 && syscall <= SyscallVerifyConsensusFault, "cannot inject failures into syscall %v", syscall)
+	rt.require(n > 0, "call number %d must be positive", n)
+	rt.require(err != nil, "injected failure must have an error")
+	rt.injectedFailures = append(rt.injectedFailures, &injectedFailure{
+		syscall: syscall,
+		call:    rt.syscallCalls[syscall] + n,
+		err:     err,
+	})
+}
+
+// Counts a call to a syscall, returning the error injected for it, if any.
+func (rt *Runtime) injectedFailure(syscall Syscall) error {
+	rt.syscallCalls[syscall]++
+	for i, f := range rt.injectedFailures {
+		if f.syscall == syscall && f.call == rt.syscallCalls[syscall] {
+			rt.injectedFailures = append(rt.injectedFailures[:i:i], rt.injectedFailures[i+1:]...)
+			return f.err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+package mock
+
+import (
+	"reflect"
+	goruntime "runtime"
+	"strings"
+
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/filecoin-project/go-state-types/network"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/util"
+)
+
+// Prices the operations an actor performs during a call, for the mock runtime and the test VM to charge.
+// Implementations may price operations by any measure of their cost, e.g. to count store operations, or to
+// approximate a proposed change to the network's prices.
+type GasCharger interface {
+	// Prices reading a block of `size` bytes from the store, whether or not it is found.
+	OnStoreGet(size int) int64
+	// Prices writing a block of `size` bytes to the store.
+	OnStorePut(size int) int64
+	// Prices a send from the actor, which transfers value if `transfer` is set.
+	OnSend(transfer bool) int64
+	// Prices a syscall. Items is the number of seals verified by VerifySeal (1), BatchVerifySeals and
+	// VerifyAggregateSeals, the number of sectors challenged by VerifyPoSt and, across all proofs, by
+	// BatchVerifyPoSts, and otherwise 1.
+	OnSyscall(call Syscall, items int) int64
+}
+
+// Gas prices charged for the operations an actor performs during a call, as a GasCharger.
+// State reads and writes are charged as the store operations that implement them, so loading and flushing
+// HAMTs and AMTs is charged per node.
+// The zero price list charges nothing, leaving only the explicit charges an actor makes with ChargeGas.
+type PriceList struct {
+	StoreGetBase    int64
+	StoreGetPerByte int64
+	StorePutBase    int64
+	StorePutPerByte int64
+
+	SendBase          int64
+	SendTransferFunds int64 // Charged in addition to the base for a send carrying value.
+
+	VerifySignature          int64
+	VerifyAggregateSigBase   int64
+	VerifyAggregateSigPer    int64 // Charged per signer in an aggregate, in addition to the base.
+	HashBlake2b              int64
+	ComputeUnsealedSectorCID int64
+	VerifySeal               int64 // Charged per seal, including each seal in a batch.
+	VerifyAggregateSealsBase int64
+	VerifyAggregateSealsPer  int64 // Charged per seal in an aggregate, in addition to the base.
+	VerifyReplicaUpdate      int64
+	VerifyPoStBase           int64
+	VerifyPoStPerSector      int64
+	VerifyConsensusFault     int64
+}
+
+// A price list approximating that of the network, for tests that assert on the gas consumed by actor methods.
+var DefaultPriceList = PriceList{
+	StoreGetBase:    114617,
+	StoreGetPerByte: 0,
+	StorePutBase:    353640,
+	StorePutPerByte: 1300,
+
+	SendBase:          29233,
+	SendTransferFunds: 27500,
+
+	VerifySignature:          16598605, // BLS, the more expensive signature type.
+	VerifyAggregateSigBase:   8299302,  // One pairing for the aggregate, and one for each signed plaintext.
+	VerifyAggregateSigPer:    8299302,
+	HashBlake2b:              31355,
+	ComputeUnsealedSectorCID: 98647,
+	VerifySeal:               2000, // Seals are verified in a batch, outside message execution.
+	VerifyAggregateSealsBase: 449900,
+	VerifyAggregateSealsPer:  103994170,
+	VerifyReplicaUpdate:      36316136,
+	VerifyPoStBase:           123861062,
+	VerifyPoStPerSector:      9226981,
+	VerifyConsensusFault:     495422,
+}
+
+var _ GasCharger = PriceList{}
+
+// A price list in effect from a network version.
+type ScheduledPriceList struct {
+	Version network.Version
+	Prices  PriceList
+}
+
+// Price lists in order of the network versions from which they are in effect, each until the next, so that tests
+// may charge the prices of the network version at which messages execute.
+type PriceSchedule []ScheduledPriceList
+
+// A schedule of the default price list at every network version.
+var DefaultPriceSchedule = PriceSchedule{{Version: network.Version0, Prices: DefaultPriceList}}
+
+// Returns the price list in effect at a network version.
+// Versions before the first scheduled are given the first price list.
+func (s PriceSchedule) ForVersion(nv network.Version) PriceList {
+	return s[util.VersionIndex(s.versions(), nv)].Prices
+}
+
+// Checks that the schedule is non-empty and in increasing order of version.
+func (s PriceSchedule) Validate() error {
+	if err := util.ValidateVersions(s.versions()); err != nil {
+		return xerrors.Errorf("price schedule: %w", err)
+	}
+	return nil
+}
+
+func (s PriceSchedule) versions() []network.Version {
+	versions := make([]network.Version, len(s))
+	for i, scheduled := range s {
+		versions[i] = scheduled.Version
+	}
+	return versions
+}
+
+func (p PriceList) OnStoreGet(size int) int64 {
+	return p.StoreGetBase + p.StoreGetPerByte*int64(size)
+}
+
+func (p PriceList) OnStorePut(size int) int64 {
+	return p.StorePutBase + p.StorePutPerByte*int64(size)
+}
+
+func (p PriceList) OnSend(transfer bool) int64 {
+	if transfer {
+		return p.SendBase + p.SendTransferFunds
+	}
+	return p.SendBase
+}
+
+func (p PriceList) OnSyscall(call Syscall, items int) int64 {
+	n := int64(items)
+	switch call {
+	case SyscallVerifySignature:
+		return p.VerifySignature
+	case SyscallVerifyAggregateSignature:
+		return p.VerifyAggregateSigBase + p.VerifyAggregateSigPer*n
+	case SyscallHashBlake2b:
+		return p.HashBlake2b
+	case SyscallComputeUnsealedSectorCID:
+		return p.ComputeUnsealedSectorCID
+	case SyscallVerifySeal, SyscallBatchVerifySeals:
+		return p.VerifySeal * n
+	case SyscallVerifyAggregateSeals:
+		return p.VerifyAggregateSealsBase + p.VerifyAggregateSealsPer*n
+	case SyscallVerifyReplicaUpdate:
+		return p.VerifyReplicaUpdate
+	case SyscallVerifyPoSt, SyscallBatchVerifyPoSts:
+		return p.VerifyPoStBase + p.VerifyPoStPerSector*n
+	case SyscallVerifyConsensusFault:
+		return p.VerifyConsensusFault
+	}
+	return 0
+}
+
+// Sets the prices charged for operations in subsequent calls.
+func (rt *Runtime) SetPriceList(prices PriceList) {
+	rt.gas = prices
+}
+
+// Sets the charger pricing operations in subsequent calls.
+func (rt *Runtime) SetGasCharger(gas GasCharger) {
+	rt.gas = gas
+}
+
+// Charges gas for an operation, aborting with SysErrOutOfGas if the gas available is exhausted.
+// Operations outside a call (e.g. test setup reading state through the store) are not charged.
+func (rt *Runtime) charge(gas int64) {
+	if !rt.inCall || gas == 0 {
+		return
+	}
+	rt.gasCharged += gas
+	if rt.gasCharged > rt.gasLimit {
+		rt.Abortf(exitcode.SysErrOutOfGas, "out of gas: charged %d of limit %d", rt.gasCharged, rt.gasLimit)
+	}
+}
+
+// Returns the gas charged by the most recent call, whether or not it succeeded.
+func (rt *Runtime) LastCallGas() int64 {
+	return rt.lastCallGas
+}
+
+// Returns the total gas charged by calls to each method so far, keyed by method name.
+func (rt *Runtime) GasByMethod() map[string]int64 {
+	totals := make(map[string]int64, len(rt.gasByMethod))
+	for name, gas := range rt.gasByMethod { //nolint:nomaprange
+		totals[name] = gas
+	}
+	return totals
+}
+
+// Fails the test if the most recent call was charged more than `max` gas.
+func (rt *Runtime) ExpectLastCallGasAtMost(max int64) {
+	rt.t.Helper()
+	if rt.lastCallGas > max {
+		rt.failTest("expected call to charge at most %d gas, charged %d", max, rt.lastCallGas)
+	}
+}
+
+// Records the gas charged by a call to a method, given the gas charged before it began.
+func (rt *Runtime) recordCallGas(meth reflect.Value, chargedBefore int64) {
+	rt.lastCallGas = rt.gasCharged - chargedBefore
+	if rt.gasByMethod == nil {
+		rt.gasByMethod = make(map[string]int64)
+	}
+	rt.gasByMethod[methodName(meth)] += rt.lastCallGas
+}
+
+// Returns the unqualified name of a method value, e.g. "TerminateSectors" for miner.Actor{}.TerminateSectors.
+func methodName(meth reflect.Value) string {
+	fn := goruntime.FuncForPC(meth.Pointer())
+	if fn == nil {
+		return "unknown"
+	}
+	name := strings.TrimSuffix(fn.Name(), "-fm")
+	return name[strings.LastIndex(name, ".")+1:]
+}
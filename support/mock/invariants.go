@@ -0,0 +1,103 @@
+package mock
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/cbor"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v2/actors/util/adt"
+)
+
+// Checks the invariants of the receiver's state, typically by loading it and calling the actor package's
+// CheckStateInvariants.
+type InvariantCheck func(rt *Runtime) (*builtin.MessageAccumulator, error)
+
+// Sets a check of the receiver's state invariants to run after every call that returns without aborting,
+// failing the test if the call left the state in violation. Every test making calls then checks invariants
+// without asserting them explicitly.
+// The check is skipped after a call in which the actor deleted itself, leaving no state to check.
+// Tests that deliberately construct invalid state may set nil to disable the check.
+func (rt *Runtime) SetInvariantCheck(check InvariantCheck) {
+	rt.invariantCheck = check
+}
+
+var (
+	storeType       = reflect.TypeOf((*adt.Store)(nil)).Elem()
+	tokenAmountType = reflect.TypeOf(abi.TokenAmount{})
+	epochType       = reflect.TypeOf(abi.ChainEpoch(0))
+	accumulatorType = reflect.TypeOf(&builtin.MessageAccumulator{})
+	errorType       = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// Returns a check of the receiver's state invariants by an actor package's CheckStateInvariants function, e.g.
+// market.CheckStateInvariants.
+// The function's first parameter is a pointer to the actor's state, which is loaded from the receiver, and any
+// others are the store, the receiver's balance, or the current epoch. Its results include a message accumulator
+// and optionally an error.
+// Panics if the function does not have this form.
+func StateInvariants(checkStateInvariants interface{}) InvariantCheck {
+	fn := reflect.ValueOf(checkStateInvariants)
+	t := fn.Type()
+	if t.Kind() != reflect.Func || t.NumIn() == 0 || t.In(0).Kind() != reflect.Ptr ||
+		!t.In(0).Implements(reflect.TypeOf((*cbor.Unmarshaler)(nil)).Elem()) {
+		panic(fmt.Sprintf("invariant check %v does not take a pointer to actor state", t))
+	}
+	for i := 1; i < t.NumIn(); i++ {
+		if in := t.In(i); in != storeType && in != tokenAmountType && in != epochType {
+			panic(fmt.Sprintf("invariant check %v has unsupported parameter %v", t, in))
+		}
+	}
+	msgsOut, errOut := -1, -1
+	for i := 0; i < t.NumOut(); i++ {
+		switch t.Out(i) {
+		case accumulatorType:
+			msgsOut = i
+		case errorType:
+			errOut = i
+		}
+	}
+	if msgsOut < 0 {
+		panic(fmt.Sprintf("invariant check %v does not return a message accumulator", t))
+	}
+
+	return func(rt *Runtime) (*builtin.MessageAccumulator, error) {
+		st := reflect.New(t.In(0).Elem())
+		rt.GetState(st.Interface().(cbor.Unmarshaler))
+		args := []reflect.Value{st}
+		for i := 1; i < t.NumIn(); i++ {
+			switch t.In(i) {
+			case storeType:
+				args = append(args, reflect.ValueOf(rt.AdtStore()))
+			case tokenAmountType:
+				args = append(args, reflect.ValueOf(rt.Balance()))
+			case epochType:
+				args = append(args, reflect.ValueOf(rt.Epoch()))
+			}
+		}
+		out := fn.Call(args)
+		msgs := out[msgsOut].Interface().(*builtin.MessageAccumulator)
+		if errOut >= 0 && !out[errOut].IsNil() {
+			return msgs, out[errOut].Interface().(error)
+		}
+		return msgs, nil
+	}
+}
+
+func (rt *Runtime) checkInvariants() {
+	if rt.invariantCheck == nil || !rt.state.Defined() || rt.deleted {
+		return
+	}
+	rt.t.Helper()
+	msgs, err := rt.invariantCheck(rt)
+	if err != nil {
+		rt.failTest("failed to check state invariants: %v", err)
+		return
+	}
+	if msgs != nil && !msgs.IsEmpty() {
+		rt.failTest("state invariants violated:\n%s", strings.Join(msgs.Messages(), "\n"))
+	}
+}
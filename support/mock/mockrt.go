@@ -51,32 +51,83 @@ type Runtime struct {
 	balance abi.TokenAmount
 
 	// VM implementation
-	inCall        bool
-	store         map[cid.Cid][]byte
-	inTransaction bool
+	inCall       bool
+	store        map[cid.Cid][]byte
+	transactions []cbor.Er // State objects of the open (possibly nested) transactions, outermost first.
+	readOnly     bool
 	// Syscalls
 	hashfunc func(data []byte) [32]byte
 
 	// Expectations
-	t                              testing.TB
+	t testing.TB
+	expectations
+	// Actors to which sends are dispatched, by address, in place of matching expected sends.
+	dispatch map[addr.Address]*dispatchTarget
+	// Changes made by dispatched invocations, shared by the runtimes dispatching to each other.
+	journal *dispatchJournal
+
+	logs []string
+	// Events emitted during the most recent call, with values serialized when emitted.
+	events [][]runtime.EventEntry
+	// Gas charged explicitly through rt.ChargeGas. Note: most charges are implicit
+	gasCharged int64
+	gasLimit   int64
+	// Pricing of operations, and the gas charged by calls.
+	gas         GasCharger
+	lastCallGas int64
+	gasByMethod map[string]int64
+	// Batch limits overriding those computed from the gas available, by kind of work.
+	batchLimits map[runtime.BatchWork]runtime.BatchLimit
+	// States recorded by Snapshot, indexed by SnapshotID.
+	snapshots []*Runtime
+	// Check of the state's invariants after each call, if set.
+	invariantCheck InvariantCheck
+	// Whether the receiver deleted itself during the most recent call.
+	deleted bool
+	// Randomness for requests without a queued expectation, if set.
+	randomness RandomnessFunc
+	// Failures injected into syscalls, and the number of calls made to each syscall.
+	injectedFailures []*injectedFailure
+	syscallCalls     [numSyscalls]int
+	// Transfers of value made by calls since the last reset, those made by the current call, and the net
+	// transfers expected.
+	transfers          []Transfer
+	callTransfers      []Transfer
+	expectNetTransfers []*expectNetTransfer
+	// Whether calls are traced, and the trace of the most recent call.
+	tracing      bool
+	traceEntries []TraceEntry
+}
+
+// Expectations of the runtime calls made by an invocation of the actor. Those of a nested invocation, such as a
+// callee calling back to the actor, are scoped to it, with the enclosing invocation's restored when it returns.
+type expectations struct {
 	expectValidateCallerAny        bool
 	expectValidateCallerAddr       []addr.Address
 	expectValidateCallerType       []cid.Cid
-	expectRandomnessBeacon         []*expectRandomness
-	expectRandomnessTickets        []*expectRandomness
+	expectRandomness               []*expectRandomness
+	expectBeaconEntries            []*expectBeaconEntry
+	expectPowerSnapshots           []*expectPowerSnapshot
 	expectSends                    []*expectedMessage
+	sendGroupCount                 int // Number of unordered send groups created.
+	sendGroup                      int // Group to which new send expectations are added, or zero.
 	expectVerifySigs               []*expectVerifySig
+	expectVerifyAggregateSig       *expectVerifyAggregateSig
 	expectCreateActor              *expectCreateActor
 	expectVerifySeal               *expectVerifySeal
 	expectComputeUnsealedSectorCID *expectComputeUnsealedSectorCID
 	expectVerifyPoSt               *expectVerifyPoSt
 	expectVerifyConsensusFault     *expectVerifyConsensusFault
-	expectDeleteActor              *addr.Address
+	expectDeleteActor              *expectDeleteActor
+	expectUpgradeActor             *cid.Cid
 	expectBatchVerifySeals         *expectBatchVerifySeals
-
-	logs []string
-	// Gas charged explicitly through rt.ChargeGas. Note: most charges are implicit
-	gasCharged int64
+	expectBatchVerifyPoSts         *expectBatchVerifyPoSts
+	expectAggregateVerifySeals     *expectAggregateVerifySeals
+	expectReplicaUpdate            *expectReplicaUpdate
+	// Single-entry events expected to be emitted, in order, with values serialized.
+	expectEvents []runtime.EventEntry
+	// Setup of the expectations of each nested invocation expected, in order.
+	expectNestedCalls []func()
 }
 
 type expectBatchVerifySeals struct {
@@ -85,8 +136,15 @@ type expectBatchVerifySeals struct {
 	err error
 }
 
+type expectBatchVerifyPoSts struct {
+	in  []proof.WindowPoStVerifyInfo
+	out []bool
+	err error
+}
+
 type expectRandomness struct {
 	// Expected parameters.
+	source  runtime.RandomnessSource
 	tag     crypto.DomainSeparationTag
 	epoch   abi.ChainEpoch
 	entropy []byte
@@ -94,12 +152,34 @@ type expectRandomness struct {
 	out abi.Randomness
 }
 
+type expectBeaconEntry struct {
+	epoch abi.ChainEpoch
+	out   []byte
+}
+
+type expectPowerSnapshot struct {
+	miner addr.Address
+	epoch abi.ChainEpoch
+	out   runtime.PowerSnapshot
+	found bool
+}
+
 type expectedMessage struct {
 	// expectedMessage values
 	to     addr.Address
 	method abi.MethodNum
 	params cbor.Marshaler
 	value  abi.TokenAmount
+	// Gas limit of the send, or zero for an unlimited send.
+	gasLimit int64
+	// Whether the send is made with SendReadOnly.
+	readOnly bool
+	// Unordered group of expectations to which this belongs, or zero if ordered.
+	group int
+	// Whether the send may be omitted.
+	optional bool
+	// Predicate on the params, if given in place of expected params.
+	matchParams func(params cbor.Marshaler) bool
 
 	// returns from applying expectedMessage
 	sendReturn cbor.Er
@@ -115,8 +195,17 @@ type expectVerifySig struct {
 	result error
 }
 
+type expectVerifyAggregateSig struct {
+	sig        crypto.Signature
+	signers    []addr.Address
+	plaintexts [][]byte
+	result     error
+}
+
 type expectVerifySeal struct {
-	seal   proof.SealVerifyInfo
+	seal proof.SealVerifyInfo
+	// Predicate on the seal, if given in place of an expected seal.
+	match  func(seal proof.SealVerifyInfo) bool
 	result error
 }
 
@@ -127,12 +216,27 @@ type expectComputeUnsealedSectorCID struct {
 	resultErr error
 }
 
+type expectAggregateVerifySeals struct {
+	in  proof.AggregateSealVerifyProofAndInfos
+	err error
+}
+
+type expectReplicaUpdate struct {
+	in  proof.ReplicaUpdateInfo
+	err error
+}
+
 type expectVerifyPoSt struct {
-	post   proof.WindowPoStVerifyInfo
+	post proof.WindowPoStVerifyInfo
+	// Predicate on the PoSt, if given in place of an expected PoSt.
+	match  func(post proof.WindowPoStVerifyInfo) bool
 	result error
 }
 
 func (m *expectedMessage) Equal(to addr.Address, method abi.MethodNum, params cbor.Marshaler, value abi.TokenAmount) bool {
+	if m.matchParams != nil {
+		return m.to == to && m.method == method && m.value.Equals(value) && m.matchParams(params)
+	}
 	// avoid nil vs. zero/empty discrepancies that would disappear in serialization
 	paramBuf1 := new(bytes.Buffer)
 	if m.params != nil {
@@ -147,13 +251,43 @@ func (m *expectedMessage) Equal(to addr.Address, method abi.MethodNum, params cb
 }
 
 func (m *expectedMessage) String() string {
-	return fmt.Sprintf("to: %v method: %v value: %v params: %v sendReturn: %v exitCode: %v", m.to, m.method, m.value, m.params, m.sendReturn, m.exitCode)
+	return fmt.Sprintf("to: %v method: %v value: %v params: %v sendReturn: %v exitCode: %v", m.to, m.method, m.value, m.describeParams(), m.sendReturn, m.exitCode)
+}
+
+func (m *expectedMessage) describeParams() interface{} {
+	if m.matchParams != nil {
+		return "<matching>"
+	}
+	return m.params
 }
 
 type expectCreateActor struct {
 	// Expected parameters
 	codeId  cid.Cid
 	address addr.Address
+	// Predicate matching the address, if given in place of the address.
+	matchAddress func(addr.Address) bool
+}
+
+func (e *expectCreateActor) describeAddress() interface{} {
+	if e.matchAddress != nil {
+		return "<matching>"
+	}
+	return e.address
+}
+
+type expectDeleteActor struct {
+	// Expected beneficiary, to receive the actor's balance.
+	beneficiary addr.Address
+	// Predicate matching the beneficiary, if given in place of the beneficiary.
+	matchBeneficiary func(addr.Address) bool
+}
+
+func (e *expectDeleteActor) describeBeneficiary() interface{} {
+	if e.matchBeneficiary != nil {
+		return "<matching>"
+	}
+	return e.beneficiary
 }
 
 type expectVerifyConsensusFault struct {
@@ -268,61 +402,117 @@ func (rt *Runtime) GetActorCodeCID(addr addr.Address) (ret cid.Cid, ok bool) {
 	return
 }
 
-func (rt *Runtime) GetRandomnessFromBeacon(tag crypto.DomainSeparationTag, epoch abi.ChainEpoch, entropy []byte) abi.Randomness {
+func (rt *Runtime) GetRandomness(source runtime.RandomnessSource, tag crypto.DomainSeparationTag, epoch abi.ChainEpoch, entropy []byte) abi.Randomness {
 	rt.requireInCall()
-	if len(rt.expectRandomnessBeacon) == 0 {
-		rt.failTestNow("unexpected call to get randomness for tag %v, epoch %v", tag, epoch)
+	rt.trace(TraceRandomness, "GetRandomness", "source: %v, tag: %d, epoch: %d, entropy: %x", source, tag, epoch, entropy)
+	if err := runtime.CheckRandomnessLookback(source, rt.epoch, epoch); err != nil {
+		rt.Abortf(exitcode.SysErrorIllegalArgument, "invalid randomness request: %v", err)
 	}
-
-	if epoch > rt.epoch {
-		rt.failTestNow("attempt to get randomness from future\n"+
-			"         requested epoch: %d greater than current epoch %d\n", epoch, rt.epoch)
+	if len(rt.expectRandomness) == 0 {
+		if rt.randomness != nil {
+			return rt.randomness(source, tag, epoch, entropy)
+		}
+		rt.failTestNow("unexpected call to get randomness from %v for tag %v, epoch %v", source, tag, epoch)
 	}
 
-	exp := rt.expectRandomnessBeacon[0]
-	if tag != exp.tag || epoch != exp.epoch || !bytes.Equal(entropy, exp.entropy) {
+	exp := rt.expectRandomness[0]
+	if source != exp.source || tag != exp.tag || epoch != exp.epoch || !bytes.Equal(entropy, exp.entropy) {
 		rt.failTest("unexpected get randomness\n"+
-			"         tag: %d, epoch: %d, entropy: %v\n"+
-			"expected tag: %d, epoch: %d, entropy: %v", tag, epoch, entropy, exp.tag, exp.epoch, exp.entropy)
+			"         source: %v, tag: %d, epoch: %d, entropy: %v\n"+
+			"expected source: %v, tag: %d, epoch: %d, entropy: %v", source, tag, epoch, entropy, exp.source, exp.tag, exp.epoch, exp.entropy)
 	}
 	defer func() {
-		rt.expectRandomnessBeacon = rt.expectRandomnessBeacon[1:]
+		rt.expectRandomness = rt.expectRandomness[1:]
 	}()
 	return exp.out
 }
 
-func (rt *Runtime) GetRandomnessFromTickets(tag crypto.DomainSeparationTag, epoch abi.ChainEpoch, entropy []byte) abi.Randomness {
+func (rt *Runtime) GetBeaconEntry(epoch abi.ChainEpoch) []byte {
 	rt.requireInCall()
-	if len(rt.expectRandomnessTickets) == 0 {
-		rt.failTestNow("unexpected call to get randomness for tag %v, epoch %v", tag, epoch)
+	rt.trace(TraceRandomness, "GetBeaconEntry", "epoch: %d", epoch)
+	if err := runtime.CheckHistoricalLookback(rt.epoch, epoch); err != nil {
+		rt.Abortf(exitcode.SysErrorIllegalArgument, "invalid beacon entry request: %v", err)
 	}
-
-	if epoch > rt.epoch {
-		rt.failTestNow("attempt to get randomness from future\n"+
-			"         requested epoch: %d greater than current epoch %d\n", epoch, rt.epoch)
+	if len(rt.expectBeaconEntries) == 0 {
+		rt.failTestNow("unexpected call to get beacon entry at epoch %v", epoch)
 	}
 
-	exp := rt.expectRandomnessTickets[0]
-	if tag != exp.tag || epoch != exp.epoch || !bytes.Equal(entropy, exp.entropy) {
-		rt.failTest("unexpected get randomness\n"+
-			"         tag: %d, epoch: %d, entropy: %v\n"+
-			"expected tag: %d, epoch: %d, entropy: %v", tag, epoch, entropy, exp.tag, exp.epoch, exp.entropy)
+	exp := rt.expectBeaconEntries[0]
+	if epoch != exp.epoch {
+		rt.failTest("unexpected get beacon entry\n"+
+			"         epoch: %d\n"+
+			"expected epoch: %d", epoch, exp.epoch)
 	}
 	defer func() {
-		rt.expectRandomnessTickets = rt.expectRandomnessTickets[1:]
+		rt.expectBeaconEntries = rt.expectBeaconEntries[1:]
 	}()
 	return exp.out
 }
 
+func (rt *Runtime) GetMinerPowerSnapshot(miner addr.Address, epoch abi.ChainEpoch) (runtime.PowerSnapshot, bool) {
+	rt.requireInCall()
+	rt.trace(TraceStateRead, "GetMinerPowerSnapshot", "miner: %v, epoch: %d", miner, epoch)
+	if err := runtime.CheckHistoricalLookback(rt.epoch, epoch); err != nil {
+		rt.Abortf(exitcode.SysErrorIllegalArgument, "invalid power snapshot request: %v", err)
+	}
+	if len(rt.expectPowerSnapshots) == 0 {
+		rt.failTestNow("unexpected call to get power snapshot for %v at epoch %v", miner, epoch)
+	}
+
+	exp := rt.expectPowerSnapshots[0]
+	if miner != exp.miner || epoch != exp.epoch {
+		rt.failTest("unexpected get power snapshot\n"+
+			"         miner: %v, epoch: %d\n"+
+			"expected miner: %v, epoch: %d", miner, epoch, exp.miner, exp.epoch)
+	}
+	defer func() {
+		rt.expectPowerSnapshots = rt.expectPowerSnapshots[1:]
+	}()
+	return exp.out, exp.found
+}
+
 func (rt *Runtime) Send(toAddr addr.Address, methodNum abi.MethodNum, params cbor.Marshaler, value abi.TokenAmount, out cbor.Er) exitcode.ExitCode {
+	return rt.send(toAddr, methodNum, params, value, out, 0, false)
+}
+
+func (rt *Runtime) SendWithGasLimit(toAddr addr.Address, methodNum abi.MethodNum, params cbor.Marshaler, value abi.TokenAmount, out cbor.Er, gasLimit int64) exitcode.ExitCode {
+	if gasLimit <= 0 {
+		rt.failTestNow("non-positive gas limit %d", gasLimit)
+	}
+	return rt.send(toAddr, methodNum, params, value, out, gasLimit, false)
+}
+
+func (rt *Runtime) SendReadOnly(toAddr addr.Address, methodNum abi.MethodNum, params cbor.Marshaler, out cbor.Er) exitcode.ExitCode {
+	return rt.send(toAddr, methodNum, params, big.Zero(), out, 0, true)
+}
+
+func (rt *Runtime) send(toAddr addr.Address, methodNum abi.MethodNum, params cbor.Marshaler, value abi.TokenAmount, out cbor.Er, gasLimit int64, readOnly bool) exitcode.ExitCode {
 	rt.requireInCall()
-	if rt.inTransaction {
+	op := "Send"
+	if readOnly {
+		op = "SendReadOnly"
+	} else if gasLimit != 0 {
+		op = "SendWithGasLimit"
+	}
+	rt.trace(TraceSend, op, "to: %v, method: %d, value: %v, params: %v, gas limit: %d", toAddr, methodNum, value, params, gasLimit)
+	if rt.inTransaction() {
 		rt.Abortf(exitcode.SysErrorIllegalActor, "side-effect within transaction")
 	}
+	if !value.IsZero() {
+		rt.abortIfReadOnly("value transfer")
+	}
+	if target, ok := rt.dispatch[toAddr]; ok {
+		return rt.dispatchSend(target, methodNum, params, value, out, readOnly)
+	}
 	if len(rt.expectSends) == 0 {
 		rt.failTestNow("unexpected send to: %v method: %v, value: %v, params: %v", toAddr, methodNum, value, params)
 	}
+	// Report a send matching no candidate against the next expected send.
+	matched := rt.matchExpectedSend(toAddr, methodNum, params, value, gasLimit, readOnly)
 	exp := rt.expectSends[0]
+	if matched >= 0 {
+		exp = rt.expectSends[matched]
+	}
 
 	if !exp.Equal(toAddr, methodNum, params, value) {
 		toName := "unknown"
@@ -341,8 +531,16 @@ func (rt *Runtime) Send(toAddr addr.Address, methodNum abi.MethodNum, params cbo
 		rt.failTestNow("unexpected send\n"+
 			"          to: %s (%s) method: %d (%s) value: %v params: %v\n"+
 			"Expected  to: %s (%s) method: %d (%s) value: %v params: %v",
-			toAddr, toName, methodNum, toMeth, value, params, exp.to, expToName, exp.method, expToMeth, exp.value, exp.params)
+			toAddr, toName, methodNum, toMeth, value, params, exp.to, expToName, exp.method, expToMeth, exp.value, exp.describeParams())
+	}
+	if exp.gasLimit != gasLimit {
+		rt.failTestNow("unexpected send gas limit %d to %v method %d, expected %d", gasLimit, toAddr, methodNum, exp.gasLimit)
 	}
+	if exp.readOnly != readOnly {
+		rt.failTestNow("unexpected send read-only %t to %v method %d, expected %t", readOnly, toAddr, methodNum, exp.readOnly)
+	}
+
+	rt.charge(rt.gas.OnSend(!value.IsZero()))
 
 	if value.GreaterThan(rt.balance) {
 		rt.Abortf(exitcode.SysErrSenderStateInvalid, "cannot send value: %v exceeds balance: %v", value, rt.balance)
@@ -350,8 +548,11 @@ func (rt *Runtime) Send(toAddr addr.Address, methodNum abi.MethodNum, params cbo
 
 	// pop the expectedMessage from the queue and modify the mockrt balance to reflect the send.
 	defer func() {
-		rt.expectSends = rt.expectSends[1:]
+		rt.consumeExpectedSend(matched)
 		rt.balance = big.Sub(rt.balance, value)
+		if exp.exitCode.IsSuccess() {
+			rt.recordTransfer(rt.receiver, toAddr, value)
+		}
 	}()
 
 	// populate the output argument
@@ -368,6 +569,59 @@ func (rt *Runtime) Send(toAddr addr.Address, methodNum abi.MethodNum, params cbo
 	return exp.exitCode
 }
 
+// Returns the indices of the expected sends which the next send may match: ordered expectations up to and
+// including the first that is required, and every member of any unordered group among them.
+func (rt *Runtime) nextSendCandidates() []int {
+	var candidates []int
+	for i := 0; i < len(rt.expectSends); {
+		exp := rt.expectSends[i]
+		if exp.group == 0 {
+			candidates = append(candidates, i)
+			if !exp.optional {
+				return candidates
+			}
+			i++
+			continue
+		}
+		required := false
+		for ; i < len(rt.expectSends) && rt.expectSends[i].group == exp.group; i++ {
+			candidates = append(candidates, i)
+			required = required || !rt.expectSends[i].optional
+		}
+		if required {
+			return candidates
+		}
+	}
+	return candidates
+}
+
+// Returns the index of the first candidate expected send matching a send, or -1 if none does.
+func (rt *Runtime) matchExpectedSend(toAddr addr.Address, methodNum abi.MethodNum, params cbor.Marshaler, value abi.TokenAmount, gasLimit int64, readOnly bool) int {
+	for _, i := range rt.nextSendCandidates() {
+		exp := rt.expectSends[i]
+		if exp.Equal(toAddr, methodNum, params, value) && exp.gasLimit == gasLimit && exp.readOnly == readOnly {
+			return i
+		}
+	}
+	return -1
+}
+
+// Removes a matched expected send, along with the optional expectations it passed over outside its own group.
+func (rt *Runtime) consumeExpectedSend(matched int) {
+	if matched < 0 {
+		return
+	}
+	group := rt.expectSends[matched].group
+	remaining := make([]*expectedMessage, 0, len(rt.expectSends)-1)
+	for i, exp := range rt.expectSends {
+		if i == matched || (i < matched && (group == 0 || exp.group != group)) {
+			continue
+		}
+		remaining = append(remaining, exp)
+	}
+	rt.expectSends = remaining
+}
+
 func (rt *Runtime) NewActorAddress() addr.Address {
 	rt.requireInCall()
 	if rt.newActorAddr == addr.Undef {
@@ -379,18 +633,23 @@ func (rt *Runtime) NewActorAddress() addr.Address {
 
 func (rt *Runtime) CreateActor(codeId cid.Cid, address addr.Address) {
 	rt.requireInCall()
-	if rt.inTransaction {
+	if rt.inTransaction() {
 		rt.Abortf(exitcode.SysErrorIllegalActor, "side-effect within transaction")
 	}
+	rt.abortIfReadOnly("actor creation")
 	exp := rt.expectCreateActor
 	if exp != nil {
-		if !exp.codeId.Equals(codeId) || exp.address != address {
-			rt.failTest("unexpected create actor, code: %s, address: %s; expected code: %s, address: %s",
-				codeId, address, exp.codeId, exp.address)
+		addressMatches := exp.address == address
+		if exp.matchAddress != nil {
+			addressMatches = exp.matchAddress(address)
 		}
-		defer func() {
-			rt.expectCreateActor = nil
-		}()
+		if !exp.codeId.Equals(codeId) || !addressMatches {
+			rt.failTest("unexpected create actor, code: %s, address: %s; expected code: %s, address: %v",
+				codeId, address, exp.codeId, exp.describeAddress())
+		}
+		// The new actor's code is observable by later calls, as in the VM.
+		rt.actorCodeCIDs[address] = codeId
+		rt.expectCreateActor = nil
 		return
 	}
 	rt.failTestNow("unexpected call to create actor")
@@ -398,17 +657,46 @@ func (rt *Runtime) CreateActor(codeId cid.Cid, address addr.Address) {
 
 func (rt *Runtime) DeleteActor(addr addr.Address) {
 	rt.requireInCall()
-	if rt.inTransaction {
+	if rt.inTransaction() {
 		rt.Abortf(exitcode.SysErrorIllegalActor, "side-effect within transaction")
 	}
-	if rt.expectDeleteActor == nil {
-		rt.failTestNow("unexpected call to delete actor %s", addr.String())
+	rt.abortIfReadOnly("actor deletion")
+	exp := rt.expectDeleteActor
+	if exp == nil {
+		rt.failTestNow("unexpected call to delete actor with beneficiary %s", addr.String())
 	}
 
-	if *rt.expectDeleteActor != addr {
-		rt.failTestNow("attempt to delete wrong actor. Expected %s, got %s.", rt.expectDeleteActor.String(), addr.String())
+	beneficiaryMatches := exp.beneficiary == addr
+	if exp.matchBeneficiary != nil {
+		beneficiaryMatches = exp.matchBeneficiary(addr)
+	}
+	if !beneficiaryMatches {
+		rt.failTestNow("attempt to delete actor with wrong beneficiary. Expected %v, got %s.", exp.describeBeneficiary(), addr.String())
 	}
 	rt.expectDeleteActor = nil
+
+	// The remaining balance passes to the beneficiary.
+	rt.recordTransfer(rt.receiver, addr, rt.balance)
+	rt.balance = big.Zero()
+	rt.deleted = true
+}
+
+// Replaces the receiver's code as expected. The mock does not authorize the upgrade with the Init actor
+// nor invoke the new code's state validation, which tests should exercise directly.
+func (rt *Runtime) UpgradeActor(newCodeID cid.Cid) {
+	rt.requireInCall()
+	if rt.inTransaction() {
+		rt.Abortf(exitcode.SysErrorIllegalActor, "side-effect within transaction")
+	}
+	rt.abortIfReadOnly("actor upgrade")
+	if rt.expectUpgradeActor == nil {
+		rt.failTestNow("unexpected call to upgrade actor to %v", newCodeID)
+	}
+	if !rt.expectUpgradeActor.Equals(newCodeID) {
+		rt.failTestNow("attempt to upgrade to wrong code. Expected %v, got %v.", rt.expectUpgradeActor, newCodeID)
+	}
+	rt.expectUpgradeActor = nil
+	rt.actorCodeCIDs[rt.receiver] = newCodeID
 }
 
 func (rt *Runtime) TotalFilCircSupply() abi.TokenAmount {
@@ -418,7 +706,17 @@ func (rt *Runtime) TotalFilCircSupply() abi.TokenAmount {
 func (rt *Runtime) Abortf(errExitCode exitcode.ExitCode, msg string, args ...interface{}) {
 	rt.requireInCall()
 	rt.t.Logf("Mock Runtime Abort ExitCode: %v Reason: %s", errExitCode, fmt.Sprintf(msg, args...))
-	panic(abort{errExitCode, fmt.Sprintf(msg, args...)})
+	panic(abort{code: errExitCode, msg: fmt.Sprintf(msg, args...)})
+}
+
+func (rt *Runtime) AbortWithData(errExitCode exitcode.ExitCode, data cbor.Marshaler, msg string, args ...interface{}) {
+	rt.requireInCall()
+	var buf bytes.Buffer
+	if err := data.MarshalCBOR(&buf); err != nil {
+		rt.failTestNow("failed to serialize abort data: %v", err)
+	}
+	rt.t.Logf("Mock Runtime Abort ExitCode: %v Reason: %s Data: %x", errExitCode, fmt.Sprintf(msg, args...), buf.Bytes())
+	panic(abort{code: errExitCode, msg: fmt.Sprintf(msg, args...), data: buf.Bytes()})
 }
 
 func (rt *Runtime) Context() context.Context {
@@ -472,6 +770,8 @@ func (rt *Runtime) put(c cid.Cid, data []byte) {
 func (rt *Runtime) StoreGet(c cid.Cid, o cbor.Unmarshaler) bool {
 	// requireInCall omitted because it makes using this mock runtime as a store awkward.
 	data, found := rt.get(c)
+	rt.trace(TraceStoreGet, "StoreGet", "cid: %v, bytes: %d, found: %t", c, len(data), found)
+	rt.charge(rt.gas.OnStoreGet(len(data)))
 	if found {
 		err := o.UnmarshalCBOR(bytes.NewReader(data))
 		if err != nil {
@@ -490,10 +790,12 @@ func (rt *Runtime) StorePut(o cbor.Marshaler) cid.Cid {
 		rt.Abortf(exitcode.ErrSerialization, err.Error())
 	}
 	data := r.Bytes()
+	rt.charge(rt.gas.OnStorePut(len(data)))
 	key, err := abi.CidBuilder.Sum(data)
 	if err != nil {
 		rt.Abortf(exitcode.ErrSerialization, err.Error())
 	}
+	rt.trace(TraceStorePut, "StorePut", "cid: %v, bytes: %d", key, len(data))
 	rt.put(key, data)
 	return key
 }
@@ -522,10 +824,21 @@ func (rt *Runtime) StateCreate(obj cbor.Marshaler) {
 	if rt.state.Defined() {
 		rt.Abortf(exitcode.SysErrorIllegalActor, "state already constructed")
 	}
+	rt.abortIfReadOnly("state creation")
 	rt.state = rt.StorePut(obj)
+	rt.trace(TraceStateWrite, "StateCreate", "head: %v", rt.state)
 }
 
 func (rt *Runtime) StateReadonly(st cbor.Unmarshaler) {
+	if rt.inTransaction() {
+		rt.trace(TraceStateRead, "StateReadonly", "enclosing transaction")
+	} else {
+		rt.trace(TraceStateRead, "StateReadonly", "head: %v", rt.state)
+	}
+	if rt.inTransaction() {
+		rt.copyState(rt.transactions[len(rt.transactions)-1], st)
+		return
+	}
 	found := rt.StoreGet(rt.state, st)
 	if !found {
 		panic(fmt.Sprintf("actor state not found: %v", rt.state))
@@ -533,19 +846,44 @@ func (rt *Runtime) StateReadonly(st cbor.Unmarshaler) {
 }
 
 func (rt *Runtime) StateTransaction(st cbor.Er, f func()) {
-	if rt.inTransaction {
-		rt.Abortf(exitcode.SysErrorIllegalActor, "nested transaction")
+	rt.abortIfReadOnly("state transaction")
+	if rt.inTransaction() {
+		// Nested transaction: start from the enclosing transaction's state and hand the result back to it.
+		enclosing := rt.transactions[len(rt.transactions)-1]
+		rt.StateReadonly(st)
+		rt.transactions = append(rt.transactions, st)
+		f()
+		rt.transactions = rt.transactions[:len(rt.transactions)-1]
+		rt.copyState(st, enclosing)
+		return
 	}
 	rt.StateReadonly(st)
-	rt.inTransaction = true
-	defer func() { rt.inTransaction = false }()
+	rt.transactions = []cbor.Er{st}
+	defer func() { rt.transactions = nil }()
 	f()
 	rt.state = rt.StorePut(st)
+	rt.trace(TraceStateWrite, "StateTransaction", "head: %v", rt.state)
+}
+
+func (rt *Runtime) inTransaction() bool {
+	return len(rt.transactions) > 0
+}
+
+func (rt *Runtime) copyState(from cbor.Marshaler, to cbor.Unmarshaler) {
+	var buf bytes.Buffer
+	if err := from.MarshalCBOR(&buf); err != nil {
+		rt.failTestNow("failed to serialize transaction state: %v", err)
+	}
+	if err := to.UnmarshalCBOR(&buf); err != nil {
+		rt.failTestNow("failed to deserialize transaction state: %v", err)
+	}
 }
 
 ///// Syscalls implementation /////
 
 func (rt *Runtime) VerifySignature(sig crypto.Signature, signer addr.Address, plaintext []byte) error {
+	rt.trace(TraceSyscall, "VerifySignature", "sig: %v, signer: %v, plaintext: %x", sig, signer, plaintext)
+	rt.charge(rt.gas.OnSyscall(SyscallVerifySignature, 1))
 	if len(rt.expectVerifySigs) == 0 {
 		rt.failTest("unexpected signature verification sig: %v, signer: %s, plaintext: %v", sig, signer, plaintext)
 	}
@@ -567,11 +905,38 @@ func (rt *Runtime) VerifySignature(sig crypto.Signature, signer addr.Address, pl
 	return nil
 }
 
+func (rt *Runtime) VerifyAggregateSignature(sig crypto.Signature, signers []addr.Address, plaintexts [][]byte) error {
+	rt.trace(TraceSyscall, "VerifyAggregateSignature", "sig: %v, signers: %v, plaintexts: %x", sig, signers, plaintexts)
+	rt.charge(rt.gas.OnSyscall(SyscallVerifyAggregateSignature, len(signers)))
+	exp := rt.expectVerifyAggregateSig
+	if exp != nil {
+		if !exp.sig.Equals(&sig) || !reflect.DeepEqual(exp.signers, signers) || !reflect.DeepEqual(exp.plaintexts, plaintexts) {
+			rt.failTest("unexpected aggregate signature verification\n"+
+				"         sig: %v, signers: %v, plaintexts: %x\n"+
+				"expected sig: %v, signers: %v, plaintexts: %x",
+				sig, signers, plaintexts, exp.sig, exp.signers, exp.plaintexts)
+		}
+		defer func() {
+			rt.expectVerifyAggregateSig = nil
+		}()
+		return exp.result
+	}
+	rt.failTestNow("unexpected syscall to verify aggregate signature %v, signers %v, plaintexts %x", sig, signers, plaintexts)
+	return nil
+}
+
 func (rt *Runtime) HashBlake2b(data []byte) [32]byte {
+	rt.trace(TraceSyscall, "HashBlake2b", "data: %x", data)
+	rt.charge(rt.gas.OnSyscall(SyscallHashBlake2b, 1))
 	return rt.hashfunc(data)
 }
 
 func (rt *Runtime) ComputeUnsealedSectorCID(reg abi.RegisteredSealProof, pieces []abi.PieceInfo) (cid.Cid, error) {
+	rt.trace(TraceSyscall, "ComputeUnsealedSectorCID", "proof: %v, pieces: %v", reg, pieces)
+	rt.charge(rt.gas.OnSyscall(SyscallComputeUnsealedSectorCID, 1))
+	if err := rt.injectedFailure(SyscallComputeUnsealedSectorCID); err != nil {
+		return cid.Undef, err
+	}
 	exp := rt.expectComputeUnsealedSectorCID
 	if exp != nil {
 		if !reflect.DeepEqual(exp.reg, reg) {
@@ -591,9 +956,18 @@ func (rt *Runtime) ComputeUnsealedSectorCID(reg abi.RegisteredSealProof, pieces
 }
 
 func (rt *Runtime) VerifySeal(seal proof.SealVerifyInfo) error {
+	rt.trace(TraceSyscall, "VerifySeal", "%v", seal)
+	rt.charge(rt.gas.OnSyscall(SyscallVerifySeal, 1))
+	if err := rt.injectedFailure(SyscallVerifySeal); err != nil {
+		return err
+	}
 	exp := rt.expectVerifySeal
 	if exp != nil {
-		if !reflect.DeepEqual(exp.seal, seal) {
+		if exp.match != nil {
+			if !exp.match(seal) {
+				rt.failTest("seal verification did not match expectation: %v", seal)
+			}
+		} else if !reflect.DeepEqual(exp.seal, seal) {
 			rt.failTest("unexpected seal verification\n"+
 				"        : %v\n"+
 				"expected: %v",
@@ -615,6 +989,13 @@ func (rt *Runtime) ExpectBatchVerifySeals(in map[addr.Address][]proof.SealVerify
 }
 
 func (rt *Runtime) BatchVerifySeals(vis map[addr.Address][]proof.SealVerifyInfo) (map[addr.Address][]bool, error) {
+	rt.trace(TraceSyscall, "BatchVerifySeals", "%v", vis)
+	for _, infos := range vis { //nolint:nomaprange
+		rt.charge(rt.gas.OnSyscall(SyscallBatchVerifySeals, len(infos)))
+	}
+	if err := rt.injectedFailure(SyscallBatchVerifySeals); err != nil {
+		return nil, err
+	}
 	exp := rt.expectBatchVerifySeals
 	if exp != nil {
 		if len(vis) != len(exp.in) {
@@ -655,10 +1036,102 @@ func (rt *Runtime) BatchVerifySeals(vis map[addr.Address][]proof.SealVerifyInfo)
 	return nil, nil
 }
 
+// Expects a batch verification of PoSts matching the input exactly and in order, returning out and err.
+func (rt *Runtime) ExpectBatchVerifyPoSts(in []proof.WindowPoStVerifyInfo, out []bool, err error) {
+	rt.expectBatchVerifyPoSts = &expectBatchVerifyPoSts{
+		in, out, err,
+	}
+}
+
+func (rt *Runtime) BatchVerifyPoSts(vis []proof.WindowPoStVerifyInfo) ([]bool, error) {
+	rt.trace(TraceSyscall, "BatchVerifyPoSts", "%v", vis)
+	sectors := 0
+	for _, vi := range vis {
+		sectors += len(vi.ChallengedSectors)
+	}
+	rt.charge(rt.gas.OnSyscall(SyscallBatchVerifyPoSts, sectors))
+	if err := rt.injectedFailure(SyscallBatchVerifyPoSts); err != nil {
+		return nil, err
+	}
+	exp := rt.expectBatchVerifyPoSts
+	if exp != nil {
+		if !reflect.DeepEqual(exp.in, vis) {
+			rt.failTest("unexpected batch PoSt verification\n"+
+				"        : %v\n"+
+				"expected: %v",
+				vis, exp.in)
+		}
+		defer func() {
+			rt.expectBatchVerifyPoSts = nil
+		}()
+		return exp.out, exp.err
+	}
+	rt.failTestNow("unexpected syscall to batch verify PoSts %v", vis)
+	return nil, nil
+}
+
+func (rt *Runtime) ExpectAggregateVerifySeals(in proof.AggregateSealVerifyProofAndInfos, err error) {
+	rt.expectAggregateVerifySeals = &expectAggregateVerifySeals{
+		in, err,
+	}
+}
+
+func (rt *Runtime) VerifyAggregateSeals(agg proof.AggregateSealVerifyProofAndInfos) error {
+	rt.trace(TraceSyscall, "VerifyAggregateSeals", "%v", agg)
+	rt.charge(rt.gas.OnSyscall(SyscallVerifyAggregateSeals, len(agg.Infos)))
+	exp := rt.expectAggregateVerifySeals
+	if exp != nil {
+		if !reflect.DeepEqual(exp.in, agg) {
+			rt.failTest("unexpected aggregate seal verification\n"+
+				"        : %v\n"+
+				"expected: %v",
+				agg, exp.in)
+		}
+		defer func() {
+			rt.expectAggregateVerifySeals = nil
+		}()
+		return exp.err
+	}
+	rt.failTestNow("unexpected syscall to verify aggregate seals with %v", agg)
+	return nil
+}
+
+func (rt *Runtime) ExpectReplicaUpdate(in proof.ReplicaUpdateInfo, err error) {
+	rt.expectReplicaUpdate = &expectReplicaUpdate{
+		in, err,
+	}
+}
+
+func (rt *Runtime) VerifyReplicaUpdate(replica proof.ReplicaUpdateInfo) error {
+	rt.trace(TraceSyscall, "VerifyReplicaUpdate", "%v", replica)
+	rt.charge(rt.gas.OnSyscall(SyscallVerifyReplicaUpdate, 1))
+	exp := rt.expectReplicaUpdate
+	if exp != nil {
+		if !reflect.DeepEqual(exp.in, replica) {
+			rt.failTest("unexpected replica update verification\n"+
+				"        : %v\n"+
+				"expected: %v",
+				replica, exp.in)
+		}
+		defer func() {
+			rt.expectReplicaUpdate = nil
+		}()
+		return exp.err
+	}
+	rt.failTestNow("unexpected syscall to verify replica update with %v", replica)
+	return nil
+}
+
 func (rt *Runtime) VerifyPoSt(vi proof.WindowPoStVerifyInfo) error {
+	rt.trace(TraceSyscall, "VerifyPoSt", "%v", vi)
+	rt.charge(rt.gas.OnSyscall(SyscallVerifyPoSt, len(vi.ChallengedSectors)))
 	exp := rt.expectVerifyPoSt
 	if exp != nil {
-		if !reflect.DeepEqual(exp.post, vi) {
+		if exp.match != nil {
+			if !exp.match(vi) {
+				rt.failTest("PoSt verification did not match expectation: %v", vi)
+			}
+		} else if !reflect.DeepEqual(exp.post, vi) {
 			rt.failTest("unexpected PoSt verification\n"+
 				"        : %v\n"+
 				"expected: %v",
@@ -674,6 +1147,11 @@ func (rt *Runtime) VerifyPoSt(vi proof.WindowPoStVerifyInfo) error {
 }
 
 func (rt *Runtime) VerifyConsensusFault(h1, h2, extra []byte) (*runtime.ConsensusFault, error) {
+	rt.trace(TraceSyscall, "VerifyConsensusFault", "h1: %x, h2: %x, extra: %x", h1, h2, extra)
+	rt.charge(rt.gas.OnSyscall(SyscallVerifyConsensusFault, 1))
+	if err := rt.injectedFailure(SyscallVerifyConsensusFault); err != nil {
+		return nil, err
+	}
 	if rt.expectVerifyConsensusFault == nil {
 		rt.failTestNow("Unexpected syscall VerifyConsensusFault")
 		return nil, nil
@@ -701,6 +1179,57 @@ func (rt *Runtime) Log(level rt.LogLevel, msg string, args ...interface{}) {
 	rt.logs = append(rt.logs, fmt.Sprintf(msg, args...))
 }
 
+func (rt *Runtime) EmitEvent(entries ...runtime.EventEntry) {
+	rt.requireInCall()
+	rt.abortIfReadOnly("event emission")
+	event := make([]runtime.EventEntry, len(entries))
+	for i, e := range entries {
+		var buf bytes.Buffer
+		if err := e.Value.MarshalCBOR(&buf); err != nil {
+			rt.failTestNow("failed to serialize event entry %s: %v", e.Key, err)
+		}
+		event[i] = runtime.EventEntry{Key: e.Key, Value: builtin.CBORBytes(buf.Bytes())}
+	}
+	rt.events = append(rt.events, event)
+	if len(rt.expectEvents) > 0 && len(event) == 1 && eventEntryEquals(event[0], rt.expectEvents[0]) {
+		rt.expectEvents = rt.expectEvents[1:]
+	}
+}
+
+// Expects a single-entry event with key and value to be emitted, after any previously expected.
+// Other events may be emitted before, between or after expected events.
+func (rt *Runtime) ExpectEvent(key string, value cbor.Marshaler) {
+	var buf bytes.Buffer
+	if err := value.MarshalCBOR(&buf); err != nil {
+		rt.failTestNow("failed to serialize expected event value: %v", err)
+	}
+	rt.expectEvents = append(rt.expectEvents, runtime.EventEntry{Key: key, Value: builtin.CBORBytes(buf.Bytes())})
+}
+
+func eventEntryEquals(a, b runtime.EventEntry) bool {
+	return a.Key == b.Key && bytes.Equal(a.Value.(builtin.CBORBytes), b.Value.(builtin.CBORBytes))
+}
+
+// Returns the events emitted during the most recent call, with each value in serialized form.
+func (rt *Runtime) EmittedEvents() [][]runtime.EventEntry {
+	return rt.events
+}
+
+// Fails the test unless the most recent call emitted a single-entry event with key and value.
+func (rt *Runtime) RequireEmittedEvent(key string, value cbor.Marshaler) {
+	rt.t.Helper()
+	var expected bytes.Buffer
+	if err := value.MarshalCBOR(&expected); err != nil {
+		rt.failTestNow("failed to serialize expected event value: %v", err)
+	}
+	for _, event := range rt.events {
+		if len(event) == 1 && eventEntryEquals(event[0], runtime.EventEntry{Key: key, Value: builtin.CBORBytes(expected.Bytes())}) {
+			return
+		}
+	}
+	rt.failTestNow("no event %s with value %v among %d emitted", key, value, len(rt.events))
+}
+
 ///// Trace span implementation /////
 
 type TraceSpan struct {
@@ -713,6 +1242,7 @@ func (t TraceSpan) End() {
 type abort struct {
 	code exitcode.ExitCode
 	msg  string
+	data []byte // Serialized data describing the failure, if provided.
 }
 
 func (a abort) String() string {
@@ -776,6 +1306,19 @@ func (rt *Runtime) SetEpoch(epoch abi.ChainEpoch) {
 	rt.epoch = epoch
 }
 
+// Sets the gas available to subsequent calls, until gas charged by the actor reduces it.
+func (rt *Runtime) SetGasAvailable(gas int64) {
+	rt.gasLimit = rt.gasCharged + gas
+}
+
+// Sets the batch limit suggested for a kind of work, in place of that computed from the gas available.
+func (rt *Runtime) SetBatchLimit(work runtime.BatchWork, limit runtime.BatchLimit) {
+	if rt.batchLimits == nil {
+		rt.batchLimits = make(map[runtime.BatchWork]runtime.BatchLimit)
+	}
+	rt.batchLimits[work] = limit
+}
+
 func (rt *Runtime) ReplaceState(o cbor.Marshaler) {
 	rt.state = rt.StorePut(o)
 }
@@ -808,8 +1351,9 @@ func (rt *Runtime) ExpectValidateCallerType(types ...cid.Cid) {
 	rt.expectValidateCallerType = types[:]
 }
 
-func (rt *Runtime) ExpectGetRandomnessBeacon(tag crypto.DomainSeparationTag, epoch abi.ChainEpoch, entropy []byte, out abi.Randomness) {
-	rt.expectRandomnessBeacon = append(rt.expectRandomnessBeacon, &expectRandomness{
+func (rt *Runtime) ExpectGetRandomness(source runtime.RandomnessSource, tag crypto.DomainSeparationTag, epoch abi.ChainEpoch, entropy []byte, out abi.Randomness) {
+	rt.expectRandomness = append(rt.expectRandomness, &expectRandomness{
+		source:  source,
 		tag:     tag,
 		epoch:   epoch,
 		entropy: entropy,
@@ -817,25 +1361,69 @@ func (rt *Runtime) ExpectGetRandomnessBeacon(tag crypto.DomainSeparationTag, epo
 	})
 }
 
-func (rt *Runtime) ExpectGetRandomnessTickets(tag crypto.DomainSeparationTag, epoch abi.ChainEpoch, entropy []byte, out abi.Randomness) {
-	rt.expectRandomnessTickets = append(rt.expectRandomnessTickets, &expectRandomness{
-		tag:     tag,
-		epoch:   epoch,
-		entropy: entropy,
-		out:     out,
+func (rt *Runtime) ExpectGetBeaconEntry(epoch abi.ChainEpoch, out []byte) {
+	rt.expectBeaconEntries = append(rt.expectBeaconEntries, &expectBeaconEntry{
+		epoch: epoch,
+		out:   out,
+	})
+}
+
+func (rt *Runtime) ExpectGetMinerPowerSnapshot(miner addr.Address, epoch abi.ChainEpoch, out runtime.PowerSnapshot, found bool) {
+	rt.expectPowerSnapshots = append(rt.expectPowerSnapshots, &expectPowerSnapshot{
+		miner: miner,
+		epoch: epoch,
+		out:   out,
+		found: found,
 	})
 }
 
 func (rt *Runtime) ExpectSend(toAddr addr.Address, methodNum abi.MethodNum, params cbor.Marshaler, value abi.TokenAmount, ret cbor.Er, exitCode exitcode.ExitCode) {
+	rt.ExpectSendWithGasLimit(toAddr, methodNum, params, value, ret, exitCode, 0)
+}
+
+// Expects a send that may or may not be made, but at most once. Sends made by the actor may pass over an
+// optional expectation that is not matched, which is then discarded.
+func (rt *Runtime) ExpectOptionalSend(toAddr addr.Address, methodNum abi.MethodNum, params cbor.Marshaler, value abi.TokenAmount, ret cbor.Er, exitCode exitcode.ExitCode) {
+	rt.ExpectSend(toAddr, methodNum, params, value, ret, exitCode)
+	rt.expectSends[len(rt.expectSends)-1].optional = true
+}
+
+// Expects the sends expected by f() to be made in any order, e.g. sends made while iterating a HAMT, which
+// proceeds in hash order. The group as a whole is ordered with respect to sends expected before and after it.
+func (rt *Runtime) ExpectSendAnyOrder(f func()) {
+	rt.require(rt.sendGroup == 0, "unordered send groups cannot be nested")
+	rt.sendGroupCount++
+	rt.sendGroup = rt.sendGroupCount
+	defer func() { rt.sendGroup = 0 }()
+	f()
+}
+
+// Expects a send whose params satisfy a predicate, rather than equal given params. The predicate receives
+// the params object passed by the actor, e.g. for asserting on a few fields of a large structure.
+func (rt *Runtime) ExpectSendMatching(toAddr addr.Address, methodNum abi.MethodNum, matchParams func(params cbor.Marshaler) bool, value abi.TokenAmount, ret cbor.Er, exitCode exitcode.ExitCode) {
+	rt.ExpectSend(toAddr, methodNum, nil, value, ret, exitCode)
+	rt.expectSends[len(rt.expectSends)-1].matchParams = matchParams
+}
+
+// Expects a send made with SendReadOnly.
+func (rt *Runtime) ExpectSendReadOnly(toAddr addr.Address, methodNum abi.MethodNum, params cbor.Marshaler, ret cbor.Er, exitCode exitcode.ExitCode) {
+	rt.ExpectSendWithGasLimit(toAddr, methodNum, params, big.Zero(), ret, exitCode, 0)
+	rt.expectSends[len(rt.expectSends)-1].readOnly = true
+}
+
+// Expects a send made with SendWithGasLimit and the given limit. A zero limit expects a plain Send.
+func (rt *Runtime) ExpectSendWithGasLimit(toAddr addr.Address, methodNum abi.MethodNum, params cbor.Marshaler, value abi.TokenAmount, ret cbor.Er, exitCode exitcode.ExitCode, gasLimit int64) {
 	// Adapt nil to Empty as convenience for the caller (otherwise we would require non-nil here).
 	if ret == nil {
 		ret = abi.Empty
 	}
 	rt.expectSends = append(rt.expectSends, &expectedMessage{
+		group:      rt.sendGroup,
 		to:         toAddr,
 		method:     methodNum,
 		params:     params,
 		value:      value,
+		gasLimit:   gasLimit,
 		sendReturn: ret,
 		exitCode:   exitCode,
 	})
@@ -850,6 +1438,15 @@ func (rt *Runtime) ExpectVerifySignature(sig crypto.Signature, signer addr.Addre
 	})
 }
 
+func (rt *Runtime) ExpectVerifyAggregateSignature(sig crypto.Signature, signers []addr.Address, plaintexts [][]byte, result error) {
+	rt.expectVerifyAggregateSig = &expectVerifyAggregateSig{
+		sig:        sig,
+		signers:    signers,
+		plaintexts: plaintexts,
+		result:     result,
+	}
+}
+
 func (rt *Runtime) ExpectCreateActor(codeId cid.Cid, address addr.Address) {
 	rt.expectCreateActor = &expectCreateActor{
 		codeId:  codeId,
@@ -857,8 +1454,27 @@ func (rt *Runtime) ExpectCreateActor(codeId cid.Cid, address addr.Address) {
 	}
 }
 
+// Expects creation of an actor with the given code at an address satisfying a predicate, e.g. for asserting
+// only the address's protocol or range where the exact address is incidental to the test.
+func (rt *Runtime) ExpectCreateActorMatching(codeId cid.Cid, matchAddress func(addr.Address) bool) {
+	rt.expectCreateActor = &expectCreateActor{
+		codeId:       codeId,
+		matchAddress: matchAddress,
+	}
+}
+
+// Expects the receiver to delete itself, passing its balance to the beneficiary.
 func (rt *Runtime) ExpectDeleteActor(beneficiary addr.Address) {
-	rt.expectDeleteActor = &beneficiary
+	rt.expectDeleteActor = &expectDeleteActor{beneficiary: beneficiary}
+}
+
+// Expects the receiver to delete itself, passing its balance to a beneficiary satisfying a predicate.
+func (rt *Runtime) ExpectDeleteActorMatching(matchBeneficiary func(addr.Address) bool) {
+	rt.expectDeleteActor = &expectDeleteActor{matchBeneficiary: matchBeneficiary}
+}
+
+func (rt *Runtime) ExpectUpgradeActor(newCodeID cid.Cid) {
+	rt.expectUpgradeActor = &newCodeID
 }
 
 func (rt *Runtime) SetHasher(f func(data []byte) [32]byte) {
@@ -872,6 +1488,14 @@ func (rt *Runtime) ExpectVerifySeal(seal proof.SealVerifyInfo, result error) {
 	}
 }
 
+// Expects a seal verification satisfying a predicate, e.g. on the sector number and randomness epochs only.
+func (rt *Runtime) ExpectVerifySealMatching(match func(seal proof.SealVerifyInfo) bool, result error) {
+	rt.expectVerifySeal = &expectVerifySeal{
+		match:  match,
+		result: result,
+	}
+}
+
 func (rt *Runtime) ExpectComputeUnsealedSectorCID(reg abi.RegisteredSealProof, pieces []abi.PieceInfo, cid cid.Cid, err error) {
 	rt.expectComputeUnsealedSectorCID = &expectComputeUnsealedSectorCID{
 		reg, pieces, cid, err,
@@ -885,6 +1509,14 @@ func (rt *Runtime) ExpectVerifyPoSt(post proof.WindowPoStVerifyInfo, result erro
 	}
 }
 
+// Expects a PoSt verification satisfying a predicate, e.g. on the challenged sector numbers only.
+func (rt *Runtime) ExpectVerifyPoStMatching(match func(post proof.WindowPoStVerifyInfo) bool, result error) {
+	rt.expectVerifyPoSt = &expectVerifyPoSt{
+		match:  match,
+		result: result,
+	}
+}
+
 func (rt *Runtime) ExpectVerifyConsensusFault(h1, h2, extra []byte, result *runtime.ConsensusFault, resultErr error) {
 	rt.expectVerifyConsensusFault = &expectVerifyConsensusFault{
 		requireCorrectInput: true,
@@ -898,6 +1530,18 @@ func (rt *Runtime) ExpectVerifyConsensusFault(h1, h2, extra []byte, result *runt
 
 // Verifies that expected calls were received, and resets all expectations.
 func (rt *Runtime) Verify() {
+	rt.t.Helper()
+	rt.verifyExpectations()
+	if len(rt.injectedFailures) > 0 {
+		rt.failTest("injected syscall failures not triggered %v", rt.injectedFailures)
+	}
+	rt.verifyNetTransfers()
+
+	rt.Reset()
+}
+
+// Verifies that the calls expected of the current invocation were received.
+func (rt *Runtime) verifyExpectations() {
 	rt.t.Helper()
 	if rt.expectValidateCallerAny {
 		rt.failTest("expected ValidateCallerAny, not received")
@@ -908,21 +1552,33 @@ func (rt *Runtime) Verify() {
 	if len(rt.expectValidateCallerType) > 0 {
 		rt.failTest("missing expected ValidateCallerType %v", rt.expectValidateCallerType)
 	}
-	if len(rt.expectRandomnessBeacon) > 0 {
-		rt.failTest("missing expected beacon randomness %v", rt.expectRandomnessBeacon)
+	if len(rt.expectRandomness) > 0 {
+		rt.failTest("missing expected randomness %v", rt.expectRandomness)
 	}
-	if len(rt.expectRandomnessTickets) > 0 {
-		rt.failTest("missing expected ticket randomness %v", rt.expectRandomnessTickets)
+	if len(rt.expectBeaconEntries) > 0 {
+		rt.failTest("missing expected beacon entries %v", rt.expectBeaconEntries)
 	}
-	if len(rt.expectSends) > 0 {
-		rt.failTest("missing expected send %v", rt.expectSends)
+	if len(rt.expectPowerSnapshots) > 0 {
+		rt.failTest("missing expected power snapshots %v", rt.expectPowerSnapshots)
+	}
+	var missingSends []*expectedMessage
+	for _, exp := range rt.expectSends {
+		if !exp.optional {
+			missingSends = append(missingSends, exp)
+		}
+	}
+	if len(missingSends) > 0 {
+		rt.failTest("missing expected send %v", missingSends)
 	}
 	if len(rt.expectVerifySigs) > 0 {
 		rt.failTest("missing expected verify signature %v", rt.expectVerifySigs)
 	}
+	if rt.expectVerifyAggregateSig != nil {
+		rt.failTest("missing expected verify aggregate signature %v", rt.expectVerifyAggregateSig.sig)
+	}
 	if rt.expectCreateActor != nil {
-		rt.failTest("missing expected create actor with code %s, address %s",
-			rt.expectCreateActor.codeId, rt.expectCreateActor.address)
+		rt.failTest("missing expected create actor with code %s, address %v",
+			rt.expectCreateActor.codeId, rt.expectCreateActor.describeAddress())
 	}
 
 	if rt.expectVerifySeal != nil {
@@ -933,6 +1589,18 @@ func (rt *Runtime) Verify() {
 		rt.failTest("missing expected batch verify seals with %v", rt.expectBatchVerifySeals)
 	}
 
+	if rt.expectBatchVerifyPoSts != nil {
+		rt.failTest("missing expected batch verify PoSts with %v", rt.expectBatchVerifyPoSts.in)
+	}
+
+	if rt.expectAggregateVerifySeals != nil {
+		rt.failTest("missing expected aggregate verify seals with %v", rt.expectAggregateVerifySeals.in)
+	}
+
+	if rt.expectReplicaUpdate != nil {
+		rt.failTest("missing expected replica update verification with %v", rt.expectReplicaUpdate.in)
+	}
+
 	if rt.expectComputeUnsealedSectorCID != nil {
 		rt.failTest("missing expected ComputeUnsealedSectorCID with %v", rt.expectComputeUnsealedSectorCID)
 	}
@@ -941,10 +1609,17 @@ func (rt *Runtime) Verify() {
 		rt.failTest("missing expected verify consensus fault")
 	}
 	if rt.expectDeleteActor != nil {
-		rt.failTest("missing expected delete actor with address %s", rt.expectDeleteActor.String())
+		rt.failTest("missing expected delete actor with beneficiary %v", rt.expectDeleteActor.describeBeneficiary())
+	}
+	if rt.expectUpgradeActor != nil {
+		rt.failTest("missing expected upgrade actor to %v", rt.expectUpgradeActor)
+	}
+	if len(rt.expectEvents) > 0 {
+		rt.failTest("missing expected event %s (and %d more)", rt.expectEvents[0].Key, len(rt.expectEvents)-1)
+	}
+	if len(rt.expectNestedCalls) > 0 {
+		rt.failTest("missing %d expected nested call(s)", len(rt.expectNestedCalls))
 	}
-
-	rt.Reset()
 }
 
 // Resets expectations
@@ -952,14 +1627,24 @@ func (rt *Runtime) Reset() {
 	rt.expectValidateCallerAny = false
 	rt.expectValidateCallerAddr = nil
 	rt.expectValidateCallerType = nil
-	rt.expectRandomnessBeacon = nil
-	rt.expectRandomnessTickets = nil
+	rt.expectRandomness = nil
+	rt.expectBeaconEntries = nil
+	rt.expectPowerSnapshots = nil
 	rt.expectSends = nil
 	rt.expectCreateActor = nil
 	rt.expectVerifySigs = nil
+	rt.expectVerifyAggregateSig = nil
 	rt.expectVerifySeal = nil
 	rt.expectBatchVerifySeals = nil
+	rt.expectBatchVerifyPoSts = nil
+	rt.expectAggregateVerifySeals = nil
+	rt.expectReplicaUpdate = nil
 	rt.expectComputeUnsealedSectorCID = nil
+	rt.injectedFailures = nil
+	rt.expectEvents = nil
+	rt.expectNestedCalls = nil
+	rt.transfers = nil
+	rt.expectNetTransfers = nil
 }
 
 // Calls f() expecting it to invoke Runtime.Abortf() with a specified exit code.
@@ -969,6 +1654,41 @@ func (rt *Runtime) ExpectAbort(expected exitcode.ExitCode, f func()) {
 
 // Calls f() expecting it to invoke Runtime.Abortf() with a specified exit code and message.
 func (rt *Runtime) ExpectAbortContainsMessage(expected exitcode.ExitCode, substr string, f func()) {
+	rt.t.Helper()
+	rt.expectAbort(expected, substr, nil, f)
+}
+
+// Calls f() expecting it to invoke Runtime.AbortWithData() with a specified exit code, and decodes the data into
+// out, e.g. a *builtin.SectorsFailure, for the test to inspect.
+func (rt *Runtime) ExpectAbortDecodingData(expected exitcode.ExitCode, out cbor.Unmarshaler, f func()) {
+	rt.t.Helper()
+	rt.expectAbort(expected, "", func(data []byte) {
+		if len(data) == 0 {
+			rt.failTest("abort expected data, got none")
+			return
+		}
+		if err := out.UnmarshalCBOR(bytes.NewReader(data)); err != nil {
+			rt.failTest("abort data %x does not decode as %T: %v", data, out, err)
+		}
+	}, f)
+}
+
+// Calls f() expecting it to invoke Runtime.AbortWithData() with a specified exit code and data.
+func (rt *Runtime) ExpectAbortWithData(expected exitcode.ExitCode, data cbor.Marshaler, f func()) {
+	rt.t.Helper()
+	var buf bytes.Buffer
+	if err := data.MarshalCBOR(&buf); err != nil {
+		rt.failTestNow("failed to serialize expected abort data: %v", err)
+	}
+	rt.expectAbort(expected, "", func(data []byte) {
+		if !bytes.Equal(data, buf.Bytes()) {
+			rt.failTest("abort expected data\n%x\ngot\n%x", buf.Bytes(), data)
+		}
+	}, f)
+}
+
+// Calls f() expecting an abort with a code, a message containing substr, and data checked by checkData if not nil.
+func (rt *Runtime) expectAbort(expected exitcode.ExitCode, substr string, checkData func(data []byte), f func()) {
 	rt.t.Helper()
 	prevState := rt.state
 
@@ -991,6 +1711,9 @@ func (rt *Runtime) ExpectAbortContainsMessage(expected exitcode.ExitCode, substr
 				rt.failTest("abort expected message\n'%s'\nto contain\n'%s'\n", a.msg, substr)
 			}
 		}
+		if checkData != nil {
+			checkData(a.data)
+		}
 		// Roll back state change.
 		rt.state = prevState
 	}()
@@ -1038,12 +1761,20 @@ func (rt *Runtime) ClearLogs() {
 	rt.logs = []string{}
 }
 
+// Fails the test unless the total gas charged, explicitly and for priced operations, is exactly `gas`.
 func (rt *Runtime) ExpectGasCharged(gas int64) {
 	if gas != rt.gasCharged {
 		rt.failTest("expected gas charged: %d, actual gas charged: %d", gas, rt.gasCharged)
 	}
 }
 
+// Fails the test if the gas charged so far is not less than `max`.
+func (rt *Runtime) ExpectGasLessThan(max int64) {
+	if rt.gasCharged >= max {
+		rt.failTest("expected gas charged less than %d, actual gas charged: %d", max, rt.gasCharged)
+	}
+}
+
 func (rt *Runtime) Call(method interface{}, params interface{}) interface{} {
 	meth := reflect.ValueOf(method)
 	rt.verifyExportedMethodType(meth)
@@ -1052,7 +1783,15 @@ func (rt *Runtime) Call(method interface{}, params interface{}) interface{} {
 	// If not expected, the panic will escape and cause the test to fail.
 
 	rt.inCall = true
-	defer func() { rt.inCall = false }()
+	rt.events = nil
+	rt.traceEntries = nil
+	rt.callTransfers = nil
+	rt.deleted = false
+	chargedBefore := rt.gasCharged
+	defer func() {
+		rt.inCall = false
+		rt.recordCallGas(meth, chargedBefore)
+	}()
 	var arg reflect.Value
 	if params != nil {
 		arg = reflect.ValueOf(params)
@@ -1060,9 +1799,19 @@ func (rt *Runtime) Call(method interface{}, params interface{}) interface{} {
 		arg = reflect.ValueOf(abi.Empty)
 	}
 	ret := meth.Call([]reflect.Value{reflect.ValueOf(rt), arg})
+	rt.inCall = false
+	rt.commitTransfers()
+	rt.checkInvariants()
 	return ret[0].Interface()
 }
 
+// Calls a method as if invoked with SendReadOnly, so that any attempt to mutate state or transfer value aborts.
+func (rt *Runtime) CallReadOnly(method interface{}, params interface{}) interface{} {
+	rt.readOnly = true
+	defer func() { rt.readOnly = false }()
+	return rt.Call(method, params)
+}
+
 func (rt *Runtime) verifyExportedMethodType(meth reflect.Value) {
 	rt.t.Helper()
 	t := meth.Type()
@@ -1075,6 +1824,12 @@ func (rt *Runtime) verifyExportedMethodType(meth reflect.Value) {
 	rt.require(t.Out(0).Implements(typeOfCborMarshaler), "exported method must return CBOR-marshalable value")
 }
 
+func (rt *Runtime) abortIfReadOnly(operation string) {
+	if rt.readOnly {
+		rt.Abortf(exitcode.SysErrForbidden, "%s not permitted in read-only context", operation)
+	}
+}
+
 func (rt *Runtime) requireInCall() {
 	rt.t.Helper()
 	rt.require(rt.inCall, "invalid runtime invocation outside of method call")
@@ -1090,6 +1845,7 @@ func (rt *Runtime) require(predicate bool, msg string, args ...interface{}) {
 func (rt *Runtime) failTest(msg string, args ...interface{}) {
 	rt.t.Helper()
 	rt.t.Logf(msg, args...)
+	rt.logTrace()
 	rt.t.Logf("%s", debug.Stack())
 	rt.t.Fail()
 }
@@ -1097,12 +1853,29 @@ func (rt *Runtime) failTest(msg string, args ...interface{}) {
 func (rt *Runtime) failTestNow(msg string, args ...interface{}) {
 	rt.t.Helper()
 	rt.t.Logf(msg, args...)
+	rt.logTrace()
 	rt.t.Logf("%s", debug.Stack())
 	rt.t.FailNow()
 }
 
 func (rt *Runtime) ChargeGas(_ string, gas, _ int64) {
-	rt.gasCharged += gas
+	rt.charge(gas)
+}
+
+func (rt *Runtime) GasAvailable() int64 {
+	return rt.gasLimit - rt.gasCharged
+}
+
+func (rt *Runtime) GasUsed() int64 {
+	return rt.gasCharged
+}
+
+func (rt *Runtime) SuggestedBatchLimit(work runtime.BatchWork) runtime.BatchLimit {
+	rt.requireInCall()
+	if limit, ok := rt.batchLimits[work]; ok {
+		return limit
+	}
+	return runtime.DefaultBatchLimit(work, rt.GasAvailable())
 }
 
 func getMethodName(code cid.Cid, num abi.MethodNum) string {
@@ -0,0 +1,930 @@
+package mock_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/cbor"
+	"github.com/filecoin-project/go-state-types/crypto"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	cbg "github.com/whyrusleeping/cbor-gen"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v2/actors/runtime"
+	"github.com/filecoin-project/specs-actors/v2/actors/runtime/proof"
+	"github.com/filecoin-project/specs-actors/v2/support/mock"
+	"github.com/filecoin-project/specs-actors/v2/support/puppet"
+	tutil "github.com/filecoin-project/specs-actors/v2/support/testing"
+)
+
+// A minimal actor whose state is a counter, exercising nested state transactions.
+type counterActor struct{}
+
+func (counterActor) Construct(rt runtime.Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
+	st := cbg.CborInt(0)
+	rt.StateCreate(&st)
+	return nil
+}
+
+// Increments the counter by one, around a nested transaction that increments it by two.
+func (counterActor) IncrementFour(rt runtime.Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
+	var st cbg.CborInt
+	rt.StateTransaction(&st, func() {
+		st++
+		incrementTwo(rt)
+		st++
+	})
+	return nil
+}
+
+// Increments the counter in a nested transaction, then aborts.
+func (counterActor) IncrementAndAbort(rt runtime.Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
+	var st cbg.CborInt
+	rt.StateTransaction(&st, func() {
+		st++
+		incrementTwo(rt)
+		rt.Abortf(exitcode.ErrIllegalState, "abort")
+	})
+	return nil
+}
+
+// Attempts a send after a nested transaction returns, but within the enclosing one.
+func (counterActor) SendAfterNested(rt runtime.Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
+	var st cbg.CborInt
+	rt.StateTransaction(&st, func() {
+		incrementTwo(rt)
+		rt.Send(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, big.Zero(), &builtin.Discard{})
+	})
+	return nil
+}
+
+// A helper with its own transactional semantics, which observes the enclosing transaction's changes.
+func incrementTwo(rt runtime.Runtime) {
+	var before cbg.CborInt
+	rt.StateReadonly(&before)
+	var st cbg.CborInt
+	rt.StateTransaction(&st, func() {
+		if st != before {
+			rt.Abortf(exitcode.ErrIllegalState, "nested transaction state %d differs from readonly state %d", st, before)
+		}
+		st += 2
+	})
+}
+
+func TestNestedStateTransactions(t *testing.T) {
+	actor := counterActor{}
+	receiver := tutil.NewIDAddr(t, 100)
+	builder := mock.NewBuilder(context.Background(), receiver)
+
+	setup := func(t *testing.T) *mock.Runtime {
+		rt := builder.Build(t)
+		rt.Call(actor.Construct, nil)
+		return rt
+	}
+	counter := func(rt *mock.Runtime) cbg.CborInt {
+		var st cbg.CborInt
+		rt.GetState(&st)
+		return st
+	}
+
+	t.Run("nested changes are visible to and committed with the enclosing transaction", func(t *testing.T) {
+		rt := setup(t)
+		rt.Call(actor.IncrementFour, nil)
+		rt.Verify()
+		assert.Equal(t, cbg.CborInt(4), counter(rt))
+
+		rt.Call(actor.IncrementFour, nil)
+		assert.Equal(t, cbg.CborInt(8), counter(rt))
+	})
+
+	t.Run("abort discards nested and enclosing changes", func(t *testing.T) {
+		rt := setup(t)
+		rt.ExpectAbort(exitcode.ErrIllegalState, func() {
+			rt.Call(actor.IncrementAndAbort, nil)
+		})
+		assert.Equal(t, cbg.CborInt(0), counter(rt))
+
+		// The runtime is usable for subsequent transactions.
+		rt.Call(actor.IncrementFour, nil)
+		assert.Equal(t, cbg.CborInt(4), counter(rt))
+	})
+
+	t.Run("side effects are prohibited until the outermost transaction returns", func(t *testing.T) {
+		rt := setup(t)
+		rt.ExpectAbort(exitcode.SysErrorIllegalActor, func() {
+			rt.Call(actor.SendAfterNested, nil)
+		})
+		assert.Equal(t, cbg.CborInt(0), counter(rt))
+	})
+}
+
+func TestStateInvariants(t *testing.T) {
+	actor := counterActor{}
+	receiver := tutil.NewIDAddr(t, 100)
+
+	t.Run("checks state loaded from the receiver after each call", func(t *testing.T) {
+		var checked []cbg.CborInt
+		check := func(st *cbg.CborInt, epoch abi.ChainEpoch) (*builtin.MessageAccumulator, error) {
+			checked = append(checked, *st)
+			assert.Equal(t, abi.ChainEpoch(5), epoch)
+			return &builtin.MessageAccumulator{}, nil
+		}
+		rt := mock.NewBuilder(context.Background(), receiver).WithEpoch(5).WithStateInvariants(check).Build(t)
+		rt.Call(actor.Construct, nil)
+		rt.Call(actor.IncrementFour, nil)
+		assert.Equal(t, []cbg.CborInt{0, 4}, checked)
+	})
+
+	t.Run("rejects a function of another form", func(t *testing.T) {
+		assert.Panics(t, func() { mock.StateInvariants(func(st cbg.CborInt) *builtin.MessageAccumulator { return nil }) })
+		assert.Panics(t, func() { mock.StateInvariants(func(st *cbg.CborInt, n int) *builtin.MessageAccumulator { return nil }) })
+		assert.Panics(t, func() { mock.StateInvariants(func(st *cbg.CborInt) error { return nil }) })
+	})
+}
+
+func TestExpectAggregateVerifySeals(t *testing.T) {
+	receiver := tutil.NewIDAddr(t, 100)
+	builder := mock.NewBuilder(context.Background(), receiver)
+
+	aggregate := proof.AggregateSealVerifyProofAndInfos{
+		Miner:          100,
+		SealProof:      abi.RegisteredSealProof_StackedDrg32GiBV1,
+		AggregateProof: proof.RegisteredAggregationProof_SnarkPackV1,
+		Proof:          []byte{1, 2, 3},
+		Infos: []proof.AggregateSealVerifyInfo{{
+			Number:      1,
+			SealedCID:   tutil.MakeCID("commr", nil),
+			UnsealedCID: tutil.MakeCID("commd", nil),
+		}},
+	}
+	verify := func(rt runtime.Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
+		if err := rt.VerifyAggregateSeals(aggregate); err != nil {
+			rt.Abortf(exitcode.ErrIllegalArgument, "invalid aggregate proof: %s", err)
+		}
+		return nil
+	}
+
+	t.Run("returns expected result", func(t *testing.T) {
+		rt := builder.Build(t)
+		rt.ExpectAggregateVerifySeals(aggregate, nil)
+		rt.Call(verify, nil)
+		rt.Verify()
+
+		rt.ExpectAggregateVerifySeals(aggregate, xerrors.New("invalid"))
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(verify, nil)
+		})
+		rt.Verify()
+	})
+}
+
+func TestExpectReplicaUpdate(t *testing.T) {
+	receiver := tutil.NewIDAddr(t, 100)
+	builder := mock.NewBuilder(context.Background(), receiver)
+
+	replica := proof.ReplicaUpdateInfo{
+		UpdateProofType:      proof.RegisteredUpdateProof_StackedDrg32GiBV1,
+		OldSealedSectorCID:   tutil.MakeCID("oldcommr", nil),
+		NewSealedSectorCID:   tutil.MakeCID("newcommr", nil),
+		NewUnsealedSectorCID: tutil.MakeCID("newcommd", nil),
+		Proof:                []byte{1, 2, 3},
+	}
+	verify := func(rt runtime.Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
+		if err := rt.VerifyReplicaUpdate(replica); err != nil {
+			rt.Abortf(exitcode.ErrIllegalArgument, "invalid replica update proof: %s", err)
+		}
+		return nil
+	}
+
+	t.Run("returns expected result", func(t *testing.T) {
+		rt := builder.Build(t)
+		rt.ExpectReplicaUpdate(replica, nil)
+		rt.Call(verify, nil)
+		rt.Verify()
+
+		rt.ExpectReplicaUpdate(replica, xerrors.New("invalid"))
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(verify, nil)
+		})
+		rt.Verify()
+	})
+}
+
+func TestExpectBatchVerifyPoSts(t *testing.T) {
+	receiver := tutil.NewIDAddr(t, 100)
+	builder := mock.NewBuilder(context.Background(), receiver)
+
+	posts := []proof.WindowPoStVerifyInfo{
+		{Prover: 100, ChallengedSectors: []proof.SectorInfo{{SectorNumber: 1}, {SectorNumber: 2}}},
+		{Prover: 100, ChallengedSectors: []proof.SectorInfo{{SectorNumber: 3}}},
+	}
+	var verified []bool
+	verify := func(rt runtime.Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
+		var err error
+		verified, err = rt.BatchVerifyPoSts(posts)
+		if err != nil {
+			rt.Abortf(exitcode.ErrIllegalState, "failed to verify PoSts: %s", err)
+		}
+		return nil
+	}
+
+	t.Run("returns expected result", func(t *testing.T) {
+		rt := builder.Build(t)
+		rt.ExpectBatchVerifyPoSts(posts, []bool{true, false}, nil)
+		rt.Call(verify, nil)
+		rt.Verify()
+		assert.Equal(t, []bool{true, false}, verified)
+
+		rt.ExpectBatchVerifyPoSts(posts, nil, xerrors.New("failed"))
+		rt.ExpectAbort(exitcode.ErrIllegalState, func() {
+			rt.Call(verify, nil)
+		})
+		rt.Verify()
+	})
+
+	t.Run("charges for every challenged sector", func(t *testing.T) {
+		rt := builder.Build(t)
+		rt.SetGasCharger(syscallCharger{})
+		rt.ExpectBatchVerifyPoSts(posts, []bool{true, true}, nil)
+		rt.Call(verify, nil)
+		rt.Verify()
+		assert.Equal(t, int64(3), rt.LastCallGas())
+	})
+}
+
+func TestExpectAbortWithData(t *testing.T) {
+	receiver := tutil.NewIDAddr(t, 100)
+	builder := mock.NewBuilder(context.Background(), receiver)
+
+	fail := func(rt runtime.Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
+		data := cbg.CborInt(42)
+		rt.AbortWithData(exitcode.ErrIllegalArgument, &data, "failed with %d", data)
+		return nil
+	}
+
+	rt := builder.Build(t)
+	expected := cbg.CborInt(42)
+	rt.ExpectAbortWithData(exitcode.ErrIllegalArgument, &expected, func() {
+		rt.Call(fail, nil)
+	})
+	rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "failed with 42", func() {
+		rt.Call(fail, nil)
+	})
+	var decoded cbg.CborInt
+	rt.ExpectAbortDecodingData(exitcode.ErrIllegalArgument, &decoded, func() {
+		rt.Call(fail, nil)
+	})
+	assert.Equal(t, expected, decoded)
+	rt.Verify()
+}
+
+func TestGasSimulation(t *testing.T) {
+	actor := counterActor{}
+	receiver := tutil.NewIDAddr(t, 100)
+	prices := mock.PriceList{StoreGetBase: 10, StorePutBase: 100, StorePutPerByte: 1, SendBase: 1000, SendTransferFunds: 500}
+	builder := mock.NewBuilder(context.Background(), receiver).WithPriceList(prices)
+
+	// A counter below 24 serializes to a single byte.
+	t.Run("charges state reads and writes", func(t *testing.T) {
+		rt := builder.Build(t)
+		rt.Call(actor.Construct, nil)
+		assert.Equal(t, int64(101), rt.LastCallGas())
+
+		// The outer transaction reads and writes state once. Nested reads and transactions work on the
+		// enclosing transaction's state without touching the store.
+		rt.Call(actor.IncrementFour, nil)
+		assert.Equal(t, int64(10+101), rt.LastCallGas())
+		rt.Call(actor.IncrementFour, nil)
+
+		assert.Equal(t, map[string]int64{"Construct": 101, "IncrementFour": 2 * 111}, rt.GasByMethod())
+		rt.ExpectGasCharged(101 + 2*111)
+		rt.ExpectGasLessThan(101 + 2*111 + 1)
+		rt.ExpectLastCallGasAtMost(111)
+	})
+
+	t.Run("charges sends", func(t *testing.T) {
+		rt := builder.Build(t)
+		rt.SetBalance(big.NewInt(10))
+		send := func(rt runtime.Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
+			rt.Send(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, big.NewInt(1), &builtin.Discard{})
+			return nil
+		}
+		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, big.NewInt(1), nil, exitcode.Ok)
+		rt.Call(send, nil)
+		rt.Verify()
+		assert.Equal(t, int64(1500), rt.LastCallGas())
+	})
+
+	t.Run("charges syscalls with a custom charger", func(t *testing.T) {
+		rt := builder.Build(t)
+		rt.SetGasCharger(syscallCharger{})
+		post := proof.WindowPoStVerifyInfo{ChallengedSectors: make([]proof.SectorInfo, 3)}
+		verify := func(rt runtime.Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
+			_ = rt.VerifyPoSt(post)
+			return nil
+		}
+		rt.ExpectVerifyPoSt(post, nil)
+		rt.Call(verify, nil)
+		rt.Verify()
+		assert.Equal(t, int64(3), rt.LastCallGas())
+	})
+
+	t.Run("aborts when out of gas", func(t *testing.T) {
+		rt := builder.Build(t)
+		rt.SetGasAvailable(100)
+		rt.ExpectAbort(exitcode.SysErrOutOfGas, func() {
+			rt.Call(actor.Construct, nil)
+		})
+		assert.Equal(t, int64(101), rt.LastCallGas())
+	})
+}
+
+// Charges only for syscalls, one unit per item.
+type syscallCharger struct{}
+
+func (syscallCharger) OnStoreGet(int) int64                      { return 0 }
+func (syscallCharger) OnStorePut(int) int64                      { return 0 }
+func (syscallCharger) OnSend(bool) int64                         { return 0 }
+func (syscallCharger) OnSyscall(_ mock.Syscall, items int) int64 { return int64(items) }
+
+func TestSnapshotRevert(t *testing.T) {
+	actor := counterActor{}
+	receiver := tutil.NewIDAddr(t, 100)
+	rt := mock.NewBuilder(context.Background(), receiver).Build(t)
+	counter := func() cbg.CborInt {
+		var st cbg.CborInt
+		rt.GetState(&st)
+		return st
+	}
+
+	rt.Call(actor.Construct, nil)
+	rt.Call(actor.IncrementFour, nil)
+	rt.SetEpoch(10)
+	rt.SetBalance(big.NewInt(5))
+	prepared := rt.Snapshot()
+
+	for i := 0; i < 3; i++ {
+		rt.Revert(prepared)
+		assert.Equal(t, cbg.CborInt(4), counter())
+		assert.Equal(t, abi.ChainEpoch(10), rt.Epoch())
+		assert.Equal(t, big.NewInt(5), rt.Balance())
+
+		for j := 0; j <= i; j++ {
+			rt.Call(actor.IncrementFour, nil)
+		}
+		rt.SetEpoch(20)
+		rt.SetBalance(big.Zero())
+		rt.AddIDAddress(tutil.NewBLSAddr(t, 1), tutil.NewIDAddr(t, 101))
+		assert.Equal(t, cbg.CborInt(4*(i+2)), counter())
+	}
+
+	// Reverting discards outstanding expectations and address mappings made since the snapshot.
+	rt.ExpectValidateCallerAny()
+	rt.Revert(prepared)
+	_, found := rt.GetIdAddr(tutil.NewBLSAddr(t, 1))
+	assert.False(t, found)
+	rt.Verify()
+
+	// Snapshots nest.
+	rt.Call(actor.IncrementFour, nil)
+	later := rt.Snapshot()
+	rt.Call(actor.IncrementFour, nil)
+	rt.Revert(later)
+	assert.Equal(t, cbg.CborInt(8), counter())
+	rt.Revert(prepared)
+	assert.Equal(t, cbg.CborInt(4), counter())
+}
+
+func TestSendExpectations(t *testing.T) {
+	receiver := tutil.NewIDAddr(t, 100)
+	builder := mock.NewBuilder(context.Background(), receiver).WithBalance(big.NewInt(100), big.Zero())
+	a, b, c := tutil.NewIDAddr(t, 101), tutil.NewIDAddr(t, 102), tutil.NewIDAddr(t, 103)
+
+	sendTo := func(addrs ...address.Address) func(rt runtime.Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
+		return func(rt runtime.Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
+			for _, to := range addrs {
+				rt.Send(to, builtin.MethodSend, nil, big.NewInt(1), &builtin.Discard{})
+			}
+			return nil
+		}
+	}
+	expectSend := func(rt *mock.Runtime, to address.Address) {
+		rt.ExpectSend(to, builtin.MethodSend, nil, big.NewInt(1), nil, exitcode.Ok)
+	}
+
+	t.Run("unordered group matches sends in any order", func(t *testing.T) {
+		rt := builder.Build(t)
+		rt.ExpectSendAnyOrder(func() {
+			expectSend(rt, a)
+			expectSend(rt, b)
+		})
+		expectSend(rt, c)
+		rt.Call(sendTo(b, a, c), nil)
+		rt.Verify()
+	})
+
+	t.Run("optional sends may be omitted", func(t *testing.T) {
+		rt := builder.Build(t)
+		rt.ExpectOptionalSend(a, builtin.MethodSend, nil, big.NewInt(1), nil, exitcode.Ok)
+		expectSend(rt, b)
+		rt.ExpectOptionalSend(c, builtin.MethodSend, nil, big.NewInt(1), nil, exitcode.Ok)
+		rt.Call(sendTo(b), nil)
+		rt.Verify()
+
+		rt.ExpectOptionalSend(a, builtin.MethodSend, nil, big.NewInt(1), nil, exitcode.Ok)
+		expectSend(rt, b)
+		rt.Call(sendTo(a, b), nil)
+		rt.Verify()
+	})
+
+	t.Run("optional sends within an unordered group", func(t *testing.T) {
+		rt := builder.Build(t)
+		rt.ExpectSendAnyOrder(func() {
+			rt.ExpectOptionalSend(a, builtin.MethodSend, nil, big.NewInt(1), nil, exitcode.Ok)
+			expectSend(rt, b)
+		})
+		expectSend(rt, c)
+		rt.Call(sendTo(b, c), nil)
+		rt.Verify()
+	})
+}
+
+func TestEventExpectations(t *testing.T) {
+	receiver := tutil.NewIDAddr(t, 100)
+	builder := mock.NewBuilder(context.Background(), receiver)
+	a, b, c := tutil.NewIDAddr(t, 101), tutil.NewIDAddr(t, 102), tutil.NewIDAddr(t, 103)
+
+	emitTransfers := func(rt runtime.Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
+		for _, to := range []address.Address{a, b, c} {
+			builtin.EmitTransfer(rt, to, big.NewInt(1))
+		}
+		return nil
+	}
+
+	t.Run("expected events match in order among others", func(t *testing.T) {
+		rt := builder.Build(t)
+		rt.ExpectEvent(builtin.EventKeyTransfer, &builtin.TransferEvent{To: a, Amount: big.NewInt(1)})
+		rt.ExpectEvent(builtin.EventKeyTransfer, &builtin.TransferEvent{To: c, Amount: big.NewInt(1)})
+		rt.Call(emitTransfers, nil)
+		rt.Verify()
+		assert.Len(t, rt.EmittedEvents(), 3)
+	})
+}
+
+func TestTrace(t *testing.T) {
+	actor := counterActor{}
+	receiver := tutil.NewIDAddr(t, 100)
+	builder := mock.NewBuilder(context.Background(), receiver).WithBalance(big.NewInt(100), big.Zero())
+
+	ops := func(entries []mock.TraceEntry) []string {
+		var out []string
+		for _, e := range entries {
+			out = append(out, e.Kind.String()+" "+e.Op)
+		}
+		return out
+	}
+
+	t.Run("records nothing unless enabled", func(t *testing.T) {
+		rt := builder.Build(t)
+		rt.Call(actor.Construct, nil)
+		assert.Empty(t, rt.Trace())
+	})
+
+	t.Run("records state access of the most recent call", func(t *testing.T) {
+		rt := builder.Build(t)
+		rt.SetTracing(true)
+		rt.Call(actor.Construct, nil)
+		assert.Equal(t, []string{"store-put StorePut", "state-write StateCreate"}, ops(rt.Trace()))
+
+		// Nested transactions read the enclosing transaction's state, and only the outer one writes.
+		rt.Call(actor.IncrementFour, nil)
+		assert.Equal(t, []string{
+			"state-read StateReadonly",
+			"store-get StoreGet",
+			"state-read StateReadonly",
+			"state-read StateReadonly",
+			"store-put StorePut",
+			"state-write StateTransaction",
+		}, ops(rt.Trace()))
+	})
+
+	t.Run("records sends and syscalls", func(t *testing.T) {
+		rt := builder.Build(t)
+		rt.SetTracing(true)
+		to := tutil.NewIDAddr(t, 101)
+		rt.ExpectSend(to, builtin.MethodSend, nil, big.NewInt(1), nil, exitcode.Ok)
+		rt.Call(func(rt runtime.Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
+			rt.Send(to, builtin.MethodSend, nil, big.NewInt(1), &builtin.Discard{})
+			rt.HashBlake2b([]byte{1})
+			return nil
+		}, nil)
+		rt.Verify()
+
+		trace := rt.Trace()
+		assert.Equal(t, []string{"send Send", "syscall HashBlake2b"}, ops(trace))
+		assert.Contains(t, trace[0].Detail, to.String())
+	})
+}
+
+func TestProgrammedRandomness(t *testing.T) {
+	receiver := tutil.NewIDAddr(t, 100)
+	builder := mock.NewBuilder(context.Background(), receiver).WithEpoch(100).WithRandomness(mock.DeterministicRandomness)
+	tag := crypto.DomainSeparationTag_WindowedPoStChallengeSeed
+
+	var got []abi.Randomness
+	drawAt := func(epochs ...abi.ChainEpoch) func(rt runtime.Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
+		return func(rt runtime.Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
+			for _, epoch := range epochs {
+				got = append(got, rt.GetRandomness(runtime.RandomnessBeacon, tag, epoch, []byte{1}))
+			}
+			return nil
+		}
+	}
+
+	t.Run("programmed randomness answers requests in any order", func(t *testing.T) {
+		rt := builder.Build(t)
+		got = nil
+		rt.Call(drawAt(90, 80, 90), nil)
+		rt.Verify()
+
+		assert.Equal(t, mock.DeterministicRandomness(runtime.RandomnessBeacon, tag, 90, []byte{1}), got[0])
+		assert.Equal(t, got[0], got[2])
+		assert.NotEqual(t, got[0], got[1])
+		assert.NotEqual(t, got[0], mock.DeterministicRandomness(runtime.RandomnessTickets, tag, 90, []byte{1}))
+		assert.NotEqual(t, got[0], mock.DeterministicRandomness(runtime.RandomnessBeacon, tag, 90, []byte{2}))
+	})
+
+	t.Run("queued expectations take precedence", func(t *testing.T) {
+		rt := builder.Build(t)
+		got = nil
+		rt.ExpectGetRandomness(runtime.RandomnessBeacon, tag, 90, []byte{1}, abi.Randomness("expected"))
+		rt.Call(drawAt(90, 90), nil)
+		rt.Verify()
+
+		assert.Equal(t, abi.Randomness("expected"), got[0])
+		assert.Equal(t, mock.DeterministicRandomness(runtime.RandomnessBeacon, tag, 90, []byte{1}), got[1])
+	})
+}
+
+func TestExpectationMatchers(t *testing.T) {
+	receiver := tutil.NewIDAddr(t, 100)
+	builder := mock.NewBuilder(context.Background(), receiver).WithBalance(big.NewInt(100), big.Zero())
+
+	t.Run("seal and PoSt verification", func(t *testing.T) {
+		rt := builder.Build(t)
+		seal := proof.SealVerifyInfo{SectorID: abi.SectorID{Number: 7}, DealIDs: []abi.DealID{1}, Randomness: abi.SealRandomness("rand")}
+		post := proof.WindowPoStVerifyInfo{ChallengedSectors: []proof.SectorInfo{{SectorNumber: 3}, {SectorNumber: 4}}}
+
+		rt.ExpectVerifySealMatching(func(s proof.SealVerifyInfo) bool {
+			return s.SectorID.Number == 7 && len(s.DealIDs) == 1
+		}, nil)
+		rt.ExpectVerifyPoStMatching(func(p proof.WindowPoStVerifyInfo) bool {
+			return len(p.ChallengedSectors) == 2 && p.ChallengedSectors[1].SectorNumber == 4
+		}, xerrors.New("bad proof"))
+		rt.Call(func(rt runtime.Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
+			assert.NoError(t, rt.VerifySeal(seal))
+			assert.EqualError(t, rt.VerifyPoSt(post), "bad proof")
+			return nil
+		}, nil)
+		rt.Verify()
+	})
+
+	t.Run("send params", func(t *testing.T) {
+		rt := builder.Build(t)
+		to := tutil.NewIDAddr(t, 101)
+		rt.ExpectSendMatching(to, builtin.MethodSend, func(params cbor.Marshaler) bool {
+			n, ok := params.(*cbg.CborInt)
+			return ok && *n > 10
+		}, big.Zero(), nil, exitcode.Ok)
+		rt.Call(func(rt runtime.Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
+			n := cbg.CborInt(42)
+			rt.Send(to, builtin.MethodSend, &n, big.Zero(), &builtin.Discard{})
+			return nil
+		}, nil)
+		rt.Verify()
+	})
+}
+
+func TestParallelRuntimes(t *testing.T) {
+	actor := counterActor{}
+	receiver := tutil.NewIDAddr(t, 100)
+	builder := mock.NewBuilder(context.Background(), receiver).
+		WithActorType(tutil.NewIDAddr(t, 101), builtin.AccountActorCodeID)
+
+	t.Run("runtimes built from a shared builder are independent", func(t *testing.T) {
+		for i := 0; i < 8; i++ {
+			i := i
+			t.Run(fmt.Sprintf("runtime %d", i), func(t *testing.T) {
+				t.Parallel()
+				rt := builder.Build(t)
+				raw := tutil.NewActorAddr(t, fmt.Sprintf("actor-%d", i))
+				id := tutil.NewIDAddr(t, uint64(200+i))
+				rt.AddIDAddress(raw, id)
+				rt.SetAddressActorType(id, builtin.AccountActorCodeID)
+				rt.SetBatchLimit(runtime.BatchWork(i), runtime.BatchLimit{Items: uint64(i), Groups: 1})
+
+				rt.Call(actor.Construct, nil)
+				rt.Call(actor.IncrementFour, nil)
+				var st cbg.CborInt
+				rt.GetState(&st)
+				assert.Equal(t, cbg.CborInt(4), st)
+
+				// Addresses registered with other runtimes are not visible.
+				for j := 0; j < 8; j++ {
+					_, found := rt.GetIdAddr(tutil.NewActorAddr(t, fmt.Sprintf("actor-%d", j)))
+					assert.Equal(t, i == j, found)
+				}
+			})
+		}
+	})
+
+	t.Run("configuring a builder does not affect runtimes already built", func(t *testing.T) {
+		b := mock.NewBuilder(context.Background(), receiver)
+		rt := b.Build(t)
+		other := tutil.NewIDAddr(t, 102)
+		b.WithActorType(other, builtin.AccountActorCodeID)
+
+		getCode := func(rt runtime.Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
+			if _, found := rt.GetActorCodeCID(other); found {
+				rt.Abortf(exitcode.ErrIllegalState, "found code for %v", other)
+			}
+			return nil
+		}
+		rt.Call(getCode, nil)
+		rt.Verify()
+	})
+}
+
+func TestFailSyscall(t *testing.T) {
+	receiver := tutil.NewIDAddr(t, 100)
+	builder := mock.NewBuilder(context.Background(), receiver)
+
+	pieces := []abi.PieceInfo{{Size: 2048, PieceCID: tutil.MakeCID("piece", nil)}}
+	commD := tutil.MakeCID("commd", nil)
+	compute := func(rt runtime.Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
+		if _, err := rt.ComputeUnsealedSectorCID(abi.RegisteredSealProof_StackedDrg2KiBV1, pieces); err != nil {
+			rt.Abortf(exitcode.ErrIllegalArgument, "failed to compute unsealed sector CID: %s", err)
+		}
+		return nil
+	}
+	seal := proof.SealVerifyInfo{SealProof: abi.RegisteredSealProof_StackedDrg2KiBV1}
+	verifySeal := func(rt runtime.Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
+		if err := rt.VerifySeal(seal); err != nil {
+			rt.Abortf(exitcode.ErrIllegalArgument, "invalid seal: %s", err)
+		}
+		return nil
+	}
+
+	t.Run("fails the nth subsequent call", func(t *testing.T) {
+		rt := builder.Build(t)
+		rt.FailSyscall(mock.SyscallComputeUnsealedSectorCID, 2, xerrors.New("injected"))
+
+		rt.ExpectComputeUnsealedSectorCID(abi.RegisteredSealProof_StackedDrg2KiBV1, pieces, commD, nil)
+		rt.Call(compute, nil)
+
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "injected", func() {
+			rt.Call(compute, nil)
+		})
+		rt.Verify()
+
+		// Later calls are handled by expectations again.
+		rt.ExpectComputeUnsealedSectorCID(abi.RegisteredSealProof_StackedDrg2KiBV1, pieces, commD, nil)
+		rt.Call(compute, nil)
+		rt.Verify()
+	})
+
+	t.Run("counts calls made after the failure is injected", func(t *testing.T) {
+		rt := builder.Build(t)
+		rt.ExpectComputeUnsealedSectorCID(abi.RegisteredSealProof_StackedDrg2KiBV1, pieces, commD, nil)
+		rt.Call(compute, nil)
+
+		rt.FailSyscall(mock.SyscallComputeUnsealedSectorCID, 1, xerrors.New("injected"))
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(compute, nil)
+		})
+		rt.Verify()
+	})
+
+	t.Run("failures apply only to the syscall injected", func(t *testing.T) {
+		rt := builder.Build(t)
+		rt.FailSyscall(mock.SyscallVerifySeal, 1, xerrors.New("injected"))
+
+		rt.ExpectComputeUnsealedSectorCID(abi.RegisteredSealProof_StackedDrg2KiBV1, pieces, commD, nil)
+		rt.Call(compute, nil)
+
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "invalid seal: injected", func() {
+			rt.Call(verifySeal, nil)
+		})
+		rt.Verify()
+	})
+}
+
+func TestNetTransfers(t *testing.T) {
+	receiver := tutil.NewIDAddr(t, 100)
+	caller := tutil.NewIDAddr(t, 101)
+	payee := tutil.NewIDAddr(t, 102)
+	builder := mock.NewBuilder(context.Background(), receiver).
+		WithCaller(caller, builtin.AccountActorCodeID).
+		WithBalance(big.NewInt(100), big.NewInt(10))
+
+	// Pays the payee, then burns a penalty in two parts.
+	payAndBurn := func(rt runtime.Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
+		rt.Send(payee, builtin.MethodSend, nil, big.NewInt(3), &builtin.Discard{})
+		rt.Send(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, big.NewInt(2), &builtin.Discard{})
+		rt.Send(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, big.NewInt(1), &builtin.Discard{})
+		return nil
+	}
+	expectPayAndBurn := func(rt *mock.Runtime, payCode exitcode.ExitCode) {
+		rt.ExpectSend(payee, builtin.MethodSend, nil, big.NewInt(3), nil, payCode)
+		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, big.NewInt(2), nil, exitcode.Ok)
+		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, big.NewInt(1), nil, exitcode.Ok)
+	}
+
+	t.Run("tracks value received and sent", func(t *testing.T) {
+		rt := builder.Build(t)
+		expectPayAndBurn(rt, exitcode.Ok)
+		rt.ExpectNetTransfer(caller, receiver, big.NewInt(10))
+		rt.ExpectNetTransfer(receiver, payee, big.NewInt(3))
+		rt.ExpectNetTransfer(receiver, builtin.BurntFundsActorAddr, big.NewInt(3))
+		rt.Call(payAndBurn, nil)
+
+		assert.Len(t, rt.Transfers(), 4)
+		assert.Equal(t, big.NewInt(-3), rt.NetTransfer(payee, receiver))
+		assert.Equal(t, big.Zero(), rt.NetTransfer(caller, payee))
+		rt.Verify()
+		assert.Empty(t, rt.Transfers())
+	})
+
+	t.Run("failed sends transfer nothing", func(t *testing.T) {
+		rt := builder.Build(t)
+		expectPayAndBurn(rt, exitcode.ErrForbidden)
+		rt.ExpectNetTransfer(receiver, payee, big.Zero())
+		rt.ExpectNetTransfer(receiver, builtin.BurntFundsActorAddr, big.NewInt(3))
+		rt.Call(payAndBurn, nil)
+		rt.Verify()
+	})
+
+	t.Run("aborted calls transfer nothing", func(t *testing.T) {
+		rt := builder.Build(t)
+		payThenAbort := func(rt runtime.Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
+			rt.Send(payee, builtin.MethodSend, nil, big.NewInt(3), &builtin.Discard{})
+			rt.Abortf(exitcode.ErrIllegalState, "abort")
+			return nil
+		}
+		rt.ExpectSend(payee, builtin.MethodSend, nil, big.NewInt(3), nil, exitcode.Ok)
+		rt.ExpectAbort(exitcode.ErrIllegalState, func() {
+			rt.Call(payThenAbort, nil)
+		})
+		assert.Empty(t, rt.Transfers())
+		rt.Verify()
+	})
+}
+
+func TestCreateDeleteActorExpectations(t *testing.T) {
+	receiver := tutil.NewIDAddr(t, 100)
+	beneficiary := tutil.NewIDAddr(t, 101)
+	builder := mock.NewBuilder(context.Background(), receiver).WithBalance(big.NewInt(10), big.Zero())
+	isID := func(a address.Address) bool { return a.Protocol() == address.ID }
+
+	t.Run("created actor matches predicate and has its code", func(t *testing.T) {
+		rt := builder.Build(t)
+		created := tutil.NewIDAddr(t, 1000)
+		create := func(rt runtime.Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
+			rt.CreateActor(builtin.PaymentChannelActorCodeID, created)
+			code, ok := rt.GetActorCodeCID(created)
+			if !ok || !code.Equals(builtin.PaymentChannelActorCodeID) {
+				rt.Abortf(exitcode.ErrIllegalState, "created actor has code %v", code)
+			}
+			return nil
+		}
+		rt.ExpectCreateActorMatching(builtin.PaymentChannelActorCodeID, isID)
+		rt.Call(create, nil)
+		rt.Verify()
+	})
+
+	t.Run("deletion passes balance to beneficiary", func(t *testing.T) {
+		rt := builder.Build(t)
+		deleteSelf := func(rt runtime.Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
+			rt.DeleteActor(beneficiary)
+			return nil
+		}
+		rt.ExpectDeleteActorMatching(isID)
+		rt.ExpectNetTransfer(receiver, beneficiary, big.NewInt(10))
+		rt.Call(deleteSelf, nil)
+		assert.Equal(t, big.Zero(), rt.Balance())
+		rt.Verify()
+	})
+}
+
+func TestDispatchedSends(t *testing.T) {
+	actor := puppet.Actor{}
+	a, b := tutil.NewIDAddr(t, 100), tutil.NewIDAddr(t, 101)
+	sender := tutil.NewIDAddr(t, 102)
+
+	newPuppet := func(t *testing.T, receiver address.Address, balance int64) *mock.Runtime {
+		rt := mock.NewBuilder(context.Background(), receiver).
+			WithCaller(builtin.InitActorAddr, builtin.InitActorCodeID).
+			WithBalance(big.NewInt(balance), big.Zero()).
+			Build(t)
+		rt.ExpectValidateCallerAddr(builtin.InitActorAddr)
+		rt.Call(actor.Constructor, nil)
+		rt.Verify()
+		rt.SetCaller(sender, builtin.AccountActorCodeID)
+		return rt
+	}
+	// Two puppets whose sends to each other are dispatched, the first with a balance of 100.
+	newPuppets := func(t *testing.T) (*mock.Runtime, *mock.Runtime) {
+		aRt, bRt := newPuppet(t, a, 100), newPuppet(t, b, 0)
+		aRt.DispatchSendsTo(bRt, actor)
+		bRt.DispatchSendsTo(aRt, actor)
+		return aRt, bRt
+	}
+	setCallback := func(rt *mock.Runtime, steps ...puppet.Step) {
+		rt.ExpectValidateCallerAny()
+		rt.Call(actor.SetCallback, &puppet.Script{Steps: steps})
+		rt.Verify()
+	}
+	callback := func(to address.Address, value int64) puppet.Step {
+		return puppet.Step{Op: puppet.OpSend, Send: &puppet.SendStep{To: to, Method: puppet.Methods.Callback, Value: big.NewInt(value)}}
+	}
+	decodeReturn := func(t *testing.T, data []byte) *puppet.ScriptReturn {
+		var ret puppet.ScriptReturn
+		require.NoError(t, ret.UnmarshalCBOR(bytes.NewReader(data)))
+		return &ret
+	}
+
+	t.Run("callee re-enters the caller", func(t *testing.T) {
+		aRt, bRt := newPuppets(t)
+		// Each callback calls back to the other puppet, until the first is re-entered.
+		setCallback(aRt,
+			puppet.Step{Op: puppet.OpSetData, Data: []byte("reentered")},
+			puppet.Step{Op: puppet.OpStopAtDepth, Depth: 1},
+			callback(b, 1))
+		setCallback(bRt, callback(a, 2))
+
+		aRt.ExpectValidateCallerAny()
+		aRt.ExpectNestedCall(func() {
+			aRt.ExpectValidateCallerAny()
+		})
+		bRt.ExpectValidateCallerAny()
+		aRt.ExpectNetTransfer(a, b, big.NewInt(3))
+		bRt.ExpectNetTransfer(a, b, big.NewInt(3))
+		ret := aRt.Call(actor.Run, &puppet.Script{Steps: []puppet.Step{callback(b, 5)}}).(*puppet.ScriptReturn)
+		aRt.Verify()
+		bRt.Verify()
+
+		// The nested callback stopped after setting the data.
+		require.Len(t, ret.Results, 1)
+		assert.Equal(t, exitcode.Ok, ret.Results[0].Code)
+		bRet := decodeReturn(t, ret.Results[0].Return)
+		require.Len(t, bRet.Results, 1)
+		assert.Len(t, decodeReturn(t, bRet.Results[0].Return).Results, 1)
+
+		var st puppet.State
+		aRt.GetState(&st)
+		assert.Equal(t, []byte("reentered"), st.Data)
+		assert.Equal(t, uint64(0), st.Depth)
+		require.Len(t, st.Invocations, 2)
+		assert.Equal(t, sender, st.Invocations[0].Caller)
+		assert.Equal(t, uint64(0), st.Invocations[0].Depth)
+		assert.Equal(t, b, st.Invocations[1].Caller)
+		assert.Equal(t, puppet.Methods.Callback, st.Invocations[1].Method)
+		assert.Equal(t, big.NewInt(2), st.Invocations[1].Value)
+		assert.Equal(t, uint64(1), st.Invocations[1].Depth)
+		assert.Equal(t, big.NewInt(97), aRt.Balance())
+		assert.Equal(t, big.NewInt(3), bRt.Balance())
+	})
+
+	t.Run("failed send undoes the callee's calls back to the caller", func(t *testing.T) {
+		aRt, bRt := newPuppets(t)
+		setCallback(aRt, puppet.Step{Op: puppet.OpSetData, Data: []byte("reentered")})
+		setCallback(bRt, callback(a, 2), puppet.Step{Op: puppet.OpAbort, Code: exitcode.ErrForbidden})
+
+		aRt.ExpectValidateCallerAny()
+		aRt.ExpectNestedCall(func() {
+			aRt.ExpectValidateCallerAny()
+		})
+		bRt.ExpectValidateCallerAny()
+		ret := aRt.Call(actor.Run, &puppet.Script{Steps: []puppet.Step{callback(b, 5)}}).(*puppet.ScriptReturn)
+		assert.Equal(t, exitcode.ErrForbidden, ret.Results[0].Code)
+		assert.Empty(t, aRt.Transfers())
+		assert.Empty(t, bRt.Transfers())
+		aRt.Verify()
+		bRt.Verify()
+
+		var st puppet.State
+		aRt.GetState(&st)
+		assert.Empty(t, st.Data)
+		assert.Len(t, st.Invocations, 1)
+		assert.Equal(t, big.NewInt(100), aRt.Balance())
+		assert.Equal(t, big.Zero(), bRt.Balance())
+	})
+}
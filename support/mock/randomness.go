@@ -0,0 +1,33 @@
+package mock
+
+import (
+	"encoding/binary"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/crypto"
+	"github.com/minio/blake2b-simd"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/runtime"
+)
+
+// Computes the randomness returned for a request, as a function of the request alone.
+type RandomnessFunc func(source runtime.RandomnessSource, tag crypto.DomainSeparationTag, epoch abi.ChainEpoch, entropy []byte) abi.Randomness
+
+// Programs randomness for requests without a queued expectation, in place of failing the test.
+// Unlike ExpectGetRandomness, this permits any number of requests in any order, so a test running over many
+// epochs needn't enumerate every challenge. Queued expectations are still matched first.
+// A nil function restores strict expectations.
+func (rt *Runtime) SetRandomness(f RandomnessFunc) {
+	rt.randomness = f
+}
+
+// A RandomnessFunc returning a hash of the request parameters, so that distinct requests receive distinct
+// randomness and tests can compute the value an actor will receive.
+func DeterministicRandomness(source runtime.RandomnessSource, tag crypto.DomainSeparationTag, epoch abi.ChainEpoch, entropy []byte) abi.Randomness {
+	buf := make([]byte, 24, 24+len(entropy))
+	binary.BigEndian.PutUint64(buf[0:], uint64(source))
+	binary.BigEndian.PutUint64(buf[8:], uint64(tag))
+	binary.BigEndian.PutUint64(buf[16:], uint64(epoch))
+	digest := blake2b.Sum256(append(buf, entropy...))
+	return digest[:]
+}
@@ -0,0 +1,88 @@
+package mock
+
+import (
+	addr "github.com/filecoin-project/go-address"
+	"github.com/ipfs/go-cid"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/runtime"
+)
+
+// Identifies a snapshot of the mock runtime's state.
+type SnapshotID int
+
+// Records the current state of the runtime: the actor's state and balance, the environment (epoch, caller,
+// addresses, etc.) and gas accounting. Tests may then prepare a common state once, and Revert to it before each
+// of many cases rather than rebuilding it.
+// May not be called during a call, or with expectations outstanding.
+func (rt *Runtime) Snapshot() SnapshotID {
+	rt.t.Helper()
+	rt.require(!rt.inCall, "cannot snapshot during a call")
+	rt.Verify()
+
+	snap := *rt
+	snap.snapshots = nil
+	snap.idAddresses = copyAddressMap(rt.idAddresses)
+	snap.actorCodeCIDs = copyCodeMap(rt.actorCodeCIDs)
+	snap.gasByMethod = copyGasMap(rt.gasByMethod)
+	snap.batchLimits = copyBatchLimits(rt.batchLimits)
+	snap.logs = append([]string(nil), rt.logs...)
+	rt.snapshots = append(rt.snapshots, &snap)
+	return SnapshotID(len(rt.snapshots) - 1)
+}
+
+// Restores the runtime to the state recorded by a snapshot, discarding all changes since, including any
+// outstanding expectations. The snapshot is retained, so a test may revert to it repeatedly.
+// Objects stored since the snapshot remain in the store, but are unreachable from the restored state.
+func (rt *Runtime) Revert(id SnapshotID) {
+	rt.t.Helper()
+	rt.require(!rt.inCall, "cannot revert during a call")
+	rt.require(id >= 0 && int(id) < len(rt.snapshots), "unknown snapshot %d", id)
+
+	snap := rt.snapshots[id]
+	t, store, snapshots := rt.t, rt.store, rt.snapshots
+	*rt = *snap
+	rt.t, rt.store, rt.snapshots = t, store, snapshots
+	rt.idAddresses = copyAddressMap(snap.idAddresses)
+	rt.actorCodeCIDs = copyCodeMap(snap.actorCodeCIDs)
+	rt.gasByMethod = copyGasMap(snap.gasByMethod)
+	rt.batchLimits = copyBatchLimits(snap.batchLimits)
+	rt.logs = append([]string(nil), snap.logs...)
+}
+
+func copyAddressMap(m map[addr.Address]addr.Address) map[addr.Address]addr.Address {
+	c := make(map[addr.Address]addr.Address, len(m))
+	for k, v := range m { //nolint:nomaprange
+		c[k] = v
+	}
+	return c
+}
+
+func copyCodeMap(m map[addr.Address]cid.Cid) map[addr.Address]cid.Cid {
+	c := make(map[addr.Address]cid.Cid, len(m))
+	for k, v := range m { //nolint:nomaprange
+		c[k] = v
+	}
+	return c
+}
+
+func copyGasMap(m map[string]int64) map[string]int64 {
+	if m == nil {
+		return nil
+	}
+	c := make(map[string]int64, len(m))
+	for k, v := range m { //nolint:nomaprange
+		c[k] = v
+	}
+	return c
+}
+
+func copyBatchLimits(m map[runtime.BatchWork]runtime.BatchLimit) map[runtime.BatchWork]runtime.BatchLimit {
+	if m == nil {
+		return nil
+	}
+	c := make(map[runtime.BatchWork]runtime.BatchLimit, len(m))
+	for k, v := range m { //nolint:nomaprange
+		c[k] = v
+	}
+	return c
+}
@@ -0,0 +1,88 @@
+package mock
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A kind of external interaction recorded in a trace.
+type TraceKind int
+
+const (
+	TraceStateRead TraceKind = iota
+	TraceStateWrite
+	TraceStoreGet
+	TraceStorePut
+	TraceSend
+	TraceSyscall
+	TraceRandomness
+)
+
+func (k TraceKind) String() string {
+	switch k {
+	case TraceStateRead:
+		return "state-read"
+	case TraceStateWrite:
+		return "state-write"
+	case TraceStoreGet:
+		return "store-get"
+	case TraceStorePut:
+		return "store-put"
+	case TraceSend:
+		return "send"
+	case TraceSyscall:
+		return "syscall"
+	case TraceRandomness:
+		return "randomness"
+	default:
+		return fmt.Sprintf("TraceKind(%d)", int(k))
+	}
+}
+
+// An interaction of an actor with the runtime during a call.
+type TraceEntry struct {
+	Kind TraceKind
+	// The runtime method invoked, e.g. "StateTransaction", "SendReadOnly" or "VerifySeal".
+	Op string
+	// A description of the arguments.
+	Detail string
+}
+
+func (e TraceEntry) String() string {
+	return fmt.Sprintf("%s %s(%s)", e.Kind, e.Op, e.Detail)
+}
+
+// Enables or disables tracing. While enabled, each call records every state read and write, store access,
+// send, syscall and randomness request made by the actor, in order. The trace of the most recent call is
+// available from Trace(), and is logged when an expectation fails.
+func (rt *Runtime) SetTracing(enabled bool) {
+	rt.tracing = enabled
+}
+
+// Returns the trace of the most recent call, if tracing was enabled.
+func (rt *Runtime) Trace() []TraceEntry {
+	return append([]TraceEntry(nil), rt.traceEntries...)
+}
+
+// Formats a trace with one entry per line, e.g. for documenting a method's external interactions.
+func FormatTrace(entries []TraceEntry) string {
+	var b strings.Builder
+	for i, e := range entries {
+		fmt.Fprintf(&b, "%3d %s\n", i, e)
+	}
+	return b.String()
+}
+
+func (rt *Runtime) trace(kind TraceKind, op string, detail string, args ...interface{}) {
+	if !rt.tracing || !rt.inCall {
+		return
+	}
+	rt.traceEntries = append(rt.traceEntries, TraceEntry{Kind: kind, Op: op, Detail: fmt.Sprintf(detail, args...)})
+}
+
+func (rt *Runtime) logTrace() {
+	if rt.tracing && len(rt.traceEntries) > 0 {
+		rt.t.Helper()
+		rt.t.Logf("trace of the current call:\n%s", FormatTrace(rt.traceEntries))
+	}
+}
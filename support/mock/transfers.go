@@ -0,0 +1,91 @@
+package mock
+
+import (
+	"fmt"
+
+	addr "github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+)
+
+// A movement of value from one actor to another.
+type Transfer struct {
+	From   addr.Address
+	To     addr.Address
+	Amount abi.TokenAmount
+}
+
+func (t Transfer) String() string {
+	return fmt.Sprintf("%v -> %v: %v", t.From, t.To, t.Amount)
+}
+
+type expectNetTransfer struct {
+	from   addr.Address
+	to     addr.Address
+	amount abi.TokenAmount
+}
+
+func (e *expectNetTransfer) String() string {
+	return fmt.Sprintf("%v -> %v: %v", e.from, e.to, e.amount)
+}
+
+// Returns the transfers of value made by calls since the last Verify or Reset, in the order made.
+// A call that returns without aborting transfers the value it received from the caller to the receiver,
+// then the value of each successful send from the receiver to its recipient, and the receiver's balance to the
+// beneficiary if the actor deletes itself. A call that aborts transfers nothing, as in the VM.
+func (rt *Runtime) Transfers() []Transfer {
+	return append([]Transfer(nil), rt.transfers...)
+}
+
+// Returns the net value moved between two actors by the transfers since the last Verify or Reset: the value
+// transferred from -> to, less that transferred to -> from.
+func (rt *Runtime) NetTransfer(from, to addr.Address) abi.TokenAmount {
+	net := big.Zero()
+	for _, t := range rt.transfers {
+		if t.From == from && t.To == to {
+			net = big.Add(net, t.Amount)
+		} else if t.From == to && t.To == from {
+			net = big.Sub(net, t.Amount)
+		}
+	}
+	return net
+}
+
+// Expects the net value moved between two actors by calls before the next Verify to equal amount.
+// Unlike the values of expected sends, this accounts for all value moving between the actors however it is
+// split among messages, e.g. a penalty burnt partly from vesting funds and partly from the balance, and so catches
+// fee and burn accounting that pays the right recipients the wrong totals.
+func (rt *Runtime) ExpectNetTransfer(from, to addr.Address, amount abi.TokenAmount) {
+	rt.expectNetTransfers = append(rt.expectNetTransfers, &expectNetTransfer{
+		from:   from,
+		to:     to,
+		amount: amount,
+	})
+}
+
+// Records a transfer made by the current call, to take effect if the call returns without aborting.
+func (rt *Runtime) recordTransfer(from, to addr.Address, amount abi.TokenAmount) {
+	if amount.NilOrZero() {
+		return
+	}
+	rt.callTransfers = append(rt.callTransfers, Transfer{From: from, To: to, Amount: amount})
+}
+
+// Commits the transfers made by a call that returned without aborting.
+func (rt *Runtime) commitTransfers() {
+	if !rt.valueReceived.NilOrZero() {
+		rt.transfers = append(rt.transfers, Transfer{From: rt.caller, To: rt.receiver, Amount: rt.valueReceived})
+	}
+	rt.transfers = append(rt.transfers, rt.callTransfers...)
+	rt.callTransfers = nil
+}
+
+func (rt *Runtime) verifyNetTransfers() {
+	rt.t.Helper()
+	for _, exp := range rt.expectNetTransfers {
+		if actual := rt.NetTransfer(exp.from, exp.to); !actual.Equals(exp.amount) {
+			rt.failTest("expected net transfer %v -> %v of %v, got %v from transfers %v",
+				exp.from, exp.to, exp.amount, actual, rt.transfers)
+		}
+	}
+}
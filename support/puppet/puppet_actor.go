@@ -0,0 +1,197 @@
+package puppet
+
+import (
+	addr "github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/cbor"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/ipfs/go-cid"
+	mh "github.com/multiformats/go-multihash"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v2/actors/runtime"
+)
+
+// The puppet actor is a test-only actor which does whatever it is told, so that integration tests can exercise
+// builtin actors against adversarial counterparties: owners, clients and cron callees which send unexpected
+// messages, abort at chosen points, or re-enter the actor calling them.
+//
+// A puppet runs a script of steps, each of which sends a message, aborts, sets the puppet's data, or stops the
+// script at a given depth of reentry.
+// A script may be run directly with Run, or stored with SetCallback to run whenever any actor invokes the
+// puppet's Callback method (e.g. the cron actor invoking a user entry). The puppet records every invocation of
+// Run and Callback, with the depth of nesting of the puppet's own invocations at the time, so a test can observe
+// reentrant calls.
+type Actor struct{}
+
+var PuppetActorCodeID cid.Cid
+
+func init() {
+	builder := cid.V1Builder{Codec: cid.Raw, MhType: mh.IDENTITY}
+	c, err := builder.Sum([]byte("fil/test/puppet"))
+	if err != nil {
+		panic(err)
+	}
+	PuppetActorCodeID = c
+}
+
+var Methods = struct {
+	Constructor abi.MethodNum
+	Run         abi.MethodNum
+	SetCallback abi.MethodNum
+	Callback    abi.MethodNum
+}{builtin.MethodConstructor, 2, 3, 4}
+
+func (a Actor) Exports() []interface{} {
+	return []interface{}{
+		builtin.MethodConstructor: a.Constructor,
+		2:                         a.Run,
+		3:                         a.SetCallback,
+		4:                         a.Callback,
+	}
+}
+
+func (a Actor) Code() cid.Cid {
+	return PuppetActorCodeID
+}
+
+func (a Actor) IsSingleton() bool {
+	return false
+}
+
+func (a Actor) State() cbor.Er {
+	return new(State)
+}
+
+var _ runtime.VMActor = Actor{}
+
+// Operations a script step may perform.
+const (
+	// Sends a message, recording its exit code and return value.
+	OpSend = uint64(iota)
+	// Aborts the invocation with the step's exit code.
+	OpAbort
+	// Sets the puppet's data to the step's data.
+	OpSetData
+	// Ends the script, skipping the remaining steps, if the invocation is nested at least the step's depth among the
+	// puppet's invocations. A callback which re-enters the actor calling it thus recurses a bounded number of times.
+	OpStopAtDepth
+)
+
+type Step struct {
+	Op   uint64
+	Send *SendStep // The message to send, for OpSend.
+	Data []byte    // The data to set, for OpSetData.
+	// For OpAbort, the exit code with which to abort. For OpSend, if non-zero, the invocation aborts with this
+	// code if the send does not succeed.
+	Code  exitcode.ExitCode
+	Depth uint64 // The depth at which to stop, for OpStopAtDepth.
+}
+
+type SendStep struct {
+	To     addr.Address
+	Method abi.MethodNum
+	Value  abi.TokenAmount
+	Params []byte
+}
+
+type Script struct {
+	Steps []Step
+}
+
+type StepResult struct {
+	Code   exitcode.ExitCode
+	Return []byte
+}
+
+type ScriptReturn struct {
+	Results []StepResult
+}
+
+func (a Actor) Constructor(rt runtime.Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
+	rt.ValidateImmediateCallerIs(builtin.InitActorAddr)
+	rt.StateCreate(ConstructState())
+	return nil
+}
+
+// Runs a script, returning the result of each step.
+func (a Actor) Run(rt runtime.Runtime, params *Script) *ScriptReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+	return run(rt, Methods.Run, params)
+}
+
+// Stores a script to run on each invocation of Callback, replacing any previous callback.
+func (a Actor) SetCallback(rt runtime.Runtime, params *Script) *abi.EmptyValue {
+	rt.ValidateImmediateCallerAcceptAny()
+	var st State
+	rt.StateTransaction(&st, func() {
+		st.Callback = *params
+	})
+	return nil
+}
+
+// Runs the stored callback script. The params, if any, are ignored.
+func (a Actor) Callback(rt runtime.Runtime, _ *builtin.CBORBytes) *ScriptReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+	var st State
+	rt.StateReadonly(&st)
+	return run(rt, Methods.Callback, &st.Callback)
+}
+
+func run(rt runtime.Runtime, method abi.MethodNum, script *Script) *ScriptReturn {
+	var st State
+	var depth uint64
+	rt.StateTransaction(&st, func() {
+		depth = st.Depth
+		st.Invocations = append(st.Invocations, Invocation{
+			Caller: rt.Caller(),
+			Method: method,
+			Value:  rt.ValueReceived(),
+			Depth:  st.Depth,
+		})
+		st.Depth++
+	})
+
+	ret := &ScriptReturn{Results: make([]StepResult, len(script.Steps))}
+steps:
+	for i, step := range script.Steps {
+		switch step.Op {
+		case OpSend:
+			if step.Send == nil {
+				rt.Abortf(exitcode.ErrIllegalArgument, "step %d has no message to send", i)
+			}
+			msg := step.Send
+			var params cbor.Marshaler
+			if len(msg.Params) > 0 {
+				params = builtin.CBORBytes(msg.Params)
+			}
+			var out builtin.CBORBytes
+			code := rt.Send(msg.To, msg.Method, params, msg.Value, &out)
+			if code != exitcode.Ok && step.Code != exitcode.Ok {
+				rt.Abortf(step.Code, "step %d send to %v method %d failed with %v", i, msg.To, msg.Method, code)
+			}
+			ret.Results[i] = StepResult{Code: code, Return: out}
+		case OpAbort:
+			if step.Code == exitcode.Ok {
+				rt.Abortf(exitcode.ErrIllegalArgument, "step %d aborts with no exit code", i)
+			}
+			rt.Abortf(step.Code, "step %d scripted abort", i)
+		case OpSetData:
+			rt.StateTransaction(&st, func() {
+				st.Data = step.Data
+			})
+		case OpStopAtDepth:
+			if depth >= step.Depth {
+				ret.Results = ret.Results[:i]
+				break steps
+			}
+		default:
+			rt.Abortf(exitcode.ErrIllegalArgument, "step %d has invalid op %d", i, step.Op)
+		}
+	}
+
+	rt.StateTransaction(&st, func() {
+		st.Depth--
+	})
+	return ret
+}
@@ -0,0 +1,29 @@
+package puppet
+
+import (
+	addr "github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+)
+
+type State struct {
+	// Arbitrary data set by scripts, e.g. to change the state root between reentrant invocations.
+	Data []byte
+	// Script run on each invocation of Callback.
+	Callback Script
+	// Every invocation of Run and Callback, in order. Invocations aborted are not recorded.
+	Invocations []Invocation
+	// Number of invocations of Run and Callback currently executing.
+	Depth uint64
+}
+
+type Invocation struct {
+	Caller addr.Address
+	Method abi.MethodNum
+	Value  abi.TokenAmount
+	// Number of the puppet's invocations executing when this one began; non-zero for a reentrant invocation.
+	Depth uint64
+}
+
+func ConstructState() *State {
+	return &State{}
+}
@@ -0,0 +1,87 @@
+package vm_test
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v2/support/ipld"
+)
+
+// The root of an exported VM, linking its state tree.
+type ExportedVM struct {
+	StateRoot cid.Cid
+	Epoch     abi.ChainEpoch
+}
+
+// Writes the VM's committed state tree and epoch to a gzipped CAR, with an ExportedVM as its root.
+// The output depends only on the state, so the same state always exports to the same bytes.
+// Export does not include the VM's history of past state roots, nor its logs and invocations.
+func (vm *VM) Export(w io.Writer) error {
+	root, err := vm.checkpoint()
+	if err != nil {
+		return err
+	}
+	exportRoot, err := vm.store.Put(vm.ctx, &ExportedVM{StateRoot: root, Epoch: vm.currentEpoch})
+	if err != nil {
+		return err
+	}
+
+	// A zero header, without modification time, keeps the output deterministic.
+	zw := gzip.NewWriter(w)
+	if err := ipld.WriteCAR(vm.store, zw, exportRoot); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// Loads a VM exported with Export into a new store.
+func ImportVM(ctx context.Context, actorImpls ActorImplLookup, r io.Reader) (*VM, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read exported VM: %w", err)
+	}
+	store := ipld.NewADTStore(ctx)
+	roots, err := ipld.ReadCAR(store, zr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read exported VM: %w", err)
+	}
+	if len(roots) != 1 {
+		return nil, fmt.Errorf("exported VM has %d roots, expected 1", len(roots))
+	}
+	var exported ExportedVM
+	if err := store.Get(ctx, roots[0], &exported); err != nil {
+		return nil, fmt.Errorf("failed to load exported VM root: %w", err)
+	}
+	return NewVMAtEpoch(ctx, actorImpls, store, exported.StateRoot, exported.Epoch)
+}
+
+// Returns a VM loaded from the fixture file at path, building the VM and exporting it there first if the file
+// does not exist. A fixture which is expensive to build, such as a miner with many sectors, may so be built once
+// and shared by tests in many packages.
+// The fixture must be removed whenever the actors' state or the build function changes.
+func LoadOrBuildFixture(ctx context.Context, t testing.TB, actorImpls ActorImplLookup, path string, build func() *VM) *VM {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		v := build()
+		// Write to a temporary file first so that a failed export leaves no fixture behind.
+		out, err := os.Create(path + ".tmp")
+		require.NoError(t, err)
+		require.NoError(t, v.Export(out))
+		require.NoError(t, out.Close())
+		require.NoError(t, os.Rename(path+".tmp", path))
+		return v
+	}
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+	v, err := ImportVM(ctx, actorImpls, f)
+	require.NoError(t, err)
+	return v
+}
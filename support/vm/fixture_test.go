@@ -0,0 +1,61 @@
+package vm_test
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportImportVM(t *testing.T) {
+	ctx := context.Background()
+	v := NewVMWithSingletons(ctx, t)
+	addrs := CreateAccounts(ctx, t, v, 3, big.Mul(big.NewInt(10), FIL), 93837778)
+	v, err := v.WithEpoch(1234)
+	require.NoError(t, err)
+
+	var first, second bytes.Buffer
+	require.NoError(t, v.Export(&first))
+	require.NoError(t, v.Export(&second))
+	assert.Equal(t, first.Bytes(), second.Bytes())
+
+	imported, err := ImportVM(ctx, v.actorImpls, &first)
+	require.NoError(t, err)
+	expectedRoot, err := v.StateRoot()
+	require.NoError(t, err)
+	importedRoot, err := imported.StateRoot()
+	require.NoError(t, err)
+	assert.Equal(t, expectedRoot, importedRoot)
+	assert.Equal(t, v.GetEpoch(), imported.GetEpoch())
+	for _, a := range addrs {
+		act, found, err := imported.GetActor(a)
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Equal(t, big.Mul(big.NewInt(10), FIL), act.Balance)
+	}
+
+	// The imported VM executes messages.
+	_, code := imported.ApplyMessage(addrs[0], addrs[1], FIL, 0, nil)
+	assert.True(t, code.IsSuccess())
+
+	t.Run("fixture is built once", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "accounts.car.gz")
+		builds := 0
+		build := func() *VM {
+			builds++
+			return v
+		}
+		built := LoadOrBuildFixture(ctx, t, v.actorImpls, path, build)
+		loaded := LoadOrBuildFixture(ctx, t, v.actorImpls, path, build)
+		assert.Equal(t, 1, builds)
+		builtRoot, err := built.StateRoot()
+		require.NoError(t, err)
+		loadedRoot, err := loaded.StateRoot()
+		require.NoError(t, err)
+		assert.Equal(t, builtRoot, loadedRoot)
+	})
+}
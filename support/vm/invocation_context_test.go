@@ -0,0 +1,489 @@
+package vm_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/cbor"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/filecoin-project/go-state-types/network"
+	"github.com/ipfs/go-cid"
+	mh "github.com/multiformats/go-multihash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	cbg "github.com/whyrusleeping/cbor-gen"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	init_ "github.com/filecoin-project/specs-actors/v2/actors/builtin/init"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/multisig"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/paramreg"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/power"
+	"github.com/filecoin-project/specs-actors/v2/actors/runtime"
+	"github.com/filecoin-project/specs-actors/v2/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/v2/support/mock"
+	tutil "github.com/filecoin-project/specs-actors/v2/support/testing"
+)
+
+var (
+	counterV1CodeID = makeTestCodeID("test/counter/1")
+	counterV2CodeID = makeTestCodeID("test/counter/2")
+	counterV3CodeID = makeTestCodeID("test/counter/3")
+)
+
+func makeTestCodeID(name string) cid.Cid {
+	c, err := cid.V1Builder{Codec: cid.Raw, MhType: mh.IDENTITY}.Sum([]byte(name))
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// A test actor, whose state is a counter, that upgrades itself on request.
+type counterActor struct {
+	code cid.Cid
+}
+
+func (a counterActor) Exports() []interface{} {
+	return []interface{}{
+		builtin.MethodConstructor: nil,
+		2:                         a.Upgrade,
+	}
+}
+
+func (a counterActor) Code() cid.Cid     { return a.code }
+func (a counterActor) IsSingleton() bool { return false }
+func (a counterActor) State() cbor.Er    { return new(cbg.CborInt) }
+
+func (a counterActor) Upgrade(rt runtime.Runtime, newCode *cbg.CborCid) *abi.EmptyValue {
+	rt.ValidateImmediateCallerAcceptAny()
+	rt.UpgradeActor(cid.Cid(*newCode))
+	return nil
+}
+
+// An upgraded counter actor, which scales the counter on upgrade and rejects negative counters.
+type upgradableCounterActor struct {
+	counterActor
+}
+
+func (a upgradableCounterActor) UpgradeState(rt runtime.Runtime, oldCodeID cid.Cid) {
+	if !oldCodeID.Equals(counterV1CodeID) {
+		rt.Abortf(exitcode.ErrIllegalArgument, "cannot upgrade from %v", oldCodeID)
+	}
+	var st cbg.CborInt
+	rt.StateTransaction(&st, func() {
+		if st < 0 {
+			rt.Abortf(exitcode.ErrIllegalState, "negative counter %d", st)
+		}
+		st *= 10
+	})
+}
+
+func TestUpgradeActor(t *testing.T) {
+	ctx := context.Background()
+	counterAddr := tutil.NewIDAddr(t, 10000)
+
+	setup := func(t *testing.T, counter cbg.CborInt, approvals []init_.UpgradeApproval) *VM {
+		v := NewVMWithSingletons(ctx, t)
+		v.actorImpls[counterV1CodeID] = counterActor{code: counterV1CodeID}
+		v.actorImpls[counterV2CodeID] = upgradableCounterActor{counterActor{code: counterV2CodeID}}
+		v.actorImpls[counterV3CodeID] = counterActor{code: counterV3CodeID}
+		initializeActor(ctx, t, v, &counter, counterV1CodeID, counterAddr, big.Zero())
+
+		var initSt init_.State
+		require.NoError(t, v.GetState(builtin.InitActorAddr, &initSt))
+		initSt.UpgradeApprovals = approvals
+		require.NoError(t, v.setActorState(ctx, builtin.InitActorAddr, &initSt))
+		_, err := v.checkpoint()
+		require.NoError(t, err)
+		return v
+	}
+	upgrade := func(v *VM, newCode cid.Cid) exitcode.ExitCode {
+		param := cbg.CborCid(newCode)
+		_, code := v.ApplyMessage(builtin.SystemActorAddr, counterAddr, big.Zero(), 2, &param)
+		return code
+	}
+	requireCounter := func(t *testing.T, v *VM, code cid.Cid, counter cbg.CborInt) {
+		act, found, err := v.GetActor(counterAddr)
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Equal(t, code, act.Code)
+		var st cbg.CborInt
+		require.NoError(t, v.GetState(counterAddr, &st))
+		assert.Equal(t, counter, st)
+	}
+	approveV2 := []init_.UpgradeApproval{{OldCodeCID: counterV1CodeID, NewCodeCID: counterV2CodeID, Expiration: 100}}
+
+	t.Run("approved upgrade replaces code and migrates state", func(t *testing.T) {
+		v := setup(t, 3, approveV2)
+		assert.Equal(t, exitcode.Ok, upgrade(v, counterV2CodeID))
+		requireCounter(t, v, counterV2CodeID, 30)
+	})
+
+	t.Run("unapproved upgrade is forbidden", func(t *testing.T) {
+		v := setup(t, 3, nil)
+		assert.Equal(t, exitcode.SysErrForbidden, upgrade(v, counterV2CodeID))
+		requireCounter(t, v, counterV1CodeID, 3)
+	})
+
+	t.Run("expired approval is forbidden", func(t *testing.T) {
+		v := setup(t, 3, approveV2)
+		v, err := v.WithEpoch(101)
+		require.NoError(t, err)
+		assert.Equal(t, exitcode.SysErrForbidden, upgrade(v, counterV2CodeID))
+		requireCounter(t, v, counterV1CodeID, 3)
+	})
+
+	t.Run("incompatible state aborts the upgrade", func(t *testing.T) {
+		v := setup(t, -1, approveV2)
+		assert.Equal(t, exitcode.ErrIllegalState, upgrade(v, counterV2CodeID))
+		requireCounter(t, v, counterV1CodeID, -1)
+	})
+
+	t.Run("new code must be upgradable", func(t *testing.T) {
+		v := setup(t, 3, []init_.UpgradeApproval{{OldCodeCID: counterV1CodeID, NewCodeCID: counterV3CodeID, Expiration: 100}})
+		assert.Equal(t, exitcode.SysErrorIllegalArgument, upgrade(v, counterV3CodeID))
+		requireCounter(t, v, counterV1CodeID, 3)
+	})
+}
+
+var powerReaderCodeID = makeTestCodeID("test/power-reader")
+
+// A test actor which reports a miner's quality-adjusted power at a past epoch.
+type powerReaderActor struct {
+	miner address.Address
+}
+
+func (a powerReaderActor) Exports() []interface{} {
+	return []interface{}{
+		builtin.MethodConstructor: nil,
+		2:                         a.QualityAdjPowerAt,
+	}
+}
+
+func (a powerReaderActor) Code() cid.Cid     { return powerReaderCodeID }
+func (a powerReaderActor) IsSingleton() bool { return false }
+func (a powerReaderActor) State() cbor.Er    { return new(cbg.CborInt) }
+
+func (a powerReaderActor) QualityAdjPowerAt(rt runtime.Runtime, epoch *cbg.CborInt) *abi.StoragePower {
+	rt.ValidateImmediateCallerAcceptAny()
+	snapshot, found := rt.GetMinerPowerSnapshot(a.miner, abi.ChainEpoch(*epoch))
+	if !found {
+		rt.Abortf(exitcode.ErrNotFound, "no power for %v at %d", a.miner, *epoch)
+	}
+	return &snapshot.QualityAdjPower
+}
+
+func TestGetMinerPowerSnapshot(t *testing.T) {
+	ctx := context.Background()
+	minerAddr := tutil.NewIDAddr(t, 10000)
+	readerAddr := tutil.NewIDAddr(t, 10001)
+
+	setClaim := func(t *testing.T, v *VM, qaPower int64) {
+		var st power.State
+		require.NoError(t, v.GetState(builtin.StoragePowerActorAddr, &st))
+		claims, err := adt.AsMap(v.Store(), st.Claims)
+		require.NoError(t, err)
+		require.NoError(t, claims.Put(abi.AddrKey(minerAddr), &power.Claim{
+			SealProofType:   abi.RegisteredSealProof_StackedDrg32GiBV1,
+			RawBytePower:    abi.NewStoragePower(qaPower),
+			QualityAdjPower: abi.NewStoragePower(qaPower),
+		}))
+		st.Claims, err = claims.Root()
+		require.NoError(t, err)
+		require.NoError(t, v.setActorState(ctx, builtin.StoragePowerActorAddr, &st))
+	}
+	powerAt := func(v *VM, epoch abi.ChainEpoch) (abi.StoragePower, exitcode.ExitCode) {
+		param := cbg.CborInt(epoch)
+		ret, code := v.ApplyMessage(builtin.SystemActorAddr, readerAddr, big.Zero(), 2, &param)
+		if code != exitcode.Ok {
+			return big.Zero(), code
+		}
+		return *ret.(*abi.StoragePower), code
+	}
+
+	v := NewVMWithSingletons(ctx, t)
+	v.actorImpls[powerReaderCodeID] = powerReaderActor{miner: minerAddr}
+	state := cbg.CborInt(0)
+	initializeActor(ctx, t, v, &state, powerReaderCodeID, readerAddr, big.Zero())
+
+	// The miner has no claim at epoch 0, then claims are updated at epochs 1 and 5.
+	v, err := v.WithEpoch(1)
+	require.NoError(t, err)
+	setClaim(t, v, 10)
+	v, err = v.WithEpoch(5)
+	require.NoError(t, err)
+	setClaim(t, v, 20)
+	v, err = v.WithEpoch(8)
+	require.NoError(t, err)
+
+	_, code := powerAt(v, 0)
+	assert.Equal(t, exitcode.ErrNotFound, code)
+	for epoch, expected := range map[abi.ChainEpoch]int64{1: 10, 4: 10, 5: 20, 7: 20} { //nolint:nomaprange
+		qaPower, code := powerAt(v, epoch)
+		require.Equal(t, exitcode.Ok, code)
+		assert.Equal(t, abi.NewStoragePower(expected), qaPower, "epoch %d", epoch)
+	}
+
+	// The current epoch and epochs beyond the lookback are unavailable.
+	_, code = powerAt(v, 8)
+	assert.Equal(t, exitcode.SysErrorIllegalArgument, code)
+	v, err = v.WithEpoch(8 + runtime.MaxHistoricalLookback)
+	require.NoError(t, err)
+	_, code = powerAt(v, 7)
+	assert.Equal(t, exitcode.SysErrorIllegalArgument, code)
+}
+
+var failingCodeID = makeTestCodeID("test/failing")
+
+// A test actor whose method aborts with data describing the failure.
+type failingActor struct{}
+
+func (a failingActor) Exports() []interface{} {
+	return []interface{}{
+		builtin.MethodConstructor: nil,
+		2:                         a.Fail,
+	}
+}
+
+func (a failingActor) Code() cid.Cid     { return failingCodeID }
+func (a failingActor) IsSingleton() bool { return false }
+func (a failingActor) State() cbor.Er    { return new(cbg.CborInt) }
+
+func (a failingActor) Fail(rt runtime.Runtime, value *cbg.CborInt) *abi.EmptyValue {
+	rt.ValidateImmediateCallerAcceptAny()
+	rt.AbortWithData(exitcode.ErrIllegalArgument, value, "failed with %d", *value)
+	return nil
+}
+
+func TestAbortWithData(t *testing.T) {
+	ctx := context.Background()
+	v := NewVMWithSingletons(ctx, t)
+	v.actorImpls[failingCodeID] = failingActor{}
+	failingAddr := tutil.NewIDAddr(t, 10000)
+	state := cbg.CborInt(0)
+	initializeActor(ctx, t, v, &state, failingCodeID, failingAddr, big.Zero())
+	addrs := CreateAccounts(ctx, t, v, 1, big.Mul(big.NewInt(10_000), big.NewInt(1e18)), 93837778)
+
+	t.Run("data is the return value of a failed message", func(t *testing.T) {
+		param := cbg.CborInt(42)
+		ret, code := v.ApplyMessage(addrs[0], failingAddr, big.Zero(), 2, &param)
+		assert.Equal(t, exitcode.ErrIllegalArgument, code)
+		assert.Equal(t, &param, ret)
+	})
+
+	t.Run("data is received by a multisig executing a call", func(t *testing.T) {
+		var ctorParams bytes.Buffer
+		require.NoError(t, (&multisig.ConstructorParams{Signers: addrs, NumApprovalsThreshold: 1}).MarshalCBOR(&ctorParams))
+		ret := ApplyOk(t, v, addrs[0], builtin.InitActorAddr, big.Zero(), builtin.MethodsInit.Exec, &init_.ExecParams{
+			CodeCID:           builtin.MultisigActorCodeID,
+			ConstructorParams: ctorParams.Bytes(),
+		})
+		multisigAddr := ret.(*init_.ExecReturn).IDAddress
+
+		var failParams bytes.Buffer
+		param := cbg.CborInt(43)
+		require.NoError(t, param.MarshalCBOR(&failParams))
+		ret = ApplyOk(t, v, addrs[0], multisigAddr, big.Zero(), builtin.MethodsMultisig.Propose, &multisig.ProposeParams{
+			To:     failingAddr,
+			Value:  big.Zero(),
+			Method: 2,
+			Params: failParams.Bytes(),
+		})
+		proposeRet := ret.(*multisig.ProposeReturn)
+		assert.True(t, proposeRet.Applied)
+		assert.Equal(t, exitcode.ErrIllegalArgument, proposeRet.Code)
+		var data cbg.CborInt
+		require.NoError(t, data.UnmarshalCBOR(bytes.NewReader(proposeRet.Ret)))
+		assert.Equal(t, param, data)
+	})
+}
+
+func TestRequireCanonicalParams(t *testing.T) {
+	ctx := context.Background()
+	v := NewVMWithSingletons(ctx, t)
+	v.actorImpls[failingCodeID] = failingActor{}
+	failingAddr := tutil.NewIDAddr(t, 10000)
+	state := cbg.CborInt(0)
+	initializeActor(ctx, t, v, &state, failingCodeID, failingAddr, big.Zero())
+	addrs := CreateAccounts(ctx, t, v, 1, big.Mul(big.NewInt(10_000), big.NewInt(1e18)), 93837778)
+
+	// The failing actor's method decodes its integer param before aborting with it.
+	// The decoder ignores bytes following the param.
+	canonical := builtin.CBORBytes{0x05}
+	trailing := builtin.CBORBytes{0x05, 0x00}
+	_, code := v.ApplyMessage(addrs[0], failingAddr, big.Zero(), 2, trailing)
+	assert.Equal(t, exitcode.ErrIllegalArgument, code)
+
+	builtin.RequireCanonicalParams = true
+	defer func() { builtin.RequireCanonicalParams = false }()
+	_, code = v.ApplyMessage(addrs[0], failingAddr, big.Zero(), 2, trailing)
+	assert.Equal(t, exitcode.ErrSerialization, code)
+	_, code = v.ApplyMessage(addrs[0], failingAddr, big.Zero(), 2, canonical)
+	assert.Equal(t, exitcode.ErrIllegalArgument, code)
+}
+
+func TestParamsLengthLimit(t *testing.T) {
+	ctx := context.Background()
+	v := NewVMWithSingletons(ctx, t)
+	addrs := CreateAccounts(ctx, t, v, 1, big.Mul(big.NewInt(10_000), big.NewInt(1e18)), 93837778)
+
+	var params bytes.Buffer
+	require.NoError(t, (&paramreg.GetParams{Name: "unknown"}).MarshalCBOR(&params))
+	_, code := v.ApplyMessage(addrs[0], builtin.ParameterRegistryActorAddr, big.Zero(), builtin.MethodsParameterRegistry.Get, builtin.CBORBytes(params.Bytes()))
+	assert.Equal(t, exitcode.ErrNotFound, code)
+
+	// Bytes following the params are ignored by the decoder, but count towards their length.
+	maxLength := (&paramreg.GetParams{}).MaxEncodedLength()
+	oversized := append(params.Bytes(), make([]byte, maxLength)...)
+	_, code = v.ApplyMessage(addrs[0], builtin.ParameterRegistryActorAddr, big.Zero(), builtin.MethodsParameterRegistry.Get, builtin.CBORBytes(oversized))
+	assert.Equal(t, exitcode.ErrSerialization, code)
+}
+
+func TestAdvanceToEpoch(t *testing.T) {
+	ctx := context.Background()
+	v := NewVMWithSingletons(ctx, t)
+
+	v, err := v.AdvanceToEpoch(3)
+	require.NoError(t, err)
+	assert.Equal(t, abi.ChainEpoch(3), v.GetEpoch())
+
+	// Cron ran at epochs 0 to 2, so the power actor has processed its queue to epoch 3, but not yet at 3.
+	var st power.State
+	require.NoError(t, v.GetState(builtin.StoragePowerActorAddr, &st))
+	assert.Equal(t, abi.ChainEpoch(3), st.FirstCronEpoch)
+
+	_, err = v.AdvanceToEpoch(2)
+	assert.Error(t, err)
+}
+
+func TestNewActorAddresses(t *testing.T) {
+	ctx := context.Background()
+	v := NewVMWithSingletons(ctx, t)
+	addrs := CreateAccounts(ctx, t, v, 1, big.Mul(big.NewInt(10_000), FIL), 93837778)
+
+	// Actors created by successive messages from a sender have distinct robust addresses.
+	params := power.CreateMinerParams{
+		Owner:         addrs[0],
+		Worker:        addrs[0],
+		SealProofType: abi.RegisteredSealProof_StackedDrg32GiBV1,
+		Peer:          abi.PeerID("peer"),
+	}
+	first := ApplyOk(t, v, addrs[0], builtin.StoragePowerActorAddr, big.Zero(), builtin.MethodsPower.CreateMiner, &params).(*power.CreateMinerReturn)
+	second := ApplyOk(t, v, addrs[0], builtin.StoragePowerActorAddr, big.Zero(), builtin.MethodsPower.CreateMiner, &params).(*power.CreateMinerReturn)
+	assert.NotEqual(t, first.RobustAddress, second.RobustAddress)
+	assert.NotEqual(t, first.IDAddress, second.IDAddress)
+
+	// The sender's call sequence number is incremented even by a failed message.
+	_, code := v.ApplyMessage(addrs[0], builtin.BurntFundsActorAddr, big.Mul(big.NewInt(20_000), FIL), builtin.MethodSend, nil)
+	require.Equal(t, exitcode.SysErrInsufficientFunds, code)
+	sender, found, err := v.GetActor(addrs[0])
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, uint64(3), sender.CallSeqNum)
+}
+
+// A gas charger counting the operations it prices, each at one unit of gas.
+type countingCharger struct {
+	gets, puts, sends, transfers int
+	syscalls                     map[mock.Syscall]int
+}
+
+func (c *countingCharger) OnStoreGet(int) int64 { c.gets++; return 1 }
+func (c *countingCharger) OnStorePut(int) int64 { c.puts++; return 1 }
+func (c *countingCharger) OnSend(transfer bool) int64 {
+	c.sends++
+	if transfer {
+		c.transfers++
+	}
+	return 1
+}
+func (c *countingCharger) OnSyscall(call mock.Syscall, items int) int64 {
+	c.syscalls[call] += items
+	return 1
+}
+
+func TestGasCharging(t *testing.T) {
+	ctx := context.Background()
+	v := NewVMWithSingletons(ctx, t)
+	addrs := CreateAccounts(ctx, t, v, 1, big.Mul(big.NewInt(10), FIL), 93837778)
+
+	var ctorParams bytes.Buffer
+	require.NoError(t, (&multisig.ConstructorParams{Signers: addrs, NumApprovalsThreshold: 1}).MarshalCBOR(&ctorParams))
+	exec := &init_.ExecParams{
+		CodeCID:           builtin.MultisigActorCodeID,
+		ConstructorParams: ctorParams.Bytes(),
+	}
+
+	t.Run("charges nothing without a charger", func(t *testing.T) {
+		ApplyOk(t, v, addrs[0], builtin.InitActorAddr, FIL, builtin.MethodsInit.Exec, exec)
+		assert.Equal(t, int64(0), v.GasUsed())
+	})
+
+	t.Run("charges store operations and sends", func(t *testing.T) {
+		charger := &countingCharger{syscalls: map[mock.Syscall]int{}}
+		v.SetGasCharger(charger)
+		defer v.SetGasCharger(nil)
+
+		// Init loads and replaces its state, then sends the value to the new multisig's constructor, which
+		// creates its state and an empty pending transactions map.
+		ApplyOk(t, v, addrs[0], builtin.InitActorAddr, FIL, builtin.MethodsInit.Exec, exec)
+		assert.Equal(t, 1, charger.sends)
+		assert.Equal(t, 1, charger.transfers)
+		assert.Greater(t, charger.gets, 0)
+		assert.Greater(t, charger.puts, 1)
+		assert.Empty(t, charger.syscalls)
+		assert.Equal(t, int64(charger.gets+charger.puts+charger.sends), v.GasUsed())
+		ExpectGasLessThan(t, v, 100)
+	})
+
+	t.Run("prices with a price list", func(t *testing.T) {
+		v.SetGasCharger(mock.PriceList{SendBase: 1000, SendTransferFunds: 500})
+		defer v.SetGasCharger(nil)
+		ApplyOk(t, v, addrs[0], builtin.InitActorAddr, FIL, builtin.MethodsInit.Exec, exec)
+		assert.Equal(t, int64(1500), v.GasUsed())
+
+		// VMs derived from this one inherit the charger.
+		next, err := v.WithEpoch(v.GetEpoch() + 1)
+		require.NoError(t, err)
+		ApplyOk(t, next, addrs[0], builtin.InitActorAddr, big.Zero(), builtin.MethodsInit.Exec, exec)
+		assert.Equal(t, int64(1000), next.GasUsed())
+	})
+
+	t.Run("prices by network version with a price schedule", func(t *testing.T) {
+		v.SetPriceSchedule(mock.PriceSchedule{
+			{Version: network.Version0, Prices: mock.PriceList{SendBase: 1000}},
+			{Version: network.Version5, Prices: mock.PriceList{SendBase: 2000}},
+		})
+		defer v.SetGasCharger(nil)
+		ApplyOk(t, v, addrs[0], builtin.InitActorAddr, big.Zero(), builtin.MethodsInit.Exec, exec)
+		assert.Equal(t, int64(2000), v.GasUsed())
+
+		prior, err := v.WithNetworkVersion(network.Version4)
+		require.NoError(t, err)
+		ApplyOk(t, prior, addrs[0], builtin.InitActorAddr, big.Zero(), builtin.MethodsInit.Exec, exec)
+		assert.Equal(t, int64(1000), prior.GasUsed())
+	})
+
+	t.Run("aborts a message exceeding the gas limit", func(t *testing.T) {
+		v.SetGasCharger(mock.PriceList{SendBase: 1000, SendTransferFunds: 500})
+		v.SetGasLimit(1500)
+		defer v.SetGasCharger(nil)
+		defer v.SetGasLimit(0)
+		ApplyOk(t, v, addrs[0], builtin.InitActorAddr, FIL, builtin.MethodsInit.Exec, exec)
+
+		// The message runs out of gas on the send constructing the new multisig, and its changes are rolled back.
+		v.SetGasLimit(1499)
+		before, _, err := v.GetActor(addrs[0])
+		require.NoError(t, err)
+		_, code := v.ApplyMessage(addrs[0], builtin.InitActorAddr, FIL, builtin.MethodsInit.Exec, exec)
+		assert.Equal(t, exitcode.SysErrOutOfGas, code)
+		assert.Equal(t, int64(1500), v.GasUsed())
+		after, _, err := v.GetActor(addrs[0])
+		require.NoError(t, err)
+		assert.Equal(t, before.Balance, after.Balance)
+	})
+}
@@ -0,0 +1,90 @@
+package vm_test
+
+import (
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/pkg/errors"
+)
+
+// A function run by a Simulator at an epoch, with the VM at that epoch. It may apply messages and inspect state,
+// and schedule further hooks. An error stops the simulation.
+type EpochHook func(v *VM) error
+
+// A Simulator advances a VM through a timeline, so that scenarios may be written as the messages and checks to
+// apply at each epoch rather than as loops advancing epochs by hand.
+// At each epoch, the hooks scheduled for it are run in the order in which they were scheduled, and then cron runs
+// at the end of the epoch (see VM.AdvanceToEpoch), which delivers the events deferred to it by the power and market
+// actors, such as miners' deadline and deal callbacks.
+type Simulator struct {
+	v         *VM
+	scheduled map[abi.ChainEpoch][]EpochHook
+}
+
+func NewSimulator(v *VM) *Simulator {
+	return &Simulator{
+		v:         v,
+		scheduled: make(map[abi.ChainEpoch][]EpochHook),
+	}
+}
+
+// Returns the VM at the simulation's current epoch.
+func (s *Simulator) VM() *VM {
+	return s.v
+}
+
+// Schedules a hook to run at an epoch, which must not have passed.
+// A hook scheduled for the current epoch runs on the next call to AdvanceTo, including one to the current epoch.
+func (s *Simulator) At(epoch abi.ChainEpoch, hook EpochHook) error {
+	if epoch < s.v.GetEpoch() {
+		return errors.Errorf("cannot schedule hook at epoch %d, before current epoch %d", epoch, s.v.GetEpoch())
+	}
+	s.scheduled[epoch] = append(s.scheduled[epoch], hook)
+	return nil
+}
+
+// Schedules a message to be applied at an epoch, which must not have passed. The simulation stops with an error
+// if the message fails.
+func (s *Simulator) Send(epoch abi.ChainEpoch, from, to address.Address, value abi.TokenAmount, method abi.MethodNum, params interface{}) error {
+	return s.At(epoch, func(v *VM) error {
+		if _, code := v.ApplyMessage(from, to, value, method, params); code != exitcode.Ok {
+			return errors.Errorf("message from %v to %v method %d failed at epoch %d with exit code %v",
+				from, to, method, v.GetEpoch(), code)
+		}
+		return nil
+	})
+}
+
+// Advances the simulation one epoch at a time to an epoch, running the hooks scheduled for each epoch and then
+// cron at the end of each epoch passed through.
+// The simulation stops at the epoch with its hooks run, ready for further messages, and cron not yet run for it.
+func (s *Simulator) AdvanceTo(epoch abi.ChainEpoch) error {
+	if epoch < s.v.GetEpoch() {
+		return errors.Errorf("cannot advance from epoch %d back to %d", s.v.GetEpoch(), epoch)
+	}
+	for {
+		if err := s.runHooks(); err != nil {
+			return err
+		}
+		if s.v.GetEpoch() == epoch {
+			return nil
+		}
+		next, err := s.v.AdvanceToEpoch(s.v.GetEpoch() + 1)
+		if err != nil {
+			return err
+		}
+		s.v = next
+	}
+}
+
+// Runs the hooks scheduled for the current epoch, including any they schedule for it.
+func (s *Simulator) runHooks() error {
+	epoch := s.v.GetEpoch()
+	for i := 0; i < len(s.scheduled[epoch]); i++ {
+		if err := s.scheduled[epoch][i](s.v); err != nil {
+			return errors.Wrapf(err, "hook failed at epoch %d", epoch)
+		}
+	}
+	delete(s.scheduled, epoch)
+	return nil
+}
@@ -0,0 +1,80 @@
+package vm_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/power"
+)
+
+func TestSimulator(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("runs hooks at their epochs and cron at the end of each epoch", func(t *testing.T) {
+		v := NewVMWithSingletons(ctx, t)
+		addrs := CreateAccounts(ctx, t, v, 2, big.Mul(big.NewInt(10), FIL), 93837778)
+		sim := NewSimulator(v)
+
+		var ran []abi.ChainEpoch
+		record := func(v *VM) error {
+			ran = append(ran, v.GetEpoch())
+			return nil
+		}
+		require.NoError(t, sim.At(7, record))
+		require.NoError(t, sim.At(3, func(v *VM) error {
+			ran = append(ran, v.GetEpoch())
+			// A hook may schedule another for the same epoch, which runs after it.
+			return sim.At(3, record)
+		}))
+		require.NoError(t, sim.Send(5, addrs[0], addrs[1], FIL, builtin.MethodSend, nil))
+
+		require.NoError(t, sim.AdvanceTo(10))
+		assert.Equal(t, abi.ChainEpoch(10), sim.VM().GetEpoch())
+		assert.Equal(t, []abi.ChainEpoch{3, 3, 7}, ran)
+		recipient, found, err := sim.VM().GetActor(addrs[1])
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Equal(t, big.Mul(big.NewInt(11), FIL), recipient.Balance)
+
+		// Cron has run for every epoch before the current one.
+		var st power.State
+		require.NoError(t, sim.VM().GetState(builtin.StoragePowerActorAddr, &st))
+		assert.Equal(t, abi.ChainEpoch(10), st.FirstCronEpoch)
+	})
+
+	t.Run("hooks at the target epoch run before advance returns", func(t *testing.T) {
+		sim := NewSimulator(NewVMWithSingletons(ctx, t))
+		ran := false
+		require.NoError(t, sim.At(4, func(*VM) error {
+			ran = true
+			return nil
+		}))
+		require.NoError(t, sim.AdvanceTo(4))
+		assert.True(t, ran)
+	})
+
+	t.Run("rejects the past", func(t *testing.T) {
+		sim := NewSimulator(NewVMWithSingletons(ctx, t))
+		require.NoError(t, sim.AdvanceTo(5))
+		assert.Error(t, sim.At(4, func(*VM) error { return nil }))
+		assert.Error(t, sim.AdvanceTo(4))
+	})
+
+	t.Run("stops at a failed message", func(t *testing.T) {
+		v := NewVMWithSingletons(ctx, t)
+		addrs := CreateAccounts(ctx, t, v, 2, FIL, 93837778)
+		sim := NewSimulator(v)
+		require.NoError(t, sim.Send(2, addrs[0], addrs[1], big.Mul(big.NewInt(2), FIL), builtin.MethodSend, nil))
+
+		err := sim.AdvanceTo(5)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "epoch 2")
+		assert.Equal(t, abi.ChainEpoch(2), sim.VM().GetEpoch())
+	})
+}
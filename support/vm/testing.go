@@ -12,6 +12,7 @@ import (
 	"github.com/filecoin-project/go-state-types/cbor"
 	"github.com/filecoin-project/go-state-types/dline"
 	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/filecoin-project/go-state-types/network"
 	"github.com/ipfs/go-cid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -23,6 +24,7 @@ import (
 	initactor "github.com/filecoin-project/specs-actors/v2/actors/builtin/init"
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/market"
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/miner"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/paramreg"
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/power"
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/reward"
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin/system"
@@ -32,6 +34,7 @@ import (
 	"github.com/filecoin-project/specs-actors/v2/actors/util/adt"
 	"github.com/filecoin-project/specs-actors/v2/actors/util/smoothing"
 	"github.com/filecoin-project/specs-actors/v2/support/ipld"
+	"github.com/filecoin-project/specs-actors/v2/support/puppet"
 	actor_testing "github.com/filecoin-project/specs-actors/v2/support/testing"
 )
 
@@ -51,7 +54,7 @@ func init() {
 //
 
 // Creates a new VM and initializes all singleton actors plus a root verifier account.
-func NewVMWithSingletons(ctx context.Context, t *testing.T) *VM {
+func NewVMWithSingletons(ctx context.Context, t testing.TB) *VM {
 	store := ipld.NewADTStore(ctx)
 
 	lookup := map[cid.Cid]runtime.VMActor{}
@@ -68,9 +71,9 @@ func NewVMWithSingletons(ctx context.Context, t *testing.T) *VM {
 	emptyMultimapCID, err := adt.MakeEmptyMultimap(vm.store).Root()
 	require.NoError(t, err)
 
-	initializeActor(ctx, t, vm, &system.State{}, builtin.SystemActorCodeID, builtin.SystemActorAddr, big.Zero())
+	initializeActor(ctx, t, vm, system.ConstructState("scenarios", network.Version4), builtin.SystemActorCodeID, builtin.SystemActorAddr, big.Zero())
 
-	initState := initactor.ConstructState(emptyMapCID, "scenarios")
+	initState := initactor.ConstructState(emptyMapCID, emptyMapCID, emptyMapCID, "scenarios")
 	initializeActor(ctx, t, vm, initState, builtin.InitActorCodeID, builtin.InitActorAddr, big.Zero())
 
 	rewardState := reward.ConstructState(abi.NewStoragePower(0))
@@ -87,9 +90,15 @@ func NewVMWithSingletons(ctx context.Context, t *testing.T) *VM {
 
 	// this will need to be replaced with the address of a multisig actor for the verified registry to be tested accurately
 	initializeActor(ctx, t, vm, &account.State{Address: VerifregRoot}, builtin.AccountActorCodeID, VerifregRoot, big.Zero())
-	vrState := verifreg.ConstructState(emptyMapCID, VerifregRoot)
+	vrState := verifreg.ConstructState(emptyMapCID, emptyArrayCID, VerifregRoot)
 	initializeActor(ctx, t, vm, vrState, builtin.VerifiedRegistryActorCodeID, builtin.VerifiedRegistryActorAddr, big.Zero())
 
+	paramregState := paramreg.ConstructState(emptyArrayCID)
+	for _, p := range paramreg.PolicyParameters() {
+		require.NoError(t, paramregState.SetParameter(vm.store, p.Name, p.Value, vm.GetEpoch(), vm.networkVersion))
+	}
+	initializeActor(ctx, t, vm, paramregState, builtin.ParameterRegistryActorCodeID, builtin.ParameterRegistryActorAddr, big.Zero())
+
 	// burnt funds
 	initializeActor(ctx, t, vm, &account.State{Address: builtin.BurntFundsActorAddr}, builtin.AccountActorCodeID, builtin.BurntFundsActorAddr, big.Zero())
 
@@ -100,7 +109,7 @@ func NewVMWithSingletons(ctx context.Context, t *testing.T) *VM {
 }
 
 // Creates n account actors in the VM with the given balance
-func CreateAccounts(ctx context.Context, t *testing.T, vm *VM, n int, balance abi.TokenAmount, seed int64) []address.Address {
+func CreateAccounts(ctx context.Context, t testing.TB, vm *VM, n int, balance abi.TokenAmount, seed int64) []address.Address {
 	var initState initactor.State
 	err := vm.GetState(builtin.InitActorAddr, &initState)
 	require.NoError(t, err)
@@ -128,6 +137,30 @@ func CreateAccounts(ctx context.Context, t *testing.T, vm *VM, n int, balance ab
 	return pubAddrs
 }
 
+// Creates n puppet actors in the VM with the given balance, returning their ID-addresses.
+// The VM learns the puppet actor code if it does not already know it.
+func CreatePuppets(ctx context.Context, t testing.TB, vm *VM, n int, balance abi.TokenAmount, seed int64) []address.Address {
+	vm.actorImpls[puppet.PuppetActorCodeID] = puppet.Actor{}
+
+	var initState initactor.State
+	err := vm.GetState(builtin.InitActorAddr, &initState)
+	require.NoError(t, err)
+
+	idAddrs := make([]address.Address, n)
+	for i := range idAddrs {
+		robust := actor_testing.NewActorAddr(t, fmt.Sprintf("puppet-%d", seed+int64(i)))
+		idAddrs[i], err = initState.MapAddressToNewID(vm.store, robust)
+		require.NoError(t, err)
+	}
+	err = vm.setActorState(ctx, builtin.InitActorAddr, &initState)
+	require.NoError(t, err)
+
+	for _, idAddr := range idAddrs {
+		initializeActor(ctx, t, vm, puppet.ConstructState(), puppet.PuppetActorCodeID, idAddr, balance)
+	}
+	return idAddrs
+}
+
 //
 // Invocation expectations
 //
@@ -283,6 +316,30 @@ func ValueForInvocation(t *testing.T, vm *VM, idxs ...int) abi.TokenAmount {
 
 type advanceDeadlinePredicate func(dlInfo *dline.Info) bool
 
+// ApplyCron runs cron for the VM's current epoch, as the chain does after the epoch's messages.
+func ApplyCron(t *testing.T, v *VM) {
+	ApplyOk(t, v, builtin.SystemActorAddr, builtin.CronActorAddr, big.Zero(), builtin.MethodsCron.EpochTick, nil)
+}
+
+// AdvanceTillEpoch creates a new VM advanced one epoch at a time to epoch e, running cron at the end of the
+// current epoch and each one passed through, as the chain would (see VM.AdvanceToEpoch), and requiring cron to
+// conserve the total balance of all actors.
+// The VM returned is at e, ready for messages, and cron has not yet run for e.
+// Tests that advance only through these helpers run cron exactly once per epoch, so every actor sees each
+// epoch's end; AdvanceByDeadline is cheaper when only the miner's deadline processing matters.
+func AdvanceTillEpoch(t *testing.T, v *VM, e abi.ChainEpoch) *VM {
+	supply := TotalBalance(t, v)
+	v, err := v.AdvanceToEpoch(e)
+	require.NoError(t, err)
+	ExpectTotalBalance(t, v, supply)
+	return v
+}
+
+// AdvanceEpochs advances by n epochs, running cron at the end of each. See AdvanceTillEpoch.
+func AdvanceEpochs(t *testing.T, v *VM, n abi.ChainEpoch) *VM {
+	return AdvanceTillEpoch(t, v, v.GetEpoch()+n)
+}
+
 func MinerDLInfo(t *testing.T, v *VM, minerIDAddr address.Address) *dline.Info {
 	var minerState miner.State
 	err := v.GetState(minerIDAddr, &minerState)
@@ -475,17 +532,77 @@ func GetDealState(t *testing.T, vm *VM, dealID abi.DealID) (*market.DealState, b
 // Misc. helpers
 //
 
-func ApplyOk(t *testing.T, v *VM, from, to address.Address, value abi.TokenAmount, method abi.MethodNum, params interface{}) cbor.Marshaler {
+// Applies a message, requiring it to succeed and to conserve the total balance of all actors.
+func ApplyOk(t testing.TB, v *VM, from, to address.Address, value abi.TokenAmount, method abi.MethodNum, params interface{}) cbor.Marshaler {
+	supply := TotalBalance(t, v)
 	ret, code := v.ApplyMessage(from, to, value, method, params)
 	require.Equal(t, exitcode.Ok, code)
+	ExpectTotalBalance(t, v, supply)
 	return ret
 }
 
+//
+// Value flow
+//
+
+// Returns the total balance of all actors, i.e. the supply of tokens in the VM.
+func TotalBalance(t testing.TB, v *VM) abi.TokenAmount {
+	total, err := v.GetTotalActorBalance()
+	require.NoError(t, err)
+	return total
+}
+
+// Requires the total balance of all actors to equal a supply recorded earlier.
+// Messages move value between actors, burning it by transfer to the burnt funds actor, and so never change the
+// total; a difference reveals value created or destroyed by faulty accounting.
+func ExpectTotalBalance(t testing.TB, v *VM, supply abi.TokenAmount) {
+	total := TotalBalance(t, v)
+	require.True(t, total.Equals(supply), "total balance %v differs from supply %v by %v",
+		total, supply, big.Sub(total, supply))
+}
+
+// Returns the net value moved between two actors by the successful messages applied to this VM: the value
+// transferred from -> to, less that transferred to -> from. Transfers rolled back are excluded.
+// Note that a VM advanced to a new epoch starts with no messages.
+func NetTransfer(t *testing.T, v *VM, from, to address.Address) abi.TokenAmount {
+	fromID, found := v.NormalizeAddress(from)
+	require.True(t, found, "no actor %v", from)
+	toID, found := v.NormalizeAddress(to)
+	require.True(t, found, "no actor %v", to)
+
+	net := big.Zero()
+	for _, tr := range v.Transfers() {
+		if tr.From == fromID && tr.To == toID {
+			net = big.Add(net, tr.Amount)
+		} else if tr.From == toID && tr.To == fromID {
+			net = big.Sub(net, tr.Amount)
+		}
+	}
+	return net
+}
+
+// Requires the net value moved between two actors by the successful messages applied to this VM to equal amount.
+func ExpectNetTransfer(t *testing.T, v *VM, from, to address.Address, amount abi.TokenAmount) {
+	net := NetTransfer(t, v, from, to)
+	require.True(t, net.Equals(amount), "net transfer %v -> %v is %v, expected %v", from, to, net, amount)
+}
+
+//
+// Gas
+//
+
+// Requires the last message applied to have used less than `max` gas, as priced by the VM's gas charger and
+// charged explicitly by actors. Bounds on the gas of representative messages catch changes which regress it.
+func ExpectGasLessThan(t testing.TB, v *VM, max int64) {
+	t.Helper()
+	require.Less(t, v.GasUsed(), max, "message used %d gas, expected less than %d", v.GasUsed(), max)
+}
+
 //
 //  internal stuff
 //
 
-func initializeActor(ctx context.Context, t *testing.T, vm *VM, state cbor.Marshaler, code cid.Cid, a address.Address, balance abi.TokenAmount) {
+func initializeActor(ctx context.Context, t testing.TB, vm *VM, state cbor.Marshaler, code cid.Cid, a address.Address, balance abi.TokenAmount) {
 	stateCID, err := vm.store.Put(ctx, state)
 	require.NoError(t, err)
 	actor := &states.Actor{
@@ -0,0 +1,406 @@
+package vm_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/cbor"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/filecoin-project/go-state-types/network"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/exported"
+	"github.com/filecoin-project/specs-actors/v2/actors/runtime"
+	"github.com/filecoin-project/specs-actors/v2/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/v2/support/ipld"
+)
+
+// A test vector of the message class, in the subset of the schema shared among Filecoin implementations that
+// this VM can execute: messages applied to a pre-state, with the receipts and post-state expected.
+// Vectors let this implementation be checked against others, and others against this one.
+//
+// The VM charges no gas and does not check sender nonces, so the post-state of a vector recorded by an
+// implementation that does will differ from the VM's, though the receipts' exit codes and return values should
+// match. Vectors recorded by RecordTestVector match in full.
+type TestVector struct {
+	Class string      `json:"class"`
+	Meta  *VectorMeta `json:"_meta,omitempty"`
+	// The pre-state, and any other blocks required, as a gzipped CAR.
+	CAR           []byte               `json:"car"`
+	Pre           VectorPreconditions  `json:"preconditions"`
+	ApplyMessages []VectorMessage      `json:"apply_messages"`
+	Post          VectorPostconditions `json:"postconditions"`
+}
+
+type VectorMeta struct {
+	ID      string `json:"id,omitempty"`
+	Comment string `json:"comment,omitempty"`
+}
+
+type VectorPreconditions struct {
+	Epoch     abi.ChainEpoch  `json:"epoch"`
+	StateTree VectorStateTree `json:"state_tree"`
+}
+
+type VectorPostconditions struct {
+	StateTree VectorStateTree `json:"state_tree"`
+	Receipts  []VectorReceipt `json:"receipts"`
+}
+
+type VectorStateTree struct {
+	RootCID cid.Cid `json:"root_cid"`
+}
+
+// A message to apply, serialized as a chain message, at an epoch offset from the pre-state's.
+type VectorMessage struct {
+	Bytes       []byte         `json:"bytes"`
+	EpochOffset abi.ChainEpoch `json:"epoch_offset,omitempty"`
+}
+
+type VectorReceipt struct {
+	ExitCode    exitcode.ExitCode `json:"exit_code"`
+	ReturnValue []byte            `json:"return"`
+	GasUsed     int64             `json:"gas_used"`
+}
+
+// A message as serialized on chain.
+// The VM ignores the version, nonce and gas fields.
+type ChainMessage struct {
+	Version    uint64
+	To         address.Address
+	From       address.Address
+	Nonce      uint64
+	Value      abi.TokenAmount
+	GasLimit   int64
+	GasFeeCap  abi.TokenAmount
+	GasPremium abi.TokenAmount
+	Method     abi.MethodNum
+	Params     []byte
+}
+
+// The receipts and post-state root resulting from applying a vector's messages.
+type VectorResult struct {
+	Receipts []VectorReceipt
+	Root     cid.Cid
+}
+
+// Reads a test vector from a JSON file.
+func LoadTestVector(path string) (*TestVector, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var vector TestVector
+	if err := json.NewDecoder(f).Decode(&vector); err != nil {
+		return nil, fmt.Errorf("failed to decode test vector %s: %w", path, err)
+	}
+	return &vector, nil
+}
+
+// Applies the vector's messages to its pre-state in a VM executing the builtin actors.
+func (tv *TestVector) Apply(ctx context.Context) (*VectorResult, error) {
+	if tv.Class != "message" {
+		return nil, fmt.Errorf("unsupported test vector class %q", tv.Class)
+	}
+	store := ipld.NewADTStore(ctx)
+	gz, err := gzip.NewReader(bytes.NewReader(tv.CAR))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress CAR: %w", err)
+	}
+	if _, err := ipld.ReadCAR(store, gz); err != nil {
+		return nil, err
+	}
+
+	lookup := map[cid.Cid]runtime.VMActor{}
+	for _, ba := range exported.BuiltinActors() {
+		lookup[ba.Code()] = ba
+	}
+	v, err := NewVMAtEpoch(ctx, lookup, store, tv.Pre.StateTree.RootCID, tv.Pre.Epoch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load pre-state: %w", err)
+	}
+
+	result := &VectorResult{}
+	for i, m := range tv.ApplyMessages {
+		var msg ChainMessage
+		if err := msg.UnmarshalCBOR(bytes.NewReader(m.Bytes)); err != nil {
+			return nil, fmt.Errorf("failed to decode message %d: %w", i, err)
+		}
+		if epoch := tv.Pre.Epoch + m.EpochOffset; epoch != v.GetEpoch() {
+			if v, err = v.WithEpoch(epoch); err != nil {
+				return nil, err
+			}
+		}
+
+		receipt, err := applyChainMessage(v, &msg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply message %d: %w", i, err)
+		}
+		result.Receipts = append(result.Receipts, receipt)
+	}
+
+	if result.Root, err = v.StateRoot(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Applies a vector's messages, requiring the receipts' exit codes and return values, and the post-state root,
+// to be those expected. Gas used is not compared.
+func CheckTestVector(t *testing.T, tv *TestVector) {
+	result, err := tv.Apply(context.Background())
+	require.NoError(t, err)
+	require.Len(t, result.Receipts, len(tv.Post.Receipts), "number of receipts")
+	for i, expected := range tv.Post.Receipts {
+		actual := result.Receipts[i]
+		require.Equal(t, expected.ExitCode, actual.ExitCode, "exit code of message %d", i)
+		require.Equal(t, len(expected.ReturnValue) == 0, len(actual.ReturnValue) == 0, "return value of message %d", i)
+		if len(expected.ReturnValue) > 0 {
+			require.Equal(t, expected.ReturnValue, actual.ReturnValue, "return value of message %d", i)
+		}
+	}
+	require.Equal(t, tv.Post.StateTree.RootCID, result.Root, "post-state root")
+}
+
+// Records a test vector of messages applied to a VM's current state, at its current epoch, applying them.
+// Tests may write the vector out for other implementations to check themselves against.
+func RecordTestVector(t *testing.T, v *VM, id string, msgs ...*ChainMessage) *TestVector {
+	preRoot, err := v.StateRoot()
+	require.NoError(t, err)
+	car, err := writeStateCAR(v.Store(), preRoot)
+	require.NoError(t, err)
+
+	tv := &TestVector{
+		Class: "message",
+		Meta:  &VectorMeta{ID: id},
+		CAR:   car,
+		Pre: VectorPreconditions{
+			Epoch:     v.GetEpoch(),
+			StateTree: VectorStateTree{RootCID: preRoot},
+		},
+	}
+	for _, msg := range msgs {
+		var buf bytes.Buffer
+		require.NoError(t, msg.MarshalCBOR(&buf))
+		tv.ApplyMessages = append(tv.ApplyMessages, VectorMessage{Bytes: buf.Bytes()})
+
+		receipt, err := applyChainMessage(v, msg)
+		require.NoError(t, err)
+		tv.Post.Receipts = append(tv.Post.Receipts, receipt)
+	}
+
+	tv.Post.StateTree.RootCID, err = v.StateRoot()
+	require.NoError(t, err)
+	return tv
+}
+
+func applyChainMessage(v *VM, msg *ChainMessage) (VectorReceipt, error) {
+	ret, code := v.ApplyMessage(msg.From, msg.To, msg.Value, msg.Method, msg.Params)
+	return newVectorReceipt(ret, code)
+}
+
+func newVectorReceipt(ret cbor.Marshaler, code exitcode.ExitCode) (VectorReceipt, error) {
+	receipt := VectorReceipt{ExitCode: code}
+	if ret != nil {
+		var buf bytes.Buffer
+		if err := ret.MarshalCBOR(&buf); err != nil {
+			return VectorReceipt{}, fmt.Errorf("failed to encode return value: %w", err)
+		}
+		receipt.ReturnValue = buf.Bytes()
+	}
+	return receipt, nil
+}
+
+// Writes the blocks reachable from a state root as a gzipped CAR.
+func writeStateCAR(store adt.Store, root cid.Cid) ([]byte, error) {
+	var car bytes.Buffer
+	gz := gzip.NewWriter(&car)
+	if err := ipld.WriteCAR(store, gz, root); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return car.Bytes(), nil
+}
+
+// The environment variable naming a directory to which Recorder.Finish writes the vectors recorded by scenarios.
+const RecordVectorsEnv = "SPECS_ACTORS_RECORD_VECTORS"
+
+// A Recorder records a scenario run on a VM as a test vector, so that scenario tests written against the VM may be
+// exported for other implementations to replay.
+// Recording starts from a VM's state and epoch. Every message then applied to the VM returned by NewRecorder, and
+// to the VMs derived from it with WithEpoch or AdvanceToEpoch, is recorded, including the cron ticks applied at the
+// end of epochs. A VM derived from a recording VM carries its own copy of the messages recorded so far, so subtests
+// which branch from a common VM record independent vectors sharing the same pre-state.
+//
+// Vectors are replayed at the latest network version, so messages applied at other versions cannot be recorded.
+type Recorder struct {
+	preEpoch abi.ChainEpoch
+	preRoot  cid.Cid
+	car      []byte
+}
+
+// The messages applied to a VM since a Recorder started recording, and their receipts.
+type recording struct {
+	recorder *Recorder
+	messages []VectorMessage
+	receipts []VectorReceipt
+}
+
+// Starts recording from a VM's current state and epoch, returning the recorder and a VM at that state and epoch,
+// on which the scenario is to continue.
+// The VM returned does not have v's history of past state roots, which a vector does not carry, so that actors
+// consulting the state of past epochs see the same states when the vector is replayed.
+func NewRecorder(t *testing.T, v *VM) (*Recorder, *VM) {
+	preRoot, err := v.StateRoot()
+	require.NoError(t, err)
+	car, err := writeStateCAR(v.Store(), preRoot)
+	require.NoError(t, err)
+	recorded, err := NewVMAtEpoch(v.ctx, v.actorImpls, v.store, preRoot, v.GetEpoch())
+	require.NoError(t, err)
+	recorded.networkVersion = v.networkVersion
+
+	r := &Recorder{
+		preEpoch: v.GetEpoch(),
+		preRoot:  preRoot,
+		car:      car,
+	}
+	recorded.recording = &recording{recorder: r}
+	return r, recorded
+}
+
+// Returns a test vector of the messages recorded by a VM since recording started, with its current state as the
+// post-state, having checked that the vector replays to the same receipts and post-state.
+// If the environment variable SPECS_ACTORS_RECORD_VECTORS names a directory, the vector is also written there.
+func (r *Recorder) Finish(t *testing.T, v *VM, id string) *TestVector {
+	require.NotNil(t, v.recording, "VM is not recording")
+	require.Equal(t, r, v.recording.recorder, "VM is recording for another recorder")
+	postRoot, err := v.StateRoot()
+	require.NoError(t, err)
+
+	tv := &TestVector{
+		Class: "message",
+		Meta:  &VectorMeta{ID: id},
+		CAR:   r.car,
+		Pre: VectorPreconditions{
+			Epoch:     r.preEpoch,
+			StateTree: VectorStateTree{RootCID: r.preRoot},
+		},
+		ApplyMessages: append([]VectorMessage(nil), v.recording.messages...),
+		Post: VectorPostconditions{
+			StateTree: VectorStateTree{RootCID: postRoot},
+			Receipts:  append([]VectorReceipt(nil), v.recording.receipts...),
+		},
+	}
+	CheckTestVector(t, tv)
+
+	if dir := os.Getenv(RecordVectorsEnv); dir != "" {
+		require.NoError(t, WriteTestVectorFile(dir, tv))
+	}
+	return tv
+}
+
+// Returns a copy of the recording for a VM derived from one recording, or nil if the VM is not recording.
+func (rec *recording) fork() *recording {
+	if rec == nil {
+		return nil
+	}
+	return &recording{
+		recorder: rec.recorder,
+		messages: append([]VectorMessage(nil), rec.messages...),
+		receipts: append([]VectorReceipt(nil), rec.receipts...),
+	}
+}
+
+// Records a message applied to a VM, serialized as a chain message, and its receipt.
+func (rec *recording) record(v *VM, from, to address.Address, value abi.TokenAmount, method abi.MethodNum, params interface{},
+	ret cbor.Marshaler, code exitcode.ExitCode) error {
+	if v.networkVersion != network.VersionMax {
+		return fmt.Errorf("cannot record message at network version %d, vectors are replayed at %d", v.networkVersion, network.VersionMax)
+	}
+	var paramBytes []byte
+	switch p := params.(type) {
+	case []byte:
+		paramBytes = p
+	case cbor.Marshaler:
+		if rv := reflect.ValueOf(p); rv.Kind() != reflect.Ptr || !rv.IsNil() {
+			var buf bytes.Buffer
+			if err := p.MarshalCBOR(&buf); err != nil {
+				return fmt.Errorf("failed to encode params: %w", err)
+			}
+			paramBytes = buf.Bytes()
+		}
+	case nil:
+	default:
+		return fmt.Errorf("cannot record params of type %T", params)
+	}
+
+	var msg bytes.Buffer
+	if err := NewChainMessage(from, to, value, method, paramBytes).MarshalCBOR(&msg); err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+	receipt, err := newVectorReceipt(ret, code)
+	if err != nil {
+		return err
+	}
+	rec.messages = append(rec.messages, VectorMessage{
+		Bytes:       msg.Bytes(),
+		EpochOffset: v.GetEpoch() - rec.recorder.preEpoch,
+	})
+	rec.receipts = append(rec.receipts, receipt)
+	return nil
+}
+
+// Writes a test vector as JSON.
+func WriteTestVector(w io.Writer, tv *TestVector) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(tv)
+}
+
+// Writes a test vector as JSON to a file in a directory, named by the vector's ID, creating the directory if needed.
+func WriteTestVectorFile(dir string, tv *TestVector) error {
+	if tv.Meta == nil || tv.Meta.ID == "" {
+		return fmt.Errorf("test vector has no ID to name its file")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(filepath.Join(dir, tv.Meta.ID+".json"))
+	if err != nil {
+		return err
+	}
+	if err := WriteTestVector(f, tv); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Returns a chain message with zero gas fields, for recording in a vector.
+func NewChainMessage(from, to address.Address, value abi.TokenAmount, method abi.MethodNum, params []byte) *ChainMessage {
+	return &ChainMessage{
+		To:         to,
+		From:       from,
+		Value:      value,
+		GasFeeCap:  big.Zero(),
+		GasPremium: big.Zero(),
+		Method:     method,
+		Params:     params,
+	}
+}
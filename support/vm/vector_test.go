@@ -0,0 +1,109 @@
+package vm_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+)
+
+func TestRecordedVectorReplays(t *testing.T) {
+	ctx := context.Background()
+	v := NewVMWithSingletons(ctx, t)
+	addrs := CreateAccounts(ctx, t, v, 2, big.Mul(big.NewInt(10), FIL), 93837778)
+	payer, payee := addrs[0], addrs[1]
+
+	var params bytes.Buffer
+	require.NoError(t, payee.MarshalCBOR(&params))
+	tv := RecordTestVector(t, v, "add-balance",
+		NewChainMessage(payer, payee, FIL, builtin.MethodSend, nil),
+		NewChainMessage(payer, builtin.StorageMarketActorAddr, FIL, builtin.MethodsMarket.AddBalance, params.Bytes()),
+		NewChainMessage(payer, payee, big.Mul(big.NewInt(100), FIL), builtin.MethodSend, nil),
+	)
+	require.Len(t, tv.Post.Receipts, 3)
+	assert.Equal(t, exitcode.Ok, tv.Post.Receipts[1].ExitCode)
+	assert.Equal(t, exitcode.SysErrInsufficientFunds, tv.Post.Receipts[2].ExitCode)
+
+	// The vector survives serialization, and replays from its CAR alone.
+	var buf bytes.Buffer
+	require.NoError(t, WriteTestVector(&buf, tv))
+	var decoded TestVector
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	CheckTestVector(t, &decoded)
+
+	// A vector expecting a different outcome does not match.
+	decoded.ApplyMessages = decoded.ApplyMessages[:1]
+	result, err := decoded.Apply(ctx)
+	require.NoError(t, err)
+	assert.NotEqual(t, decoded.Post.StateTree.RootCID, result.Root)
+}
+
+func TestRecorder(t *testing.T) {
+	ctx := context.Background()
+	v := NewVMWithSingletons(ctx, t)
+	addrs := CreateAccounts(ctx, t, v, 2, big.Mul(big.NewInt(10), FIL), 93837778)
+	payer, payee := addrs[0], addrs[1]
+
+	rec, v := NewRecorder(t, v)
+	_, code := v.ApplyMessage(payer, payee, FIL, builtin.MethodSend, nil)
+	require.Equal(t, exitcode.Ok, code)
+	v, err := v.AdvanceToEpoch(v.GetEpoch() + 2)
+	require.NoError(t, err)
+
+	// Branches from a common VM record independent vectors.
+	branch, err := v.WithEpoch(v.GetEpoch())
+	require.NoError(t, err)
+	_, code = branch.ApplyMessage(payer, builtin.StorageMarketActorAddr, FIL, builtin.MethodsMarket.AddBalance, &payee)
+	require.Equal(t, exitcode.Ok, code)
+	branchVector := rec.Finish(t, branch, "recorder-branch")
+
+	_, code = v.ApplyMessage(payer, payee, big.Mul(big.NewInt(100), FIL), builtin.MethodSend, nil)
+	require.Equal(t, exitcode.SysErrInsufficientFunds, code)
+	tv := rec.Finish(t, v, "recorder")
+
+	// Both vectors record the send and the two cron ticks, then their own message at the later epoch.
+	require.Len(t, tv.ApplyMessages, 4)
+	require.Len(t, branchVector.ApplyMessages, 4)
+	assert.Equal(t, tv.ApplyMessages[:3], branchVector.ApplyMessages[:3])
+	assert.Equal(t, abi.ChainEpoch(0), tv.ApplyMessages[0].EpochOffset)
+	assert.Equal(t, abi.ChainEpoch(1), tv.ApplyMessages[2].EpochOffset)
+	assert.Equal(t, abi.ChainEpoch(2), tv.ApplyMessages[3].EpochOffset)
+	assert.Equal(t, exitcode.SysErrInsufficientFunds, tv.Post.Receipts[3].ExitCode)
+	assert.Equal(t, exitcode.Ok, branchVector.Post.Receipts[3].ExitCode)
+	assert.Equal(t, tv.Pre, branchVector.Pre)
+
+	dir := t.TempDir()
+	require.NoError(t, WriteTestVectorFile(dir, tv))
+	loaded, err := LoadTestVector(filepath.Join(dir, "recorder.json"))
+	require.NoError(t, err)
+	CheckTestVector(t, loaded)
+}
+
+// Checks the vectors in the directory named by the environment variable SPECS_ACTORS_TEST_VECTORS, if set, so
+// that vectors from other implementations may be run against these actors.
+func TestExternalVectors(t *testing.T) {
+	dir := os.Getenv("SPECS_ACTORS_TEST_VECTORS")
+	if dir == "" {
+		t.Skip("SPECS_ACTORS_TEST_VECTORS not set")
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	require.NoError(t, err)
+	for _, path := range paths {
+		path := path
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			tv, err := LoadTestVector(path)
+			require.NoError(t, err)
+			CheckTestVector(t, tv)
+		})
+	}
+}
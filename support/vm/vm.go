@@ -3,6 +3,7 @@ package vm_test
 import (
 	"context"
 	"fmt"
+	"math"
 
 	"github.com/filecoin-project/go-address"
 	hamt "github.com/filecoin-project/go-hamt-ipld/v2"
@@ -16,16 +17,18 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v2/actors/builtin/account"
 	init_ "github.com/filecoin-project/specs-actors/v2/actors/builtin/init"
 	"github.com/filecoin-project/specs-actors/v2/actors/runtime"
 	"github.com/filecoin-project/specs-actors/v2/actors/states"
 	"github.com/filecoin-project/specs-actors/v2/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/v2/support/mock"
 )
 
 // VM is a simplified message execution framework for the purposes of testing inter-actor communication.
 // The VM maintains actor state and can be used to simulate message validation for a single block or tipset.
-// The VM does not track gas charges, provide working syscalls, validate message nonces and many other things
-// that a compliant VM needs to do.
+// The VM does not meter gas against a limit unless one is set (see SetGasCharger and SetGasLimit), provide
+// working syscalls, validate message nonces and many other things that a compliant VM needs to do.
 type VM struct {
 	ctx   context.Context
 	store adt.Store
@@ -37,12 +40,20 @@ type VM struct {
 	stateRoot   cid.Cid  // The last committed root.
 	actors      *adt.Map // The current (not necessarily committed) root node.
 	actorsDirty bool
+	history     map[abi.ChainEpoch]cid.Cid // Roots committed at the end of past epochs, keyed by the epoch.
 
 	emptyObject cid.Cid
 
+	gasCharger  mock.GasCharger    // Prices the operations of messages, which are charged nothing if nil.
+	prices      mock.PriceSchedule // The schedule from which the charger is selected by network version, if any.
+	gasLimit    int64              // Gas limit of each message, or zero if unlimited.
+	lastGasUsed int64              // Gas used by the last message applied.
+
 	logs            []string
 	invocationStack []*Invocation
 	invocations     []*Invocation
+
+	recording *recording // The messages applied since a Recorder started recording, if one did.
 }
 
 // VM types
@@ -62,6 +73,21 @@ type Invocation struct {
 	Exitcode       exitcode.ExitCode
 	Ret            cbor.Marshaler
 	SubInvocations []*Invocation
+	Events         []Event    // Events emitted directly by this invocation
+	Transfers      []Transfer // Value transferred directly by this invocation
+}
+
+// A movement of value from one actor to another, between ID addresses.
+type Transfer struct {
+	From   address.Address
+	To     address.Address
+	Amount abi.TokenAmount
+}
+
+// An event emitted by an actor, with each entry value in serialized form.
+type Event struct {
+	Emitter address.Address
+	Entries []runtime.EventEntry
 }
 
 // NewVM creates a new runtime for executing messages.
@@ -109,6 +135,7 @@ func NewVMAtEpoch(ctx context.Context, actorImpls ActorImplLookup, store adt.Sto
 		stateRoot:      stateRoot,
 		actorsDirty:    false,
 		emptyObject:    emptyObject,
+		currentEpoch:   epoch,
 		networkVersion: network.VersionMax,
 	}, nil
 }
@@ -119,6 +146,16 @@ func (vm *VM) WithEpoch(epoch abi.ChainEpoch) (*VM, error) {
 		return nil, err
 	}
 
+	history := make(map[abi.ChainEpoch]cid.Cid, len(vm.history)+1)
+	for e, root := range vm.history { //nolint:nomaprange
+		if e < epoch {
+			history[e] = root
+		}
+	}
+	if vm.currentEpoch < epoch {
+		history[vm.currentEpoch] = vm.stateRoot
+	}
+
 	actors, err := adt.AsMap(vm.store, vm.stateRoot)
 	if err != nil {
 		return nil, err
@@ -132,11 +169,39 @@ func (vm *VM) WithEpoch(epoch abi.ChainEpoch) (*VM, error) {
 		stateRoot:      vm.stateRoot,
 		actorsDirty:    false,
 		emptyObject:    vm.emptyObject,
+		history:        history,
 		currentEpoch:   epoch,
 		networkVersion: vm.networkVersion,
+		gasCharger:     vm.gasCharger,
+		prices:         vm.prices,
+		gasLimit:       vm.gasLimit,
+		recording:      vm.recording.fork(),
 	}, nil
 }
 
+// AdvanceToEpoch returns a new VM advanced one epoch at a time to epoch e, running cron at the end of the
+// current epoch and each one passed through, as the chain does after each epoch's messages. The cron tick is sent
+// by the system actor, as the implicit message of the chain, so the cron actor dispatches to the power and market
+// actors' handlers exactly as on chain.
+// The VM returned is at e, ready for messages, and cron has not yet run for e. An error is returned if cron fails
+// at any epoch, which the chain does not permit.
+func (vm *VM) AdvanceToEpoch(e abi.ChainEpoch) (*VM, error) {
+	if e < vm.currentEpoch {
+		return nil, errors.Errorf("cannot advance from epoch %d back to %d", vm.currentEpoch, e)
+	}
+	for vm.currentEpoch < e {
+		if _, code := vm.ApplyMessage(builtin.SystemActorAddr, builtin.CronActorAddr, big.Zero(), builtin.MethodsCron.EpochTick, nil); code != exitcode.Ok {
+			return nil, errors.Errorf("cron failed at epoch %d with exit code %v", vm.currentEpoch, code)
+		}
+		next, err := vm.WithEpoch(vm.currentEpoch + 1)
+		if err != nil {
+			return nil, err
+		}
+		vm = next
+	}
+	return vm, nil
+}
+
 func (vm *VM) WithNetworkVersion(nv network.Version) (*VM, error) {
 	_, err := vm.checkpoint()
 	if err != nil {
@@ -148,6 +213,10 @@ func (vm *VM) WithNetworkVersion(nv network.Version) (*VM, error) {
 		return nil, err
 	}
 
+	gasCharger := vm.gasCharger
+	if vm.prices != nil {
+		gasCharger = vm.prices.ForVersion(nv)
+	}
 	return &VM{
 		ctx:            vm.ctx,
 		actorImpls:     vm.actorImpls,
@@ -156,8 +225,13 @@ func (vm *VM) WithNetworkVersion(nv network.Version) (*VM, error) {
 		stateRoot:      vm.stateRoot,
 		actorsDirty:    false,
 		emptyObject:    vm.emptyObject,
+		history:        vm.history,
 		currentEpoch:   vm.currentEpoch,
 		networkVersion: nv,
+		gasCharger:     gasCharger,
+		prices:         vm.prices,
+		gasLimit:       vm.gasLimit,
+		recording:      vm.recording.fork(),
 	}, nil
 }
 
@@ -267,7 +341,18 @@ func (vm *VM) NormalizeAddress(addr address.Address) (address.Address, bool) {
 }
 
 // ApplyMessage applies the message to the current state.
+// The message and its receipt are recorded if a Recorder is recording the VM.
 func (vm *VM) ApplyMessage(from, to address.Address, value abi.TokenAmount, method abi.MethodNum, params interface{}) (cbor.Marshaler, exitcode.ExitCode) {
+	ret, code := vm.applyMessage(from, to, value, method, params)
+	if vm.recording != nil {
+		if err := vm.recording.record(vm, from, to, value, method, params, ret, code); err != nil {
+			panic(err)
+		}
+	}
+	return ret, code
+}
+
+func (vm *VM) applyMessage(from, to address.Address, value abi.TokenAmount, method abi.MethodNum, params interface{}) (cbor.Marshaler, exitcode.ExitCode) {
 	// This method does not actually execute the message itself,
 	// but rather deals with the pre/post processing of a message.
 	// (see: `invocationContext.invoke()` for the dispatch and execution)
@@ -287,6 +372,17 @@ func (vm *VM) ApplyMessage(from, to address.Address, value abi.TokenAmount, meth
 		return nil, exitcode.SysErrSenderInvalid
 	}
 
+	// The VM does not check message nonces, but increments the sender's call sequence number as the chain does, so
+	// that the addresses of actors created by successive messages from a sender are distinct.
+	// Implicit messages from the system actor do not increment it.
+	callSeq := fromActor.CallSeqNum
+	if from != builtin.SystemActorAddr {
+		fromActor.CallSeqNum++
+		if err := vm.setActor(vm.ctx, from, fromActor); err != nil {
+			panic(err)
+		}
+	}
+
 	// checkpoint state
 	// Even if the message fails, the following accumulated changes will be applied:
 	// - CallSeqNumber increment
@@ -302,7 +398,9 @@ func (vm *VM) ApplyMessage(from, to address.Address, value abi.TokenAmount, meth
 	// 3. process the msg
 
 	topLevel := topLevelContext{
-		newActorAddressCount: 0,
+		originatorStableAddress: vm.stableAddress(from, fromActor),
+		originatorCallSeq:       callSeq,
+		newActorAddressCount:    0,
 	}
 
 	// build internal msg
@@ -315,10 +413,15 @@ func (vm *VM) ApplyMessage(from, to address.Address, value abi.TokenAmount, meth
 	}
 
 	// build invocation context
-	ctx := newInvocationContext(vm, &topLevel, imsg, fromActor, vm.emptyObject)
+	gasLimit := vm.gasLimit
+	if gasLimit == 0 {
+		gasLimit = math.MaxInt64
+	}
+	ctx := newInvocationContext(vm, &topLevel, imsg, fromActor, vm.emptyObject, gasLimit)
 
 	// 3. invoke
 	ret, exitCode := ctx.invoke()
+	vm.lastGasUsed = topLevel.gasUsed
 
 	// Roll back all state if the receipt's exit code is not ok.
 	// This is required in addition to rollback within the invocation context since top level messages can fail for
@@ -333,6 +436,18 @@ func (vm *VM) ApplyMessage(from, to address.Address, value abi.TokenAmount, meth
 	return ret.inner, exitCode
 }
 
+// Returns the public key address of an account actor, or the ID address of any other actor.
+func (vm *VM) stableAddress(id address.Address, act *states.Actor) address.Address {
+	if act.Code != builtin.AccountActorCodeID {
+		return id
+	}
+	var st account.State
+	if err := vm.store.Get(vm.ctx, act.Head, &st); err != nil {
+		panic(err)
+	}
+	return st.Address
+}
+
 func (vm *VM) GetState(addr address.Address, out cbor.Unmarshaler) error {
 	act, found, err := vm.GetActor(addr)
 	if err != nil {
@@ -353,6 +468,11 @@ func (vm *VM) GetStateTree() (*states.Tree, error) {
 	return states.LoadTree(vm.store, root)
 }
 
+// StateRoot commits pending changes and returns the root of the state tree.
+func (vm *VM) StateRoot() (cid.Cid, error) {
+	return vm.checkpoint()
+}
+
 func (vm *VM) GetTotalActorBalance() (abi.TokenAmount, error) {
 	tree, err := vm.GetStateTree()
 	if err != nil {
@@ -373,11 +493,53 @@ func (vm *VM) Store() adt.Store {
 	return vm.store
 }
 
+// Sets the charger pricing the store operations, sends and syscalls of subsequent messages, in addition to the
+// gas actors charge explicitly. VMs derived from this one inherit the charger.
+func (vm *VM) SetGasCharger(g mock.GasCharger) {
+	vm.gasCharger = g
+	vm.prices = nil
+}
+
+// Sets the price lists of subsequent messages by network version, charging those of the VM's version and of the
+// version given to WithNetworkVersion by VMs derived from this one. The schedule must be valid.
+func (vm *VM) SetPriceSchedule(s mock.PriceSchedule) {
+	vm.gasCharger = s.ForVersion(vm.networkVersion)
+	vm.prices = s
+}
+
+// Sets the gas limit of subsequent messages, which abort with SysErrOutOfGas when the gas charged to them
+// exceeds it, or zero for no limit. VMs derived from this one inherit the limit.
+func (vm *VM) SetGasLimit(limit int64) {
+	vm.gasLimit = limit
+}
+
+// Returns the gas used by the last message applied, whether or not it succeeded.
+func (vm *VM) GasUsed() int64 {
+	return vm.lastGasUsed
+}
+
 // Get the chain epoch for this vm
 func (vm *VM) GetEpoch() abi.ChainEpoch {
 	return vm.currentEpoch
 }
 
+// Returns the state root as committed at the end of a past epoch, if the VM has passed through it.
+// An epoch that was skipped over has the state of the most recent epoch before it.
+func (vm *VM) historicalStateRoot(epoch abi.ChainEpoch) (cid.Cid, bool) {
+	found := false
+	var latest abi.ChainEpoch
+	for e := range vm.history { //nolint:nomaprange
+		if e <= epoch && (!found || e > latest) {
+			latest = e
+			found = true
+		}
+	}
+	if !found {
+		return cid.Undef, false
+	}
+	return vm.history[latest], true
+}
+
 // transfer debits money from one account and credits it to another.
 // avoid calling this method with a zero amount else it will perform unnecessary actor loading.
 //
@@ -426,6 +588,13 @@ func (vm *VM) transfer(debitFrom address.Address, creditTo address.Address, amou
 	if err := vm.setActor(ctx, creditTo, toActor); err != nil {
 		panic(err)
 	}
+
+	if len(vm.invocationStack) > 0 {
+		current := vm.invocationStack[len(vm.invocationStack)-1]
+		fromID, _ := vm.NormalizeAddress(debitFrom)
+		toID, _ := vm.NormalizeAddress(creditTo)
+		current.Transfers = append(current.Transfers, Transfer{From: fromID, To: toID, Amount: amount})
+	}
 	return toActor, fromActor
 }
 
@@ -469,6 +638,52 @@ func (vm *VM) LastInvocation() *Invocation {
 	return vm.invocations[len(vm.invocations)-1]
 }
 
+// Returns the events emitted by all successful invocations, in order of emission.
+// Events emitted by invocations that aborted, or that were made by invocations that aborted, are excluded.
+func (vm *VM) Events() []Event {
+	var events []Event
+	var collect func(inv *Invocation)
+	collect = func(inv *Invocation) {
+		if !inv.Exitcode.IsSuccess() {
+			return
+		}
+		events = append(events, inv.Events...)
+		for _, sub := range inv.SubInvocations {
+			collect(sub)
+		}
+	}
+	for _, inv := range vm.invocations {
+		collect(inv)
+	}
+	return events
+}
+
+// Returns the value transferred by all successful invocations, in order of invocation.
+// Transfers made by invocations that aborted, or that were made by invocations that aborted, are excluded, as
+// they were rolled back.
+func (vm *VM) Transfers() []Transfer {
+	var transfers []Transfer
+	var collect func(inv *Invocation)
+	collect = func(inv *Invocation) {
+		if !inv.Exitcode.IsSuccess() {
+			return
+		}
+		transfers = append(transfers, inv.Transfers...)
+		for _, sub := range inv.SubInvocations {
+			collect(sub)
+		}
+	}
+	for _, inv := range vm.invocations {
+		collect(inv)
+	}
+	return transfers
+}
+
+func (vm *VM) emitEvent(emitter address.Address, entries []runtime.EventEntry) {
+	current := vm.invocationStack[len(vm.invocationStack)-1]
+	current.Events = append(current.Events, Event{Emitter: emitter, Entries: entries})
+}
+
 //
 // implement runtime.Runtime for VM
 //
@@ -480,10 +695,11 @@ func (vm *VM) Log(level rt.LogLevel, msg string, args ...interface{}) {
 type abort struct {
 	code exitcode.ExitCode
 	msg  string
+	data cbor.Marshaler // Return value describing the failure, if any.
 }
 
 func (vm *VM) Abortf(errExitCode exitcode.ExitCode, msg string, args ...interface{}) {
-	panic(abort{errExitCode, fmt.Sprintf(msg, args...)})
+	panic(abort{code: errExitCode, msg: fmt.Sprintf(msg, args...)})
 }
 
 //
@@ -506,3 +722,8 @@ func (msg InternalMessage) Caller() address.Address {
 func (msg InternalMessage) Receiver() address.Address {
 	return msg.to
 }
+
+// Returns the number of the method the message invokes.
+func (msg InternalMessage) Method() abi.MethodNum {
+	return msg.method
+}